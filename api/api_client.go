@@ -2,6 +2,9 @@ package api
 
 import (
 	"fmt"
+	"net"
+	"net/http"
+	"strconv"
 
 	"github.com/luxdefi/node/api/admin"
 	"github.com/luxdefi/node/api/health"
@@ -9,6 +12,7 @@ import (
 	"github.com/luxdefi/node/api/ipcs"
 	"github.com/luxdefi/node/api/keystore"
 	"github.com/luxdefi/node/indexer"
+	"github.com/luxdefi/node/utils/rpc"
 	"github.com/luxdefi/node/vms/avm"
 	"github.com/luxdefi/node/vms/platformvm"
 	"github.com/luxdefi/coreth/plugin/evm"
@@ -22,6 +26,9 @@ var (
 
 // APIClient gives access to most node apis (or suitable wrappers)
 type APIClient struct {
+	uri          string
+	httpClient   *http.Client
+	callOptions  []rpc.Option
 	platform     platformvm.Client
 	xChain       avm.Client
 	xChainWallet avm.WalletClient
@@ -32,8 +39,9 @@ type APIClient struct {
 	ipcs         ipcs.Client
 	keystore     keystore.Client
 	admin        admin.Client
-	pindex       indexer.Client
-	cindex       indexer.Client
+	pindex       IndexClient
+	cindex       IndexClient
+	metrics      MetricsClient
 }
 
 // Returns a new API client for a node at [ipAddr]:[port].
@@ -41,20 +49,51 @@ type NewAPIClientF func(ipAddr string, port uint16) Client
 
 // NewAPIClient initialize most of node apis
 func NewAPIClient(ipAddr string, port uint16) Client {
-	uri := fmt.Sprintf("http://%s:%d", ipAddr, port)
+	return newAPIClient(ipAddr, port, &apiClientConfig{})
+}
+
+// NewAPIClientWithOptions initialize most of node apis, configured by [opts].
+// The base URI applies to all of them, and the headers to the calls of the
+// metrics, warp and index clients. The other node API clients always use the
+// default HTTP client, so the headers are added by passing CallOptions to
+// their calls, and the HTTP client, transport and timeout options are
+// rejected. The C-Chain EthClient connects over websocket, without headers.
+func NewAPIClientWithOptions(ipAddr string, port uint16, opts ...APIClientOption) (Client, error) {
+	cfg := newAPIClientConfig(opts)
+	if cfg.httpClient != nil || cfg.timeout != 0 {
+		return nil, fmt.Errorf("%w: the node API clients only use the default HTTP client, without timeout", ErrUnsupportedAPIClientOption)
+	}
+	return newAPIClient(ipAddr, port, cfg), nil
+}
+
+func newAPIClient(ipAddr string, port uint16, cfg *apiClientConfig) Client {
+	uri := cfg.uri
+	if uri == "" {
+		uri = "http://" + net.JoinHostPort(ipAddr, strconv.Itoa(int(port)))
+	}
+	httpClient := cfg.getHTTPClient()
+	callOptions := cfg.getCallOptions()
+	ethOpts := []EthClientOption{}
+	if cfg.uri != "" {
+		ethOpts = append(ethOpts, WithEthBaseURI(cfg.uri))
+	}
 	return &APIClient{
+		uri:          uri,
+		httpClient:   httpClient,
+		callOptions:  callOptions,
 		platform:     platformvm.NewClient(uri),
 		xChain:       avm.NewClient(uri, "X"),
 		xChainWallet: avm.NewWalletClient(uri, "X"),
 		cChain:       evm.NewCChainClient(uri),
-		cChainEth:    NewEthClient(ipAddr, uint(port)), // wrapper over ethclient.Client
+		cChainEth:    NewEthClient(ipAddr, uint(port), ethOpts...), // wrapper over ethclient.Client
 		info:         info.NewClient(uri),
 		health:       health.NewClient(uri),
 		ipcs:         ipcs.NewClient(uri),
 		keystore:     keystore.NewClient(uri),
 		admin:        admin.NewClient(uri),
-		pindex:       indexer.NewClient(uri + "/ext/index/P/block"),
-		cindex:       indexer.NewClient(uri + "/ext/index/C/block"),
+		pindex:       newIndexClient(uri+"/ext/index/P/block", callOptions),
+		cindex:       newIndexClient(uri+"/ext/index/C/block", callOptions),
+		metrics:      newMetricsClient(uri, httpClient),
 	}
 }
 
@@ -105,3 +144,26 @@ func (c APIClient) PChainIndexAPI() indexer.Client {
 func (c APIClient) CChainIndexAPI() indexer.Client {
 	return c.cindex
 }
+
+func (c APIClient) PChainIndex() IndexClient {
+	return c.pindex
+}
+
+func (c APIClient) CChainIndex() IndexClient {
+	return c.cindex
+}
+
+func (c APIClient) MetricsAPI() MetricsClient {
+	return c.metrics
+}
+
+// WarpAPI returns a client of the warp API of the EVM chain [chain] (ID or alias)
+func (c APIClient) WarpAPI(chain string) WarpClient {
+	return newWarpClient(c.uri, chain, c.httpClient)
+}
+
+// CallOptions returns the options to pass to the calls of the node API
+// clients, adding the headers given to NewAPIClientWithOptions
+func (c APIClient) CallOptions() []rpc.Option {
+	return c.callOptions
+}
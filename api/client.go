@@ -7,6 +7,7 @@ import (
 	"github.com/luxdefi/node/api/ipcs"
 	"github.com/luxdefi/node/api/keystore"
 	"github.com/luxdefi/node/indexer"
+	"github.com/luxdefi/node/utils/rpc"
 	"github.com/luxdefi/node/vms/avm"
 	"github.com/luxdefi/node/vms/platformvm"
 	"github.com/luxdefi/coreth/plugin/evm"
@@ -27,5 +28,12 @@ type Client interface {
 	AdminAPI() admin.Client
 	PChainIndexAPI() indexer.Client
 	CChainIndexAPI() indexer.Client
+	// block indexer clients with height lookups and range iteration
+	PChainIndex() IndexClient
+	CChainIndex() IndexClient
+	MetricsAPI() MetricsClient
+	WarpAPI(chain string) WarpClient
+	// options to pass to the calls of the node API clients, e.g. to add headers
+	CallOptions() []rpc.Option
 	// TODO add methods
 }
@@ -2,15 +2,25 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"net"
+	"strconv"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"github.com/luxdefi/node/ids"
 	"github.com/luxdefi/coreth/core/types"
 	"github.com/luxdefi/coreth/ethclient"
 	"github.com/luxdefi/coreth/interfaces"
+	"github.com/luxdefi/coreth/rpc"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/gorilla/websocket"
 )
 
 // Interface compliance
@@ -37,225 +47,451 @@ type EthClient interface {
 	SuggestGasTipCap(context.Context) (*big.Int, error)
 	FilterLogs(context.Context, interfaces.FilterQuery) ([]types.Log, error)
 	SubscribeFilterLogs(context.Context, interfaces.FilterQuery, chan<- types.Log) (interfaces.Subscription, error)
+	SubscribeNewHead(context.Context, chan<- *types.Header) (interfaces.Subscription, error)
+	BatchCallContext(context.Context, []rpc.BatchElem) error
 }
 
+// max delay between attempts to resubscribe after the websocket connection is lost
+const resubscribeMaxBackoff = 10 * time.Second
+
 // ethClient websocket ethclient.Client with mutexed api calls and lazy conn (on first call)
 // All calls are wrapped in a mutex, and try to create a connection if it doesn't exist yet
 type ethClient struct {
-	ipAddr  string
-	chainID string
-	port    uint
-	client  ethclient.Client
-	lock    sync.Mutex
+	ipAddr    string
+	chainID   string
+	port      uint
+	client    ethclient.Client
+	rpcClient *rpc.Client
+	lock      sync.Mutex
+	// base URI of the node APIs, replacing ipAddr:port if set
+	baseURI string
+
+	// retries of the connection and calls, set by [WithRetries]
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+
+	// subscriptions not yet unsubscribed, ended on Close
+	subs map[*ethSubscription]struct{}
 }
 
 // NewEthClient mainly takes ip/port info for usage in future calls
 // Connection can't be initialized in constructor because node is not ready when the constructor is called
 // It follows convention of most node api constructors that can be called without having a ready node
-func NewEthClient(ipAddr string, port uint) EthClient {
+func NewEthClient(ipAddr string, port uint, opts ...EthClientOption) EthClient {
 	// default to using the C chain
-	return NewEthClientWithChainID(ipAddr, port, "C")
+	return NewEthClientWithChainID(ipAddr, port, "C", opts...)
 }
 
 // NewEthClientWithChainID creates an EthClient initialized to connect to
 // ipAddr/port and communicate with the given chainID.
-func NewEthClientWithChainID(ipAddr string, port uint, chainID string) EthClient {
-	return &ethClient{
-		ipAddr:  ipAddr,
-		port:    port,
-		chainID: chainID,
+func NewEthClientWithChainID(ipAddr string, port uint, chainID string, opts ...EthClientOption) EthClient {
+	c := &ethClient{
+		ipAddr:      ipAddr,
+		port:        port,
+		chainID:     chainID,
+		maxAttempts: 1,
+		subs:        map[*ethSubscription]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// EthClientOption configures an EthClient.
+type EthClientOption func(*ethClient)
+
+// WithEthBaseURI makes the EthClient connect to the node APIs at [uri]
+// (http or https, converted to ws or wss) instead of ipAddr:port, e.g.
+// when the node sits behind a proxy
+func WithEthBaseURI(uri string) EthClientOption {
+	return func(c *ethClient) {
+		c.baseURI = uri
+	}
+}
+
+// WithRetries makes the EthClient retry connecting and the calls failing
+// because of the connection up to [maxAttempts] times, waiting [backoff]
+// before the first retry, doubled for each next one up to [maxBackoff].
+// Transactions are sent only once, as they may have reached the node.
+func WithRetries(maxAttempts int, backoff time.Duration, maxBackoff time.Duration) EthClientOption {
+	return func(c *ethClient) {
+		c.maxAttempts = maxAttempts
+		c.backoff = backoff
+		c.maxBackoff = maxBackoff
 	}
 }
 
 // connect attempts to connect with websocket ethclient API
-func (c *ethClient) connect() error {
+func (c *ethClient) connect(ctx context.Context) error {
 	if c.client == ethclient.Client(nil) {
-		client, err := ethclient.Dial(fmt.Sprintf("ws://%s:%d/ext/bc/%s/ws", c.ipAddr, c.port, c.chainID))
+		rpcClient, err := c.dial(ctx)
 		if err != nil {
 			return err
 		}
-		c.client = client
+		c.rpcClient = rpcClient
+		c.client = ethclient.NewClient(rpcClient)
 	}
 	return nil
 }
 
-// Close closes opened connection (if any)
-func (c *ethClient) Close() {
-	if c.client == ethclient.Client(nil) {
-		return
+// dial opens a new websocket connection to the chain
+func (c *ethClient) dial(ctx context.Context) (*rpc.Client, error) {
+	return rpc.DialContext(ctx, c.getWebsocketURI())
+}
+
+// getWebsocketURI returns the URI of the websocket API of the chain
+func (c *ethClient) getWebsocketURI() string {
+	if c.baseURI == "" {
+		return fmt.Sprintf("ws://%s/ext/bc/%s/ws", net.JoinHostPort(c.ipAddr, strconv.Itoa(int(c.port))), c.chainID)
 	}
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	c.client.Close()
+	baseURI := c.baseURI
+	switch {
+	case strings.HasPrefix(baseURI, "https://"):
+		baseURI = "wss://" + strings.TrimPrefix(baseURI, "https://")
+	case strings.HasPrefix(baseURI, "http://"):
+		baseURI = "ws://" + strings.TrimPrefix(baseURI, "http://")
+	}
+	return fmt.Sprintf("%s/ext/bc/%s/ws", baseURI, c.chainID)
 }
 
-func (c *ethClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return err
+// callWithRetry calls [fn] with a connected client, connecting again and
+// retrying as configured by [WithRetries] while it fails because of the
+// connection, if [retry] is set.
+// [c.lock] is only held during each attempt, so the other calls aren't
+// blocked while waiting to retry.
+func callWithRetry[T any](ctx context.Context, c *ethClient, retry bool, fn func(ethclient.Client) (T, error)) (T, error) {
+	var zero T
+	for attempt := 1; ; attempt++ {
+		res, connErr, err := callOnce(ctx, c, fn)
+		if err == nil || !connErr {
+			return res, err
+		}
+		if !retry || !c.waitRetry(ctx, attempt) {
+			return zero, err
+		}
 	}
-	return c.client.SendTransaction(ctx, tx)
 }
 
-func (c *ethClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+// callOnce calls [fn] with a connected client, dropping the connection if
+// the call fails because of it. [connErr] is true if [err] is caused by the
+// connection, rather than by the call itself.
+func callOnce[T any](ctx context.Context, c *ethClient, fn func(ethclient.Client) (T, error)) (res T, connErr bool, err error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
+
+	if err := c.connect(ctx); err != nil {
+		return res, ctx.Err() == nil, err
+	}
+	res, err = fn(c.client)
+	// the connection is kept if the call gave up because of [ctx]
+	if err != nil && ctx.Err() == nil && isConnectionError(err) {
+		c.reconnect(err)
+		return res, true, err
 	}
-	return c.client.TransactionReceipt(ctx, txHash)
+	return res, false, err
 }
 
-func (c *ethClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
+// waitRetry waits before retrying a call that failed on its [attempt], as
+// configured by [WithRetries]: [c.backoff] before the first retry, doubled
+// for each next one up to [c.maxBackoff]. Returns false if the call
+// shouldn't be retried, as it ran out of attempts or [ctx] is done.
+func (c *ethClient) waitRetry(ctx context.Context, attempt int) bool {
+	if attempt >= c.maxAttempts {
+		return false
+	}
+	backoff := c.backoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if c.maxBackoff > 0 && backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+			break
+		}
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
 	}
-	return c.client.BalanceAt(ctx, account, blockNumber)
 }
 
-func (c *ethClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
+// isConnectionError returns true if [err] is caused by the connection
+// to the node, rather than by the call itself. A canceled or timed out
+// call isn't, so it is neither retried nor drops the connection.
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
 	}
-	return c.client.BlockByNumber(ctx, number)
+	var (
+		netErr   net.Error
+		closeErr *websocket.CloseError
+	)
+	return errors.As(err, &netErr) ||
+		errors.As(err, &closeErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, rpc.ErrClientQuit)
 }
 
-func (c *ethClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+// Close closes opened connection (if any), and ends the subscriptions
+func (c *ethClient) Close() {
 	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
+	subs := make([]*ethSubscription, 0, len(c.subs))
+	for sub := range c.subs {
+		subs = append(subs, sub)
 	}
-	return c.client.BlockByHash(ctx, hash)
+	if c.client != ethclient.Client(nil) {
+		c.client.Close()
+	}
+	c.lock.Unlock()
+
+	for _, sub := range subs {
+		sub.Unsubscribe()
+	}
+}
+
+func (c *ethClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := callWithRetry(ctx, c, false, func(client ethclient.Client) (struct{}, error) {
+		return struct{}{}, client.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+// BatchCallContext sends all the JSON-RPC requests of [batch] at once.
+// The error of each request is set in its Error field.
+func (c *ethClient) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	_, err := callWithRetry(ctx, c, true, func(ethclient.Client) (struct{}, error) {
+		return struct{}{}, c.rpcClient.BatchCallContext(ctx, batch)
+	})
+	return err
+}
+
+func (c *ethClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, txHash)
+	})
+}
+
+func (c *ethClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (*big.Int, error) {
+		return client.BalanceAt(ctx, account, blockNumber)
+	})
+}
+
+func (c *ethClient) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (*types.Block, error) {
+		return client.BlockByNumber(ctx, number)
+	})
+}
+
+func (c *ethClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (*types.Block, error) {
+		return client.BlockByHash(ctx, hash)
+	})
 }
 
 func (c *ethClient) BlockNumber(ctx context.Context) (uint64, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return 0, err
-	}
-	return c.client.BlockNumber(ctx)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
 }
 
 func (c *ethClient) CallContract(ctx context.Context, msg interfaces.CallMsg, blockNumber *big.Int) ([]byte, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
-	}
-	return c.client.CallContract(ctx, msg, blockNumber)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) ([]byte, error) {
+		return client.CallContract(ctx, msg, blockNumber)
+	})
 }
 
 func (c *ethClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return 0, err
-	}
-	return c.client.NonceAt(ctx, account, blockNumber)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (uint64, error) {
+		return client.NonceAt(ctx, account, blockNumber)
+	})
 }
 
 func (c *ethClient) AssetBalanceAt(ctx context.Context, account common.Address, assetID ids.ID, blockNumber *big.Int) (*big.Int, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
-	}
-	return c.client.AssetBalanceAt(ctx, account, assetID, blockNumber)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (*big.Int, error) {
+		return client.AssetBalanceAt(ctx, account, assetID, blockNumber)
+	})
 }
 
 func (c *ethClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
-	}
-	return c.client.SuggestGasPrice(ctx)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasPrice(ctx)
+	})
 }
 
 func (c *ethClient) AcceptedCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
-	}
-	return c.client.AcceptedCodeAt(ctx, account)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) ([]byte, error) {
+		return client.AcceptedCodeAt(ctx, account)
+	})
 }
 
 func (c *ethClient) AcceptedNonceAt(ctx context.Context, account common.Address) (uint64, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return 0, err
-	}
-	return c.client.AcceptedNonceAt(ctx, account)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (uint64, error) {
+		return client.AcceptedNonceAt(ctx, account)
+	})
 }
 
 func (c *ethClient) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
-	}
-	return c.client.CodeAt(ctx, account, blockNumber)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) ([]byte, error) {
+		return client.CodeAt(ctx, account, blockNumber)
+	})
 }
 
 func (c *ethClient) EstimateGas(ctx context.Context, msg interfaces.CallMsg) (uint64, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return 0, err
-	}
-	return c.client.EstimateGas(ctx, msg)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (uint64, error) {
+		return client.EstimateGas(ctx, msg)
+	})
 }
 
 func (c *ethClient) AcceptedCallContract(ctx context.Context, call interfaces.CallMsg) ([]byte, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
-	}
-	return c.client.AcceptedCallContract(ctx, call)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) ([]byte, error) {
+		return client.AcceptedCallContract(ctx, call)
+	})
 }
 
 func (c *ethClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
-	}
-	return c.client.HeaderByNumber(ctx, number)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (*types.Header, error) {
+		return client.HeaderByNumber(ctx, number)
+	})
 }
 
 func (c *ethClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
-	}
-	return c.client.SuggestGasTipCap(ctx)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasTipCap(ctx)
+	})
 }
 
 func (c *ethClient) FilterLogs(ctx context.Context, query interfaces.FilterQuery) ([]types.Log, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
-	}
-	return c.client.FilterLogs(ctx, query)
+	return callWithRetry(ctx, c, true, func(client ethclient.Client) ([]types.Log, error) {
+		return client.FilterLogs(ctx, query)
+	})
 }
 
+// SubscribeFilterLogs sends the logs matching [query] to [ch]. If the
+// websocket connection is lost, it reconnects and resubscribes, so the
+// subscription only ends when unsubscribed. Logs emitted while
+// disconnected are not sent.
 func (c *ethClient) SubscribeFilterLogs(ctx context.Context, query interfaces.FilterQuery, ch chan<- types.Log) (interfaces.Subscription, error) {
+	return c.subscribe(ctx, func(ctx context.Context, client ethclient.Client) (interfaces.Subscription, error) {
+		return client.SubscribeFilterLogs(ctx, query, ch)
+	})
+}
+
+// SubscribeNewHead sends the headers of the new accepted blocks to [ch].
+// If the websocket connection is lost, it reconnects and resubscribes, so
+// the subscription only ends when unsubscribed. Headers of the blocks
+// accepted while disconnected are not sent.
+func (c *ethClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (interfaces.Subscription, error) {
+	return c.subscribe(ctx, func(ctx context.Context, client ethclient.Client) (interfaces.Subscription, error) {
+		return client.SubscribeNewHead(ctx, ch)
+	})
+}
+
+// ethSubscription is a subscription on a connection of its own, closed
+// once unsubscribed
+type ethSubscription struct {
+	event.Subscription
+	c         *ethClient
+	closeOnce sync.Once
+	close     func()
+}
+
+func (s *ethSubscription) Unsubscribe() {
+	s.Subscription.Unsubscribe()
+	s.closeOnce.Do(func() {
+		s.close()
+		s.c.lock.Lock()
+		delete(s.c.subs, s)
+		s.c.lock.Unlock()
+	})
+}
+
+// subscribe calls [subscribeFn] with a client on a new connection, and
+// again on another one each time the subscription fails. The connection
+// isn't shared with the calls nor the other subscriptions, so dropping it
+// doesn't end them. Only the first subscription error is returned.
+func (c *ethClient) subscribe(
+	ctx context.Context,
+	subscribeFn func(context.Context, ethclient.Client) (interfaces.Subscription, error),
+) (interfaces.Subscription, error) {
+	var (
+		rpcClient *rpc.Client
+		sub       interfaces.Subscription
+		err       error
+	)
+	for attempt := 1; ; attempt++ {
+		rpcClient, sub, err = c.dialAndSubscribe(ctx, subscribeFn)
+		if err == nil {
+			break
+		}
+		if !isConnectionError(err) || !c.waitRetry(ctx, attempt) {
+			return nil, err
+		}
+	}
+	// only used by the resubscribe loop, and once it has returned
+	closeConn := func() {
+		if rpcClient != nil {
+			rpcClient.Close()
+			rpcClient = nil
+		}
+	}
+	first := true
+	resub := event.ResubscribeErr(resubscribeMaxBackoff, func(ctx context.Context, _ error) (event.Subscription, error) {
+		if first {
+			first = false
+			return sub, nil
+		}
+		closeConn()
+		var err error
+		rpcClient, sub, err = c.dialAndSubscribe(ctx, subscribeFn)
+		if err != nil {
+			return nil, err
+		}
+		return sub, nil
+	})
+	ethSub := &ethSubscription{
+		Subscription: resub,
+		c:            c,
+		close:        closeConn,
+	}
 	c.lock.Lock()
-	defer c.lock.Unlock()
-	if err := c.connect(); err != nil {
-		return nil, err
+	c.subs[ethSub] = struct{}{}
+	c.lock.Unlock()
+	return ethSub, nil
+}
+
+// dialAndSubscribe returns a new connection, subscribed with [subscribeFn]
+func (c *ethClient) dialAndSubscribe(
+	ctx context.Context,
+	subscribeFn func(context.Context, ethclient.Client) (interfaces.Subscription, error),
+) (*rpc.Client, interfaces.Subscription, error) {
+	rpcClient, err := c.dial(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	sub, err := subscribeFn(ctx, ethclient.NewClient(rpcClient))
+	if err != nil {
+		rpcClient.Close()
+		return nil, nil, err
 	}
-	return c.client.SubscribeFilterLogs(ctx, query, ch)
+	return rpcClient, sub, nil
+}
+
+// reconnect drops the connection lost with [err], so the next call
+// connects again.
+// Assumes [c.lock] is held.
+func (c *ethClient) reconnect(err error) {
+	if err == nil || c.client == ethclient.Client(nil) {
+		return
+	}
+	c.client.Close()
+	c.client = nil
+	c.rpcClient = nil
 }
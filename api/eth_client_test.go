@@ -0,0 +1,417 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/websocket"
+	"github.com/luxdefi/coreth/core/types"
+	"github.com/luxdefi/coreth/ethclient"
+	"github.com/luxdefi/coreth/interfaces"
+	"github.com/luxdefi/coreth/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// eth API served by [testEthServer]
+type testEthService struct {
+	calls atomic.Int32
+	// makes the next eth_gasPrice call block until the connection is closed
+	hold atomic.Bool
+	// number of newHeads subscriptions made
+	subscriptions atomic.Int32
+}
+
+// served as eth_blockNumber
+func (s *testEthService) BlockNumber() hexutil.Uint64 {
+	s.calls.Add(1)
+	return 10
+}
+
+// served as eth_gasPrice
+func (s *testEthService) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	s.calls.Add(1)
+	if s.hold.CompareAndSwap(true, false) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return (*hexutil.Big)(big.NewInt(1)), nil
+}
+
+// served as the newHeads subscription of eth_subscribe, notifying a new
+// header every few milliseconds
+func (s *testEthService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	s.subscriptions.Add(1)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for number := int64(1); ; number++ {
+			select {
+			case <-sub.Err():
+				return
+			case <-ticker.C:
+				_ = notifier.Notify(sub.ID, &types.Header{
+					Number:     big.NewInt(number),
+					Difficulty: big.NewInt(0),
+				})
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// websocket server of an eth API whose connections can be dropped
+type testEthServer struct {
+	*httptest.Server
+	service *testEthService
+
+	lock     sync.Mutex
+	conns    []net.Conn
+	accepted int
+}
+
+// records the connections accepted by a [testEthServer]
+type trackingListener struct {
+	net.Listener
+	s *testEthServer
+}
+
+func (l *trackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.s.lock.Lock()
+		l.s.conns = append(l.s.conns, conn)
+		l.s.accepted++
+		l.s.lock.Unlock()
+	}
+	return conn, err
+}
+
+func newTestEthServer(t *testing.T) *testEthServer {
+	s := &testEthServer{service: &testEthService{}}
+	rpcServer := rpc.NewServer(0)
+	require.NoError(t, rpcServer.RegisterName("eth", s.service))
+	s.Server = httptest.NewUnstartedServer(rpcServer.WebsocketHandler([]string{"*"}))
+	s.Listener = &trackingListener{Listener: s.Listener, s: s}
+	s.Start()
+	t.Cleanup(func() {
+		s.dropConnections()
+		s.Close()
+		rpcServer.Stop()
+	})
+	return s
+}
+
+// closes the connections accepted so far, as a node restarting would
+func (s *testEthServer) dropConnections() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.conns = nil
+}
+
+func (s *testEthServer) numAccepted() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.accepted
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "EOF", err: io.EOF, expected: true},
+		{name: "unexpected EOF", err: fmt.Errorf("read: %w", io.ErrUnexpectedEOF), expected: true},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, expected: true},
+		{name: "connection reset", err: syscall.ECONNRESET, expected: true},
+		{name: "client quit", err: rpc.ErrClientQuit, expected: true},
+		{name: "websocket closed", err: &websocket.CloseError{Code: websocket.CloseAbnormalClosure, Text: io.ErrUnexpectedEOF.Error()}, expected: true},
+		{name: "call error", err: errors.New("execution reverted")},
+		{name: "canceled", err: context.Canceled},
+		{name: "deadline exceeded", err: context.DeadlineExceeded},
+		{name: "canceled dial", err: &net.OpError{Op: "dial", Err: context.Canceled}},
+		{name: "timed out read", err: &net.OpError{Op: "read", Err: fmt.Errorf("read: %w", context.DeadlineExceeded)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, isConnectionError(tt.err))
+		})
+	}
+}
+
+func TestWaitRetry(t *testing.T) {
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		attempt     int
+		maxAttempts int
+		expected    bool
+	}{
+		{name: "first attempt", ctx: context.Background(), attempt: 1, maxAttempts: 3, expected: true},
+		{name: "backoff capped", ctx: context.Background(), attempt: 10, maxAttempts: 20, expected: true},
+		{name: "last attempt", ctx: context.Background(), attempt: 3, maxAttempts: 3},
+		{name: "no retries", ctx: context.Background(), attempt: 1, maxAttempts: 1},
+		{name: "canceled", ctx: canceledCtx, attempt: 1, maxAttempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewEthClient("127.0.0.1", 1, WithRetries(tt.maxAttempts, time.Millisecond, 10*time.Millisecond)).(*ethClient)
+			start := time.Now()
+			require.Equal(t, tt.expected, c.waitRetry(tt.ctx, tt.attempt))
+			// the backoff doubles up to the max one
+			require.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
+
+func TestCallWithRetry(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	srv := newTestEthServer(t)
+	c := NewEthClient("127.0.0.1", 1, WithEthBaseURI(srv.URL), WithRetries(3, 10*time.Millisecond, 50*time.Millisecond)).(*ethClient)
+	defer c.Close()
+
+	blockNumber, err := c.BlockNumber(ctx)
+	require.NoError(err)
+	require.Equal(uint64(10), blockNumber)
+	require.Equal(1, srv.numAccepted())
+
+	// drops the connection while a held eth_gasPrice call is in flight
+	// with [retry], returning the call error and the number of calls done
+	callDroppingConnection := func(retry bool) (int32, error) {
+		calls := srv.service.calls.Load()
+		srv.service.hold.Store(true)
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := callWithRetry(ctx, c, retry, func(client ethclient.Client) (*big.Int, error) {
+				return client.SuggestGasPrice(ctx)
+			})
+			errCh <- err
+		}()
+		require.Eventually(func() bool {
+			return srv.service.calls.Load() == calls+1
+		}, 5*time.Second, 10*time.Millisecond)
+		srv.dropConnections()
+		err := <-errCh
+		return srv.service.calls.Load() - calls, err
+	}
+
+	// the call failing on the dropped connection connects again and is retried
+	calls, err := callDroppingConnection(true)
+	require.NoError(err)
+	require.Equal(int32(2), calls)
+	require.Equal(2, srv.numAccepted())
+
+	// without retry, the call fails, and the next one connects again
+	calls, err = callDroppingConnection(false)
+	require.True(isConnectionError(err))
+	require.Equal(int32(1), calls)
+	_, err = c.BlockNumber(ctx)
+	require.NoError(err)
+	require.Equal(3, srv.numAccepted())
+
+	// a call timing out is neither retried nor drops the connection
+	calls0 := srv.service.calls.Load()
+	srv.service.hold.Store(true)
+	cctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	_, err = c.SuggestGasPrice(cctx)
+	cancel()
+	require.ErrorIs(err, context.DeadlineExceeded)
+	require.Equal(calls0+1, srv.service.calls.Load())
+	_, err = c.BlockNumber(ctx)
+	require.NoError(err)
+	require.Equal(3, srv.numAccepted())
+}
+
+func TestCallWithRetryAttempts(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	// closes the connections at once, so the websocket handshakes fail
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	defer listener.Close()
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			_ = conn.Close()
+		}
+	}()
+	uri := "http://" + listener.Addr().String()
+
+	for _, maxAttempts := range []int{1, 3} {
+		accepted.Store(0)
+		c := NewEthClient("127.0.0.1", 1, WithEthBaseURI(uri), WithRetries(maxAttempts, time.Millisecond, time.Millisecond))
+		_, err := c.BlockNumber(ctx)
+		require.Error(err)
+		require.Equal(int32(maxAttempts), accepted.Load())
+	}
+
+	// a canceled call isn't retried
+	accepted.Store(0)
+	c := NewEthClient("127.0.0.1", 1, WithEthBaseURI(uri), WithRetries(100, time.Hour, time.Hour))
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = c.BlockNumber(cctx)
+	require.Error(err)
+	require.LessOrEqual(accepted.Load(), int32(1))
+}
+
+func TestSubscribeReconnects(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	srv := newTestEthServer(t)
+	c := NewEthClient("127.0.0.1", 1, WithEthBaseURI(srv.URL), WithRetries(3, 10*time.Millisecond, 50*time.Millisecond)).(*ethClient)
+	defer c.Close()
+
+	_, err := c.BlockNumber(ctx)
+	require.NoError(err)
+	heads := make(chan *types.Header, 100)
+	sub, err := c.SubscribeNewHead(ctx, heads)
+	require.NoError(err)
+	// the subscription has a connection of its own
+	require.Equal(2, srv.numAccepted())
+	require.Equal(int32(1), srv.service.subscriptions.Load())
+
+	// waits for a header, failing if the subscription ends meanwhile
+	waitHead := func() {
+		select {
+		case <-heads:
+		case err := <-sub.Err():
+			require.FailNow("subscription ended", "error: %v", err)
+		case <-time.After(10 * time.Second):
+			require.FailNow("no header received")
+		}
+	}
+	waitHead()
+
+	// resubscribes on a new connection once dropped, without ending the
+	// subscription nor reporting the connection error
+	srv.dropConnections()
+	require.Eventually(func() bool {
+		return srv.service.subscriptions.Load() == 2
+	}, 10*time.Second, 10*time.Millisecond)
+	for len(heads) > 0 {
+		<-heads
+	}
+	waitHead()
+	require.Equal(int32(2), srv.service.subscriptions.Load())
+
+	// the calls connect again on their own
+	_, err = c.BlockNumber(ctx)
+	require.NoError(err)
+
+	// the error channel is closed once unsubscribed, with no error
+	sub.Unsubscribe()
+	err, ok := <-sub.Err()
+	require.False(ok)
+	require.NoError(err)
+	sub.Unsubscribe()
+	c.lock.Lock()
+	require.Empty(c.subs)
+	c.lock.Unlock()
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	srv := newTestEthServer(t)
+	c := NewEthClient("127.0.0.1", 1, WithEthBaseURI(srv.URL), WithRetries(3, 10*time.Millisecond, 50*time.Millisecond))
+
+	// the subscription error is returned, without retrying
+	_, err := c.SubscribeFilterLogs(ctx, interfaces.FilterQuery{}, make(chan types.Log))
+	require.Error(err)
+	require.False(isConnectionError(err))
+	require.Equal(1, srv.numAccepted())
+
+	// closing the client ends the subscriptions
+	sub, err := c.SubscribeNewHead(ctx, make(chan *types.Header, 100))
+	require.NoError(err)
+	c.Close()
+	select {
+	case err, ok := <-sub.Err():
+		require.False(ok)
+		require.NoError(err)
+	case <-time.After(10 * time.Second):
+		require.FailNow("subscription not ended")
+	}
+}
+
+func TestBatchCallContext(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	srv := newTestEthServer(t)
+	c := NewEthClient("127.0.0.1", 1, WithEthBaseURI(srv.URL), WithRetries(3, 10*time.Millisecond, 50*time.Millisecond))
+	defer c.Close()
+
+	newBatch := func() []rpc.BatchElem {
+		return []rpc.BatchElem{
+			{Method: "eth_blockNumber", Result: new(hexutil.Uint64)},
+			{Method: "eth_gasPrice", Result: new(hexutil.Big)},
+			{Method: "eth_unknown", Result: new(hexutil.Uint64)},
+		}
+	}
+	checkBatch := func(batch []rpc.BatchElem) {
+		require.NoError(batch[0].Error)
+		require.Equal(hexutil.Uint64(10), *batch[0].Result.(*hexutil.Uint64))
+		require.NoError(batch[1].Error)
+		require.Equal(big.NewInt(1), batch[1].Result.(*hexutil.Big).ToInt())
+		// the error of a request is set on it
+		require.Error(batch[2].Error)
+	}
+
+	batch := newBatch()
+	require.NoError(c.BatchCallContext(ctx, batch))
+	checkBatch(batch)
+	require.Equal(1, srv.numAccepted())
+
+	// the batch in flight when the connection drops is sent again
+	calls := srv.service.calls.Load()
+	srv.service.hold.Store(true)
+	batch = newBatch()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.BatchCallContext(ctx, batch)
+	}()
+	require.Eventually(func() bool {
+		return srv.service.calls.Load() == calls+2
+	}, 5*time.Second, 10*time.Millisecond)
+	srv.dropConnections()
+	require.NoError(<-errCh)
+	checkBatch(batch)
+	require.Equal(calls+4, srv.service.calls.Load())
+	require.Equal(2, srv.numAccepted())
+}
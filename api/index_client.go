@@ -0,0 +1,129 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/indexer"
+	"github.com/luxdefi/node/utils/rpc"
+)
+
+var ErrInvalidIndexRange = errors.New("invalid index range")
+
+// Interface compliance
+var _ IndexClient = &indexClient{}
+
+// IndexClient is a block indexer client that can also fetch the accepted
+// blocks by height, and iterate over ranges of them
+type IndexClient interface {
+	indexer.Client
+	// GetContainerByHeight returns the accepted block at [height]. As the
+	// genesis block is not indexed, the block at height h has index h-1,
+	// given the node indexed the chain since genesis
+	GetContainerByHeight(ctx context.Context, height uint64, options ...rpc.Option) (indexer.Container, error)
+	// IterateContainers calls [f] on the accepted containers with index in
+	// [start, end], in order, fetching them in pages. It stops at the last
+	// accepted container if [end] is beyond it. If [f] returns an error, the
+	// iteration stops and the error is returned
+	IterateContainers(ctx context.Context, start uint64, end uint64, f func(indexer.Container) error, options ...rpc.Option) error
+}
+
+type indexClient struct {
+	indexer.Client
+	// options added to all the calls, e.g. with the headers of the
+	// APIClientOption
+	options []rpc.Option
+}
+
+// NewIndexClient creates an IndexClient for the block index at [uri],
+// e.g. http://127.0.0.1:9650/ext/index/P/block
+func NewIndexClient(uri string) IndexClient {
+	return newIndexClient(uri, nil)
+}
+
+func newIndexClient(uri string, options []rpc.Option) IndexClient {
+	return &indexClient{Client: indexer.NewClient(uri), options: options}
+}
+
+// returns [options] after the ones of the client
+func (c *indexClient) withOptions(options []rpc.Option) []rpc.Option {
+	if len(c.options) == 0 {
+		return options
+	}
+	return append(append([]rpc.Option{}, c.options...), options...)
+}
+
+func (c *indexClient) GetContainerRange(ctx context.Context, startIndex uint64, numToFetch int, options ...rpc.Option) ([]indexer.Container, error) {
+	return c.Client.GetContainerRange(ctx, startIndex, numToFetch, c.withOptions(options)...)
+}
+
+func (c *indexClient) GetContainerByIndex(ctx context.Context, index uint64, options ...rpc.Option) (indexer.Container, error) {
+	return c.Client.GetContainerByIndex(ctx, index, c.withOptions(options)...)
+}
+
+func (c *indexClient) GetLastAccepted(ctx context.Context, options ...rpc.Option) (indexer.Container, uint64, error) {
+	return c.Client.GetLastAccepted(ctx, c.withOptions(options)...)
+}
+
+func (c *indexClient) GetIndex(ctx context.Context, containerID ids.ID, options ...rpc.Option) (uint64, error) {
+	return c.Client.GetIndex(ctx, containerID, c.withOptions(options)...)
+}
+
+func (c *indexClient) IsAccepted(ctx context.Context, containerID ids.ID, options ...rpc.Option) (bool, error) {
+	return c.Client.IsAccepted(ctx, containerID, c.withOptions(options)...)
+}
+
+func (c *indexClient) GetContainerByID(ctx context.Context, containerID ids.ID, options ...rpc.Option) (indexer.Container, uint64, error) {
+	return c.Client.GetContainerByID(ctx, containerID, c.withOptions(options)...)
+}
+
+func (c *indexClient) GetContainerByHeight(ctx context.Context, height uint64, options ...rpc.Option) (indexer.Container, error) {
+	if height == 0 {
+		return indexer.Container{}, fmt.Errorf("%w: the genesis block is not indexed", ErrInvalidIndexRange)
+	}
+	return c.GetContainerByIndex(ctx, height-1, options...)
+}
+
+func (c *indexClient) IterateContainers(
+	ctx context.Context,
+	start uint64,
+	end uint64,
+	f func(indexer.Container) error,
+	options ...rpc.Option,
+) error {
+	if start > end {
+		return fmt.Errorf("%w: start %d is greater than end %d", ErrInvalidIndexRange, start, end)
+	}
+	_, lastAcceptedIndex, err := c.GetLastAccepted(ctx, options...)
+	if err != nil {
+		return err
+	}
+	if start > lastAcceptedIndex {
+		return fmt.Errorf("%w: start %d is greater than the last accepted index %d", ErrInvalidIndexRange, start, lastAcceptedIndex)
+	}
+	if end > lastAcceptedIndex {
+		end = lastAcceptedIndex
+	}
+	for next := start; next <= end; {
+		numToFetch := uint64(indexer.MaxFetchedByRange)
+		if end-next+1 < numToFetch {
+			numToFetch = end - next + 1
+		}
+		containers, err := c.GetContainerRange(ctx, next, int(numToFetch), options...)
+		if err != nil {
+			return err
+		}
+		if len(containers) == 0 {
+			return nil
+		}
+		for _, container := range containers {
+			if err := f(container); err != nil {
+				return err
+			}
+		}
+		next += uint64(len(containers))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+var ErrMetricNotFound = errors.New("metric not found")
+
+// Interface compliance
+var _ MetricsClient = &metricsClient{}
+
+// MetricsClient fetches the Prometheus metrics exposed by a node at /ext/metrics
+type MetricsClient interface {
+	// GetMetrics returns the metric families of the node, by name
+	GetMetrics(ctx context.Context) (map[string]*dto.MetricFamily, error)
+	// GetMetricValue returns the value of the counter, gauge or untyped metric
+	// [name] whose labels include [labels], summing them if several match
+	GetMetricValue(ctx context.Context, name string, labels map[string]string) (float64, error)
+}
+
+type metricsClient struct {
+	uri        string
+	httpClient *http.Client
+}
+
+// NewMetricsClient creates a MetricsClient for the node at [uri],
+// configured by [opts]
+func NewMetricsClient(uri string, opts ...APIClientOption) MetricsClient {
+	cfg := newAPIClientConfig(opts)
+	if cfg.uri != "" {
+		uri = cfg.uri
+	}
+	return newMetricsClient(uri, cfg.getHTTPClient())
+}
+
+func newMetricsClient(uri string, httpClient *http.Client) MetricsClient {
+	return &metricsClient{uri: uri + "/ext/metrics", httpClient: httpClient}
+}
+
+func (c *metricsClient) GetMetrics(ctx context.Context) (map[string]*dto.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get metrics from %s: %s", c.uri, resp.Status)
+	}
+	var parser expfmt.TextParser
+	return parser.TextToMetricFamilies(resp.Body)
+}
+
+func (c *metricsClient) GetMetricValue(ctx context.Context, name string, labels map[string]string) (float64, error) {
+	families, err := c.GetMetrics(ctx)
+	if err != nil {
+		return 0, err
+	}
+	family, ok := families[name]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrMetricNotFound, name)
+	}
+	var (
+		value float64
+		found bool
+	)
+	for _, metric := range family.GetMetric() {
+		if !hasLabels(metric, labels) {
+			continue
+		}
+		switch {
+		case metric.GetCounter() != nil:
+			value += metric.GetCounter().GetValue()
+		case metric.GetGauge() != nil:
+			value += metric.GetGauge().GetValue()
+		case metric.GetUntyped() != nil:
+			value += metric.GetUntyped().GetValue()
+		default:
+			return 0, fmt.Errorf("metric %s is not a counter, gauge or untyped metric", name)
+		}
+		found = true
+	}
+	if !found {
+		return 0, fmt.Errorf("%w: %s with labels %v", ErrMetricNotFound, name, labels)
+	}
+	return value, nil
+}
+
+// hasLabels returns true if [metric] has all [labels]
+func hasLabels(metric *dto.Metric, labels map[string]string) bool {
+	matched := 0
+	for _, label := range metric.GetLabel() {
+		if value, ok := labels[label.GetName()]; ok {
+			if value != label.GetValue() {
+				return false
+			}
+			matched++
+		}
+	}
+	return matched == len(labels)
+}
@@ -14,6 +14,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	rpc "github.com/luxdefi/coreth/rpc"
+
 	types "github.com/luxdefi/coreth/core/types"
 )
 
@@ -135,6 +137,20 @@ func (_m *EthClient) BalanceAt(_a0 context.Context, _a1 common.Address, _a2 *big
 	return r0, r1
 }
 
+// BatchCallContext provides a mock function with given fields: _a0, _a1
+func (_m *EthClient) BatchCallContext(_a0 context.Context, _a1 []rpc.BatchElem) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []rpc.BatchElem) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BlockByHash provides a mock function with given fields: _a0, _a1
 func (_m *EthClient) BlockByHash(_a0 context.Context, _a1 common.Hash) (*types.Block, error) {
 	ret := _m.Called(_a0, _a1)
@@ -378,6 +394,29 @@ func (_m *EthClient) SubscribeFilterLogs(_a0 context.Context, _a1 interfaces.Fil
 	return r0, r1
 }
 
+// SubscribeNewHead provides a mock function with given fields: _a0, _a1
+func (_m *EthClient) SubscribeNewHead(_a0 context.Context, _a1 chan<- *types.Header) (interfaces.Subscription, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 interfaces.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, chan<- *types.Header) interfaces.Subscription); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interfaces.Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, chan<- *types.Header) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SuggestGasPrice provides a mock function with given fields: _a0
 func (_m *EthClient) SuggestGasPrice(_a0 context.Context) (*big.Int, error) {
 	ret := _m.Called(_a0)
@@ -23,6 +23,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	platformvm "github.com/luxdefi/node/vms/platformvm"
+
+	rpc "github.com/luxdefi/node/utils/rpc"
 )
 
 // Client is an autogenerated mock type for the Client type
@@ -46,6 +48,22 @@ func (_m *Client) AdminAPI() admin.Client {
 	return r0
 }
 
+// CallOptions provides a mock function with given fields:
+func (_m *Client) CallOptions() []rpc.Option {
+	ret := _m.Called()
+
+	var r0 []rpc.Option
+	if rf, ok := ret.Get(0).(func() []rpc.Option); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]rpc.Option)
+		}
+	}
+
+	return r0
+}
+
 // CChainAPI provides a mock function with given fields:
 func (_m *Client) CChainAPI() evm.Client {
 	ret := _m.Called()
@@ -78,6 +96,22 @@ func (_m *Client) CChainEthAPI() api.EthClient {
 	return r0
 }
 
+// CChainIndex provides a mock function with given fields:
+func (_m *Client) CChainIndex() api.IndexClient {
+	ret := _m.Called()
+
+	var r0 api.IndexClient
+	if rf, ok := ret.Get(0).(func() api.IndexClient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.IndexClient)
+		}
+	}
+
+	return r0
+}
+
 // CChainIndexAPI provides a mock function with given fields:
 func (_m *Client) CChainIndexAPI() indexer.Client {
 	ret := _m.Called()
@@ -158,6 +192,22 @@ func (_m *Client) KeystoreAPI() keystore.Client {
 	return r0
 }
 
+// MetricsAPI provides a mock function with given fields:
+func (_m *Client) MetricsAPI() api.MetricsClient {
+	ret := _m.Called()
+
+	var r0 api.MetricsClient
+	if rf, ok := ret.Get(0).(func() api.MetricsClient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.MetricsClient)
+		}
+	}
+
+	return r0
+}
+
 // PChainAPI provides a mock function with given fields:
 func (_m *Client) PChainAPI() platformvm.Client {
 	ret := _m.Called()
@@ -174,6 +224,22 @@ func (_m *Client) PChainAPI() platformvm.Client {
 	return r0
 }
 
+// PChainIndex provides a mock function with given fields:
+func (_m *Client) PChainIndex() api.IndexClient {
+	ret := _m.Called()
+
+	var r0 api.IndexClient
+	if rf, ok := ret.Get(0).(func() api.IndexClient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.IndexClient)
+		}
+	}
+
+	return r0
+}
+
 // PChainIndexAPI provides a mock function with given fields:
 func (_m *Client) PChainIndexAPI() indexer.Client {
 	ret := _m.Called()
@@ -190,6 +256,22 @@ func (_m *Client) PChainIndexAPI() indexer.Client {
 	return r0
 }
 
+// WarpAPI provides a mock function with given fields: chain
+func (_m *Client) WarpAPI(chain string) api.WarpClient {
+	ret := _m.Called(chain)
+
+	var r0 api.WarpClient
+	if rf, ok := ret.Get(0).(func(string) api.WarpClient); ok {
+		r0 = rf(chain)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(api.WarpClient)
+		}
+	}
+
+	return r0
+}
+
 // XChainAPI provides a mock function with given fields:
 func (_m *Client) XChainAPI() avm.Client {
 	ret := _m.Called()
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/luxdefi/node/utils/rpc"
+)
+
+var ErrUnsupportedAPIClientOption = errors.New("API client option not supported")
+
+// APIClientOption configures the clients created by NewAPIClientWithOptions,
+// NewMetricsClient and NewWarpClient
+type APIClientOption func(*apiClientConfig)
+
+type apiClientConfig struct {
+	// base URI of the node APIs, replacing http://ipAddr:port
+	uri        string
+	httpClient *http.Client
+	header     http.Header
+	timeout    time.Duration
+}
+
+func newAPIClientConfig(opts []APIClientOption) *apiClientConfig {
+	cfg := &apiClientConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithBaseURI makes the clients call the node APIs at [uri] instead of
+// http://ipAddr:port, e.g. when the node sits behind a proxy.
+// The node APIs are expected at [uri]/ext/...
+func WithBaseURI(uri string) APIClientOption {
+	return func(cfg *apiClientConfig) {
+		cfg.uri = uri
+	}
+}
+
+// WithHTTPClient makes the clients send their HTTP requests with [client].
+// Not supported by NewAPIClientWithOptions.
+func WithHTTPClient(client *http.Client) APIClientOption {
+	return func(cfg *apiClientConfig) {
+		cfg.httpClient = client
+	}
+}
+
+// WithTransport makes the clients send their HTTP requests with [transport].
+// Not supported by NewAPIClientWithOptions.
+func WithTransport(transport http.RoundTripper) APIClientOption {
+	return func(cfg *apiClientConfig) {
+		cfg.httpClient = &http.Client{Transport: transport}
+	}
+}
+
+// WithHeader adds the header [key] with [value] to all the requests,
+// e.g. to authenticate with a proxy
+func WithHeader(key string, value string) APIClientOption {
+	return func(cfg *apiClientConfig) {
+		if cfg.header == nil {
+			cfg.header = http.Header{}
+		}
+		cfg.header.Add(key, value)
+	}
+}
+
+// WithTimeout sets the max duration of each HTTP request.
+// Not supported by NewAPIClientWithOptions.
+func WithTimeout(timeout time.Duration) APIClientOption {
+	return func(cfg *apiClientConfig) {
+		cfg.timeout = timeout
+	}
+}
+
+// returns the HTTP client sending the requests as configured
+func (cfg *apiClientConfig) getHTTPClient() *http.Client {
+	client := http.DefaultClient
+	if cfg.httpClient != nil {
+		client = cfg.httpClient
+	}
+	if cfg.timeout == 0 && cfg.header == nil {
+		return client
+	}
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if cfg.header != nil {
+		transport = &headerTransport{header: cfg.header, transport: transport}
+	}
+	withOptions := *client
+	withOptions.Transport = transport
+	if cfg.timeout != 0 {
+		withOptions.Timeout = cfg.timeout
+	}
+	return &withOptions
+}
+
+// returns the options adding the configured headers to the calls of
+// the node API clients
+func (cfg *apiClientConfig) getCallOptions() []rpc.Option {
+	options := []rpc.Option{}
+	for key, values := range cfg.header {
+		for _, value := range values {
+			options = append(options, rpc.WithHeader(key, value))
+		}
+	}
+	return options
+}
+
+// headerTransport adds [header] to the requests sent with [transport]
+type headerTransport struct {
+	header    http.Header
+	transport http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for key, values := range t.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	return t.transport.RoundTrip(req)
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxdefi/node/utils/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// Records the headers of the requests received
+type headerRecorder struct {
+	lock    sync.Mutex
+	headers map[string]http.Header
+}
+
+func newHeaderRecorder() *headerRecorder {
+	return &headerRecorder{headers: map[string]http.Header{}}
+}
+
+func (r *headerRecorder) record(req *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.headers[req.URL.Path] = req.Header.Clone()
+}
+
+func (r *headerRecorder) get(path string) http.Header {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.headers[path]
+}
+
+func TestHeaderTransport(t *testing.T) {
+	require := require.New(t)
+
+	recorder := newHeaderRecorder()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+	}))
+	defer srv.Close()
+
+	header := http.Header{}
+	header.Add("Authorization", "Bearer token")
+	header.Add("X-Multi", "a")
+	header.Add("X-Multi", "b")
+	client := &http.Client{Transport: &headerTransport{header: header, transport: http.DefaultTransport}}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/path", nil)
+	require.NoError(err)
+	req.Header.Set("X-Request", "request")
+	resp, err := client.Do(req)
+	require.NoError(err)
+	require.NoError(resp.Body.Close())
+
+	got := recorder.get("/path")
+	require.Equal("Bearer token", got.Get("Authorization"))
+	require.Equal([]string{"a", "b"}, got.Values("X-Multi"))
+	require.Equal("request", got.Get("X-Request"))
+	// the request of the caller is left as is
+	require.Empty(req.Header.Get("Authorization"))
+}
+
+func TestGetHTTPClient(t *testing.T) {
+	transport := &http.Transport{}
+	givenClient := &http.Client{Timeout: time.Minute}
+
+	tests := []struct {
+		name              string
+		opts              []APIClientOption
+		expectedClient    *http.Client
+		expectedTimeout   time.Duration
+		expectedTransport http.RoundTripper
+		expectHeaders     bool
+	}{
+		{
+			name:           "default",
+			expectedClient: http.DefaultClient,
+		},
+		{
+			name:           "given client",
+			opts:           []APIClientOption{WithHTTPClient(givenClient)},
+			expectedClient: givenClient,
+		},
+		{
+			name:              "given transport",
+			opts:              []APIClientOption{WithTransport(transport)},
+			expectedTransport: transport,
+		},
+		{
+			name:              "timeout",
+			opts:              []APIClientOption{WithHTTPClient(givenClient), WithTimeout(time.Second)},
+			expectedTimeout:   time.Second,
+			expectedTransport: http.DefaultTransport,
+		},
+		{
+			name:          "headers",
+			opts:          []APIClientOption{WithTransport(transport), WithHeader("Authorization", "Bearer token")},
+			expectHeaders: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			client := newAPIClientConfig(tt.opts).getHTTPClient()
+			if tt.expectedClient != nil {
+				require.Same(tt.expectedClient, client)
+				return
+			}
+			require.Equal(tt.expectedTimeout, client.Timeout)
+			if tt.expectHeaders {
+				headers, ok := client.Transport.(*headerTransport)
+				require.True(ok)
+				require.Same(transport, headers.transport)
+				require.Equal("Bearer token", headers.header.Get("Authorization"))
+				return
+			}
+			require.Equal(tt.expectedTransport, client.Transport)
+		})
+	}
+	// the given client is not changed
+	require.Equal(t, time.Minute, givenClient.Timeout)
+	require.Nil(t, givenClient.Transport)
+}
+
+func TestNewAPIClientWithOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []APIClientOption
+		expectErr bool
+	}{
+		{name: "no options"},
+		{name: "base URI and headers", opts: []APIClientOption{WithBaseURI("http://proxy"), WithHeader("Authorization", "Bearer token")}},
+		{name: "HTTP client", opts: []APIClientOption{WithHTTPClient(&http.Client{})}, expectErr: true},
+		{name: "transport", opts: []APIClientOption{WithTransport(&http.Transport{})}, expectErr: true},
+		{name: "timeout", opts: []APIClientOption{WithTimeout(time.Second)}, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAPIClientWithOptions("127.0.0.1", 9650, tt.opts...)
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrUnsupportedAPIClientOption)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAPIClientOptionsHeaders(t *testing.T) {
+	require := require.New(t)
+
+	recorder := newHeaderRecorder()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+		if r.URL.Path == "/ext/metrics" {
+			_, _ = w.Write([]byte("# TYPE requests counter\nrequests 3\n"))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewAPIClientWithOptions("127.0.0.1", 1, WithBaseURI(srv.URL), WithHeader("Authorization", "Bearer token"))
+	require.NoError(err)
+
+	value, err := client.MetricsAPI().GetMetricValue(context.Background(), "requests", nil)
+	require.NoError(err)
+	require.Equal(float64(3), value)
+	require.Equal("Bearer token", recorder.get("/ext/metrics").Get("Authorization"))
+
+	// the index clients add the headers to the ones given on the call. The
+	// response is not a valid index one, only the request is checked
+	_, _, _ = client.PChainIndex().GetLastAccepted(context.Background(), rpc.WithHeader("X-Call", "call"))
+	got := recorder.get("/ext/index/P/block")
+	require.Equal("Bearer token", got.Get("Authorization"))
+	require.Equal("call", got.Get("X-Call"))
+
+	// the other node API clients get them with the call options
+	_, _ = client.InfoAPI().GetNodeVersion(context.Background(), client.CallOptions()...)
+	require.Equal("Bearer token", recorder.get("/ext/info").Get("Authorization"))
+}
+
+func TestMetricsClientOptions(t *testing.T) {
+	require := require.New(t)
+
+	recorder := newHeaderRecorder()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte("# TYPE requests counter\nrequests 3\n"))
+	}))
+	defer srv.Close()
+
+	client := NewMetricsClient("http://127.0.0.1:1", WithBaseURI(srv.URL), WithHeader("Authorization", "Bearer token"))
+	_, err := client.GetMetrics(context.Background())
+	require.NoError(err)
+	require.Equal("Bearer token", recorder.get("/ext/metrics").Get("Authorization"))
+
+	// the timeout applies to each request
+	client = NewMetricsClient(srv.URL, WithTimeout(10*time.Millisecond))
+	_, err = client.GetMetrics(context.Background())
+	var netErr net.Error
+	require.ErrorAs(err, &netErr)
+	require.True(netErr.Timeout())
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/luxdefi/coreth/rpc"
+	"github.com/luxdefi/node/ids"
+)
+
+// Interface compliance
+var _ WarpClient = &warpClient{}
+
+// WarpClient calls the warp API of an EVM chain of a node, to get the
+// warp messages of the chain and the validator signatures of them
+type WarpClient interface {
+	// GetMessage returns the unsigned warp message [messageID]
+	GetMessage(ctx context.Context, messageID ids.ID) ([]byte, error)
+	// GetMessageSignature returns the BLS signature of the node for [messageID]
+	GetMessageSignature(ctx context.Context, messageID ids.ID) ([]byte, error)
+	// GetMessageAggregateSignature returns the signed warp message [messageID],
+	// with the signatures of at least [quorumNum] percent of the stake of the
+	// validators of [subnetID] (the chain subnet if empty) aggregated by the node
+	GetMessageAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetID string) ([]byte, error)
+	// GetBlockSignature returns the BLS signature of the node for [blockID]
+	GetBlockSignature(ctx context.Context, blockID ids.ID) ([]byte, error)
+	// GetBlockAggregateSignature returns the signed warp message of [blockID],
+	// with the signatures of at least [quorumNum] percent of the stake of the
+	// validators of [subnetID] (the chain subnet if empty) aggregated by the node
+	GetBlockAggregateSignature(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetID string) ([]byte, error)
+}
+
+type warpClient struct {
+	uri        string
+	httpClient *http.Client
+}
+
+// NewWarpClient creates a WarpClient for the chain [chain] (ID or alias)
+// of the node at [uri], configured by [opts]
+func NewWarpClient(uri string, chain string, opts ...APIClientOption) WarpClient {
+	cfg := newAPIClientConfig(opts)
+	if cfg.uri != "" {
+		uri = cfg.uri
+	}
+	return newWarpClient(uri, chain, cfg.getHTTPClient())
+}
+
+func newWarpClient(uri string, chain string, httpClient *http.Client) WarpClient {
+	return &warpClient{
+		uri:        fmt.Sprintf("%s/ext/bc/%s/rpc", uri, chain),
+		httpClient: httpClient,
+	}
+}
+
+func (c *warpClient) GetMessage(ctx context.Context, messageID ids.ID) ([]byte, error) {
+	return c.call(ctx, "warp_getMessage", messageID)
+}
+
+func (c *warpClient) GetMessageSignature(ctx context.Context, messageID ids.ID) ([]byte, error) {
+	return c.call(ctx, "warp_getMessageSignature", messageID)
+}
+
+func (c *warpClient) GetMessageAggregateSignature(ctx context.Context, messageID ids.ID, quorumNum uint64, subnetID string) ([]byte, error) {
+	return c.call(ctx, "warp_getMessageAggregateSignature", messageID, quorumNum, subnetID)
+}
+
+func (c *warpClient) GetBlockSignature(ctx context.Context, blockID ids.ID) ([]byte, error) {
+	return c.call(ctx, "warp_getBlockSignature", blockID)
+}
+
+func (c *warpClient) GetBlockAggregateSignature(ctx context.Context, blockID ids.ID, quorumNum uint64, subnetID string) ([]byte, error) {
+	return c.call(ctx, "warp_getBlockAggregateSignature", blockID, quorumNum, subnetID)
+}
+
+// call calls the JSON-RPC [method] of the chain, that returns hex encoded bytes
+func (c *warpClient) call(ctx context.Context, method string, args ...interface{}) ([]byte, error) {
+	client, err := rpc.DialHTTPWithClient(c.uri, c.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	var res hexutil.Bytes
+	if err := client.CallContext(ctx, &res, method, args...); err != nil {
+		return nil, fmt.Errorf("%s failed: %w", method, err)
+	}
+	return res, nil
+}
@@ -7,6 +7,7 @@ package client
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"strings"
 	"sync"
@@ -14,43 +15,141 @@ import (
 
 	"github.com/luxdefi/netrunner/local"
 	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/netrunner/utils/constants"
 	"github.com/luxdefi/node/utils/logging"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// RPCVersion is the version of the RPC protocol of this client
+	RPCVersion uint32 = 2
+	// MinServerRPCVersion is the oldest RPC server version compatible
+	// with this client
+	MinServerRPCVersion uint32 = 1
+)
+
+var ErrIncompatibleVersion = errors.New("incompatible RPC versions")
+
+// How the client checks the server RPC version on New.
+type VersionCheck int
+
+const (
+	// logs a warning if the server is not compatible
+	VersionCheckWarn VersionCheck = iota
+	// fails New if the server is not compatible
+	VersionCheckStrict
+	// doesn't check the server version
+	VersionCheckDisabled
+)
+
 type Config struct {
-	Endpoint    string
+	Endpoint string
+	// unix domain socket path of the server (overrides Endpoint)
+	GRPCSocket  string
 	DialTimeout time.Duration
+	// name of the network targeted by the requests. if empty, the server
+	// targets its default network
+	NetworkName string
+	// how the server RPC version is checked on New
+	VersionCheck VersionCheck
+	// API token sent with the requests, if the server requires one
+	Token string
+	// max delay between attempts to reconnect to the server once the
+	// connection is lost. 5 seconds if not given
+	ReconnectMaxBackoff time.Duration
+	// retries of the idempotent calls while the server is unavailable.
+	// No retries by default
+	RetryPolicy RetryPolicy
+	// called each time the connection to the server is lost
+	OnDisconnect func()
+	// true to return from New without waiting for the connection to the
+	// server. The server RPC version is then not checked
+	LazyConnect bool
 }
 
 type Client interface {
 	Ping(ctx context.Context) (*rpcpb.PingResponse, error)
 	RPCVersion(ctx context.Context) (*rpcpb.RPCVersionResponse, error)
+	HasCapability(ctx context.Context, capability string) (bool, error)
 	Start(ctx context.Context, execPath string, opts ...OpOption) (*rpcpb.StartResponse, error)
-	CreateBlockchains(ctx context.Context, blockchainSpecs []*rpcpb.BlockchainSpec) (*rpcpb.CreateBlockchainsResponse, error)
+	CreateBlockchains(ctx context.Context, blockchainSpecs []*rpcpb.BlockchainSpec, opts ...OpOption) (*rpcpb.CreateBlockchainsResponse, error)
 	CreateSubnets(ctx context.Context, subnetSpecs []*rpcpb.SubnetSpec) (*rpcpb.CreateSubnetsResponse, error)
 	TransformElasticSubnets(ctx context.Context, elasticSubnetSpecs []*rpcpb.ElasticSubnetSpec) (*rpcpb.TransformElasticSubnetsResponse, error)
 	AddPermissionlessValidator(ctx context.Context, validatorSpec []*rpcpb.PermissionlessValidatorSpec) (*rpcpb.AddPermissionlessValidatorResponse, error)
-	RemoveSubnetValidator(ctx context.Context, validatorSpec []*rpcpb.RemoveSubnetValidatorSpec) (*rpcpb.RemoveSubnetValidatorResponse, error)
+	RemoveSubnetValidator(ctx context.Context, validatorSpec []*rpcpb.RemoveSubnetValidatorSpec, opts ...OpOption) (*rpcpb.RemoveSubnetValidatorResponse, error)
+	WaitForValidatorRewards(ctx context.Context, nodeName string, subnetID string) (*rpcpb.WaitForValidatorRewardsResponse, error)
+	ListBlockchains(ctx context.Context) (*rpcpb.ListBlockchainsResponse, error)
+	GetBlockchainStatus(ctx context.Context, chain string) (*rpcpb.GetBlockchainStatusResponse, error)
+	AddBlockchainAlias(ctx context.Context, chainID string, alias string) (*rpcpb.AddBlockchainAliasResponse, error)
+	RemoveBlockchainAlias(ctx context.Context, chainID string, alias string) (*rpcpb.RemoveBlockchainAliasResponse, error)
+	AddVMAlias(ctx context.Context, vmID string, alias string) (*rpcpb.AddVMAliasResponse, error)
+	TransferSubnetOwnership(ctx context.Context, transferSpecs []*rpcpb.TransferSubnetOwnershipSpec) (*rpcpb.TransferSubnetOwnershipResponse, error)
+	CreateAsset(ctx context.Context, req *rpcpb.CreateAssetRequest) (*rpcpb.CreateAssetResponse, error)
+	Transfer(ctx context.Context, to string, amount uint64) (*rpcpb.TransferResponse, error)
+	Stake(ctx context.Context, req *rpcpb.StakeRequest) (*rpcpb.StakeResponse, error)
+	RenewPrimaryValidations(ctx context.Context, req *rpcpb.RenewPrimaryValidationsRequest) (*rpcpb.RenewPrimaryValidationsResponse, error)
+	GetUptimes(ctx context.Context, subnetID string) (*rpcpb.GetUptimesResponse, error)
+	GetElasticSubnet(ctx context.Context, subnetID string) (*rpcpb.GetElasticSubnetResponse, error)
+	GetStakingRewards(ctx context.Context, subnetID string) (*rpcpb.GetStakingRewardsResponse, error)
+	GetVersions(ctx context.Context) (*rpcpb.GetVersionsResponse, error)
+	GetTopology(ctx context.Context, format rpcpb.TopologyFormat) (*rpcpb.GetTopologyResponse, error)
+	ExportNetwork(ctx context.Context, format rpcpb.NetworkExportFormat, image string) (string, error)
+	CheckConnectivity(ctx context.Context) (*rpcpb.CheckConnectivityResponse, error)
+	GetOperationHistory(ctx context.Context, method string, since time.Time, limit uint32) (*rpcpb.GetOperationHistoryResponse, error)
 	Health(ctx context.Context) (*rpcpb.HealthResponse, error)
 	WaitForHealthy(ctx context.Context) (*rpcpb.WaitForHealthyResponse, error)
+	WaitFor(ctx context.Context, req *rpcpb.WaitForRequest) (*rpcpb.WaitForResponse, error)
+	Assert(ctx context.Context, assertions ...*rpcpb.Assertion) (*rpcpb.AssertResponse, error)
+	AssertValidatorCount(ctx context.Context, subnetID string, count uint32) (*rpcpb.AssertionResult, error)
+	AssertChainHealthy(ctx context.Context, chain string) (*rpcpb.AssertionResult, error)
+	AssertNodeVersion(ctx context.Context, nodeName string, version string) (*rpcpb.AssertionResult, error)
 	URIs(ctx context.Context) ([]string, error)
 	Status(ctx context.Context) (*rpcpb.StatusResponse, error)
-	StreamStatus(ctx context.Context, pushInterval time.Duration) (<-chan *rpcpb.ClusterInfo, error)
+	GetNodeInfo(ctx context.Context, name string) (*rpcpb.GetNodeInfoResponse, error)
+	ListNodes(ctx context.Context, req *rpcpb.ListNodesRequest) (*rpcpb.ListNodesResponse, error)
+	ListCustomChains(ctx context.Context, req *rpcpb.ListCustomChainsRequest) (*rpcpb.ListCustomChainsResponse, error)
+	StreamStatus(ctx context.Context, pushInterval time.Duration, opts ...OpOption) (<-chan *rpcpb.ClusterInfo, error)
+	StreamLogs(ctx context.Context, nodeName string, handler func(lines []string), opts ...OpOption) error
+	SearchLogs(ctx context.Context, nodeName string, pattern string, opts ...OpOption) (*rpcpb.SearchLogsResponse, error)
 	RemoveNode(ctx context.Context, name string) (*rpcpb.RemoveNodeResponse, error)
+	DrainNode(ctx context.Context, name string) (*rpcpb.DrainNodeResponse, error)
+	ScaleNetwork(ctx context.Context, numNodes uint32) (*rpcpb.ScaleNetworkResponse, error)
+	ResetNetwork(ctx context.Context) (*rpcpb.ResetNetworkResponse, error)
+	StateSyncNode(ctx context.Context, name string, handler func(*rpcpb.StateSyncNodeResponse), opts ...OpOption) error
+	ExportCChainState(ctx context.Context, path string, opts ...OpOption) (*rpcpb.ExportCChainStateResponse, error)
+	GetDBSizes(ctx context.Context, nodeNames ...string) (*rpcpb.GetDBSizesResponse, error)
+	PruneNodeDB(ctx context.Context, name string) (*rpcpb.PruneNodeDBResponse, error)
 	PauseNode(ctx context.Context, name string) (*rpcpb.PauseNodeResponse, error)
 	ResumeNode(ctx context.Context, name string) (*rpcpb.ResumeNodeResponse, error)
+	PauseAll(ctx context.Context) (*rpcpb.PauseAllResponse, error)
+	ResumeAll(ctx context.Context) (*rpcpb.ResumeAllResponse, error)
+	SimulateDowntime(ctx context.Context, name string, duration time.Duration) (*rpcpb.SimulateDowntimeResponse, error)
+	StartChaos(ctx context.Context, req *rpcpb.StartChaosRequest) (*rpcpb.StartChaosResponse, error)
+	StopChaos(ctx context.Context) (*rpcpb.StopChaosResponse, error)
+	GetChaosStatus(ctx context.Context) (*rpcpb.GetChaosStatusResponse, error)
+	ListKeys(ctx context.Context) (*rpcpb.ListKeysResponse, error)
+	CreateKey(ctx context.Context, name string) (*rpcpb.CreateKeyResponse, error)
+	ExportKey(ctx context.Context, name string) (*rpcpb.ExportKeyResponse, error)
+	SignMessage(ctx context.Context, keyName string, message []byte) (*rpcpb.SignMessageResponse, error)
+	SignTx(ctx context.Context, req *rpcpb.SignTxRequest) (*rpcpb.SignTxResponse, error)
 	RestartNode(ctx context.Context, name string, opts ...OpOption) (*rpcpb.RestartNodeResponse, error)
 	AddNode(ctx context.Context, name string, execPath string, opts ...OpOption) (*rpcpb.AddNodeResponse, error)
-	Stop(ctx context.Context) (*rpcpb.StopResponse, error)
-	AttachPeer(ctx context.Context, nodeName string) (*rpcpb.AttachPeerResponse, error)
+	Stop(ctx context.Context, opts ...OpOption) (*rpcpb.StopResponse, error)
+	AttachPeer(ctx context.Context, nodeName string, opts ...OpOption) (*rpcpb.AttachPeerResponse, error)
 	SendOutboundMessage(ctx context.Context, nodeName string, peerID string, op uint32, msgBody []byte) (*rpcpb.SendOutboundMessageResponse, error)
+	SendPeerMessage(ctx context.Context, req *rpcpb.SendPeerMessageRequest) (*rpcpb.SendPeerMessageResponse, error)
+	SendAppRequest(ctx context.Context, nodeName string, peerID string, chainID string, appBytes []byte, deadline time.Duration) (*rpcpb.SendAppRequestResponse, error)
+	StreamPeerMessages(ctx context.Context, nodeName string, peerID string, handler func(*rpcpb.StreamPeerMessagesResponse)) error
+	StreamProgress(ctx context.Context, handler func(*rpcpb.StreamProgressResponse)) error
+	RecordPeerMessages(ctx context.Context, nodeName string, peerID string, path string) (*rpcpb.RecordPeerMessagesResponse, error)
+	ReplayPeerMessages(ctx context.Context, nodeName string, peerID string, path string, keepTiming bool) (*rpcpb.ReplayPeerMessagesResponse, error)
 	Close() error
-	SaveSnapshot(ctx context.Context, snapshotName string) (*rpcpb.SaveSnapshotResponse, error)
+	SaveSnapshot(ctx context.Context, snapshotName string, opts ...OpOption) (*rpcpb.SaveSnapshotResponse, error)
 	LoadSnapshot(ctx context.Context, snapshotName string, opts ...OpOption) (*rpcpb.LoadSnapshotResponse, error)
 	RemoveSnapshot(ctx context.Context, snapshotName string) (*rpcpb.RemoveSnapshotResponse, error)
 	GetSnapshotNames(ctx context.Context) ([]string, error)
@@ -64,34 +163,108 @@ type client struct {
 
 	pingc    rpcpb.PingServiceClient
 	controlc rpcpb.ControlServiceClient
+	keyc     rpcpb.KeyServiceClient
 
 	closed    chan struct{}
 	closeOnce sync.Once
+
+	// server RPC version, cached by HasCapability
+	versionLock sync.Mutex
+	version     *rpcpb.RPCVersionResponse
 }
 
 func New(cfg Config, log logging.Logger) (Client, error) {
-	log.Debug("dialing server at ", zap.String("endpoint", cfg.Endpoint))
+	return NewWithContext(context.Background(), cfg, log)
+}
+
+// NewWithContext creates a client, connecting to the server until [ctx]
+// is done or [cfg.DialTimeout] expires. If [cfg.LazyConnect] is set, it
+// returns without waiting for the connection, nor checking the server
+// RPC version.
+func NewWithContext(ctx context.Context, cfg Config, log logging.Logger) (Client, error) {
+	target := cfg.Endpoint
+	if cfg.GRPCSocket != "" {
+		target = "unix:" + cfg.GRPCSocket
+	}
+	log.Debug("dialing server at ", zap.String("endpoint", target))
 
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
-	conn, err := grpc.DialContext(
-		ctx,
-		cfg.Endpoint,
-		grpc.WithBlock(),
+	dialOpts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
-	cancel()
+		withReconnectBackoff(cfg.ReconnectMaxBackoff, cfg.DialTimeout),
+		grpc.WithChainUnaryInterceptor(
+			networkNameUnaryInterceptor(cfg.NetworkName),
+			tokenUnaryInterceptor(cfg.Token),
+			retryUnaryInterceptor(cfg.RetryPolicy, log),
+		),
+		grpc.WithChainStreamInterceptor(
+			networkNameStreamInterceptor(cfg.NetworkName),
+			tokenStreamInterceptor(cfg.Token),
+		),
+	}
+	if !cfg.LazyConnect {
+		dialOpts = append(dialOpts, grpc.WithBlock())
+	}
+	if cfg.DialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.DialTimeout)
+		defer cancel()
+	}
+	conn, err := grpc.DialContext(ctx, target, dialOpts...)
 	if err != nil {
 		return nil, err
 	}
 
-	return &client{
+	c := &client{
 		cfg:      cfg,
 		log:      log,
 		conn:     conn,
 		pingc:    rpcpb.NewPingServiceClient(conn),
 		controlc: rpcpb.NewControlServiceClient(conn),
+		keyc:     rpcpb.NewKeyServiceClient(conn),
 		closed:   make(chan struct{}),
-	}, nil
+	}
+	if !cfg.LazyConnect {
+		if err := c.checkVersion(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	go c.monitorConnection()
+	return c, nil
+}
+
+// Checks the server RPC version is compatible with this client,
+// as configured by [c.cfg.VersionCheck].
+func (c *client) checkVersion(ctx context.Context) error {
+	if c.cfg.VersionCheck == VersionCheckDisabled {
+		return nil
+	}
+	resp, err := c.RPCVersion(ctx)
+	if err == nil {
+		err = CheckRPCVersion(resp)
+	}
+	if err == nil {
+		return nil
+	}
+	if c.cfg.VersionCheck == VersionCheckStrict {
+		return err
+	}
+	c.log.Warn("couldn't verify RPC server compatibility", zap.Error(err))
+	return nil
+}
+
+// CheckRPCVersion returns an error if the server that replied [resp]
+// is not compatible with this client.
+func CheckRPCVersion(resp *rpcpb.RPCVersionResponse) error {
+	if resp.Version < MinServerRPCVersion {
+		return fmt.Errorf("%w: server version %d is older than the min supported %d, upgrade the server",
+			ErrIncompatibleVersion, resp.Version, MinServerRPCVersion)
+	}
+	if RPCVersion < resp.MinClientVersion {
+		return fmt.Errorf("%w: client version %d is older than the min supported by the server %d, upgrade the client",
+			ErrIncompatibleVersion, RPCVersion, resp.MinClientVersion)
+	}
+	return nil
 }
 
 func (c *client) Ping(ctx context.Context) (*rpcpb.PingResponse, error) {
@@ -104,7 +277,29 @@ func (c *client) Ping(ctx context.Context) (*rpcpb.PingResponse, error) {
 
 func (c *client) RPCVersion(ctx context.Context) (*rpcpb.RPCVersionResponse, error) {
 	c.log.Info("rpc version")
-	return c.controlc.RPCVersion(ctx, &rpcpb.RPCVersionRequest{})
+	return c.controlc.RPCVersion(ctx, &rpcpb.RPCVersionRequest{ClientVersion: RPCVersion})
+}
+
+// HasCapability returns true if the server supports the optional feature
+// [capability], one of the constants.Capability values. Servers older
+// than the capabilities report have none.
+func (c *client) HasCapability(ctx context.Context, capability string) (bool, error) {
+	c.versionLock.Lock()
+	defer c.versionLock.Unlock()
+
+	if c.version == nil {
+		resp, err := c.RPCVersion(ctx)
+		if err != nil {
+			return false, err
+		}
+		c.version = resp
+	}
+	for _, supported := range c.version.Capabilities {
+		if supported == capability {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (c *client) Start(ctx context.Context, execPath string, opts ...OpOption) (*rpcpb.StartResponse, error) {
@@ -112,11 +307,22 @@ func (c *client) Start(ctx context.Context, execPath string, opts ...OpOption) (
 	ret.applyOpts(opts)
 
 	req := &rpcpb.StartRequest{
-		ExecPath:       execPath,
-		NumNodes:       &ret.numNodes,
-		ChainConfigs:   ret.chainConfigs,
-		UpgradeConfigs: ret.upgradeConfigs,
-		SubnetConfigs:  ret.subnetConfigs,
+		ExecPath:           execPath,
+		NumNodes:           &ret.numNodes,
+		ChainConfigs:       ret.chainConfigs,
+		UpgradeConfigs:     ret.upgradeConfigs,
+		UpgradeSpecs:       ret.upgradeSpecs,
+		SubnetConfigs:      ret.subnetConfigs,
+		Backend:            ret.backend,
+		Ttl:                int64(ret.ttl),
+		Reconcile:          ret.reconcile,
+		NodeGroups:         ret.nodeGroups,
+		LogsShardSize:      ret.logsShardSize,
+		DirLayout:          ret.dirLayout,
+		Seed:               ret.seed,
+		NetworkId:          ret.networkID,
+		TxSigner:           ret.txSigner,
+		StakingKeysStorage: ret.stakingKeysStorage,
 	}
 	if ret.trackSubnets != "" {
 		req.WhitelistedSubnets = &ret.trackSubnets
@@ -138,14 +344,37 @@ func (c *client) Start(ctx context.Context, execPath string, opts ...OpOption) (
 	}
 	req.ReassignPortsIfUsed = &ret.reassignPortsIfUsed
 	req.DynamicPorts = &ret.dynamicPorts
+	if ret.stakingSpec != nil {
+		req.StakingSpec = ret.stakingSpec
+	}
+	if ret.networkAccess != nil {
+		req.NetworkAccess = ret.networkAccess
+	}
+	if ret.portRange != nil {
+		req.PortRange = ret.portRange
+	}
+	if ret.externalNetwork != nil {
+		req.ExternalNetwork = ret.externalNetwork
+	}
+	if ret.fork != nil {
+		req.Fork = ret.fork
+	}
+	if ret.cChainStatePath != "" {
+		req.CChainStatePath = ret.cChainStatePath
+	}
+	req.ValidateOnly = ret.validateOnly
 
 	c.log.Info("start")
 	return c.controlc.Start(ctx, req)
 }
 
-func (c *client) CreateBlockchains(ctx context.Context, blockchainSpecs []*rpcpb.BlockchainSpec) (*rpcpb.CreateBlockchainsResponse, error) {
+func (c *client) CreateBlockchains(ctx context.Context, blockchainSpecs []*rpcpb.BlockchainSpec, opts ...OpOption) (*rpcpb.CreateBlockchainsResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
 	req := &rpcpb.CreateBlockchainsRequest{
 		BlockchainSpecs: blockchainSpecs,
+		ValidateOnly:    ret.validateOnly,
 	}
 
 	c.log.Info("create blockchains")
@@ -179,9 +408,140 @@ func (c *client) AddPermissionlessValidator(ctx context.Context, validatorSpec [
 	return c.controlc.AddPermissionlessValidator(ctx, req)
 }
 
-func (c *client) RemoveSubnetValidator(ctx context.Context, validatorSpec []*rpcpb.RemoveSubnetValidatorSpec) (*rpcpb.RemoveSubnetValidatorResponse, error) {
+func (c *client) WaitForValidatorRewards(ctx context.Context, nodeName string, subnetID string) (*rpcpb.WaitForValidatorRewardsResponse, error) {
+	req := &rpcpb.WaitForValidatorRewardsRequest{
+		NodeName: nodeName,
+		SubnetId: subnetID,
+	}
+
+	c.log.Info("wait for validator rewards")
+	return c.controlc.WaitForValidatorRewards(ctx, req)
+}
+
+func (c *client) ListBlockchains(ctx context.Context) (*rpcpb.ListBlockchainsResponse, error) {
+	c.log.Info("list blockchains")
+	return c.controlc.ListBlockchains(ctx, &rpcpb.ListBlockchainsRequest{})
+}
+
+func (c *client) GetBlockchainStatus(ctx context.Context, chain string) (*rpcpb.GetBlockchainStatusResponse, error) {
+	c.log.Info("get blockchain status", zap.String("chain", chain))
+	return c.controlc.GetBlockchainStatus(ctx, &rpcpb.GetBlockchainStatusRequest{Chain: chain})
+}
+
+func (c *client) AddBlockchainAlias(ctx context.Context, chainID string, alias string) (*rpcpb.AddBlockchainAliasResponse, error) {
+	c.log.Info("add blockchain alias", zap.String("chain-id", chainID), zap.String("alias", alias))
+	return c.controlc.AddBlockchainAlias(ctx, &rpcpb.AddBlockchainAliasRequest{ChainId: chainID, Alias: alias})
+}
+
+func (c *client) RemoveBlockchainAlias(ctx context.Context, chainID string, alias string) (*rpcpb.RemoveBlockchainAliasResponse, error) {
+	c.log.Info("remove blockchain alias", zap.String("chain-id", chainID), zap.String("alias", alias))
+	return c.controlc.RemoveBlockchainAlias(ctx, &rpcpb.RemoveBlockchainAliasRequest{ChainId: chainID, Alias: alias})
+}
+
+func (c *client) AddVMAlias(ctx context.Context, vmID string, alias string) (*rpcpb.AddVMAliasResponse, error) {
+	c.log.Info("add vm alias", zap.String("vm-id", vmID), zap.String("alias", alias))
+	return c.controlc.AddVMAlias(ctx, &rpcpb.AddVMAliasRequest{VmId: vmID, Alias: alias})
+}
+
+func (c *client) TransferSubnetOwnership(ctx context.Context, transferSpecs []*rpcpb.TransferSubnetOwnershipSpec) (*rpcpb.TransferSubnetOwnershipResponse, error) {
+	req := &rpcpb.TransferSubnetOwnershipRequest{
+		TransferSpecs: transferSpecs,
+	}
+
+	c.log.Info("transfer subnet ownership")
+	return c.controlc.TransferSubnetOwnership(ctx, req)
+}
+
+func (c *client) CreateAsset(ctx context.Context, req *rpcpb.CreateAssetRequest) (*rpcpb.CreateAssetResponse, error) {
+	c.log.Info("create asset", zap.String("name", req.GetName()), zap.Bool("nft", req.GetNft()))
+	return c.controlc.CreateAsset(ctx, req)
+}
+
+func (c *client) Transfer(ctx context.Context, to string, amount uint64) (*rpcpb.TransferResponse, error) {
+	c.log.Info("transfer", zap.String("to", to), zap.Uint64("amount", amount))
+	return c.controlc.Transfer(ctx, &rpcpb.TransferRequest{To: to, Amount: amount})
+}
+
+func (c *client) Stake(ctx context.Context, req *rpcpb.StakeRequest) (*rpcpb.StakeResponse, error) {
+	c.log.Info("stake", zap.String("node-name", req.GetNodeName()), zap.Bool("delegate", req.GetDelegate()))
+	return c.controlc.Stake(ctx, req)
+}
+
+func (c *client) RenewPrimaryValidations(ctx context.Context, req *rpcpb.RenewPrimaryValidationsRequest) (*rpcpb.RenewPrimaryValidationsResponse, error) {
+	c.log.Info("renew primary validations", zap.Strings("node-names", req.GetNodeNames()))
+	return c.controlc.RenewPrimaryValidations(ctx, req)
+}
+
+func (c *client) GetUptimes(ctx context.Context, subnetID string) (*rpcpb.GetUptimesResponse, error) {
+	c.log.Info("get uptimes")
+	return c.controlc.GetUptimes(ctx, &rpcpb.GetUptimesRequest{SubnetId: subnetID})
+}
+
+func (c *client) GetElasticSubnet(ctx context.Context, subnetID string) (*rpcpb.GetElasticSubnetResponse, error) {
+	c.log.Info("get elastic subnet", zap.String("subnet-id", subnetID))
+	return c.controlc.GetElasticSubnet(ctx, &rpcpb.GetElasticSubnetRequest{SubnetId: subnetID})
+}
+
+func (c *client) GetStakingRewards(ctx context.Context, subnetID string) (*rpcpb.GetStakingRewardsResponse, error) {
+	c.log.Info("get staking rewards", zap.String("subnet-id", subnetID))
+	return c.controlc.GetStakingRewards(ctx, &rpcpb.GetStakingRewardsRequest{SubnetId: subnetID})
+}
+
+func (c *client) GetVersions(ctx context.Context) (*rpcpb.GetVersionsResponse, error) {
+	c.log.Info("get versions")
+	return c.controlc.GetVersions(ctx, &rpcpb.GetVersionsRequest{})
+}
+
+// GetTopology returns the nodes, peer connections and subnets of the
+// network, also exported in [format] if given.
+func (c *client) GetTopology(ctx context.Context, format rpcpb.TopologyFormat) (*rpcpb.GetTopologyResponse, error) {
+	c.log.Info("get topology", zap.String("format", format.String()))
+	return c.controlc.GetTopology(ctx, &rpcpb.GetTopologyRequest{Format: format})
+}
+
+// ExportNetwork returns the running network rendered in [format], with
+// the nodes running [image] (a default image if empty).
+func (c *client) ExportNetwork(ctx context.Context, format rpcpb.NetworkExportFormat, image string) (string, error) {
+	c.log.Info("export network", zap.String("format", format.String()), zap.String("image", image))
+	resp, err := c.controlc.ExportNetwork(ctx, &rpcpb.ExportNetworkRequest{Format: format, Image: image})
+	if err != nil {
+		return "", err
+	}
+	return resp.Export, nil
+}
+
+// CheckConnectivity returns the connections between running nodes
+// missing from the peers reported by their info API.
+func (c *client) CheckConnectivity(ctx context.Context) (*rpcpb.CheckConnectivityResponse, error) {
+	c.log.Info("check connectivity")
+	return c.controlc.CheckConnectivity(ctx, &rpcpb.CheckConnectivityRequest{})
+}
+
+func (c *client) GetOperationHistory(
+	ctx context.Context,
+	method string,
+	since time.Time,
+	limit uint32,
+) (*rpcpb.GetOperationHistoryResponse, error) {
+	req := &rpcpb.GetOperationHistoryRequest{
+		Method: method,
+		Limit:  limit,
+	}
+	if !since.IsZero() {
+		req.Since = since.UnixNano()
+	}
+
+	c.log.Info("get operation history", zap.String("method", method))
+	return c.controlc.GetOperationHistory(ctx, req)
+}
+
+func (c *client) RemoveSubnetValidator(ctx context.Context, validatorSpec []*rpcpb.RemoveSubnetValidatorSpec, opts ...OpOption) (*rpcpb.RemoveSubnetValidatorResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
 	req := &rpcpb.RemoveSubnetValidatorRequest{
 		ValidatorSpec: validatorSpec,
+		SkipRestart:   ret.skipRestart,
 	}
 
 	c.log.Info("remove subnet validator")
@@ -198,6 +558,57 @@ func (c *client) WaitForHealthy(ctx context.Context) (*rpcpb.WaitForHealthyRespo
 	return c.controlc.WaitForHealthy(ctx, &rpcpb.WaitForHealthyRequest{})
 }
 
+// WaitFor waits until all the conditions of [req] hold. [ctx] must not
+// expire before the timeout of [req].
+func (c *client) WaitFor(ctx context.Context, req *rpcpb.WaitForRequest) (*rpcpb.WaitForResponse, error) {
+	c.log.Info("wait for", zap.Uint32("healthy-nodes", req.HealthyNodes), zap.Strings("bootstrapped-blockchains", req.BootstrappedBlockchains))
+	return c.controlc.WaitFor(ctx, req)
+}
+
+// Assert evaluates [assertions] on the network. Failed assertions are
+// reported in the response, not as an error.
+func (c *client) Assert(ctx context.Context, assertions ...*rpcpb.Assertion) (*rpcpb.AssertResponse, error) {
+	c.log.Info("assert", zap.Int("assertions", len(assertions)))
+	return c.controlc.Assert(ctx, &rpcpb.AssertRequest{Assertions: assertions})
+}
+
+// AssertValidatorCount checks [subnetID], or the primary network if empty,
+// has [count] current validators.
+func (c *client) AssertValidatorCount(ctx context.Context, subnetID string, count uint32) (*rpcpb.AssertionResult, error) {
+	return c.assertOne(ctx, &rpcpb.Assertion{
+		Assertion: &rpcpb.Assertion_ValidatorCount{
+			ValidatorCount: &rpcpb.ValidatorCountAssertion{SubnetId: subnetID, Count: count},
+		},
+	})
+}
+
+// AssertChainHealthy checks [chain], given by ID or alias, is validated
+// and bootstrapped on all running nodes.
+func (c *client) AssertChainHealthy(ctx context.Context, chain string) (*rpcpb.AssertionResult, error) {
+	return c.assertOne(ctx, &rpcpb.Assertion{
+		Assertion: &rpcpb.Assertion_ChainHealthy{
+			ChainHealthy: &rpcpb.ChainHealthyAssertion{Chain: chain},
+		},
+	})
+}
+
+// AssertNodeVersion checks [nodeName] runs [version].
+func (c *client) AssertNodeVersion(ctx context.Context, nodeName string, version string) (*rpcpb.AssertionResult, error) {
+	return c.assertOne(ctx, &rpcpb.Assertion{
+		Assertion: &rpcpb.Assertion_NodeVersion{
+			NodeVersion: &rpcpb.NodeVersionAssertion{NodeName: nodeName, Version: version},
+		},
+	})
+}
+
+func (c *client) assertOne(ctx context.Context, assertion *rpcpb.Assertion) (*rpcpb.AssertionResult, error) {
+	resp, err := c.Assert(ctx, assertion)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results[0], nil
+}
+
 func (c *client) URIs(ctx context.Context) ([]string, error) {
 	c.log.Info("uris")
 	resp, err := c.controlc.URIs(ctx, &rpcpb.URIsRequest{})
@@ -212,9 +623,85 @@ func (c *client) Status(ctx context.Context) (*rpcpb.StatusResponse, error) {
 	return c.controlc.Status(ctx, &rpcpb.StatusRequest{})
 }
 
-func (c *client) StreamStatus(ctx context.Context, pushInterval time.Duration) (<-chan *rpcpb.ClusterInfo, error) {
+func (c *client) GetNodeInfo(ctx context.Context, name string) (*rpcpb.GetNodeInfoResponse, error) {
+	c.log.Info("get node info", zap.String("name", name))
+	return c.controlc.GetNodeInfo(ctx, &rpcpb.GetNodeInfoRequest{Name: name})
+}
+
+func (c *client) ListNodes(ctx context.Context, req *rpcpb.ListNodesRequest) (*rpcpb.ListNodesResponse, error) {
+	c.log.Info("list nodes", zap.String("subnet-id", req.SubnetId), zap.String("page-token", req.PageToken))
+	return c.controlc.ListNodes(ctx, req)
+}
+
+func (c *client) ListCustomChains(ctx context.Context, req *rpcpb.ListCustomChainsRequest) (*rpcpb.ListCustomChainsResponse, error) {
+	c.log.Info("list custom chains", zap.String("subnet-id", req.SubnetId), zap.String("page-token", req.PageToken))
+	return c.controlc.ListCustomChains(ctx, req)
+}
+
+// StreamLogs calls [handler] with the log lines of [nodeName] until the
+// server closes the stream, returning the error that closed it, if any.
+func (c *client) StreamLogs(ctx context.Context, nodeName string, handler func(lines []string), opts ...OpOption) error {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	req := &rpcpb.StreamLogsRequest{
+		NodeName: nodeName,
+		Chain:    ret.logsChain,
+		Follow:   ret.logsFollow,
+		Grep:     ret.logsGrep,
+	}
+	if !ret.logsSince.IsZero() {
+		req.Since = ret.logsSince.UnixNano()
+	}
+
+	c.log.Info("stream logs", zap.String("node-name", nodeName), zap.String("chain", ret.logsChain))
+	stream, err := c.controlc.StreamLogs(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if isClientCanceled(stream.Context().Err(), err) {
+				return nil
+			}
+			return err
+		}
+		handler(resp.Lines)
+	}
+}
+
+// SearchLogs returns the log lines of [nodeName], or of every node if
+// empty, matching the regular expression [pattern].
+func (c *client) SearchLogs(ctx context.Context, nodeName string, pattern string, opts ...OpOption) (*rpcpb.SearchLogsResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	req := &rpcpb.SearchLogsRequest{
+		NodeName:   nodeName,
+		Chain:      ret.logsChain,
+		Pattern:    pattern,
+		MaxMatches: ret.logsMaxMatches,
+	}
+	if !ret.logsSince.IsZero() {
+		req.Since = ret.logsSince.UnixNano()
+	}
+
+	c.log.Info("search logs", zap.String("node-name", nodeName), zap.String("chain", ret.logsChain), zap.String("pattern", pattern))
+	return c.controlc.SearchLogs(ctx, req)
+}
+
+func (c *client) StreamStatus(ctx context.Context, pushInterval time.Duration, opts ...OpOption) (<-chan *rpcpb.ClusterInfo, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
 	stream, err := c.controlc.StreamStatus(ctx, &rpcpb.StreamStatusRequest{
 		PushInterval: int64(pushInterval),
+		Fields:       ret.statusFields,
+		Delta:        ret.statusDelta,
 	})
 	if err != nil {
 		return nil, err
@@ -259,9 +746,15 @@ func (c *client) StreamStatus(ctx context.Context, pushInterval time.Duration) (
 	return ch, nil
 }
 
-func (c *client) Stop(ctx context.Context) (*rpcpb.StopResponse, error) {
+func (c *client) Stop(ctx context.Context, opts ...OpOption) (*rpcpb.StopResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
 	c.log.Info("stop")
-	return c.controlc.Stop(ctx, &rpcpb.StopRequest{})
+	return c.controlc.Stop(ctx, &rpcpb.StopRequest{
+		NonBeaconsFirst: ret.nonBeaconsFirst,
+		NodeTimeout:     int64(ret.nodeStopTimeout),
+	})
 }
 
 func (c *client) AddNode(ctx context.Context, name string, execPath string, opts ...OpOption) (*rpcpb.AddNodeResponse, error) {
@@ -290,6 +783,79 @@ func (c *client) RemoveNode(ctx context.Context, name string) (*rpcpb.RemoveNode
 	return c.controlc.RemoveNode(ctx, &rpcpb.RemoveNodeRequest{Name: name})
 }
 
+func (c *client) DrainNode(ctx context.Context, name string) (*rpcpb.DrainNodeResponse, error) {
+	c.log.Info("drain node", zap.String("name", name))
+	return c.controlc.DrainNode(ctx, &rpcpb.DrainNodeRequest{Name: name})
+}
+
+func (c *client) ScaleNetwork(ctx context.Context, numNodes uint32) (*rpcpb.ScaleNetworkResponse, error) {
+	c.log.Info("scale network", zap.Uint32("num-nodes", numNodes))
+	return c.controlc.ScaleNetwork(ctx, &rpcpb.ScaleNetworkRequest{NumNodes: numNodes})
+}
+
+func (c *client) ResetNetwork(ctx context.Context) (*rpcpb.ResetNetworkResponse, error) {
+	c.log.Info("reset network")
+	return c.controlc.ResetNetwork(ctx, &rpcpb.ResetNetworkRequest{})
+}
+
+// StateSyncNode adds a node state syncing from the network, and calls
+// [handler] with its sync milestones until it is healthy or [ctx] is done.
+func (c *client) StateSyncNode(ctx context.Context, name string, handler func(*rpcpb.StateSyncNodeResponse), opts ...OpOption) error {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	c.log.Info("state sync node", zap.String("name", name))
+	stream, err := c.controlc.StateSyncNode(ctx, &rpcpb.StateSyncNodeRequest{
+		Name:               name,
+		ExecPath:           ret.execPath,
+		StateSyncMinBlocks: ret.stateSyncMinBlocks,
+		PollInterval:       int64(ret.pollInterval),
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if isClientCanceled(stream.Context().Err(), err) {
+				return nil
+			}
+			return err
+		}
+		handler(resp)
+	}
+}
+
+// ExportCChainState dumps the C-Chain state of a node of the network, and
+// writes it as a C-Chain genesis with alloc at [path] on the server host.
+func (c *client) ExportCChainState(ctx context.Context, path string, opts ...OpOption) (*rpcpb.ExportCChainStateResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	c.log.Info("export C-Chain state", zap.String("path", path))
+	return c.controlc.ExportCChainState(ctx, &rpcpb.ExportCChainStateRequest{
+		Path:        path,
+		NodeName:    ret.nodeName,
+		BlockNumber: ret.blockNumber,
+	})
+}
+
+// GetDBSizes returns the sizes on disk of the db and chain data dirs of
+// [nodeNames], or of all the nodes if empty.
+func (c *client) GetDBSizes(ctx context.Context, nodeNames ...string) (*rpcpb.GetDBSizesResponse, error) {
+	c.log.Info("get db sizes", zap.Strings("node-names", nodeNames))
+	return c.controlc.GetDBSizes(ctx, &rpcpb.GetDBSizesRequest{NodeNames: nodeNames})
+}
+
+// PruneNodeDB prunes the C-Chain state of node [name] offline, restarting it.
+func (c *client) PruneNodeDB(ctx context.Context, name string) (*rpcpb.PruneNodeDBResponse, error) {
+	c.log.Info("prune node db", zap.String("name", name))
+	return c.controlc.PruneNodeDB(ctx, &rpcpb.PruneNodeDBRequest{Name: name})
+}
+
 func (c *client) PauseNode(ctx context.Context, name string) (*rpcpb.PauseNodeResponse, error) {
 	c.log.Info("pause node", zap.String("name", name))
 	return c.controlc.PauseNode(ctx, &rpcpb.PauseNodeRequest{Name: name})
@@ -300,6 +866,72 @@ func (c *client) ResumeNode(ctx context.Context, name string) (*rpcpb.ResumeNode
 	return c.controlc.ResumeNode(ctx, &rpcpb.ResumeNodeRequest{Name: name})
 }
 
+func (c *client) PauseAll(ctx context.Context) (*rpcpb.PauseAllResponse, error) {
+	c.log.Info("pause all")
+	return c.controlc.PauseAll(ctx, &rpcpb.PauseAllRequest{})
+}
+
+func (c *client) ResumeAll(ctx context.Context) (*rpcpb.ResumeAllResponse, error) {
+	c.log.Info("resume all")
+	return c.controlc.ResumeAll(ctx, &rpcpb.ResumeAllRequest{})
+}
+
+func (c *client) SimulateDowntime(ctx context.Context, name string, duration time.Duration) (*rpcpb.SimulateDowntimeResponse, error) {
+	c.log.Info("simulate downtime", zap.String("name", name), zap.Duration("duration", duration))
+	return c.controlc.SimulateDowntime(ctx, &rpcpb.SimulateDowntimeRequest{
+		Name:     name,
+		Duration: uint64(duration.Seconds()),
+	})
+}
+
+// StartChaos starts taking random nodes down and back up, as set by [req].
+func (c *client) StartChaos(ctx context.Context, req *rpcpb.StartChaosRequest) (*rpcpb.StartChaosResponse, error) {
+	c.log.Info("start chaos", zap.Duration("interval", time.Duration(req.Interval)), zap.Duration("downtime", time.Duration(req.Downtime)))
+	return c.controlc.StartChaos(ctx, req)
+}
+
+// StopChaos stops taking nodes down, bringing back up the ones that are,
+// and returns the actions taken.
+func (c *client) StopChaos(ctx context.Context) (*rpcpb.StopChaosResponse, error) {
+	c.log.Info("stop chaos")
+	return c.controlc.StopChaos(ctx, &rpcpb.StopChaosRequest{})
+}
+
+func (c *client) GetChaosStatus(ctx context.Context) (*rpcpb.GetChaosStatusResponse, error) {
+	c.log.Info("get chaos status")
+	return c.controlc.GetChaosStatus(ctx, &rpcpb.GetChaosStatusRequest{})
+}
+
+// ListKeys returns the keys of the network, including the funded ones.
+func (c *client) ListKeys(ctx context.Context) (*rpcpb.ListKeysResponse, error) {
+	c.log.Info("list keys")
+	return c.keyc.ListKeys(ctx, &rpcpb.ListKeysRequest{})
+}
+
+// CreateKey creates a new random key named [name] in the network.
+func (c *client) CreateKey(ctx context.Context, name string) (*rpcpb.CreateKeyResponse, error) {
+	c.log.Info("create key", zap.String("name", name))
+	return c.keyc.CreateKey(ctx, &rpcpb.CreateKeyRequest{Name: name})
+}
+
+// ExportKey returns the private key named [name].
+func (c *client) ExportKey(ctx context.Context, name string) (*rpcpb.ExportKeyResponse, error) {
+	c.log.Info("export key", zap.String("name", name))
+	return c.keyc.ExportKey(ctx, &rpcpb.ExportKeyRequest{Name: name})
+}
+
+// SignMessage signs [message] with the key named [keyName].
+func (c *client) SignMessage(ctx context.Context, keyName string, message []byte) (*rpcpb.SignMessageResponse, error) {
+	c.log.Info("sign message", zap.String("key-name", keyName))
+	return c.keyc.SignMessage(ctx, &rpcpb.SignMessageRequest{KeyName: keyName, Message: message})
+}
+
+// SignTx signs the unsigned tx of [req] with the key it names.
+func (c *client) SignTx(ctx context.Context, req *rpcpb.SignTxRequest) (*rpcpb.SignTxResponse, error) {
+	c.log.Info("sign tx", zap.String("key-name", req.KeyName), zap.Uint64("evm-chain-id", req.EvmChainId))
+	return c.keyc.SignTx(ctx, req)
+}
+
 func (c *client) RestartNode(ctx context.Context, name string, opts ...OpOption) (*rpcpb.RestartNodeResponse, error) {
 	ret := &Op{}
 	ret.applyOpts(opts)
@@ -317,14 +949,21 @@ func (c *client) RestartNode(ctx context.Context, name string, opts ...OpOption)
 	req.ChainConfigs = ret.chainConfigs
 	req.UpgradeConfigs = ret.upgradeConfigs
 	req.SubnetConfigs = ret.subnetConfigs
+	req.WipeDb = ret.wipeDB
 
-	c.log.Info("restart node", zap.String("name", name))
+	c.log.Info("restart node", zap.String("name", name), zap.Bool("wipe-db", ret.wipeDB))
 	return c.controlc.RestartNode(ctx, req)
 }
 
-func (c *client) AttachPeer(ctx context.Context, nodeName string) (*rpcpb.AttachPeerResponse, error) {
+func (c *client) AttachPeer(ctx context.Context, nodeName string, opts ...OpOption) (*rpcpb.AttachPeerResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
 	c.log.Info("attaching peer", zap.String("name", nodeName))
-	return c.controlc.AttachPeer(ctx, &rpcpb.AttachPeerRequest{NodeName: nodeName})
+	return c.controlc.AttachPeer(ctx, &rpcpb.AttachPeerRequest{
+		NodeName: nodeName,
+		Behavior: ret.peerBehavior,
+	})
 }
 
 func (c *client) SendOutboundMessage(ctx context.Context, nodeName string, peerID string, op uint32, msgBody []byte) (*rpcpb.SendOutboundMessageResponse, error) {
@@ -337,9 +976,112 @@ func (c *client) SendOutboundMessage(ctx context.Context, nodeName string, peerI
 	})
 }
 
-func (c *client) SaveSnapshot(ctx context.Context, snapshotName string) (*rpcpb.SaveSnapshotResponse, error) {
+// SendPeerMessage has the server build the message given in [req], and
+// send it from the attached peer to the node.
+func (c *client) SendPeerMessage(ctx context.Context, req *rpcpb.SendPeerMessageRequest) (*rpcpb.SendPeerMessageResponse, error) {
+	c.log.Info("sending peer message", zap.String("name", req.NodeName), zap.String("peer-ID", req.PeerId))
+	return c.controlc.SendPeerMessage(ctx, req)
+}
+
+// SendAppRequest sends [appBytes] as an app request to [chainID] from the
+// attached peer, and waits up to [deadline] for the node app response.
+// Uses the server default deadline if zero.
+func (c *client) SendAppRequest(
+	ctx context.Context,
+	nodeName string,
+	peerID string,
+	chainID string,
+	appBytes []byte,
+	deadline time.Duration,
+) (*rpcpb.SendAppRequestResponse, error) {
+	c.log.Info("sending app request", zap.String("name", nodeName), zap.String("peer-ID", peerID), zap.String("chain-ID", chainID))
+	return c.controlc.SendAppRequest(ctx, &rpcpb.SendAppRequestRequest{
+		NodeName: nodeName,
+		PeerId:   peerID,
+		ChainId:  chainID,
+		AppBytes: appBytes,
+		Deadline: int64(deadline),
+	})
+}
+
+// StreamPeerMessages calls [handler] with the messages received by the
+// attached peer [peerID] until the server closes the stream, returning
+// the error that closed it, if any.
+func (c *client) StreamPeerMessages(ctx context.Context, nodeName string, peerID string, handler func(*rpcpb.StreamPeerMessagesResponse)) error {
+	c.log.Info("stream peer messages", zap.String("name", nodeName), zap.String("peer-ID", peerID))
+	stream, err := c.controlc.StreamPeerMessages(ctx, &rpcpb.StreamPeerMessagesRequest{
+		NodeName: nodeName,
+		PeerId:   peerID,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if isClientCanceled(stream.Context().Err(), err) {
+				return nil
+			}
+			return err
+		}
+		handler(resp)
+	}
+}
+
+// StreamProgress calls [handler] with the progress of the network
+// operations (start, blockchain creation, ...) until [ctx] is done or the
+// server closes the stream, returning the error that closed it, if any.
+func (c *client) StreamProgress(ctx context.Context, handler func(*rpcpb.StreamProgressResponse)) error {
+	c.log.Info("stream progress")
+	stream, err := c.controlc.StreamProgress(ctx, &rpcpb.StreamProgressRequest{})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if isClientCanceled(stream.Context().Err(), err) {
+				return nil
+			}
+			return err
+		}
+		handler(resp)
+	}
+}
+
+func (c *client) RecordPeerMessages(ctx context.Context, nodeName string, peerID string, path string) (*rpcpb.RecordPeerMessagesResponse, error) {
+	c.log.Info("recording peer messages", zap.String("name", nodeName), zap.String("peer-ID", peerID), zap.String("path", path))
+	return c.controlc.RecordPeerMessages(ctx, &rpcpb.RecordPeerMessagesRequest{
+		NodeName: nodeName,
+		PeerId:   peerID,
+		Path:     path,
+	})
+}
+
+func (c *client) ReplayPeerMessages(ctx context.Context, nodeName string, peerID string, path string, keepTiming bool) (*rpcpb.ReplayPeerMessagesResponse, error) {
+	c.log.Info("replaying peer messages", zap.String("name", nodeName), zap.String("peer-ID", peerID), zap.String("path", path))
+	return c.controlc.ReplayPeerMessages(ctx, &rpcpb.ReplayPeerMessagesRequest{
+		NodeName:   nodeName,
+		PeerId:     peerID,
+		Path:       path,
+		KeepTiming: keepTiming,
+	})
+}
+
+func (c *client) SaveSnapshot(ctx context.Context, snapshotName string, opts ...OpOption) (*rpcpb.SaveSnapshotResponse, error) {
 	c.log.Info("save snapshot", zap.String("snapshot-name", snapshotName))
-	return c.controlc.SaveSnapshot(ctx, &rpcpb.SaveSnapshotRequest{SnapshotName: snapshotName})
+	ret := &Op{}
+	ret.applyOpts(opts)
+	return c.controlc.SaveSnapshot(ctx, &rpcpb.SaveSnapshotRequest{
+		SnapshotName: snapshotName,
+		Passphrase:   ret.snapshotPassphrase,
+	})
 }
 
 func (c *client) LoadSnapshot(ctx context.Context, snapshotName string, opts ...OpOption) (*rpcpb.LoadSnapshotResponse, error) {
@@ -351,6 +1093,8 @@ func (c *client) LoadSnapshot(ctx context.Context, snapshotName string, opts ...
 		ChainConfigs:   ret.chainConfigs,
 		UpgradeConfigs: ret.upgradeConfigs,
 		SubnetConfigs:  ret.subnetConfigs,
+		Backend:        ret.backend,
+		Passphrase:     ret.snapshotPassphrase,
 	}
 	if ret.execPath != "" {
 		req.ExecPath = &ret.execPath
@@ -401,9 +1145,44 @@ type Op struct {
 	numSubnets          uint32
 	chainConfigs        map[string]string
 	upgradeConfigs      map[string]string
+	upgradeSpecs        map[string]*rpcpb.NetworkUpgradeSpec
 	subnetConfigs       map[string]string
 	reassignPortsIfUsed bool
 	dynamicPorts        bool
+	stakingSpec         *rpcpb.StakingSpec
+	networkAccess       *rpcpb.NetworkAccessSpec
+	portRange           *rpcpb.PortRange
+	backend             string
+	ttl                 time.Duration
+	reconcile           bool
+	nodeGroups          []*rpcpb.NodeGroup
+	logsShardSize       uint32
+	dirLayout           *rpcpb.DirLayout
+	seed                int64
+	networkID           uint32
+	txSigner            *rpcpb.TxSignerSpec
+	stakingKeysStorage  string
+	snapshotPassphrase  string
+	logsChain           string
+	logsFollow          bool
+	logsGrep            string
+	logsSince           time.Time
+	logsMaxMatches      uint32
+	peerBehavior        *rpcpb.PeerBehavior
+	statusFields        []rpcpb.ClusterInfoField
+	statusDelta         bool
+	wipeDB              bool
+	stateSyncMinBlocks  uint64
+	pollInterval        time.Duration
+	externalNetwork     *rpcpb.ExternalNetworkSpec
+	fork                *rpcpb.ForkSpec
+	cChainStatePath     string
+	nodeName            string
+	blockNumber         uint64
+	validateOnly        bool
+	skipRestart         bool
+	nonBeaconsFirst     bool
+	nodeStopTimeout     time.Duration
 }
 
 type OpOption func(*Op)
@@ -477,6 +1256,14 @@ func WithUpgradeConfigs(upgradeConfigs map[string]string) OpOption {
 	}
 }
 
+// Map from chain name to its high level upgrade spec, from which
+// the upgrade json contents are built.
+func WithUpgradeSpecs(upgradeSpecs map[string]*rpcpb.NetworkUpgradeSpec) OpOption {
+	return func(op *Op) {
+		op.upgradeSpecs = upgradeSpecs
+	}
+}
+
 // Map from subnet id to its configuration json contents.
 func WithSubnetConfigs(subnetConfigs map[string]string) OpOption {
 	return func(op *Op) {
@@ -509,6 +1296,309 @@ func WithDynamicPorts(dynamicPorts bool) OpOption {
 	}
 }
 
+// Staking parameters used when adding validators to the network.
+func WithStakingSpec(stakingSpec *rpcpb.StakingSpec) OpOption {
+	return func(op *Op) {
+		op.stakingSpec = stakingSpec
+	}
+}
+
+// Interfaces and addresses used by all nodes of the network.
+func WithNetworkAccess(networkAccess *rpcpb.NetworkAccessSpec) OpOption {
+	return func(op *Op) {
+		op.networkAccess = networkAccess
+	}
+}
+
+// Existing network joined by the nodes, instead of starting a new one.
+func WithExternalNetwork(externalNetwork *rpcpb.ExternalNetworkSpec) OpOption {
+	return func(op *Op) {
+		op.externalNetwork = externalNetwork
+	}
+}
+
+// Remote C-Chain state copied into the genesis of the started network.
+func WithFork(fork *rpcpb.ForkSpec) OpOption {
+	return func(op *Op) {
+		op.fork = fork
+	}
+}
+
+// File of C-Chain accounts (a C-Chain genesis with alloc, or a state dump)
+// added to the genesis of the started network.
+func WithCChainStatePath(path string) OpOption {
+	return func(op *Op) {
+		op.cChainStatePath = path
+	}
+}
+
+// WithValidateOnly makes Start and CreateBlockchains only check the request,
+// returning all the problems found, without launching anything.
+func WithValidateOnly(validateOnly bool) OpOption {
+	return func(op *Op) {
+		op.validateOnly = validateOnly
+	}
+}
+
+// WithSkipRestart makes RemoveSubnetValidator not restart the removed
+// validators, so they keep tracking the subnets until restarted.
+func WithSkipRestart(skipRestart bool) OpOption {
+	return func(op *Op) {
+		op.skipRestart = skipRestart
+	}
+}
+
+// Stops the non beacon nodes before the beacon ones.
+func WithNonBeaconsFirst(nonBeaconsFirst bool) OpOption {
+	return func(op *Op) {
+		op.nonBeaconsFirst = nonBeaconsFirst
+	}
+}
+
+// Time given to each node to exit on stop, before it is killed.
+func WithNodeStopTimeout(nodeStopTimeout time.Duration) OpOption {
+	return func(op *Op) {
+		op.nodeStopTimeout = nodeStopTimeout
+	}
+}
+
+// Range of the ports assigned to nodes, when not given in their configs.
+func WithPortRange(minPort uint16, maxPort uint16) OpOption {
+	return func(op *Op) {
+		op.portRange = &rpcpb.PortRange{
+			Min: uint32(minPort),
+			Max: uint32(maxPort),
+		}
+	}
+}
+
+// WithBackend selects the registered backend creating the network.
+func WithBackend(backend string) OpOption {
+	return func(op *Op) {
+		op.backend = backend
+	}
+}
+
+// WithTTL stops the network once [ttl] elapses after it starts.
+func WithTTL(ttl time.Duration) OpOption {
+	return func(op *Op) {
+		op.ttl = ttl
+	}
+}
+
+// WithReconcile makes Start update a running network to match
+// the request, instead of failing if it doesn't.
+func WithReconcile(reconcile bool) OpOption {
+	return func(op *Op) {
+		op.reconcile = reconcile
+	}
+}
+
+// WithNodeGroups adds groups of nodes sharing a config to the network.
+func WithNodeGroups(nodeGroups []*rpcpb.NodeGroup) OpOption {
+	return func(op *Op) {
+		op.nodeGroups = nodeGroups
+	}
+}
+
+// WithLogsShardSize groups the node logs in shard dirs of
+// [logsShardSize] nodes.
+func WithLogsShardSize(logsShardSize uint32) OpOption {
+	return func(op *Op) {
+		op.logsShardSize = logsShardSize
+	}
+}
+
+// WithDirLayout places the node dirs as given by the templates of
+// [dirLayout], instead of under the root data dir.
+func WithDirLayout(dirLayout *rpcpb.DirLayout) OpOption {
+	return func(op *Op) {
+		op.dirLayout = dirLayout
+	}
+}
+
+// WithSeed derives the generated node keys, the ports and the default
+// genesis start time from [seed], so runs with the same options get the
+// same genesis and node IDs.
+func WithSeed(seed int64) OpOption {
+	return func(op *Op) {
+		op.seed = seed
+	}
+}
+
+// WithNetworkID sets the ID of the new network in its genesis, with the
+// addresses formatted with its HRP.
+func WithNetworkID(networkID uint32) OpOption {
+	return func(op *Op) {
+		op.networkID = networkID
+	}
+}
+
+// WithTxSigner signs the txs issued by the server with the external
+// signer of [txSigner], instead of the funded key.
+func WithTxSigner(txSigner *rpcpb.TxSignerSpec) OpOption {
+	return func(op *Op) {
+		op.txSigner = txSigner
+	}
+}
+
+// WithStakingKeysStorage sets how the staking keys are given to the nodes,
+// one of the network.StakingKeysStorage values, e.g. to keep them out of
+// plaintext files on shared machines.
+func WithStakingKeysStorage(stakingKeysStorage string) OpOption {
+	return func(op *Op) {
+		op.stakingKeysStorage = stakingKeysStorage
+	}
+}
+
+// WithSnapshotPassphrase encrypts the saved snapshot with [passphrase], or
+// decrypts the loaded one with it.
+func WithSnapshotPassphrase(passphrase string) OpOption {
+	return func(op *Op) {
+		op.snapshotPassphrase = passphrase
+	}
+}
+
+// WithLogsChain streams the log of the given chain ID or alias,
+// instead of the node main log.
+func WithLogsChain(chain string) OpOption {
+	return func(op *Op) {
+		op.logsChain = chain
+	}
+}
+
+func WithLogsFollow(follow bool) OpOption {
+	return func(op *Op) {
+		op.logsFollow = follow
+	}
+}
+
+func WithLogsGrep(grep string) OpOption {
+	return func(op *Op) {
+		op.logsGrep = grep
+	}
+}
+
+func WithLogsSince(since time.Time) OpOption {
+	return func(op *Op) {
+		op.logsSince = since
+	}
+}
+
+// WithLogsMaxMatches limits the number of lines returned by a log search.
+func WithLogsMaxMatches(maxMatches uint32) OpOption {
+	return func(op *Op) {
+		op.logsMaxMatches = maxMatches
+	}
+}
+
+// WithPeerBehavior makes the attached peer misbehave as described
+// by [behavior] when sending messages.
+func WithPeerBehavior(behavior *rpcpb.PeerBehavior) OpOption {
+	return func(op *Op) {
+		op.peerBehavior = behavior
+	}
+}
+
+// WithStatusFields sets the parts of the cluster info sent by
+// StreamStatus. All are sent by default.
+func WithStatusFields(fields ...rpcpb.ClusterInfoField) OpOption {
+	return func(op *Op) {
+		op.statusFields = fields
+	}
+}
+
+// WithStatusDelta makes StreamStatus only send the cluster info
+// when it changed.
+func WithStatusDelta(delta bool) OpOption {
+	return func(op *Op) {
+		op.statusDelta = delta
+	}
+}
+
+// WithWipeDB makes RestartNode remove the node db before restarting,
+// so the node bootstraps again from its peers.
+func WithWipeDB(wipeDB bool) OpOption {
+	return func(op *Op) {
+		op.wipeDB = wipeDB
+	}
+}
+
+// WithStateSyncMinBlocks sets the C-Chain blocks behind the network tip
+// needed by StateSyncNode to state sync instead of bootstrapping.
+func WithStateSyncMinBlocks(minBlocks uint64) OpOption {
+	return func(op *Op) {
+		op.stateSyncMinBlocks = minBlocks
+	}
+}
+
+// WithPollInterval sets the interval between the progress checks
+// of StateSyncNode.
+func WithPollInterval(interval time.Duration) OpOption {
+	return func(op *Op) {
+		op.pollInterval = interval
+	}
+}
+
+// WithNodeName sets the node whose C-Chain state is exported by
+// ExportCChainState, instead of the first running node.
+func WithNodeName(nodeName string) OpOption {
+	return func(op *Op) {
+		op.nodeName = nodeName
+	}
+}
+
+// WithBlockNumber sets the height the C-Chain state is exported at by
+// ExportCChainState, instead of the latest block.
+func WithBlockNumber(blockNumber uint64) OpOption {
+	return func(op *Op) {
+		op.blockNumber = blockNumber
+	}
+}
+
+// Adds [networkName] to the metadata of unary requests, so the server
+// can check they target the expected network.
+func networkNameUnaryInterceptor(networkName string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withNetworkName(ctx, networkName), method, req, reply, cc, opts...)
+	}
+}
+
+// Adds [networkName] to the metadata of streaming requests.
+func networkNameStreamInterceptor(networkName string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withNetworkName(ctx, networkName), desc, cc, method, opts...)
+	}
+}
+
+func withNetworkName(ctx context.Context, networkName string) context.Context {
+	if networkName == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, constants.NetworkNameMetadataKey, networkName)
+}
+
+// Adds the API [token] to the metadata of unary requests.
+func tokenUnaryInterceptor(token string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withToken(ctx, token), method, req, reply, cc, opts...)
+	}
+}
+
+// Adds the API [token] to the metadata of streaming requests.
+func tokenStreamInterceptor(token string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withToken(ctx, token), desc, cc, method, opts...)
+	}
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	if token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, constants.AuthorizationMetadataKey, "Bearer "+token)
+}
+
 func isClientCanceled(ctxErr error, err error) bool {
 	if ctxErr != nil {
 		return true
@@ -540,3 +1630,18 @@ func isClientCanceled(ctxErr error, err error) bool {
 	}
 	return false
 }
+
+// GetErrorCode returns the error code attached by the server to [err],
+// or ErrorCode_ERROR_CODE_UNSPECIFIED if there is none.
+func GetErrorCode(err error) rpcpb.ErrorCode {
+	st, ok := status.FromError(err)
+	if !ok {
+		return rpcpb.ErrorCode_ERROR_CODE_UNSPECIFIED
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*rpcpb.ErrorInfo); ok {
+			return info.Code
+		}
+	}
+	return rpcpb.ErrorCode_ERROR_CODE_UNSPECIFIED
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/luxdefi/node/utils/logging"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// max delay between reconnection attempts, if not given
+	defaultReconnectMaxBackoff = 5 * time.Second
+	// delay before the first retry of a call, if not given
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	// max delay between retries of a call, if not given
+	defaultRetryMaxBackoff = 5 * time.Second
+	// min time given to each connection attempt, if no dial timeout is given
+	defaultMinConnectTimeout = 20 * time.Second
+)
+
+// Unary methods that can be safely retried, as they don't change
+// the server state.
+var idempotentMethods = []string{
+	"/rpcpb.PingService/",
+	"/rpcpb.ControlService/RPCVersion",
+	"/rpcpb.ControlService/ListBlockchains",
+	"/rpcpb.ControlService/GetBlockchainStatus",
+	"/rpcpb.ControlService/GetUptimes",
+	"/rpcpb.ControlService/GetVersions",
+	"/rpcpb.ControlService/GetOperationHistory",
+	"/rpcpb.ControlService/Health",
+	"/rpcpb.ControlService/URIs",
+	"/rpcpb.ControlService/WaitForHealthy",
+	"/rpcpb.ControlService/Status",
+	"/rpcpb.ControlService/GetNodeInfo",
+	"/rpcpb.ControlService/ListNodes",
+	"/rpcpb.ControlService/ListCustomChains",
+	"/rpcpb.ControlService/GetSnapshotNames",
+}
+
+// RetryPolicy configures how the calls to idempotent methods are
+// retried when the server is unavailable.
+type RetryPolicy struct {
+	// max number of attempts of each call. 0 or 1 disables retries
+	MaxAttempts uint32
+	// delay before the first retry, doubled for each next one
+	InitialBackoff time.Duration
+	// max delay between retries
+	MaxBackoff time.Duration
+}
+
+type retryPolicyKey struct{}
+
+// WithRetryPolicy returns a context for the calls that should be retried
+// following [policy] instead of the one of the client config.
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, policy)
+}
+
+// Returns the retry policy of the call with [ctx].
+func getRetryPolicy(ctx context.Context, policy RetryPolicy) RetryPolicy {
+	if p, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy); ok {
+		policy = p
+	}
+	if policy.InitialBackoff == 0 {
+		policy.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff == 0 {
+		policy.MaxBackoff = defaultRetryMaxBackoff
+	}
+	return policy
+}
+
+// Retries the calls to idempotent methods failing because the server
+// is unavailable, as configured by [policy].
+func retryUnaryInterceptor(policy RetryPolicy, log logging.Logger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		callPolicy := getRetryPolicy(ctx, policy)
+		if callPolicy.MaxAttempts <= 1 || !isIdempotent(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		delay := callPolicy.InitialBackoff
+		for attempt := uint32(1); ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= callPolicy.MaxAttempts || !isRetryable(ctx, err) {
+				return err
+			}
+			log.Warn("server unavailable, retrying call",
+				zap.String("method", method),
+				zap.Uint32("attempt", attempt),
+				zap.Duration("delay", delay),
+				zap.Error(err),
+			)
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+			delay *= 2
+			if delay > callPolicy.MaxBackoff {
+				delay = callPolicy.MaxBackoff
+			}
+		}
+	}
+}
+
+func isIdempotent(method string) bool {
+	for _, m := range idempotentMethods {
+		if strings.HasPrefix(method, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the call failed with [err] because the server was
+// unavailable, and not because it was canceled by the client.
+func isRetryable(ctx context.Context, err error) bool {
+	if isClientCanceled(ctx.Err(), err) {
+		return false
+	}
+	return status.Code(err) == codes.Unavailable
+}
+
+// Returns the dial option reconnecting to the server with an
+// exponential backoff, up to [maxDelay] between attempts.
+func withReconnectBackoff(maxDelay time.Duration, minConnectTimeout time.Duration) grpc.DialOption {
+	if maxDelay == 0 {
+		maxDelay = defaultReconnectMaxBackoff
+	}
+	backoffConfig := backoff.DefaultConfig
+	backoffConfig.MaxDelay = maxDelay
+	if backoffConfig.BaseDelay > maxDelay {
+		backoffConfig.BaseDelay = maxDelay
+	}
+	if minConnectTimeout == 0 {
+		minConnectTimeout = defaultMinConnectTimeout
+	}
+	return grpc.WithConnectParams(grpc.ConnectParams{
+		Backoff:           backoffConfig,
+		MinConnectTimeout: minConnectTimeout,
+	})
+}
+
+// Watches the connection to the server until the client is closed,
+// calling [c.cfg.OnDisconnect] each time it is lost. gRPC reconnects
+// in the background.
+func (c *client) monitorConnection() {
+	state := c.conn.GetState()
+	for c.conn.WaitForStateChange(context.Background(), state) {
+		newState := c.conn.GetState()
+		switch {
+		case newState == connectivity.Shutdown:
+			return
+		case state == connectivity.Ready && newState != connectivity.Ready:
+			c.log.Warn("disconnected from server", zap.String("state", newState.String()))
+			if c.cfg.OnDisconnect != nil {
+				c.cfg.OnDisconnect()
+			}
+		case state != connectivity.Ready && newState == connectivity.Ready:
+			c.log.Info("connected to server")
+		}
+		state = newState
+	}
+}
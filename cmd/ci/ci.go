@@ -0,0 +1,473 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ci
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxdefi/netrunner/client"
+	"github.com/luxdefi/netrunner/scenario"
+	"github.com/luxdefi/netrunner/utils/constants"
+	"github.com/luxdefi/netrunner/ux"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/luxdefi/node/utils/set"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	serverPidFname   = "server.pid"
+	reaperPidFname   = "reaper.pid"
+	serverOutFname   = "server.out"
+	reaperOutFname   = "reaper.out"
+	manifestFname    = "manifest.json"
+	serverLogsSubdir = "logs"
+
+	// env var the GitHub Actions runner marks the job processes with,
+	// killing the ones left once the job ends or is cancelled
+	runnerTrackingIDEnvVar = "RUNNER_TRACKING_ID"
+	githubOutputEnvVar     = "GITHUB_OUTPUT"
+	githubEnvEnvVar        = "GITHUB_ENV"
+
+	// time between the polls of the node PIDs by the reaper
+	reaperPollInterval = 2 * time.Second
+	// time the server is given to stop the network on teardown
+	serverStopTimeout = 2 * time.Minute
+	// time the reaper is given to write its PID file, if torn down
+	// right after being started
+	reaperStartTimeout = 5 * time.Second
+)
+
+var errScenarioFailed = errors.New("scenario failed")
+
+var (
+	logLevel       string
+	stateDir       string
+	port           string
+	gwPort         string
+	serverTimeout  time.Duration
+	healthyTimeout time.Duration
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ci [options]",
+		Short: "Runs an ephemeral network for a CI job (e.g. GitHub Actions).",
+	}
+
+	cmd.PersistentFlags().StringVar(&logLevel, "log-level", logging.Info.String(), "log level")
+	cmd.PersistentFlags().StringVar(&stateDir, "state-dir", getDefaultStateDir(), "dir of the server PID, logs and manifest, shared by up and down")
+
+	cmd.AddCommand(newUpCommand(), newDownCommand(), newReapCommand())
+	return cmd
+}
+
+// Returns a dir under the runner temp dir on GitHub Actions, which is
+// cleaned up after each job.
+func getDefaultStateDir() string {
+	tmpDir := os.Getenv("RUNNER_TEMP")
+	if tmpDir == "" {
+		tmpDir = os.TempDir()
+	}
+	return filepath.Join(tmpDir, constants.RootDirPrefix, "ci")
+}
+
+func newUpCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "up scenario-file [options]",
+		Short: "Starts a server in the background, runs a scenario starting the network, and waits for it to be healthy.",
+		Long: `Starts a server in the background, runs a scenario starting the network, and waits for it to be healthy.
+The endpoints of the network are written to the GITHUB_OUTPUT and GITHUB_ENV files, if set.
+A reaper process is left running, which tears down the network when 'ci down' is called,
+or when the server is killed, e.g. when the job is cancelled.`,
+		RunE: upFunc,
+		Args: cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().StringVar(&port, "port", ":8080", "server port")
+	cmd.PersistentFlags().StringVar(&gwPort, "grpc-gateway-port", ":8081", "grpc-gateway server port")
+	cmd.PersistentFlags().DurationVar(&serverTimeout, "server-timeout", 30*time.Second, "timeout for the server to accept connections")
+	cmd.PersistentFlags().DurationVar(&healthyTimeout, "healthy-timeout", 5*time.Minute, "timeout for the network to be healthy after the scenario")
+	return cmd
+}
+
+func newDownCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "down [options]",
+		Short: "Stops the network and the server started by 'ci up'.",
+		RunE:  downFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+}
+
+func newReapCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "reap [options]",
+		Short:  "Tears down the network once signaled or once the server exits. Started by 'ci up'.",
+		RunE:   reapFunc,
+		Args:   cobra.ExactArgs(0),
+		Hidden: true,
+	}
+	cmd.PersistentFlags().StringVar(&port, "port", ":8080", "server port")
+	return cmd
+}
+
+func newLogger() (logging.Logger, error) {
+	lvl, err := logging.ToLevel(logLevel)
+	if err != nil {
+		return nil, err
+	}
+	logFactory := logging.NewFactory(logging.Config{
+		DisplayLevel: lvl,
+		LogLevel:     logging.Off,
+	})
+	return logFactory.Make(constants.LogNameControl)
+}
+
+// If [lazyConnect], returns without waiting for the server to accept
+// connections.
+func newClient(log logging.Logger, dialTimeout time.Duration, lazyConnect bool) (client.Client, error) {
+	return client.New(client.Config{
+		Endpoint:    "0.0.0.0" + port,
+		DialTimeout: dialTimeout,
+		LazyConnect: lazyConnect,
+	}, log)
+}
+
+func upFunc(_ *cobra.Command, args []string) error {
+	sc, err := scenario.Load(args[0])
+	if err != nil {
+		return err
+	}
+	log, err := newLogger()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir, 0o750); err != nil {
+		return err
+	}
+
+	serverPid, err := startBackground(
+		filepath.Join(stateDir, serverOutFname),
+		// the server keeps the job tracking env var, so it is killed by
+		// the runner if the job ends without calling 'ci down'
+		false,
+		"server",
+		"--log-level", logLevel,
+		"--port", port,
+		"--grpc-gateway-port", gwPort,
+		"--log-dir", filepath.Join(stateDir, serverLogsSubdir),
+		"--pid-file", filepath.Join(stateDir, serverPidFname),
+		"--manifest-path", filepath.Join(stateDir, manifestFname),
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't start server: %w", err)
+	}
+	ux.Print(log, logging.Green.Wrap("started server with pid %d"), serverPid)
+
+	// the reaper must outlive the job processes to clean up after them
+	reaperPid, err := startBackground(
+		filepath.Join(stateDir, reaperOutFname),
+		true,
+		"ci", "reap",
+		"--log-level", logLevel,
+		"--state-dir", stateDir,
+		"--port", port,
+	)
+	if err != nil {
+		_ = syscall.Kill(serverPid, syscall.SIGTERM)
+		return fmt.Errorf("couldn't start reaper: %w", err)
+	}
+	ux.Print(log, logging.Green.Wrap("started reaper with pid %d"), reaperPid)
+
+	if err := up(log, sc); err != nil {
+		ux.Print(log, logging.Red.Wrap("tearing down the network: %s"), err)
+		if downErr := down(log); downErr != nil {
+			log.Warn("couldn't tear down the network", zap.Error(downErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// Runs [sc] against the server, waits for the network to be healthy and
+// writes its endpoints.
+func up(log logging.Logger, sc *scenario.Scenario) error {
+	cli, err := newClient(log, serverTimeout, false)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	report := scenario.Run(ctx, cli, log, sc)
+	for _, step := range report.Steps {
+		if step.Err != nil {
+			ux.Print(log, logging.Red.Wrap("  FAIL  %s: %s"), step.Name, step.Err)
+			return errScenarioFailed
+		}
+		ux.Print(log, logging.Green.Wrap("  PASS  %s (%s)"), step.Name, step.Duration.Round(time.Millisecond))
+	}
+
+	healthyCtx, healthyCancel := context.WithTimeout(ctx, healthyTimeout)
+	_, err = cli.WaitForHealthy(healthyCtx)
+	healthyCancel()
+	if err != nil {
+		return err
+	}
+
+	statusCtx, statusCancel := context.WithTimeout(ctx, 30*time.Second)
+	resp, err := cli.Status(statusCtx)
+	statusCancel()
+	if err != nil {
+		return err
+	}
+	clusterInfo := resp.GetClusterInfo()
+
+	nodeNames := append([]string{}, clusterInfo.GetNodeNames()...)
+	sort.Strings(nodeNames)
+	uris := []string{}
+	for _, nodeName := range nodeNames {
+		uris = append(uris, clusterInfo.GetNodeInfos()[nodeName].GetUri())
+	}
+	chainIDs := []string{}
+	for chainID := range clusterInfo.GetCustomChains() {
+		chainIDs = append(chainIDs, chainID)
+	}
+	sort.Strings(chainIDs)
+
+	outputs := [][2]string{
+		{"endpoint", "0.0.0.0" + port},
+		{"gateway-endpoint", "http://127.0.0.1" + gwPort},
+		{"uris", strings.Join(uris, ",")},
+		{"blockchain-ids", strings.Join(chainIDs, ",")},
+		{"root-data-dir", clusterInfo.GetRootDataDir()},
+		{"manifest", filepath.Join(stateDir, manifestFname)},
+	}
+	for _, output := range outputs {
+		ux.Print(log, logging.Blue.Wrap("%s=%s"), output[0], output[1])
+	}
+	if err := appendKeyValues(os.Getenv(githubOutputEnvVar), outputs, ""); err != nil {
+		return err
+	}
+	return appendKeyValues(os.Getenv(githubEnvEnvVar), outputs, "NETRUNNER_")
+}
+
+// Appends [keyValues] to the GitHub Actions file at [path], with the keys
+// upper cased and prefixed with [envPrefix] if given. Does nothing if
+// [path] is empty, as when not run by GitHub Actions.
+func appendKeyValues(path string, keyValues [][2]string, envPrefix string) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	for _, kv := range keyValues {
+		key := kv[0]
+		if envPrefix != "" {
+			key = envPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		}
+		if _, err := fmt.Fprintf(f, "%s=%s\n", key, kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Starts this binary with [args] in its own session, so it isn't stopped
+// with the calling step, writing its output to [outPath]. If [untracked],
+// the process isn't marked as a process of the job. Returns its PID.
+func startBackground(outPath string, untracked bool, args ...string) (int, error) {
+	execPath, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	out, err := os.OpenFile(outPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	cmd := exec.Command(execPath, args...) //nolint
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if untracked {
+		for _, env := range os.Environ() {
+			if !strings.HasPrefix(env, runnerTrackingIDEnvVar+"=") {
+				cmd.Env = append(cmd.Env, env)
+			}
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	pid := cmd.Process.Pid
+	return pid, cmd.Process.Release()
+}
+
+func downFunc(*cobra.Command, []string) error {
+	log, err := newLogger()
+	if err != nil {
+		return err
+	}
+	return down(log)
+}
+
+// Signals the reaper to tear down the network, and waits for it to finish.
+func down(log logging.Logger) error {
+	reaperPid, err := waitForPid(filepath.Join(stateDir, reaperPidFname), reaperStartTimeout)
+	if err != nil {
+		return fmt.Errorf("couldn't read reaper pid, was 'ci up' called?: %w", err)
+	}
+	if err := syscall.Kill(reaperPid, syscall.SIGTERM); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			ux.Print(log, logging.Yellow.Wrap("reaper already exited"))
+			return nil
+		}
+		return err
+	}
+	if !waitForExit(reaperPid, serverStopTimeout+30*time.Second) {
+		return fmt.Errorf("reaper with pid %d didn't exit", reaperPid)
+	}
+	ux.Print(log, logging.Green.Wrap("network torn down"))
+	return nil
+}
+
+// Waits for a signal or for the server to exit, then stops the server
+// if needed, and kills the node processes left.
+func reapFunc(*cobra.Command, []string) error {
+	log, err := newLogger()
+	if err != nil {
+		return err
+	}
+	reaperPidPath := filepath.Join(stateDir, reaperPidFname)
+	if err := os.WriteFile(reaperPidPath, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644); err != nil {
+		return err
+	}
+	defer os.Remove(reaperPidPath)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+	serverPidPath := filepath.Join(stateDir, serverPidFname)
+	var serverPid int
+	// PIDs of the nodes seen running, killed if left by the server
+	nodePids := set.Set[int]{}
+	cli, err := newClient(log, reaperPollInterval, true)
+	if err != nil {
+		log.Warn("couldn't connect to the server", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(reaperPollInterval)
+	defer ticker.Stop()
+	for {
+		if serverPid == 0 {
+			serverPid, _ = readPid(serverPidPath)
+		}
+		if serverPid != 0 && !isRunning(serverPid) {
+			log.Warn("server exited", zap.Int("pid", serverPid))
+			break
+		}
+		if cli != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), reaperPollInterval)
+			resp, err := cli.Status(ctx)
+			cancel()
+			if err == nil {
+				for _, nodeInfo := range resp.GetClusterInfo().GetNodeInfos() {
+					if nodeInfo.GetPid() != 0 {
+						nodePids.Add(int(nodeInfo.GetPid()))
+					}
+				}
+			}
+		}
+
+		select {
+		case sig := <-sigCh:
+			log.Info("signal received: stopping server", zap.String("signal", sig.String()))
+			if serverPid != 0 {
+				// the server stops the network on SIGTERM
+				_ = syscall.Kill(serverPid, syscall.SIGTERM)
+				if !waitForExit(serverPid, serverStopTimeout) {
+					log.Warn("server didn't stop, killing it", zap.Int("pid", serverPid))
+					_ = syscall.Kill(serverPid, syscall.SIGKILL)
+				}
+			}
+			killNodes(log, nodePids)
+			return nil
+		case <-ticker.C:
+		}
+	}
+	if cli != nil {
+		_ = cli.Close()
+	}
+	killNodes(log, nodePids)
+	return nil
+}
+
+// Kills the process groups of the nodes in [nodePids] still running.
+func killNodes(log logging.Logger, nodePids set.Set[int]) {
+	for pid := range nodePids {
+		if !isRunning(pid) {
+			continue
+		}
+		log.Warn("killing node left running", zap.Int("pid", pid))
+		// nodes lead their own process groups, with their plugins
+		if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+			log.Warn("couldn't kill node", zap.Int("pid", pid), zap.Error(err))
+		}
+	}
+}
+
+func readPid(path string) (int, error) {
+	pidBytes, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(pidBytes)))
+}
+
+// Returns the PID written to [path], waiting up to [timeout] for it.
+func waitForPid(path string, timeout time.Duration) (int, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		pid, err := readPid(path)
+		if err == nil || time.Now().After(deadline) {
+			return pid, err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// Returns true if the process [pid] exists. Signal 0 only checks it.
+func isRunning(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
+
+// Returns true once [pid] exits, or false after [timeout].
+func waitForExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !isRunning(pid) {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
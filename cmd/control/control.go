@@ -6,11 +6,15 @@ package control
 import (
 	"context"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -32,13 +36,19 @@ func init() {
 const clientRootDirPrefix = "client"
 
 var (
-	logLevel       string
-	logDir         string
-	trackSubnets   string
-	endpoint       string
-	dialTimeout    time.Duration
-	requestTimeout time.Duration
-	log            logging.Logger
+	logLevel           string
+	logFormat          string
+	logDir             string
+	trackSubnets       string
+	endpoint           string
+	grpcSocket         string
+	networkName        string
+	dialTimeout        time.Duration
+	requestTimeout     time.Duration
+	strictVersionCheck bool
+	apiToken           string
+	maxAttempts        uint32
+	log                logging.Logger
 )
 
 // NOTE: Naming convention for node names is currently `node` + number, i.e. `node1,node2,node3,...node101`
@@ -50,10 +60,17 @@ func NewCommand() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVar(&logLevel, "log-level", logging.Info.String(), "log level")
+	cmd.PersistentFlags().StringVar(&logFormat, "log-format", "auto", "log format: auto, plain, colors or json")
 	cmd.PersistentFlags().StringVar(&logDir, "log-dir", "", "log directory")
 	cmd.PersistentFlags().StringVar(&endpoint, "endpoint", "0.0.0.0:8080", "server endpoint")
+	cmd.PersistentFlags().StringVar(&grpcSocket, "grpc-socket", "", "server unix domain socket path (overrides --endpoint)")
+	cmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "output format of the command responses (text, json)")
+	cmd.PersistentFlags().StringVar(&networkName, "network-name", "", "name of the network targeted by the command (default \"default\")")
 	cmd.PersistentFlags().DurationVar(&dialTimeout, "dial-timeout", 10*time.Second, "server dial timeout")
 	cmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 3*time.Minute, "client request timeout")
+	cmd.PersistentFlags().BoolVar(&strictVersionCheck, "strict-version-check", false, "fail instead of warning if the server RPC version is not compatible")
+	cmd.PersistentFlags().StringVar(&apiToken, "token", os.Getenv(constants.APITokenEnvVar), "API token sent to the server, if it requires one (defaults to $"+constants.APITokenEnvVar+")")
+	cmd.PersistentFlags().Uint32Var(&maxAttempts, "max-attempts", 3, "max attempts of the read-only requests while the server is unavailable")
 
 	cmd.AddCommand(
 		newRPCVersionCommand(),
@@ -63,18 +80,62 @@ func NewCommand() *cobra.Command {
 		newTransformElasticSubnetsCommand(),
 		newAddPermissionlessValidatorCommand(),
 		newRemoveSubnetValidatorCommand(),
+		newTransferSubnetOwnershipCommand(),
+		newCreateAssetCommand(),
+		newTransferCommand(),
+		newStakeCommand(),
+		newRenewPrimaryValidationsCommand(),
+		newWaitForValidatorRewardsCommand(),
+		newGetUptimesCommand(),
+		newGetElasticSubnetCommand(),
+		newGetStakingRewardsCommand(),
+		newGetVersionsCommand(),
+		newGetTopologyCommand(),
+		newExportCommand(),
+		newCheckConnectivityCommand(),
+		newGetOperationHistoryCommand(),
+		newListBlockchainsCommand(),
+		newListNodesCommand(),
+		newListCustomChainsCommand(),
+		newGetBlockchainStatusCommand(),
+		newAddBlockchainAliasCommand(),
+		newRemoveBlockchainAliasCommand(),
+		newAddVMAliasCommand(),
 		newHealthCommand(),
 		newWaitForHealthyCommand(),
+		newWaitCommand(),
+		newAssertCommand(),
 		newURIsCommand(),
 		newStatusCommand(),
+		newGetNodeInfoCommand(),
 		newStreamStatusCommand(),
+		newLogsCommand(),
+		newSearchLogsCommand(),
 		newAddNodeCommand(),
 		newRemoveNodeCommand(),
+		newDrainNodeCommand(),
+		newScaleNetworkCommand(),
+		newResetNetworkCommand(),
+		newStateSyncNodeCommand(),
+		newExportCChainStateCommand(),
+		newDBSizesCommand(),
+		newPruneNodeDBCommand(),
 		newPauseNodeCommand(),
 		newResumeNodeCommand(),
+		newPauseAllCommand(),
+		newResumeAllCommand(),
+		newSimulateDowntimeCommand(),
+		newChaosCommand(),
+		newKeysCommand(),
 		newRestartNodeCommand(),
 		newAttachPeerCommand(),
 		newSendOutboundMessageCommand(),
+		newSendPeerMessageCommand(),
+		newSendAppRequestCommand(),
+		newStreamPeerMessagesCommand(),
+		newStreamProgressCommand(),
+		newRecordPeerMessagesCommand(),
+		newReplayPeerMessagesCommand(),
 		newStopCommand(),
 		newSaveSnapshotCommand(),
 		newLoadSnapshotCommand(),
@@ -93,15 +154,66 @@ var (
 	addNodeConfig       string
 	blockchainSpecsStr  string
 	customNodeConfigs   string
+	nodeGroups          string
 	rootDataDir         string
 	chainConfigs        string
 	upgradeConfigs      string
 	subnetConfigs       string
 	reassignPortsIfUsed bool
 	dynamicPorts        bool
+	stakingSpecStr      string
+	httpHost            string
+	publicIP            string
+	externalAccess      bool
+	minPort             uint16
+	maxPort             uint16
+	backendName         string
+	networkTTL          time.Duration
+	reconcile           bool
+	logsShardSize       uint32
+	dataDirTemplate     string
+	dbDirTemplate       string
+	logsDirTemplate     string
+	networkSeed         int64
+	networkID           uint32
+	txSignerURL         string
+	txSignerAddress     string
+	stakingKeysStorage  string
+	snapshotPassphrase  string
+	rewardsSubnetID     string
+	rewardsTimeout      time.Duration
+	uptimesSubnetID     string
+	stakesSubnetID      string
+	downtimeDuration    time.Duration
+	stakeAmount         uint64
+	stakeDelegate       bool
+	stakeDuration       time.Duration
+	stakeRewardAddress  string
+	renewAmount         uint64
+	renewDuration       time.Duration
+)
+
+var (
+	externalNetworkStr string
+	forkStr            string
+	cChainStatePath    string
+)
+
+// only check the start or create-blockchains request
+var validateOnly bool
+
+// don't restart the removed subnet validators
+var skipRestart bool
+
+var (
+	nonBeaconsFirst bool
+	nodeStopTimeout time.Duration
 )
 
 func setLogs() error {
+	if err := checkOutputFormat(); err != nil {
+		return err
+	}
 	var err error
 	if logDir == "" {
 		anrRootDir := filepath.Join(os.TempDir(), constants.RootDirPrefix)
@@ -119,11 +231,21 @@ func setLogs() error {
 	if err != nil {
 		return err
 	}
+	format, err := logging.ToFormat(logFormat, os.Stdout.Fd())
+	if err != nil {
+		return err
+	}
+	displayLvl := lvl
+	if outputFormat == outputJSON {
+		// keep stdout parseable, logs are still written to [logDir]
+		displayLvl = logging.Off
+	}
 	logFactory := logging.NewFactory(logging.Config{
 		RotatingWriterConfig: logging.RotatingWriterConfig{
 			Directory: logDir,
 		},
-		DisplayLevel: lvl,
+		LogFormat:    format,
+		DisplayLevel: displayLvl,
 		LogLevel:     lvl,
 	})
 	log, err = logFactory.Make(constants.LogNameControl)
@@ -154,8 +276,7 @@ func RPCVersionFunc(*cobra.Command, []string) error {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("version response: %+v"), resp)
-	return nil
+	return printResponse("version response: %+v", resp)
 }
 
 func newStartCommand() *cobra.Command {
@@ -207,6 +328,12 @@ func newStartCommand() *cobra.Command {
 		"",
 		"[optional] custom node configs as JSON string of map, for each node individually. Common entries override `global-node-config`, but can be combined. Invalidates `number-of-nodes` (provide all node configs if used).",
 	)
+	cmd.PersistentFlags().StringVar(
+		&nodeGroups,
+		"node-groups",
+		"",
+		"[optional] JSON string of array of node groups [(name, count, node config)], each expanded into count custom node configs. Can be combined with `custom-node-configs`. Invalidates `number-of-nodes`.",
+	)
 	cmd.PersistentFlags().StringVar(
 		&trackSubnets,
 		"whitelisted-subnets",
@@ -243,6 +370,138 @@ func newStartCommand() *cobra.Command {
 		false,
 		"true to assign dynamic ports",
 	)
+	cmd.PersistentFlags().StringVar(
+		&stakingSpecStr,
+		"staking-spec",
+		"",
+		"[optional] JSON string of staking parameters (validation start offset and duration in seconds, subnet validators weight)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&httpHost,
+		"http-host",
+		"",
+		"[optional] interface the node APIs are bound to, applied to all nodes",
+	)
+	cmd.PersistentFlags().StringVar(
+		&publicIP,
+		"public-ip",
+		"",
+		"[optional] IP the nodes advertise to their peers, applied to all nodes",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&externalAccess,
+		"external-access",
+		false,
+		"true to bind the node APIs on all interfaces, so the network is reachable from other hosts",
+	)
+	cmd.PersistentFlags().Uint16Var(
+		&minPort,
+		"min-port",
+		0,
+		"[optional] lower bound of the ports assigned to nodes, when not given in their configs",
+	)
+	cmd.PersistentFlags().Uint16Var(
+		&maxPort,
+		"max-port",
+		0,
+		"[optional] upper bound of the ports assigned to nodes, when not given in their configs",
+	)
+	cmd.PersistentFlags().StringVar(
+		&backendName,
+		"backend",
+		"",
+		"[optional] name of the backend creating the network (default \"local\")",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&networkTTL,
+		"ttl",
+		0,
+		"[optional] stop the network once this duration elapses after it starts (e.g. 2h)",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&reconcile,
+		"reconcile",
+		false,
+		"[optional] if the network is already running, add or remove nodes and chains so it matches the request",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&logsShardSize,
+		"logs-shard-size",
+		0,
+		"[optional] group the node logs in dirs of this many nodes under the network logs dir (useful for 100+ nodes)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&dataDirTemplate,
+		"data-dir-template",
+		"",
+		"[optional] template of the node data dirs, containing {node} and optionally {root} (e.g. /mnt/nodes/{node})",
+	)
+	cmd.PersistentFlags().StringVar(
+		&dbDirTemplate,
+		"db-dir-template",
+		"",
+		"[optional] template of the node db dirs, containing {node} and optionally {root} (e.g. /mnt/fast/{node}/db)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&logsDirTemplate,
+		"logs-dir-template",
+		"",
+		"[optional] template of the node logs dirs, containing {node} and optionally {root} (e.g. {root}/logs/{node})",
+	)
+	cmd.PersistentFlags().Int64Var(
+		&networkSeed,
+		"seed",
+		0,
+		"[optional] derive the generated node keys, ports and genesis start time from this seed, for reproducible networks",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&networkID,
+		"network-id",
+		0,
+		"[optional] ID of the new network, set in the genesis (can't be mainnet, testnet or local network ID)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&txSignerURL,
+		"tx-signer-url",
+		"",
+		"[optional] URL of an external signer (e.g. backed by an HSM or a KMS) of the txs issued by the server, instead of the funded key",
+	)
+	cmd.PersistentFlags().StringVar(
+		&txSignerAddress,
+		"tx-signer-address",
+		"",
+		"[optional] address of the key of the external tx signer, funded in the genesis",
+	)
+	cmd.PersistentFlags().StringVar(
+		&stakingKeysStorage,
+		"staking-keys-storage",
+		"",
+		"[optional] how the staking keys are given to the nodes: file (plaintext files in the node dirs, default), memory (flag contents only) or keystore (also encrypted into the node dirs with the passphrase of the server env var "+constants.StakingKeystorePassphraseEnvVar+")",
+	)
+	cmd.PersistentFlags().StringVar(
+		&externalNetworkStr,
+		"external-network",
+		"",
+		"[optional] JSON string of an existing network to join instead of starting a new one (network ID, bootstrap IPs and IDs, genesis, bootstrap timeout in nanoseconds)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&forkStr,
+		"fork",
+		"",
+		"[optional] JSON string of the remote C-Chain state to copy into the genesis (rpc url, block number, addresses, storage slots, network ID)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&cChainStatePath,
+		"c-chain-state",
+		"",
+		"[optional] path of a C-Chain genesis with alloc or state dump whose accounts are added to the genesis (see export-c-chain-state)",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&validateOnly,
+		"validate-only",
+		false,
+		"[optional] only check the request (exec path, plugins, genesis, node configs, ports, blockchain specs), reporting all the problems found, without starting the network",
+	)
 	if err := cmd.MarkPersistentFlagRequired("node-path"); err != nil {
 		panic(err)
 	}
@@ -263,6 +522,28 @@ func startFunc(*cobra.Command, []string) error {
 		client.WithRootDataDir(rootDataDir),
 		client.WithReassignPortsIfUsed(reassignPortsIfUsed),
 		client.WithDynamicPorts(dynamicPorts),
+		client.WithBackend(backendName),
+		client.WithTTL(networkTTL),
+		client.WithReconcile(reconcile),
+		client.WithLogsShardSize(logsShardSize),
+		client.WithSeed(networkSeed),
+		client.WithNetworkID(networkID),
+		client.WithStakingKeysStorage(stakingKeysStorage),
+	}
+
+	if txSignerURL != "" {
+		opts = append(opts, client.WithTxSigner(&rpcpb.TxSignerSpec{
+			Url:     txSignerURL,
+			Address: txSignerAddress,
+		}))
+	}
+
+	if dataDirTemplate != "" || dbDirTemplate != "" || logsDirTemplate != "" {
+		opts = append(opts, client.WithDirLayout(&rpcpb.DirLayout{
+			DataDir: dataDirTemplate,
+			DbDir:   dbDirTemplate,
+			LogsDir: logsDirTemplate,
+		}))
 	}
 
 	if globalNodeConfig != "" {
@@ -283,6 +564,14 @@ func startFunc(*cobra.Command, []string) error {
 		opts = append(opts, client.WithCustomNodeConfigs(nodeConfigs))
 	}
 
+	if nodeGroups != "" {
+		groups := []*rpcpb.NodeGroup{}
+		if err := json.Unmarshal([]byte(nodeGroups), &groups); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithNodeGroups(groups))
+	}
+
 	if blockchainSpecsStr != "" {
 		blockchainSpecs := []*rpcpb.BlockchainSpec{}
 		if err := json.Unmarshal([]byte(blockchainSpecsStr), &blockchainSpecs); err != nil {
@@ -312,6 +601,43 @@ func startFunc(*cobra.Command, []string) error {
 		}
 		opts = append(opts, client.WithSubnetConfigs(subnetConfigsMap))
 	}
+	if stakingSpecStr != "" {
+		stakingSpec := &rpcpb.StakingSpec{}
+		if err := json.Unmarshal([]byte(stakingSpecStr), stakingSpec); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithStakingSpec(stakingSpec))
+	}
+	if httpHost != "" || publicIP != "" || externalAccess {
+		opts = append(opts, client.WithNetworkAccess(&rpcpb.NetworkAccessSpec{
+			HttpHost:       httpHost,
+			PublicIp:       publicIP,
+			ExternalAccess: externalAccess,
+		}))
+	}
+	if minPort != 0 || maxPort != 0 {
+		opts = append(opts, client.WithPortRange(minPort, maxPort))
+	}
+	if externalNetworkStr != "" {
+		externalNetwork := &rpcpb.ExternalNetworkSpec{}
+		if err := json.Unmarshal([]byte(externalNetworkStr), externalNetwork); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithExternalNetwork(externalNetwork))
+	}
+	if forkStr != "" {
+		fork := &rpcpb.ForkSpec{}
+		if err := json.Unmarshal([]byte(forkStr), fork); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithFork(fork))
+	}
+	if cChainStatePath != "" {
+		opts = append(opts, client.WithCChainStatePath(cChainStatePath))
+	}
+	if validateOnly {
+		opts = append(opts, client.WithValidateOnly(true))
+	}
 
 	ctx := getAsyncContext()
 
@@ -324,8 +650,7 @@ func startFunc(*cobra.Command, []string) error {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("start response: %+v"), info)
-	return nil
+	return printResponse("start response: %+v", info)
 }
 
 func newCreateBlockchainsCommand() *cobra.Command {
@@ -335,6 +660,12 @@ func newCreateBlockchainsCommand() *cobra.Command {
 		RunE:  createBlockchainsFunc,
 		Args:  cobra.ExactArgs(1),
 	}
+	cmd.PersistentFlags().BoolVar(
+		&validateOnly,
+		"validate-only",
+		false,
+		"[optional] only check the blockchain specs, reporting all the problems found, without creating the blockchains",
+	)
 	return cmd
 }
 
@@ -357,13 +688,13 @@ func createBlockchainsFunc(_ *cobra.Command, args []string) error {
 	info, err := cli.CreateBlockchains(
 		ctx,
 		blockchainSpecs,
+		client.WithValidateOnly(validateOnly),
 	)
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("create-blockchains response: %+v"), info)
-	return nil
+	return printResponse("create-blockchains response: %+v", info)
 }
 
 func newCreateSubnetsCommand() *cobra.Command {
@@ -400,8 +731,7 @@ func createSubnetsFunc(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("create-subnets response: %+v"), info)
-	return nil
+	return printResponse("create-subnets response: %+v", info)
 }
 
 func newTransformElasticSubnetsCommand() *cobra.Command {
@@ -431,6 +761,12 @@ func newRemoveSubnetValidatorCommand() *cobra.Command {
 		RunE:  removeSubnetValidatorFunc,
 		Args:  cobra.ExactArgs(1),
 	}
+	cmd.PersistentFlags().BoolVar(
+		&skipRestart,
+		"skip-restart",
+		false,
+		"[optional] don't restart the removed validators, so they keep tracking the subnets until restarted",
+	)
 	return cmd
 }
 
@@ -458,8 +794,7 @@ func transformElasticSubnetsFunc(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("elastic-subnets response: %+v"), info)
-	return nil
+	return printResponse("elastic-subnets response: %+v", info)
 }
 
 func addPermissionlessValidatorFunc(_ *cobra.Command, args []string) error {
@@ -486,133 +821,194 @@ func addPermissionlessValidatorFunc(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("add-permissionless-validator response: %+v"), info)
-	return nil
+	return printResponse("add-permissionless-validator response: %+v", info)
 }
 
-func removeSubnetValidatorFunc(_ *cobra.Command, args []string) error {
+func newTransferSubnetOwnershipCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-subnet-ownership transferSubnetOwnershipSpecs [options]",
+		Short: "Transfer the ownership of subnets.",
+		RunE:  transferSubnetOwnershipFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func transferSubnetOwnershipFunc(_ *cobra.Command, args []string) error {
 	cli, err := newClient()
 	if err != nil {
 		return err
 	}
 	defer cli.Close()
 
-	validatorSpecStr := args[0]
+	transferSpecsStr := args[0]
 
-	validatorSpec := []*rpcpb.RemoveSubnetValidatorSpec{}
-	if err := json.Unmarshal([]byte(validatorSpecStr), &validatorSpec); err != nil {
+	transferSpecs := []*rpcpb.TransferSubnetOwnershipSpec{}
+	if err := json.Unmarshal([]byte(transferSpecsStr), &transferSpecs); err != nil {
 		return err
 	}
 
 	ctx := getAsyncContext()
 
-	info, err := cli.RemoveSubnetValidator(
+	info, err := cli.TransferSubnetOwnership(
 		ctx,
-		validatorSpec,
+		transferSpecs,
 	)
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("remove-subnet-validator response: %+v"), info)
-	return nil
+	return printResponse("transfer-subnet-ownership response: %+v", info)
 }
 
-func newHealthCommand() *cobra.Command {
+func newCreateAssetCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "health [options]",
-		Short: "Requests server health.",
-		RunE:  healthFunc,
-		Args:  cobra.ExactArgs(0),
+		Use:   "create-asset assetSpec [options]",
+		Short: "Create a fungible or NFT X-Chain asset with the given initial holders.",
+		RunE:  createAssetFunc,
+		Args:  cobra.ExactArgs(1),
 	}
 	return cmd
 }
 
-func healthFunc(*cobra.Command, []string) error {
+func createAssetFunc(_ *cobra.Command, args []string) error {
 	cli, err := newClient()
 	if err != nil {
 		return err
 	}
 	defer cli.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	resp, err := cli.Health(ctx)
-	cancel()
+	assetSpecStr := args[0]
+
+	assetSpec := &rpcpb.CreateAssetRequest{}
+	if err := json.Unmarshal([]byte(assetSpecStr), assetSpec); err != nil {
+		return err
+	}
+
+	ctx := getAsyncContext()
+
+	info, err := cli.CreateAsset(ctx, assetSpec)
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("health response: %+v"), resp)
-	return nil
+	return printResponse("create-asset response: %+v", info)
 }
 
-func newWaitForHealthyCommand() *cobra.Command {
+func newTransferCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "wait-for-healthy [options]",
-		Short: "Wait until local cluster + custom vms are ready.",
-		RunE:  waitForHealthy,
-		Args:  cobra.ExactArgs(0),
+		Use:   "transfer to-address amount [options]",
+		Short: "Transfer nLUX of the network key to a P-Chain address.",
+		RunE:  transferFunc,
+		Args:  cobra.ExactArgs(2),
 	}
 	return cmd
 }
 
-func waitForHealthy(*cobra.Command, []string) error {
+func transferFunc(_ *cobra.Command, args []string) error {
+	to := args[0]
+	amount, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", args[1], err)
+	}
 	cli, err := newClient()
 	if err != nil {
 		return err
 	}
 	defer cli.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	defer cancel()
-	resp, err := cli.WaitForHealthy(ctx)
+	ctx := getAsyncContext()
+
+	info, err := cli.Transfer(ctx, to, amount)
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("wait for healthy response: %+v"), resp)
-	return nil
+	return printResponse("transfer response: %+v", info)
 }
 
-func newURIsCommand() *cobra.Command {
+func newStakeCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "uris [options]",
-		Short: "Requests server uris.",
-		RunE:  urisFunc,
-		Args:  cobra.ExactArgs(0),
+		Use:   "stake node-name [options]",
+		Short: "Stake nLUX of the network key on a node, adding it as primary network validator or delegating to it.",
+		RunE:  stakeFunc,
+		Args:  cobra.ExactArgs(1),
 	}
+	cmd.PersistentFlags().Uint64Var(
+		&stakeAmount,
+		"amount",
+		0,
+		"nLUX staked. if zero, uses the minimum stake",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&stakeDelegate,
+		"delegate",
+		false,
+		"delegate to the node, which must be a validator, instead of adding it as one",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&stakeDuration,
+		"duration",
+		0,
+		"duration of the stake. if zero, uses the network validation duration, or the remaining validation of the node for delegations",
+	)
+	cmd.PersistentFlags().StringVar(
+		&stakeRewardAddress,
+		"reward-address",
+		"",
+		"P-Chain address given the rewards. if empty, uses the network key",
+	)
 	return cmd
 }
 
-func urisFunc(*cobra.Command, []string) error {
+func stakeFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
 	cli, err := newClient()
 	if err != nil {
 		return err
 	}
 	defer cli.Close()
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	uris, err := cli.URIs(ctx)
-	cancel()
+	ctx := getAsyncContext()
+
+	info, err := cli.Stake(ctx, &rpcpb.StakeRequest{
+		NodeName:      nodeName,
+		Amount:        stakeAmount,
+		Delegate:      stakeDelegate,
+		Duration:      uint64(stakeDuration.Seconds()),
+		RewardAddress: stakeRewardAddress,
+	})
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("URIs: %s"), uris)
-	return nil
+	return printResponse("stake response: %+v", info)
 }
 
-func newStatusCommand() *cobra.Command {
+func newRenewPrimaryValidationsCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "status [options]",
-		Short: "Requests server status.",
-		RunE:  statusFunc,
-		Args:  cobra.ExactArgs(0),
+		Use:   "renew-primary-validations [node-names] [options]",
+		Short: "Adds nodes again as primary network validators, along with their subnet validations, as their validations end. Defaults to all nodes.",
+		RunE:  renewPrimaryValidationsFunc,
+		Args:  cobra.ArbitraryArgs,
 	}
+	cmd.PersistentFlags().Uint64Var(
+		&renewAmount,
+		"amount",
+		0,
+		"nLUX staked on each new validation. if zero, uses the weight of the ended validation",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&renewDuration,
+		"duration",
+		0,
+		"duration of the new validations. if zero, uses the network validation duration",
+	)
 	return cmd
 }
 
-func statusFunc(*cobra.Command, []string) error {
+func renewPrimaryValidationsFunc(_ *cobra.Command, args []string) error {
 	cli, err := newClient()
 	if err != nil {
 		return err
@@ -620,80 +1016,2194 @@ func statusFunc(*cobra.Command, []string) error {
 	defer cli.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	resp, err := cli.Status(ctx)
+	info, err := cli.RenewPrimaryValidations(ctx, &rpcpb.RenewPrimaryValidationsRequest{
+		NodeNames: args,
+		Amount:    renewAmount,
+		Duration:  uint64(renewDuration.Seconds()),
+	})
 	cancel()
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("status response: %+v"), resp)
-	return nil
-}
-
-var pushInterval time.Duration
-
-func newStreamStatusCommand() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "stream-status [options]",
-		Short: "Requests server bootstrap status.",
-		RunE:  streamStatusFunc,
-		Args:  cobra.ExactArgs(0),
-	}
-	cmd.PersistentFlags().DurationVar(
-		&pushInterval,
-		"push-interval",
-		5*time.Second,
-		"interval that server pushes status updates to the client",
-	)
-	return cmd
+	return printResponse("renew-primary-validations response: %+v", info)
 }
 
-func streamStatusFunc(*cobra.Command, []string) error {
+func removeSubnetValidatorFunc(_ *cobra.Command, args []string) error {
 	cli, err := newClient()
 	if err != nil {
 		return err
 	}
 	defer cli.Close()
 
-	// poll until the cluster is healthy or os signal
-	sigc := make(chan os.Signal, 1)
-	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+	validatorSpecStr := args[0]
 
-	donec := make(chan struct{})
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	go func() {
-		select {
-		case sig := <-sigc:
-			log.Warn("received signal", zap.String("signal", sig.String()))
-		case <-ctx.Done():
-		}
-		cancel()
-		close(donec)
-	}()
+	validatorSpec := []*rpcpb.RemoveSubnetValidatorSpec{}
+	if err := json.Unmarshal([]byte(validatorSpecStr), &validatorSpec); err != nil {
+		return err
+	}
 
-	ch, err := cli.StreamStatus(ctx, pushInterval)
+	ctx := getAsyncContext()
+
+	info, err := cli.RemoveSubnetValidator(
+		ctx,
+		validatorSpec,
+		client.WithSkipRestart(skipRestart),
+	)
 	if err != nil {
 		return err
 	}
-	for info := range ch {
-		ux.Print(log, logging.Cyan.Wrap("cluster info: %+v"), info)
-	}
-	cancel() // receiver channel is closed, so cancel goroutine
-	<-donec
-	return nil
+
+	return printResponse("remove-subnet-validator response: %+v", info)
 }
 
-func newRemoveNodeCommand() *cobra.Command {
+func newWaitForValidatorRewardsCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "remove-node node-name [options]",
-		Short: "Removes a node.",
-		RunE:  removeNodeFunc,
+		Use:   "wait-for-validator-rewards node-name [options]",
+		Short: "Waits for the validation of a node to end and shows the rewards received.",
+		RunE:  waitForValidatorRewardsFunc,
 		Args:  cobra.ExactArgs(1),
 	}
-	return cmd
-}
-
-func removeNodeFunc(_ *cobra.Command, args []string) error {
+	cmd.PersistentFlags().StringVar(
+		&rewardsSubnetID,
+		"subnet-id",
+		"",
+		"[optional] subnet id of the validation. defaults to primary network",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&rewardsTimeout,
+		"timeout",
+		0,
+		"[optional] max duration to wait for. no limit if 0, as validations can last long",
+	)
+	return cmd
+}
+
+func waitForValidatorRewardsFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	// the request timeout doesn't apply, as the validation may end long after
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	if rewardsTimeout > 0 {
+		var timeoutCancel context.CancelFunc
+		ctx, timeoutCancel = context.WithTimeout(ctx, rewardsTimeout)
+		defer timeoutCancel()
+	}
+	info, err := cli.WaitForValidatorRewards(ctx, nodeName, rewardsSubnetID)
+	if err != nil {
+		return err
+	}
+
+	return printResponse("wait-for-validator-rewards response: %+v", info)
+}
+
+func newGetUptimesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-uptimes [options]",
+		Short: "Shows the uptimes of the network validators.",
+		RunE:  getUptimesFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringVar(
+		&uptimesSubnetID,
+		"subnet-id",
+		"",
+		"[optional] subnet id of the validators. defaults to primary network",
+	)
+	return cmd
+}
+
+func getUptimesFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetUptimes(ctx, uptimesSubnetID)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("get-uptimes response: %+v", resp)
+}
+
+func newGetElasticSubnetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-elastic-subnet subnet-id [options]",
+		Short: "Shows the parameters an elastic subnet was transformed with, and the state of its staking pool.",
+		RunE:  getElasticSubnetFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func getElasticSubnetFunc(_ *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetElasticSubnet(ctx, args[0])
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("get-elastic-subnet response: %+v", resp)
+}
+
+func newGetStakingRewardsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-staking-rewards [options]",
+		Short: "Shows the current validators, with their delegators and pending rewards.",
+		RunE:  getStakingRewardsFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringVar(
+		&stakesSubnetID,
+		"subnet-id",
+		"",
+		"[optional] subnet id of the validators. defaults to primary network",
+	)
+	return cmd
+}
+
+func getStakingRewardsFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetStakingRewards(ctx, stakesSubnetID)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("get-staking-rewards response: %+v", resp)
+}
+
+func newGetVersionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-versions [options]",
+		Short: "Shows the node, database and VM versions of the running nodes.",
+		RunE:  getVersionsFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func getVersionsFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetVersions(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("get-versions response: %+v", resp)
+}
+
+var topologyFormat string
+
+func newGetTopologyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-topology [options]",
+		Short: "Shows the nodes, their ports and subnets, and the peer connections of the network.",
+		RunE:  getTopologyFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringVar(
+		&topologyFormat,
+		"format",
+		"",
+		"[optional] prints only the topology exported in this format (json, dot)",
+	)
+	return cmd
+}
+
+func getTopologyFunc(*cobra.Command, []string) error {
+	var format rpcpb.TopologyFormat
+	switch topologyFormat {
+	case "":
+	case "json":
+		format = rpcpb.TopologyFormat_TOPOLOGY_FORMAT_JSON
+	case "dot":
+		format = rpcpb.TopologyFormat_TOPOLOGY_FORMAT_DOT
+	default:
+		return fmt.Errorf("invalid topology format %q, expected json or dot", topologyFormat)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetTopology(ctx, format)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if format != rpcpb.TopologyFormat_TOPOLOGY_FORMAT_UNSPECIFIED {
+		// printed as is, so it can be piped to graphviz or jq
+		fmt.Print(resp.Export)
+		return nil
+	}
+	return printResponse("get-topology response: %+v", resp)
+}
+
+var (
+	exportFormat string
+	exportImage  string
+	exportOutput string
+)
+
+func newExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export [options]",
+		Short: "Renders the running network in a format it can be run with outside the runner.",
+		RunE:  exportFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringVar(
+		&exportFormat,
+		"format",
+		"docker-compose",
+		"format of the export (docker-compose, manifest)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&exportImage,
+		"image",
+		"",
+		"[optional] image the nodes are run with, whose entrypoint is the node binary (default luxdefi/node:latest)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&exportOutput,
+		"output",
+		"",
+		"[optional] file to write the export to, instead of stdout",
+	)
+	return cmd
+}
+
+func exportFunc(*cobra.Command, []string) error {
+	var format rpcpb.NetworkExportFormat
+	switch exportFormat {
+	case "docker-compose":
+		format = rpcpb.NetworkExportFormat_NETWORK_EXPORT_FORMAT_DOCKER_COMPOSE
+	case "manifest":
+		format = rpcpb.NetworkExportFormat_NETWORK_EXPORT_FORMAT_MANIFEST
+	default:
+		return fmt.Errorf("invalid export format %q, expected docker-compose or manifest", exportFormat)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	export, err := cli.ExportNetwork(ctx, format, exportImage)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if exportOutput != "" {
+		return os.WriteFile(exportOutput, []byte(export), 0o644)
+	}
+	// printed as is, so it can be piped to docker compose
+	fmt.Print(export)
+	return nil
+}
+
+func newCheckConnectivityCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check-connectivity [options]",
+		Short: "Reports the connections between running nodes missing from their peers.",
+		RunE:  checkConnectivityFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func checkConnectivityFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.CheckConnectivity(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("check-connectivity response: %+v", resp)
+}
+
+var (
+	historyMethod string
+	historySince  time.Duration
+	historyLimit  uint32
+)
+
+func newGetOperationHistoryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-operation-history [options]",
+		Short: "Shows the control calls recorded in the server audit log.",
+		RunE:  getOperationHistoryFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringVar(
+		&historyMethod,
+		"method",
+		"",
+		"only show the calls to methods containing this string",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&historySince,
+		"since",
+		0,
+		"only show the calls made in this last duration",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&historyLimit,
+		"limit",
+		0,
+		"max number of calls shown, the most recent ones (100 if not given)",
+	)
+	return cmd
+}
+
+func getOperationHistoryFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	var since time.Time
+	if historySince > 0 {
+		since = time.Now().Add(-historySince)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetOperationHistory(ctx, historyMethod, since, historyLimit)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("get-operation-history response: %+v", resp)
+}
+
+var (
+	listSubnetID  string
+	listNodeState string
+	listPageSize  uint32
+	listPageToken string
+)
+
+// names of the node states accepted by list-nodes --state
+var nodeStates = map[string]rpcpb.NodeState{
+	"running": rpcpb.NodeState_NODE_STATE_RUNNING,
+	"paused":  rpcpb.NodeState_NODE_STATE_PAUSED,
+}
+
+func addListFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&listSubnetID,
+		"subnet-id",
+		"",
+		"only list the ones of this subnet",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&listPageSize,
+		"page-size",
+		0,
+		"max number listed (100 if not given)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&listPageToken,
+		"page-token",
+		"",
+		"next_page_token returned for the previous page",
+	)
+}
+
+func newListNodesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-nodes [options]",
+		Short: "Lists a page of nodes of the network.",
+		RunE:  listNodesFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	addListFlags(cmd)
+	cmd.PersistentFlags().StringVar(
+		&listNodeState,
+		"state",
+		"",
+		"only list the nodes in this state (running, paused)",
+	)
+	return cmd
+}
+
+func listNodesFunc(*cobra.Command, []string) error {
+	req := &rpcpb.ListNodesRequest{
+		SubnetId:  listSubnetID,
+		PageSize:  listPageSize,
+		PageToken: listPageToken,
+	}
+	if listNodeState != "" {
+		state, ok := nodeStates[listNodeState]
+		if !ok {
+			return fmt.Errorf("invalid node state %q", listNodeState)
+		}
+		req.State = state
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.ListNodes(ctx, req)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("list-nodes response: %+v", resp)
+}
+
+func newListCustomChainsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-custom-chains [options]",
+		Short: "Lists a page of custom chains of the network.",
+		RunE:  listCustomChainsFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	addListFlags(cmd)
+	return cmd
+}
+
+func listCustomChainsFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.ListCustomChains(ctx, &rpcpb.ListCustomChainsRequest{
+		SubnetId:  listSubnetID,
+		PageSize:  listPageSize,
+		PageToken: listPageToken,
+	})
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("list-custom-chains response: %+v", resp)
+}
+
+func newListBlockchainsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-blockchains [options]",
+		Short: "Lists the custom blockchains of the network.",
+		RunE:  listBlockchainsFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func listBlockchainsFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.ListBlockchains(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("list-blockchains response: %+v", resp)
+}
+
+func newGetBlockchainStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-blockchain-status blockchain-id-or-alias [options]",
+		Short: "Shows the status of a custom blockchain.",
+		RunE:  getBlockchainStatusFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func getBlockchainStatusFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	chain := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetBlockchainStatus(ctx, chain)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("get-blockchain-status response: %+v", resp)
+}
+
+func newAddBlockchainAliasCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-blockchain-alias blockchain-id alias [options]",
+		Short: "Registers an alias for a blockchain on all nodes.",
+		RunE:  addBlockchainAliasFunc,
+		Args:  cobra.ExactArgs(2),
+	}
+	return cmd
+}
+
+func addBlockchainAliasFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty strings required, as covered by `cobra.ExactArgs`
+	chainID := args[0]
+	alias := args[1]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx := getAsyncContext()
+
+	info, err := cli.AddBlockchainAlias(ctx, chainID, alias)
+	if err != nil {
+		return err
+	}
+
+	return printResponse("add-blockchain-alias response: %+v", info)
+}
+
+func newRemoveBlockchainAliasCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-blockchain-alias blockchain-id alias [options]",
+		Short: "Removes an alias of a blockchain from all nodes, restarting them.",
+		RunE:  removeBlockchainAliasFunc,
+		Args:  cobra.ExactArgs(2),
+	}
+	return cmd
+}
+
+func removeBlockchainAliasFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty strings required, as covered by `cobra.ExactArgs`
+	chainID := args[0]
+	alias := args[1]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx := getAsyncContext()
+
+	info, err := cli.RemoveBlockchainAlias(ctx, chainID, alias)
+	if err != nil {
+		return err
+	}
+
+	return printResponse("remove-blockchain-alias response: %+v", info)
+}
+
+func newAddVMAliasCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-vm-alias vm-id alias [options]",
+		Short: "Registers an alias for a VM on all nodes, restarting them.",
+		RunE:  addVMAliasFunc,
+		Args:  cobra.ExactArgs(2),
+	}
+	return cmd
+}
+
+func addVMAliasFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty strings required, as covered by `cobra.ExactArgs`
+	vmID := args[0]
+	alias := args[1]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx := getAsyncContext()
+
+	info, err := cli.AddVMAlias(ctx, vmID, alias)
+	if err != nil {
+		return err
+	}
+
+	return printResponse("add-vm-alias response: %+v", info)
+}
+
+func newHealthCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "health [options]",
+		Short: "Requests server health.",
+		RunE:  healthFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func healthFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.Health(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("health response: %+v", resp)
+}
+
+func newWaitForHealthyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait-for-healthy [options]",
+		Short: "Wait until local cluster + custom vms are ready.",
+		RunE:  waitForHealthy,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func waitForHealthy(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	resp, err := cli.WaitForHealthy(ctx)
+	if err != nil {
+		return err
+	}
+
+	return printResponse("wait for healthy response: %+v", resp)
+}
+
+var (
+	waitHealthyNodes            uint32
+	waitBootstrappedBlockchains []string
+	waitSubnetValidators        []string
+	waitTimeout                 time.Duration
+)
+
+func newWaitCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wait [options]",
+		Short: "Waits until all the given conditions hold at the same time.",
+		Long: `Waits until all the given conditions hold at the same time, e.g.
+
+  control wait --healthy-nodes 5 --blockchain-bootstrapped C --subnet-validators <subnet-id>=3
+
+Fails listing the conditions that didn't hold if the timeout expires.`,
+		RunE: waitFunc,
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().Uint32Var(
+		&waitHealthyNodes,
+		"healthy-nodes",
+		0,
+		"min number of running nodes reporting healthy",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&waitBootstrappedBlockchains,
+		"blockchain-bootstrapped",
+		nil,
+		"blockchain ID or alias that all running nodes must have bootstrapped (can be repeated)",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&waitSubnetValidators,
+		"subnet-validators",
+		nil,
+		"subnet-id=N, min number of current validators of the subnet (can be repeated)",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&waitTimeout,
+		"timeout",
+		5*time.Minute,
+		"max duration to wait for",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&pollInterval,
+		"poll-interval",
+		0,
+		"[optional] interval between checks (1s if 0)",
+	)
+	return cmd
+}
+
+func waitFunc(*cobra.Command, []string) error {
+	subnetValidators := map[string]uint32{}
+	for _, s := range waitSubnetValidators {
+		subnetID, numValidatorsStr, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("invalid subnet validators %q, expected subnet-id=N", s)
+		}
+		numValidators, err := strconv.ParseUint(numValidatorsStr, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid subnet validators %q: %w", s, err)
+		}
+		subnetValidators[subnetID] = uint32(numValidators)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	// the server enforces the timeout, and reports the unmet conditions
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	resp, err := cli.WaitFor(ctx, &rpcpb.WaitForRequest{
+		HealthyNodes:            waitHealthyNodes,
+		BootstrappedBlockchains: waitBootstrappedBlockchains,
+		SubnetValidators:        subnetValidators,
+		Timeout:                 int64(waitTimeout),
+		PollInterval:            int64(pollInterval),
+	})
+	if err != nil {
+		return err
+	}
+
+	return printResponse("wait response: %+v", resp)
+}
+
+var (
+	assertValidatorCounts []string
+	assertHealthyChains   []string
+	assertNodeVersions    []string
+)
+
+func newAssertCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "assert [options]",
+		Short: "Checks assertions on the network, failing if any doesn't pass.",
+		Long: `Checks assertions on the network, printing the expected and actual values of each, e.g.
+
+  control assert --validator-count 5 --validator-count <subnet-id>=3 --chain-healthy C --node-version node1=1.10.3`,
+		RunE: assertFunc,
+		Args: cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringSliceVar(
+		&assertValidatorCounts,
+		"validator-count",
+		nil,
+		"[subnet-id=]N, number of current validators of the subnet, or of the primary network (can be repeated)",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&assertHealthyChains,
+		"chain-healthy",
+		nil,
+		"blockchain ID or alias that must be validated and bootstrapped on all running nodes (can be repeated)",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&assertNodeVersions,
+		"node-version",
+		nil,
+		"node-name=version, version the node must run (can be repeated)",
+	)
+	return cmd
+}
+
+func assertFunc(*cobra.Command, []string) error {
+	assertions := []*rpcpb.Assertion{}
+	for _, s := range assertValidatorCounts {
+		subnetID, countStr, ok := strings.Cut(s, "=")
+		if !ok {
+			subnetID, countStr = "", s
+		}
+		count, err := strconv.ParseUint(countStr, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid validator count %q: %w", s, err)
+		}
+		assertions = append(assertions, &rpcpb.Assertion{
+			Assertion: &rpcpb.Assertion_ValidatorCount{
+				ValidatorCount: &rpcpb.ValidatorCountAssertion{SubnetId: subnetID, Count: uint32(count)},
+			},
+		})
+	}
+	for _, chain := range assertHealthyChains {
+		assertions = append(assertions, &rpcpb.Assertion{
+			Assertion: &rpcpb.Assertion_ChainHealthy{
+				ChainHealthy: &rpcpb.ChainHealthyAssertion{Chain: chain},
+			},
+		})
+	}
+	for _, s := range assertNodeVersions {
+		nodeName, version, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("invalid node version %q, expected node-name=version", s)
+		}
+		assertions = append(assertions, &rpcpb.Assertion{
+			Assertion: &rpcpb.Assertion_NodeVersion{
+				NodeVersion: &rpcpb.NodeVersionAssertion{NodeName: nodeName, Version: version},
+			},
+		})
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.Assert(ctx, assertions...)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		if err := printResponse("", resp); err != nil {
+			return err
+		}
+	} else {
+		for _, result := range resp.Results {
+			outcome := logging.Green.Wrap("PASS")
+			if !result.Passed {
+				outcome = logging.Red.Wrap("FAIL")
+			}
+			details := fmt.Sprintf("expected %s, got %s", result.Expected, result.Actual)
+			if result.Error != "" {
+				details = result.Error
+			}
+			ux.Print(log, "%s %s: %s", outcome, result.Assertion, details)
+		}
+	}
+	if !resp.Passed {
+		return errors.New("assertions failed")
+	}
+	return nil
+}
+
+func newURIsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "uris [options]",
+		Short: "Requests server uris.",
+		RunE:  urisFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func urisFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	uris, err := cli.URIs(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("URIs: %s", uris)
+}
+
+func newStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [options]",
+		Short: "Requests server status.",
+		RunE:  statusFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func statusFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.Status(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("status response: %+v", resp)
+}
+
+func newGetNodeInfoCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-node-info node-name [options]",
+		Short: "Requests the details of a node.",
+		RunE:  getNodeInfoFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func getNodeInfoFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetNodeInfo(ctx, nodeName)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("get node info response: %+v", resp)
+}
+
+var (
+	logsChain  string
+	logsFollow bool
+	logsGrep   string
+	logsSince  time.Duration
+)
+
+func newLogsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs node-name [options]",
+		Short: "Prints the logs of a node.",
+		RunE:  logsFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().StringVar(
+		&logsChain,
+		"chain",
+		"",
+		"blockchain ID or alias (e.g. C) of the chain log to print, instead of the node main log",
+	)
+	cmd.PersistentFlags().BoolVarP(
+		&logsFollow,
+		"follow",
+		"f",
+		false,
+		"keep printing the lines appended to the log",
+	)
+	cmd.PersistentFlags().StringVar(
+		&logsGrep,
+		"grep",
+		"",
+		"only print the lines matching this regular expression",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&logsSince,
+		"since",
+		0,
+		"only print the lines logged in this last duration (e.g. 10m)",
+	)
+	return cmd
+}
+
+func logsFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	opts := []client.OpOption{
+		client.WithLogsChain(logsChain),
+		client.WithLogsFollow(logsFollow),
+		client.WithLogsGrep(logsGrep),
+	}
+	if logsSince > 0 {
+		opts = append(opts, client.WithLogsSince(time.Now().Add(-logsSince)))
+	}
+
+	// followed logs are printed until os signal
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if logsFollow {
+		ctx, cancel = signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	} else {
+		ctx, cancel = context.WithTimeout(context.Background(), requestTimeout)
+	}
+	defer cancel()
+
+	var printErr error
+	err = cli.StreamLogs(ctx, nodeName, func(lines []string) {
+		if printErr != nil {
+			return
+		}
+		if outputFormat == outputJSON {
+			printErr = printResponse("", &rpcpb.StreamLogsResponse{Lines: lines})
+			return
+		}
+		for _, line := range lines {
+			if _, printErr = fmt.Fprintln(os.Stdout, line); printErr != nil {
+				return
+			}
+		}
+	}, opts...)
+	if err != nil {
+		return err
+	}
+	return printErr
+}
+
+var (
+	searchLogsNodeName   string
+	searchLogsMaxMatches uint32
+)
+
+func newSearchLogsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search-logs pattern [options]",
+		Short: "Prints the log lines of the nodes matching a regular expression.",
+		RunE:  searchLogsFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().StringVar(
+		&searchLogsNodeName,
+		"node-name",
+		"",
+		"node whose log is searched, instead of every node",
+	)
+	cmd.PersistentFlags().StringVar(
+		&logsChain,
+		"chain",
+		"",
+		"blockchain ID or alias (e.g. C) of the chain log to search, instead of the node main log",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&logsSince,
+		"since",
+		0,
+		"only search the lines logged in this last duration (e.g. 10m)",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&searchLogsMaxMatches,
+		"max-matches",
+		0,
+		"max number of lines printed (defaults to 1000)",
+	)
+	return cmd
+}
+
+func searchLogsFunc(_ *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	opts := []client.OpOption{
+		client.WithLogsChain(logsChain),
+		client.WithLogsMaxMatches(searchLogsMaxMatches),
+	}
+	if logsSince > 0 {
+		opts = append(opts, client.WithLogsSince(time.Now().Add(-logsSince)))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.SearchLogs(ctx, searchLogsNodeName, args[0], opts...)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		return printResponse("", resp)
+	}
+	for _, match := range resp.Matches {
+		if _, err := fmt.Fprintf(os.Stdout, "%s: %s\n", match.NodeName, match.Line); err != nil {
+			return err
+		}
+	}
+	if resp.Truncated {
+		ux.Print(log, logging.Yellow.Wrap("more lines matched, raise --max-matches to print them"))
+	}
+	return nil
+}
+
+var (
+	pushInterval time.Duration
+	statusFields []string
+	statusDelta  bool
+)
+
+// names of the cluster info parts accepted by stream-status --fields
+var clusterInfoFields = map[string]rpcpb.ClusterInfoField{
+	"nodes":   rpcpb.ClusterInfoField_CLUSTER_INFO_FIELD_NODES,
+	"subnets": rpcpb.ClusterInfoField_CLUSTER_INFO_FIELD_SUBNETS,
+}
+
+func newStreamStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stream-status [options]",
+		Short: "Requests server bootstrap status.",
+		RunE:  streamStatusFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().DurationVar(
+		&pushInterval,
+		"push-interval",
+		5*time.Second,
+		"interval that server pushes status updates to the client",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&statusFields,
+		"fields",
+		nil,
+		"parts of the cluster info pushed (nodes, subnets), all if not given",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&statusDelta,
+		"delta",
+		false,
+		"true to only push the cluster info when it changed",
+	)
+	return cmd
+}
+
+func streamStatusFunc(*cobra.Command, []string) error {
+	opts := []client.OpOption{client.WithStatusDelta(statusDelta)}
+	if len(statusFields) > 0 {
+		fields := make([]rpcpb.ClusterInfoField, 0, len(statusFields))
+		for _, name := range statusFields {
+			field, ok := clusterInfoFields[name]
+			if !ok {
+				return fmt.Errorf("invalid cluster info field %q", name)
+			}
+			fields = append(fields, field)
+		}
+		opts = append(opts, client.WithStatusFields(fields...))
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	// poll until the cluster is healthy or os signal
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+
+	donec := make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	go func() {
+		select {
+		case sig := <-sigc:
+			log.Warn("received signal", zap.String("signal", sig.String()))
+		case <-ctx.Done():
+		}
+		cancel()
+		close(donec)
+	}()
+
+	ch, err := cli.StreamStatus(ctx, pushInterval, opts...)
+	if err != nil {
+		return err
+	}
+	for info := range ch {
+		if err := printResponse("cluster info: %+v", info); err != nil {
+			return err
+		}
+	}
+	cancel() // receiver channel is closed, so cancel goroutine
+	<-donec
+	return nil
+}
+
+func newRemoveNodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove-node node-name [options]",
+		Short: "Removes a node.",
+		RunE:  removeNodeFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func removeNodeFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	info, err := cli.RemoveNode(ctx, nodeName)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("remove node response: %+v", info)
+}
+
+func newDrainNodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "drain-node node-name [options]",
+		Short: "Removes a node as subnet validator, and then removes it.",
+		RunE:  drainNodeFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func drainNodeFunc(_ *cobra.Command, args []string) error {
+	nodeName := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx := getAsyncContext()
+
+	info, err := cli.DrainNode(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	return printResponse("drain node response: %+v", info)
+}
+
+func newScaleNetworkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scale-network num-nodes [options]",
+		Short: "Adds or removes nodes so the network has the given number of nodes.",
+		RunE:  scaleNetworkFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func scaleNetworkFunc(_ *cobra.Command, args []string) error {
+	numNodes, err := strconv.ParseUint(args[0], 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid number of nodes %q: %w", args[0], err)
+	}
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	info, err := cli.ScaleNetwork(ctx, uint32(numNodes))
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("scale network response: %+v", info)
+}
+
+func newResetNetworkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset-network [options]",
+		Short: "Restarts all nodes from genesis, removing their dbs but keeping their keys and configs.",
+		RunE:  resetNetworkFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func resetNetworkFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	info, err := cli.ResetNetwork(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("reset network response: %+v", info)
+}
+
+var (
+	stateSyncMinBlocks uint64
+	pollInterval       time.Duration
+)
+
+func newStateSyncNodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state-sync-node [node-name] [options]",
+		Short: "Adds a node state syncing from the network, and prints its sync milestones until it is healthy.",
+		RunE:  stateSyncNodeFunc,
+		Args:  cobra.MaximumNArgs(1),
+	}
+	cmd.PersistentFlags().StringVar(
+		&luxdBinPath,
+		"node-path",
+		"",
+		"node binary path",
+	)
+	cmd.PersistentFlags().Uint64Var(
+		&stateSyncMinBlocks,
+		"state-sync-min-blocks",
+		0,
+		"[optional] C-Chain blocks behind the network tip needed to state sync (node default if 0)",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&pollInterval,
+		"poll-interval",
+		0,
+		"[optional] interval between sync progress checks (2s if 0)",
+	)
+	return cmd
+}
+
+func stateSyncNodeFunc(_ *cobra.Command, args []string) error {
+	nodeName := ""
+	if len(args) > 0 {
+		nodeName = args[0]
+	}
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	var printErr error
+	err = cli.StateSyncNode(
+		ctx,
+		nodeName,
+		func(resp *rpcpb.StateSyncNodeResponse) {
+			if printErr != nil {
+				return
+			}
+			printErr = printResponse("state sync milestone: %+v", resp)
+		},
+		client.WithExecPath(luxdBinPath),
+		client.WithStateSyncMinBlocks(stateSyncMinBlocks),
+		client.WithPollInterval(pollInterval),
+	)
+	if err != nil {
+		return err
+	}
+	return printErr
+}
+
+var (
+	exportNodeName    string
+	exportBlockNumber uint64
+)
+
+func newExportCChainStateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-c-chain-state path [options]",
+		Short: "Writes the C-Chain state of a node as a C-Chain genesis with alloc, on the server host.",
+		RunE:  exportCChainStateFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().StringVar(
+		&exportNodeName,
+		"node-name",
+		"",
+		"[optional] node whose state is exported (first running node if empty)",
+	)
+	cmd.PersistentFlags().Uint64Var(
+		&exportBlockNumber,
+		"block-number",
+		0,
+		"[optional] height the state is exported at (latest block if 0)",
+	)
+	return cmd
+}
+
+func exportCChainStateFunc(_ *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.ExportCChainState(
+		ctx,
+		args[0],
+		client.WithNodeName(exportNodeName),
+		client.WithBlockNumber(exportBlockNumber),
+	)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("export C-Chain state response: %+v", resp)
+}
+
+func newDBSizesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db-sizes [node-names] [options]",
+		Short: "Reports the sizes on disk of the db and chain data dirs of the nodes (all nodes if none given).",
+		RunE:  dbSizesFunc,
+	}
+	return cmd
+}
+
+func dbSizesFunc(_ *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetDBSizes(ctx, args...)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("db sizes response: %+v", resp)
+}
+
+func newPruneNodeDBCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune-node-db node-name [options]",
+		Short: "Prunes the C-Chain state of a node offline, restarting it, and reports its db sizes before and after.",
+		RunE:  pruneNodeDBFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func pruneNodeDBFunc(_ *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.PruneNodeDB(ctx, args[0])
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("prune node db response: %+v", resp)
+}
+
+func newPauseNodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pause-node node-name [options]",
+		Short: "Pauses a node.",
+		RunE:  pauseNodeFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func pauseNodeFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	info, err := cli.PauseNode(ctx, nodeName)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("pause node response: %+v", info)
+}
+
+func newResumeNodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume-node node-name [options]",
+		Short: "Resumes a node.",
+		RunE:  resumeNodeFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func resumeNodeFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	info, err := cli.ResumeNode(ctx, nodeName)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("resume node response: %+v", info)
+}
+
+func newPauseAllCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pause-all [options]",
+		Short: "Pauses all the running nodes.",
+		RunE:  pauseAllFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func pauseAllFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	info, err := cli.PauseAll(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("pause all response: %+v", info)
+}
+
+func newResumeAllCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume-all [options]",
+		Short: "Resumes all the paused nodes.",
+		RunE:  resumeAllFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func resumeAllFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	info, err := cli.ResumeAll(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("resume all response: %+v", info)
+}
+
+var (
+	chaosInterval  time.Duration
+	chaosDowntime  time.Duration
+	chaosActions   []string
+	chaosNodeNames []string
+	chaosMaxDown   uint32
+	chaosSeed      int64
+)
+
+func newChaosCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chaos [command]",
+		Short: "Randomly pauses, kills and restarts nodes, for soak tests.",
+	}
+	cmd.AddCommand(
+		newChaosStartCommand(),
+		newChaosStopCommand(),
+		newChaosStatusCommand(),
+	)
+	return cmd
+}
+
+func newChaosStartCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start [options]",
+		Short: "Starts taking random nodes down and back up, until stopped.",
+		RunE:  chaosStartFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().DurationVar(
+		&chaosInterval,
+		"interval",
+		0,
+		"[optional] mean interval between actions, each one picked at random between half and one and a half of it (1m if 0)",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&chaosDowntime,
+		"downtime",
+		0,
+		"[optional] duration paused or killed nodes are kept down (30s if 0)",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&chaosActions,
+		"actions",
+		nil,
+		"[optional] actions to pick from: pause, kill, restart (all if empty)",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&chaosNodeNames,
+		"node-names",
+		nil,
+		"[optional] nodes to pick from (all if empty)",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&chaosMaxDown,
+		"max-down",
+		0,
+		"[optional] max number of nodes down at the same time (1 if 0)",
+	)
+	cmd.PersistentFlags().Int64Var(
+		&chaosSeed,
+		"seed",
+		0,
+		"[optional] seed of the random choices, to repeat a run (time based if 0)",
+	)
+	return cmd
+}
+
+func chaosStartFunc(*cobra.Command, []string) error {
+	actions := []rpcpb.ChaosAction{}
+	for _, action := range chaosActions {
+		value, ok := rpcpb.ChaosAction_value["CHAOS_ACTION_"+strings.ToUpper(action)]
+		if !ok || value == int32(rpcpb.ChaosAction_CHAOS_ACTION_UNSPECIFIED) {
+			return fmt.Errorf("invalid chaos action %q", action)
+		}
+		actions = append(actions, rpcpb.ChaosAction(value))
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.StartChaos(ctx, &rpcpb.StartChaosRequest{
+		Interval:  int64(chaosInterval),
+		Downtime:  int64(chaosDowntime),
+		Actions:   actions,
+		NodeNames: chaosNodeNames,
+		MaxDown:   chaosMaxDown,
+		Seed:      chaosSeed,
+	})
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("chaos start response: %+v", resp)
+}
+
+func newChaosStopCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop [options]",
+		Short: "Stops taking nodes down, brings back up the ones that are, and prints the actions taken.",
+		RunE:  chaosStopFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func chaosStopFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.StopChaos(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("chaos stop response: %+v", resp)
+}
+
+func newChaosStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [options]",
+		Short: "Prints whether chaos is running, its config and the actions taken.",
+		RunE:  chaosStatusFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func chaosStatusFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.GetChaosStatus(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("chaos status response: %+v", resp)
+}
+
+var (
+	signTxEVMChainID uint64
+)
+
+func newKeysCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys [command]",
+		Short: "Manages the keys of the network, used to sign txs.",
+	}
+	cmd.AddCommand(
+		newKeysListCommand(),
+		newKeysCreateCommand(),
+		newKeysExportCommand(),
+		newKeysSignMessageCommand(),
+		newKeysSignTxCommand(),
+	)
+	return cmd
+}
+
+func newKeysListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [options]",
+		Short: "Lists the keys of the network, with their addresses.",
+		RunE:  keysListFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func keysListFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.ListKeys(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("list keys response: %+v", resp)
+}
+
+func newKeysCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create key-name [options]",
+		Short: "Creates a new random key.",
+		RunE:  keysCreateFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func keysCreateFunc(_ *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.CreateKey(ctx, args[0])
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("create key response: %+v", resp)
+}
+
+func newKeysExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export key-name [options]",
+		Short: "Prints the private key.",
+		RunE:  keysExportFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func keysExportFunc(_ *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.ExportKey(ctx, args[0])
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("export key response: %+v", resp)
+}
+
+func newKeysSignMessageCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-message key-name message [options]",
+		Short: "Signs the SHA-256 hash of the message, printing the hex encoded signature.",
+		RunE:  keysSignMessageFunc,
+		Args:  cobra.ExactArgs(2),
+	}
+	return cmd
+}
+
+func keysSignMessageFunc(_ *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.SignMessage(ctx, args[0], []byte(args[1]))
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		return printResponse("sign message response: %+v", resp)
+	}
+	ux.Print(log, "0x%s", hex.EncodeToString(resp.Signature))
+	return nil
+}
+
+func newKeysSignTxCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sign-tx key-name unsigned-tx-hex [options]",
+		Short: "Signs a P-Chain or X-Chain unsigned tx, printing the credential signature, or an EVM tx, printing it signed.",
+		RunE:  keysSignTxFunc,
+		Args:  cobra.ExactArgs(2),
+	}
+	cmd.PersistentFlags().Uint64Var(
+		&signTxEVMChainID,
+		"evm-chain-id",
+		0,
+		"[optional] chain ID of the EVM chain the tx is for (P-Chain or X-Chain tx if 0)",
+	)
+	return cmd
+}
+
+func keysSignTxFunc(_ *cobra.Command, args []string) error {
+	unsignedTx, err := hex.DecodeString(strings.TrimPrefix(args[1], "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid unsigned tx hex: %w", err)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.SignTx(ctx, &rpcpb.SignTxRequest{
+		KeyName:    args[0],
+		UnsignedTx: unsignedTx,
+		EvmChainId: signTxEVMChainID,
+	})
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	if outputFormat == outputJSON {
+		return printResponse("sign tx response: %+v", resp)
+	}
+	if len(resp.SignedTx) > 0 {
+		ux.Print(log, "0x%s", hex.EncodeToString(resp.SignedTx))
+	} else {
+		ux.Print(log, "0x%s", hex.EncodeToString(resp.Signature))
+	}
+	return nil
+}
+
+func newSimulateDowntimeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "simulate-downtime node-name [options]",
+		Short: "Pauses a node for a given duration, resumes it, and shows its uptime as seen by the other validators.",
+		RunE:  simulateDowntimeFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().DurationVar(
+		&downtimeDuration,
+		"duration",
+		time.Minute,
+		"time the node is kept paused",
+	)
+	return cmd
+}
+
+func simulateDowntimeFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout+downtimeDuration)
+	info, err := cli.SimulateDowntime(ctx, nodeName, downtimeDuration)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("simulate downtime response: %+v", info)
+}
+
+func newAddNodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-node node-name [options]",
+		Short: "Add a new node to the network",
+		RunE:  addNodeFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().StringVar(
+		&luxdBinPath,
+		"node-path",
+		"",
+		"node binary path",
+	)
+	cmd.PersistentFlags().StringVar(
+		&addNodeConfig,
+		"node-config",
+		"",
+		"node config as string",
+	)
+	cmd.PersistentFlags().StringVar(
+		&pluginDir,
+		"plugin-dir",
+		"",
+		"[optional] plugin directory",
+	)
+	cmd.PersistentFlags().StringVar(
+		&chainConfigs,
+		"chain-configs",
+		"",
+		"[optional] JSON string of map from chain id to its config file contents",
+	)
+	cmd.PersistentFlags().StringVar(
+		&upgradeConfigs,
+		"upgrade-configs",
+		"",
+		"[optional] JSON string of map from chain id to its upgrade file contents",
+	)
+	cmd.PersistentFlags().StringVar(
+		&subnetConfigs,
+		"subnet-configs",
+		"",
+		"[optional] JSON string of map from subnet id to its config file contents",
+	)
+	return cmd
+}
+
+func addNodeFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	opts := []client.OpOption{
+		client.WithPluginDir(pluginDir),
+	}
+
+	if addNodeConfig != "" {
+		ux.Print(log, logging.Yellow.Wrap("WARNING: overriding node configs with custom provided config %s"), addNodeConfig)
+		// validate it's valid JSON
+		var js json.RawMessage
+		if err := json.Unmarshal([]byte(addNodeConfig), &js); err != nil {
+			return fmt.Errorf("failed to validate JSON for provided config file: %w", err)
+		}
+		opts = append(opts, client.WithGlobalNodeConfig(addNodeConfig))
+	}
+
+	if chainConfigs != "" {
+		chainConfigsMap := make(map[string]string)
+		if err := json.Unmarshal([]byte(chainConfigs), &chainConfigsMap); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithChainConfigs(chainConfigsMap))
+	}
+	if upgradeConfigs != "" {
+		upgradeConfigsMap := make(map[string]string)
+		if err := json.Unmarshal([]byte(upgradeConfigs), &upgradeConfigsMap); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithUpgradeConfigs(upgradeConfigsMap))
+	}
+	if subnetConfigs != "" {
+		subnetConfigsMap := make(map[string]string)
+		if err := json.Unmarshal([]byte(subnetConfigs), &subnetConfigsMap); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithSubnetConfigs(subnetConfigsMap))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	info, err := cli.AddNode(
+		ctx,
+		nodeName,
+		luxdBinPath,
+		opts...,
+	)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("add node response: %+v", info)
+}
+
+func newRestartNodeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restart-node node-name [options]",
+		Short: "Restarts a node.",
+		RunE:  restartNodeFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().StringVar(
+		&luxdBinPath,
+		"node-path",
+		"",
+		"node binary path",
+	)
+	cmd.PersistentFlags().StringVar(
+		&trackSubnets,
+		"whitelisted-subnets",
+		"",
+		"[optional] whitelisted subnets (comma-separated)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&pluginDir,
+		"plugin-dir",
+		"",
+		"[optional] plugin directory",
+	)
+	cmd.PersistentFlags().StringVar(
+		&chainConfigs,
+		"chain-configs",
+		"",
+		"[optional] JSON string of map from chain id to its config file contents",
+	)
+	cmd.PersistentFlags().StringVar(
+		&upgradeConfigs,
+		"upgrade-configs",
+		"",
+		"[optional] JSON string of map from chain id to its upgrade file contents",
+	)
+	cmd.PersistentFlags().StringVar(
+		&subnetConfigs,
+		"subnet-configs",
+		"",
+		"[optional] JSON string of map from subnet id to its config file contents",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&wipeDB,
+		"wipe-db",
+		false,
+		"[optional] remove the node db before restarting, keeping its staking keys",
+	)
+	return cmd
+}
+
+var wipeDB bool
+
+func restartNodeFunc(_ *cobra.Command, args []string) error {
 	// no validation for empty string required, as covered by `cobra.ExactArgs`
 	nodeName := args[0]
 	cli, err := newClient()
@@ -702,28 +3212,91 @@ func removeNodeFunc(_ *cobra.Command, args []string) error {
 	}
 	defer cli.Close()
 
+	opts := []client.OpOption{
+		client.WithExecPath(luxdBinPath),
+		client.WithPluginDir(pluginDir),
+		client.WithTrackSubnets(trackSubnets),
+		client.WithWipeDB(wipeDB),
+	}
+
+	if chainConfigs != "" {
+		chainConfigsMap := make(map[string]string)
+		if err := json.Unmarshal([]byte(chainConfigs), &chainConfigsMap); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithChainConfigs(chainConfigsMap))
+	}
+	if upgradeConfigs != "" {
+		upgradeConfigsMap := make(map[string]string)
+		if err := json.Unmarshal([]byte(upgradeConfigs), &upgradeConfigsMap); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithUpgradeConfigs(upgradeConfigsMap))
+	}
+	if subnetConfigs != "" {
+		subnetConfigsMap := make(map[string]string)
+		if err := json.Unmarshal([]byte(subnetConfigs), &subnetConfigsMap); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithSubnetConfigs(subnetConfigsMap))
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	info, err := cli.RemoveNode(ctx, nodeName)
+	info, err := cli.RestartNode(
+		ctx,
+		nodeName,
+		opts...,
+	)
 	cancel()
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("remove node response: %+v"), info)
-	return nil
+	return printResponse("restart node response: %+v", info)
 }
 
-func newPauseNodeCommand() *cobra.Command {
+var (
+	peerSendDelay     time.Duration
+	peerDuplicates    uint32
+	peerMalformedBody bool
+	peerWrongChainID  bool
+)
+
+func newAttachPeerCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "pause-node node-name [options]",
-		Short: "Pauses a node.",
-		RunE:  pauseNodeFunc,
+		Use:   "attach-peer node-name [options]",
+		Short: "Attaches a peer to the node.",
+		RunE:  attachPeerFunc,
 		Args:  cobra.ExactArgs(1),
 	}
+	cmd.PersistentFlags().DurationVar(
+		&peerSendDelay,
+		"send-delay",
+		0,
+		"[optional] time the peer waits before sending each message",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&peerDuplicates,
+		"duplicates",
+		0,
+		"[optional] number of extra copies the peer sends of each message, up to 100",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&peerMalformedBody,
+		"malformed-body",
+		false,
+		"[optional] true to make the peer corrupt the bytes of each message",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&peerWrongChainID,
+		"wrong-chain-id",
+		false,
+		"[optional] true to make the peer replace the chain ID of each message with a random one",
+	)
 	return cmd
 }
 
-func pauseNodeFunc(_ *cobra.Command, args []string) error {
+func attachPeerFunc(_ *cobra.Command, args []string) error {
 	// no validation for empty string required, as covered by `cobra.ExactArgs`
 	nodeName := args[0]
 	cli, err := newClient()
@@ -732,28 +3305,71 @@ func pauseNodeFunc(_ *cobra.Command, args []string) error {
 	}
 	defer cli.Close()
 
+	opts := []client.OpOption{}
+	if peerSendDelay != 0 || peerDuplicates != 0 || peerMalformedBody || peerWrongChainID {
+		opts = append(opts, client.WithPeerBehavior(&rpcpb.PeerBehavior{
+			SendDelay:     int64(peerSendDelay),
+			Duplicates:    peerDuplicates,
+			MalformedBody: peerMalformedBody,
+			WrongChainId:  peerWrongChainID,
+		}))
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	info, err := cli.PauseNode(ctx, nodeName)
+	resp, err := cli.AttachPeer(ctx, nodeName, opts...)
 	cancel()
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("pause node response: %+v"), info)
-	return nil
+	return printResponse("attach peer response: %+v", resp)
 }
 
-func newResumeNodeCommand() *cobra.Command {
+var (
+	peerID      string
+	msgOp       uint32
+	msgBytesB64 string
+)
+
+func newSendOutboundMessageCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "resume-node node-name [options]",
-		Short: "Resumes a node.",
-		RunE:  resumeNodeFunc,
-		Args:  cobra.ExactArgs(1),
+		Use:       "send-outbound-message node-name [options]",
+		Short:     "Sends an outbound message to an attached peer.",
+		RunE:      sendOutboundMessageFunc,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"node-name"},
+	}
+	cmd.PersistentFlags().StringVar(
+		&peerID,
+		"peer-id",
+		"",
+		"peer ID to send a message to",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&msgOp,
+		"message-op",
+		0,
+		"Message operation type",
+	)
+	cmd.PersistentFlags().StringVar(
+		&msgBytesB64,
+		"message-bytes-b64",
+		"",
+		"Message bytes in base64 encoding",
+	)
+	if err := cmd.MarkPersistentFlagRequired("peer-id"); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkPersistentFlagRequired("message-op"); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkPersistentFlagRequired("message-bytes-b64"); err != nil {
+		panic(err)
 	}
 	return cmd
 }
 
-func resumeNodeFunc(_ *cobra.Command, args []string) error {
+func sendOutboundMessageFunc(_ *cobra.Command, args []string) error {
 	// no validation for empty string required, as covered by `cobra.ExactArgs`
 	nodeName := args[0]
 	cli, err := newClient()
@@ -762,171 +3378,274 @@ func resumeNodeFunc(_ *cobra.Command, args []string) error {
 	}
 	defer cli.Close()
 
+	b, err := base64.StdEncoding.DecodeString(msgBytesB64)
+	if err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	info, err := cli.ResumeNode(ctx, nodeName)
+	resp, err := cli.SendOutboundMessage(ctx, nodeName, peerID, msgOp, b)
 	cancel()
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("resume node response: %+v"), info)
-	return nil
+	return printResponse("send outbound message response: %+v", resp)
 }
 
-func newAddNodeCommand() *cobra.Command {
+var (
+	peerMsgType         string
+	peerMsgChainID      string
+	peerMsgRequestID    uint32
+	peerMsgDeadline     time.Duration
+	peerMsgEngine       string
+	peerMsgContainerID  string
+	peerMsgContainerB64 string
+	peerMsgPreferredIDs []string
+	peerMsgAcceptedIDs  []string
+	peerMsgAppBytesB64  string
+)
+
+func newSendPeerMessageCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "add-node node-name [options]",
-		Short: "Add a new node to the network",
-		RunE:  addNodeFunc,
+		Use:   "send-peer-message node-name [options]",
+		Short: "Builds a message and sends it from an attached peer to the node.",
+		RunE:  sendPeerMessageFunc,
 		Args:  cobra.ExactArgs(1),
 	}
 	cmd.PersistentFlags().StringVar(
-		&luxdBinPath,
-		"node-path",
+		&peerID,
+		"peer-id",
 		"",
-		"node binary path",
+		"ID of the attached peer",
 	)
 	cmd.PersistentFlags().StringVar(
-		&addNodeConfig,
-		"node-config",
+		&peerMsgType,
+		"message-type",
 		"",
-		"node config as string",
+		"type of the message: pull-query, push-query, get, chits or app-request",
 	)
 	cmd.PersistentFlags().StringVar(
-		&pluginDir,
-		"plugin-dir",
+		&peerMsgChainID,
+		"chain-id",
 		"",
-		"[optional] plugin directory",
+		"ID of the chain the message is addressed to",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&peerMsgRequestID,
+		"request-id",
+		0,
+		"[optional] request ID of the message",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&peerMsgDeadline,
+		"deadline",
+		0,
+		"[optional] time the node has to respond to a request message",
 	)
 	cmd.PersistentFlags().StringVar(
-		&chainConfigs,
-		"chain-configs",
+		&peerMsgEngine,
+		"engine",
 		"",
-		"[optional] JSON string of map from chain id to its config file contents",
+		"[optional] consensus engine the message is addressed to: avalanche or snowman",
 	)
 	cmd.PersistentFlags().StringVar(
-		&upgradeConfigs,
-		"upgrade-configs",
+		&peerMsgContainerID,
+		"container-id",
 		"",
-		"[optional] JSON string of map from chain id to its upgrade file contents",
+		"container ID, for pull-query and get messages",
 	)
 	cmd.PersistentFlags().StringVar(
-		&subnetConfigs,
-		"subnet-configs",
+		&peerMsgContainerB64,
+		"container-b64",
 		"",
-		"[optional] JSON string of map from subnet id to its config file contents",
+		"container bytes in base64 encoding, for push-query messages",
 	)
+	cmd.PersistentFlags().StringSliceVar(
+		&peerMsgPreferredIDs,
+		"preferred-ids",
+		nil,
+		"preferred container IDs, for chits messages",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&peerMsgAcceptedIDs,
+		"accepted-ids",
+		nil,
+		"accepted container IDs, for chits messages",
+	)
+	cmd.PersistentFlags().StringVar(
+		&peerMsgAppBytesB64,
+		"app-bytes-b64",
+		"",
+		"app request bytes in base64 encoding, for app-request messages",
+	)
+	if err := cmd.MarkPersistentFlagRequired("peer-id"); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkPersistentFlagRequired("message-type"); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkPersistentFlagRequired("chain-id"); err != nil {
+		panic(err)
+	}
 	return cmd
 }
 
-func addNodeFunc(_ *cobra.Command, args []string) error {
+func sendPeerMessageFunc(_ *cobra.Command, args []string) error {
 	// no validation for empty string required, as covered by `cobra.ExactArgs`
 	nodeName := args[0]
-	cli, err := newClient()
-	if err != nil {
-		return err
-	}
-	defer cli.Close()
 
-	opts := []client.OpOption{
-		client.WithPluginDir(pluginDir),
+	req := &rpcpb.SendPeerMessageRequest{
+		NodeName:  nodeName,
+		PeerId:    peerID,
+		ChainId:   peerMsgChainID,
+		RequestId: peerMsgRequestID,
+		Deadline:  int64(peerMsgDeadline),
 	}
-
-	if addNodeConfig != "" {
-		ux.Print(log, logging.Yellow.Wrap("WARNING: overriding node configs with custom provided config %s"), addNodeConfig)
-		// validate it's valid JSON
-		var js json.RawMessage
-		if err := json.Unmarshal([]byte(addNodeConfig), &js); err != nil {
-			return fmt.Errorf("failed to validate JSON for provided config file: %w", err)
-		}
-		opts = append(opts, client.WithGlobalNodeConfig(addNodeConfig))
+	switch peerMsgEngine {
+	case "":
+	case "avalanche":
+		req.EngineType = rpcpb.EngineType_ENGINE_TYPE_AVALANCHE
+	case "snowman":
+		req.EngineType = rpcpb.EngineType_ENGINE_TYPE_SNOWMAN
+	default:
+		return fmt.Errorf("invalid engine %q", peerMsgEngine)
 	}
-
-	if chainConfigs != "" {
-		chainConfigsMap := make(map[string]string)
-		if err := json.Unmarshal([]byte(chainConfigs), &chainConfigsMap); err != nil {
-			return err
+	switch peerMsgType {
+	case "pull-query":
+		req.Message = &rpcpb.SendPeerMessageRequest_PullQuery{
+			PullQuery: &rpcpb.PullQueryMessage{ContainerId: peerMsgContainerID},
 		}
-		opts = append(opts, client.WithChainConfigs(chainConfigsMap))
-	}
-	if upgradeConfigs != "" {
-		upgradeConfigsMap := make(map[string]string)
-		if err := json.Unmarshal([]byte(upgradeConfigs), &upgradeConfigsMap); err != nil {
+	case "push-query":
+		container, err := base64.StdEncoding.DecodeString(peerMsgContainerB64)
+		if err != nil {
 			return err
 		}
-		opts = append(opts, client.WithUpgradeConfigs(upgradeConfigsMap))
-	}
-	if subnetConfigs != "" {
-		subnetConfigsMap := make(map[string]string)
-		if err := json.Unmarshal([]byte(subnetConfigs), &subnetConfigsMap); err != nil {
+		req.Message = &rpcpb.SendPeerMessageRequest_PushQuery{
+			PushQuery: &rpcpb.PushQueryMessage{Container: container},
+		}
+	case "get":
+		req.Message = &rpcpb.SendPeerMessageRequest_Get{
+			Get: &rpcpb.GetMessage{ContainerId: peerMsgContainerID},
+		}
+	case "chits":
+		req.Message = &rpcpb.SendPeerMessageRequest_Chits{
+			Chits: &rpcpb.ChitsMessage{
+				PreferredContainerIds: peerMsgPreferredIDs,
+				AcceptedContainerIds:  peerMsgAcceptedIDs,
+			},
+		}
+	case "app-request":
+		appBytes, err := base64.StdEncoding.DecodeString(peerMsgAppBytesB64)
+		if err != nil {
 			return err
 		}
-		opts = append(opts, client.WithSubnetConfigs(subnetConfigsMap))
+		req.Message = &rpcpb.SendPeerMessageRequest_AppRequest{
+			AppRequest: &rpcpb.AppRequestMessage{AppBytes: appBytes},
+		}
+	default:
+		return fmt.Errorf("invalid message type %q", peerMsgType)
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
 	}
+	defer cli.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	info, err := cli.AddNode(
-		ctx,
-		nodeName,
-		luxdBinPath,
-		opts...,
-	)
+	resp, err := cli.SendPeerMessage(ctx, req)
 	cancel()
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("add node response: %+v"), info)
-	return nil
+	return printResponse("send peer message response: %+v", resp)
 }
 
-func newRestartNodeCommand() *cobra.Command {
+func newSendAppRequestCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "restart-node node-name [options]",
-		Short: "Restarts a node.",
-		RunE:  restartNodeFunc,
+		Use:   "send-app-request node-name [options]",
+		Short: "Sends an app request to a chain from an attached peer, and prints the app response.",
+		RunE:  sendAppRequestFunc,
 		Args:  cobra.ExactArgs(1),
 	}
 	cmd.PersistentFlags().StringVar(
-		&luxdBinPath,
-		"node-path",
-		"",
-		"node binary path",
-	)
-	cmd.PersistentFlags().StringVar(
-		&trackSubnets,
-		"whitelisted-subnets",
+		&peerID,
+		"peer-id",
 		"",
-		"[optional] whitelisted subnets (comma-separated)",
+		"ID of the attached peer",
 	)
 	cmd.PersistentFlags().StringVar(
-		&pluginDir,
-		"plugin-dir",
+		&peerMsgChainID,
+		"chain-id",
 		"",
-		"[optional] plugin directory",
+		"ID of the chain the request is addressed to",
 	)
 	cmd.PersistentFlags().StringVar(
-		&chainConfigs,
-		"chain-configs",
+		&peerMsgAppBytesB64,
+		"app-bytes-b64",
 		"",
-		"[optional] JSON string of map from chain id to its config file contents",
+		"app request bytes in base64 encoding",
 	)
-	cmd.PersistentFlags().StringVar(
-		&upgradeConfigs,
-		"upgrade-configs",
-		"",
-		"[optional] JSON string of map from chain id to its upgrade file contents",
+	cmd.PersistentFlags().DurationVar(
+		&peerMsgDeadline,
+		"deadline",
+		0,
+		"[optional] time to wait for the app response",
 	)
+	if err := cmd.MarkPersistentFlagRequired("peer-id"); err != nil {
+		panic(err)
+	}
+	if err := cmd.MarkPersistentFlagRequired("chain-id"); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func sendAppRequestFunc(_ *cobra.Command, args []string) error {
+	// no validation for empty string required, as covered by `cobra.ExactArgs`
+	nodeName := args[0]
+	appBytes, err := base64.StdEncoding.DecodeString(peerMsgAppBytesB64)
+	if err != nil {
+		return err
+	}
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.SendAppRequest(ctx, nodeName, peerID, peerMsgChainID, appBytes, peerMsgDeadline)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	return printResponse("send app request response: %+v", resp)
+}
+
+func newStreamPeerMessagesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stream-peer-messages node-name [options]",
+		Short: "Prints the messages received by an attached peer, until interrupted.",
+		RunE:  streamPeerMessagesFunc,
+		Args:  cobra.ExactArgs(1),
+	}
 	cmd.PersistentFlags().StringVar(
-		&subnetConfigs,
-		"subnet-configs",
+		&peerID,
+		"peer-id",
 		"",
-		"[optional] JSON string of map from subnet id to its config file contents",
+		"ID of the attached peer",
 	)
+	if err := cmd.MarkPersistentFlagRequired("peer-id"); err != nil {
+		panic(err)
+	}
 	return cmd
 }
 
-func restartNodeFunc(_ *cobra.Command, args []string) error {
+func streamPeerMessagesFunc(_ *cobra.Command, args []string) error {
 	// no validation for empty string required, as covered by `cobra.ExactArgs`
 	nodeName := args[0]
 	cli, err := newClient()
@@ -935,62 +3654,98 @@ func restartNodeFunc(_ *cobra.Command, args []string) error {
 	}
 	defer cli.Close()
 
-	opts := []client.OpOption{
-		client.WithExecPath(luxdBinPath),
-		client.WithPluginDir(pluginDir),
-		client.WithTrackSubnets(trackSubnets),
-	}
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
 
-	if chainConfigs != "" {
-		chainConfigsMap := make(map[string]string)
-		if err := json.Unmarshal([]byte(chainConfigs), &chainConfigsMap); err != nil {
-			return err
-		}
-		opts = append(opts, client.WithChainConfigs(chainConfigsMap))
-	}
-	if upgradeConfigs != "" {
-		upgradeConfigsMap := make(map[string]string)
-		if err := json.Unmarshal([]byte(upgradeConfigs), &upgradeConfigsMap); err != nil {
-			return err
+	var printErr error
+	err = cli.StreamPeerMessages(ctx, nodeName, peerID, func(msg *rpcpb.StreamPeerMessagesResponse) {
+		if printErr != nil {
+			return
 		}
-		opts = append(opts, client.WithUpgradeConfigs(upgradeConfigsMap))
+		printErr = printResponse("peer message: %+v", msg)
+	})
+	if err != nil {
+		return err
 	}
-	if subnetConfigs != "" {
-		subnetConfigsMap := make(map[string]string)
-		if err := json.Unmarshal([]byte(subnetConfigs), &subnetConfigsMap); err != nil {
-			return err
-		}
-		opts = append(opts, client.WithSubnetConfigs(subnetConfigsMap))
+	return printErr
+}
+
+func newStreamProgressCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stream-progress [options]",
+		Short: "Prints the progress of the network operations (start, blockchain creation), until interrupted.",
+		RunE:  streamProgressFunc,
+		Args:  cobra.ExactArgs(0),
 	}
+	return cmd
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	info, err := cli.RestartNode(
-		ctx,
-		nodeName,
-		opts...,
-	)
-	cancel()
+func streamProgressFunc(*cobra.Command, []string) error {
+	cli, err := newClient()
 	if err != nil {
 		return err
 	}
+	defer cli.Close()
 
-	ux.Print(log, logging.Green.Wrap("restart node response: %+v"), info)
-	return nil
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	var printErr error
+	err = cli.StreamProgress(ctx, func(event *rpcpb.StreamProgressResponse) {
+		if printErr != nil {
+			return
+		}
+		printErr = printResponse("progress: %+v", event)
+	})
+	if err != nil {
+		return err
+	}
+	return printErr
 }
 
-func newAttachPeerCommand() *cobra.Command {
+var (
+	peerMessagesPath string
+	stopRecording    bool
+	keepTiming       bool
+)
+
+func newRecordPeerMessagesCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "attach-peer node-name [options]",
-		Short: "Attaches a peer to the node.",
-		RunE:  attachPeerFunc,
+		Use:   "record-peer-messages node-name [options]",
+		Short: "Records the messages exchanged by an attached peer to a file on the server host.",
+		RunE:  recordPeerMessagesFunc,
 		Args:  cobra.ExactArgs(1),
 	}
+	cmd.PersistentFlags().StringVar(
+		&peerID,
+		"peer-id",
+		"",
+		"ID of the attached peer",
+	)
+	cmd.PersistentFlags().StringVar(
+		&peerMessagesPath,
+		"path",
+		"",
+		"path of the file to record the messages to",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&stopRecording,
+		"stop",
+		false,
+		"true to stop recording",
+	)
+	if err := cmd.MarkPersistentFlagRequired("peer-id"); err != nil {
+		panic(err)
+	}
 	return cmd
 }
 
-func attachPeerFunc(_ *cobra.Command, args []string) error {
+func recordPeerMessagesFunc(_ *cobra.Command, args []string) error {
 	// no validation for empty string required, as covered by `cobra.ExactArgs`
 	nodeName := args[0]
+	if stopRecording == (peerMessagesPath != "") {
+		return errors.New("exactly one of --path and --stop must be given")
+	}
 	cli, err := newClient()
 	if err != nil {
 		return err
@@ -998,61 +3753,50 @@ func attachPeerFunc(_ *cobra.Command, args []string) error {
 	defer cli.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	resp, err := cli.AttachPeer(ctx, nodeName)
+	resp, err := cli.RecordPeerMessages(ctx, nodeName, peerID, peerMessagesPath)
 	cancel()
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("attach peer response: %+v"), resp)
-	return nil
+	return printResponse("record peer messages response: %+v", resp)
 }
 
-var (
-	peerID      string
-	msgOp       uint32
-	msgBytesB64 string
-)
-
-func newSendOutboundMessageCommand() *cobra.Command {
+func newReplayPeerMessagesCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:       "send-outbound-message node-name [options]",
-		Short:     "Sends an outbound message to an attached peer.",
-		RunE:      sendOutboundMessageFunc,
-		Args:      cobra.ExactArgs(1),
-		ValidArgs: []string{"node-name"},
+		Use:   "replay-peer-messages node-name [options]",
+		Short: "Sends the messages of a recorded session to the node through an attached peer.",
+		RunE:  replayPeerMessagesFunc,
+		Args:  cobra.ExactArgs(1),
 	}
 	cmd.PersistentFlags().StringVar(
 		&peerID,
 		"peer-id",
 		"",
-		"peer ID to send a message to",
-	)
-	cmd.PersistentFlags().Uint32Var(
-		&msgOp,
-		"message-op",
-		0,
-		"Message operation type",
+		"ID of the attached peer",
 	)
 	cmd.PersistentFlags().StringVar(
-		&msgBytesB64,
-		"message-bytes-b64",
+		&peerMessagesPath,
+		"path",
 		"",
-		"Message bytes in base64 encoding",
+		"path of the recorded session file on the server host",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&keepTiming,
+		"keep-timing",
+		false,
+		"[optional] true to wait between messages as much as when recorded",
 	)
 	if err := cmd.MarkPersistentFlagRequired("peer-id"); err != nil {
 		panic(err)
 	}
-	if err := cmd.MarkPersistentFlagRequired("message-op"); err != nil {
-		panic(err)
-	}
-	if err := cmd.MarkPersistentFlagRequired("message-bytes-b64"); err != nil {
+	if err := cmd.MarkPersistentFlagRequired("path"); err != nil {
 		panic(err)
 	}
 	return cmd
 }
 
-func sendOutboundMessageFunc(_ *cobra.Command, args []string) error {
+func replayPeerMessagesFunc(_ *cobra.Command, args []string) error {
 	// no validation for empty string required, as covered by `cobra.ExactArgs`
 	nodeName := args[0]
 	cli, err := newClient()
@@ -1061,20 +3805,14 @@ func sendOutboundMessageFunc(_ *cobra.Command, args []string) error {
 	}
 	defer cli.Close()
 
-	b, err := base64.StdEncoding.DecodeString(msgBytesB64)
-	if err != nil {
-		return err
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	resp, err := cli.SendOutboundMessage(ctx, nodeName, peerID, msgOp, b)
+	resp, err := cli.ReplayPeerMessages(ctx, nodeName, peerID, peerMessagesPath, keepTiming)
 	cancel()
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("send outbound message response: %+v"), resp)
-	return nil
+	return printResponse("replay peer messages response: %+v", resp)
 }
 
 func newStopCommand() *cobra.Command {
@@ -1084,6 +3822,18 @@ func newStopCommand() *cobra.Command {
 		RunE:  stopFunc,
 		Args:  cobra.ExactArgs(0),
 	}
+	cmd.PersistentFlags().BoolVar(
+		&nonBeaconsFirst,
+		"non-beacons-first",
+		false,
+		"[optional] stop the non beacon nodes before the beacon ones",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&nodeStopTimeout,
+		"node-stop-timeout",
+		0,
+		"[optional] time given to each node to exit before being killed (defaults to the server one)",
+	)
 	return cmd
 }
 
@@ -1095,14 +3845,17 @@ func stopFunc(*cobra.Command, []string) error {
 	defer cli.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	info, err := cli.Stop(ctx)
+	info, err := cli.Stop(
+		ctx,
+		client.WithNonBeaconsFirst(nonBeaconsFirst),
+		client.WithNodeStopTimeout(nodeStopTimeout),
+	)
 	cancel()
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("stop response: %+v"), info)
-	return nil
+	return printResponse("stop response: %+v", info)
 }
 
 func newSaveSnapshotCommand() *cobra.Command {
@@ -1112,6 +3865,12 @@ func newSaveSnapshotCommand() *cobra.Command {
 		RunE:  saveSnapshotFunc,
 		Args:  cobra.ExactArgs(1),
 	}
+	cmd.PersistentFlags().StringVar(
+		&snapshotPassphrase,
+		"passphrase",
+		os.Getenv(constants.SnapshotPassphraseEnvVar),
+		"[optional] save the snapshot as an archive encrypted with this passphrase (defaults to $"+constants.SnapshotPassphraseEnvVar+")",
+	)
 	return cmd
 }
 
@@ -1123,14 +3882,13 @@ func saveSnapshotFunc(_ *cobra.Command, args []string) error {
 	defer cli.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	resp, err := cli.SaveSnapshot(ctx, args[0])
+	resp, err := cli.SaveSnapshot(ctx, args[0], client.WithSnapshotPassphrase(snapshotPassphrase))
 	cancel()
 	if err != nil {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("save-snapshot response: %+v"), resp)
-	return nil
+	return printResponse("save-snapshot response: %+v", resp)
 }
 
 func newLoadSnapshotCommand() *cobra.Command {
@@ -1188,6 +3946,18 @@ func newLoadSnapshotCommand() *cobra.Command {
 		false,
 		"true to reassign snapshot ports if already taken",
 	)
+	cmd.PersistentFlags().StringVar(
+		&backendName,
+		"backend",
+		"",
+		"[optional] name of the backend creating the network (default \"local\")",
+	)
+	cmd.PersistentFlags().StringVar(
+		&snapshotPassphrase,
+		"passphrase",
+		os.Getenv(constants.SnapshotPassphraseEnvVar),
+		"[optional] passphrase of the snapshot, if it was saved encrypted (defaults to $"+constants.SnapshotPassphraseEnvVar+")",
+	)
 	return cmd
 }
 
@@ -1203,6 +3973,8 @@ func loadSnapshotFunc(_ *cobra.Command, args []string) error {
 		client.WithPluginDir(pluginDir),
 		client.WithRootDataDir(rootDataDir),
 		client.WithReassignPortsIfUsed(reassignPortsIfUsed),
+		client.WithBackend(backendName),
+		client.WithSnapshotPassphrase(snapshotPassphrase),
 	}
 
 	if chainConfigs != "" {
@@ -1245,8 +4017,7 @@ func loadSnapshotFunc(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("load-snapshot response: %+v"), resp)
-	return nil
+	return printResponse("load-snapshot response: %+v", resp)
 }
 
 func newRemoveSnapshotCommand() *cobra.Command {
@@ -1273,8 +4044,7 @@ func removeSnapshotFunc(_ *cobra.Command, args []string) error {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("remove-snapshot response: %+v"), resp)
-	return nil
+	return printResponse("remove-snapshot response: %+v", resp)
 }
 
 func newGetSnapshotNamesCommand() *cobra.Command {
@@ -1300,17 +4070,25 @@ func getSnapshotNamesFunc(*cobra.Command, []string) error {
 		return err
 	}
 
-	ux.Print(log, logging.Green.Wrap("Snapshots: %s"), snapshotNames)
-	return nil
+	return printResponse("Snapshots: %s", snapshotNames)
 }
 
 func newClient() (client.Client, error) {
 	if err := setLogs(); err != nil {
 		return nil, err
 	}
+	versionCheck := client.VersionCheckWarn
+	if strictVersionCheck {
+		versionCheck = client.VersionCheckStrict
+	}
 	return client.New(client.Config{
-		Endpoint:    endpoint,
-		DialTimeout: dialTimeout,
+		Endpoint:     endpoint,
+		GRPCSocket:   grpcSocket,
+		DialTimeout:  dialTimeout,
+		NetworkName:  networkName,
+		VersionCheck: versionCheck,
+		Token:        apiToken,
+		RetryPolicy:  client.RetryPolicy{MaxAttempts: maxAttempts},
 	}, log)
 }
 
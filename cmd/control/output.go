@@ -0,0 +1,54 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package control
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/luxdefi/netrunner/ux"
+	"github.com/luxdefi/node/utils/logging"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var outputFormat string
+
+func checkOutputFormat() error {
+	switch outputFormat {
+	case outputText, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q, expected %q or %q", outputFormat, outputText, outputJSON)
+	}
+}
+
+// Prints the RPC response [resp]. In JSON output mode, the raw response
+// is written to stdout as JSON, otherwise it is logged using [format].
+func printResponse(format string, resp interface{}) error {
+	if outputFormat != outputJSON {
+		ux.Print(log, logging.Green.Wrap(format), resp)
+		return nil
+	}
+	var (
+		respBytes []byte
+		err       error
+	)
+	if msg, ok := resp.(proto.Message); ok {
+		respBytes, err = protojson.Marshal(msg)
+	} else {
+		respBytes, err = json.Marshal(resp)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(respBytes))
+	return err
+}
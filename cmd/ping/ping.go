@@ -5,6 +5,7 @@ package ping
 
 import (
 	"context"
+	"os"
 	"time"
 
 	"github.com/luxdefi/netrunner/client"
@@ -16,7 +17,9 @@ import (
 
 var (
 	logLevel       string
+	logFormat      string
 	endpoint       string
+	grpcSocket     string
 	dialTimeout    time.Duration
 	requestTimeout time.Duration
 )
@@ -29,7 +32,9 @@ func NewCommand() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVar(&logLevel, "log-level", logging.Info.String(), "log level")
+	cmd.PersistentFlags().StringVar(&logFormat, "log-format", "auto", "log format: auto, plain, colors or json")
 	cmd.PersistentFlags().StringVar(&endpoint, "endpoint", "0.0.0.0:8080", "server endpoint")
+	cmd.PersistentFlags().StringVar(&grpcSocket, "grpc-socket", "", "server unix domain socket path (overrides --endpoint)")
 	cmd.PersistentFlags().DurationVar(&dialTimeout, "dial-timeout", 10*time.Second, "server dial timeout")
 	cmd.PersistentFlags().DurationVar(&requestTimeout, "request-timeout", 10*time.Second, "client request timeout")
 
@@ -41,7 +46,12 @@ func pingFunc(*cobra.Command, []string) error {
 	if err != nil {
 		return err
 	}
+	format, err := logging.ToFormat(logFormat, os.Stdout.Fd())
+	if err != nil {
+		return err
+	}
 	lcfg := logging.Config{
+		LogFormat:    format,
 		DisplayLevel: lvl,
 		LogLevel:     logging.Off,
 	}
@@ -53,6 +63,7 @@ func pingFunc(*cobra.Command, []string) error {
 
 	cli, err := client.New(client.Config{
 		Endpoint:    endpoint,
+		GRPCSocket:  grpcSocket,
 		DialTimeout: dialTimeout,
 	}, log)
 	if err != nil {
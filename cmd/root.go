@@ -7,14 +7,35 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/luxdefi/netrunner/client"
+	"github.com/luxdefi/netrunner/cmd/ci"
 	"github.com/luxdefi/netrunner/cmd/control"
 	"github.com/luxdefi/netrunner/cmd/ping"
+	"github.com/luxdefi/netrunner/cmd/scenario"
 	"github.com/luxdefi/netrunner/cmd/server"
+	"github.com/luxdefi/netrunner/rpcpb"
 	"github.com/spf13/cobra"
 )
 
 var Version = ""
 
+// exit codes for the server error codes, so scripts can
+// branch on the failure cause. Other failures exit with 1.
+var exitCodes = map[rpcpb.ErrorCode]int{
+	rpcpb.ErrorCode_PORT_IN_USE:          10,
+	rpcpb.ErrorCode_BINARY_NOT_FOUND:     11,
+	rpcpb.ErrorCode_UNHEALTHY_TIMEOUT:    12,
+	rpcpb.ErrorCode_TX_FAILED:            13,
+	rpcpb.ErrorCode_NOT_BOOTSTRAPPED:     14,
+	rpcpb.ErrorCode_ALREADY_BOOTSTRAPPED: 15,
+	rpcpb.ErrorCode_NOT_FOUND:            16,
+	rpcpb.ErrorCode_INVALID_ARGUMENT:     17,
+	rpcpb.ErrorCode_RATE_LIMITED:         18,
+	rpcpb.ErrorCode_UNAUTHENTICATED:      19,
+	rpcpb.ErrorCode_PERMISSION_DENIED:    20,
+	rpcpb.ErrorCode_NODE_PAUSED:          21,
+}
+
 var rootCmd = &cobra.Command{
 	Use:        "netrunner",
 	Short:      "netrunner commands",
@@ -31,13 +52,22 @@ func init() {
 		server.NewCommand(),
 		ping.NewCommand(),
 		control.NewCommand(),
+		scenario.NewCommand(),
+		ci.NewCommand(),
 	)
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "netrunner failed %v\n", err)
-		os.Exit(1)
+		os.Exit(getExitCode(err))
 	}
 	os.Exit(0)
 }
+
+func getExitCode(err error) int {
+	if exitCode, ok := exitCodes[client.GetErrorCode(err)]; ok {
+		return exitCode
+	}
+	return 1
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scenario
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/luxdefi/netrunner/client"
+	"github.com/luxdefi/netrunner/scenario"
+	"github.com/luxdefi/netrunner/utils/constants"
+	"github.com/luxdefi/netrunner/ux"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/spf13/cobra"
+)
+
+var errScenarioFailed = errors.New("scenario failed")
+
+var (
+	logLevel    string
+	logFormat   string
+	endpoint    string
+	grpcSocket  string
+	networkName string
+	dialTimeout time.Duration
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scenario [options]",
+		Short: "Run scenarios against the server.",
+	}
+
+	cmd.PersistentFlags().StringVar(&logLevel, "log-level", logging.Info.String(), "log level")
+	cmd.PersistentFlags().StringVar(&logFormat, "log-format", "auto", "log format: auto, plain, colors or json")
+	cmd.PersistentFlags().StringVar(&endpoint, "endpoint", "0.0.0.0:8080", "server endpoint")
+	cmd.PersistentFlags().StringVar(&grpcSocket, "grpc-socket", "", "server unix domain socket path (overrides --endpoint)")
+	cmd.PersistentFlags().StringVar(&networkName, "network-name", "", "name of the network targeted by the scenario (default \"default\")")
+	cmd.PersistentFlags().DurationVar(&dialTimeout, "dial-timeout", 10*time.Second, "server dial timeout")
+
+	cmd.AddCommand(newRunCommand())
+	return cmd
+}
+
+func newRunCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run scenario-file [options]",
+		Short: "Runs the steps of a YAML scenario file.",
+		RunE:  runFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+func runFunc(_ *cobra.Command, args []string) error {
+	sc, err := scenario.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	lvl, err := logging.ToLevel(logLevel)
+	if err != nil {
+		return err
+	}
+	format, err := logging.ToFormat(logFormat, os.Stdout.Fd())
+	if err != nil {
+		return err
+	}
+	lcfg := logging.Config{
+		LogFormat:    format,
+		DisplayLevel: lvl,
+		LogLevel:     logging.Off,
+	}
+	logFactory := logging.NewFactory(lcfg)
+	log, err := logFactory.Make(constants.LogNameControl)
+	if err != nil {
+		return err
+	}
+
+	cli, err := client.New(client.Config{
+		Endpoint:    endpoint,
+		GRPCSocket:  grpcSocket,
+		DialTimeout: dialTimeout,
+		NetworkName: networkName,
+	}, log)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	report := scenario.Run(ctx, cli, log, sc)
+
+	ux.Print(log, logging.Blue.Wrap("scenario %q finished in %s"), report.Name, report.Duration.Round(time.Millisecond))
+	for _, step := range report.Steps {
+		switch {
+		case step.Skipped:
+			ux.Print(log, logging.Yellow.Wrap("  SKIP  %s"), step.Name)
+		case step.Err != nil:
+			ux.Print(log, logging.Red.Wrap("  FAIL  %s (%s): %s"), step.Name, step.Duration.Round(time.Millisecond), step.Err)
+		default:
+			ux.Print(log, logging.Green.Wrap("  PASS  %s (%s)"), step.Name, step.Duration.Round(time.Millisecond))
+		}
+	}
+	if report.Failed() {
+		return errScenarioFailed
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	// set on the environment of the server process started by --daemonize,
+	// so it doesn't daemonize again
+	daemonizedEnvVar = "NETRUNNER_DAEMONIZED"
+	// socket systemd expects the service state at, for Type=notify services
+	notifySocketEnvVar = "NOTIFY_SOCKET"
+
+	sdNotifyReady    = "READY=1"
+	sdNotifyStopping = "STOPPING=1"
+)
+
+var errServerRunning = errors.New("server already running")
+
+// Starts the server again in the background, detached from the terminal,
+// and returns its PID. Returns 0 if this process is the one started in
+// the background.
+func daemonize() (int, error) {
+	if os.Getenv(daemonizedEnvVar) != "" {
+		return 0, nil
+	}
+	execPath, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	cmd := exec.Command(execPath, os.Args[1:]...) //nolint
+	cmd.Env = append(os.Environ(), daemonizedEnvVar+"=1")
+	// the server logs are written to the log dir, so the standard
+	// streams are left to /dev/null
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+	pid := cmd.Process.Pid
+	return pid, cmd.Process.Release()
+}
+
+// Writes the PID of this process to [path]. Fails if the file holds
+// the PID of another running process.
+func writePidFile(path string) error {
+	pidBytes, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		pid, err := strconv.Atoi(strings.TrimSpace(string(pidBytes)))
+		// signal 0 only checks the process exists
+		if err == nil && pid != os.Getpid() && syscall.Kill(pid, 0) == nil {
+			return fmt.Errorf("%w with pid %d (pid file %s)", errServerRunning, pid, path)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644)
+}
+
+// Sends [state] to systemd, if the server runs as a Type=notify service.
+// See sd_notify(3).
+func sdNotify(state string) error {
+	socketPath := os.Getenv(notifySocketEnvVar)
+	if socketPath == "" {
+		return nil
+	}
+	// abstract socket
+	if strings.HasPrefix(socketPath, "@") {
+		socketPath = "\x00" + socketPath[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
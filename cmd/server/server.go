@@ -5,6 +5,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 	"github.com/luxdefi/netrunner/utils"
 	"github.com/luxdefi/netrunner/utils/constants"
 	"github.com/luxdefi/node/utils/logging"
+	"github.com/luxdefi/node/utils/units"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
@@ -27,13 +29,35 @@ const serverRootDirPrefix = "server"
 
 var (
 	logLevel           string
+	logFormat          string
 	logDir             string
 	port               string
 	gwPort             string
+	grpcSocket         string
 	gwDisabled         bool
 	dialTimeout        time.Duration
 	disableNodesOutput bool
 	snapshotsDir       string
+	dashboard          bool
+	grpcWeb            bool
+	corsAllowedOrigins []string
+	rateLimit          float64
+	rateLimitBurst     int
+	maxConcurrentOps   int
+	maxQueuedOps       int
+	auditLogPath       string
+	auditLogDisabled   bool
+	adminTokens        []string
+	readOnlyTokens     []string
+	dataDirMaxSizeMB   uint64
+	dataDirMaxAge      time.Duration
+	logForwardLokiURL  string
+	logForwardSyslog   string
+	logForwardFile     string
+	daemon             bool
+	manifestPath       string
+	nodeDirsAllowed    []string
+	pidFile            string
 )
 
 func NewCommand() *cobra.Command {
@@ -45,18 +69,57 @@ func NewCommand() *cobra.Command {
 	}
 
 	cmd.PersistentFlags().StringVar(&logLevel, "log-level", logging.Info.String(), "log level for server logs")
+	cmd.PersistentFlags().StringVar(&logFormat, "log-format", "auto", "format of the server logs: auto, plain, colors or json")
 	cmd.PersistentFlags().StringVar(&logDir, "log-dir", "", "log directory")
 	cmd.PersistentFlags().StringVar(&port, "port", ":8080", "server port")
 	cmd.PersistentFlags().StringVar(&gwPort, "grpc-gateway-port", ":8081", "grpc-gateway server port")
+	cmd.PersistentFlags().StringVar(&grpcSocket, "grpc-socket", "", "unix domain socket path for the gRPC server (overrides --port)")
 	cmd.PersistentFlags().BoolVar(&gwDisabled, "disable-grpc-gateway", false, "true to disable grpc-gateway server (overrides --grpc-gateway-port)")
 	cmd.PersistentFlags().DurationVar(&dialTimeout, "dial-timeout", 10*time.Second, "server dial timeout")
 	cmd.PersistentFlags().BoolVar(&disableNodesOutput, "disable-nodes-output", false, "true to disable nodes stdout/stderr")
 	cmd.PersistentFlags().StringVar(&snapshotsDir, "snapshots-dir", "", "directory for snapshots")
+	cmd.PersistentFlags().BoolVar(&dashboard, "dashboard", false, "true to serve a web dashboard at /dashboard/ on the grpc-gateway port")
+	cmd.PersistentFlags().BoolVar(&grpcWeb, "grpc-web", false, "true to serve the gRPC services with grpc-web on the grpc-gateway port")
+	cmd.PersistentFlags().StringSliceVar(&corsAllowedOrigins, "cors-allowed-origins", nil, "origins browsers may call the grpc-gateway port from (\"*\" allows all)")
+	cmd.PersistentFlags().Float64Var(&rateLimit, "rate-limit", 0, "requests per second allowed for each client (0 to disable)")
+	cmd.PersistentFlags().IntVar(&rateLimitBurst, "rate-limit-burst", 20, "requests a client can burst over --rate-limit")
+	cmd.PersistentFlags().IntVar(&maxConcurrentOps, "max-concurrent-heavy-ops", 1, "max number of start and load-snapshot operations in progress (0 to disable)")
+	cmd.PersistentFlags().IntVar(&maxQueuedOps, "max-queued-heavy-ops", 8, "max number of start and load-snapshot operations waiting, before new ones are rejected")
+	cmd.PersistentFlags().StringVar(&auditLogPath, "audit-log", "", "file the control calls are appended to (defaults to audit.jsonl in the log directory)")
+	cmd.PersistentFlags().BoolVar(&auditLogDisabled, "disable-audit-log", false, "true to disable the audit log of control calls")
+	cmd.PersistentFlags().StringSliceVar(&adminTokens, "admin-tokens", nil, "API tokens allowed to call every method (enables auth)")
+	cmd.PersistentFlags().StringSliceVar(&readOnlyTokens, "read-only-tokens", nil, "API tokens only allowed to read the server state (enables auth)")
+	cmd.PersistentFlags().Uint64Var(&dataDirMaxSizeMB, "data-dir-max-size", 0, "max total size in MB of the dirs of old networks, removing the oldest first (0 to disable)")
+	cmd.PersistentFlags().DurationVar(&dataDirMaxAge, "data-dir-max-age", 0, "max age of the dirs of old networks (0 to disable)")
+	cmd.PersistentFlags().StringVar(&logForwardLokiURL, "log-forward-loki-url", "", "base URL of a Loki server to push the node logs to (e.g. http://localhost:3100)")
+	cmd.PersistentFlags().StringVar(&logForwardSyslog, "log-forward-syslog", "", "syslog to send the node logs to, either 'local' or an address such as udp://localhost:514")
+	cmd.PersistentFlags().StringVar(&logForwardFile, "log-forward-file", "", "file to append the node logs to as JSON lines")
+	cmd.PersistentFlags().BoolVar(&daemon, "daemonize", false, "true to run the server in the background, detached from the terminal")
+	cmd.PersistentFlags().StringVar(&pidFile, "pid-file", "", "file to write the server PID to, removed on exit")
+	cmd.PersistentFlags().StringVar(&manifestPath, "manifest-path", "", "file to also write the JSON manifest of the running network endpoints to, removed when it stops. It is always written to the network root data dir")
+	cmd.PersistentFlags().StringSliceVar(&nodeDirsAllowed, "node-dirs-allowed", nil, "dirs the node dirs of a start request dir layout may be placed under, besides the network root data dir")
 
 	return cmd
 }
 
 func serverFunc(*cobra.Command, []string) (err error) {
+	if daemon {
+		pid, err := daemonize()
+		if err != nil {
+			return err
+		}
+		if pid != 0 {
+			fmt.Printf("server running in the background with pid %d\n", pid)
+			return nil
+		}
+	}
+	if pidFile != "" {
+		if err := writePidFile(pidFile); err != nil {
+			return err
+		}
+		defer os.Remove(pidFile)
+	}
+
 	if logDir == "" {
 		anrRootDir := filepath.Join(os.TempDir(), constants.RootDirPrefix)
 		err = os.MkdirAll(anrRootDir, os.ModePerm)
@@ -75,10 +138,16 @@ func serverFunc(*cobra.Command, []string) (err error) {
 		return err
 	}
 
+	logFormat, err := logging.ToFormat(logFormat, os.Stdout.Fd())
+	if err != nil {
+		return err
+	}
+
 	logFactory := logging.NewFactory(logging.Config{
 		RotatingWriterConfig: logging.RotatingWriterConfig{
 			Directory: logDir,
 		},
+		LogFormat:    logFormat,
 		DisplayLevel: logLevel,
 		LogLevel:     logLevel,
 	})
@@ -87,18 +156,47 @@ func serverFunc(*cobra.Command, []string) (err error) {
 		return err
 	}
 
+	switch {
+	case auditLogDisabled:
+		auditLogPath = ""
+	case auditLogPath == "":
+		auditLogPath = filepath.Join(logDir, constants.AuditLogFileName)
+	}
+
 	s, err := server.New(server.Config{
-		Port:                port,
-		GwPort:              gwPort,
-		GwDisabled:          gwDisabled,
-		DialTimeout:         dialTimeout,
-		RedirectNodesOutput: !disableNodesOutput,
-		SnapshotsDir:        snapshotsDir,
-		LogLevel:            logLevel,
+		Port:                  port,
+		GwPort:                gwPort,
+		GRPCSocket:            grpcSocket,
+		GwDisabled:            gwDisabled,
+		DialTimeout:           dialTimeout,
+		RedirectNodesOutput:   !disableNodesOutput,
+		SnapshotsDir:          snapshotsDir,
+		LogLevel:              logLevel,
+		DashboardEnabled:      dashboard,
+		GRPCWebEnabled:        grpcWeb,
+		CORSAllowedOrigins:    corsAllowedOrigins,
+		RateLimit:             rateLimit,
+		RateLimitBurst:        rateLimitBurst,
+		MaxConcurrentHeavyOps: maxConcurrentOps,
+		MaxQueuedHeavyOps:     maxQueuedOps,
+		AuditLogPath:          auditLogPath,
+		AdminTokens:           adminTokens,
+		ReadOnlyTokens:        readOnlyTokens,
+		DataDirMaxSize:        dataDirMaxSizeMB * units.MiB,
+		DataDirMaxAge:         dataDirMaxAge,
+		LogForwardLokiURL:     logForwardLokiURL,
+		LogForwardSyslogAddr:  logForwardSyslog,
+		LogForwardFile:        logForwardFile,
+		ManifestPath:          manifestPath,
+		NodeDirsAllowed:       nodeDirsAllowed,
 	}, log)
 	if err != nil {
 		return err
 	}
+	// the gRPC server listener is bound, so clients can connect
+	if err := sdNotify(sdNotifyReady); err != nil {
+		log.Warn("couldn't notify systemd", zap.Error(err))
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -115,6 +213,9 @@ func serverFunc(*cobra.Command, []string) (err error) {
 	case sig := <-sigChan:
 		// Got a SIGINT or SIGTERM; stop the server and wait for it to finish.
 		log.Warn("signal received: closing server", zap.String("signal", sig.String()))
+		if err := sdNotify(sdNotifyStopping); err != nil {
+			log.Warn("couldn't notify systemd", zap.Error(err))
+		}
 		cancel()
 		waitForServerStop := <-errChan
 		log.Warn("closed server", zap.Error(waitForServerStop))
@@ -0,0 +1,202 @@
+package local
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/node/config"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/logging"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// Registers [alias] for [chainID] on all running nodes. The alias is
+// also kept at network level so it is applied to nodes started later on.
+func (ln *localNetwork) AddBlockchainAlias(ctx context.Context, chainID ids.ID, alias string) error {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	if err := ln.aliasChain(ctx, chainID, alias); err != nil {
+		return err
+	}
+	return ln.persistNetworkState()
+}
+
+// Removes [alias] of [chainID]. As nodes can't drop chain aliases at runtime,
+// all running nodes are restarted.
+func (ln *localNetwork) RemoveBlockchainAlias(ctx context.Context, chainID ids.ID, alias string) error {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	if err := removeAlias(ln.chainAliases, chainID, alias); err != nil {
+		return err
+	}
+	if err := ln.persistNetworkState(); err != nil {
+		return err
+	}
+	ln.log.Info(logging.Green.Wrap("removed blockchain alias"), zap.String("alias", alias), zap.String("chain-id", chainID.String()))
+	return ln.restartAllNodes(ctx)
+}
+
+// Registers [alias] for [vmID]. As VM aliases are only loaded at node
+// startup, all running nodes are restarted.
+func (ln *localNetwork) AddVMAlias(ctx context.Context, vmID ids.ID, alias string) error {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	if slices.Contains(ln.vmAliases[vmID.String()], alias) {
+		return nil
+	}
+	ln.vmAliases[vmID.String()] = append(ln.vmAliases[vmID.String()], alias)
+	if err := ln.persistNetworkState(); err != nil {
+		return err
+	}
+	ln.log.Info(logging.Green.Wrap("added vm alias"), zap.String("alias", alias), zap.String("vm-id", vmID.String()))
+	return ln.restartAllNodes(ctx)
+}
+
+// registers [alias] for [chainID] on all running nodes, and keeps it at network level
+func (ln *localNetwork) aliasChain(ctx context.Context, chainID ids.ID, alias string) error {
+	ln.log.Info("registering blockchain alias",
+		zap.String("alias", alias),
+		zap.String("chain-id", chainID.String()))
+	for nodeName, node := range ln.nodes {
+		if node.paused {
+			ln.queueForPausedNode(nodeName, queuedWork{
+				description: fmt.Sprintf("register alias %q for blockchain %s", alias, chainID),
+				apply:       ln.setAliasesFlags,
+			})
+			continue
+		}
+		if err := node.client.AdminAPI().AliasChain(ctx, chainID.String(), alias); err != nil {
+			return fmt.Errorf("failure to register blockchain alias %v on node %v: %w", alias, nodeName, err)
+		}
+	}
+	if !slices.Contains(ln.chainAliases[chainID.String()], alias) {
+		ln.chainAliases[chainID.String()] = append(ln.chainAliases[chainID.String()], alias)
+	}
+	return nil
+}
+
+// restarts all running nodes, so they pick up network level changes
+func (ln *localNetwork) restartAllNodes(ctx context.Context) error {
+	nodeNames := maps.Keys(ln.nodes)
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		if ln.nodes[nodeName].paused {
+			ln.queueForPausedNode(nodeName, queuedWork{
+				description: "update blockchain and VM aliases",
+				apply:       ln.setAliasesFlags,
+			})
+			continue
+		}
+		if err := ln.restartNode(ctx, nodeName, "", "", "", nil, nil, nil, false); err != nil {
+			return err
+		}
+	}
+	return ln.healthy(ctx)
+}
+
+// merges the chain and VM aliases kept at network level into the aliases
+// flags of [nodeConfig], keeping the aliases already given on them
+func (ln *localNetwork) setAliasesFlags(nodeConfig *node.Config) error {
+	if err := setAliasesFlag(nodeConfig.Flags, config.ChainAliasesContentKey, ln.chainAliases); err != nil {
+		return err
+	}
+	return setAliasesFlag(nodeConfig.Flags, config.VMAliasesContentKey, ln.vmAliases)
+}
+
+// merges [aliases] into the aliases JSON set on [flags] under [key]
+func setAliasesFlag(flags map[string]interface{}, key string, aliases map[string][]string) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+	merged, err := getAliasesFlag(flags, key)
+	if err != nil {
+		return err
+	}
+	if merged == nil {
+		merged = map[string][]string{}
+	}
+	mergeAliases(merged, aliases)
+	aliasesBytes, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	flags[key] = base64.StdEncoding.EncodeToString(aliasesBytes)
+	return nil
+}
+
+// adds to [aliases] the ones of [newAliases] not already there
+func mergeAliases(aliases map[string][]string, newAliases map[string][]string) {
+	for id, idAliases := range newAliases {
+		for _, alias := range idAliases {
+			if !slices.Contains(aliases[id], alias) {
+				aliases[id] = append(aliases[id], alias)
+			}
+		}
+	}
+}
+
+// Returns the aliases set on [flags] by setAliasesFlag under [key], or nil if not set.
+func getAliasesFlag(flags map[string]interface{}, key string) (map[string][]string, error) {
+	aliasesIntf, ok := flags[key]
+	if !ok {
+		return nil, nil
+	}
+	aliasesStr, ok := aliasesIntf.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected %q to be of type string but got %T", key, aliasesIntf)
+	}
+	aliasesBytes, err := base64.StdEncoding.DecodeString(aliasesStr)
+	if err != nil {
+		return nil, fmt.Errorf("failure decoding %q: %w", key, err)
+	}
+	aliases := map[string][]string{}
+	if err := json.Unmarshal(aliasesBytes, &aliases); err != nil {
+		return nil, fmt.Errorf("failure unmarshaling %q: %w", key, err)
+	}
+	return aliases, nil
+}
+
+// Returns the chain and VM aliases set on the flags of [nodeConfigs], for
+// snapshots without network state. All nodes get the same aliases flags,
+// so they are merged.
+func aliasesFromNodeConfigs(nodeConfigs []node.Config) (map[string][]string, map[string][]string, error) {
+	chainAliases := map[string][]string{}
+	vmAliases := map[string][]string{}
+	for _, nodeConfig := range nodeConfigs {
+		for key, aliases := range map[string]map[string][]string{
+			config.ChainAliasesContentKey: chainAliases,
+			config.VMAliasesContentKey:    vmAliases,
+		} {
+			nodeAliases, err := getAliasesFlag(nodeConfig.Flags, key)
+			if err != nil {
+				return nil, nil, fmt.Errorf("node %q: %w", nodeConfig.Name, err)
+			}
+			mergeAliases(aliases, nodeAliases)
+		}
+	}
+	return chainAliases, vmAliases, nil
+}
+
+func removeAlias(aliases map[string][]string, id ids.ID, alias string) error {
+	idAliases := aliases[id.String()]
+	i := slices.Index(idAliases, alias)
+	if i == -1 {
+		return fmt.Errorf("alias %q not found for %s", alias, id)
+	}
+	idAliases = slices.Delete(idAliases, i, i+1)
+	if len(idAliases) == 0 {
+		delete(aliases, id.String())
+	} else {
+		aliases[id.String()] = idAliases
+	}
+	return nil
+}
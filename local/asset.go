@@ -0,0 +1,143 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/vms/components/verify"
+	"github.com/luxdefi/node/vms/nftfx"
+	"github.com/luxdefi/node/vms/secp256k1fx"
+	"github.com/luxdefi/node/wallet/subnet/primary/common"
+	"go.uber.org/zap"
+)
+
+// indices of the X-Chain fxs, in the order they are registered on the AVM
+const (
+	secp256k1fxIndex = 0
+	nftfxIndex       = 1
+)
+
+// CreateAsset creates an X-Chain asset, fungible or NFT, owned initially by
+// the holders of [assetSpec]. The network key pays the fees.
+func (ln *localNetwork) CreateAsset(ctx context.Context, assetSpec network.AssetSpec) (ids.ID, error) {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	clientURI, err := ln.getClientURI()
+	if err != nil {
+		return ids.Empty, err
+	}
+	w, err := newWallet(ctx, clientURI, nil, ln.txSigner)
+	if err != nil {
+		return ids.Empty, err
+	}
+	assetID, err := createXChainAsset(ctx, w, assetSpec)
+	if err != nil {
+		return ids.Empty, err
+	}
+	ln.log.Info("created X-Chain asset",
+		zap.String("name", assetSpec.Name),
+		zap.String("symbol", assetSpec.Symbol),
+		zap.Bool("nft", assetSpec.NFT),
+		zap.Int("holders", len(assetSpec.Holders)),
+		zap.String("asset-ID", assetID.String()),
+	)
+	return assetID, nil
+}
+
+func createXChainAsset(ctx context.Context, w *wallet, assetSpec network.AssetSpec) (ids.ID, error) {
+	if len(assetSpec.Holders) == 0 {
+		return ids.Empty, errors.New("no asset holders given")
+	}
+	owners := make([]*secp256k1fx.OutputOwners, len(assetSpec.Holders))
+	for i, holder := range assetSpec.Holders {
+		var err error
+		owners[i], err = getSpecOwner(w, holder.Owners, holder.Threshold)
+		if err != nil {
+			return ids.Empty, err
+		}
+	}
+	if assetSpec.NFT {
+		return createNFTAsset(ctx, w, assetSpec, owners)
+	}
+	outputs := make([]verify.State, len(assetSpec.Holders))
+	for i, holder := range assetSpec.Holders {
+		if holder.Amount == 0 {
+			return ids.Empty, fmt.Errorf("zero amount for holder %d of asset %q", i, assetSpec.Name)
+		}
+		outputs[i] = &secp256k1fx.TransferOutput{
+			Amt:          holder.Amount,
+			OutputOwners: *owners[i],
+		}
+	}
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	assetID, err := w.xWallet.IssueCreateAssetTx(
+		assetSpec.Name,
+		assetSpec.Symbol,
+		assetSpec.Denomination,
+		map[uint32][]verify.State{
+			secp256k1fxIndex: outputs,
+		},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+	if err != nil {
+		return ids.Empty, newTxError(fmt.Errorf("X-Wallet Tx Error %s %w, asset %q", "IssueCreateAssetTx", err, assetSpec.Name))
+	}
+	return assetID, nil
+}
+
+// creates an NFT asset whose minting right is kept by the network key,
+// and mints an NFT with the holder payload to each holder
+func createNFTAsset(
+	ctx context.Context,
+	w *wallet,
+	assetSpec network.AssetSpec,
+	owners []*secp256k1fx.OutputOwners,
+) (ids.ID, error) {
+	cctx, cancel := createDefaultCtx(ctx)
+	assetID, err := w.xWallet.IssueCreateAssetTx(
+		assetSpec.Name,
+		assetSpec.Symbol,
+		0, // NFTs are not divisible
+		map[uint32][]verify.State{
+			nftfxIndex: {
+				&nftfx.MintOutput{
+					GroupID: 0,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{w.addr},
+					},
+				},
+			},
+		},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+	cancel()
+	if err != nil {
+		return ids.Empty, newTxError(fmt.Errorf("X-Wallet Tx Error %s %w, asset %q", "IssueCreateAssetTx", err, assetSpec.Name))
+	}
+	for i, holder := range assetSpec.Holders {
+		cctx, cancel := createDefaultCtx(ctx)
+		_, err := w.xWallet.IssueOperationTxMintNFT(
+			assetID,
+			holder.Payload,
+			[]*secp256k1fx.OutputOwners{owners[i]},
+			common.WithContext(cctx),
+			defaultPoll,
+		)
+		cancel()
+		if err != nil {
+			return ids.Empty, newTxError(fmt.Errorf("X-Wallet Tx Error %s %w, asset ID %s", "IssueOperationTxMintNFT", err, assetID.String()))
+		}
+	}
+	return assetID, nil
+}
@@ -0,0 +1,45 @@
+package local
+
+import (
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/utils/logging"
+)
+
+// Name of the backend running the nodes as local processes
+const BackendName = "local"
+
+func init() {
+	if err := network.RegisterBackend(BackendName, backend{}); err != nil {
+		panic(err)
+	}
+}
+
+// backend creates networks of local node processes
+type backend struct{}
+
+func (backend) NewNetwork(log logging.Logger, cfg network.Config, opts network.BackendOptions) (network.Network, error) {
+	return newNetworkWithOptions(log, cfg, opts)
+}
+
+func (backend) NewNetworkFromSnapshot(log logging.Logger, snapshotName string, opts network.SnapshotOptions) (network.Network, error) {
+	nw, err := NewNetworkFromSnapshot(
+		log,
+		snapshotName,
+		opts.RootDataDir,
+		opts.SnapshotsDir,
+		opts.BinaryPath,
+		opts.PluginDir,
+		opts.ChainConfigs,
+		opts.UpgradeConfigs,
+		opts.SubnetConfigs,
+		opts.GlobalNodeConfig,
+		opts.ReassignPortsIfUsed,
+		opts.Passphrase,
+	)
+	if err != nil {
+		return nw, err
+	}
+	nw.(*localNetwork).onProgress = opts.OnProgress
+	nw.(*localNetwork).txSigner = opts.TxSigner
+	return nw, nil
+}
@@ -4,6 +4,7 @@
 package local
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
@@ -12,26 +13,31 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/luxdefi/node/vms/platformvm/reward"
 
 	"github.com/luxdefi/node/vms/avm"
 	"github.com/luxdefi/node/vms/components/lux"
-	"github.com/luxdefi/node/vms/components/verify"
 	"github.com/luxdefi/node/wallet/chain/x"
 
 	"github.com/luxdefi/netrunner/network"
 	"github.com/luxdefi/netrunner/network/node"
 	"github.com/luxdefi/netrunner/utils"
+	"github.com/luxdefi/node/api"
 	"github.com/luxdefi/node/api/admin"
 	"github.com/luxdefi/node/config"
 	"github.com/luxdefi/node/genesis"
 	"github.com/luxdefi/node/ids"
 	"github.com/luxdefi/node/utils/constants"
 	"github.com/luxdefi/node/utils/crypto/bls"
+	"github.com/luxdefi/node/utils/crypto/keychain"
+	"github.com/luxdefi/node/utils/formatting/address"
 	"github.com/luxdefi/node/utils/logging"
 	"github.com/luxdefi/node/utils/set"
+	"github.com/luxdefi/node/utils/wrappers"
 	"github.com/luxdefi/node/vms/platformvm"
 	"github.com/luxdefi/node/vms/platformvm/signer"
 	"github.com/luxdefi/node/vms/platformvm/txs"
@@ -44,13 +50,11 @@ import (
 )
 
 const (
-	// offset of validation start from current time
-	validationStartOffset               = 20 * time.Second
 	permissionlessValidationStartOffset = 30 * time.Second
-	// duration for primary network validators
-	validationDuration = 365 * 24 * time.Hour
-	// weight assigned to subnet validators
-	subnetValidatorsWeight = 1000
+	// default duration for primary network validators
+	defaultValidationDuration = 365 * 24 * time.Hour
+	// default weight assigned to subnet validators
+	defaultSubnetValidatorsWeight = 1000
 	// check period for blockchain logs while waiting for custom chains to be ready
 	blockchainLogPullFrequency = time.Second
 	// check period while waiting for all validators to be ready
@@ -65,6 +69,28 @@ var (
 	defaultPoll = common.WithPollFrequency(100 * time.Millisecond)
 )
 
+// txError is returned when the wallet fails to issue a tx. It keeps
+// the wallet error, while matching network.ErrTxFailed.
+type txError struct {
+	err error
+}
+
+func newTxError(err error) error {
+	return &txError{err: err}
+}
+
+func (e *txError) Error() string {
+	return e.err.Error()
+}
+
+func (e *txError) Unwrap() error {
+	return e.err
+}
+
+func (*txError) Is(target error) bool {
+	return target == network.ErrTxFailed
+}
+
 type blockchainInfo struct {
 	chainName    string
 	vmID         ids.ID
@@ -89,9 +115,12 @@ func (ln *localNetwork) getNode() node.Node {
 }
 
 // get node client URI for an arbitrary node in the network
-func (ln *localNetwork) getClientURI() (string, error) { //nolint
+func (ln *localNetwork) getClientURI() (string, error) {
 	node := ln.getNode()
-	clientURI := fmt.Sprintf("http://%s:%d", node.GetURL(), node.GetAPIPort())
+	if node == nil {
+		return "", fmt.Errorf("%w: all the nodes of the network are paused", network.ErrNodePaused)
+	}
+	clientURI := utils.HTTPURI(node.GetURL(), node.GetAPIPort())
 	ln.log.Info("getClientURI",
 		zap.String("nodeName", node.GetName()),
 		zap.String("uri", clientURI))
@@ -105,11 +134,21 @@ func (ln *localNetwork) CreateBlockchains(
 	ln.lock.Lock()
 	defer ln.lock.Unlock()
 
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
 	chainInfos, err := ln.installCustomChains(ctx, chainSpecs)
 	if err != nil {
 		return nil, err
 	}
 
+	// the blockchains already exist on the P-Chain, so they are recorded
+	// even if the wait below is aborted by Stop
+	ln.blockchains = append(ln.blockchains, chainInfos...)
+	if err := ln.persistNetworkState(); err != nil {
+		return nil, err
+	}
+
 	if err := ln.waitForCustomChainsReady(ctx, chainInfos); err != nil {
 		return nil, err
 	}
@@ -117,6 +156,9 @@ func (ln *localNetwork) CreateBlockchains(
 	if err := ln.RegisterBlockchainAliases(ctx, chainInfos, chainSpecs); err != nil {
 		return nil, err
 	}
+	if err := ln.persistNetworkState(); err != nil {
+		return nil, err
+	}
 
 	chainIDs := []ids.ID{}
 	for _, chainInfo := range chainInfos {
@@ -138,18 +180,8 @@ func (ln *localNetwork) RegisterBlockchainAliases(
 		if chainSpec.BlockchainAlias == "" {
 			continue
 		}
-		blockchainAlias := chainSpec.BlockchainAlias
-		chainID := chainInfos[i].blockchainID.String()
-		ln.log.Info("registering blockchain alias",
-			zap.String("alias", blockchainAlias),
-			zap.String("chain-id", chainID))
-		for nodeName, node := range ln.nodes {
-			if node.paused {
-				continue
-			}
-			if err := node.client.AdminAPI().AliasChain(ctx, chainID, blockchainAlias); err != nil {
-				return fmt.Errorf("failure to register blockchain alias %v on node %v: %w", blockchainAlias, nodeName, err)
-			}
+		if err := ln.aliasChain(ctx, chainInfos[i].blockchainID, chainSpec.BlockchainAlias); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -158,20 +190,62 @@ func (ln *localNetwork) RegisterBlockchainAliases(
 func (ln *localNetwork) RemoveSubnetValidators(
 	ctx context.Context,
 	removeSubnetSpecs []network.RemoveSubnetValidatorSpec,
+	skipRestart bool,
 ) error {
 	ln.lock.Lock()
 	defer ln.lock.Unlock()
 
-	return ln.removeSubnetValidators(ctx, removeSubnetSpecs)
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	return ln.removeSubnetValidators(ctx, removeSubnetSpecs, skipRestart)
+}
+
+func (ln *localNetwork) TransferSubnetOwnership(
+	ctx context.Context,
+	transferSpecs []network.TransferSubnetOwnershipSpec,
+) ([]ids.ID, error) {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	clientURI, err := ln.getClientURI()
+	if err != nil {
+		return nil, err
+	}
+	subnetIDs := make([]ids.ID, len(transferSpecs))
+	for i, transferSpec := range transferSpecs {
+		subnetIDs[i], err = ids.FromString(transferSpec.SubnetID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	w, err := newWallet(ctx, clientURI, subnetIDs, ln.txSigner)
+	if err != nil {
+		return nil, err
+	}
+	txIDs := make([]ids.ID, len(transferSpecs))
+	for i, transferSpec := range transferSpecs {
+		txIDs[i], err = ln.transferSubnetOwnership(ctx, w, subnetIDs[i], transferSpec.Owners, transferSpec.Threshold)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return txIDs, nil
 }
 
 func (ln *localNetwork) AddPermissionlessValidators(
 	ctx context.Context,
 	validatorSpec []network.PermissionlessValidatorSpec,
-) error {
+) ([]network.AddedPermissionlessValidator, error) {
 	ln.lock.Lock()
 	defer ln.lock.Unlock()
 
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
 	return ln.addPermissionlessValidators(ctx, validatorSpec)
 }
 
@@ -182,7 +256,17 @@ func (ln *localNetwork) TransformSubnet(
 	ln.lock.Lock()
 	defer ln.lock.Unlock()
 
-	return ln.transformToElasticSubnets(ctx, elasticSubnetConfig)
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	elasticSubnetIDs, assetIDs, err := ln.transformToElasticSubnets(ctx, elasticSubnetConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := ln.persistNetworkState(); err != nil {
+		return nil, nil, err
+	}
+	return elasticSubnetIDs, assetIDs, nil
 }
 
 func (ln *localNetwork) CreateSubnets(
@@ -192,6 +276,9 @@ func (ln *localNetwork) CreateSubnets(
 	ln.lock.Lock()
 	defer ln.lock.Unlock()
 
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
 	return ln.installSubnets(ctx, subnetSpecs)
 }
 
@@ -226,7 +313,7 @@ func (ln *localNetwork) installCustomChains(
 		}
 	}
 
-	w, err := newWallet(ctx, clientURI, preloadTXs)
+	w, err := newWallet(ctx, clientURI, preloadTXs, ln.txSigner)
 	if err != nil {
 		return nil, err
 	}
@@ -272,7 +359,7 @@ func (ln *localNetwork) installCustomChains(
 	}
 
 	// just ensure all nodes are primary validators (so can be subnet validators)
-	if err := ln.addPrimaryValidators(ctx, platformCli, w); err != nil {
+	if err := ln.addPrimaryValidators(ctx, platformCli, w, nil); err != nil {
 		return nil, err
 	}
 
@@ -281,6 +368,7 @@ func (ln *localNetwork) installCustomChains(
 	if err != nil {
 		return nil, err
 	}
+	ln.reportProgress(network.PhaseCreateSubnets, len(subnetIDs), len(subnetIDs), fmt.Sprintf("created %d subnets", len(subnetIDs)))
 
 	if err := ln.setSubnetConfigFiles(subnetIDs, subnetSpecs); err != nil {
 		return nil, err
@@ -297,7 +385,7 @@ func (ln *localNetwork) installCustomChains(
 	}
 
 	// wait for nodes to be primary validators before trying to add them as subnet ones
-	if err = ln.waitPrimaryValidators(ctx, platformCli); err != nil {
+	if err = ln.waitPrimaryValidators(ctx, platformCli, nil); err != nil {
 		return nil, err
 	}
 
@@ -318,7 +406,7 @@ func (ln *localNetwork) installCustomChains(
 	if len(subnetSpecs) > 0 || len(nodesToRestartForBlockchainConfigUpdate) > 0 {
 		// we need to restart if there are new subnets or if there are new network config files
 		// add missing subnets, restarting network and waiting for subnet validation to start
-		if err := ln.restartNodes(ctx, subnetIDs, subnetSpecs, nil, nil, nodesToRestartForBlockchainConfigUpdate); err != nil {
+		if err := ln.restartNodes(ctx, subnetIDs, subnetSpecs, nil, nodesToRestartForBlockchainConfigUpdate); err != nil {
 			return nil, err
 		}
 		clientURI, err = ln.getClientURI()
@@ -342,6 +430,10 @@ func (ln *localNetwork) installCustomChains(
 		return nil, err
 	}
 
+	if err := ln.setSubnetOwners(ctx, w, subnetIDs, subnetSpecs); err != nil {
+		return nil, err
+	}
+
 	chainInfos := make([]blockchainInfo, len(chainSpecs))
 	for i, chainSpec := range chainSpecs {
 		vmID, err := utils.VMID(chainSpec.VMName)
@@ -378,7 +470,7 @@ func (ln *localNetwork) installSubnets(
 	}
 	platformCli := platformvm.NewClient(clientURI)
 
-	w, err := newWallet(ctx, clientURI, []ids.ID{})
+	w, err := newWallet(ctx, clientURI, []ids.ID{}, ln.txSigner)
 	if err != nil {
 		return nil, err
 	}
@@ -409,7 +501,7 @@ func (ln *localNetwork) installSubnets(
 	}
 
 	// just ensure all nodes are primary validators (so can be subnet validators)
-	if err := ln.addPrimaryValidators(ctx, platformCli, w); err != nil {
+	if err := ln.addPrimaryValidators(ctx, platformCli, w, nil); err != nil {
 		return nil, err
 	}
 
@@ -417,13 +509,14 @@ func (ln *localNetwork) installSubnets(
 	if err != nil {
 		return nil, err
 	}
+	ln.reportProgress(network.PhaseCreateSubnets, len(subnetIDs), len(subnetIDs), fmt.Sprintf("created %d subnets", len(subnetIDs)))
 
 	if err := ln.setSubnetConfigFiles(subnetIDs, subnetSpecs); err != nil {
 		return nil, err
 	}
 
 	// wait for nodes to be primary validators before trying to add them as subnet ones
-	if err = ln.waitPrimaryValidators(ctx, platformCli); err != nil {
+	if err = ln.waitPrimaryValidators(ctx, platformCli, nil); err != nil {
 		return nil, err
 	}
 
@@ -431,7 +524,7 @@ func (ln *localNetwork) installSubnets(
 		return nil, err
 	}
 
-	if err := ln.restartNodes(ctx, subnetIDs, subnetSpecs, nil, nil, nil); err != nil {
+	if err := ln.restartNodes(ctx, subnetIDs, subnetSpecs, nil, nil); err != nil {
 		return nil, err
 	}
 
@@ -439,9 +532,92 @@ func (ln *localNetwork) installSubnets(
 		return nil, err
 	}
 
+	if err := ln.setSubnetOwners(ctx, w, subnetIDs, subnetSpecs); err != nil {
+		return nil, err
+	}
+
 	return subnetIDs, nil
 }
 
+// transfers the ownership of the new subnets to the owners given in their specs, if any
+// this is done after the network key finished setting up the subnets
+func (ln *localNetwork) setSubnetOwners(
+	ctx context.Context,
+	w *wallet,
+	subnetIDs []ids.ID,
+	subnetSpecs []network.SubnetSpec,
+) error {
+	for i, subnetID := range subnetIDs {
+		if len(subnetSpecs[i].Owners) == 0 {
+			continue
+		}
+		if _, err := ln.transferSubnetOwnership(ctx, w, subnetID, subnetSpecs[i].Owners, subnetSpecs[i].Threshold); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ln *localNetwork) transferSubnetOwnership(
+	ctx context.Context,
+	w *wallet,
+	subnetID ids.ID,
+	owners []string,
+	threshold uint32,
+) (ids.ID, error) {
+	owner, err := getOutputOwners(owners, threshold)
+	if err != nil {
+		return ids.Empty, err
+	}
+	cctx, cancel := createDefaultCtx(ctx)
+	txID, err := w.pWallet.IssueTransferSubnetOwnershipTx(
+		subnetID,
+		owner,
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+	cancel()
+	if err != nil {
+		return ids.Empty, newTxError(fmt.Errorf("P-Wallet Tx Error %s %w, subnetID %s", "IssueTransferSubnetOwnershipTx", err, subnetID.String()))
+	}
+	ln.log.Info("transferred subnet ownership",
+		zap.String("subnet-ID", subnetID.String()),
+		zap.Strings("owners", owners),
+		zap.Uint32("threshold", owner.Threshold),
+		zap.String("tx-ID", txID.String()),
+	)
+	return txID, nil
+}
+
+// builds output owners, as the ones of a subnet or an asset holding,
+// from the given P-Chain or X-Chain addresses
+func getOutputOwners(owners []string, threshold uint32) (*secp256k1fx.OutputOwners, error) {
+	if len(owners) == 0 {
+		return nil, errors.New("no owners given")
+	}
+	if threshold == 0 {
+		threshold = 1
+	}
+	if int(threshold) > len(owners) {
+		return nil, fmt.Errorf("threshold %d is greater than the number of owners %d", threshold, len(owners))
+	}
+	addrs := make([]ids.ShortID, len(owners))
+	for i, owner := range owners {
+		addr, err := address.ParseToID(owner)
+		if err != nil {
+			return nil, fmt.Errorf("failure parsing owner %q: %w", owner, err)
+		}
+		addrs[i] = addr
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i][:], addrs[j][:]) < 0
+	})
+	return &secp256k1fx.OutputOwners{
+		Threshold: threshold,
+		Addrs:     addrs,
+	}, nil
+}
+
 func (ln *localNetwork) getSubnetValidatorsNodenames(
 	ctx context.Context,
 	subnetID ids.ID,
@@ -483,7 +659,7 @@ func (ln *localNetwork) waitForCustomChainsReady(
 		return err
 	}
 
-	for _, chainInfo := range chainInfos {
+	for i, chainInfo := range chainInfos {
 		nodeNames, err := ln.getSubnetValidatorsNodenames(ctx, chainInfo.subnetID)
 		if err != nil {
 			return err
@@ -492,35 +668,20 @@ func (ln *localNetwork) waitForCustomChainsReady(
 		for _, nodeName := range nodeNames {
 			node := ln.nodes[nodeName]
 			if node.paused {
+				// the node runs the chain once resumed, as it tracks its subnet
+				ln.queueForPausedNode(nodeName, queuedWork{description: fmt.Sprintf("run blockchain %s", chainInfo.blockchainID)})
 				continue
 			}
-			ln.log.Info("inspecting node log directory for custom chain logs", zap.String("log-dir", node.GetLogsDir()), zap.String("node-name", nodeName))
-			p := filepath.Join(node.GetLogsDir(), chainInfo.blockchainID.String()+".log")
-			ln.log.Info("checking log",
-				zap.String("vm-ID", chainInfo.vmID.String()),
-				zap.String("subnet-ID", chainInfo.subnetID.String()),
-				zap.String("blockchain-ID", chainInfo.blockchainID.String()),
-				zap.String("path", p),
-			)
-			for {
-				if _, err := os.Stat(p); err == nil {
-					ln.log.Info("found the log", zap.String("path", p))
-					break
-				}
-				ln.log.Info("log not found yet, retrying...",
-					zap.String("vm-ID", chainInfo.vmID.String()),
-					zap.String("subnet-ID", chainInfo.subnetID.String()),
-					zap.String("blockchain-ID", chainInfo.blockchainID.String()),
-				)
-				select {
-				case <-ln.onStopCh:
-					return errAborted
-				case <-ctx.Done():
-					return ctx.Err()
-				case <-time.After(blockchainLogPullFrequency):
-				}
+			if err := ln.waitForChainLog(ctx, node, chainInfo); err != nil {
+				return err
 			}
 		}
+		ln.reportProgress(
+			network.PhaseWaitBlockchains,
+			i+1,
+			len(chainInfos),
+			fmt.Sprintf("blockchain %s is running", chainInfo.blockchainID),
+		)
 	}
 
 	fmt.Println()
@@ -532,18 +693,46 @@ func (ln *localNetwork) waitForCustomChainsReady(
 	return nil
 }
 
+// Waits until [node] runs the chain of [chainInfo], which creates its log.
+func (ln *localNetwork) waitForChainLog(ctx context.Context, node *localNode, chainInfo blockchainInfo) error {
+	ln.log.Info("inspecting node log directory for custom chain logs", zap.String("log-dir", node.GetLogsDir()), zap.String("node-name", node.GetName()))
+	p := filepath.Join(node.GetLogsDir(), chainInfo.blockchainID.String()+".log")
+	ln.log.Info("checking log",
+		zap.String("vm-ID", chainInfo.vmID.String()),
+		zap.String("subnet-ID", chainInfo.subnetID.String()),
+		zap.String("blockchain-ID", chainInfo.blockchainID.String()),
+		zap.String("path", p),
+	)
+	for {
+		if _, err := os.Stat(p); err == nil {
+			ln.log.Info("found the log", zap.String("path", p))
+			return nil
+		}
+		ln.log.Info("log not found yet, retrying...",
+			zap.String("vm-ID", chainInfo.vmID.String()),
+			zap.String("subnet-ID", chainInfo.subnetID.String()),
+			zap.String("blockchain-ID", chainInfo.blockchainID.String()),
+		)
+		select {
+		case <-ln.onStopCh:
+			return errAborted
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(blockchainLogPullFrequency):
+		}
+	}
+}
+
 func (ln *localNetwork) restartNodes(
 	ctx context.Context,
 	subnetIDs []ids.ID,
 	subnetSpecs []network.SubnetSpec,
 	validatorSpecs []network.PermissionlessValidatorSpec,
-	removeValidatorSpecs []network.RemoveSubnetValidatorSpec,
 	nodesToRestartForBlockchainConfigUpdate set.Set[string],
 ) (err error) {
-	if (subnetSpecs != nil && validatorSpecs != nil) || (subnetSpecs != nil && removeValidatorSpecs != nil) ||
-		(validatorSpecs != nil && removeValidatorSpecs != nil) {
-		return errors.New("only one type of spec between subnet specs, add permissionless validator specs and " +
-			"remove validator specs can be supplied at one time")
+	if subnetSpecs != nil && validatorSpecs != nil {
+		return errors.New("only one type of spec between subnet specs and add permissionless validator specs " +
+			"can be supplied at one time")
 	}
 	fmt.Println()
 	ln.log.Info(logging.Blue.Wrap(logging.Bold.Wrap("restarting network")))
@@ -551,7 +740,7 @@ func (ln *localNetwork) restartNodes(
 	nodeNames := maps.Keys(ln.nodes)
 	sort.Strings(nodeNames)
 
-	for _, nodeName := range nodeNames {
+	for i, nodeName := range nodeNames {
 		node := ln.nodes[nodeName]
 
 		// delete node specific flag so as to use default one
@@ -567,28 +756,22 @@ func (ln *localNetwork) restartNodes(
 		}
 
 		trackSubnetIDsSet := set.Set[string]{}
+		previousTrackSubnetIDsSet := set.Set[string]{}
 		if previousTrackedSubnets != "" {
 			for _, s := range strings.Split(previousTrackedSubnets, ",") {
 				trackSubnetIDsSet.Add(s)
+				previousTrackSubnetIDsSet.Add(s)
 			}
 		}
 		needsRestart := false
 		for _, validatorSpec := range validatorSpecs {
-			if validatorSpec.NodeName == node.name {
+			// the primary network is tracked by all nodes
+			if validatorSpec.NodeName == node.name && validatorSpec.SubnetID != "" {
 				trackSubnetIDsSet.Add(validatorSpec.SubnetID)
 				needsRestart = true
 			}
 		}
 
-		for _, removeValidatorSpec := range removeValidatorSpecs {
-			for _, toRemoveNode := range removeValidatorSpec.NodeNames {
-				if toRemoveNode == node.name {
-					trackSubnetIDsSet.Remove(removeValidatorSpec.SubnetID)
-					needsRestart = true
-				}
-			}
-		}
-
 		for i, subnetID := range subnetIDs {
 			for _, participant := range subnetSpecs[i].Participants {
 				if participant == nodeName {
@@ -615,19 +798,37 @@ func (ln *localNetwork) restartNodes(
 		}
 
 		if node.paused {
+			ln.queueForPausedNode(nodeName, queuedWork{
+				description: fmt.Sprintf("track subnets %s", tracked),
+				apply:       setTrackedSubnetsFlag(tracked),
+			})
 			continue
 		}
 
-		if removeValidatorSpecs != nil {
-			ln.log.Info(logging.Green.Wrap(fmt.Sprintf("restarting node %s to stop tracking subnets %s", nodeName, tracked)))
-		} else {
-			ln.log.Info(logging.Green.Wrap(fmt.Sprintf("restarting node %s to track subnets %s", nodeName, tracked)))
+		// only added subnets can be tracked at runtime, and chain
+		// config files are only read on start
+		if !nodesToRestartForBlockchainConfigUpdate.Contains(nodeName) {
+			addedSubnetIDs := []string{}
+			for _, subnetID := range trackSubnetIDs {
+				if !previousTrackSubnetIDsSet.Contains(subnetID) {
+					addedSubnetIDs = append(addedSubnetIDs, subnetID)
+				}
+			}
+			if len(addedSubnetIDs) == 0 || ln.trackSubnetsAtRuntime(ctx, node, addedSubnetIDs) {
+				ln.log.Info(logging.Green.Wrap(fmt.Sprintf("node %s tracks subnets %s without restart", nodeName, tracked)))
+				ln.reportProgress(network.PhaseRestartNodes, i+1, len(nodeNames), fmt.Sprintf("node %s tracks subnets without restart", nodeName))
+				continue
+			}
 		}
 
-		if err := ln.restartNode(ctx, nodeName, "", "", "", nil, nil, nil); err != nil {
+		ln.log.Info(logging.Green.Wrap(fmt.Sprintf("restarting node %s to track subnets %s", nodeName, tracked)))
+
+		if err := ln.restartNode(ctx, nodeName, "", "", "", nil, nil, nil, false); err != nil {
 			return err
 		}
+		ln.reportProgress(network.PhaseRestartNodes, i+1, len(nodeNames), fmt.Sprintf("restarted node %s", nodeName))
 	}
+	ln.reportProgress(network.PhaseRestartNodes, len(nodeNames), len(nodeNames), "restarted nodes")
 	if err := ln.healthy(ctx); err != nil {
 		return err
 	}
@@ -643,12 +844,20 @@ type wallet struct {
 	xWallet  x.Wallet
 }
 
+// Returns a wallet of the key funded by the default genesis, or of the
+// key of [txSigner] if not nil.
 func newWallet(
 	ctx context.Context,
 	uri string,
 	preloadTXs []ids.ID,
+	txSigner network.TxSigner,
 ) (*wallet, error) {
-	kc := secp256k1fx.NewKeychain(genesis.EWOQKey)
+	var kc keychain.Keychain = secp256k1fx.NewKeychain(genesis.EWOQKey)
+	addr := genesis.EWOQKey.PublicKey().Address()
+	if txSigner != nil {
+		kc = newTxSignerKeychain(txSigner)
+		addr = txSigner.Address()
+	}
 	pCTX, xCTX, utxos, err := primary.FetchState(ctx, uri, kc.Addresses())
 	if err != nil {
 		return nil, err
@@ -670,7 +879,7 @@ func newWallet(
 	xChainID := xCTX.BlockchainID()
 	xUTXOs := primary.NewChainUTXOs(xChainID, utxos)
 	var w wallet
-	w.addr = genesis.EWOQKey.PublicKey().Address()
+	w.addr = addr
 	w.pBackend = p.NewBackend(pCTX, pUTXOs, pTXs)
 	w.pBuilder = p.NewBuilder(kc.Addresses(), w.pBackend)
 	w.pSigner = p.NewSigner(kc, w.pBackend)
@@ -689,13 +898,51 @@ func (w *wallet) reload(uri string) {
 	w.pWallet = p.NewWallet(w.pBuilder, w.pSigner, pClient, w.pBackend)
 }
 
+func (ln *localNetwork) validationStartOffset() time.Duration {
+	return ln.stakingSpec.GetValidationStartOffset()
+}
+
+func (ln *localNetwork) validationDuration() time.Duration {
+	if ln.stakingSpec.ValidationDuration == 0 {
+		return defaultValidationDuration
+	}
+	return ln.stakingSpec.ValidationDuration
+}
+
+func (ln *localNetwork) subnetValidatorsWeight(subnetSpec network.SubnetSpec) uint64 {
+	if subnetSpec.ValidatorsWeight != 0 {
+		return subnetSpec.ValidatorsWeight
+	}
+	if ln.stakingSpec.SubnetValidatorsWeight != 0 {
+		return ln.stakingSpec.SubnetValidatorsWeight
+	}
+	return defaultSubnetValidatorsWeight
+}
+
+// Returns an error if one of the nodes of the primary network validator specs
+// [primarySpecNodeNames] is already in [primaryValidators], as adding it
+// again would fail on-chain. The nodes to be created are not validators.
+func (ln *localNetwork) checkPrimarySpecNodes(primarySpecNodeNames set.Set[string], primaryValidators set.Set[ids.NodeID]) error {
+	nodeNames := primarySpecNodeNames.List()
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		node, ok := ln.nodes[nodeName]
+		if ok && primaryValidators.Contains(node.nodeID) {
+			return fmt.Errorf("%w: node %s is already a primary network validator", network.ErrInvalidValidatorSpec, nodeName)
+		}
+	}
+	return nil
+}
+
 // add all nodes as validators of the primary network, in case they are not
 // the validation starts as soon as possible and its duration is as long as possible, that is,
 // it is set to max accepted duration by node
+// the nodes in [skipNodeNames] are left out, as the caller adds them
 func (ln *localNetwork) addPrimaryValidators(
 	ctx context.Context,
 	platformCli platformvm.Client,
 	w *wallet,
+	skipNodeNames set.Set[string],
 ) error {
 	ln.log.Info(logging.Green.Wrap("adding the nodes as primary network validators"))
 	// ref. https://docs.lux.network/build/node-apis/p-chain/#platformgetcurrentvalidators
@@ -709,32 +956,32 @@ func (ln *localNetwork) addPrimaryValidators(
 	for _, v := range vdrs {
 		curValidators.Add(v.NodeID)
 	}
+	numDone := 0
 	for nodeName, node := range ln.nodes {
 		nodeID := node.GetNodeID()
 
+		numDone++
 		if curValidators.Contains(nodeID) {
+			ln.reportProgress(network.PhaseAddPrimaryValidators, numDone, len(ln.nodes), fmt.Sprintf("node %s is already a validator", nodeName))
+			continue
+		}
+		if skipNodeNames.Contains(nodeName) {
 			continue
 		}
 
-		// Prepare node BLS PoP
 		// It is important to note that this will ONLY register BLS signers for
 		// nodes registered AFTER genesis.
-		blsKeyBytes, err := base64.StdEncoding.DecodeString(node.GetConfig().StakingSigningKey)
-		if err != nil {
-			return err
-		}
-		blsSk, err := bls.SecretKeyFromBytes(blsKeyBytes)
+		proofOfPossession, err := getProofOfPossession(node)
 		if err != nil {
 			return err
 		}
-		proofOfPossession := signer.NewProofOfPossession(blsSk)
 		cctx, cancel = createDefaultCtx(ctx)
 		txID, err := w.pWallet.IssueAddPermissionlessValidatorTx(
 			&txs.SubnetValidator{
 				Validator: txs.Validator{
 					NodeID: nodeID,
-					Start:  uint64(time.Now().Add(validationStartOffset).Unix()),
-					End:    uint64(time.Now().Add(validationDuration).Unix()),
+					Start:  uint64(time.Now().Add(ln.validationStartOffset()).Unix()),
+					End:    uint64(time.Now().Add(ln.validationDuration()).Unix()),
 					Wght:   genesis.LocalParams.MinValidatorStake,
 				},
 				Subnet: ids.Empty,
@@ -754,37 +1001,49 @@ func (ln *localNetwork) addPrimaryValidators(
 		)
 		cancel()
 		if err != nil {
-			return fmt.Errorf("P-Wallet Tx Error %s %w, node ID %s", "IssueAddPermissionlessValidatorTx", err, nodeID.String())
+			return newTxError(fmt.Errorf("P-Wallet Tx Error %s %w, node ID %s", "IssueAddPermissionlessValidatorTx", err, nodeID.String()))
 		}
 		ln.log.Info("added node as primary subnet validator", zap.String("node-name", nodeName), zap.String("node-ID", nodeID.String()), zap.String("tx-ID", txID.String()))
+		ln.reportProgress(network.PhaseAddPrimaryValidators, numDone, len(ln.nodes), fmt.Sprintf("added node %s as validator", nodeName))
 	}
 	return nil
 }
 
-func getXChainAssetID(ctx context.Context, w *wallet, tokenName string, tokenSymbol string, maxSupply uint64) (ids.ID, error) {
-	owner := &secp256k1fx.OutputOwners{
-		Threshold: 1,
-		Addrs: []ids.ShortID{
-			w.addr,
-		},
+// returns the owners given on a spec, defaulting to the network key
+func getSpecOwner(w *wallet, owners []string, threshold uint32) (*secp256k1fx.OutputOwners, error) {
+	if len(owners) == 0 {
+		return &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{w.addr},
+		}, nil
 	}
-	cctx, cancel := createDefaultCtx(ctx)
-	defer cancel()
-	return w.xWallet.IssueCreateAssetTx(
-		tokenName,
-		tokenSymbol,
-		9, // denomination for UI purposes only in explorer
-		map[uint32][]verify.State{
-			0: {
-				&secp256k1fx.TransferOutput{
-					Amt:          maxSupply,
-					OutputOwners: *owner,
-				},
+	return getOutputOwners(owners, threshold)
+}
+
+// prepares the BLS proof of possession of [node] signing key
+func getProofOfPossession(node node.Node) (*signer.ProofOfPossession, error) {
+	blsKeyBytes, err := base64.StdEncoding.DecodeString(node.GetConfig().StakingSigningKey)
+	if err != nil {
+		return nil, err
+	}
+	blsSk, err := bls.SecretKeyFromBytes(blsKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	return signer.NewProofOfPossession(blsSk), nil
+}
+
+func getXChainAssetID(ctx context.Context, w *wallet, tokenName string, tokenSymbol string, maxSupply uint64) (ids.ID, error) {
+	return createXChainAsset(ctx, w, network.AssetSpec{
+		Name:         tokenName,
+		Symbol:       tokenSymbol,
+		Denomination: 9, // denomination for UI purposes only in explorer
+		Holders: []network.AssetHolder{
+			{
+				Amount: maxSupply,
 			},
 		},
-		common.WithContext(cctx),
-		defaultPoll,
-	)
+	})
 }
 
 func exportXChainToPChain(ctx context.Context, w *wallet, owner *secp256k1fx.OutputOwners, subnetAssetID ids.ID, assetAmount uint64) error {
@@ -827,6 +1086,7 @@ func importPChainFromXChain(ctx context.Context, w *wallet, owner *secp256k1fx.O
 func (ln *localNetwork) removeSubnetValidators(
 	ctx context.Context,
 	removeSubnetSpecs []network.RemoveSubnetValidatorSpec,
+	skipRestart bool,
 ) error {
 	ln.log.Info("removing subnet validator tx")
 	removeSubnetSpecIDs := make([]ids.ID, len(removeSubnetSpecs))
@@ -844,7 +1104,7 @@ func (ln *localNetwork) removeSubnetValidators(
 		}
 		preloadTXs[i] = subnetID
 	}
-	w, err := newWallet(ctx, clientURI, preloadTXs)
+	w, err := newWallet(ctx, clientURI, preloadTXs, ln.txSigner)
 	if err != nil {
 		return err
 	}
@@ -883,7 +1143,7 @@ func (ln *localNetwork) removeSubnetValidators(
 			)
 			cancel()
 			if err != nil {
-				return fmt.Errorf("P-Wallet Tx Error %s %w, node ID %s, subnetID %s", "IssueRemoveSubnetValidatorTx", err, nodeID.String(), subnetID.String())
+				return newTxError(fmt.Errorf("P-Wallet Tx Error %s %w, node ID %s, subnetID %s", "IssueRemoveSubnetValidatorTx", err, nodeID.String(), subnetID.String()))
 			}
 			ln.log.Info("removed node as subnet validator",
 				zap.String("node-name", nodeName),
@@ -894,37 +1154,185 @@ func (ln *localNetwork) removeSubnetValidators(
 			removeSubnetSpecIDs[i] = txID
 		}
 	}
-	return ln.restartNodes(ctx, nil, nil, nil, removeSubnetSpecs, nil)
+	return ln.untrackRemovedSubnets(ctx, removeSubnetSpecs, skipRestart)
+}
+
+// Removes the subnets of [removeSubnetSpecs] from the tracked subnets of
+// their nodes, and restarts concurrently the nodes whose tracked subnets
+// changed, waiting only for them to be healthy.
+// If [skipRestart] is true, the tracked subnets are only updated on the
+// node configs, and the nodes keep validating the subnets until restarted.
+func (ln *localNetwork) untrackRemovedSubnets(
+	ctx context.Context,
+	removeSubnetSpecs []network.RemoveSubnetValidatorSpec,
+	skipRestart bool,
+) error {
+	untrackedSubnetIDs := map[string]set.Set[string]{}
+	for _, removeSubnetSpec := range removeSubnetSpecs {
+		for _, nodeName := range removeSubnetSpec.NodeNames {
+			if _, ok := untrackedSubnetIDs[nodeName]; !ok {
+				untrackedSubnetIDs[nodeName] = set.Set[string]{}
+			}
+			untrackedSubnetIDs[nodeName].Add(removeSubnetSpec.SubnetID)
+		}
+	}
+
+	nodeNames := maps.Keys(untrackedSubnetIDs)
+	sort.Strings(nodeNames)
+	nodesToRestart := []*localNode{}
+	for _, nodeName := range nodeNames {
+		node, ok := ln.nodes[nodeName]
+		if !ok {
+			return fmt.Errorf("node %s is not in network nodes", nodeName)
+		}
+		nodeConfig := node.GetConfig()
+		previousTrackedSubnets := ""
+		if previousTrackedSubnetsIntf, ok := nodeConfig.Flags[config.TrackSubnetsKey]; ok {
+			previousTrackedSubnets, ok = previousTrackedSubnetsIntf.(string)
+			if !ok {
+				return fmt.Errorf("expected node config %s to have type string obtained %T", config.TrackSubnetsKey, previousTrackedSubnetsIntf)
+			}
+		}
+		trackSubnetIDs := []string{}
+		changed := false
+		if previousTrackedSubnets != "" {
+			for _, subnetID := range strings.Split(previousTrackedSubnets, ",") {
+				if untrackedSubnetIDs[nodeName].Contains(subnetID) {
+					changed = true
+					continue
+				}
+				trackSubnetIDs = append(trackSubnetIDs, subnetID)
+			}
+		}
+		if !changed {
+			continue
+		}
+		sort.Strings(trackSubnetIDs)
+		tracked := strings.Join(trackSubnetIDs, ",")
+		nodeConfig.Flags[config.TrackSubnetsKey] = tracked
+		if node.paused {
+			ln.queueForPausedNode(nodeName, queuedWork{
+				description: fmt.Sprintf("stop tracking subnets %s", untrackedSubnetIDs[nodeName].List()),
+				apply:       setTrackedSubnetsFlag(tracked),
+			})
+			continue
+		}
+		nodesToRestart = append(nodesToRestart, node)
+	}
+
+	if len(nodesToRestart) == 0 {
+		return nil
+	}
+	if skipRestart {
+		for _, node := range nodesToRestart {
+			ln.log.Info(logging.Yellow.Wrap(fmt.Sprintf("node %s keeps tracking the removed subnets until restarted", node.name)))
+		}
+		return nil
+	}
+
+	fmt.Println()
+	ln.log.Info(logging.Blue.Wrap(logging.Bold.Wrap("restarting nodes to stop tracking subnets")))
+
+	// stopping is the slow part of a restart, so it is done concurrently,
+	// and then the nodes are started again with the updated configs.
+	// the nodes are marked paused before stopping, so health waits in
+	// progress don't take the stop as unexpected, and a node that fails
+	// to restart is left paused instead of running with a stale config
+	ln.nodesLock.Lock()
+	for _, node := range nodesToRestart {
+		node.paused = true
+	}
+	ln.nodesLock.Unlock()
+	stopErrs := make([]error, len(nodesToRestart))
+	wg := sync.WaitGroup{}
+	for i, node := range nodesToRestart {
+		node.closePeerSubscriptions()
+		wg.Add(1)
+		go func(i int, node *localNode) {
+			defer wg.Done()
+			node.client.CChainEthAPI().Close()
+			// a failed stop doesn't stop the others, as the nodes are
+			// started again anyway
+			if exitCode := node.process.Stop(ctx); exitCode != 0 {
+				stopErrs[i] = fmt.Errorf("node %q exited with exit code: %d", node.name, exitCode)
+			}
+		}(i, node)
+	}
+	wg.Wait()
+	errs := wrappers.Errs{}
+	for _, err := range stopErrs {
+		if err != nil {
+			ln.log.Error("error stopping node", zap.Error(err))
+			errs.Add(err)
+		}
+	}
+	syscall.Sync()
+
+	// the process of a node is gone once its stop returns, even with an
+	// error, so all of them are started again
+	restartedNodes := make([]*localNode, 0, len(nodesToRestart))
+	for i, node := range nodesToRestart {
+		ln.log.Info(logging.Green.Wrap(fmt.Sprintf("restarting node %s to track subnets %s", node.name, node.GetConfig().Flags[config.TrackSubnetsKey])))
+		if err := ln.restartNode(ctx, node.name, "", "", "", nil, nil, nil, false); err != nil {
+			ln.log.Error("error restarting node, left paused", zap.String("name", node.name), zap.Error(err))
+			errs.Add(err)
+			continue
+		}
+		ln.reportProgress(network.PhaseRestartNodes, i+1, len(nodesToRestart), fmt.Sprintf("restarted node %s", node.name))
+		restartedNodes = append(restartedNodes, ln.nodes[node.name])
+	}
+	errs.Add(ln.nodesHealthy(ctx, restartedNodes))
+	return errs.Err
 }
 
 func (ln *localNetwork) addPermissionlessValidators(
 	ctx context.Context,
 	validatorSpecs []network.PermissionlessValidatorSpec,
-) error {
+) ([]network.AddedPermissionlessValidator, error) {
 	ln.log.Info("adding permissionless validator tx")
+	for _, validatorSpec := range validatorSpecs {
+		if err := validatorSpec.Validate(time.Now().Add(permissionlessValidationStartOffset)); err != nil {
+			return nil, err
+		}
+	}
 	clientURI, err := ln.getClientURI()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	platformCli := platformvm.NewClient(clientURI)
 	// wallet needs txs for all previously created subnets
-	preloadTXs := make([]ids.ID, len(validatorSpecs))
-	for i, validatorSpec := range validatorSpecs {
+	var preloadTXs []ids.ID
+	// the primary network specs are the ones adding the nodes as primary validators
+	primarySpecNodeNames := set.Set[string]{}
+	for _, validatorSpec := range validatorSpecs {
+		if validatorSpec.SubnetID == "" {
+			primarySpecNodeNames.Add(validatorSpec.NodeName)
+			continue
+		}
 		subnetID, err := ids.FromString(validatorSpec.SubnetID)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		preloadTXs[i] = subnetID
+		preloadTXs = append(preloadTXs, subnetID)
 	}
-	w, err := newWallet(ctx, clientURI, preloadTXs)
-	if err != nil {
-		return err
+	if primarySpecNodeNames.Len() > 0 {
+		cctx, cancel := createDefaultCtx(ctx)
+		vdrs, err := platformCli.GetCurrentValidators(cctx, constants.PrimaryNetworkID, nil)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		primaryValidators := set.Set[ids.NodeID]{}
+		for _, v := range vdrs {
+			primaryValidators.Add(v.NodeID)
+		}
+		if err := ln.checkPrimarySpecNodes(primarySpecNodeNames, primaryValidators); err != nil {
+			return nil, err
+		}
 	}
-	owner := &secp256k1fx.OutputOwners{
-		Threshold: 1,
-		Addrs: []ids.ShortID{
-			w.addr,
-		},
+	w, err := newWallet(ctx, clientURI, preloadTXs, ln.txSigner)
+	if err != nil {
+		return nil, err
 	}
 	// create new nodes
 	for _, validatorSpec := range validatorSpecs {
@@ -932,85 +1340,133 @@ func (ln *localNetwork) addPermissionlessValidators(
 		if !ok {
 			ln.log.Info(logging.Green.Wrap(fmt.Sprintf("adding new participant %s", validatorSpec.NodeName)))
 			if _, err := ln.addNode(node.Config{Name: validatorSpec.NodeName}); err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 	if err := ln.healthy(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
 	// just ensure all nodes are primary validators (so can be subnet validators)
-	if err := ln.addPrimaryValidators(ctx, platformCli, w); err != nil {
-		return err
+	if err := ln.addPrimaryValidators(ctx, platformCli, w, primarySpecNodeNames); err != nil {
+		return nil, err
 	}
 
 	// wait for nodes to be primary validators before trying to add them as subnet ones
-	if err = ln.waitPrimaryValidators(ctx, platformCli); err != nil {
-		return err
+	if err = ln.waitPrimaryValidators(ctx, platformCli, primarySpecNodeNames); err != nil {
+		return nil, err
 	}
 
 	cctx, cancel := createDefaultCtx(ctx)
 	vs, err := platformCli.GetCurrentValidators(cctx, constants.PrimaryNetworkID, nil)
 	cancel()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	primaryValidatorsEndtime := make(map[ids.NodeID]time.Time)
 	for _, v := range vs {
 		primaryValidatorsEndtime[v.NodeID] = time.Unix(int64(v.EndTime), 0)
 	}
 
+	added := make([]network.AddedPermissionlessValidator, 0, len(validatorSpecs))
 	for _, validatorSpec := range validatorSpecs {
 		ln.log.Info(logging.Green.Wrap("adding permissionless validator"), zap.String("node ", validatorSpec.NodeName))
-		cctx, cancel := createDefaultCtx(ctx)
-		validatorNodeID := ln.nodes[validatorSpec.NodeName].nodeID
-		subnetID, err := ids.FromString(validatorSpec.SubnetID)
+		validatorNode := ln.nodes[validatorSpec.NodeName]
+		validatorNodeID := validatorNode.nodeID
+		isPrimary := validatorSpec.SubnetID == ""
+		subnetID := constants.PrimaryNetworkID
+		assetID := w.pWallet.LUXAssetID()
+		// BLS keys are only registered for primary network validators
+		var blsSigner signer.Signer = &signer.Empty{}
+		if isPrimary {
+			proofOfPossession, err := getProofOfPossession(validatorNode)
+			if err != nil {
+				return nil, err
+			}
+			blsSigner = proofOfPossession
+		} else {
+			subnetID, err = ids.FromString(validatorSpec.SubnetID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if validatorSpec.AssetID != "" {
+			assetID, err = ids.FromString(validatorSpec.AssetID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rewardOwner, err := getSpecOwner(w, validatorSpec.RewardOwners, validatorSpec.RewardThreshold)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		assetID, err := ids.FromString(validatorSpec.AssetID)
+		changeOwner, err := getSpecOwner(w, validatorSpec.ChangeOwners, validatorSpec.ChangeThreshold)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		var startTime uint64
-		var endTime uint64
-		if validatorSpec.StartTime.IsZero() {
-			startTime = uint64(time.Now().Add(permissionlessValidationStartOffset).Unix())
-		} else {
-			startTime = uint64(validatorSpec.StartTime.Unix())
+		delegationRewardOwner := &secp256k1fx.OutputOwners{}
+		delegationFee := uint32(reward.PercentDenominator)
+		if isPrimary {
+			// primary network validators take delegations, with the fee the other nodes are added with
+			delegationRewardOwner = rewardOwner
+			delegationFee = 10 * 10000 // 10% fee percent, times 10000 to make it as shares
 		}
-
-		if validatorSpec.StakeDuration == 0 {
-			endTime = uint64(primaryValidatorsEndtime[validatorNodeID].Unix())
-		} else {
-			endTime = uint64(validatorSpec.StartTime.Add(validatorSpec.StakeDuration).Unix())
+		primaryEndTime := primaryValidatorsEndtime[validatorNodeID]
+		defaultEndTime := primaryEndTime
+		if isPrimary {
+			defaultEndTime = time.Now().Add(ln.validationDuration())
 		}
+		startTime, endTime, err := validatorSpec.GetValidationPeriod(time.Now().Add(permissionlessValidationStartOffset), defaultEndTime)
+		if err != nil {
+			return nil, err
+		}
+		// the P-Chain rejects subnet validations outlasting the primary network one
+		if !isPrimary && endTime.After(primaryEndTime) {
+			return nil, fmt.Errorf("%w: validation of %s would end at %s, after its primary network validation at %s", network.ErrInvalidValidatorSpec, validatorSpec.NodeName, endTime, primaryEndTime)
+		}
+		cctx, cancel := createDefaultCtx(ctx)
 		txID, err := w.pWallet.IssueAddPermissionlessValidatorTx(
 			&txs.SubnetValidator{
 				Validator: txs.Validator{
 					NodeID: validatorNodeID,
-					Start:  startTime,
-					End:    endTime,
+					Start:  uint64(startTime.Unix()),
+					End:    uint64(endTime.Unix()),
 					Wght:   validatorSpec.StakedAmount,
 				},
 				Subnet: subnetID,
 			},
-			&signer.Empty{},
+			blsSigner,
 			assetID,
-			owner,
-			&secp256k1fx.OutputOwners{},
-			reward.PercentDenominator,
+			rewardOwner,
+			delegationRewardOwner,
+			delegationFee,
 			common.WithContext(cctx),
+			common.WithChangeOwner(changeOwner),
 			defaultPoll,
 		)
 		cancel()
 		if err != nil {
-			return err
+			return nil, newTxError(fmt.Errorf("P-Wallet Tx Error %s %w, node ID %s", "IssueAddPermissionlessValidatorTx", err, validatorNodeID.String()))
 		}
-		ln.log.Info("Validator successfully added as permissionless validator", zap.String("TX ID", txID.String()))
+		ln.log.Info("Validator successfully added as permissionless validator",
+			zap.String("TX ID", txID.String()),
+			zap.Time("start-time", startTime),
+			zap.Time("end-time", endTime),
+		)
+		added = append(added, network.AddedPermissionlessValidator{
+			NodeName:  validatorSpec.NodeName,
+			SubnetID:  subnetID,
+			TxID:      txID,
+			StartTime: startTime,
+			EndTime:   endTime,
+			Weight:    validatorSpec.StakedAmount,
+		})
+	}
+	if err := ln.restartNodes(ctx, nil, nil, validatorSpecs, nil); err != nil {
+		return nil, err
 	}
-	return ln.restartNodes(ctx, nil, nil, validatorSpecs, nil, nil)
+	return added, nil
 }
 
 func (ln *localNetwork) transformToElasticSubnets(
@@ -1037,7 +1493,7 @@ func (ln *localNetwork) transformToElasticSubnets(
 			preloadTXs = append(preloadTXs, subnetID)
 		}
 	}
-	w, err := newWallet(ctx, clientURI, preloadTXs)
+	w, err := newWallet(ctx, clientURI, preloadTXs, ln.txSigner)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1094,11 +1550,282 @@ func (ln *localNetwork) transformToElasticSubnets(
 func (ln *localNetwork) GetElasticSubnetID(_ context.Context, subnetID ids.ID) (ids.ID, error) {
 	elasticSubnetID, ok := ln.subnetID2ElasticSubnetID[subnetID]
 	if !ok {
-		return ids.Empty, fmt.Errorf("subnetID not found on map: %s", subnetID)
+		return ids.Empty, fmt.Errorf("%w for subnet %s", network.ErrElasticSubnetIDNotFound, subnetID)
 	}
 	return elasticSubnetID, nil
 }
 
+// GetElasticSubnet returns the parameters [subnetID] was transformed with,
+// and the current state of its staking pool
+func (ln *localNetwork) GetElasticSubnet(ctx context.Context, subnetID ids.ID) (network.ElasticSubnet, error) {
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	ln.lock.RLock()
+	elasticSubnetID, ok := ln.subnetID2ElasticSubnetID[subnetID]
+	clientURI, err := ln.getClientURI()
+	ln.lock.RUnlock()
+	if !ok {
+		return network.ElasticSubnet{}, fmt.Errorf("%w for subnet %s", network.ErrElasticSubnetIDNotFound, subnetID)
+	}
+	if err != nil {
+		return network.ElasticSubnet{}, err
+	}
+	platformCli := platformvm.NewClient(clientURI)
+
+	transformTx, err := getTransformSubnetTx(ctx, platformCli, elasticSubnetID)
+	if err != nil {
+		return network.ElasticSubnet{}, err
+	}
+	cctx, cancel := createDefaultCtx(ctx)
+	asset, err := avm.NewClient(clientURI, "X").GetAssetDescription(cctx, transformTx.AssetID.String())
+	cancel()
+	if err != nil {
+		return network.ElasticSubnet{}, err
+	}
+	cctx, cancel = createDefaultCtx(ctx)
+	currentSupply, err := platformCli.GetCurrentSupply(cctx, subnetID)
+	cancel()
+	if err != nil {
+		return network.ElasticSubnet{}, err
+	}
+	cctx, cancel = createDefaultCtx(ctx)
+	vdrs, err := platformCli.GetCurrentValidators(cctx, subnetID, nil)
+	cancel()
+	if err != nil {
+		return network.ElasticSubnet{}, err
+	}
+
+	subnetIDStr := subnetID.String()
+	elasticSubnet := network.ElasticSubnet{
+		ElasticSubnetID: elasticSubnetID,
+		AssetID:         transformTx.AssetID,
+		Spec: network.ElasticSubnetSpec{
+			SubnetID:                 &subnetIDStr,
+			AssetName:                asset.Name,
+			AssetSymbol:              asset.Symbol,
+			InitialSupply:            transformTx.InitialSupply,
+			MaxSupply:                transformTx.MaximumSupply,
+			MinConsumptionRate:       transformTx.MinConsumptionRate,
+			MaxConsumptionRate:       transformTx.MaxConsumptionRate,
+			MinValidatorStake:        transformTx.MinValidatorStake,
+			MaxValidatorStake:        transformTx.MaxValidatorStake,
+			MinStakeDuration:         time.Duration(transformTx.MinStakeDuration) * time.Second,
+			MaxStakeDuration:         time.Duration(transformTx.MaxStakeDuration) * time.Second,
+			MinDelegationFee:         transformTx.MinDelegationFee,
+			MinDelegatorStake:        transformTx.MinDelegatorStake,
+			MaxValidatorWeightFactor: transformTx.MaxValidatorWeightFactor,
+			UptimeRequirement:        transformTx.UptimeRequirement,
+		},
+		CurrentSupply: currentSupply,
+		NumValidators: uint32(len(vdrs)),
+	}
+	for _, vdr := range vdrs {
+		elasticSubnet.ValidatorsStake += vdr.Weight
+		if vdr.DelegatorCount != nil {
+			elasticSubnet.NumDelegators += *vdr.DelegatorCount
+		}
+		if vdr.DelegatorWeight != nil {
+			elasticSubnet.DelegatorsStake += *vdr.DelegatorWeight
+		}
+	}
+	return elasticSubnet, nil
+}
+
+// returns the tx [elasticSubnetID] subnet was transformed with
+func getTransformSubnetTx(
+	ctx context.Context,
+	platformCli platformvm.Client,
+	elasticSubnetID ids.ID,
+) (*txs.TransformSubnetTx, error) {
+	cctx, cancel := createDefaultCtx(ctx)
+	txBytes, err := platformCli.GetTx(cctx, elasticSubnetID)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txs.Parse(txs.Codec, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	transformTx, ok := tx.Unsigned.(*txs.TransformSubnetTx)
+	if !ok {
+		return nil, fmt.Errorf("tx %s is not a transform subnet tx", elasticSubnetID)
+	}
+	return transformTx, nil
+}
+
+// waits for the current validation of [nodeName] on [subnetID] to end, and returns
+// the reward UTXOs issued to the validator
+// the network lock is not held while waiting, as validation periods can be long
+func (ln *localNetwork) WaitForValidatorRewards(
+	ctx context.Context,
+	nodeName string,
+	subnetID ids.ID,
+) (network.ValidatorRewards, error) {
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	ln.lock.RLock()
+	node, ok := ln.nodes[nodeName]
+	if !ok {
+		ln.lock.RUnlock()
+		return network.ValidatorRewards{}, fmt.Errorf("%w: %s", network.ErrNodeNotFound, nodeName)
+	}
+	nodeID := node.GetNodeID()
+	clientURI, err := ln.getClientURI()
+	ln.lock.RUnlock()
+	if err != nil {
+		return network.ValidatorRewards{}, err
+	}
+	platformCli := platformvm.NewClient(clientURI)
+
+	cctx, cancel := createDefaultCtx(ctx)
+	vs, err := platformCli.GetCurrentValidators(cctx, subnetID, []ids.NodeID{nodeID})
+	cancel()
+	if err != nil {
+		return network.ValidatorRewards{}, err
+	}
+	if len(vs) == 0 {
+		return network.ValidatorRewards{}, fmt.Errorf("node %s is not a current validator of subnet %s", nodeName, subnetID)
+	}
+	validator := vs[0]
+	rewards := network.ValidatorRewards{
+		NodeName: nodeName,
+		SubnetID: subnetID,
+		TxID:     validator.TxID,
+		EndTime:  time.Unix(int64(validator.EndTime), 0),
+	}
+	if validator.PotentialReward != nil {
+		rewards.PotentialReward = *validator.PotentialReward
+	}
+
+	ln.log.Info(logging.Green.Wrap("waiting for validation period to end"),
+		zap.String("node-name", nodeName),
+		zap.String("subnet-ID", subnetID.String()),
+		zap.Time("end-time", rewards.EndTime),
+	)
+	for {
+		if time.Now().After(rewards.EndTime) {
+			cctx, cancel := createDefaultCtx(ctx)
+			vs, err := platformCli.GetCurrentValidators(cctx, subnetID, []ids.NodeID{nodeID})
+			cancel()
+			if err != nil {
+				return network.ValidatorRewards{}, err
+			}
+			if len(vs) == 0 || vs[0].TxID != rewards.TxID {
+				break
+			}
+		}
+		select {
+		case <-ln.onStopCh:
+			return network.ValidatorRewards{}, errAborted
+		case <-ctx.Done():
+			return network.ValidatorRewards{}, ctx.Err()
+		case <-time.After(waitForValidatorsPullFrequency):
+		}
+	}
+
+	cctx, cancel = createDefaultCtx(ctx)
+	utxosBytes, err := platformCli.GetRewardUTXOs(cctx, &api.GetTxArgs{TxID: rewards.TxID})
+	cancel()
+	if err != nil {
+		return network.ValidatorRewards{}, err
+	}
+	for _, utxoBytes := range utxosBytes {
+		utxo := lux.UTXO{}
+		if _, err := txs.Codec.Unmarshal(utxoBytes, &utxo); err != nil {
+			return network.ValidatorRewards{}, fmt.Errorf("failure parsing reward utxo: %w", err)
+		}
+		out, ok := utxo.Out.(lux.Amounter)
+		if !ok {
+			return network.ValidatorRewards{}, fmt.Errorf("unexpected reward utxo output type %T", utxo.Out)
+		}
+		rewards.RewardUTXOs = append(rewards.RewardUTXOs, network.RewardUTXO{
+			UTXOID:  utxo.InputID(),
+			AssetID: utxo.AssetID(),
+			Amount:  out.Amount(),
+		})
+	}
+	return rewards, nil
+}
+
+// GetStakingRewards returns the current validators of [subnetID], sorted by
+// node ID, with their delegators and the rewards pending on them
+func (ln *localNetwork) GetStakingRewards(ctx context.Context, subnetID ids.ID) ([]network.ValidatorStake, error) {
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	ln.lock.RLock()
+	nodeNames := make(map[ids.NodeID]string, len(ln.nodes))
+	for nodeName, node := range ln.nodes {
+		nodeNames[node.GetNodeID()] = nodeName
+	}
+	clientURI, err := ln.getClientURI()
+	ln.lock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	platformCli := platformvm.NewClient(clientURI)
+
+	cctx, cancel := createDefaultCtx(ctx)
+	vdrs, err := platformCli.GetCurrentValidators(cctx, subnetID, nil)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	if len(vdrs) == 0 {
+		return nil, nil
+	}
+	// delegators are only reported when the validators are given
+	nodeIDs := make([]ids.NodeID, len(vdrs))
+	for i, vdr := range vdrs {
+		nodeIDs[i] = vdr.NodeID
+	}
+	cctx, cancel = createDefaultCtx(ctx)
+	vdrs, err = platformCli.GetCurrentValidators(cctx, subnetID, nodeIDs)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	validators := make([]network.ValidatorStake, len(vdrs))
+	for i, vdr := range vdrs {
+		validator := network.ValidatorStake{
+			NodeName:      nodeNames[vdr.NodeID],
+			NodeID:        vdr.NodeID,
+			TxID:          vdr.TxID,
+			StartTime:     time.Unix(int64(vdr.StartTime), 0),
+			EndTime:       time.Unix(int64(vdr.EndTime), 0),
+			Weight:        vdr.Weight,
+			DelegationFee: vdr.DelegationFee,
+		}
+		if vdr.PotentialReward != nil {
+			validator.PotentialReward = *vdr.PotentialReward
+		}
+		if vdr.AccruedDelegateeReward != nil {
+			validator.AccruedDelegateeReward = *vdr.AccruedDelegateeReward
+		}
+		for _, delegator := range vdr.Delegators {
+			delegatorStake := network.DelegatorStake{
+				TxID:      delegator.TxID,
+				StartTime: time.Unix(int64(delegator.StartTime), 0),
+				EndTime:   time.Unix(int64(delegator.EndTime), 0),
+				Weight:    delegator.Weight,
+			}
+			if delegator.PotentialReward != nil {
+				delegatorStake.PotentialReward = *delegator.PotentialReward
+			}
+			validator.Delegators = append(validator.Delegators, delegatorStake)
+		}
+		validators[i] = validator
+	}
+	sort.Slice(validators, func(i, j int) bool {
+		return bytes.Compare(validators[i].NodeID[:], validators[j].NodeID[:]) < 0
+	})
+	return validators, nil
+}
+
 func createSubnets(
 	ctx context.Context,
 	numSubnets uint32,
@@ -1121,7 +1848,7 @@ func createSubnets(
 		)
 		cancel()
 		if err != nil {
-			return nil, fmt.Errorf("P-Wallet Tx Error %s %w", "IssueCreateSubnetTx", err)
+			return nil, newTxError(fmt.Errorf("P-Wallet Tx Error %s %w", "IssueCreateSubnetTx", err))
 		}
 		log.Info("created subnet tx", zap.String("subnet-ID", subnetID.String()))
 		subnetIDs[i] = subnetID
@@ -1171,15 +1898,22 @@ func (ln *localNetwork) addSubnetValidators(
 			if isValidator := subnetValidators.Contains(nodeID); isValidator {
 				continue
 			}
+			startTime := time.Now().Add(ln.validationStartOffset())
+			endTime := primaryValidatorsEndtime[nodeID]
+			if subnetSpecs[i].ValidationDuration != 0 {
+				if specEndTime := startTime.Add(subnetSpecs[i].ValidationDuration); specEndTime.Before(endTime) {
+					endTime = specEndTime
+				}
+			}
 			cctx, cancel := createDefaultCtx(ctx)
 			txID, err := w.pWallet.IssueAddSubnetValidatorTx(
 				&txs.SubnetValidator{
 					Validator: txs.Validator{
 						NodeID: nodeID,
 						// reasonable delay in most/slow test environments
-						Start: uint64(time.Now().Add(validationStartOffset).Unix()),
-						End:   uint64(primaryValidatorsEndtime[nodeID].Unix()),
-						Wght:  subnetValidatorsWeight,
+						Start: uint64(startTime.Unix()),
+						End:   uint64(endTime.Unix()),
+						Wght:  ln.subnetValidatorsWeight(subnetSpecs[i]),
 					},
 					Subnet: subnetID,
 				},
@@ -1188,7 +1922,7 @@ func (ln *localNetwork) addSubnetValidators(
 			)
 			cancel()
 			if err != nil {
-				return fmt.Errorf("P-Wallet Tx Error %s %w, node ID %s, subnetID %s", "IssueAddSubnetValidatorTx", err, nodeID.String(), subnetID.String())
+				return newTxError(fmt.Errorf("P-Wallet Tx Error %s %w, node ID %s, subnetID %s", "IssueAddSubnetValidatorTx", err, nodeID.String(), subnetID.String()))
 			}
 			ln.log.Info("added node as a subnet validator to subnet",
 				zap.String("node-name", nodeName),
@@ -1197,14 +1931,21 @@ func (ln *localNetwork) addSubnetValidators(
 				zap.String("tx-ID", txID.String()),
 			)
 		}
+		ln.reportProgress(
+			network.PhaseAddSubnetValidators,
+			i+1,
+			len(subnetIDs),
+			fmt.Sprintf("added the validators of subnet %s", subnetID),
+		)
 	}
 	return nil
 }
 
-// waits until all nodes start validating the primary network
+// waits for all nodes but the ones in [skipNodeNames] to be primary validators
 func (ln *localNetwork) waitPrimaryValidators(
 	ctx context.Context,
 	platformCli platformvm.Client,
+	skipNodeNames set.Set[string],
 ) error {
 	ln.log.Info(logging.Green.Wrap("waiting for the nodes to become primary validators"))
 	for {
@@ -1219,12 +1960,26 @@ func (ln *localNetwork) waitPrimaryValidators(
 		for _, v := range vs {
 			primaryValidators.Add(v.NodeID)
 		}
-		for _, node := range ln.nodes {
+		numValidators := 0
+		numNodes := 0
+		for nodeName, node := range ln.nodes {
+			if skipNodeNames.Contains(nodeName) {
+				continue
+			}
+			numNodes++
 			nodeID := node.GetNodeID()
 			if isValidator := primaryValidators.Contains(nodeID); !isValidator {
 				ready = false
+			} else {
+				numValidators++
 			}
 		}
+		ln.reportProgress(
+			network.PhaseWaitPrimaryValidators,
+			numValidators,
+			numNodes,
+			fmt.Sprintf("%d of %d nodes are validating", numValidators, numNodes),
+		)
 		if ready {
 			return nil
 		}
@@ -1248,6 +2003,7 @@ func (ln *localNetwork) waitSubnetValidators(
 	ln.log.Info(logging.Green.Wrap("waiting for the nodes to become subnet validators"))
 	for {
 		ready := true
+		numReady := 0
 		for i, subnetID := range subnetIDs {
 			cctx, cancel := createDefaultCtx(ctx)
 			vs, err := platformCli.GetCurrentValidators(cctx, subnetID, nil)
@@ -1273,7 +2029,14 @@ func (ln *localNetwork) waitSubnetValidators(
 			if !ready {
 				break
 			}
+			numReady++
 		}
+		ln.reportProgress(
+			network.PhaseWaitSubnetValidators,
+			numReady,
+			len(subnetIDs),
+			fmt.Sprintf("%d of %d subnets are validated", numReady, len(subnetIDs)),
+		)
 		if ready {
 			return nil
 		}
@@ -1287,14 +2050,24 @@ func (ln *localNetwork) waitSubnetValidators(
 	}
 }
 
+// Returns the work setting the subnets tracked by a node to [tracked].
+func setTrackedSubnetsFlag(tracked string) func(*node.Config) error {
+	return func(nodeConfig *node.Config) error {
+		nodeConfig.Flags[config.TrackSubnetsKey] = tracked
+		return nil
+	}
+}
+
 // reload VM plugins on all nodes
 func (ln *localNetwork) reloadVMPlugins(ctx context.Context) error {
 	ln.log.Info(logging.Green.Wrap("reloading plugin binaries"))
-	for _, node := range ln.nodes {
+	for nodeName, node := range ln.nodes {
 		if node.paused {
+			// the node loads the plugins once resumed
+			ln.queueForPausedNode(nodeName, queuedWork{description: "reload VM plugins"})
 			continue
 		}
-		uri := fmt.Sprintf("http://%s:%d", node.GetURL(), node.GetAPIPort())
+		uri := utils.HTTPURI(node.GetURL(), node.GetAPIPort())
 		adminCli := admin.NewClient(uri)
 		cctx, cancel := createDefaultCtx(ctx)
 		_, failedVMs, err := adminCli.LoadVMs(cctx)
@@ -1420,6 +2193,13 @@ func (ln *localNetwork) setBlockchainConfigFiles(
 				nodesToRestart.Add(nodeName)
 			}
 		}
+		// keep the files at network level, so nodes added later on also get them
+		if chainSpec.ChainConfig != nil {
+			ln.chainConfigFiles[chainAlias] = string(chainSpec.ChainConfig)
+		}
+		if chainSpec.NetworkUpgrade != nil {
+			ln.upgradeConfigFiles[chainAlias] = string(chainSpec.NetworkUpgrade)
+		}
 	}
 	return nodesToRestart, nil
 }
@@ -1439,12 +2219,14 @@ func (ln *localNetwork) setSubnetConfigFiles(
 				}
 				ln.nodes[nodeName].config.SubnetConfigFiles[subnetID.String()] = string(subnetConfig)
 			}
+			// keep the file at network level, so nodes added later on also get it
+			ln.subnetConfigFiles[subnetID.String()] = string(subnetConfig)
 		}
 	}
 	return nil
 }
 
-func (*localNetwork) createBlockchains(
+func (ln *localNetwork) createBlockchains(
 	ctx context.Context,
 	chainSpecs []network.BlockchainSpec,
 	blockchainTxs []*txs.Tx,
@@ -1473,7 +2255,7 @@ func (*localNetwork) createBlockchains(
 			defaultPoll,
 		)
 		if err != nil {
-			return fmt.Errorf("P-Wallet Tx Error %s %w, blockchainID %s", "IssueCreateBlockchainTx", err, blockchainTxs[i].ID().String())
+			return newTxError(fmt.Errorf("P-Wallet Tx Error %s %w, blockchainID %s", "IssueCreateBlockchainTx", err, blockchainTxs[i].ID().String()))
 		}
 		if blockchainID != blockchainTxs[i].ID() {
 			return fmt.Errorf("failure issuing create blockchain: txID differs from blockchainID")
@@ -1484,6 +2266,12 @@ func (*localNetwork) createBlockchains(
 			zap.String("vm-ID", vmID.String()),
 			zap.String("blockchain-ID", blockchainID.String()),
 		)
+		ln.reportProgress(
+			network.PhaseCreateBlockchains,
+			i+1,
+			len(chainSpecs),
+			fmt.Sprintf("created blockchain %s of vm %s", blockchainID, vmName),
+		)
 	}
 
 	return nil
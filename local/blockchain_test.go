@@ -0,0 +1,117 @@
+package local
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxdefi/netrunner/local/mocks"
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/network/node/status"
+	"github.com/luxdefi/node/config"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/luxdefi/node/utils/set"
+	"github.com/luxdefi/node/vms/secp256k1fx"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Creates processes whose first stop fails for the nodes in [failStop].
+type localTestFailedStopProcessCreator struct {
+	failStop map[string]bool
+}
+
+func (lt *localTestFailedStopProcessCreator) NewNodeProcess(config node.Config, _ []string, _ ...string) (NodeProcess, error) {
+	exitCode := 0
+	if lt.failStop[config.Name] {
+		exitCode = 1
+		delete(lt.failStop, config.Name)
+	}
+	process := &mocks.NodeProcess{}
+	process.On("Wait").Return(nil)
+	process.On("Stop", mock.Anything).Return(exitCode)
+	process.On("Status").Return(status.Running)
+	process.On("GetPID").Return(0)
+	return process, nil
+}
+
+func (*localTestFailedStopProcessCreator) GetNodeVersion(node.Config) (string, error) {
+	return nodeVersion, nil
+}
+
+// Assert that a node failing to stop while untracking removed subnets
+// doesn't prevent the others from being restarted, and that all of them
+// run again with the updated tracked subnets.
+func TestUntrackRemovedSubnetsFailedStop(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	networkConfig := testNetworkConfig(t)
+	for i := range networkConfig.NodeConfigs {
+		networkConfig.NodeConfigs[i].Flags[config.TrackSubnetsKey] = "subnet1,subnet2"
+	}
+	processCreator := &localTestFailedStopProcessCreator{failStop: map[string]bool{"node0": true}}
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, processCreator, "", "", false)
+	require.NoError(err)
+	require.NoError(net.loadConfig(ctx, networkConfig))
+	require.NoError(awaitNetworkHealthy(net, defaultHealthyTimeout))
+
+	err = net.untrackRemovedSubnets(ctx, []network.RemoveSubnetValidatorSpec{
+		{SubnetID: "subnet1", NodeNames: []string{"node0", "node1"}},
+	}, false)
+	require.ErrorContains(err, `node "node0" exited with exit code: 1`)
+
+	expectedTracked := map[string]string{
+		"node0": "subnet2",
+		"node1": "subnet2",
+		"node2": "subnet1,subnet2",
+	}
+	for nodeName, tracked := range expectedTracked {
+		node, err := net.GetNode(nodeName)
+		require.NoError(err)
+		require.False(node.GetPaused())
+		require.Equal(tracked, node.GetConfig().Flags[config.TrackSubnetsKey])
+	}
+	require.NoError(net.Stop(ctx))
+}
+
+func TestCheckPrimarySpecNodes(t *testing.T) {
+	require := require.New(t)
+
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, "", "", false)
+	require.NoError(err)
+	require.NoError(net.loadConfig(context.Background(), testNetworkConfig(t)))
+	primaryValidators := set.Set[ids.NodeID]{}
+	primaryValidators.Add(net.nodes["node0"].nodeID)
+
+	// nodes not validating yet, and nodes to be created
+	primarySpecNodeNames := set.Set[string]{}
+	primarySpecNodeNames.Add("node1", "node2", "node3")
+	require.NoError(net.checkPrimarySpecNodes(primarySpecNodeNames, primaryValidators))
+
+	primarySpecNodeNames.Add("node0")
+	err = net.checkPrimarySpecNodes(primarySpecNodeNames, primaryValidators)
+	require.ErrorIs(err, network.ErrInvalidValidatorSpec)
+	require.ErrorContains(err, "node0")
+	require.NoError(net.Stop(context.Background()))
+}
+
+func TestGetSpecOwner(t *testing.T) {
+	require := require.New(t)
+
+	w := &wallet{addr: ids.GenerateTestShortID()}
+	owner, err := getSpecOwner(w, nil, 0)
+	require.NoError(err)
+	require.Equal(&secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{w.addr}}, owner)
+
+	// the threshold defaults to one of the given owners
+	owner, err = getSpecOwner(w, []string{"P-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p"}, 0)
+	require.NoError(err)
+	require.Equal(uint32(1), owner.Threshold)
+	require.Len(owner.Addrs, 1)
+	require.NotEqual(w.addr, owner.Addrs[0])
+
+	_, err = getSpecOwner(w, []string{"P-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p"}, 2)
+	require.ErrorContains(err, "threshold 2 is greater than the number of owners 1")
+}
@@ -0,0 +1,139 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/luxdefi/node/vms/platformvm"
+	"go.uber.org/zap"
+)
+
+// DrainNode removes [nodeName] as validator of the permissioned subnets it
+// validates, waits for the removals to be reflected on the P-Chain validator
+// sets, and only then stops the node and removes it from the network.
+// Primary network and elastic subnet validations can't be removed, so they
+// are left to expire.
+func (ln *localNetwork) DrainNode(ctx context.Context, nodeName string) error {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	if ln.stopCalled() {
+		return network.ErrStopped
+	}
+	node, ok := ln.nodes[nodeName]
+	if !ok {
+		return fmt.Errorf("%w: %s", network.ErrNodeNotFound, nodeName)
+	}
+	nodeID := node.GetNodeID()
+
+	ln.log.Info(logging.Blue.Wrap(logging.Bold.Wrap("draining node")), zap.String("node", nodeName))
+
+	removableSubnetIDs, err := ln.getRemovableValidations(ctx, nodeName, nodeID)
+	if err != nil {
+		return err
+	}
+	if len(removableSubnetIDs) > 0 {
+		removeSubnetSpecs := make([]network.RemoveSubnetValidatorSpec, len(removableSubnetIDs))
+		for i, subnetID := range removableSubnetIDs {
+			removeSubnetSpecs[i] = network.RemoveSubnetValidatorSpec{
+				SubnetID:  subnetID.String(),
+				NodeNames: []string{nodeName},
+			}
+		}
+		// the node is stopped afterwards, so it isn't restarted to untrack the subnets
+		if err := ln.removeSubnetValidators(ctx, removeSubnetSpecs, true); err != nil {
+			return err
+		}
+		if err := ln.waitValidationsRemoved(ctx, nodeID, removableSubnetIDs); err != nil {
+			return err
+		}
+	}
+
+	ln.log.Info(logging.Green.Wrap("node drained, stopping it"), zap.String("node", nodeName))
+	return ln.removeNode(ctx, nodeName)
+}
+
+// Returns the permissioned subnets [nodeID] currently validates, in ID order.
+// Logs the validations that can't be removed.
+func (ln *localNetwork) getRemovableValidations(ctx context.Context, nodeName string, nodeID ids.NodeID) ([]ids.ID, error) {
+	clientURI, err := ln.getClientURI()
+	if err != nil {
+		return nil, err
+	}
+	platformCli := platformvm.NewClient(clientURI)
+	cctx, cancel := createDefaultCtx(ctx)
+	subnets, err := platformCli.GetSubnets(cctx, nil)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	subnetIDs := []ids.ID{constants.PrimaryNetworkID}
+	for _, subnet := range subnets {
+		if subnet.ID != constants.PrimaryNetworkID {
+			subnetIDs = append(subnetIDs, subnet.ID)
+		}
+	}
+
+	removableSubnetIDs := []ids.ID{}
+	for _, subnetID := range subnetIDs {
+		cctx, cancel := createDefaultCtx(ctx)
+		vs, err := platformCli.GetCurrentValidators(cctx, subnetID, []ids.NodeID{nodeID})
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		if len(vs) == 0 {
+			continue
+		}
+		_, isElastic := ln.subnetID2ElasticSubnetID[subnetID]
+		if subnetID == constants.PrimaryNetworkID || isElastic {
+			ln.log.Info("validation can't be removed, leaving it to expire",
+				zap.String("node", nodeName),
+				zap.String("subnet-ID", subnetID.String()),
+			)
+			continue
+		}
+		removableSubnetIDs = append(removableSubnetIDs, subnetID)
+	}
+	sort.Slice(removableSubnetIDs, func(i, j int) bool {
+		return removableSubnetIDs[i].String() < removableSubnetIDs[j].String()
+	})
+	return removableSubnetIDs, nil
+}
+
+// Waits until [nodeID] is not a current validator of any of [subnetIDs].
+func (ln *localNetwork) waitValidationsRemoved(ctx context.Context, nodeID ids.NodeID, subnetIDs []ids.ID) error {
+	clientURI, err := ln.getClientURI()
+	if err != nil {
+		return err
+	}
+	platformCli := platformvm.NewClient(clientURI)
+	for _, subnetID := range subnetIDs {
+		for {
+			cctx, cancel := createDefaultCtx(ctx)
+			vs, err := platformCli.GetCurrentValidators(cctx, subnetID, []ids.NodeID{nodeID})
+			cancel()
+			if err != nil {
+				return err
+			}
+			if len(vs) == 0 {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("node %s still validates subnet %s: %w", nodeID, subnetID, ctx.Err())
+			case <-time.After(waitForValidatorsPullFrequency):
+			}
+		}
+	}
+	return nil
+}
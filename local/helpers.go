@@ -1,16 +1,17 @@
 package local
 
 import (
-	"context"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
-	"net"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/luxdefi/netrunner/network"
 	"github.com/luxdefi/netrunner/network/node"
 	"github.com/luxdefi/node/config"
 	"github.com/luxdefi/node/utils/constants"
@@ -24,80 +25,81 @@ func init() {
 
 const (
 	MaxPort          = math.MaxUint16
-	minPort          = 10000
 	netListenTimeout = 3 * time.Second
 )
 
-// isFreePort verifies a given [port] is free
-func isFreePort(port uint16) error {
-	// Verify it's free by binding to it
-	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
-	if err != nil {
-		// Could not bind to [port]. Assumed to be not free.
-		return err
-	}
-	// We could bind to [port] so must be free.
-	_ = l.Close()
-	return nil
-}
-
-// getFreePort generates a random port number and then
-// verifies it is free. If it is, returns that port, otherwise retries.
-// Returns an error if no free port is found within [netListenTimeout].
-// Note that it is possible for [getFreePort] to return the same port twice.
-func getFreePort() (uint16, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), netListenTimeout)
-	defer cancel()
-	for {
-		select {
-		case <-ctx.Done():
-			return 0, ctx.Err()
-		default:
-			// Generate random port in [minPort, maxPort]
-			port := uint16(rand.Intn(MaxPort-minPort+1) + minPort) //nolint
-			if isFreePort(port) != nil {
-				// Not free. Try another.
-				continue
-			}
-			return port, nil
-		}
-	}
-}
-
 // writeFiles writes the files a node needs on startup.
 // It returns flags used to point to those files.
-func writeFiles(networkID uint32, genesis []byte, nodeRootDir string, nodeConfig *node.Config) (map[string]string, error) {
+// The staking keys are only written as files if [keysStorage] is
+// network.StakingKeysStorageFile or empty. Otherwise they are kept out of
+// [nodeRootDir], and given to the node process on its environment (see
+// getStakingKeysEnv), as the command line of the node can be read by any
+// user of the machine.
+// The files holding key material are only readable by the owner.
+func writeFiles(
+	networkID uint32,
+	genesis []byte,
+	nodeRootDir string,
+	nodeConfig *node.Config,
+	keysStorage string,
+) (map[string]string, error) {
 	type file struct {
 		pathKey   string
 		flagValue string
 		path      string
 		contents  []byte
+		private   bool
 	}
 	decodedStakingSigningKey, err := base64.StdEncoding.DecodeString(nodeConfig.StakingSigningKey)
 	if err != nil {
 		return nil, err
 	}
-	files := []file{
-		{
-			flagValue: filepath.Join(nodeRootDir, stakingKeyFileName),
-			path:      filepath.Join(nodeRootDir, stakingKeyFileName),
-			pathKey:   config.StakingTLSKeyPathKey,
-			contents:  []byte(nodeConfig.StakingKey),
-		},
-		{
-			flagValue: filepath.Join(nodeRootDir, stakingCertFileName),
-			path:      filepath.Join(nodeRootDir, stakingCertFileName),
-			pathKey:   config.StakingCertPathKey,
-			contents:  []byte(nodeConfig.StakingCert),
-		},
-		{
-			flagValue: filepath.Join(nodeRootDir, stakingSigningKeyFileName),
-			path:      filepath.Join(nodeRootDir, stakingSigningKeyFileName),
-			pathKey:   config.StakingSignerKeyPathKey,
-			contents:  decodedStakingSigningKey,
-		},
+	files := []file{}
+	flags := map[string]string{}
+	configFile := []byte(nodeConfig.ConfigFile)
+	stakingKeyPaths := []string{
+		filepath.Join(nodeRootDir, stakingKeyFileName),
+		filepath.Join(nodeRootDir, stakingCertFileName),
+		filepath.Join(nodeRootDir, stakingSigningKeyFileName),
+	}
+	if keysStorage == "" || keysStorage == network.StakingKeysStorageFile {
+		files = append(files,
+			file{
+				flagValue: stakingKeyPaths[0],
+				path:      stakingKeyPaths[0],
+				pathKey:   config.StakingTLSKeyPathKey,
+				contents:  []byte(nodeConfig.StakingKey),
+				private:   true,
+			},
+			file{
+				flagValue: stakingKeyPaths[1],
+				path:      stakingKeyPaths[1],
+				pathKey:   config.StakingCertPathKey,
+				contents:  []byte(nodeConfig.StakingCert),
+				private:   true,
+			},
+			file{
+				flagValue: stakingKeyPaths[2],
+				path:      stakingKeyPaths[2],
+				pathKey:   config.StakingSignerKeyPathKey,
+				contents:  decodedStakingSigningKey,
+				private:   true,
+			},
+		)
+	} else {
+		// the dir may be reused from a run storing the keys as files
+		stalePaths := stakingKeyPaths
+		if len(configFile) == 0 {
+			stalePaths = append(stalePaths, filepath.Join(nodeRootDir, configFileName))
+		}
+		for _, path := range stalePaths {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("couldn't remove file at %q: %w", path, err)
+			}
+		}
 	}
-	if networkID != constants.LocalID {
+	// standard networks joined as external networks have no genesis given
+	if networkID != constants.LocalID && len(genesis) != 0 {
 		files = append(files, file{
 			flagValue: filepath.Join(nodeRootDir, genesisFileName),
 			path:      filepath.Join(nodeRootDir, genesisFileName),
@@ -105,18 +107,22 @@ func writeFiles(networkID uint32, genesis []byte, nodeRootDir string, nodeConfig
 			contents:  genesis,
 		})
 	}
-	if len(nodeConfig.ConfigFile) != 0 {
+	if len(configFile) != 0 {
 		files = append(files, file{
 			flagValue: filepath.Join(nodeRootDir, configFileName),
 			path:      filepath.Join(nodeRootDir, configFileName),
 			pathKey:   config.ConfigFileKey,
-			contents:  []byte(nodeConfig.ConfigFile),
+			contents:  configFile,
+			private:   true,
 		})
 	}
-	flags := map[string]string{}
 	for _, f := range files {
 		flags[f.pathKey] = f.flagValue
-		if err := createFileAndWrite(f.path, f.contents); err != nil {
+		write := createFileAndWrite
+		if f.private {
+			write = createPrivateFileAndWrite
+		}
+		if err := write(f.path, f.contents); err != nil {
 			return nil, fmt.Errorf("couldn't write file at %q: %w", f.path, err)
 		}
 	}
@@ -156,6 +162,72 @@ func writeFiles(networkID uint32, genesis []byte, nodeRootDir string, nodeConfig
 	return flags, nil
 }
 
+// getStakingKeysEnv returns the environment entries giving the staking
+// keys of [nodeConfig] to the node as content of its staking key flags.
+// The environment of a process is only readable by its owner.
+func getStakingKeysEnv(nodeConfig *node.Config) []string {
+	keys := []struct {
+		flag  string
+		value string
+	}{
+		{flag: config.StakingTLSKeyContentKey, value: base64.StdEncoding.EncodeToString([]byte(nodeConfig.StakingKey))},
+		{flag: config.StakingCertContentKey, value: base64.StdEncoding.EncodeToString([]byte(nodeConfig.StakingCert))},
+		{flag: config.StakingSignerKeyContentKey, value: nodeConfig.StakingSigningKey},
+	}
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		env = append(env, config.EnvVarName(config.EnvPrefix, key.flag)+"="+key.value)
+	}
+	return env
+}
+
+// applyNodeMode expands the storage mode of [nodeConfig] to its node flags
+// and C-Chain config. The mode overrides the network flags and chain
+// configs, but not the flags given in [nodeConfig].
+func applyNodeMode(nodeConfig *node.Config) error {
+	var (
+		indexEnabled bool
+		cChainConfig map[string]interface{}
+	)
+	switch nodeConfig.Mode {
+	case "":
+		return nil
+	case node.ModeArchival:
+		indexEnabled = true
+		cChainConfig = map[string]interface{}{
+			"pruning-enabled": false,
+			// synced state has no history
+			"state-sync-enabled": false,
+		}
+	case node.ModePruned:
+		indexEnabled = false
+		cChainConfig = map[string]interface{}{
+			"pruning-enabled": true,
+		}
+	default:
+		return fmt.Errorf("%w: %q", node.ErrInvalidMode, nodeConfig.Mode)
+	}
+
+	if _, ok := nodeConfig.Flags[config.IndexEnabledKey]; !ok {
+		nodeConfig.Flags[config.IndexEnabledKey] = indexEnabled
+	}
+	cChainConfigMap := map[string]interface{}{}
+	if cChainConfigFile := nodeConfig.ChainConfigFiles["C"]; cChainConfigFile != "" {
+		if err := json.Unmarshal([]byte(cChainConfigFile), &cChainConfigMap); err != nil {
+			return fmt.Errorf("couldn't unmarshal C-Chain config of node %q: %w", nodeConfig.Name, err)
+		}
+	}
+	for k, v := range cChainConfig {
+		cChainConfigMap[k] = v
+	}
+	cChainConfigBytes, err := json.Marshal(cChainConfigMap)
+	if err != nil {
+		return err
+	}
+	nodeConfig.ChainConfigFiles["C"] = string(cChainConfigBytes)
+	return nil
+}
+
 // getConfigEntry returns an entry in the config file if it is found, otherwise returns the default value
 func getConfigEntry(
 	nodeConfigFlags map[string]interface{},
@@ -179,13 +251,16 @@ func getConfigEntry(
 	return defaultVal, nil
 }
 
-// getPort looks up the port config in the config file, if there is none, it tries to get a random free port from the OS
-// if [reassingIfUsed] is true, and the port from config is not free, also tries to get a random free port
+// getPort looks up the port config in the config file, if there is none, it reserves a random free port in [portRange]
+// if [reassignIfUsed] is true, and the port from config is in use, also reserves a random free port
+// The returned port is reserved, and must be released before the node process binds it.
 func getPort(
 	flags map[string]interface{},
 	configFile map[string]interface{},
 	portKey string,
 	reassignIfUsed bool,
+	portRange network.PortRange,
+	rng *rand.Rand,
 ) (port uint16, err error) {
 	if portIntf, ok := flags[portKey]; ok {
 		switch gotPort := portIntf.(type) {
@@ -203,21 +278,22 @@ func getPort(
 		}
 		port = uint16(portFromConfigFile)
 	} else {
-		// Use a random free port.
-		// Note: it is possible but unlikely for getFreePort to return the same port multiple times.
-		port, err = getFreePort()
+		port, err = ports.reserveFree(portRange, rng)
 		if err != nil {
 			return 0, fmt.Errorf("couldn't get free port: %w", err)
 		}
+		return port, nil
 	}
-	if reassignIfUsed && isFreePort(port) != nil {
-		port, err = getFreePort()
+	err = ports.reserve(port)
+	if err != nil && reassignIfUsed && isPortInUse(err) {
+		port, err = ports.reserveFree(portRange, rng)
 		if err != nil {
 			return 0, fmt.Errorf("couldn't get free port: %w", err)
 		}
+		return port, nil
 	}
-	// last check, avoid starting network with used ports
-	if err := isFreePort(port); err != nil {
+	// avoid starting network with used ports
+	if err != nil {
 		return 0, fmt.Errorf("port %d is not free: %w", port, err)
 	}
 	return port, nil
@@ -260,6 +336,18 @@ func createFileAndWrite(path string, contents []byte) error {
 	return err
 }
 
+// createPrivateFileAndWrite is like createFileAndWrite, but the file is
+// only readable by the owner, even if it already existed
+func createPrivateFileAndWrite(path string, contents []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return os.WriteFile(path, contents, 0o600)
+}
+
 // addNetworkFlags adds the flags in [networkFlags] to [nodeConfig.Flags].
 // [nodeFlags] must not be nil.
 func addNetworkFlags(networkFlags map[string]interface{}, nodeFlags map[string]interface{}) {
@@ -0,0 +1,86 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/utils"
+	"github.com/luxdefi/netrunner/utils/constants"
+)
+
+var ErrNoKeystorePassphrase = fmt.Errorf("no staking keystore passphrase given on env var %s", constants.StakingKeystorePassphraseEnvVar)
+
+// getKeystorePassphrase returns the passphrase of the staking keystores,
+// as given on the server env
+func getKeystorePassphrase() (string, error) {
+	passphrase := os.Getenv(constants.StakingKeystorePassphraseEnvVar)
+	if passphrase == "" {
+		return "", ErrNoKeystorePassphrase
+	}
+	return passphrase, nil
+}
+
+// Staking keys of a node, as kept by its keystore
+type stakingKeys struct {
+	StakingKey        string `json:"stakingKey"`
+	StakingCert       string `json:"stakingCert"`
+	StakingSigningKey string `json:"stakingSigningKey"`
+}
+
+// writeStakingKeystore writes the staking keys of [nodeConfig] into
+// [nodeDir], encrypted with [passphrase]
+func writeStakingKeystore(nodeDir string, nodeConfig *node.Config, passphrase string) error {
+	keysBytes, err := json.Marshal(stakingKeys{
+		StakingKey:        nodeConfig.StakingKey,
+		StakingCert:       nodeConfig.StakingCert,
+		StakingSigningKey: nodeConfig.StakingSigningKey,
+	})
+	if err != nil {
+		return err
+	}
+	keystoreBytes, err := utils.EncryptKeystore(keysBytes, passphrase)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(nodeDir, stakingKeystoreFileName)
+	if err := os.MkdirAll(nodeDir, 0o750); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, keystoreBytes, 0o600); err != nil {
+		return fmt.Errorf("couldn't write file at %q: %w", path, err)
+	}
+	return nil
+}
+
+// readStakingKeystore sets the empty staking keys of [nodeConfig] to the
+// ones of the keystore in [nodeDir], if there is one
+func readStakingKeystore(nodeDir string, nodeConfig *node.Config, passphrase string) error {
+	path := filepath.Join(nodeDir, stakingKeystoreFileName)
+	keystoreBytes, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("couldn't read file at %q: %w", path, err)
+	}
+	keysBytes, err := utils.DecryptKeystore(keystoreBytes, passphrase)
+	if err != nil {
+		return fmt.Errorf("couldn't decrypt staking keystore at %q: %w", path, err)
+	}
+	var keys stakingKeys
+	if err := json.Unmarshal(keysBytes, &keys); err != nil {
+		return fmt.Errorf("couldn't unmarshal staking keystore at %q: %w", path, err)
+	}
+	if nodeConfig.StakingCert == "" || nodeConfig.StakingKey == "" {
+		nodeConfig.StakingCert = keys.StakingCert
+		nodeConfig.StakingKey = keys.StakingKey
+	}
+	if nodeConfig.StakingSigningKey == "" {
+		nodeConfig.StakingSigningKey = keys.StakingSigningKey
+	}
+	return nil
+}
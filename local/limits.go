@@ -0,0 +1,73 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"syscall"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/utils/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	// open files kept by this process for each node: output pipes, API
+	// connections and log tails
+	fileDescriptorsPerNode = 16
+	// open files kept by this process regardless of the nodes
+	baseFileDescriptors = 1024
+	// max number of health requests in flight while waiting for the
+	// network to become healthy
+	maxConcurrentHealthChecks = 32
+)
+
+var ErrFileLimit = errors.New("open files limit too low")
+
+// Checks the open files limit of this process is enough to run [numNodes]
+// nodes, raising the soft limit up to the hard one if needed. The node
+// processes inherit the raised limit.
+func checkFileLimit(log logging.Logger, numNodes int) error {
+	required := uint64(baseFileDescriptors + numNodes*fileDescriptorsPerNode)
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return fmt.Errorf("couldn't get open files limit: %w", err)
+	}
+	if limit.Cur >= required {
+		return nil
+	}
+	if limit.Max < required {
+		return fmt.Errorf("%w: %d nodes need %d open files, but the hard limit is %d (raise it with `ulimit -Hn`)",
+			ErrFileLimit, numNodes, required, limit.Max)
+	}
+	soft := limit.Cur
+	limit.Cur = required
+	if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return fmt.Errorf("couldn't raise open files limit to %d: %w", required, err)
+	}
+	log.Info("raised open files limit", zap.Uint64("from", soft), zap.Uint64("to", required))
+	return nil
+}
+
+// Warns if [portRange] is too small to assign the API and P2P ports
+// of [numNodes] nodes without retrying on collisions.
+func checkPortRange(log logging.Logger, portRange network.PortRange, numNodes int) {
+	minPort, maxPort := portRange.Bounds()
+	available := int(maxPort) - int(minPort) + 1
+	if available < 4*numNodes {
+		log.Warn("port range is small for the number of nodes, port assignment may fail",
+			zap.Uint16("min-port", minPort),
+			zap.Uint16("max-port", maxPort),
+			zap.Int("num-nodes", numNodes),
+		)
+	}
+}
+
+// Returns the default logs dir of the next node added with name [nodeName],
+// if logs sharding is enabled. Nodes are grouped in shards of
+// [ln.logsShardSize] nodes, under the logs dir of the network root dir.
+// Assumes [ln.lock] is held.
+func (ln *localNetwork) getShardedLogsDir(nodeName string) string {
+	shard := ln.numShardedLogsDirs/ln.logsShardSize + 1
+	return filepath.Join(ln.rootDir, defaultLogsSubdir, fmt.Sprintf("shard-%03d", shard), nodeName)
+}
@@ -0,0 +1,23 @@
+package local
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetShardedLogsDir(t *testing.T) {
+	require := require.New(t)
+
+	ln := &localNetwork{rootDir: "/tmp/network", logsShardSize: 2}
+	expected := []string{
+		"/tmp/network/logs/shard-001/node1",
+		"/tmp/network/logs/shard-001/node2",
+		"/tmp/network/logs/shard-002/node3",
+	}
+	for i, logsDir := range expected {
+		require.Equal(filepath.FromSlash(logsDir), ln.getShardedLogsDir(filepath.Base(logsDir)), i)
+		ln.numShardedLogsDirs++
+	}
+}
@@ -17,6 +17,20 @@ type NodeProcess struct {
 	mock.Mock
 }
 
+// GetPID provides a mock function with given fields:
+func (_m *NodeProcess) GetPID() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
 // Status provides a mock function with given fields:
 func (_m *NodeProcess) Status() status.Status {
 	ret := _m.Called()
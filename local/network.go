@@ -8,13 +8,16 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"math/rand"
 	"net"
 	"os"
 	"os/user"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -26,7 +29,6 @@ import (
 	"github.com/luxdefi/netrunner/utils/constants"
 	"github.com/luxdefi/node/config"
 	"github.com/luxdefi/node/ids"
-	"github.com/luxdefi/node/network/peer"
 	"github.com/luxdefi/node/staking"
 	"github.com/luxdefi/node/utils/beacon"
 	"github.com/luxdefi/node/utils/crypto/bls"
@@ -47,8 +49,11 @@ const (
 	stakingKeyFileName        = "staking.key"
 	stakingCertFileName       = "staking.crt"
 	stakingSigningKeyFileName = "signer.key"
+	stakingKeystoreFileName   = "staking.keystore"
+	snapshotKeystoresSubdir   = "keystores"
 	genesisFileName           = "genesis.json"
 	stopTimeout               = 30 * time.Second
+	maxPortsRetries           = 3
 	healthCheckFreq           = 3 * time.Second
 	DefaultNumNodes           = 5
 	snapshotPrefix            = "anr-snapshot-"
@@ -79,6 +84,8 @@ var (
 
 // network keeps information uses for network management, and accessing all the nodes
 type localNetwork struct {
+	// serializes the operations on the network, and may be held for
+	// minutes while waiting for health or installing blockchains
 	lock sync.RWMutex
 	log  logging.Logger
 	// This network's ID.
@@ -97,6 +104,10 @@ type localNetwork struct {
 	nextNodeSuffix uint64
 	// Node Name --> Node
 	nodes map[string]*localNode
+	// guards [nodes] and the paused state of its nodes. Written holding
+	// both [lock] and [nodesLock], so holding either is enough to read
+	// them. Never held while waiting on the nodes.
+	nodesLock sync.RWMutex
 	// Set of nodes that new nodes will bootstrap from.
 	bootstraps beacon.Set
 	// rootDir is the root directory under which we write all node
@@ -118,6 +129,46 @@ type localNetwork struct {
 	reassignPortsIfUsed bool
 	// map from subnet id to elastic subnet tx id
 	subnetID2ElasticSubnetID map[ids.ID]ids.ID
+	// blockchains created on the network
+	blockchains []blockchainInfo
+	// blockchains loaded from the persisted network state, which the
+	// nodes have to run again before the network is healthy
+	reloadedBlockchains []blockchainInfo
+	// staking parameters used when adding validators
+	stakingSpec network.StakingSpec
+	// range of the ports assigned to nodes, when not given in their configs
+	portRange network.PortRange
+	// if not zero, the generated keys and the ports derive from it
+	seed int64
+	// source of the port choices, seeded with [seed] if given
+	rand *rand.Rand
+	// number of nodes per logs shard dir. no sharding if zero
+	logsShardSize int
+	// templates of the node dirs, if not under [rootDir]
+	dirLayout network.DirLayout
+	// how the staking keys are given to the nodes, see network.Config
+	stakingKeysStorage string
+	// passphrase of the staking keystores, if [stakingKeysStorage] is
+	// network.StakingKeysStorageKeystore
+	keystorePassphrase string
+	// number of nodes added with a sharded logs dir
+	numShardedLogsDirs int
+	// map from chain id to its aliases, applied to all nodes
+	chainAliases map[string][]string
+	// map from vm id to its aliases, applied to all nodes
+	vmAliases map[string][]string
+	// called with the progress of the long operations. May be nil
+	onProgress network.ProgressHandler
+	// signs the txs issued by the network. May be nil, in which case
+	// the key funded by the default genesis is used
+	txSigner network.TxSigner
+	// binaries of the nodes found not to track subnets at runtime
+	runtimeTrackingUnsupported set.Set[string]
+	// map from paused node name to the work skipped on it, which is applied
+	// to the node config when the node is resumed
+	pausedNodesQueuedWork map[string][]queuedWork
+	// names of the nodes whose primary network validations are being renewed
+	renewingNodes set.Set[string]
 }
 
 type deprecatedFlagEsp struct {
@@ -138,6 +189,8 @@ var (
 	// TODO add method Copy() to network.Config to prevent
 	// accidental overwriting
 	defaultNetworkConfig network.Config
+	// start time of the default genesis, the time this process started at
+	defaultGenesisStartTime int64
 	// snapshots directory
 	defaultSnapshotsDir string
 )
@@ -154,35 +207,8 @@ func init() {
 		panic(err)
 	}
 
-	startTime := time.Now().Unix()
-	lockTime := startTime + genesisLocktimeStartimeDelta
-	genesisMap["startTime"] = float64(startTime)
-	allocations, ok := genesisMap["allocations"].([]interface{})
-	if !ok {
-		panic(errors.New("could not get allocations in genesis"))
-	}
-	for _, allocIntf := range allocations {
-		alloc, ok := allocIntf.(map[string]interface{})
-		if !ok {
-			panic(fmt.Errorf("unexpected type for allocation in genesis. got %T", allocIntf))
-		}
-		unlockSchedule, ok := alloc["unlockSchedule"].([]interface{})
-		if !ok {
-			panic(errors.New("could not get unlockSchedule in allocation"))
-		}
-		for _, schedIntf := range unlockSchedule {
-			sched, ok := schedIntf.(map[string]interface{})
-			if !ok {
-				panic(fmt.Errorf("unexpected type for unlockSchedule elem in genesis. got %T", schedIntf))
-			}
-			if _, ok := sched["locktime"]; ok {
-				sched["locktime"] = float64(lockTime)
-			}
-		}
-	}
-
-	// now we can marshal the *whole* thing into bytes
-	updatedGenesis, err := json.Marshal(genesisMap)
+	defaultGenesisStartTime = time.Now().Unix()
+	updatedGenesis, err := setGenesisStartTime(genesisMap, defaultGenesisStartTime)
 	if err != nil {
 		panic(err)
 	}
@@ -255,10 +281,53 @@ func init() {
 	defaultSnapshotsDir = filepath.Join(usr.HomeDir, snapshotsRelPath)
 }
 
+// Returns the dir the snapshots are saved to, given [snapshotsDir] as
+// configured, which may be empty to use the default one.
+func GetSnapshotsDir(snapshotsDir string) string {
+	if snapshotsDir == "" {
+		return defaultSnapshotsDir
+	}
+	return snapshotsDir
+}
+
+// Sets the start time of [genesisMap] to [startTime], along with the
+// locktimes relative to it, and returns the updated genesis.
+func setGenesisStartTime(genesisMap map[string]interface{}, startTime int64) ([]byte, error) {
+	lockTime := startTime + genesisLocktimeStartimeDelta
+	genesisMap["startTime"] = float64(startTime)
+	allocations, ok := genesisMap["allocations"].([]interface{})
+	if !ok {
+		return nil, errors.New("could not get allocations in genesis")
+	}
+	for _, allocIntf := range allocations {
+		alloc, ok := allocIntf.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected type for allocation in genesis. got %T", allocIntf)
+		}
+		unlockSchedule, ok := alloc["unlockSchedule"].([]interface{})
+		if !ok {
+			return nil, errors.New("could not get unlockSchedule in allocation")
+		}
+		for _, schedIntf := range unlockSchedule {
+			sched, ok := schedIntf.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unexpected type for unlockSchedule elem in genesis. got %T", schedIntf)
+			}
+			if _, ok := sched["locktime"]; ok {
+				sched["locktime"] = float64(lockTime)
+			}
+		}
+	}
+
+	// now we can marshal the *whole* thing into bytes
+	return json.Marshal(genesisMap)
+}
+
 // NewNetwork returns a new network that uses the given log.
 // Files (e.g. logs, databases) default to being written at directory [rootDir].
 // If there isn't a directory at [dir] one will be created.
 // If len([dir]) == 0, files will be written underneath a new temporary directory.
+// If [dir] holds the network state persisted by a previous network, it is loaded.
 // Snapshots are saved to snapshotsDir, defaults to defaultSnapshotsDir if not given
 func NewNetwork(
 	log logging.Logger,
@@ -267,6 +336,20 @@ func NewNetwork(
 	snapshotsDir string,
 	reassignPortsIfUsed bool,
 ) (network.Network, error) {
+	return newNetworkWithOptions(log, networkConfig, network.BackendOptions{
+		RootDataDir:         rootDir,
+		SnapshotsDir:        snapshotsDir,
+		ReassignPortsIfUsed: reassignPortsIfUsed,
+	})
+}
+
+// See NewNetwork. The progress of the network operations is
+// reported to [opts.OnProgress].
+func newNetworkWithOptions(
+	log logging.Logger,
+	networkConfig network.Config,
+	opts network.BackendOptions,
+) (*localNetwork, error) {
 	net, err := newNetwork(
 		log,
 		api.NewAPIClient,
@@ -276,13 +359,18 @@ func NewNetwork(
 			stdout:      os.Stdout,
 			stderr:      os.Stderr,
 		},
-		rootDir,
-		snapshotsDir,
-		reassignPortsIfUsed,
+		opts.RootDataDir,
+		opts.SnapshotsDir,
+		opts.ReassignPortsIfUsed,
 	)
 	if err != nil {
 		return net, err
 	}
+	net.onProgress = opts.OnProgress
+	net.txSigner = opts.TxSigner
+	if err := net.loadPersistedNetworkState(); err != nil {
+		return net, err
+	}
 	return net, net.loadConfig(context.Background(), networkConfig)
 }
 
@@ -310,9 +398,7 @@ func newNetwork(
 			return nil, err
 		}
 	}
-	if snapshotsDir == "" {
-		snapshotsDir = defaultSnapshotsDir
-	}
+	snapshotsDir = GetSnapshotsDir(snapshotsDir)
 	// create the snapshots dir if not present
 	err = os.MkdirAll(snapshotsDir, os.ModePerm)
 	if err != nil {
@@ -331,6 +417,9 @@ func newNetwork(
 		snapshotsDir:             snapshotsDir,
 		reassignPortsIfUsed:      reassignPortsIfUsed,
 		subnetID2ElasticSubnetID: map[ids.ID]ids.ID{},
+		chainAliases:             map[string][]string{},
+		vmAliases:                map[string][]string{},
+		pausedNodesQueuedWork:    map[string][]queuedWork{},
 	}
 	return net, nil
 }
@@ -379,7 +468,9 @@ func NewDefaultConfig(binaryPath string) network.Config {
 	return config
 }
 
-// NewDefaultConfigNNodes creates a new default network config, with an arbitrary number of nodes
+// NewDefaultConfigNNodes creates a new default network config, with an arbitrary number of nodes.
+// The nodes past the default ones are given no staking and signing keys, so they are generated
+// when the nodes are added to the network, derived from the network seed if given.
 func NewDefaultConfigNNodes(binaryPath string, numNodes uint32) (network.Config, error) {
 	netConfig := NewDefaultConfig(binaryPath)
 	if int(numNodes) > len(netConfig.NodeConfigs) {
@@ -403,12 +494,11 @@ func NewDefaultConfigNNodes(binaryPath string, numNodes uint32) (network.Config,
 		}
 		for i := 0; i < toAdd; i++ {
 			nodeConfig := refNodeConfig
-			stakingCert, stakingKey, err := staking.NewCertAndKeyBytes()
-			if err != nil {
-				return netConfig, fmt.Errorf("couldn't generate staking Cert/Key: %w", err)
-			}
-			nodeConfig.StakingKey = string(stakingKey)
-			nodeConfig.StakingCert = string(stakingCert)
+			// the keys are generated when the node is added to the network,
+			// so they derive from the network seed if given
+			nodeConfig.StakingKey = ""
+			nodeConfig.StakingCert = ""
+			nodeConfig.StakingSigningKey = ""
 			// replace ports
 			nodeConfig.Flags = map[string]interface{}{
 				config.HTTPPortKey:    int(refAPIPort) + (i+1)*2,
@@ -430,28 +520,72 @@ func (ln *localNetwork) loadConfig(ctx context.Context, networkConfig network.Co
 	ln.log.Info("creating network", zap.Int("node-num", len(networkConfig.NodeConfigs)))
 
 	ln.genesis = []byte(networkConfig.Genesis)
+	ln.seed = networkConfig.Seed
+	randSeed := ln.seed
+	if ln.seed != 0 {
+		ln.log.Info("deriving the network randomness from seed", zap.Int64("seed", ln.seed))
+		// the start time of the default genesis, also kept by the genesis
+		// derived from it (e.g. with another network ID), is the time this
+		// process started at, so it is replaced by one only moving forward
+		// in windows, with the genesis validations lasting past the current
+		// time.
+		// an external network may have no genesis given
+		var genesisMap map[string]interface{}
+		if err := json.Unmarshal(ln.genesis, &genesisMap); err == nil {
+			if startTime, ok := genesisMap["startTime"].(float64); ok && int64(startTime) == defaultGenesisStartTime {
+				genesisMap["initialStakeDuration"] = network.SeededInitialStakeDuration.Seconds()
+				ln.genesis, err = setGenesisStartTime(genesisMap, network.GetSeededStartTime(time.Now()).Unix())
+				if err != nil {
+					return err
+				}
+			}
+		}
+	} else {
+		randSeed = time.Now().UnixNano()
+	}
+	ln.rand = rand.New(rand.NewSource(randSeed)) //nolint:gosec
 
 	var err error
-	ln.networkID, err = utils.NetworkIDFromGenesis([]byte(networkConfig.Genesis))
+	ln.networkID, err = networkConfig.GetNetworkID()
 	if err != nil {
-		return fmt.Errorf("couldn't get network ID from genesis: %w", err)
+		return err
+	}
+	if err := ln.addExternalBootstraps(networkConfig.ExternalNetwork); err != nil {
+		return err
 	}
 
 	// save node defaults
 	ln.flags = networkConfig.Flags
 	ln.binaryPath = networkConfig.BinaryPath
-	ln.chainConfigFiles = networkConfig.ChainConfigFiles
+	ln.chainConfigFiles = maps.Clone(networkConfig.ChainConfigFiles)
 	if ln.chainConfigFiles == nil {
 		ln.chainConfigFiles = map[string]string{}
 	}
-	ln.upgradeConfigFiles = networkConfig.UpgradeConfigFiles
+	ln.upgradeConfigFiles = maps.Clone(networkConfig.UpgradeConfigFiles)
 	if ln.upgradeConfigFiles == nil {
 		ln.upgradeConfigFiles = map[string]string{}
 	}
-	ln.subnetConfigFiles = networkConfig.SubnetConfigFiles
+	ln.subnetConfigFiles = maps.Clone(networkConfig.SubnetConfigFiles)
 	if ln.subnetConfigFiles == nil {
 		ln.subnetConfigFiles = map[string]string{}
 	}
+	ln.stakingSpec = networkConfig.StakingSpec
+	ln.portRange = networkConfig.PortRange
+	ln.logsShardSize = networkConfig.LogsShardSize
+	ln.dirLayout = networkConfig.DirLayout
+	ln.stakingKeysStorage = networkConfig.StakingKeysStorage
+	if ln.stakingKeysStorage == network.StakingKeysStorageKeystore {
+		passphrase, err := getKeystorePassphrase()
+		if err != nil {
+			return err
+		}
+		ln.keystorePassphrase = passphrase
+	}
+
+	if err := checkFileLimit(ln.log, len(networkConfig.NodeConfigs)); err != nil {
+		return err
+	}
+	checkPortRange(ln.log, ln.portRange, len(networkConfig.NodeConfigs))
 
 	// Sort node configs so beacons start first
 	var nodeConfigs []node.Config
@@ -479,6 +613,32 @@ func (ln *localNetwork) loadConfig(ctx context.Context, networkConfig network.Co
 	return nil
 }
 
+// Adds the bootstrap nodes of [externalNetwork], if given, to the beacons
+// of the nodes.
+func (ln *localNetwork) addExternalBootstraps(externalNetwork *network.ExternalNetworkSpec) error {
+	if externalNetwork == nil {
+		return nil
+	}
+	ln.log.Info("joining external network",
+		zap.Uint32("network-id", externalNetwork.NetworkID),
+		zap.Strings("bootstrap-ips", externalNetwork.BootstrapIPs),
+	)
+	for i, ip := range externalNetwork.BootstrapIPs {
+		ipPort, err := ips.ToIPPort(ip)
+		if err != nil {
+			return err
+		}
+		nodeID, err := ids.NodeIDFromString(externalNetwork.BootstrapIDs[i])
+		if err != nil {
+			return err
+		}
+		if err := ln.bootstraps.Add(beacon.New(nodeID, ipPort)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // See network.Network
 func (ln *localNetwork) AddNode(nodeConfig node.Config) (node.Node, error) {
 	ln.lock.Lock()
@@ -528,12 +688,38 @@ func (ln *localNetwork) addNode(nodeConfig node.Config) (node.Node, error) {
 			nodeConfig.SubnetConfigFiles[k] = v
 		}
 	}
+	if err := applyNodeMode(&nodeConfig); err != nil {
+		return nil, err
+	}
 	addNetworkFlags(ln.flags, nodeConfig.Flags)
 
+	// the name is set first, as the keys generated from the seed derive
+	// from it
+	if err := ln.setNodeName(&nodeConfig); err != nil {
+		return nil, err
+	}
+
+	// the node dir is only created under the root dir if not placed elsewhere
+	nodeDir := ln.getLayoutDir(nodeConfig.DataDir, ln.dirLayout.DataDir, nodeConfig.Name)
+	if nodeDir == "" {
+		var err error
+		nodeDir, err = makeNodeDir(ln.log, ln.rootDir, nodeConfig.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// a node restarted on its dir keeps the keys of its keystore
+	if ln.stakingKeysStorage == network.StakingKeysStorageKeystore {
+		if err := readStakingKeystore(nodeDir, &nodeConfig, ln.keystorePassphrase); err != nil {
+			return nil, err
+		}
+	}
+
 	// it shouldn't happen that just one is empty, most probably both,
 	// but in any case if just one is empty it's unusable so we just assign a new one.
 	if nodeConfig.StakingCert == "" || nodeConfig.StakingKey == "" {
-		stakingCert, stakingKey, err := staking.NewCertAndKeyBytes()
+		stakingCert, stakingKey, err := ln.newStakingCertAndKey(nodeConfig.Name)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't generate staking Cert/Key: %w", err)
 		}
@@ -541,26 +727,22 @@ func (ln *localNetwork) addNode(nodeConfig node.Config) (node.Node, error) {
 		nodeConfig.StakingKey = string(stakingKey)
 	}
 	if nodeConfig.StakingSigningKey == "" {
-		key, err := bls.NewSecretKey()
+		keyBytes, err := ln.newSigningKey(nodeConfig.Name)
 		if err != nil {
 			return nil, fmt.Errorf("couldn't generate new signing key: %w", err)
 		}
-		keyBytes := bls.SecretKeyToBytes(key)
 		encodedKey := base64.StdEncoding.EncodeToString(keyBytes)
 		nodeConfig.StakingSigningKey = encodedKey
 	}
 
-	if err := ln.setNodeName(&nodeConfig); err != nil {
-		return nil, err
+	if ln.stakingKeysStorage == network.StakingKeysStorageKeystore {
+		if err := writeStakingKeystore(nodeDir, &nodeConfig, ln.keystorePassphrase); err != nil {
+			return nil, err
+		}
 	}
 
 	isPausedNode := ln.isPausedNode(&nodeConfig)
 
-	nodeDir, err := makeNodeDir(ln.log, ln.rootDir, nodeConfig.Name)
-	if err != nil {
-		return nil, err
-	}
-
 	// If config file is given, don't overwrite API port, P2P port, DB path, logs path
 	var configFile map[string]interface{}
 	if len(nodeConfig.ConfigFile) != 0 {
@@ -575,10 +757,22 @@ func (ln *localNetwork) addNode(nodeConfig node.Config) (node.Node, error) {
 		return nil, err
 	}
 
-	nodeData, err := ln.buildArgs(nodeSemVer, configFile, nodeDir, &nodeConfig)
+	// the aliases kept at network level are only merged into the args, so
+	// the node config keeps the aliases given by the user, and a removed
+	// alias is not given again on restart
+	argsConfig := nodeConfig
+	argsConfig.Flags = maps.Clone(nodeConfig.Flags)
+	if err := ln.setAliasesFlags(&argsConfig); err != nil {
+		return nil, err
+	}
+
+	nodeData, err := ln.buildArgs(nodeSemVer, configFile, nodeDir, &argsConfig)
 	if err != nil {
 		return nil, err
 	}
+	// the ports are reserved up to here, so the node process can bind them
+	ports.release(nodeData.apiPort)
+	ports.release(nodeData.p2pPort)
 
 	// Parse this node's ID
 	nodeID, err := utils.ToNodeID([]byte(nodeConfig.StakingKey), []byte(nodeConfig.StakingCert))
@@ -587,7 +781,7 @@ func (ln *localNetwork) addNode(nodeConfig node.Config) (node.Node, error) {
 	}
 
 	// Start the Lux node and pass it the flags defined above
-	nodeProcess, err := ln.nodeProcessCreator.NewNodeProcess(nodeConfig, nodeData.args...)
+	nodeProcess, err := ln.nodeProcessCreator.NewNodeProcess(nodeConfig, nodeData.env, nodeData.args...)
 	if err != nil {
 		return nil, fmt.Errorf(
 			"couldn't create new node process with binary %q and args %v: %w",
@@ -612,12 +806,17 @@ func (ln *localNetwork) addNode(nodeConfig node.Config) (node.Node, error) {
 		zap.Strings("args", nodeData.args),
 	)
 
+	apiHost := "localhost"
+	if isBoundToInterface(nodeData.httpHost) {
+		apiHost = nodeData.httpHost
+	}
+
 	// Create a wrapper for this node so we can reference it later
 	node := &localNode{
 		name:          nodeConfig.Name,
 		nodeID:        nodeID,
 		networkID:     ln.networkID,
-		client:        ln.newAPIClientF("localhost", nodeData.apiPort),
+		client:        ln.newAPIClientF(apiHost, nodeData.apiPort),
 		process:       nodeProcess,
 		apiPort:       nodeData.apiPort,
 		p2pPort:       nodeData.p2pPort,
@@ -626,11 +825,14 @@ func (ln *localNetwork) addNode(nodeConfig node.Config) (node.Node, error) {
 		dbDir:         nodeData.dbDir,
 		logsDir:       nodeData.logsDir,
 		config:        nodeConfig,
+		args:          nodeData.args,
 		pluginDir:     nodeData.pluginDir,
 		httpHost:      nodeData.httpHost,
-		attachedPeers: map[string]peer.Peer{},
+		attachedPeers: map[string]attachedPeer{},
 	}
+	ln.nodesLock.Lock()
 	ln.nodes[node.name] = node
+	ln.nodesLock.Unlock()
 	// If this node is a beacon, add its IP/ID to the beacon lists.
 	// Note that we do this *after* we set this node's bootstrap IPs/IDs
 	// so this node won't try to use itself as a beacon.
@@ -644,16 +846,75 @@ func (ln *localNetwork) addNode(nodeConfig node.Config) (node.Node, error) {
 }
 
 // See network.Network
+// [ln.lock] is not held while waiting, so other operations and queries
+// on the network are not blocked by a long health wait.
+// A node that exited because another process took one of its dynamically
+// assigned ports is restarted on fresh ports, up to [maxPortsRetries] times.
 func (ln *localNetwork) Healthy(ctx context.Context) error {
-	ln.lock.RLock()
-	defer ln.lock.RUnlock()
+	for retries := 0; ; retries++ {
+		err := ln.healthy(ctx)
+		var portsErr *nodePortsTakenError
+		if !errors.As(err, &portsErr) || retries == maxPortsRetries {
+			if err != nil {
+				return err
+			}
+			return ln.waitForReloadedBlockchains(ctx)
+		}
+		if err := ln.restartNodeOnFreshPorts(ctx, portsErr.nodeName); err != nil {
+			return err
+		}
+	}
+}
 
-	return ln.healthy(ctx)
+// Restarts [nodeName], that exited as its ports were taken, on new ports.
+func (ln *localNetwork) restartNodeOnFreshPorts(ctx context.Context, nodeName string) error {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	if ln.stopCalled() {
+		return network.ErrStopped
+	}
+	node, ok := ln.nodes[nodeName]
+	if !ok {
+		return fmt.Errorf("node %q not found", nodeName)
+	}
+	ln.log.Warn("node ports were taken by another process, restarting node on new ports",
+		zap.String("node", nodeName),
+		zap.Uint16("api-port", node.GetAPIPort()),
+		zap.Uint16("p2p-port", node.GetP2PPort()),
+	)
+	nodeConfig := getRestartConfig(node)
+	delete(nodeConfig.Flags, config.HTTPPortKey)
+	delete(nodeConfig.Flags, config.StakingPortKey)
+	// the process already exited, so its exit code is not of interest
+	_, _ = ln.stopNode(ctx, nodeName)
+	_, err := ln.addNode(nodeConfig)
+	return err
 }
 
+// Waits until all running nodes currently in the network are healthy.
+// Nodes added while waiting are not checked.
 func (ln *localNetwork) healthy(ctx context.Context) error {
-	ln.log.Info("checking local network healthiness", zap.Int("num-of-nodes", len(ln.nodes)))
+	nodes := ln.getRunningNodes()
+	ln.log.Info("checking local network healthiness", zap.Int("num-of-nodes", len(nodes)))
+	return ln.nodesHealthy(ctx, nodes)
+}
+
+// Returns the node [nodeName] of the network, or false if it was removed
+// or paused, so it is not waited on anymore. A restarted node is a new one.
+func (ln *localNetwork) getWaitedNode(nodeName string) (*localNode, bool) {
+	ln.nodesLock.RLock()
+	defer ln.nodesLock.RUnlock()
+
+	node, ok := ln.nodes[nodeName]
+	if !ok || node.paused {
+		return nil, false
+	}
+	return node, true
+}
 
+// Waits until all the given [nodes] are healthy.
+func (ln *localNetwork) nodesHealthy(ctx context.Context, nodes []*localNode) error {
 	// Return unhealthy if the network is stopped
 	if ln.stopCalled() {
 		return network.ErrStopped
@@ -661,24 +922,15 @@ func (ln *localNetwork) healthy(ctx context.Context) error {
 
 	// Derive a new context that's cancelled when Stop is called,
 	// so that calls to Healthy() below immediately return.
-	ctx, cancel := context.WithCancel(ctx)
+	ctx, cancel := ln.cancelOnStop(ctx)
 	defer cancel()
-	go func(ctx context.Context) {
-		// This goroutine runs until [ln.Stop] is called
-		// or this function returns.
-		select {
-		case <-ln.onStopCh:
-			cancel()
-		case <-ctx.Done():
-		}
-	}(ctx)
 
+	// bounds the health requests in flight, so big networks don't open
+	// a connection per node at each poll
+	healthChecks := make(chan struct{}, maxConcurrentHealthChecks)
+	var numHealthy atomic.Int32
 	errGr, ctx := errgroup.WithContext(ctx)
-	for _, node := range ln.nodes {
-		if node.paused {
-			// no health check for paused nodes
-			continue
-		}
+	for _, node := range nodes {
 		node := node
 		nodeName := node.GetName()
 		errGr.Go(func() error {
@@ -686,13 +938,41 @@ func (ln *localNetwork) healthy(ctx context.Context) error {
 			// Do this until ctx timeout or network closed.
 			for {
 				if node.Status() != status.Running {
-					// If we had stopped this node ourselves, it wouldn't be in [ln.nodes].
-					// Since it is, it means the node stopped unexpectedly.
+					// [ln.lock] may not be held, so the node may have been removed,
+					// paused or restarted while waiting
+					current, ok := ln.getWaitedNode(nodeName)
+					if !ok {
+						return nil
+					}
+					if current != node {
+						node = current
+						continue
+					}
+					if ln.stopCalled() {
+						return network.ErrStopped
+					}
+					if node.portsTaken() {
+						return &nodePortsTakenError{nodeName: nodeName}
+					}
+					// If we had stopped this node ourselves, it wouldn't be in [ln.nodes]
+					// or it would be paused. Since it isn't, it means the node stopped unexpectedly.
 					return fmt.Errorf("node %q stopped unexpectedly", nodeName)
 				}
+				select {
+				case healthChecks <- struct{}{}:
+				case <-ctx.Done():
+					return fmt.Errorf("node %q failed to become healthy within timeout, or network stopped", nodeName)
+				}
 				health, err := node.client.HealthAPI().Health(ctx, nil)
+				<-healthChecks
 				if err == nil && health.Healthy {
 					ln.log.Debug("node became healthy", zap.String("name", nodeName))
+					ln.reportProgress(
+						network.PhaseWaitHealthy,
+						int(numHealthy.Add(1)),
+						len(nodes),
+						fmt.Sprintf("node %s is healthy", nodeName),
+					)
 					return nil
 				}
 				select {
@@ -709,8 +989,8 @@ func (ln *localNetwork) healthy(ctx context.Context) error {
 
 // See network.Network
 func (ln *localNetwork) GetNode(nodeName string) (node.Node, error) {
-	ln.lock.RLock()
-	defer ln.lock.RUnlock()
+	ln.nodesLock.RLock()
+	defer ln.nodesLock.RUnlock()
 
 	if ln.stopCalled() {
 		return nil, network.ErrStopped
@@ -725,8 +1005,8 @@ func (ln *localNetwork) GetNode(nodeName string) (node.Node, error) {
 
 // See network.Network
 func (ln *localNetwork) GetNodeNames() ([]string, error) {
-	ln.lock.RLock()
-	defer ln.lock.RUnlock()
+	ln.nodesLock.RLock()
+	defer ln.nodesLock.RUnlock()
 
 	if ln.stopCalled() {
 		return nil, network.ErrStopped
@@ -735,10 +1015,19 @@ func (ln *localNetwork) GetNodeNames() ([]string, error) {
 	return maps.Keys(ln.nodes), nil
 }
 
+// See network.Network
+func (ln *localNetwork) GetGenesis() ([]byte, error) {
+	if ln.stopCalled() {
+		return nil, network.ErrStopped
+	}
+	// set when the network is loaded, and not changed after
+	return append([]byte{}, ln.genesis...), nil
+}
+
 // See network.Network
 func (ln *localNetwork) GetAllNodes() (map[string]node.Node, error) {
-	ln.lock.RLock()
-	defer ln.lock.RUnlock()
+	ln.nodesLock.RLock()
+	defer ln.nodesLock.RUnlock()
 
 	if ln.stopCalled() {
 		return nil, network.ErrStopped
@@ -752,7 +1041,14 @@ func (ln *localNetwork) GetAllNodes() (map[string]node.Node, error) {
 }
 
 func (ln *localNetwork) Stop(ctx context.Context) error {
+	_, err := ln.StopWithOptions(ctx, network.StopOptions{})
+	return err
+}
+
+// See network.Network
+func (ln *localNetwork) StopWithOptions(ctx context.Context, opts network.StopOptions) ([]network.NodeStopResult, error) {
 	err := network.ErrStopped
+	var results []network.NodeStopResult
 	ln.stopOnce.Do(
 		func() {
 			close(ln.onStopCh)
@@ -760,25 +1056,79 @@ func (ln *localNetwork) Stop(ctx context.Context) error {
 			ln.lock.Lock()
 			defer ln.lock.Unlock()
 
-			err = ln.stop(ctx)
+			results, err = ln.stopNodes(ctx, opts)
 		},
 	)
-	return err
+	return results, err
 }
 
 // Assumes [ln.lock] is held.
 func (ln *localNetwork) stop(ctx context.Context) error {
+	_, err := ln.stopNodes(ctx, network.StopOptions{})
+	return err
+}
+
+// Stops and removes all the nodes, one at a time, in the order set by [opts].
+// Each node is given [opts.NodeTimeout] to exit before being killed, and
+// a node failing to stop doesn't prevent the others from stopping.
+// Returns the outcome for each node, and the errors of all of them.
+// Assumes [ln.lock] is held.
+func (ln *localNetwork) stopNodes(ctx context.Context, opts network.StopOptions) ([]network.NodeStopResult, error) {
+	nodeTimeout := opts.NodeTimeout
+	if nodeTimeout == 0 {
+		nodeTimeout = stopTimeout
+	}
+	nodeNames := maps.Keys(ln.nodes)
+	sort.Slice(nodeNames, func(i, j int) bool {
+		if opts.NonBeaconsFirst {
+			iBeacon := ln.nodes[nodeNames[i]].GetConfig().IsBeacon
+			jBeacon := ln.nodes[nodeNames[j]].GetConfig().IsBeacon
+			if iBeacon != jBeacon {
+				return jBeacon
+			}
+		}
+		return nodeNames[i] < nodeNames[j]
+	})
+
+	results := make([]network.NodeStopResult, 0, len(nodeNames))
 	errs := wrappers.Errs{}
-	for nodeName := range ln.nodes {
-		stopCtx, stopCtxCancel := context.WithTimeout(ctx, stopTimeout)
-		if err := ln.removeNode(stopCtx, nodeName); err != nil {
+	for _, nodeName := range nodeNames {
+		start := time.Now()
+		stopCtx, stopCtxCancel := context.WithTimeout(ctx, nodeTimeout)
+		exitCode, err := ln.stopNode(stopCtx, nodeName)
+		result := network.NodeStopResult{
+			NodeName: nodeName,
+			ExitCode: exitCode,
+			Killed:   stopCtx.Err() != nil,
+			Duration: time.Since(start),
+			Err:      err,
+		}
+		stopCtxCancel()
+		if err != nil {
 			ln.log.Error("error stopping node", zap.String("name", nodeName), zap.Error(err))
 			errs.Add(err)
 		}
-		stopCtxCancel()
+		if result.Killed {
+			ln.log.Warn("node didn't exit within timeout and was killed", zap.String("name", nodeName), zap.Duration("timeout", nodeTimeout))
+		}
+		results = append(results, result)
 	}
 	ln.log.Info("done stopping network")
-	return errs.Err
+	return results, errs.Err
+}
+
+// Returns the nodes of the network that are not paused.
+func (ln *localNetwork) getRunningNodes() []*localNode {
+	ln.nodesLock.RLock()
+	defer ln.nodesLock.RUnlock()
+
+	nodes := make([]*localNode, 0, len(ln.nodes))
+	for _, node := range ln.nodes {
+		if !node.paused {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
 }
 
 // Sends a SIGTERM to the given node and removes it from this network.
@@ -794,27 +1144,40 @@ func (ln *localNetwork) RemoveNode(ctx context.Context, nodeName string) error {
 
 // Assumes [ln.lock] is held.
 func (ln *localNetwork) removeNode(ctx context.Context, nodeName string) error {
+	_, err := ln.stopNode(ctx, nodeName)
+	return err
+}
+
+// Stops [nodeName] if not paused, and removes it from the network.
+// Returns the exit code of the node process.
+// Assumes [ln.lock] is held.
+func (ln *localNetwork) stopNode(ctx context.Context, nodeName string) (int, error) {
 	ln.log.Debug("removing node", zap.String("name", nodeName))
 	node, ok := ln.nodes[nodeName]
 	if !ok {
-		return fmt.Errorf("node %q not found", nodeName)
+		return 0, fmt.Errorf("node %q not found", nodeName)
 	}
 
 	paused := node.paused
 
 	// If the node wasn't a beacon, we don't care
 	_ = ln.bootstraps.RemoveByID(node.nodeID)
+	ln.nodesLock.Lock()
 	delete(ln.nodes, nodeName)
+	ln.nodesLock.Unlock()
+	// a restarted node gets the network level changes from its config
+	delete(ln.pausedNodesQueuedWork, nodeName)
+	node.closePeerSubscriptions()
 
 	if !paused {
 		// cchain eth api uses a websocket connection and must be closed before stopping the node,
 		// to avoid errors logs at client
 		node.client.CChainEthAPI().Close()
 		if exitCode := node.process.Stop(ctx); exitCode != 0 {
-			return fmt.Errorf("node %q exited with exit code: %d", nodeName, exitCode)
+			return exitCode, fmt.Errorf("node %q exited with exit code: %d", nodeName, exitCode)
 		}
 	}
-	return nil
+	return 0, nil
 }
 
 // Sends a SIGTERM to the given node and keeps it in the network with paused state
@@ -837,14 +1200,50 @@ func (ln *localNetwork) pauseNode(ctx context.Context, nodeName string) error {
 	if node.paused {
 		return fmt.Errorf("node has been paused already")
 	}
+	node.closePeerSubscriptions()
 	// cchain eth api uses a websocket connection and must be closed before stopping the node,
 	// to avoid errors logs at client
 	node.client.CChainEthAPI().Close()
+	// marked paused before stopping, so health waits in progress don't
+	// take the stop as unexpected
+	ln.nodesLock.Lock()
+	node.paused = true
+	ln.nodesLock.Unlock()
 	if exitCode := node.process.Stop(ctx); exitCode != 0 {
 		return fmt.Errorf("node %q exited with exit code: %d", nodeName, exitCode)
 	}
 	syscall.Sync()
-	node.paused = true
+	return nil
+}
+
+// Work skipped on a paused node, applied when it is resumed.
+type queuedWork struct {
+	description string
+	// updates the config the node is resumed with. nil if the node does
+	// the work by itself when started, e.g. reloading its VM plugins
+	apply func(nodeConfig *node.Config) error
+}
+
+// Queues [work] skipped on the paused [nodeName], to apply it when the node
+// is resumed.
+func (ln *localNetwork) queueForPausedNode(nodeName string, work queuedWork) {
+	ln.log.Info("node is paused, work queued until it is resumed", zap.String("node", nodeName), zap.String("work", work.description))
+	if ln.pausedNodesQueuedWork == nil {
+		ln.pausedNodesQueuedWork = map[string][]queuedWork{}
+	}
+	ln.pausedNodesQueuedWork[nodeName] = append(ln.pausedNodesQueuedWork[nodeName], work)
+}
+
+// Applies to [nodeConfig] the work queued while [nodeName] was paused.
+func (ln *localNetwork) applyQueuedWork(nodeName string, nodeConfig *node.Config) error {
+	for _, work := range ln.pausedNodesQueuedWork[nodeName] {
+		if work.apply != nil {
+			if err := work.apply(nodeConfig); err != nil {
+				return fmt.Errorf("couldn't apply work %q queued for node %q: %w", work.description, nodeName, err)
+			}
+		}
+		ln.log.Info("applied work queued while node was paused", zap.String("node", nodeName), zap.String("work", work.description))
+	}
 	return nil
 }
 
@@ -880,9 +1279,13 @@ func (ln *localNetwork) resumeNode(
 	nodeConfig.Flags[config.LogsDirKey] = node.GetLogsDir()
 	nodeConfig.Flags[config.HTTPPortKey] = int(node.GetAPIPort())
 	nodeConfig.Flags[config.StakingPortKey] = int(node.GetP2PPort())
+	if err := ln.applyQueuedWork(nodeName, &nodeConfig); err != nil {
+		return err
+	}
 	if _, err := ln.addNode(nodeConfig); err != nil {
 		return err
 	}
+	delete(ln.pausedNodesQueuedWork, nodeName)
 	return nil
 }
 
@@ -897,6 +1300,7 @@ func (ln *localNetwork) RestartNode(
 	chainConfigs map[string]string,
 	upgradeConfigs map[string]string,
 	subnetConfigs map[string]string,
+	wipeDB bool,
 ) error {
 	ln.lock.Lock()
 	defer ln.lock.Unlock()
@@ -910,6 +1314,7 @@ func (ln *localNetwork) RestartNode(
 		chainConfigs,
 		upgradeConfigs,
 		subnetConfigs,
+		wipeDB,
 	)
 }
 
@@ -922,13 +1327,14 @@ func (ln *localNetwork) restartNode(
 	chainConfigs map[string]string,
 	upgradeConfigs map[string]string,
 	subnetConfigs map[string]string,
+	wipeDB bool,
 ) error {
 	node, ok := ln.nodes[nodeName]
 	if !ok {
 		return fmt.Errorf("node %q not found", nodeName)
 	}
 
-	nodeConfig := node.GetConfig()
+	nodeConfig := getRestartConfig(node)
 
 	if binaryPath != "" {
 		nodeConfig.BinaryPath = binaryPath
@@ -941,12 +1347,6 @@ func (ln *localNetwork) restartNode(
 		nodeConfig.Flags[config.TrackSubnetsKey] = trackSubnets
 	}
 
-	// keep same ports, dbdir in node flags
-	nodeConfig.Flags[config.DataDirKey] = node.GetDataDir()
-	nodeConfig.Flags[config.DBPathKey] = node.GetDbDir()
-	nodeConfig.Flags[config.LogsDirKey] = node.GetLogsDir()
-	nodeConfig.Flags[config.HTTPPortKey] = int(node.GetAPIPort())
-	nodeConfig.Flags[config.StakingPortKey] = int(node.GetP2PPort())
 	// apply chain configs
 	for k, v := range chainConfigs {
 		nodeConfig.ChainConfigFiles[k] = v
@@ -967,6 +1367,14 @@ func (ln *localNetwork) restartNode(
 		syscall.Sync()
 	}
 
+	// staking keys are given on the node config, so they are kept
+	if wipeDB {
+		ln.log.Info("removing node db", zap.String("node", nodeName), zap.String("db-dir", node.GetDbDir()))
+		if err := os.RemoveAll(node.GetDbDir()); err != nil {
+			return fmt.Errorf("couldn't remove db dir of node %q: %w", nodeName, err)
+		}
+	}
+
 	if _, err := ln.addNode(nodeConfig); err != nil {
 		return err
 	}
@@ -974,6 +1382,34 @@ func (ln *localNetwork) restartNode(
 	return nil
 }
 
+// Returns the config to restart [n] with, keeping the same dirs and ports.
+func getRestartConfig(n *localNode) node.Config {
+	nodeConfig := n.GetConfig()
+	nodeConfig.Flags[config.DataDirKey] = n.GetDataDir()
+	nodeConfig.Flags[config.DBPathKey] = n.GetDbDir()
+	nodeConfig.Flags[config.LogsDirKey] = n.GetLogsDir()
+	nodeConfig.Flags[config.HTTPPortKey] = int(n.GetAPIPort())
+	nodeConfig.Flags[config.StakingPortKey] = int(n.GetP2PPort())
+	return nodeConfig
+}
+
+// Derives a context from [ctx] that's cancelled when Stop is called, so
+// in-flight operations holding [ln.lock] return instead of blocking Stop.
+// The returned cancel func must be called once the operation is done.
+func (ln *localNetwork) cancelOnStop(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	go func(ctx context.Context) {
+		// This goroutine runs until [ln.Stop] is called
+		// or the operation is done.
+		select {
+		case <-ln.onStopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}(ctx)
+	return ctx, cancel
+}
+
 // Returns whether Stop has been called.
 func (ln *localNetwork) stopCalled() bool {
 	select {
@@ -1012,8 +1448,31 @@ func (ln *localNetwork) setNodeName(nodeConfig *node.Config) error {
 	return nil
 }
 
+// Returns a new staking cert and key for [nodeName], derived from the
+// network seed if given.
+func (ln *localNetwork) newStakingCertAndKey(nodeName string) ([]byte, []byte, error) {
+	if ln.seed != 0 {
+		return utils.NewSeededCertAndKeyBytes(ln.seed, nodeName)
+	}
+	return staking.NewCertAndKeyBytes()
+}
+
+// Returns a new BLS signing key for [nodeName], derived from the network
+// seed if given.
+func (ln *localNetwork) newSigningKey(nodeName string) ([]byte, error) {
+	if ln.seed != 0 {
+		return utils.NewSeededSigningKey(ln.seed, nodeName)
+	}
+	key, err := bls.NewSecretKey()
+	if err != nil {
+		return nil, err
+	}
+	return bls.SecretKeyToBytes(key), nil
+}
+
 type buildArgsReturn struct {
 	args      []string
+	env       []string
 	apiPort   uint16
 	p2pPort   uint16
 	dataDir   string
@@ -1023,6 +1482,16 @@ type buildArgsReturn struct {
 	httpHost  string
 }
 
+// Returns [nodeDir] if given on the node config, or else the expansion of
+// [template] of the network dir layout. Returns the empty string if neither
+// is given.
+func (ln *localNetwork) getLayoutDir(nodeDir string, template string, nodeName string) string {
+	if nodeDir != "" {
+		return nodeDir
+	}
+	return network.ExpandDirTemplate(template, ln.rootDir, nodeName)
+}
+
 // buildArgs returns the:
 // 1) Args for luxd execution
 // 2) API port
@@ -1055,27 +1524,44 @@ func (ln *localNetwork) buildArgs(
 	}
 
 	// Tell the node to put the database in [dataDir/db] unless given in config file
-	dbDir, err := getConfigEntry(nodeConfig.Flags, configFile, config.DBPathKey, filepath.Join(dataDir, defaultDBSubdir))
+	// or in the dir layout
+	defaultDBDir := ln.getLayoutDir(nodeConfig.DBDir, ln.dirLayout.DBDir, nodeConfig.Name)
+	if defaultDBDir == "" {
+		defaultDBDir = filepath.Join(dataDir, defaultDBSubdir)
+	}
+	dbDir, err := getConfigEntry(nodeConfig.Flags, configFile, config.DBPathKey, defaultDBDir)
 	if err != nil {
 		return buildArgsReturn{}, err
 	}
 
-	// Tell the node to put the log directory in [dataDir/logs] unless given in config file
-	logsDir, err := getConfigEntry(nodeConfig.Flags, configFile, config.LogsDirKey, filepath.Join(dataDir, defaultLogsSubdir))
+	// Tell the node to put the log directory in [dataDir/logs], or in its logs shard
+	// if sharding is enabled, unless given in config file or in the dir layout
+	defaultLogsDir := ln.getLayoutDir(nodeConfig.LogsDir, ln.dirLayout.LogsDir, nodeConfig.Name)
+	shardedLogs := defaultLogsDir == "" && ln.logsShardSize > 0
+	switch {
+	case shardedLogs:
+		defaultLogsDir = ln.getShardedLogsDir(nodeConfig.Name)
+	case defaultLogsDir == "":
+		defaultLogsDir = filepath.Join(dataDir, defaultLogsSubdir)
+	}
+	logsDir, err := getConfigEntry(nodeConfig.Flags, configFile, config.LogsDirKey, defaultLogsDir)
 	if err != nil {
 		return buildArgsReturn{}, err
 	}
+	if shardedLogs && logsDir == defaultLogsDir {
+		ln.numShardedLogsDirs++
+	}
 
 	// Use random free API port unless given in config file
-	apiPort, err := getPort(nodeConfig.Flags, configFile, config.HTTPPortKey, ln.reassignPortsIfUsed)
+	apiPort, err := getPort(nodeConfig.Flags, configFile, config.HTTPPortKey, ln.reassignPortsIfUsed, ln.portRange, ln.rand)
 	if err != nil {
 		return buildArgsReturn{}, err
 	}
 
 	// Use a random free P2P (staking) port unless given in config file
-	// Use random free API port unless given in config file
-	p2pPort, err := getPort(nodeConfig.Flags, configFile, config.StakingPortKey, ln.reassignPortsIfUsed)
+	p2pPort, err := getPort(nodeConfig.Flags, configFile, config.StakingPortKey, ln.reassignPortsIfUsed, ln.portRange, ln.rand)
 	if err != nil {
+		ports.release(apiPort)
 		return buildArgsReturn{}, err
 	}
 
@@ -1093,13 +1579,20 @@ func (ln *localNetwork) buildArgs(
 
 	// Write staking key/cert etc. to disk so the new node can use them,
 	// and get flag that point the node to those files
-	fileFlags, err := writeFiles(ln.networkID, ln.genesis, dataDir, nodeConfig)
+	fileFlags, err := writeFiles(ln.networkID, ln.genesis, dataDir, nodeConfig, ln.stakingKeysStorage)
 	if err != nil {
+		ports.release(apiPort)
+		ports.release(p2pPort)
 		return buildArgsReturn{}, err
 	}
 	for k := range fileFlags {
 		flags[k] = fileFlags[k]
 	}
+	// the staking keys not written as files are given on the node environment
+	var env []string
+	if ln.stakingKeysStorage != "" && ln.stakingKeysStorage != network.StakingKeysStorageFile {
+		env = getStakingKeysEnv(nodeConfig)
+	}
 
 	// avoid given these again, as apiPort/p2pPort can be dynamic even if given in nodeConfig
 	portFlags := set.Set[string]{
@@ -1131,6 +1624,7 @@ func (ln *localNetwork) buildArgs(
 
 	return buildArgsReturn{
 		args:      args,
+		env:       env,
 		apiPort:   apiPort,
 		p2pPort:   p2pPort,
 		dataDir:   dataDir,
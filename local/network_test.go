@@ -3,6 +3,8 @@ package local
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -10,6 +12,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -21,15 +24,19 @@ import (
 	"github.com/luxdefi/netrunner/network/node"
 	"github.com/luxdefi/netrunner/network/node/status"
 	"github.com/luxdefi/netrunner/utils"
+	"github.com/luxdefi/netrunner/utils/constants"
 	"github.com/luxdefi/node/api/health"
 	"github.com/luxdefi/node/config"
+	"github.com/luxdefi/node/genesis"
 	"github.com/luxdefi/node/ids"
 	"github.com/luxdefi/node/message"
 	"github.com/luxdefi/node/snow/networking/router"
 	"github.com/luxdefi/node/utils/logging"
 	"github.com/luxdefi/node/utils/rpc"
+	"github.com/luxdefi/node/utils/set"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
 )
 
 const (
@@ -49,7 +56,7 @@ var (
 
 type localTestSuccessfulNodeProcessCreator struct{}
 
-func (*localTestSuccessfulNodeProcessCreator) NewNodeProcess(config node.Config, flags ...string) (NodeProcess, error) {
+func (*localTestSuccessfulNodeProcessCreator) NewNodeProcess(config node.Config, _ []string, flags ...string) (NodeProcess, error) {
 	return newMockProcessSuccessful(config, flags...)
 }
 
@@ -59,7 +66,7 @@ func (*localTestSuccessfulNodeProcessCreator) GetNodeVersion(_ node.Config) (str
 
 type localTestFailedStartProcessCreator struct{}
 
-func (*localTestFailedStartProcessCreator) NewNodeProcess(node.Config, ...string) (NodeProcess, error) {
+func (*localTestFailedStartProcessCreator) NewNodeProcess(node.Config, []string, ...string) (NodeProcess, error) {
 	return nil, errors.New("error on purpose for test")
 }
 
@@ -69,7 +76,7 @@ func (*localTestFailedStartProcessCreator) GetNodeVersion(_ node.Config) (string
 
 type localTestProcessUndefNodeProcessCreator struct{}
 
-func (*localTestProcessUndefNodeProcessCreator) NewNodeProcess(config node.Config, flags ...string) (NodeProcess, error) {
+func (*localTestProcessUndefNodeProcessCreator) NewNodeProcess(config node.Config, _ []string, flags ...string) (NodeProcess, error) {
 	return newMockProcessUndef(config, flags...)
 }
 
@@ -82,7 +89,7 @@ type localTestFlagCheckProcessCreator struct {
 	require       *require.Assertions
 }
 
-func (lt *localTestFlagCheckProcessCreator) NewNodeProcess(config node.Config, flags ...string) (NodeProcess, error) {
+func (lt *localTestFlagCheckProcessCreator) NewNodeProcess(config node.Config, _ []string, flags ...string) (NodeProcess, error) {
 	lt.require.EqualValues(lt.expectedFlags, config.Flags)
 	return newMockProcessSuccessful(config, flags...)
 }
@@ -130,6 +137,7 @@ func newMockProcessSuccessful(node.Config, ...string) (NodeProcess, error) {
 	process.On("Wait").Return(nil)
 	process.On("Stop", mock.Anything).Return(0)
 	process.On("Status").Return(status.Running)
+	process.On("GetPID").Return(0)
 	return process, nil
 }
 
@@ -176,7 +184,7 @@ func newLocalTestOneNodeCreator(require *require.Assertions, networkConfig netwo
 
 // Assert that the node's config is being passed correctly
 // to the function that starts the node process.
-func (lt *localTestOneNodeCreator) NewNodeProcess(config node.Config, flags ...string) (NodeProcess, error) {
+func (lt *localTestOneNodeCreator) NewNodeProcess(config node.Config, _ []string, flags ...string) (NodeProcess, error) {
 	lt.require.True(config.IsBeacon)
 	expectedConfig := lt.networkConfig.NodeConfigs[0]
 	lt.require.EqualValues(lt.networkConfig.ChainConfigFiles, config.ChainConfigFiles)
@@ -192,7 +200,7 @@ func (lt *localTestOneNodeCreator) NewNodeProcess(config node.Config, flags ...s
 		lt.require.True(ok)
 		lt.require.EqualValues(v, gotV)
 	}
-	return lt.successCreator.NewNodeProcess(config, flags...)
+	return lt.successCreator.NewNodeProcess(config, nil, flags...)
 }
 
 func (*localTestOneNodeCreator) GetNodeVersion(_ node.Config) (string, error) {
@@ -569,6 +577,59 @@ func TestGenerateDefaultNetwork(t *testing.T) {
 	}
 }
 
+func TestGenerateDefaultNetworkNNodes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	networkConfig, err := NewDefaultConfigNNodes("pepito", 7)
+	require.NoError(err)
+	require.Len(networkConfig.NodeConfigs, 7)
+	// the extra nodes don't share the keys of the default ones
+	for _, nodeConfig := range networkConfig.NodeConfigs[5:] {
+		require.Empty(nodeConfig.StakingKey)
+		require.Empty(nodeConfig.StakingCert)
+		require.Empty(nodeConfig.StakingSigningKey)
+	}
+	require.NoError(networkConfig.Validate())
+
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, "", "", false)
+	require.NoError(err)
+	require.NoError(net.loadConfig(context.Background(), networkConfig))
+	nodes, err := net.GetAllNodes()
+	require.NoError(err)
+	require.Len(nodes, 7)
+	nodeIDs := set.Set[ids.NodeID]{}
+	for _, node := range nodes {
+		nodeIDs.Add(node.GetNodeID())
+	}
+	require.Equal(7, nodeIDs.Len())
+}
+
+func TestSeededGenesisStartTime(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	networkConfig := NewDefaultConfig("pepito")
+	networkConfig.Seed = 42
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, "", "", false)
+	require.NoError(err)
+	require.NoError(net.loadConfig(context.Background(), networkConfig))
+
+	// the genesis doesn't depend on the current time within a window
+	var genesisFields struct {
+		StartTime            int64 `json:"startTime"`
+		InitialStakeDuration int64 `json:"initialStakeDuration"`
+	}
+	require.NoError(json.Unmarshal(net.genesis, &genesisFields))
+	require.Equal(network.GetSeededStartTime(time.Now()).Unix(), genesisFields.StartTime)
+	require.Equal(int64(network.SeededInitialStakeDuration/time.Second), genesisFields.InitialStakeDuration)
+	require.Greater(genesisFields.StartTime+genesisFields.InitialStakeDuration, time.Now().Unix())
+
+	// the nodes accept the genesis
+	genesisPath := filepath.Join(t.TempDir(), genesisFileName)
+	require.NoError(os.WriteFile(genesisPath, net.genesis, 0o600))
+	_, _, err = genesis.FromFile(net.networkID, genesisPath, &genesis.LocalParams.StakingConfig)
+	require.NoError(err)
+}
+
 // TODO add byzantine node to conf
 // TestNetworkFromConfig creates/waits/checks/stops a network from config file
 // the check verify that all the nodes can be accessed
@@ -630,6 +691,193 @@ func TestNetworkNodeOps(t *testing.T) {
 	}
 }
 
+// TestAddNodeNetworkLevelConfigs checks that chain configs, upgrade files,
+// subnet configs and aliases kept at network level are applied to new nodes
+func TestAddNodeNetworkLevelConfigs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	emptyNetworkConfig, err := emptyNetworkConfig()
+	require.NoError(err)
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, "", "", false)
+	require.NoError(err)
+	err = net.loadConfig(context.Background(), emptyNetworkConfig)
+	require.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	net.chainConfigFiles[chainID.String()] = "chain config"
+	net.upgradeConfigFiles[chainID.String()] = "upgrade"
+	net.subnetConfigFiles["subnet"] = "subnet config"
+	net.chainAliases[chainID.String()] = []string{"alias"}
+
+	networkConfig := testNetworkConfig(t)
+	nodeConfig := networkConfig.NodeConfigs[0]
+	nodeConfig.Flags = maps.Clone(nodeConfig.Flags)
+	userAliases := map[string][]string{chainID.String(): {"user-alias"}}
+	require.NoError(setAliasesFlag(nodeConfig.Flags, config.ChainAliasesContentKey, userAliases))
+	userAliasesFlag := nodeConfig.Flags[config.ChainAliasesContentKey]
+	_, err = net.AddNode(nodeConfig)
+	require.NoError(err)
+
+	node, err := net.GetNode(nodeConfig.Name)
+	require.NoError(err)
+	gotConfig := node.GetConfig()
+	require.Equal("chain config", gotConfig.ChainConfigFiles[chainID.String()])
+	require.Equal("upgrade", gotConfig.UpgradeConfigFiles[chainID.String()])
+	require.Equal("subnet config", gotConfig.SubnetConfigFiles["subnet"])
+	// the node config keeps the user given aliases, while the args merge
+	// the network level ones into them
+	require.Equal(userAliasesFlag, gotConfig.Flags[config.ChainAliasesContentKey])
+	require.NotContains(gotConfig.Flags, config.VMAliasesContentKey)
+	aliasesBytes, err := json.Marshal(map[string][]string{chainID.String(): {"user-alias", "alias"}})
+	require.NoError(err)
+	localNode := node.(*localNode)
+	require.Contains(localNode.args, fmt.Sprintf("--%s=%s", config.ChainAliasesContentKey, base64.StdEncoding.EncodeToString(aliasesBytes)))
+	for _, arg := range localNode.args {
+		require.False(strings.HasPrefix(arg, "--"+config.VMAliasesContentKey+"="))
+	}
+}
+
+// TestSetAliasesFlag checks that the aliases kept at network level are
+// merged into the ones given on the node flags
+func TestSetAliasesFlag(t *testing.T) {
+	require := require.New(t)
+
+	chainID := ids.GenerateTestID().String()
+	flags := map[string]interface{}{}
+	require.NoError(setAliasesFlag(flags, config.ChainAliasesContentKey, nil))
+	require.NotContains(flags, config.ChainAliasesContentKey)
+
+	require.NoError(setAliasesFlag(flags, config.ChainAliasesContentKey, map[string][]string{chainID: {"user-alias"}}))
+	require.NoError(setAliasesFlag(flags, config.ChainAliasesContentKey, map[string][]string{chainID: {"user-alias", "alias"}}))
+	aliases, err := getAliasesFlag(flags, config.ChainAliasesContentKey)
+	require.NoError(err)
+	require.Equal(map[string][]string{chainID: {"user-alias", "alias"}}, aliases)
+
+	flags[config.ChainAliasesContentKey] = "not base64"
+	require.Error(setAliasesFlag(flags, config.ChainAliasesContentKey, map[string][]string{chainID: {"alias"}}))
+}
+
+// TestPersistNetworkState checks that the network state written to the
+// root data dir is read back
+func TestPersistNetworkState(t *testing.T) {
+	require := require.New(t)
+
+	rootDir := t.TempDir()
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, rootDir, "", false)
+	require.NoError(err)
+	subnetID := ids.GenerateTestID()
+	net.subnetID2ElasticSubnetID[subnetID] = ids.GenerateTestID()
+	net.chainAliases[ids.GenerateTestID().String()] = []string{"alias"}
+	net.blockchains = []blockchainInfo{{
+		chainName:    "subnetevm",
+		vmID:         ids.GenerateTestID(),
+		subnetID:     subnetID,
+		blockchainID: ids.GenerateTestID(),
+	}}
+	require.NoError(net.persistNetworkState())
+
+	networkState, err := ReadNetworkState(rootDir)
+	require.NoError(err)
+	require.Equal(net.getNetworkState(), networkState)
+
+	net2, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, t.TempDir(), "", false)
+	require.NoError(err)
+	require.NoError(net2.setNetworkState(networkState))
+	require.Equal(net.subnetID2ElasticSubnetID, net2.subnetID2ElasticSubnetID)
+	require.Equal(net.chainAliases, net2.chainAliases)
+	require.Equal(net.blockchains, net2.blockchains)
+
+	_, err = ReadNetworkState(t.TempDir())
+	require.ErrorIs(err, os.ErrNotExist)
+}
+
+// TestRestartLoadsNetworkState checks that a network created again on the
+// root data dir of a stopped one loads the state persisted by it, applies
+// its aliases to the nodes, and waits for its blockchains to run again
+func TestRestartLoadsNetworkState(t *testing.T) {
+	require := require.New(t)
+
+	rootDir := t.TempDir()
+	networkConfig := testNetworkConfig(t)
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, rootDir, "", false)
+	require.NoError(err)
+	require.NoError(net.loadPersistedNetworkState())
+	require.Empty(net.blockchains)
+	require.NoError(net.loadConfig(context.Background(), networkConfig))
+
+	subnetID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	net.lock.Lock()
+	net.subnetID2ElasticSubnetID[subnetID] = ids.GenerateTestID()
+	net.blockchains = []blockchainInfo{{
+		chainName:    "subnetevm",
+		vmID:         ids.GenerateTestID(),
+		subnetID:     subnetID,
+		blockchainID: chainID,
+	}}
+	net.chainAliases[chainID.String()] = []string{"alias"}
+	require.NoError(net.persistNetworkState())
+	net.lock.Unlock()
+	require.NoError(net.Stop(context.Background()))
+
+	trackingNodeName := networkConfig.NodeConfigs[0].Name
+	networkConfig.NodeConfigs[0].Flags[config.TrackSubnetsKey] = subnetID.String()
+	net2, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, rootDir, "", false)
+	require.NoError(err)
+	require.NoError(net2.loadPersistedNetworkState())
+	require.NoError(net2.loadConfig(context.Background(), networkConfig))
+	require.Equal(net.subnetID2ElasticSubnetID, net2.subnetID2ElasticSubnetID)
+	require.Equal(net.blockchains, net2.blockchains)
+	require.Equal(net.blockchains, net2.reloadedBlockchains)
+	require.Equal(map[string][]string{chainID.String(): {"alias"}}, net2.chainAliases)
+
+	aliasesBytes, err := json.Marshal(net2.chainAliases)
+	require.NoError(err)
+	aliasesArg := fmt.Sprintf("--%s=%s", config.ChainAliasesContentKey, base64.StdEncoding.EncodeToString(aliasesBytes))
+	for _, node := range net2.nodes {
+		require.Contains(node.args, aliasesArg)
+	}
+
+	// the network is healthy once the node tracking the subnet runs the
+	// reloaded blockchain again
+	ctx, cancel := context.WithTimeout(context.Background(), 3*blockchainLogPullFrequency)
+	require.ErrorIs(net2.Healthy(ctx), context.DeadlineExceeded)
+	cancel()
+	logsDir := net2.nodes[trackingNodeName].GetLogsDir()
+	require.NoError(os.MkdirAll(logsDir, 0o750))
+	require.NoError(os.WriteFile(filepath.Join(logsDir, chainID.String()+".log"), nil, 0o644))
+	require.NoError(awaitNetworkHealthy(net2, defaultHealthyTimeout))
+	require.Empty(net2.reloadedBlockchains)
+	require.NoError(net2.Stop(context.Background()))
+}
+
+// TestAliasesFromNodeConfigs checks that the aliases set on node flags
+// are recovered and merged
+func TestAliasesFromNodeConfigs(t *testing.T) {
+	require := require.New(t)
+
+	chainID := ids.GenerateTestID().String()
+	vmID := ids.GenerateTestID().String()
+	nodeConfigs := []node.Config{
+		{Name: "node1", Flags: map[string]interface{}{}},
+		{Name: "node2", Flags: map[string]interface{}{}},
+		{Name: "node3", Flags: map[string]interface{}{}},
+	}
+	require.NoError(setAliasesFlag(nodeConfigs[0].Flags, config.ChainAliasesContentKey, map[string][]string{chainID: {"alias1"}}))
+	require.NoError(setAliasesFlag(nodeConfigs[1].Flags, config.ChainAliasesContentKey, map[string][]string{chainID: {"alias1", "alias2"}}))
+	require.NoError(setAliasesFlag(nodeConfigs[1].Flags, config.VMAliasesContentKey, map[string][]string{vmID: {"vm"}}))
+
+	chainAliases, vmAliases, err := aliasesFromNodeConfigs(nodeConfigs)
+	require.NoError(err)
+	require.Equal(map[string][]string{chainID: {"alias1", "alias2"}}, chainAliases)
+	require.Equal(map[string][]string{vmID: {"vm"}}, vmAliases)
+
+	nodeConfigs[2].Flags[config.ChainAliasesContentKey] = "not base64"
+	_, _, err = aliasesFromNodeConfigs(nodeConfigs)
+	require.Error(err)
+}
+
 // TestNodeNotFound checks all operations fail for an unknown node,
 // being it either not created, or created and removed thereafter
 func TestNodeNotFound(t *testing.T) {
@@ -875,7 +1123,7 @@ func TestChildCmdRedirection(t *testing.T) {
 	// Sleep for a second after echoing so that we have a chance to read from the stdout pipe
 	// before it closes when the process exits and Wait() returns.
 	// See https://pkg.go.dev/os/exec#Cmd.StdoutPipe
-	proc, err := npc.NewNodeProcess(testConfig, "-c", fmt.Sprintf("echo %s && sleep 1", testOutput))
+	proc, err := npc.NewNodeProcess(testConfig, nil, "-c", fmt.Sprintf("echo %s && sleep 1", testOutput))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1011,6 +1259,64 @@ func TestAddNetworkFlags(t *testing.T) {
 	}
 }
 
+func TestApplyNodeMode(t *testing.T) {
+	t.Parallel()
+	type test struct {
+		name              string
+		mode              string
+		beforeFlags       map[string]interface{}
+		beforeCChain      string
+		afterFlags        map[string]interface{}
+		afterCChainConfig map[string]interface{}
+		expectedErr       error
+	}
+	tests := []test{
+		{
+			name:              "archival",
+			mode:              node.ModeArchival,
+			beforeFlags:       map[string]interface{}{},
+			beforeCChain:      `{"pruning-enabled":true,"log-level":"debug"}`,
+			afterFlags:        map[string]interface{}{config.IndexEnabledKey: true},
+			afterCChainConfig: map[string]interface{}{"pruning-enabled": false, "state-sync-enabled": false, "log-level": "debug"},
+		},
+		{
+			name:              "pruned; explicit node flag kept",
+			mode:              node.ModePruned,
+			beforeFlags:       map[string]interface{}{config.IndexEnabledKey: true},
+			afterFlags:        map[string]interface{}{config.IndexEnabledKey: true},
+			afterCChainConfig: map[string]interface{}{"pruning-enabled": true},
+		},
+		{
+			name:        "invalid mode",
+			mode:        "lite",
+			beforeFlags: map[string]interface{}{},
+			expectedErr: node.ErrInvalidMode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			nodeConfig := node.Config{
+				Mode:             tt.mode,
+				Flags:            tt.beforeFlags,
+				ChainConfigFiles: map[string]string{},
+			}
+			if tt.beforeCChain != "" {
+				nodeConfig.ChainConfigFiles["C"] = tt.beforeCChain
+			}
+			err := applyNodeMode(&nodeConfig)
+			require.ErrorIs(err, tt.expectedErr)
+			if tt.expectedErr != nil {
+				return
+			}
+			require.Equal(tt.afterFlags, nodeConfig.Flags)
+			var cChainConfig map[string]interface{}
+			require.NoError(json.Unmarshal([]byte(nodeConfig.ChainConfigFiles["C"]), &cChainConfig))
+			require.Equal(tt.afterCChainConfig, cChainConfig)
+		})
+	}
+}
+
 func TestSetNodeName(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -1231,7 +1537,7 @@ func TestWriteFiles(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			require := require.New(t)
-			flags, err := writeFiles(0, tt.genesis, tmpDir, &tt.nodeConfig)
+			flags, err := writeFiles(0, tt.genesis, tmpDir, &tt.nodeConfig, "")
 			if tt.shouldErr {
 				require.Error(err)
 				return
@@ -1249,6 +1555,9 @@ func TestWriteFiles(t *testing.T) {
 			gotStakingCert, err := os.ReadFile(stakingCertPath)
 			require.NoError(err)
 			require.Equal([]byte(tt.nodeConfig.StakingCert), gotStakingCert)
+			info, err := os.Stat(stakingKeyPath)
+			require.NoError(err)
+			require.Equal(os.FileMode(0o600), info.Mode().Perm())
 			gotGenesis, err := os.ReadFile(genesisPath)
 			require.NoError(err)
 			require.Equal(tt.genesis, gotGenesis)
@@ -1266,6 +1575,155 @@ func TestWriteFiles(t *testing.T) {
 	}
 }
 
+func TestWriteFilesKeysInMemory(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	tmpDir := t.TempDir()
+	stakingKeyPath := filepath.Join(tmpDir, stakingKeyFileName)
+	// left by a previous run storing the keys as files
+	require.NoError(createFileAndWrite(stakingKeyPath, []byte("stakingKey")))
+
+	nodeConfig := node.Config{
+		StakingKey:        "stakingKey",
+		StakingCert:       "stakingCert",
+		StakingSigningKey: base64.StdEncoding.EncodeToString([]byte("signingKey")),
+		ConfigFile:        `{"log-level":"debug"}`,
+	}
+	flags, err := writeFiles(0, []byte("genesis"), tmpDir, &nodeConfig, network.StakingKeysStorageMemory)
+	require.NoError(err)
+	// the keys are neither given on the command line nor on the node config file
+	require.NotContains(flags, config.StakingTLSKeyContentKey)
+	require.NotContains(flags, config.StakingCertContentKey)
+	require.NotContains(flags, config.StakingSignerKeyContentKey)
+	configFilePath := filepath.Join(tmpDir, configFileName)
+	require.Equal(configFilePath, flags[config.ConfigFileKey])
+	configFileBytes, err := os.ReadFile(configFilePath)
+	require.NoError(err)
+	require.JSONEq(nodeConfig.ConfigFile, string(configFileBytes))
+	require.NotContains(flags, config.StakingTLSKeyPathKey)
+	require.NotContains(flags, config.StakingCertPathKey)
+	require.NotContains(flags, config.StakingSignerKeyPathKey)
+	for _, fileName := range []string{stakingKeyFileName, stakingCertFileName, stakingSigningKeyFileName} {
+		_, err := os.Stat(filepath.Join(tmpDir, fileName))
+		require.ErrorIs(err, os.ErrNotExist)
+	}
+
+	// a config file left by a previous run is removed if there is none
+	nodeConfig.ConfigFile = ""
+	flags, err = writeFiles(0, []byte("genesis"), tmpDir, &nodeConfig, network.StakingKeysStorageMemory)
+	require.NoError(err)
+	require.NotContains(flags, config.ConfigFileKey)
+	_, err = os.Stat(configFilePath)
+	require.ErrorIs(err, os.ErrNotExist)
+}
+
+func TestGetStakingKeysEnv(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	nodeConfig := node.Config{
+		StakingKey:        "stakingKey",
+		StakingCert:       "stakingCert",
+		StakingSigningKey: base64.StdEncoding.EncodeToString([]byte("signingKey")),
+	}
+	require.Equal([]string{
+		config.EnvVarName(config.EnvPrefix, config.StakingTLSKeyContentKey) + "=" + base64.StdEncoding.EncodeToString([]byte("stakingKey")),
+		config.EnvVarName(config.EnvPrefix, config.StakingCertContentKey) + "=" + base64.StdEncoding.EncodeToString([]byte("stakingCert")),
+		config.EnvVarName(config.EnvPrefix, config.StakingSignerKeyContentKey) + "=" + nodeConfig.StakingSigningKey,
+	}, getStakingKeysEnv(&nodeConfig))
+}
+
+// Records the env given to the node processes
+type localTestEnvProcessCreator struct {
+	localTestSuccessfulNodeProcessCreator
+	lock sync.Mutex
+	env  map[string][]string
+}
+
+func (lt *localTestEnvProcessCreator) NewNodeProcess(config node.Config, env []string, flags ...string) (NodeProcess, error) {
+	lt.lock.Lock()
+	lt.env[config.Name] = env
+	lt.lock.Unlock()
+	return newMockProcessSuccessful(config, flags...)
+}
+
+// Assert that the nodes of networks keeping the staking keys out of
+// plaintext files are given the keys on their env, and that no key material
+// is left under the node dirs.
+func TestStakingKeysStorageNodeDirs(t *testing.T) {
+	t.Setenv(constants.StakingKeystorePassphraseEnvVar, "passphrase")
+
+	for _, keysStorage := range []string{network.StakingKeysStorageMemory, network.StakingKeysStorageKeystore} {
+		t.Run(keysStorage, func(t *testing.T) {
+			require := require.New(t)
+
+			rootDir := t.TempDir()
+			processCreator := &localTestEnvProcessCreator{env: map[string][]string{}}
+			net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, processCreator, rootDir, "", false)
+			require.NoError(err)
+			networkConfig := testNetworkConfig(t)
+			networkConfig.StakingKeysStorage = keysStorage
+			require.NoError(net.loadConfig(context.Background(), networkConfig))
+			require.NoError(awaitNetworkHealthy(net, defaultHealthyTimeout))
+
+			keys := [][]byte{}
+			for _, nodeConfig := range networkConfig.NodeConfigs {
+				require.Equal(getStakingKeysEnv(&nodeConfig), processCreator.env[nodeConfig.Name])
+				signingKey, err := base64.StdEncoding.DecodeString(nodeConfig.StakingSigningKey)
+				require.NoError(err)
+				keys = append(keys,
+					[]byte(nodeConfig.StakingKey),
+					[]byte(base64.StdEncoding.EncodeToString([]byte(nodeConfig.StakingKey))),
+					[]byte(nodeConfig.StakingSigningKey),
+					signingKey,
+				)
+			}
+			require.NoError(filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() {
+					return err
+				}
+				fileBytes, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				for _, key := range keys {
+					require.False(bytes.Contains(fileBytes, key), "key material found at %s", path)
+				}
+				return nil
+			}))
+			require.NoError(net.Stop(context.Background()))
+		})
+	}
+}
+
+func TestStakingKeystore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	tmpDir := t.TempDir()
+	nodeConfig := node.Config{
+		StakingKey:        "stakingKey",
+		StakingCert:       "stakingCert",
+		StakingSigningKey: "signingKey",
+	}
+	require.NoError(writeStakingKeystore(tmpDir, &nodeConfig, "passphrase"))
+	keystoreBytes, err := os.ReadFile(filepath.Join(tmpDir, stakingKeystoreFileName))
+	require.NoError(err)
+	require.NotContains(string(keystoreBytes), "stakingKey")
+
+	// the keys given in the config are kept
+	restored := node.Config{StakingSigningKey: "otherSigningKey"}
+	require.NoError(readStakingKeystore(tmpDir, &restored, "passphrase"))
+	require.Equal("stakingKey", restored.StakingKey)
+	require.Equal("stakingCert", restored.StakingCert)
+	require.Equal("otherSigningKey", restored.StakingSigningKey)
+
+	require.ErrorIs(readStakingKeystore(tmpDir, &node.Config{}, "other passphrase"), utils.ErrKeystorePassphrase)
+	// no keystore
+	require.NoError(readStakingKeystore(t.TempDir(), &node.Config{}, "passphrase"))
+}
+
 func TestRemoveBeacon(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -1346,3 +1804,148 @@ func TestHealthyDuringNetworkStop(t *testing.T) {
 		require.Fail("Healthy should've returned immediately because network closed")
 	}
 }
+
+// Assert that the nodes can be queried while a call to Healthy is
+// ongoing, and while another operation holds the network lock.
+func TestQueriesDuringHealthy(t *testing.T) {
+	require := require.New(t)
+	networkConfig := testNetworkConfig(t)
+	// Calls to a node's Healthy() function blocks until context cancelled
+	net, err := newNetwork(logging.NoLog{}, newMockAPIHealthyBlocks, &localTestSuccessfulNodeProcessCreator{}, "", "", false)
+	require.NoError(err)
+	err = net.loadConfig(context.Background(), networkConfig)
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	healthyChan := make(chan error)
+	go func() {
+		healthyChan <- net.Healthy(ctx)
+	}()
+	// Wait to make sure we're actually blocking on Health API call
+	time.Sleep(500 * time.Millisecond)
+
+	queriesDone := make(chan struct{})
+	net.lock.Lock()
+	go func() {
+		defer close(queriesDone)
+		names, err := net.GetNodeNames()
+		require.NoError(err)
+		require.Len(names, len(networkConfig.NodeConfigs))
+		_, err = net.GetNode(names[0])
+		require.NoError(err)
+		nodes, err := net.GetAllNodes()
+		require.NoError(err)
+		require.Len(nodes, len(networkConfig.NodeConfigs))
+	}()
+	select {
+	case <-queriesDone:
+	case <-time.After(1 * time.Second):
+		require.Fail("queries should've returned while Healthy is ongoing")
+	}
+	net.lock.Unlock()
+
+	cancel()
+	require.Error(<-healthyChan)
+	require.NoError(net.Stop(context.Background()))
+}
+
+type localTestStoppedNodeProcessCreator struct{}
+
+func (*localTestStoppedNodeProcessCreator) NewNodeProcess(node.Config, []string, ...string) (NodeProcess, error) {
+	process := &mocks.NodeProcess{}
+	process.On("Stop", mock.Anything).Return(0)
+	process.On("Status").Return(status.Stopped)
+	process.On("GetPID").Return(0)
+	return process, nil
+}
+
+func (*localTestStoppedNodeProcessCreator) GetNodeVersion(node.Config) (string, error) {
+	return nodeVersion, nil
+}
+
+// Assert that a node found stopped while waiting for it to be healthy
+// only fails the wait if it is still part of the network and not paused.
+func TestHealthyStoppedNode(t *testing.T) {
+	require := require.New(t)
+	networkConfig := testNetworkConfig(t)
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestStoppedNodeProcessCreator{}, "", "", false)
+	require.NoError(err)
+	err = net.loadConfig(context.Background(), networkConfig)
+	require.NoError(err)
+
+	nodes := net.getRunningNodes()
+	require.ErrorContains(net.nodesHealthy(context.Background(), nodes), "stopped unexpectedly")
+
+	// paused and removed nodes are not waited on
+	net.nodesLock.Lock()
+	for i, node := range nodes {
+		if i%2 == 0 {
+			node.paused = true
+		} else {
+			delete(net.nodes, node.name)
+		}
+	}
+	net.nodesLock.Unlock()
+	require.NoError(net.nodesHealthy(context.Background(), nodes))
+}
+
+func TestReportProgress(t *testing.T) {
+	require := require.New(t)
+
+	// no handler set
+	ln := &localNetwork{}
+	ln.reportProgress(network.PhaseRestartNodes, 1, 2, "restarted node node1")
+
+	events := []network.ProgressEvent{}
+	ln.onProgress = func(event network.ProgressEvent) {
+		events = append(events, event)
+	}
+	ln.reportProgress(network.PhaseRestartNodes, 1, 4, "restarted node node1")
+	ln.reportProgress(network.PhaseCreateSubnets, 0, 0, "created 0 subnets")
+	require.Equal([]network.ProgressEvent{
+		{Phase: network.PhaseRestartNodes, Percent: 25, Message: "restarted node node1"},
+		{Phase: network.PhaseCreateSubnets, Percent: 100, Message: "created 0 subnets"},
+	}, events)
+}
+
+// TestResumeNodeAppliesQueuedWork checks that the work queued while a
+// node was paused is applied to the config it is resumed with
+func TestResumeNodeAppliesQueuedWork(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := context.Background()
+
+	emptyNetworkConfig, err := emptyNetworkConfig()
+	require.NoError(err)
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, "", "", false)
+	require.NoError(err)
+	require.NoError(net.loadConfig(ctx, emptyNetworkConfig))
+	networkConfig := testNetworkConfig(t)
+	nodeName := networkConfig.NodeConfigs[0].Name
+	_, err = net.AddNode(networkConfig.NodeConfigs[0])
+	require.NoError(err)
+
+	require.NoError(net.PauseNode(ctx, nodeName))
+	errQueuedWork := errors.New("queued work failed")
+	net.queueForPausedNode(nodeName, queuedWork{
+		description: "failing work",
+		apply: func(*node.Config) error {
+			return errQueuedWork
+		},
+	})
+	// the node is kept paused with its work queued
+	require.ErrorIs(net.ResumeNode(ctx, nodeName), errQueuedWork)
+	pausedNode, err := net.GetNode(nodeName)
+	require.NoError(err)
+	require.True(pausedNode.GetPaused())
+
+	net.pausedNodesQueuedWork[nodeName] = nil
+	net.queueForPausedNode(nodeName, queuedWork{description: "track subnets", apply: setTrackedSubnetsFlag("subnet1")})
+	net.queueForPausedNode(nodeName, queuedWork{description: "reload VM plugins"})
+	require.NoError(net.ResumeNode(ctx, nodeName))
+	resumedNode, err := net.GetNode(nodeName)
+	require.NoError(err)
+	require.False(resumedNode.GetPaused())
+	require.Equal("subnet1", resumedNode.GetConfig().Flags[config.TrackSubnetsKey])
+	require.Empty(net.pausedNodesQueuedWork[nodeName])
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/luxdefi/netrunner/api"
 	"github.com/luxdefi/netrunner/network/node"
 	"github.com/luxdefi/netrunner/network/node/status"
+	"github.com/luxdefi/node/config"
 	"github.com/luxdefi/node/ids"
 	"github.com/luxdefi/node/message"
 	"github.com/luxdefi/node/network/peer"
@@ -71,10 +72,12 @@ type localNode struct {
 	pluginDir string
 	// The node config
 	config node.Config
+	// The flags the node process was started with
+	args []string
 	// The node httpHost
 	httpHost string
 	// maps from peer ID to peer object
-	attachedPeers map[string]peer.Peer
+	attachedPeers map[string]attachedPeer
 	// signals that the process is stopped but the information is valid
 	// and can be resumed
 	paused bool
@@ -86,7 +89,7 @@ func defaultGetConnFunc(ctx context.Context, node node.Node) (net.Conn, error) {
 }
 
 // AttachPeer: see Network
-func (node *localNode) AttachPeer(ctx context.Context, router router.InboundHandler) (peer.Peer, error) {
+func (node *localNode) AttachPeer(ctx context.Context, router router.InboundHandler, behavior *node.PeerBehavior) (peer.Peer, error) {
 	tlsCert, err := staking.NewTLSCert()
 	if err != nil {
 		return nil, err
@@ -125,6 +128,7 @@ func (node *localNode) AttachPeer(ctx context.Context, router router.InboundHand
 	if err != nil {
 		return nil, err
 	}
+	handler := &peerMessageHandler{handler: router}
 	signerIP := ips.NewDynamicIPPort(net.IPv6zero, 0)
 	tls := tlsCert.PrivateKey.(crypto.Signer)
 	config := &peer.Config{
@@ -133,7 +137,7 @@ func (node *localNode) AttachPeer(ctx context.Context, router router.InboundHand
 		Log:                  logging.NoLog{},
 		InboundMsgThrottler:  throttling.NewNoInboundThrottler(),
 		Network:              peer.TestNetwork,
-		Router:               router,
+		Router:               handler,
 		VersionCompatibility: version.GetCompatibility(node.networkID),
 		MySubnets:            set.Set[ids.ID]{},
 		Beacons:              validators.NewSet(),
@@ -167,17 +171,104 @@ func (node *localNode) AttachPeer(ctx context.Context, router router.InboundHand
 		return nil, err
 	}
 
-	node.attachedPeers[p.ID().String()] = p
+	attached := attachedPeer{peer: p, handler: handler}
+	if behavior != nil {
+		attached.behavior = *behavior
+	}
+	node.attachedPeers[p.ID().String()] = attached
 	return p, nil
 }
 
+// Sends [content] from the attached peer to the node, applying the
+// peer misbehavior given on attach. Returns true if all the resulting
+// messages were sent.
 func (node *localNode) SendOutboundMessage(ctx context.Context, peerID string, content []byte, op uint32) (bool, error) {
-	attachedPeer, ok := node.attachedPeers[peerID]
+	attached, ok := node.attachedPeers[peerID]
 	if !ok {
 		return false, fmt.Errorf("peer with ID %s is not attached here", peerID)
 	}
-	msg := NewTestMsg(message.Op(op), content, false)
-	return attachedPeer.Send(ctx, msg), nil
+	msgs, err := applyPeerBehavior(attached.behavior, content)
+	if err != nil {
+		return false, err
+	}
+	sent := true
+	for _, msgBytes := range msgs {
+		if attached.behavior.SendDelay > 0 {
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case <-time.After(attached.behavior.SendDelay):
+			}
+		}
+		msg := NewTestMsg(message.Op(op), msgBytes, false)
+		attached.handler.record(false, msg.Op(), msgBytes)
+		sent = attached.peer.Send(ctx, msg) && sent
+	}
+	return sent, nil
+}
+
+// See node.Node
+func (node *localNode) RecordPeerMessages(peerID string, path string) error {
+	attached, ok := node.attachedPeers[peerID]
+	if !ok {
+		return fmt.Errorf("peer with ID %s is not attached here", peerID)
+	}
+	if path == "" {
+		return attached.handler.stopRecording()
+	}
+	return attached.handler.startRecording(path)
+}
+
+// See node.Node
+func (node *localNode) SubscribePeerMessages(peerID string) (<-chan node.PeerMessage, func(), error) {
+	attached, ok := node.attachedPeers[peerID]
+	if !ok {
+		return nil, nil, fmt.Errorf("peer with ID %s is not attached here", peerID)
+	}
+	msgs, unsubscribe := attached.handler.subscribe()
+	return msgs, unsubscribe, nil
+}
+
+// Closes the channels returned by SubscribePeerMessages, as the
+// attached peers stop receiving messages when the node stops.
+func (node *localNode) closePeerSubscriptions() {
+	for _, attached := range node.attachedPeers {
+		attached.handler.closeSubscriptions()
+	}
+}
+
+// See node.Node
+func (node *localNode) ReplayPeerMessages(ctx context.Context, peerID string, path string, keepTiming bool) (uint32, error) {
+	attached, ok := node.attachedPeers[peerID]
+	if !ok {
+		return 0, fmt.Errorf("peer with ID %s is not attached here", peerID)
+	}
+	msgs, err := readRecordedMessages(path)
+	if err != nil {
+		return 0, err
+	}
+	numSent := uint32(0)
+	var lastTime time.Time
+	for _, recorded := range msgs {
+		if recorded.Inbound {
+			continue
+		}
+		if keepTiming && !lastTime.IsZero() {
+			select {
+			case <-ctx.Done():
+				return numSent, ctx.Err()
+			case <-time.After(recorded.Time.Sub(lastTime)):
+			}
+		}
+		lastTime = recorded.Time
+		msg := NewTestMsg(message.Op(recorded.Op), recorded.Bytes, false)
+		attached.handler.record(false, msg.Op(), recorded.Bytes)
+		if !attached.peer.Send(ctx, msg) {
+			return numSent, fmt.Errorf("failed to send recorded message %d", numSent+1)
+		}
+		numSent++
+	}
+	return numSent, nil
 }
 
 // See node.Node
@@ -197,10 +288,27 @@ func (node *localNode) GetAPIClient() api.Client {
 
 // See node.Node
 func (node *localNode) GetURL() string {
-	if node.httpHost == "0.0.0.0" || node.httpHost == "." {
+	switch {
+	case node.httpHost == "0.0.0.0" || node.httpHost == ".":
 		return "0.0.0.0"
+	case node.httpHost == "::":
+		return "::1"
+	case isBoundToInterface(node.httpHost):
+		return node.httpHost
+	default:
+		return "127.0.0.1"
+	}
+}
+
+// Returns true if [httpHost] binds the node APIs to a specific,
+// non loopback, interface, so they are not reachable through localhost.
+func isBoundToInterface(httpHost string) bool {
+	switch httpHost {
+	case "", ".", "0.0.0.0", "::", "localhost", "127.0.0.1":
+		return false
+	default:
+		return true
 	}
-	return "127.0.0.1"
 }
 
 // See node.Node
@@ -284,3 +392,37 @@ func (node *localNode) GetFlag(k string) (string, error) {
 func (node *localNode) GetPaused() bool {
 	return node.paused
 }
+
+// See node.Node
+func (node *localNode) GetPID() int {
+	if node.paused || node.process == nil {
+		return 0
+	}
+	return node.process.GetPID()
+}
+
+// See node.Node
+func (node *localNode) GetArgs() []string {
+	return node.args
+}
+
+// Returns true if one of the ports assigned to the stopped node is now
+// taken by another process. Ports given on the node config or config file
+// are not reassigned, so they are not considered.
+func (node *localNode) portsTaken() bool {
+	var configFile map[string]interface{}
+	if node.config.ConfigFile != "" {
+		if err := json.Unmarshal([]byte(node.config.ConfigFile), &configFile); err != nil {
+			return false
+		}
+	}
+	for _, portKey := range []string{config.HTTPPortKey, config.StakingPortKey} {
+		if _, ok := node.config.Flags[portKey]; ok {
+			return false
+		}
+		if _, ok := configFile[portKey]; ok {
+			return false
+		}
+	}
+	return ports.inUse(node.apiPort) || ports.inUse(node.p2pPort)
+}
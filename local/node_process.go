@@ -2,11 +2,13 @@ package local
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"sync"
+	"syscall"
 
 	"github.com/luxdefi/netrunner/network/node"
 	"github.com/luxdefi/netrunner/network/node/status"
@@ -24,17 +26,21 @@ type NodeProcess interface {
 	// Sends a SIGINT to this process and returns the process's
 	// exit code.
 	// If [ctx] is cancelled, sends a SIGKILL to this process and descendants.
+	// Once the process exits, the processes left on its group are killed.
 	// We assume sending a SIGKILL to a process will always successfully kill it.
 	// Subsequent calls to [Stop] have no effect.
 	Stop(ctx context.Context) int
 	// Returns the status of the process.
 	Status() status.Status
+	// Returns the OS process ID, or 0 if the process is not running.
+	GetPID() int
 }
 
 // NodeProcessCreator is an interface for new node process creation
 type NodeProcessCreator interface {
 	GetNodeVersion(config node.Config) (string, error)
-	NewNodeProcess(config node.Config, args ...string) (NodeProcess, error)
+	// [env] entries are added to the environment of the process.
+	NewNodeProcess(config node.Config, env []string, args ...string) (NodeProcess, error)
 }
 
 type nodeProcessCreator struct {
@@ -53,9 +59,12 @@ type nodeProcessCreator struct {
 // NewNodeProcess creates a new process of the passed binary
 // If the config has redirection set to `true` for either StdErr or StdOut,
 // the output will be redirected and colored
-func (npc *nodeProcessCreator) NewNodeProcess(config node.Config, args ...string) (NodeProcess, error) {
+func (npc *nodeProcessCreator) NewNodeProcess(config node.Config, env []string, args ...string) (NodeProcess, error) {
 	// Start the Lux node and pass it the flags defined above
 	cmd := exec.Command(config.BinaryPath, args...) //nolint
+	if len(env) != 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	// assign a new color to this process (might not be used if the config isn't set for it)
 	color := npc.colorPicker.NextColor()
 	// Optionally redirect stdout and stderr
@@ -90,6 +99,12 @@ type nodeProcess struct {
 }
 
 func newNodeProcess(name string, log logging.Logger, cmd *exec.Cmd) (*nodeProcess, error) {
+	// Start the node in its own process group, so the VM plugin processes it
+	// starts can be killed together with it
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
 	np := &nodeProcess{
 		name:         name,
 		log:          log,
@@ -117,7 +132,8 @@ func (p *nodeProcess) start() error {
 }
 
 // Wait for the process to exit.
-// When it does, update the state and close [p.closedOnStop]
+// When it does, kill the processes left on its group, update the state
+// and close [p.closedOnStop]
 func (p *nodeProcess) awaitExit() {
 	if err := p.cmd.Wait(); err != nil {
 		p.log.Debug("node returned error on wait", zap.String("node", p.name), zap.Error(err))
@@ -125,6 +141,9 @@ func (p *nodeProcess) awaitExit() {
 
 	p.log.Debug("node process finished", zap.String("node", p.name))
 
+	// VM plugins may outlive the node, eg if it crashed or was killed
+	killProcessGroup(p.cmd.Process.Pid, p.name, p.log)
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
@@ -187,6 +206,24 @@ func (p *nodeProcess) Status() status.Status {
 	return p.state
 }
 
+func (p *nodeProcess) GetPID() int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.state == status.Stopped || p.cmd.Process == nil {
+		return 0
+	}
+	return p.cmd.Process.Pid
+}
+
+// Sends a SIGKILL to all the processes in the group led by [pgid].
+// It is not an error for the group to be already empty.
+func killProcessGroup(pgid int, nodeName string, log logging.Logger) {
+	if err := syscall.Kill(-pgid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		log.Warn("error killing process group", zap.String("node", nodeName), zap.Int("pgid", pgid), zap.Error(err))
+	}
+}
+
 func killDescendants(pid int32, log logging.Logger) {
 	procs, err := process.Processes()
 	if err != nil {
@@ -0,0 +1,86 @@
+package local
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/shirou/gopsutil/process"
+	"github.com/stretchr/testify/require"
+)
+
+// Starts a shell that, as a node running a VM plugin, starts a child process
+// that ignores SIGINT. Returns the process and the PID of the child.
+func startProcessWithChild(t *testing.T, script string) (*nodeProcess, int32) {
+	require := require.New(t)
+
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	cmd := exec.Command("sh", "-c", script, "sh", pidFile) //nolint
+	np, err := newNodeProcess("node1", logging.NoLog{}, cmd)
+	require.NoError(err)
+
+	var childPID int
+	require.Eventually(func() bool {
+		pidBytes, err := os.ReadFile(pidFile)
+		if err != nil || !strings.HasSuffix(string(pidBytes), "\n") {
+			return false
+		}
+		childPID, err = strconv.Atoi(strings.TrimSpace(string(pidBytes)))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	return np, int32(childPID)
+}
+
+// Returns true if [pid] is running. Exited processes not yet reaped
+// by their new parent don't count.
+func isRunning(pid int32) bool {
+	proc, err := process.NewProcess(pid)
+	if err != nil {
+		return false
+	}
+	status, err := proc.Status()
+	if err != nil {
+		return false
+	}
+	return status != "Z"
+}
+
+func TestNodeProcessStopKillsProcessGroup(t *testing.T) {
+	require := require.New(t)
+
+	// the shell exits on SIGINT, but the background child ignores it
+	np, childPID := startProcessWithChild(t, `sleep 60 & echo $! > "$1"; wait`)
+	require.True(isRunning(childPID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	np.Stop(ctx)
+	require.NoError(ctx.Err())
+
+	require.Eventually(func() bool {
+		return !isRunning(childPID)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNodeProcessKillKillsProcessGroup(t *testing.T) {
+	require := require.New(t)
+
+	// neither the shell nor the child exit on SIGINT
+	np, childPID := startProcessWithChild(t, `trap '' INT; sleep 60 & echo $! > "$1"; wait`)
+	require.True(isRunning(childPID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	np.Stop(ctx)
+	require.Error(ctx.Err())
+
+	require.Eventually(func() bool {
+		return !isRunning(childPID)
+	}, 5*time.Second, 10*time.Millisecond)
+}
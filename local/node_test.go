@@ -193,7 +193,7 @@ func TestAttachPeer(t *testing.T) {
 		getConnFunc: func(ctx context.Context, n node.Node) (net.Conn, error) {
 			return peerConn, nil
 		},
-		attachedPeers: map[string]peer.Peer{},
+		attachedPeers: map[string]attachedPeer{},
 	}
 
 	// For message creation and parsing
@@ -221,7 +221,7 @@ func TestAttachPeer(t *testing.T) {
 
 	// attach a test peer to [node]
 	handler := &noOpInboundHandler{}
-	p, err := node.AttachPeer(context.Background(), handler)
+	p, err := node.AttachPeer(context.Background(), handler, nil)
 	require.NoError(err)
 
 	// we'll use a Chits message for testing. (We could use any message type.)
@@ -242,3 +242,30 @@ func TestAttachPeer(t *testing.T) {
 	// also ensures that [require] calls will be reflected in test results if failed
 	require.NoError(<-errCh)
 }
+
+func TestIsBoundToInterface(t *testing.T) {
+	tests := []struct {
+		httpHost      string
+		expectedBound bool
+		expectedURL   string
+	}{
+		{httpHost: "", expectedBound: false, expectedURL: "127.0.0.1"},
+		{httpHost: "localhost", expectedBound: false, expectedURL: "127.0.0.1"},
+		{httpHost: "127.0.0.1", expectedBound: false, expectedURL: "127.0.0.1"},
+		{httpHost: ".", expectedBound: false, expectedURL: "0.0.0.0"},
+		{httpHost: "0.0.0.0", expectedBound: false, expectedURL: "0.0.0.0"},
+		{httpHost: "::", expectedBound: false, expectedURL: "::1"},
+		{httpHost: "192.168.1.10", expectedBound: true, expectedURL: "192.168.1.10"},
+		{httpHost: "10.0.0.5", expectedBound: true, expectedURL: "10.0.0.5"},
+		{httpHost: "fd00::1", expectedBound: true, expectedURL: "fd00::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.httpHost, func(t *testing.T) {
+			require := require.New(t)
+
+			require.Equal(tt.expectedBound, isBoundToInterface(tt.httpHost))
+			node := localNode{httpHost: tt.httpHost}
+			require.Equal(tt.expectedURL, node.GetURL())
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/genesis"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/formatting/address"
+	"github.com/luxdefi/node/vms/components/lux"
+	"github.com/luxdefi/node/vms/platformvm"
+	"github.com/luxdefi/node/vms/platformvm/txs"
+	"github.com/luxdefi/node/vms/secp256k1fx"
+	"github.com/luxdefi/node/wallet/subnet/primary/common"
+	"go.uber.org/zap"
+)
+
+// Transfer sends [transferSpec] amount of the network key funds to the
+// given P-Chain address
+func (ln *localNetwork) Transfer(ctx context.Context, transferSpec network.TransferSpec) (ids.ID, error) {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	if transferSpec.Amount == 0 {
+		return ids.Empty, errors.New("zero transfer amount")
+	}
+	to, err := address.ParseToID(transferSpec.To)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failure parsing transfer destination %q: %w", transferSpec.To, err)
+	}
+	clientURI, err := ln.getClientURI()
+	if err != nil {
+		return ids.Empty, err
+	}
+	w, err := newWallet(ctx, clientURI, nil, ln.txSigner)
+	if err != nil {
+		return ids.Empty, err
+	}
+	cctx, cancel := createDefaultCtx(ctx)
+	txID, err := w.pWallet.IssueBaseTx(
+		[]*lux.TransferableOutput{
+			{
+				Asset: lux.Asset{
+					ID: w.pWallet.LUXAssetID(),
+				},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: transferSpec.Amount,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{to},
+					},
+				},
+			},
+		},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+	cancel()
+	if err != nil {
+		return ids.Empty, newTxError(fmt.Errorf("P-Wallet Tx Error %s %w, to %s", "IssueBaseTx", err, transferSpec.To))
+	}
+	ln.log.Info("transferred funds",
+		zap.String("to", transferSpec.To),
+		zap.Uint64("amount", transferSpec.Amount),
+		zap.String("tx-ID", txID.String()),
+	)
+	return txID, nil
+}
+
+// Stake stakes network key funds on a primary network node, adding it as
+// validator or delegating to it
+func (ln *localNetwork) Stake(ctx context.Context, stakeSpec network.StakeSpec) (ids.ID, error) {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	node, ok := ln.nodes[stakeSpec.NodeName]
+	if !ok {
+		return ids.Empty, fmt.Errorf("%w: %s", network.ErrNodeNotFound, stakeSpec.NodeName)
+	}
+	nodeID := node.GetNodeID()
+	clientURI, err := ln.getClientURI()
+	if err != nil {
+		return ids.Empty, err
+	}
+	platformCli := platformvm.NewClient(clientURI)
+	cctx, cancel := createDefaultCtx(ctx)
+	vdrs, err := platformCli.GetCurrentValidators(cctx, constants.PrimaryNetworkID, []ids.NodeID{nodeID})
+	cancel()
+	if err != nil {
+		return ids.Empty, err
+	}
+	var validationEndTime *time.Time
+	if len(vdrs) > 0 {
+		endTime := time.Unix(int64(vdrs[0].EndTime), 0)
+		validationEndTime = &endTime
+	}
+	validator, err := ln.getStakeValidator(stakeSpec, nodeID, validationEndTime, time.Now())
+	if err != nil {
+		return ids.Empty, err
+	}
+	w, err := newWallet(ctx, clientURI, nil, ln.txSigner)
+	if err != nil {
+		return ids.Empty, err
+	}
+	var rewardOwners []string
+	if stakeSpec.RewardAddress != "" {
+		rewardOwners = []string{stakeSpec.RewardAddress}
+	}
+	rewardsOwner, err := getSpecOwner(w, rewardOwners, 1)
+	if err != nil {
+		return ids.Empty, err
+	}
+
+	var txID ids.ID
+	if stakeSpec.Delegate {
+		cctx, cancel := createDefaultCtx(ctx)
+		txID, err = w.pWallet.IssueAddPermissionlessDelegatorTx(
+			validator,
+			w.pWallet.LUXAssetID(),
+			rewardsOwner,
+			common.WithContext(cctx),
+			defaultPoll,
+		)
+		cancel()
+		if err != nil {
+			return ids.Empty, newTxError(fmt.Errorf("P-Wallet Tx Error %s %w, node ID %s", "IssueAddPermissionlessDelegatorTx", err, nodeID.String()))
+		}
+	} else {
+		proofOfPossession, err := getProofOfPossession(node)
+		if err != nil {
+			return ids.Empty, err
+		}
+		cctx, cancel := createDefaultCtx(ctx)
+		txID, err = w.pWallet.IssueAddPermissionlessValidatorTx(
+			validator,
+			proofOfPossession,
+			w.pWallet.LUXAssetID(),
+			rewardsOwner,
+			rewardsOwner,
+			10*10000, // 10% fee percent, times 10000 to make it as shares
+			common.WithContext(cctx),
+			defaultPoll,
+		)
+		cancel()
+		if err != nil {
+			return ids.Empty, newTxError(fmt.Errorf("P-Wallet Tx Error %s %w, node ID %s", "IssueAddPermissionlessValidatorTx", err, nodeID.String()))
+		}
+	}
+	ln.log.Info("staked on node",
+		zap.String("node-name", stakeSpec.NodeName),
+		zap.String("node-ID", nodeID.String()),
+		zap.Bool("delegate", stakeSpec.Delegate),
+		zap.Uint64("amount", validator.Wght),
+		zap.Time("end-time", validator.EndTime()),
+		zap.String("tx-ID", txID.String()),
+	)
+	return txID, nil
+}
+
+// Returns the primary network validation of [stakeSpec] for [nodeID],
+// starting after [now]. [validationEndTime] is the end of the current
+// validation of the node, nil if it is not a validator. The amount
+// defaults to the minimum stake, and the duration to the one of the
+// network, or the rest of the validation for a delegation.
+func (ln *localNetwork) getStakeValidator(
+	stakeSpec network.StakeSpec,
+	nodeID ids.NodeID,
+	validationEndTime *time.Time,
+	now time.Time,
+) (*txs.SubnetValidator, error) {
+	switch {
+	case stakeSpec.Delegate && validationEndTime == nil:
+		return nil, fmt.Errorf("node %s is not a primary network validator", stakeSpec.NodeName)
+	case !stakeSpec.Delegate && validationEndTime != nil:
+		return nil, fmt.Errorf("node %s is already a primary network validator", stakeSpec.NodeName)
+	}
+	startTime := now.Add(ln.validationStartOffset())
+	endTime := now.Add(ln.validationDuration())
+	if stakeSpec.Duration != 0 {
+		endTime = startTime.Add(stakeSpec.Duration)
+	} else if stakeSpec.Delegate {
+		// delegations can't outlast the validation
+		endTime = *validationEndTime
+	}
+	amount := stakeSpec.Amount
+	if amount == 0 {
+		amount = genesis.LocalParams.MinValidatorStake
+		if stakeSpec.Delegate {
+			amount = genesis.LocalParams.MinDelegatorStake
+		}
+	}
+	return &txs.SubnetValidator{
+		Validator: txs.Validator{
+			NodeID: nodeID,
+			Start:  uint64(startTime.Unix()),
+			End:    uint64(endTime.Unix()),
+			Wght:   amount,
+		},
+		Subnet: ids.Empty,
+	}, nil
+}
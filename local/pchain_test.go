@@ -0,0 +1,104 @@
+package local
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/genesis"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferInvalidSpec(t *testing.T) {
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, "", "", false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		spec network.TransferSpec
+	}{
+		{name: "zero amount", spec: network.TransferSpec{To: "P-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p"}},
+		{name: "invalid address", spec: network.TransferSpec{To: "invalid", Amount: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := net.Transfer(context.Background(), tt.spec)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestStakeNodeNotFound(t *testing.T) {
+	net, err := newNetwork(logging.NoLog{}, newMockAPISuccessful, &localTestSuccessfulNodeProcessCreator{}, "", "", false)
+	require.NoError(t, err)
+
+	_, err = net.Stake(context.Background(), network.StakeSpec{NodeName: "node1"})
+	require.ErrorIs(t, err, network.ErrNodeNotFound)
+}
+
+func TestGetStakeValidator(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	validationEndTime := now.Add(48 * time.Hour)
+	nodeID := ids.GenerateTestNodeID()
+
+	tests := []struct {
+		name              string
+		spec              network.StakeSpec
+		validationEndTime *time.Time
+		expectedEndTime   time.Time
+		expectedWeight    uint64
+		expectErr         bool
+	}{
+		{
+			name:            "validation with defaults",
+			spec:            network.StakeSpec{NodeName: "node1"},
+			expectedEndTime: now.Add(defaultValidationDuration),
+			expectedWeight:  genesis.LocalParams.MinValidatorStake,
+		},
+		{
+			name:            "validation with duration",
+			spec:            network.StakeSpec{NodeName: "node1", Amount: 3000, Duration: time.Hour},
+			expectedEndTime: now.Add(network.DefaultValidationStartOffset + time.Hour),
+			expectedWeight:  3000,
+		},
+		{
+			name:              "validation of a validator",
+			spec:              network.StakeSpec{NodeName: "node1"},
+			validationEndTime: &validationEndTime,
+			expectErr:         true,
+		},
+		{
+			name:              "delegation until the validation ends",
+			spec:              network.StakeSpec{NodeName: "node1", Delegate: true},
+			validationEndTime: &validationEndTime,
+			expectedEndTime:   validationEndTime,
+			expectedWeight:    genesis.LocalParams.MinDelegatorStake,
+		},
+		{
+			name:      "delegation to a non validator",
+			spec:      network.StakeSpec{NodeName: "node1", Delegate: true},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			ln := &localNetwork{}
+			validator, err := ln.getStakeValidator(tt.spec, nodeID, tt.validationEndTime, now)
+			if tt.expectErr {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(nodeID, validator.NodeID)
+			require.Equal(ids.Empty, validator.Subnet)
+			require.Equal(uint64(now.Add(network.DefaultValidationStartOffset).Unix()), validator.Start)
+			require.Equal(uint64(tt.expectedEndTime.Unix()), validator.End)
+			require.Equal(tt.expectedWeight, validator.Wght)
+		})
+	}
+}
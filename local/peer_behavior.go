@@ -0,0 +1,110 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/network/peer"
+	"github.com/luxdefi/node/proto/pb/p2p"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	ErrCompressedMessage = errors.New("can't replace the chain ID of a compressed message")
+	ErrNoChainID         = errors.New("message has no chain ID")
+)
+
+// A test peer attached to a node, with the misbehavior it applies to
+// the messages sent to the node.
+type attachedPeer struct {
+	peer     peer.Peer
+	behavior node.PeerBehavior
+	handler  *peerMessageHandler
+}
+
+// Returns the messages the test peer sends to the node in place of
+// [content], according to [behavior].
+func applyPeerBehavior(behavior node.PeerBehavior, content []byte) ([][]byte, error) {
+	if behavior.WrongChainID {
+		var err error
+		content, err = replaceChainID(content, ids.GenerateTestID())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if behavior.MalformedBody {
+		content = malformBody(content)
+	}
+	numMsgs := int(behavior.Duplicates) + 1
+	msgs := make([][]byte, 0, numMsgs)
+	for i := 0; i < numMsgs; i++ {
+		msgs = append(msgs, content)
+	}
+	return msgs, nil
+}
+
+// Returns a copy of [content] with its second half inverted, so it
+// can't be parsed or fails validation, while keeping its length.
+func malformBody(content []byte) []byte {
+	malformed := make([]byte, len(content))
+	copy(malformed, content)
+	for i := len(malformed) / 2; i < len(malformed); i++ {
+		malformed[i] = ^malformed[i]
+	}
+	return malformed
+}
+
+// Returns the chain ID of the p2p message body [msg], or the empty
+// ID if it has none.
+func getChainID(msg proto.Message) ids.ID {
+	inner := msg.ProtoReflect()
+	chainIDField := inner.Descriptor().Fields().ByName("chain_id")
+	if chainIDField == nil || chainIDField.Kind() != protoreflect.BytesKind {
+		return ids.Empty
+	}
+	chainID, err := ids.ToID(inner.Get(chainIDField).Bytes())
+	if err != nil {
+		return ids.Empty
+	}
+	return chainID
+}
+
+// Returns a copy of the p2p message [content] with its chain ID set to [chainID].
+func replaceChainID(content []byte, chainID ids.ID) ([]byte, error) {
+	msg := &p2p.Message{}
+	if err := proto.Unmarshal(content, msg); err != nil {
+		return nil, fmt.Errorf("couldn't parse message: %w", err)
+	}
+	var (
+		replaced   bool
+		compressed bool
+	)
+	msg.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() == protoreflect.BytesKind {
+			// only the compressed forms of a message are bytes
+			compressed = true
+			return false
+		}
+		if fd.Kind() != protoreflect.MessageKind {
+			return true
+		}
+		inner := v.Message()
+		chainIDField := inner.Descriptor().Fields().ByName("chain_id")
+		if chainIDField == nil {
+			return true
+		}
+		inner.Set(chainIDField, protoreflect.ValueOfBytes(chainID[:]))
+		replaced = true
+		return false
+	})
+	switch {
+	case compressed:
+		return nil, ErrCompressedMessage
+	case !replaced:
+		return nil, ErrNoChainID
+	}
+	return proto.Marshal(msg)
+}
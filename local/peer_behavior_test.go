@@ -0,0 +1,69 @@
+package local
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/message"
+	"github.com/luxdefi/node/proto/pb/p2p"
+	"github.com/luxdefi/node/utils/compression"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestApplyPeerBehavior(t *testing.T) {
+	require := require.New(t)
+
+	mc, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		"",
+		compression.TypeNone,
+		10*time.Second,
+	)
+	require.NoError(err)
+	chainID := constants.PlatformChainID
+	msg, err := mc.Chits(chainID, 42, []ids.ID{}, []ids.ID{ids.GenerateTestID()})
+	require.NoError(err)
+	content := msg.Bytes()
+
+	// well behaved peer sends the message as is
+	msgs, err := applyPeerBehavior(node.PeerBehavior{}, content)
+	require.NoError(err)
+	require.Equal([][]byte{content}, msgs)
+
+	msgs, err = applyPeerBehavior(node.PeerBehavior{Duplicates: 2}, content)
+	require.NoError(err)
+	require.Equal([][]byte{content, content, content}, msgs)
+
+	msgs, err = applyPeerBehavior(node.PeerBehavior{MalformedBody: true}, content)
+	require.NoError(err)
+	require.Len(msgs, 1)
+	require.Len(msgs[0], len(content))
+	require.NotEqual(content, msgs[0])
+
+	msgs, err = applyPeerBehavior(node.PeerBehavior{WrongChainID: true}, content)
+	require.NoError(err)
+	require.Len(msgs, 1)
+	parsed := &p2p.Message{}
+	require.NoError(proto.Unmarshal(msgs[0], parsed))
+	require.Len(parsed.GetChits().ChainId, len(chainID))
+	require.NotEqual(chainID[:], parsed.GetChits().ChainId)
+	require.Equal(uint32(42), parsed.GetChits().RequestId)
+
+	_, err = applyPeerBehavior(node.PeerBehavior{WrongChainID: true}, []byte{0xff})
+	require.Error(err)
+}
+
+func TestGetChainID(t *testing.T) {
+	require := require.New(t)
+
+	chainID := ids.GenerateTestID()
+	require.Equal(chainID, getChainID(&p2p.Chits{ChainId: chainID[:]}))
+	require.Equal(ids.Empty, getChainID(&p2p.Ping{}))
+}
@@ -0,0 +1,195 @@
+package local
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/node/message"
+	"github.com/luxdefi/node/snow/networking/router"
+	"google.golang.org/protobuf/proto"
+)
+
+// max number of received messages buffered for a subscriber
+const peerMessageSubscriptionBufferSize = 1024
+
+var (
+	_ router.InboundHandler = (*peerMessageHandler)(nil)
+
+	ErrAlreadyRecording = errors.New("peer messages are already being recorded")
+	ErrNotRecording     = errors.New("peer messages are not being recorded")
+)
+
+// A p2p message sent or received by an attached peer, as written
+// to a recording file, one JSON object per line.
+type RecordedMessage struct {
+	Time time.Time `json:"time"`
+	// true if the message was received by the peer, false if it was
+	// sent by the peer to the node
+	Inbound bool   `json:"inbound"`
+	Op      uint32 `json:"op"`
+	// for outbound messages, the bytes sent to the node. for inbound
+	// messages, the serialized body of the message, if it is a
+	// protobuf message
+	Bytes []byte `json:"bytes"`
+}
+
+// Wraps the inbound handler of an attached peer, so the messages
+// exchanged with the node can be recorded, and the received ones
+// forwarded to subscribers.
+type peerMessageHandler struct {
+	handler router.InboundHandler
+
+	lock sync.Mutex
+	// file the messages are recorded to. nil if not recording
+	recordFile *os.File
+	recordBuf  *bufio.Writer
+	// channels the received messages are forwarded to
+	subscribers map[chan node.PeerMessage]struct{}
+}
+
+func (h *peerMessageHandler) HandleInbound(ctx context.Context, m message.InboundMessage) {
+	peerMsg := node.PeerMessage{Op: uint32(m.Op())}
+	if body, ok := m.Message().(proto.Message); ok {
+		// error is ignored as the message was already parsed
+		peerMsg.Bytes, _ = proto.Marshal(body)
+		peerMsg.ChainID = getChainID(body)
+	}
+	h.record(true, m.Op(), peerMsg.Bytes)
+	h.publish(peerMsg)
+	h.handler.HandleInbound(ctx, m)
+}
+
+// Returns a channel with the messages received from now on, and a
+// function to stop receiving them.
+func (h *peerMessageHandler) subscribe() (<-chan node.PeerMessage, func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	ch := make(chan node.PeerMessage, peerMessageSubscriptionBufferSize)
+	if h.subscribers == nil {
+		h.subscribers = map[chan node.PeerMessage]struct{}{}
+	}
+	h.subscribers[ch] = struct{}{}
+	unsubscribe := func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// Closes the channels of all the subscribers.
+func (h *peerMessageHandler) closeSubscriptions() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for ch := range h.subscribers {
+		close(ch)
+	}
+	h.subscribers = nil
+}
+
+// Forwards [msg] to the subscribers, dropping it for the ones whose
+// channel is full, so a slow subscriber doesn't block the peer.
+func (h *peerMessageHandler) publish(msg node.PeerMessage) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
+
+// Starts recording the messages exchanged by the peer to a new file at [path].
+func (h *peerMessageHandler) startRecording(path string) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.recordFile != nil {
+		return ErrAlreadyRecording
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("couldn't create recording file: %w", err)
+	}
+	h.recordFile = f
+	h.recordBuf = bufio.NewWriter(f)
+	return nil
+}
+
+// Stops recording and closes the recording file.
+func (h *peerMessageHandler) stopRecording() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.recordFile == nil {
+		return ErrNotRecording
+	}
+	err := h.recordBuf.Flush()
+	if closeErr := h.recordFile.Close(); err == nil {
+		err = closeErr
+	}
+	h.recordFile = nil
+	h.recordBuf = nil
+	return err
+}
+
+// Records a message if recording. Write errors are ignored, so they
+// don't affect the peer; they are reported on flush when stopping.
+func (h *peerMessageHandler) record(inbound bool, op message.Op, bytes []byte) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.recordFile == nil {
+		return
+	}
+	line, err := json.Marshal(RecordedMessage{
+		Time:    time.Now(),
+		Inbound: inbound,
+		Op:      uint32(op),
+		Bytes:   bytes,
+	})
+	if err != nil {
+		return
+	}
+	_, _ = h.recordBuf.Write(append(line, '\n'))
+}
+
+// Reads the messages recorded at [path].
+func readRecordedMessages(path string) ([]RecordedMessage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open recording file: %w", err)
+	}
+	defer f.Close()
+
+	msgs := []RecordedMessage{}
+	scanner := bufio.NewScanner(f)
+	// messages may be bigger than the default max line size
+	scanner.Buffer(nil, 4*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var msg RecordedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			return nil, fmt.Errorf("couldn't parse recorded message %d: %w", len(msgs)+1, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, scanner.Err()
+}
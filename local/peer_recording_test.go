@@ -0,0 +1,80 @@
+package local
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/message"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerMessageRecording(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	h := &peerMessageHandler{handler: &noOpInboundHandler{}}
+
+	// not recorded
+	h.record(false, message.ChitsOp, []byte{0})
+
+	require.ErrorIs(h.stopRecording(), ErrNotRecording)
+	require.NoError(h.startRecording(path))
+	require.ErrorIs(h.startRecording(path), ErrAlreadyRecording)
+	h.record(false, message.ChitsOp, []byte{1, 2})
+	h.record(true, message.PingOp, nil)
+	h.record(false, message.GetOp, []byte{3})
+	require.NoError(h.stopRecording())
+
+	// not recorded
+	h.record(false, message.ChitsOp, []byte{4})
+
+	msgs, err := readRecordedMessages(path)
+	require.NoError(err)
+	require.Len(msgs, 3)
+	require.False(msgs[0].Inbound)
+	require.Equal(uint32(message.ChitsOp), msgs[0].Op)
+	require.Equal([]byte{1, 2}, msgs[0].Bytes)
+	require.True(msgs[1].Inbound)
+	require.Equal(uint32(message.PingOp), msgs[1].Op)
+	require.Equal(uint32(message.GetOp), msgs[2].Op)
+	require.False(msgs[2].Time.Before(msgs[0].Time))
+
+	_, err = readRecordedMessages(filepath.Join(t.TempDir(), "missing.jsonl"))
+	require.Error(err)
+}
+
+func TestPeerMessageSubscription(t *testing.T) {
+	require := require.New(t)
+
+	h := &peerMessageHandler{handler: &noOpInboundHandler{}}
+	msgs1, unsubscribe1 := h.subscribe()
+	msgs2, unsubscribe2 := h.subscribe()
+
+	msg := node.PeerMessage{Op: uint32(message.ChitsOp), ChainID: ids.GenerateTestID(), Bytes: []byte{1}}
+	h.publish(msg)
+	require.Equal(msg, <-msgs1)
+	require.Equal(msg, <-msgs2)
+
+	unsubscribe1()
+	_, ok := <-msgs1
+	require.False(ok)
+	// no-op when called again
+	unsubscribe1()
+
+	// messages are dropped when the channel is full
+	for i := 0; i < peerMessageSubscriptionBufferSize+1; i++ {
+		h.publish(msg)
+	}
+	require.Len(msgs2, peerMessageSubscriptionBufferSize)
+
+	h.closeSubscriptions()
+	numReceived := 0
+	for range msgs2 {
+		numReceived++
+	}
+	require.Equal(peerMessageSubscriptionBufferSize, numReceived)
+	// no-op after the subscriptions are closed
+	unsubscribe2()
+}
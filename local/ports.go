@@ -0,0 +1,121 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"syscall"
+
+	"github.com/luxdefi/netrunner/network"
+)
+
+var errPortReserved = errors.New("port already reserved")
+
+// The allocator is shared by all the networks of this process, so
+// concurrent networks never get the same port.
+var ports = newPortAllocator()
+
+// portAllocator assigns ports to nodes. Assigned ports are reserved by keeping
+// them bound until the node process is about to start, so other processes,
+// and other networks, can't take them in between.
+type portAllocator struct {
+	lock sync.Mutex
+	// port -> listener keeping it bound
+	reserved map[uint16]net.Listener
+}
+
+func newPortAllocator() *portAllocator {
+	return &portAllocator{
+		reserved: map[uint16]net.Listener{},
+	}
+}
+
+// Reserves [port], failing if it is already reserved or used.
+func (pa *portAllocator) reserve(port uint16) error {
+	pa.lock.Lock()
+	defer pa.lock.Unlock()
+
+	if _, ok := pa.reserved[port]; ok {
+		return fmt.Errorf("%w: %d", errPortReserved, port)
+	}
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return err
+	}
+	pa.reserved[port] = l
+	return nil
+}
+
+// Reserves a random free port in [portRange], picked with [rng], or with
+// the global source if nil. Ports found reserved or in use are retried
+// until [netListenTimeout] elapses.
+func (pa *portAllocator) reserveFree(portRange network.PortRange, rng *rand.Rand) (uint16, error) {
+	if err := portRange.Validate(); err != nil {
+		return 0, err
+	}
+	minPort, maxPort := portRange.Bounds()
+	ctx, cancel := context.WithTimeout(context.Background(), netListenTimeout)
+	defer cancel()
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("no free port in range [%d, %d]: %w", minPort, maxPort, ctx.Err())
+		default:
+		}
+		var n int
+		if rng != nil {
+			n = rng.Intn(int(maxPort) - int(minPort) + 1)
+		} else {
+			n = rand.Intn(int(maxPort) - int(minPort) + 1) //nolint
+		}
+		port := uint16(n + int(minPort))
+		err := pa.reserve(port)
+		if err == nil {
+			return port, nil
+		}
+		if !isPortInUse(err) {
+			return 0, err
+		}
+	}
+}
+
+// Releases the reservation of [port], so the node process can bind it.
+// Releasing a port that is not reserved has no effect.
+func (pa *portAllocator) release(port uint16) {
+	pa.lock.Lock()
+	defer pa.lock.Unlock()
+
+	if l, ok := pa.reserved[port]; ok {
+		_ = l.Close()
+		delete(pa.reserved, port)
+	}
+}
+
+// Returns true if [port] is taken, either by another process or by a
+// reservation of this process.
+func (pa *portAllocator) inUse(port uint16) bool {
+	err := pa.reserve(port)
+	if err == nil {
+		pa.release(port)
+	}
+	return isPortInUse(err)
+}
+
+// nodePortsTakenError is returned by the health wait when a node exited
+// because another process took one of its ports.
+type nodePortsTakenError struct {
+	nodeName string
+}
+
+func (e *nodePortsTakenError) Error() string {
+	return fmt.Sprintf("node %q stopped as its ports were taken by another process", e.nodeName)
+}
+
+// Returns true if [err] means the port is already taken, either by
+// another process or by a reservation of this process.
+func isPortInUse(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE) || errors.Is(err, errPortReserved)
+}
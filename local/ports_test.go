@@ -0,0 +1,45 @@
+package local
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortAllocator(t *testing.T) {
+	require := require.New(t)
+
+	pa := newPortAllocator()
+	portRange := network.PortRange{Min: 30000, Max: 30100}
+
+	port, err := pa.reserveFree(portRange, nil)
+	require.NoError(err)
+	require.GreaterOrEqual(port, portRange.Min)
+	require.LessOrEqual(port, portRange.Max)
+
+	// reserved ports can't be taken by other processes, nor reserved again
+	_, err = net.Listen("tcp", ":"+strconv.Itoa(int(port)))
+	require.Error(err)
+	err = pa.reserve(port)
+	require.ErrorIs(err, errPortReserved)
+	require.True(isPortInUse(err))
+
+	// released ports can be bound by the node
+	pa.release(port)
+	l, err := net.Listen("tcp", ":"+strconv.Itoa(int(port)))
+	require.NoError(err)
+
+	// ports in use by other processes can't be reserved
+	err = pa.reserve(port)
+	require.True(isPortInUse(err))
+	require.True(pa.inUse(port))
+	require.NoError(l.Close())
+
+	// checking a free port doesn't keep it reserved
+	require.False(pa.inUse(port))
+	require.NoError(pa.reserve(port))
+	pa.release(port)
+}
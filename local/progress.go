@@ -0,0 +1,20 @@
+package local
+
+import "github.com/luxdefi/netrunner/network"
+
+// Reports to [ln.onProgress], if set, that [done] of the [total] steps of
+// [phase] are done, the last one being described by [msg].
+func (ln *localNetwork) reportProgress(phase string, done int, total int, msg string) {
+	if ln.onProgress == nil {
+		return
+	}
+	percent := float64(100)
+	if total > 0 {
+		percent = float64(done) * 100 / float64(total)
+	}
+	ln.onProgress(network.ProgressEvent{
+		Phase:   phase,
+		Percent: percent,
+		Message: msg,
+	})
+}
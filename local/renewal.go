@@ -0,0 +1,515 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/luxdefi/node/utils/set"
+	"github.com/luxdefi/node/vms/platformvm"
+	"github.com/luxdefi/node/vms/platformvm/reward"
+	"github.com/luxdefi/node/vms/platformvm/signer"
+	"github.com/luxdefi/node/vms/platformvm/txs"
+	"github.com/luxdefi/node/vms/secp256k1fx"
+	"github.com/luxdefi/node/wallet/subnet/primary/common"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+// time taken to issue the renewal of a validation once it ends, on top of
+// the validation start offset
+const renewalIssueMargin = time.Minute
+
+// primary network validation renewed once it ends, along with the subnet
+// validations of its node, which end with it
+type validationRenewal struct {
+	nodeName string
+	nodeID   ids.NodeID
+	// current validation
+	txID    ids.ID
+	endTime time.Time
+	weight  uint64
+	// subnet validations of the node, re-added after the primary one
+	subnetValidations []subnetValidationRenewal
+}
+
+// subnet validation re-added once the primary network validation of
+// its node is renewed
+type subnetValidationRenewal struct {
+	subnetID ids.ID
+	// asset staked on the subnet if it is elastic, empty otherwise
+	assetID  ids.ID
+	weight   uint64
+	duration time.Duration
+}
+
+// end time and weight of a current primary network validation
+type validationWindow struct {
+	nodeID  ids.NodeID
+	endTime time.Time
+	weight  uint64
+}
+
+// RenewPrimaryValidations checks the primary network validations of the
+// nodes of [renewSpec] can be renewed, marks the nodes as being renewed, and
+// returns the validations expected to be added along with the function
+// renewing them. The function waits for the validations to end, and adds
+// each node again as validator as soon as its validation ends, along with
+// its subnet validations, so long lived networks keep their validators.
+// The P-Chain doesn't accept a new validation of a node before its current
+// one ends, so that is the earliest a validation can be renewed. The renewals
+// are refused if the validations end so close to each other that less than a
+// quorum of the primary network weight would keep validating meanwhile.
+// The network lock is not held while waiting, as validation periods can be long
+func (ln *localNetwork) RenewPrimaryValidations(
+	ctx context.Context,
+	renewSpec network.RenewValidationSpec,
+) ([]network.AddedPermissionlessValidator, network.RenewFunc, error) {
+	cctx, cancel := ln.cancelOnStop(ctx)
+	renewals, clientURI, err := ln.getValidationRenewals(cctx, renewSpec)
+	cancel()
+	if err != nil {
+		return nil, nil, err
+	}
+	nodeNames := make([]string, len(renewals))
+	expected := []network.AddedPermissionlessValidator{}
+	for i, renewal := range renewals {
+		nodeNames[i] = renewal.nodeName
+		startTime := renewal.endTime.Add(ln.validationStartOffset())
+		expected = append(expected, renewal.getRenewedValidations(startTime, renewSpec, ln.validationDuration())...)
+	}
+	// marked before returning, so a concurrent renewal of the nodes fails
+	// for its caller instead of in the background
+	if err := ln.startRenewing(nodeNames); err != nil {
+		return nil, nil, err
+	}
+	renew := func(ctx context.Context) ([]network.AddedPermissionlessValidator, error) {
+		defer ln.stopRenewing(nodeNames)
+		return ln.renewPrimaryValidations(ctx, renewals, clientURI, renewSpec)
+	}
+	return expected, renew, nil
+}
+
+// Waits for the validations of [renewals] to end, renewing each one as
+// soon as it ends. Returns the new validations
+func (ln *localNetwork) renewPrimaryValidations(
+	ctx context.Context,
+	renewals []*validationRenewal,
+	clientURI string,
+	renewSpec network.RenewValidationSpec,
+) ([]network.AddedPermissionlessValidator, error) {
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	nodeNames := make([]string, len(renewals))
+	pending := make(map[ids.NodeID]*validationRenewal, len(renewals))
+	for i, renewal := range renewals {
+		nodeNames[i] = renewal.nodeName
+		pending[renewal.nodeID] = renewal
+	}
+	platformCli := platformvm.NewClient(clientURI)
+
+	ln.log.Info(logging.Green.Wrap("waiting for primary network validations to end to renew them"),
+		zap.Strings("node-names", nodeNames),
+	)
+	renewed := []network.AddedPermissionlessValidator{}
+	for len(pending) > 0 {
+		ended, err := getEndedValidations(ctx, platformCli, pending)
+		if err != nil {
+			return nil, err
+		}
+		for _, renewal := range ended {
+			added, err := ln.renewValidation(ctx, renewal, renewSpec)
+			if err != nil {
+				return nil, err
+			}
+			renewed = append(renewed, added...)
+			delete(pending, renewal.nodeID)
+		}
+		if len(ended) > 0 {
+			continue
+		}
+		select {
+		case <-ln.onStopCh:
+			return nil, errAborted
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(waitForValidatorsPullFrequency):
+		}
+	}
+	return renewed, nil
+}
+
+// Marks the primary network validations of [nodeNames] as being renewed,
+// failing if any of them already is.
+func (ln *localNetwork) startRenewing(nodeNames []string) error {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	for _, nodeName := range nodeNames {
+		if ln.renewingNodes.Contains(nodeName) {
+			return fmt.Errorf("%w for node %s", network.ErrRenewalInProgress, nodeName)
+		}
+	}
+	ln.renewingNodes.Add(nodeNames...)
+	return nil
+}
+
+func (ln *localNetwork) stopRenewing(nodeNames []string) {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	ln.renewingNodes.Remove(nodeNames...)
+}
+
+// Returns the renewals of the primary network validations of the nodes of
+// [renewSpec], sorted by end time, and the URI of the node queried for them.
+// Fails if any node isn't a current validator, or if a quorum of the
+// primary network weight wouldn't keep validating while they are renewed.
+func (ln *localNetwork) getValidationRenewals(
+	ctx context.Context,
+	renewSpec network.RenewValidationSpec,
+) ([]*validationRenewal, string, error) {
+	ln.lock.RLock()
+	nodeNames := renewSpec.NodeNames
+	if len(nodeNames) == 0 {
+		nodeNames = maps.Keys(ln.nodes)
+		sort.Strings(nodeNames)
+	}
+	renewals := make(map[ids.NodeID]*validationRenewal, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		node, ok := ln.nodes[nodeName]
+		if !ok {
+			ln.lock.RUnlock()
+			return nil, "", fmt.Errorf("%w: %s", network.ErrNodeNotFound, nodeName)
+		}
+		if ln.renewingNodes.Contains(nodeName) {
+			ln.lock.RUnlock()
+			return nil, "", fmt.Errorf("%w for node %s", network.ErrRenewalInProgress, nodeName)
+		}
+		renewals[node.GetNodeID()] = &validationRenewal{
+			nodeName: nodeName,
+			nodeID:   node.GetNodeID(),
+		}
+	}
+	trackedSubnets, err := ln.getTrackedSubnets()
+	if err != nil {
+		ln.lock.RUnlock()
+		return nil, "", err
+	}
+	elasticSubnetIDs := maps.Clone(ln.subnetID2ElasticSubnetID)
+	clientURI, err := ln.getClientURI()
+	ln.lock.RUnlock()
+	if err != nil {
+		return nil, "", err
+	}
+	platformCli := platformvm.NewClient(clientURI)
+
+	cctx, cancel := createDefaultCtx(ctx)
+	vdrs, err := platformCli.GetCurrentValidators(cctx, constants.PrimaryNetworkID, nil)
+	cancel()
+	if err != nil {
+		return nil, "", err
+	}
+	current := make([]validationWindow, len(vdrs))
+	for i, vdr := range vdrs {
+		current[i] = validationWindow{
+			nodeID:  vdr.NodeID,
+			endTime: time.Unix(int64(vdr.EndTime), 0),
+			weight:  vdr.Weight,
+		}
+		if renewal, ok := renewals[vdr.NodeID]; ok {
+			renewal.txID = vdr.TxID
+			renewal.endTime = current[i].endTime
+			renewal.weight = vdr.Weight
+		}
+	}
+	for _, renewal := range renewals {
+		if renewal.txID == ids.Empty {
+			return nil, "", fmt.Errorf("node %s is not a current primary network validator", renewal.nodeName)
+		}
+	}
+
+	// the subnet validations can't outlast the primary one, so they
+	// end with it and are renewed along
+	nodeIDs := maps.Keys(renewals)
+	for _, subnetIDStr := range strings.Split(trackedSubnets, ",") {
+		if subnetIDStr == "" {
+			continue
+		}
+		subnetID, err := ids.FromString(subnetIDStr)
+		if err != nil {
+			return nil, "", err
+		}
+		var assetID ids.ID
+		if elasticSubnetID, ok := elasticSubnetIDs[subnetID]; ok {
+			transformTx, err := getTransformSubnetTx(ctx, platformCli, elasticSubnetID)
+			if err != nil {
+				return nil, "", err
+			}
+			assetID = transformTx.AssetID
+		}
+		cctx, cancel := createDefaultCtx(ctx)
+		subnetVdrs, err := platformCli.GetCurrentValidators(cctx, subnetID, nodeIDs)
+		cancel()
+		if err != nil {
+			return nil, "", err
+		}
+		for _, vdr := range subnetVdrs {
+			renewal, ok := renewals[vdr.NodeID]
+			if !ok {
+				continue
+			}
+			renewal.subnetValidations = append(renewal.subnetValidations, subnetValidationRenewal{
+				subnetID: subnetID,
+				assetID:  assetID,
+				weight:   vdr.Weight,
+				duration: time.Duration(vdr.EndTime-vdr.StartTime) * time.Second,
+			})
+		}
+	}
+
+	renewed := set.Set[ids.NodeID]{}
+	renewed.Add(nodeIDs...)
+	if err := checkRenewalQuorum(current, renewed, ln.validationStartOffset()+renewalIssueMargin); err != nil {
+		return nil, "", err
+	}
+	return sortRenewals(maps.Values(renewals)), clientURI, nil
+}
+
+// Checks that, while each of the [renewed] validations is ended and not
+// renewed yet, which takes up to [gap] after it ends, more than 2/3 of the
+// weight of the [current] primary network validations keeps validating.
+// The other renewed validations that end meanwhile, and the ones not renewed
+// that ended before, don't count as validating.
+func checkRenewalQuorum(current []validationWindow, renewed set.Set[ids.NodeID], gap time.Duration) error {
+	totalWeight := uint64(0)
+	for _, validation := range current {
+		totalWeight += validation.weight
+	}
+	for _, validation := range current {
+		if !renewed.Contains(validation.nodeID) {
+			continue
+		}
+		gapStart, gapEnd := validation.endTime, validation.endTime.Add(gap)
+		endedWeight := uint64(0)
+		for _, other := range current {
+			ended := other.endTime.Before(gapEnd)
+			if renewed.Contains(other.nodeID) {
+				// back once its own gap is over
+				ended = ended && other.endTime.Add(gap).After(gapStart)
+			}
+			if ended {
+				endedWeight += other.weight
+			}
+		}
+		if 3*(totalWeight-endedWeight) <= 2*totalWeight {
+			return fmt.Errorf(
+				"%w: validations of %d of %d nLUX end around %s, the renewals must be staggered",
+				network.ErrRenewalBelowQuorum,
+				endedWeight,
+				totalWeight,
+				gapStart.UTC().Format(time.RFC3339),
+			)
+		}
+	}
+	return nil
+}
+
+// Returns the validations renewing [renewal] from [startTime] on: the
+// primary network one first, followed by the subnet ones, which can't
+// outlast it.
+func (renewal *validationRenewal) getRenewedValidations(
+	startTime time.Time,
+	renewSpec network.RenewValidationSpec,
+	defaultDuration time.Duration,
+) []network.AddedPermissionlessValidator {
+	// the P-Chain works with second precision
+	startTime = startTime.Truncate(time.Second)
+	duration := renewSpec.Duration
+	if duration == 0 {
+		duration = defaultDuration
+	}
+	weight := renewSpec.Amount
+	if weight == 0 {
+		weight = renewal.weight
+	}
+	endTime := startTime.Add(duration)
+	validations := []network.AddedPermissionlessValidator{
+		{
+			NodeName:  renewal.nodeName,
+			SubnetID:  constants.PrimaryNetworkID,
+			StartTime: startTime,
+			EndTime:   endTime,
+			Weight:    weight,
+		},
+	}
+	for _, subnetValidation := range renewal.subnetValidations {
+		subnetEndTime := startTime.Add(subnetValidation.duration)
+		if subnetEndTime.After(endTime) {
+			subnetEndTime = endTime
+		}
+		validations = append(validations, network.AddedPermissionlessValidator{
+			NodeName:  renewal.nodeName,
+			SubnetID:  subnetValidation.subnetID,
+			StartTime: startTime,
+			EndTime:   subnetEndTime,
+			Weight:    subnetValidation.weight,
+		})
+	}
+	return validations
+}
+
+// sorts [renewals] by end time, and then by node name
+func sortRenewals(renewals []*validationRenewal) []*validationRenewal {
+	sort.Slice(renewals, func(i, j int) bool {
+		if !renewals[i].endTime.Equal(renewals[j].endTime) {
+			return renewals[i].endTime.Before(renewals[j].endTime)
+		}
+		return renewals[i].nodeName < renewals[j].nodeName
+	})
+	return renewals
+}
+
+// returns the validations of [pending] that are past their end time and
+// are no longer current, sorted by end time
+func getEndedValidations(
+	ctx context.Context,
+	platformCli platformvm.Client,
+	pending map[ids.NodeID]*validationRenewal,
+) ([]*validationRenewal, error) {
+	now := time.Now()
+	nodeIDs := []ids.NodeID{}
+	for nodeID, renewal := range pending {
+		if now.After(renewal.endTime) {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+	if len(nodeIDs) == 0 {
+		return nil, nil
+	}
+	cctx, cancel := createDefaultCtx(ctx)
+	vdrs, err := platformCli.GetCurrentValidators(cctx, constants.PrimaryNetworkID, nodeIDs)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	currentTxIDs := make(map[ids.NodeID]ids.ID, len(vdrs))
+	for _, vdr := range vdrs {
+		currentTxIDs[vdr.NodeID] = vdr.TxID
+	}
+	ended := []*validationRenewal{}
+	for _, nodeID := range nodeIDs {
+		renewal := pending[nodeID]
+		if currentTxIDs[nodeID] != renewal.txID {
+			ended = append(ended, renewal)
+		}
+	}
+	return sortRenewals(ended), nil
+}
+
+// adds the node of the ended [renewal] validation again as primary network
+// validator, and then as validator of its subnets, staking funds of the
+// network key
+func (ln *localNetwork) renewValidation(
+	ctx context.Context,
+	renewal *validationRenewal,
+	renewSpec network.RenewValidationSpec,
+) ([]network.AddedPermissionlessValidator, error) {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	node, ok := ln.nodes[renewal.nodeName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", network.ErrNodeNotFound, renewal.nodeName)
+	}
+	proofOfPossession, err := getProofOfPossession(node)
+	if err != nil {
+		return nil, err
+	}
+	clientURI, err := ln.getClientURI()
+	if err != nil {
+		return nil, err
+	}
+	// the wallet needs the txs of the subnets to add their validators
+	preloadTXs := make([]ids.ID, len(renewal.subnetValidations))
+	for i, subnetValidation := range renewal.subnetValidations {
+		preloadTXs[i] = subnetValidation.subnetID
+	}
+	// the wallet is created once the validation ended, so it sees the returned stake
+	w, err := newWallet(ctx, clientURI, preloadTXs, ln.txSigner)
+	if err != nil {
+		return nil, err
+	}
+	rewardsOwner, err := getSpecOwner(w, nil, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	validations := renewal.getRenewedValidations(time.Now().Add(ln.validationStartOffset()), renewSpec, ln.validationDuration())
+	for i := range validations {
+		validation := &validations[i]
+		validator := &txs.SubnetValidator{
+			Validator: txs.Validator{
+				NodeID: renewal.nodeID,
+				Start:  uint64(validation.StartTime.Unix()),
+				End:    uint64(validation.EndTime.Unix()),
+				Wght:   validation.Weight,
+			},
+			Subnet: validation.SubnetID,
+		}
+		cctx, cancel := createDefaultCtx(ctx)
+		switch {
+		case i == 0:
+			validation.TxID, err = w.pWallet.IssueAddPermissionlessValidatorTx(
+				validator,
+				proofOfPossession,
+				w.pWallet.LUXAssetID(),
+				rewardsOwner,
+				rewardsOwner,
+				10*10000, // 10% fee percent, times 10000 to make it as shares
+				common.WithContext(cctx),
+				defaultPoll,
+			)
+		case renewal.subnetValidations[i-1].assetID == ids.Empty:
+			validation.TxID, err = w.pWallet.IssueAddSubnetValidatorTx(
+				validator,
+				common.WithContext(cctx),
+				defaultPoll,
+			)
+		default:
+			validation.TxID, err = w.pWallet.IssueAddPermissionlessValidatorTx(
+				validator,
+				&signer.Empty{},
+				renewal.subnetValidations[i-1].assetID,
+				rewardsOwner,
+				&secp256k1fx.OutputOwners{},
+				reward.PercentDenominator,
+				common.WithContext(cctx),
+				defaultPoll,
+			)
+		}
+		cancel()
+		if err != nil {
+			return nil, newTxError(fmt.Errorf("P-Wallet Tx Error renewing validation %w, node ID %s, subnet ID %s", err, renewal.nodeID, validation.SubnetID))
+		}
+		ln.log.Info("renewed validation",
+			zap.String("node-name", renewal.nodeName),
+			zap.String("node-ID", renewal.nodeID.String()),
+			zap.String("subnet-ID", validation.SubnetID.String()),
+			zap.Uint64("weight", validation.Weight),
+			zap.Time("end-time", validation.EndTime),
+			zap.String("tx-ID", validation.TxID.String()),
+		)
+	}
+	return validations, nil
+}
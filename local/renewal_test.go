@@ -0,0 +1,142 @@
+package local
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/set"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRenewalQuorum(t *testing.T) {
+	now := time.Now()
+	gap := 2 * time.Minute
+	nodeIDs := []ids.NodeID{ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()}
+
+	tests := []struct {
+		name      string
+		endTimes  []time.Duration
+		renewed   []int
+		expectErr bool
+	}{
+		{
+			name:      "all ending together",
+			endTimes:  []time.Duration{time.Hour, time.Hour, time.Hour, time.Hour},
+			renewed:   []int{0, 1, 2, 3},
+			expectErr: true,
+		},
+		{
+			name:     "one ending with the others later",
+			endTimes: []time.Duration{time.Hour, 2 * time.Hour, 2 * time.Hour, 2 * time.Hour},
+			renewed:  []int{0},
+		},
+		{
+			name:     "staggered",
+			endTimes: []time.Duration{time.Hour, 2 * time.Hour, 3 * time.Hour, 4 * time.Hour},
+			renewed:  []int{0, 1, 2, 3},
+		},
+		{
+			name:      "two ending within the gap",
+			endTimes:  []time.Duration{time.Hour, time.Hour + time.Minute, 3 * time.Hour, 4 * time.Hour},
+			renewed:   []int{0, 1, 2, 3},
+			expectErr: true,
+		},
+		{
+			name:      "not renewed ones ending before",
+			endTimes:  []time.Duration{time.Hour, time.Hour, 3 * time.Hour, 4 * time.Hour},
+			renewed:   []int{2},
+			expectErr: true,
+		},
+		{
+			name:     "not renewed ones ending after",
+			endTimes: []time.Duration{time.Hour, 3 * time.Hour, 3 * time.Hour, 4 * time.Hour},
+			renewed:  []int{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := make([]validationWindow, len(tt.endTimes))
+			for i, endTime := range tt.endTimes {
+				current[i] = validationWindow{
+					nodeID:  nodeIDs[i],
+					endTime: now.Add(endTime),
+					weight:  100,
+				}
+			}
+			renewed := set.Set[ids.NodeID]{}
+			for _, i := range tt.renewed {
+				renewed.Add(nodeIDs[i])
+			}
+			err := checkRenewalQuorum(current, renewed, gap)
+			if tt.expectErr {
+				require.ErrorIs(t, err, network.ErrRenewalBelowQuorum)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetRenewedValidations(t *testing.T) {
+	require := require.New(t)
+
+	subnetID := ids.GenerateTestID()
+	renewal := &validationRenewal{
+		nodeName: "node1",
+		weight:   2000,
+		subnetValidations: []subnetValidationRenewal{
+			{
+				subnetID: subnetID,
+				weight:   10,
+				duration: 48 * time.Hour,
+			},
+		},
+	}
+	startTime := time.Unix(1_000_000, 500)
+
+	// defaults to the current weight and the network duration, and the
+	// subnet validation doesn't outlast the primary one
+	validations := renewal.getRenewedValidations(startTime, network.RenewValidationSpec{}, 24*time.Hour)
+	require.Equal([]network.AddedPermissionlessValidator{
+		{
+			NodeName:  "node1",
+			SubnetID:  constants.PrimaryNetworkID,
+			StartTime: time.Unix(1_000_000, 0),
+			EndTime:   time.Unix(1_000_000, 0).Add(24 * time.Hour),
+			Weight:    2000,
+		},
+		{
+			NodeName:  "node1",
+			SubnetID:  subnetID,
+			StartTime: time.Unix(1_000_000, 0),
+			EndTime:   time.Unix(1_000_000, 0).Add(24 * time.Hour),
+			Weight:    10,
+		},
+	}, validations)
+
+	validations = renewal.getRenewedValidations(startTime, network.RenewValidationSpec{Amount: 3000, Duration: 72 * time.Hour}, 24*time.Hour)
+	require.Len(validations, 2)
+	require.Equal(uint64(3000), validations[0].Weight)
+	require.Equal(time.Unix(1_000_000, 0).Add(72*time.Hour), validations[0].EndTime)
+	require.Equal(uint64(10), validations[1].Weight)
+	require.Equal(time.Unix(1_000_000, 0).Add(48*time.Hour), validations[1].EndTime)
+}
+
+func TestSortRenewals(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	renewals := sortRenewals([]*validationRenewal{
+		{nodeName: "node3", endTime: now.Add(time.Hour)},
+		{nodeName: "node2", endTime: now},
+		{nodeName: "node1", endTime: now.Add(time.Hour)},
+	})
+	nodeNames := []string{}
+	for _, renewal := range renewals {
+		nodeNames = append(nodeNames, renewal.nodeName)
+	}
+	require.Equal([]string{"node2", "node1", "node3"}, nodeNames)
+}
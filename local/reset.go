@@ -0,0 +1,77 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"syscall"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/logging"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+// ResetNetwork stops all the nodes, removes their dbs and restarts them with
+// the same configs, so the network starts again from genesis. The nodes keep
+// their staking keys, ports, configs and tracked subnets, but the subnets and
+// blockchains created after genesis are gone. Paused nodes are restarted too.
+func (ln *localNetwork) ResetNetwork(ctx context.Context) error {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	if ln.stopCalled() {
+		return network.ErrStopped
+	}
+
+	ln.log.Info(logging.Blue.Wrap(logging.Bold.Wrap("resetting network to genesis")))
+
+	// restarted in the original order, so the beacons are the same
+	nodeNames := maps.Keys(ln.nodes)
+	sort.Slice(nodeNames, func(i, j int) bool {
+		if len(nodeNames[i]) != len(nodeNames[j]) {
+			return len(nodeNames[i]) < len(nodeNames[j])
+		}
+		return nodeNames[i] < nodeNames[j]
+	})
+	nodeConfigs := make([]node.Config, 0, len(nodeNames))
+	dbDirs := make([]string, 0, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		n := ln.nodes[nodeName]
+		nodeConfigs = append(nodeConfigs, getRestartConfig(n))
+		dbDirs = append(dbDirs, n.GetDbDir())
+		if err := ln.removeNode(ctx, nodeName); err != nil {
+			return err
+		}
+	}
+	syscall.Sync()
+
+	for _, dbDir := range dbDirs {
+		ln.log.Debug("removing node db", zap.String("db-dir", dbDir))
+		if err := os.RemoveAll(dbDir); err != nil {
+			return fmt.Errorf("couldn't remove node db dir %q: %w", dbDir, err)
+		}
+	}
+
+	// the blockchains and elastic subnets are gone, but the aliases are
+	// kept as part of the node configs
+	ln.subnetID2ElasticSubnetID = map[ids.ID]ids.ID{}
+	ln.blockchains = nil
+	ln.reloadedBlockchains = nil
+	if err := ln.persistNetworkState(); err != nil {
+		return err
+	}
+
+	for _, nodeConfig := range nodeConfigs {
+		if _, err := ln.addNode(nodeConfig); err != nil {
+			return err
+		}
+	}
+	return ln.healthy(ctx)
+}
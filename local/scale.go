@@ -0,0 +1,138 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/node/config"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/luxdefi/node/utils/set"
+	"github.com/luxdefi/node/vms/platformvm"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+// ScaleNetwork adds or removes nodes so the network has [numNodes] nodes.
+// Added nodes track all the subnets tracked by the current nodes, and are
+// registered as primary network validators. Removed nodes are taken from
+// the last added non beacon nodes first.
+func (ln *localNetwork) ScaleNetwork(ctx context.Context, numNodes uint32) error {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	if ln.stopCalled() {
+		return network.ErrStopped
+	}
+	if numNodes == 0 {
+		return fmt.Errorf("can't scale network to 0 nodes")
+	}
+
+	switch current := uint32(len(ln.nodes)); {
+	case numNodes < current:
+		return ln.scaleDown(ctx, int(current-numNodes))
+	case numNodes > current:
+		return ln.scaleUp(ctx, int(numNodes-current))
+	default:
+		return nil
+	}
+}
+
+// Assumes [ln.lock] is held.
+func (ln *localNetwork) scaleDown(ctx context.Context, numRemoved int) error {
+	ln.log.Info(logging.Blue.Wrap(logging.Bold.Wrap(fmt.Sprintf("removing %d nodes", numRemoved))))
+
+	// last added nodes first, keeping the beacons as long as possible
+	nodeNames := maps.Keys(ln.nodes)
+	sort.Slice(nodeNames, func(i, j int) bool {
+		iBeacon := ln.nodes[nodeNames[i]].GetConfig().IsBeacon
+		jBeacon := ln.nodes[nodeNames[j]].GetConfig().IsBeacon
+		if iBeacon != jBeacon {
+			return jBeacon
+		}
+		if len(nodeNames[i]) != len(nodeNames[j]) {
+			return len(nodeNames[i]) > len(nodeNames[j])
+		}
+		return nodeNames[i] > nodeNames[j]
+	})
+	for _, nodeName := range nodeNames[:numRemoved] {
+		if err := ln.removeNode(ctx, nodeName); err != nil {
+			return err
+		}
+	}
+	return ln.healthy(ctx)
+}
+
+// Assumes [ln.lock] is held.
+func (ln *localNetwork) scaleUp(ctx context.Context, numAdded int) error {
+	ln.log.Info(logging.Blue.Wrap(logging.Bold.Wrap(fmt.Sprintf("adding %d nodes", numAdded))))
+
+	if err := checkFileLimit(ln.log, len(ln.nodes)+numAdded); err != nil {
+		return err
+	}
+	trackSubnets, err := ln.getTrackedSubnets()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < numAdded; i++ {
+		nodeConfig := node.Config{
+			Flags: map[string]interface{}{},
+		}
+		if trackSubnets != "" {
+			nodeConfig.Flags[config.TrackSubnetsKey] = trackSubnets
+		}
+		n, err := ln.addNode(nodeConfig)
+		if err != nil {
+			return err
+		}
+		ln.log.Info("added node", zap.String("node-name", n.GetName()), zap.String("track-subnets", trackSubnets))
+	}
+	if err := ln.healthy(ctx); err != nil {
+		return err
+	}
+
+	clientURI, err := ln.getClientURI()
+	if err != nil {
+		return err
+	}
+	platformCli := platformvm.NewClient(clientURI)
+	w, err := newWallet(ctx, clientURI, nil, ln.txSigner)
+	if err != nil {
+		return err
+	}
+	if err := ln.addPrimaryValidators(ctx, platformCli, w, nil); err != nil {
+		return err
+	}
+	return ln.waitPrimaryValidators(ctx, platformCli, nil)
+}
+
+// Returns the comma separated IDs of all subnets tracked by the nodes.
+// Assumes [ln.lock] is held.
+func (ln *localNetwork) getTrackedSubnets() (string, error) {
+	subnetIDs := set.Set[string]{}
+	for nodeName, n := range ln.nodes {
+		// flags updated on subnet changes take precedence over the config file
+		tracked, ok := n.GetConfig().Flags[config.TrackSubnetsKey].(string)
+		if !ok {
+			var err error
+			tracked, err = n.GetFlag(config.TrackSubnetsKey)
+			if err != nil {
+				return "", fmt.Errorf("couldn't get tracked subnets of node %q: %w", nodeName, err)
+			}
+		}
+		for _, subnetID := range strings.Split(tracked, ",") {
+			if subnetID != "" {
+				subnetIDs.Add(subnetID)
+			}
+		}
+	}
+	trackSubnets := subnetIDs.List()
+	sort.Strings(trackSubnets)
+	return strings.Join(trackSubnets, ","), nil
+}
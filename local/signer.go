@@ -0,0 +1,55 @@
+package local
+
+import (
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/crypto/keychain"
+	"github.com/luxdefi/node/utils/hashing"
+	"github.com/luxdefi/node/utils/set"
+)
+
+var (
+	_ keychain.Keychain = (*txSignerKeychain)(nil)
+	_ keychain.Signer   = (*txSignerKey)(nil)
+)
+
+// Keychain of the single key of a network.TxSigner, so the wallet
+// signs with it instead of with an in-memory key.
+type txSignerKeychain struct {
+	key *txSignerKey
+}
+
+func newTxSignerKeychain(txSigner network.TxSigner) *txSignerKeychain {
+	return &txSignerKeychain{
+		key: &txSignerKey{txSigner: txSigner},
+	}
+}
+
+func (kc *txSignerKeychain) Get(addr ids.ShortID) (keychain.Signer, bool) {
+	if addr != kc.key.Address() {
+		return nil, false
+	}
+	return kc.key, true
+}
+
+func (kc *txSignerKeychain) Addresses() set.Set[ids.ShortID] {
+	addrs := set.NewSet[ids.ShortID](1)
+	addrs.Add(kc.key.Address())
+	return addrs
+}
+
+type txSignerKey struct {
+	txSigner network.TxSigner
+}
+
+func (k *txSignerKey) Address() ids.ShortID {
+	return k.txSigner.Address()
+}
+
+func (k *txSignerKey) SignHash(hash []byte) ([]byte, error) {
+	return k.txSigner.SignHash(hash)
+}
+
+func (k *txSignerKey) Sign(msg []byte) ([]byte, error) {
+	return k.txSigner.SignHash(hashing.ComputeHash256(msg))
+}
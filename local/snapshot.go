@@ -15,7 +15,6 @@ import (
 	"github.com/luxdefi/netrunner/network/node"
 	"github.com/luxdefi/netrunner/utils"
 	"github.com/luxdefi/node/config"
-	"github.com/luxdefi/node/ids"
 	"github.com/luxdefi/node/utils/constants"
 	"github.com/luxdefi/node/utils/logging"
 	dircopy "github.com/otiai10/copy"
@@ -27,12 +26,6 @@ const (
 	deprecatedWhitelistedSubnetsKey = "whitelisted-subnets"
 )
 
-// NetworkState defines dynamic network information not available on blockchain db
-type NetworkState struct {
-	// Map from subnet id to elastic subnet tx id
-	SubnetID2ElasticSubnetID map[string]string `json:"subnetID2ElasticSubnetID"`
-}
-
 // snapshots generated using older ANR versions may contain deprecated luxd flags
 func fixDeprecatedLuxdFlags(flags map[string]interface{}) error {
 	if vIntf, ok := flags[deprecatedWhitelistedSubnetsKey]; ok {
@@ -71,6 +64,7 @@ func NewNetworkFromSnapshot(
 	subnetConfigs map[string]string,
 	flags map[string]interface{},
 	reassignPortsIfUsed bool,
+	snapshotPassphrase string,
 ) (network.Network, error) {
 	net, err := newNetwork(
 		log,
@@ -97,6 +91,7 @@ func NewNetworkFromSnapshot(
 		upgradeConfigs,
 		subnetConfigs,
 		flags,
+		snapshotPassphrase,
 	)
 	return net, err
 }
@@ -118,6 +113,9 @@ func (ln *localNetwork) SaveSnapshot(ctx context.Context, snapshotName string) (
 	if _, err := os.Stat(snapshotDir); err == nil {
 		return "", fmt.Errorf("snapshot %q already exists", snapshotName)
 	}
+	if _, err := os.Stat(snapshotDir + encryptedSnapshotSuffix); err == nil {
+		return "", fmt.Errorf("encrypted snapshot %q already exists", snapshotName)
+	}
 	// keep copy of node info that will be removed by stop
 	nodesConfig := map[string]node.Config{}
 	nodesDBDir := map[string]string{}
@@ -126,6 +124,10 @@ func (ln *localNetwork) SaveSnapshot(ctx context.Context, snapshotName string) (
 		// depending on how the user generated the config, different nodes config flags
 		// may point to the same map, so we made a copy to avoid always modifying the same value
 		nodeConfig.Flags = maps.Clone(nodeConfig.Flags)
+		// aliases registered at runtime are not yet on the flags of running nodes
+		if err := ln.setAliasesFlags(&nodeConfig); err != nil {
+			return "", err
+		}
 		nodesConfig[nodeName] = nodeConfig
 		nodesDBDir[nodeName] = node.GetDbDir()
 	}
@@ -149,6 +151,9 @@ func (ln *localNetwork) SaveSnapshot(ctx context.Context, snapshotName string) (
 		}
 		delete(nodeConfig.Flags, config.DataDirKey)
 		delete(nodeConfig.Flags, config.LogsDirKey)
+		nodeConfig.DataDir = ""
+		nodeConfig.DBDir = ""
+		nodeConfig.LogsDir = ""
 		nodesConfig[nodeName] = nodeConfig
 	}
 
@@ -174,6 +179,19 @@ func (ln *localNetwork) SaveSnapshot(ctx context.Context, snapshotName string) (
 			return "", fmt.Errorf("failure saving node %q db dir: %w", nodeConfig.Name, err)
 		}
 	}
+	// the keys of a keystore network are only saved encrypted
+	if ln.stakingKeysStorage == network.StakingKeysStorageKeystore {
+		for nodeName, nodeConfig := range nodesConfig {
+			keystoreDir := filepath.Join(snapshotDir, snapshotKeystoresSubdir, nodeName)
+			if err := writeStakingKeystore(keystoreDir, &nodeConfig, ln.keystorePassphrase); err != nil {
+				return "", err
+			}
+			nodeConfig.StakingKey = ""
+			nodeConfig.StakingCert = ""
+			nodeConfig.StakingSigningKey = ""
+			nodesConfig[nodeName] = nodeConfig
+		}
+	}
 	// save network conf
 	networkConfig := network.Config{
 		Genesis:            string(ln.genesis),
@@ -183,6 +201,9 @@ func (ln *localNetwork) SaveSnapshot(ctx context.Context, snapshotName string) (
 		ChainConfigFiles:   ln.chainConfigFiles,
 		UpgradeConfigFiles: ln.upgradeConfigFiles,
 		SubnetConfigFiles:  ln.subnetConfigFiles,
+		StakingSpec:        ln.stakingSpec,
+		PortRange:          ln.portRange,
+		StakingKeysStorage: ln.stakingKeysStorage,
 	}
 
 	// no need to save this, will be generated automatically on snapshot load
@@ -195,18 +216,7 @@ func (ln *localNetwork) SaveSnapshot(ctx context.Context, snapshotName string) (
 		return "", err
 	}
 	// save dynamic part of network not available on blockchain
-	subnetID2ElasticSubnetID := map[string]string{}
-	for subnetID, elasticSubnetID := range ln.subnetID2ElasticSubnetID {
-		subnetID2ElasticSubnetID[subnetID.String()] = elasticSubnetID.String()
-	}
-	networkState := NetworkState{
-		SubnetID2ElasticSubnetID: subnetID2ElasticSubnetID,
-	}
-	networkStateJSON, err := json.MarshalIndent(networkState, "", "    ")
-	if err != nil {
-		return "", err
-	}
-	if err := createFileAndWrite(filepath.Join(snapshotDir, "state.json"), networkStateJSON); err != nil {
+	if err := ln.writeNetworkState(snapshotDir); err != nil {
 		return "", err
 	}
 	return snapshotDir, nil
@@ -222,20 +232,37 @@ func (ln *localNetwork) loadSnapshot(
 	upgradeConfigs map[string]string,
 	subnetConfigs map[string]string,
 	flags map[string]interface{},
+	passphrase string,
 ) error {
 	ln.lock.Lock()
 	defer ln.lock.Unlock()
 
 	snapshotDir := filepath.Join(ln.snapshotsDir, snapshotPrefix+snapshotName)
-	snapshotDBDir := filepath.Join(snapshotDir, defaultDBSubdir)
 	_, err := os.Stat(snapshotDir)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			return ErrSnapshotNotFound
-		} else {
+	if errors.Is(err, os.ErrNotExist) {
+		// the snapshot may be encrypted
+		archivePath := snapshotDir + encryptedSnapshotSuffix
+		if _, err := os.Stat(archivePath); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return ErrSnapshotNotFound
+			}
 			return fmt.Errorf("failure accessing snapshot %q: %w", snapshotName, err)
 		}
+		if passphrase == "" {
+			return ErrSnapshotEncrypted
+		}
+		snapshotDir, err = os.MkdirTemp("", snapshotPrefix+"*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(snapshotDir)
+		if err := decryptSnapshot(archivePath, passphrase, snapshotDir); err != nil {
+			return fmt.Errorf("failure decrypting snapshot %q: %w", snapshotName, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failure accessing snapshot %q: %w", snapshotName, err)
 	}
+	snapshotDBDir := filepath.Join(snapshotDir, defaultDBSubdir)
 	// load network config
 	networkConfigJSON, err := os.ReadFile(filepath.Join(snapshotDir, "network.json"))
 	if err != nil {
@@ -254,6 +281,20 @@ func (ln *localNetwork) loadSnapshot(
 			return err
 		}
 	}
+	// restore the keys of a keystore network
+	if networkConfig.StakingKeysStorage == network.StakingKeysStorageKeystore {
+		passphrase, err := getKeystorePassphrase()
+		if err != nil {
+			return err
+		}
+		for i := range networkConfig.NodeConfigs {
+			nodeConfig := &networkConfig.NodeConfigs[i]
+			keystoreDir := filepath.Join(snapshotDir, snapshotKeystoresSubdir, nodeConfig.Name)
+			if err := readStakingKeystore(keystoreDir, nodeConfig, passphrase); err != nil {
+				return err
+			}
+		}
+	}
 	// add flags
 	for i := range networkConfig.NodeConfigs {
 		for k, v := range flags {
@@ -303,37 +344,36 @@ func (ln *localNetwork) loadSnapshot(
 		}
 	}
 	// load network state not available at blockchain db
-	networkStateJSON, err := os.ReadFile(filepath.Join(snapshotDir, "state.json"))
+	networkState, err := ReadNetworkState(snapshotDir)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("failure reading network state file from snapshot: %w", err)
+			return fmt.Errorf("failure loading snapshot: %w", err)
 		}
-		ln.log.Warn("network state file not found on snapshot")
-	} else {
-		networkState := NetworkState{}
-		if err := json.Unmarshal(networkStateJSON, &networkState); err != nil {
-			return fmt.Errorf("failure unmarshaling network state from snapshot: %w", err)
-		}
-		ln.subnetID2ElasticSubnetID = map[ids.ID]ids.ID{}
-		for subnetIDStr, elasticSubnetIDStr := range networkState.SubnetID2ElasticSubnetID {
-			subnetID, err := ids.FromString(subnetIDStr)
-			if err != nil {
-				return err
-			}
-			elasticSubnetID, err := ids.FromString(elasticSubnetIDStr)
-			if err != nil {
-				return err
-			}
-			ln.subnetID2ElasticSubnetID[subnetID] = elasticSubnetID
+		ln.log.Warn("network state file not found on snapshot, elastic subnet IDs are unknown")
+		// aliases are also kept on the node flags saved on the snapshot
+		ln.chainAliases, ln.vmAliases, err = aliasesFromNodeConfigs(networkConfig.NodeConfigs)
+		if err != nil {
+			return err
 		}
+	} else if err := ln.setNetworkState(networkState); err != nil {
+		return fmt.Errorf("failure loading network state from snapshot: %w", err)
+	}
+	if err := ln.persistNetworkState(); err != nil {
+		return err
 	}
 	return ln.loadConfig(ctx, networkConfig)
 }
 
-// Remove network snapshot
+// Remove network snapshot, either encrypted or not
 func (ln *localNetwork) RemoveSnapshot(snapshotName string) error {
 	snapshotDir := filepath.Join(ln.snapshotsDir, snapshotPrefix+snapshotName)
 	_, err := os.Stat(snapshotDir)
+	if errors.Is(err, os.ErrNotExist) {
+		if _, encryptedErr := os.Stat(snapshotDir + encryptedSnapshotSuffix); encryptedErr == nil {
+			snapshotDir += encryptedSnapshotSuffix
+			err = nil
+		}
+	}
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return ErrSnapshotNotFound
@@ -363,7 +403,8 @@ func (ln *localNetwork) GetSnapshotNames() ([]string, error) {
 	}
 	snapshots := []string{}
 	for _, match := range matches {
-		snapshots = append(snapshots, strings.TrimPrefix(filepath.Base(match), snapshotPrefix))
+		snapshotName := strings.TrimPrefix(filepath.Base(match), snapshotPrefix)
+		snapshots = append(snapshots, strings.TrimSuffix(snapshotName, encryptedSnapshotSuffix))
 	}
 	return snapshots, nil
 }
@@ -0,0 +1,176 @@
+package local
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/luxdefi/netrunner/utils"
+)
+
+// suffix of the archive an encrypted snapshot is kept in, instead of
+// its dir
+const encryptedSnapshotSuffix = ".enc"
+
+var ErrSnapshotEncrypted = errors.New("snapshot is encrypted, but no passphrase was given")
+
+// EncryptSnapshot replaces the snapshot dir [snapshotDir], as returned by
+// SaveSnapshot, by a gzipped tar archive of it encrypted with [passphrase],
+// so it can be kept on shared artifact stores. Returns the path of the
+// archive. Networks are loaded from it giving the same passphrase.
+// The archive is written to a temp file first, so a failure doesn't leave
+// a partial archive behind. On failure the snapshot dir is kept, and it is
+// up to the caller to remove it.
+func EncryptSnapshot(snapshotDir string, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", errors.New("empty snapshot passphrase")
+	}
+	archivePath := snapshotDir + encryptedSnapshotSuffix
+	if _, err := os.Stat(archivePath); err == nil {
+		return "", fmt.Errorf("encrypted snapshot %q already exists", archivePath)
+	}
+	// hidden, so it is not listed as a snapshot
+	archiveFile, err := os.CreateTemp(filepath.Dir(archivePath), "."+filepath.Base(archivePath)+"-*")
+	if err != nil {
+		return "", err
+	}
+	err = writeEncryptedArchive(snapshotDir, archiveFile, passphrase)
+	if closeErr := archiveFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(archiveFile.Name(), archivePath)
+	}
+	if err != nil {
+		_ = os.Remove(archiveFile.Name())
+		return "", fmt.Errorf("failure encrypting snapshot %q: %w", snapshotDir, err)
+	}
+	if err := os.RemoveAll(snapshotDir); err != nil {
+		return "", fmt.Errorf("failure removing snapshot path %q: %w", snapshotDir, err)
+	}
+	return archivePath, nil
+}
+
+// Assumes [archiveFile] is closed by the caller.
+func writeEncryptedArchive(dir string, archiveFile *os.File, passphrase string) error {
+	encryptWriter, err := utils.NewEncryptWriter(archiveFile, passphrase)
+	if err != nil {
+		return err
+	}
+	gzipWriter := gzip.NewWriter(encryptWriter)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsDir() && !info.Mode().IsRegular() {
+			return fmt.Errorf("unexpected file type at %q", path)
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tarWriter, file)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return err
+	}
+	if err := encryptWriter.Close(); err != nil {
+		return err
+	}
+	return archiveFile.Sync()
+}
+
+// decryptSnapshot extracts the encrypted snapshot archive [archivePath]
+// into [dir]
+func decryptSnapshot(archivePath string, passphrase string, dir string) error {
+	archiveFile, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer archiveFile.Close()
+	decryptReader, err := utils.NewDecryptReader(archiveFile, passphrase)
+	if err != nil {
+		return err
+	}
+	gzipReader, err := gzip.NewReader(decryptReader)
+	if err != nil {
+		// the first chunk is decrypted when the gzip header is read
+		return err
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path %q in snapshot archive", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := extractFile(tarReader, path, header.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected type of %q in snapshot archive", header.Name)
+		}
+	}
+}
+
+func extractFile(r io.Reader, path string, perm fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, r)
+	return err
+}
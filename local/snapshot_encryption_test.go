@@ -0,0 +1,61 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxdefi/netrunner/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	snapshotDir := filepath.Join(t.TempDir(), snapshotPrefix+"test")
+	files := map[string]string{
+		"network.json":                    `{"nodeConfigs":[{"stakingKey":"secret"}]}`,
+		filepath.Join("db", "node1", "x"): "db contents",
+	}
+	for path, contents := range files {
+		require.NoError(createFileAndWrite(filepath.Join(snapshotDir, path), []byte(contents)))
+	}
+
+	archivePath, err := EncryptSnapshot(snapshotDir, "passphrase")
+	require.NoError(err)
+	require.Equal(snapshotDir+encryptedSnapshotSuffix, archivePath)
+	_, err = os.Stat(snapshotDir)
+	require.ErrorIs(err, os.ErrNotExist)
+	archive, err := os.ReadFile(archivePath)
+	require.NoError(err)
+	require.NotContains(string(archive), "secret")
+
+	decryptedDir := t.TempDir()
+	require.NoError(decryptSnapshot(archivePath, "passphrase", decryptedDir))
+	for path, contents := range files {
+		got, err := os.ReadFile(filepath.Join(decryptedDir, path))
+		require.NoError(err)
+		require.Equal(contents, string(got))
+	}
+
+	require.ErrorIs(decryptSnapshot(archivePath, "other passphrase", t.TempDir()), utils.ErrDecryptStream)
+}
+
+func TestEncryptSnapshotFailure(t *testing.T) {
+	require := require.New(t)
+
+	snapshotsDir := t.TempDir()
+	snapshotDir := filepath.Join(snapshotsDir, snapshotPrefix+"test")
+	require.NoError(createFileAndWrite(filepath.Join(snapshotDir, "network.json"), []byte("{}")))
+	// only dirs and regular files are archived
+	require.NoError(os.Symlink("network.json", filepath.Join(snapshotDir, "link")))
+
+	_, err := EncryptSnapshot(snapshotDir, "passphrase")
+	require.ErrorContains(err, "unexpected file type")
+
+	// no partial archive is left, and the snapshot is kept for the caller
+	entries, err := os.ReadDir(snapshotsDir)
+	require.NoError(err)
+	require.Len(entries, 1)
+	require.Equal(filepath.Base(snapshotDir), entries[0].Name())
+}
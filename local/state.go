@@ -0,0 +1,207 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/config"
+	"github.com/luxdefi/node/ids"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// name of the network state file, both on the root data dir and on snapshots
+const networkStateFileName = "state.json"
+
+// NetworkState defines dynamic network information not available on blockchain db
+type NetworkState struct {
+	// Map from subnet id to elastic subnet tx id
+	SubnetID2ElasticSubnetID map[string]string `json:"subnetID2ElasticSubnetID"`
+	// Map from chain id to its aliases
+	ChainAliases map[string][]string `json:"chainAliases"`
+	// Map from vm id to its aliases
+	VMAliases map[string][]string `json:"vmAliases"`
+	// Blockchains created on the network
+	Blockchains []BlockchainState `json:"blockchains,omitempty"`
+}
+
+// BlockchainState is the record of a blockchain created on the network
+type BlockchainState struct {
+	// VM name, as it can't be recovered from the VM ID
+	ChainName    string `json:"chainName"`
+	VMID         string `json:"vmID"`
+	SubnetID     string `json:"subnetID"`
+	BlockchainID string `json:"blockchainID"`
+}
+
+// ReadNetworkState returns the network state saved on [dir], which can be
+// the root data dir of a network or a snapshot dir.
+// Returns an error wrapping os.ErrNotExist if there is no state saved on [dir].
+func ReadNetworkState(dir string) (NetworkState, error) {
+	networkState := NetworkState{}
+	networkStateJSON, err := os.ReadFile(filepath.Join(dir, networkStateFileName))
+	if err != nil {
+		return networkState, fmt.Errorf("failure reading network state file: %w", err)
+	}
+	if err := json.Unmarshal(networkStateJSON, &networkState); err != nil {
+		return networkState, fmt.Errorf("failure unmarshaling network state: %w", err)
+	}
+	return networkState, nil
+}
+
+// Writes the network state to [dir]
+func (ln *localNetwork) writeNetworkState(dir string) error {
+	networkStateJSON, err := json.MarshalIndent(ln.getNetworkState(), "", "    ")
+	if err != nil {
+		return err
+	}
+	return createFileAndWrite(filepath.Join(dir, networkStateFileName), networkStateJSON)
+}
+
+// Writes the network state to the root data dir, so it can be read after
+// the process using the network is restarted.
+// Called after each change of the state.
+func (ln *localNetwork) persistNetworkState() error {
+	if err := ln.writeNetworkState(ln.rootDir); err != nil {
+		return fmt.Errorf("failure persisting network state: %w", err)
+	}
+	return nil
+}
+
+// Loads the network state persisted on the root data dir, if any, so a
+// network created again on the root data dir of a previous one, e.g.
+// after the process using it was restarted, keeps its blockchains,
+// elastic subnets and aliases.
+func (ln *localNetwork) loadPersistedNetworkState() error {
+	networkState, err := ReadNetworkState(ln.rootDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := ln.setNetworkState(networkState); err != nil {
+		return fmt.Errorf("failure loading persisted network state: %w", err)
+	}
+	ln.reloadedBlockchains = append([]blockchainInfo{}, ln.blockchains...)
+	ln.log.Info("loaded persisted network state",
+		zap.String("root-dir", ln.rootDir),
+		zap.Int("blockchains", len(ln.blockchains)),
+	)
+	return nil
+}
+
+// Waits until the running nodes tracking the subnet of each blockchain
+// loaded from the persisted network state run it again. Only waits the
+// first time the network is healthy after being reloaded.
+func (ln *localNetwork) waitForReloadedBlockchains(ctx context.Context) error {
+	ln.lock.Lock()
+	defer ln.lock.Unlock()
+
+	if len(ln.reloadedBlockchains) == 0 {
+		return nil
+	}
+	ctx, cancel := ln.cancelOnStop(ctx)
+	defer cancel()
+
+	nodeNames := maps.Keys(ln.nodes)
+	sort.Strings(nodeNames)
+	for i, chainInfo := range ln.reloadedBlockchains {
+		for _, nodeName := range nodeNames {
+			node := ln.nodes[nodeName]
+			if node.paused || !isTrackingSubnet(node, chainInfo.subnetID) {
+				continue
+			}
+			if err := ln.waitForChainLog(ctx, node, chainInfo); err != nil {
+				return err
+			}
+		}
+		ln.reportProgress(
+			network.PhaseWaitBlockchains,
+			i+1,
+			len(ln.reloadedBlockchains),
+			fmt.Sprintf("blockchain %s is running", chainInfo.blockchainID),
+		)
+	}
+	ln.log.Info("reloaded blockchains are running", zap.Int("blockchains", len(ln.reloadedBlockchains)))
+	ln.reloadedBlockchains = nil
+	return nil
+}
+
+// Returns true if [node] is configured to track [subnetID].
+func isTrackingSubnet(node *localNode, subnetID ids.ID) bool {
+	tracked, _ := node.GetConfig().Flags[config.TrackSubnetsKey].(string)
+	return slices.Contains(strings.Split(tracked, ","), subnetID.String())
+}
+
+func (ln *localNetwork) getNetworkState() NetworkState {
+	subnetID2ElasticSubnetID := map[string]string{}
+	for subnetID, elasticSubnetID := range ln.subnetID2ElasticSubnetID {
+		subnetID2ElasticSubnetID[subnetID.String()] = elasticSubnetID.String()
+	}
+	blockchains := make([]BlockchainState, len(ln.blockchains))
+	for i, chainInfo := range ln.blockchains {
+		blockchains[i] = BlockchainState{
+			ChainName:    chainInfo.chainName,
+			VMID:         chainInfo.vmID.String(),
+			SubnetID:     chainInfo.subnetID.String(),
+			BlockchainID: chainInfo.blockchainID.String(),
+		}
+	}
+	return NetworkState{
+		SubnetID2ElasticSubnetID: subnetID2ElasticSubnetID,
+		ChainAliases:             ln.chainAliases,
+		VMAliases:                ln.vmAliases,
+		Blockchains:              blockchains,
+	}
+}
+
+func (ln *localNetwork) setNetworkState(networkState NetworkState) error {
+	subnetID2ElasticSubnetID := map[ids.ID]ids.ID{}
+	for subnetIDStr, elasticSubnetIDStr := range networkState.SubnetID2ElasticSubnetID {
+		subnetID, err := ids.FromString(subnetIDStr)
+		if err != nil {
+			return err
+		}
+		elasticSubnetID, err := ids.FromString(elasticSubnetIDStr)
+		if err != nil {
+			return err
+		}
+		subnetID2ElasticSubnetID[subnetID] = elasticSubnetID
+	}
+	blockchains := make([]blockchainInfo, len(networkState.Blockchains))
+	for i, blockchain := range networkState.Blockchains {
+		idStrs := []string{blockchain.VMID, blockchain.SubnetID, blockchain.BlockchainID}
+		chainIDs := make([]ids.ID, len(idStrs))
+		for j, idStr := range idStrs {
+			id, err := ids.FromString(idStr)
+			if err != nil {
+				return fmt.Errorf("invalid ID on blockchain %q state: %w", blockchain.ChainName, err)
+			}
+			chainIDs[j] = id
+		}
+		blockchains[i] = blockchainInfo{
+			chainName:    blockchain.ChainName,
+			vmID:         chainIDs[0],
+			subnetID:     chainIDs[1],
+			blockchainID: chainIDs[2],
+		}
+	}
+	ln.subnetID2ElasticSubnetID = subnetID2ElasticSubnetID
+	ln.blockchains = blockchains
+	if networkState.ChainAliases != nil {
+		ln.chainAliases = networkState.ChainAliases
+	}
+	if networkState.VMAliases != nil {
+		ln.vmAliases = networkState.VMAliases
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package local
+
+import (
+	"context"
+
+	"github.com/luxdefi/netrunner/utils"
+	"github.com/luxdefi/node/utils/rpc"
+	"go.uber.org/zap"
+)
+
+// admin API method of the node versions that can start tracking
+// subnets at runtime, without a restart
+const trackSubnetsMethod = "admin.trackSubnets"
+
+type trackSubnetsArgs struct {
+	SubnetIDs []string `json:"subnetIDs"`
+}
+
+// Asks [node] to start tracking [subnetIDs] through its admin API.
+// Returns false if the node doesn't support it (or its admin API is
+// disabled), in which case it has to be restarted to track them. Binaries
+// found not supporting it are not asked again.
+// Assumes [ln.lock] is held.
+func (ln *localNetwork) trackSubnetsAtRuntime(ctx context.Context, node *localNode, subnetIDs []string) bool {
+	binaryPath := node.GetBinaryPath()
+	if ln.runtimeTrackingUnsupported.Contains(binaryPath) {
+		return false
+	}
+	uri := utils.HTTPURI(node.GetURL(), node.GetAPIPort())
+	requester := rpc.NewEndpointRequester(uri + "/ext/admin")
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	if err := requester.SendRequest(cctx, trackSubnetsMethod, &trackSubnetsArgs{SubnetIDs: subnetIDs}, &struct{}{}); err != nil {
+		ln.log.Debug("node can't track subnets at runtime",
+			zap.String("node-name", node.GetName()),
+			zap.String("binary-path", binaryPath),
+			zap.Error(err),
+		)
+		ln.runtimeTrackingUnsupported.Add(binaryPath)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/luxdefi/node/utils/logging"
+)
+
+var (
+	ErrBackendNotFound   = errors.New("network backend not found")
+	ErrBackendRegistered = errors.New("network backend already registered")
+)
+
+// BackendOptions holds the settings common to all networks a backend creates
+type BackendOptions struct {
+	// Dir where the node data and logs are kept
+	RootDataDir string
+	// Dir where the snapshots are kept
+	SnapshotsDir string
+	// If true, the ports of the nodes are changed if they are already in use
+	ReassignPortsIfUsed bool
+	// Called with the progress of the long operations of the network. May be nil
+	OnProgress ProgressHandler
+	// Signs the txs issued by the network. May be nil, in which case the
+	// key funded by the default genesis is used
+	TxSigner TxSigner
+}
+
+// SnapshotOptions holds the settings of a network created from a snapshot.
+// Empty values keep the ones saved on the snapshot
+type SnapshotOptions struct {
+	BackendOptions
+	BinaryPath       string
+	PluginDir        string
+	ChainConfigs     map[string]string
+	UpgradeConfigs   map[string]string
+	SubnetConfigs    map[string]string
+	GlobalNodeConfig map[string]interface{}
+	// Passphrase of the snapshot, if it was encrypted
+	Passphrase string
+}
+
+// Backend creates networks on a given kind of infrastructure
+// (e.g. local processes, VMs, remote hardware)
+type Backend interface {
+	// Create and start a network from the given config
+	NewNetwork(log logging.Logger, cfg Config, opts BackendOptions) (Network, error)
+	// Create and start a network from the given snapshot
+	NewNetworkFromSnapshot(log logging.Logger, snapshotName string, opts SnapshotOptions) (Network, error)
+}
+
+var (
+	backendsLock sync.RWMutex
+	backends     = map[string]Backend{}
+)
+
+// RegisterBackend makes [backend] available by [name]. Backends usually
+// register themselves from an init function of their package.
+func RegisterBackend(name string, backend Backend) error {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+
+	if _, ok := backends[name]; ok {
+		return fmt.Errorf("%w: %q", ErrBackendRegistered, name)
+	}
+	backends[name] = backend
+	return nil
+}
+
+// GetBackend returns the backend registered as [name].
+func GetBackend(name string) (Backend, error) {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	backend, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrBackendNotFound, name)
+	}
+	return backend, nil
+}
+
+// BackendNames returns the names of the registered backends.
+func BackendNames() []string {
+	backendsLock.RLock()
+	defer backendsLock.RUnlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
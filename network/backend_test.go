@@ -0,0 +1,35 @@
+package network_test
+
+import (
+	"testing"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+type testBackend struct{}
+
+func (testBackend) NewNetwork(logging.Logger, network.Config, network.BackendOptions) (network.Network, error) {
+	return nil, nil
+}
+
+func (testBackend) NewNetworkFromSnapshot(logging.Logger, string, network.SnapshotOptions) (network.Network, error) {
+	return nil, nil
+}
+
+func TestBackendRegistry(t *testing.T) {
+	require := require.New(t)
+
+	_, err := network.GetBackend("test")
+	require.ErrorIs(err, network.ErrBackendNotFound)
+
+	require.NoError(network.RegisterBackend("test", testBackend{}))
+	err = network.RegisterBackend("test", testBackend{})
+	require.ErrorIs(err, network.ErrBackendRegistered)
+
+	backend, err := network.GetBackend("test")
+	require.NoError(err)
+	require.Equal(testBackend{}, backend)
+	require.Contains(network.BackendNames(), "test")
+}
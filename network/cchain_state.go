@@ -0,0 +1,215 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/luxdefi/coreth/rpc"
+	"github.com/luxdefi/node/utils/constants"
+)
+
+var ErrNoCChainState = errors.New("no C-Chain alloc or accounts found")
+
+// CChainAlloc holds genesis allocations of C-Chain accounts, in the format
+// of the alloc of a geth genesis (balance, nonce, code, storage)
+type CChainAlloc map[common.Address]map[string]interface{}
+
+// state dump returned by debug_dumpBlock, and written by geth dump
+type cChainStateDump struct {
+	Accounts map[string]cChainDumpAccount `json:"accounts"`
+}
+
+type cChainDumpAccount struct {
+	Balance string            `json:"balance"`
+	Nonce   uint64            `json:"nonce"`
+	Code    string            `json:"code"`
+	Storage map[string]string `json:"storage"`
+	Address *common.Address   `json:"address"`
+}
+
+// ReadCChainAlloc reads the C-Chain accounts of the file at [path], either
+// a genesis with an alloc (as a geth or C-Chain genesis), or a state dump
+// (as returned by debug_dumpBlock or written by geth dump)
+func ReadCChainAlloc(path string) (CChainAlloc, error) {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Alloc    map[string]map[string]interface{} `json:"alloc"`
+		Accounts map[string]cChainDumpAccount      `json:"accounts"`
+	}
+	if err := json.Unmarshal(fileBytes, &file); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal %s: %w", path, err)
+	}
+	switch {
+	case file.Alloc != nil:
+		alloc := CChainAlloc{}
+		for key, account := range file.Alloc {
+			if !common.IsHexAddress(key) {
+				return nil, fmt.Errorf("invalid alloc address %q in %s", key, path)
+			}
+			alloc[common.HexToAddress(key)] = account
+		}
+		return alloc, nil
+	case file.Accounts != nil:
+		return dumpToCChainAlloc(cChainStateDump{Accounts: file.Accounts})
+	default:
+		return nil, fmt.Errorf("%w in %s", ErrNoCChainState, path)
+	}
+}
+
+// DumpCChainAlloc returns the C-Chain accounts of the chain at [rpcURL] at
+// height [blockNumber] (the latest block if zero). The node must have the
+// debug API enabled ("debug" in the eth-apis of its C-Chain config).
+func DumpCChainAlloc(ctx context.Context, rpcURL string, blockNumber uint64) (CChainAlloc, error) {
+	rpcClient, err := rpc.DialContext(ctx, rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't connect to %s: %w", rpcURL, err)
+	}
+	defer rpcClient.Close()
+
+	block := "latest"
+	if blockNumber != 0 {
+		block = hexutil.EncodeUint64(blockNumber)
+	}
+	var dump cChainStateDump
+	if err := rpcClient.CallContext(ctx, &dump, "debug_dumpBlock", block); err != nil {
+		return nil, fmt.Errorf("debug_dumpBlock failed (is the debug API enabled?): %w", err)
+	}
+	return dumpToCChainAlloc(dump)
+}
+
+// WriteCChainAlloc writes [cChainGenesis] (a C-Chain genesis JSON) with its
+// alloc replaced by [alloc] to [path], to be read by ReadCChainAlloc
+func WriteCChainAlloc(path string, cChainGenesis string, alloc CChainAlloc) error {
+	var cChainGenesisMap map[string]interface{}
+	if err := json.Unmarshal([]byte(cChainGenesis), &cChainGenesisMap); err != nil {
+		return fmt.Errorf("couldn't unmarshal C-Chain genesis: %w", err)
+	}
+	allocMap := map[string]interface{}{}
+	for addr, account := range alloc {
+		allocMap[strings.ToLower(addr.Hex())] = account
+	}
+	cChainGenesisMap["alloc"] = allocMap
+	fileBytes, err := json.MarshalIndent(cChainGenesisMap, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, fileBytes, 0o644)
+}
+
+// CChainGenesis returns the C-Chain genesis of [genesis]
+func CChainGenesis(genesis string) (string, error) {
+	var genesisMap map[string]interface{}
+	if err := json.Unmarshal([]byte(genesis), &genesisMap); err != nil {
+		return "", fmt.Errorf("couldn't unmarshal genesis: %w", err)
+	}
+	cChainGenesis, ok := genesisMap["cChainGenesis"].(string)
+	if !ok {
+		return "", fmt.Errorf("expected cChainGenesis to be a string, but got %T", genesisMap["cChainGenesis"])
+	}
+	return cChainGenesis, nil
+}
+
+// ImportCChainAlloc returns [genesis] with [alloc] merged into the alloc of
+// its C-Chain genesis, replacing the existing entries of the accounts, and
+// network ID [networkID] (DefaultForkNetworkID if zero), as nodes ignore the
+// given genesis for standard networks
+func ImportCChainAlloc(genesis string, alloc CChainAlloc, networkID uint32) (string, error) {
+	if networkID == 0 {
+		networkID = DefaultForkNetworkID
+	}
+	switch networkID {
+	case constants.TestnetID, constants.MainnetID, constants.LocalID:
+		return "", fmt.Errorf("%w: %d", ErrStandardForkNetworkID, networkID)
+	}
+
+	var genesisMap map[string]interface{}
+	if err := json.Unmarshal([]byte(genesis), &genesisMap); err != nil {
+		return "", fmt.Errorf("couldn't unmarshal genesis: %w", err)
+	}
+	cChainGenesisStr, ok := genesisMap["cChainGenesis"].(string)
+	if !ok {
+		return "", fmt.Errorf("expected cChainGenesis to be a string, but got %T", genesisMap["cChainGenesis"])
+	}
+	var cChainGenesis map[string]interface{}
+	if err := json.Unmarshal([]byte(cChainGenesisStr), &cChainGenesis); err != nil {
+		return "", fmt.Errorf("couldn't unmarshal C-Chain genesis: %w", err)
+	}
+	genesisAlloc, ok := cChainGenesis["alloc"].(map[string]interface{})
+	if !ok {
+		genesisAlloc = map[string]interface{}{}
+	}
+	// alloc keys are hex addresses, with or without prefix and in any case
+	for key := range genesisAlloc {
+		if _, ok := alloc[common.HexToAddress(key)]; ok {
+			delete(genesisAlloc, key)
+		}
+	}
+	for addr, account := range alloc {
+		genesisAlloc[strings.ToLower(addr.Hex())] = account
+	}
+	cChainGenesis["alloc"] = genesisAlloc
+
+	cChainGenesisBytes, err := json.Marshal(cChainGenesis)
+	if err != nil {
+		return "", err
+	}
+	genesisMap["cChainGenesis"] = string(cChainGenesisBytes)
+	if err := setGenesisMapNetworkID(genesisMap, networkID); err != nil {
+		return "", err
+	}
+	genesisBytes, err := json.Marshal(genesisMap)
+	if err != nil {
+		return "", err
+	}
+	return string(genesisBytes), nil
+}
+
+// Converts the accounts of [dump] to genesis allocations. The accounts
+// without address preimage can't be allocated, and are skipped.
+func dumpToCChainAlloc(dump cChainStateDump) (CChainAlloc, error) {
+	alloc := CChainAlloc{}
+	for key, dumpAccount := range dump.Accounts {
+		var addr common.Address
+		switch {
+		case dumpAccount.Address != nil:
+			addr = *dumpAccount.Address
+		case common.IsHexAddress(key):
+			addr = common.HexToAddress(key)
+		default:
+			continue
+		}
+		balance, ok := new(big.Int).SetString(dumpAccount.Balance, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid balance %q of account %s", dumpAccount.Balance, addr)
+		}
+		account := map[string]interface{}{
+			"balance": hexutil.EncodeBig(balance),
+		}
+		if dumpAccount.Nonce != 0 {
+			account["nonce"] = hexutil.EncodeUint64(dumpAccount.Nonce)
+		}
+		if dumpAccount.Code != "" && dumpAccount.Code != "0x" {
+			account["code"] = dumpAccount.Code
+		}
+		if len(dumpAccount.Storage) != 0 {
+			// dumped storage values are trimmed and may lack the hex prefix
+			storage := map[string]string{}
+			for slot, value := range dumpAccount.Storage {
+				storage[common.HexToHash(slot).Hex()] = common.HexToHash(value).Hex()
+			}
+			account["storage"] = storage
+		}
+		alloc[addr] = account
+	}
+	return alloc, nil
+}
@@ -0,0 +1,82 @@
+package network_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/luxdefi/netrunner/network"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCChainAlloc(t *testing.T) {
+	require := require.New(t)
+
+	addr := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	dir := t.TempDir()
+
+	genesisPath := filepath.Join(dir, "genesis.json")
+	require.NoError(os.WriteFile(genesisPath, []byte(`{"config":{"chainId":43112},"alloc":{"8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC":{"balance":"0x64"}}}`), 0o644))
+	alloc, err := network.ReadCChainAlloc(genesisPath)
+	require.NoError(err)
+	require.Equal(network.CChainAlloc{addr: {"balance": "0x64"}}, alloc)
+
+	dumpPath := filepath.Join(dir, "dump.json")
+	require.NoError(os.WriteFile(dumpPath, []byte(`{"root":"0x00","accounts":{"0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc":{"balance":"100","nonce":2,"code":"0x6080","storage":{"0x0000000000000000000000000000000000000000000000000000000000000001":"2a"}},"0x1234":{"balance":"1"}}}`), 0o644))
+	alloc, err = network.ReadCChainAlloc(dumpPath)
+	require.NoError(err)
+	// the account without address is skipped
+	require.Equal(network.CChainAlloc{addr: {
+		"balance": "0x64",
+		"nonce":   "0x2",
+		"code":    "0x6080",
+		"storage": map[string]string{
+			common.HexToHash("0x1").Hex(): common.HexToHash("0x2a").Hex(),
+		},
+	}}, alloc)
+
+	emptyPath := filepath.Join(dir, "empty.json")
+	require.NoError(os.WriteFile(emptyPath, []byte(`{}`), 0o644))
+	_, err = network.ReadCChainAlloc(emptyPath)
+	require.ErrorIs(err, network.ErrNoCChainState)
+}
+
+func TestImportCChainAlloc(t *testing.T) {
+	require := require.New(t)
+
+	alloc := network.CChainAlloc{
+		common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"): {"balance": "0x64"},
+	}
+	genesis, err := network.ImportCChainAlloc(forkTestGenesis, alloc, 0)
+	require.NoError(err)
+
+	var genesisMap map[string]interface{}
+	require.NoError(json.Unmarshal([]byte(genesis), &genesisMap))
+	require.EqualValues(network.DefaultForkNetworkID, genesisMap["networkID"])
+	var cChainGenesis struct {
+		Alloc map[string]map[string]interface{} `json:"alloc"`
+	}
+	require.NoError(json.Unmarshal([]byte(genesisMap["cChainGenesis"].(string)), &cChainGenesis))
+	require.Equal(map[string]map[string]interface{}{
+		"0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc": {"balance": "0x64"},
+		"0x1111111111111111111111111111111111111111": {"balance": "0x2"},
+	}, cChainGenesis.Alloc)
+
+	_, err = network.ImportCChainAlloc(forkTestGenesis, alloc, 1)
+	require.ErrorIs(err, network.ErrStandardForkNetworkID)
+}
+
+func TestWriteCChainAlloc(t *testing.T) {
+	require := require.New(t)
+
+	alloc := network.CChainAlloc{
+		common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"): {"balance": "0x64"},
+	}
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(network.WriteCChainAlloc(path, `{"config":{"chainId":43112},"alloc":{"0x1111111111111111111111111111111111111111":{"balance":"0x2"}}}`, alloc))
+	readAlloc, err := network.ReadCChainAlloc(path)
+	require.NoError(err)
+	require.Equal(alloc, readAlloc)
+}
@@ -1,11 +1,15 @@
 package network
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"math/big"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/luxdefi/netrunner/network/node"
@@ -14,6 +18,7 @@ import (
 	"github.com/luxdefi/node/ids"
 	"github.com/luxdefi/node/utils/constants"
 	"github.com/luxdefi/node/utils/formatting/address"
+	"github.com/luxdefi/node/utils/ips"
 	"github.com/luxdefi/node/utils/units"
 	"golang.org/x/exp/maps"
 )
@@ -22,6 +27,10 @@ var cChainConfig map[string]interface{}
 
 const (
 	validatorStake = units.MegaLux
+
+	// bounds of the port ranges not given
+	DefaultMinPort uint16 = 10000
+	DefaultMaxPort uint16 = math.MaxUint16
 )
 
 func init() {
@@ -75,17 +84,196 @@ type Config struct {
 	UpgradeConfigFiles map[string]string `json:"upgradeConfigFiles"`
 	// Subnet config files to use per default, if not specified in node config
 	SubnetConfigFiles map[string]string `json:"subnetConfigFiles"`
+	// Staking parameters used when adding validators at runtime
+	StakingSpec StakingSpec `json:"stakingSpec"`
+	// Range of the ports assigned to nodes, when not given in their configs
+	PortRange PortRange `json:"portRange"`
+	// If not zero, node logs are grouped in shard dirs of this many nodes
+	// under the network logs dir, instead of being in each node dir.
+	// Useful to browse the logs of big networks.
+	LogsShardSize int `json:"logsShardSize"`
+	// Templates of the node dirs, used instead of the dirs under the network
+	// root dir. The dirs given on the node configs take precedence.
+	DirLayout DirLayout `json:"dirLayout"`
+	// If set, the nodes join this existing network instead of starting
+	// a new one. Genesis is then only needed for non standard networks.
+	ExternalNetwork *ExternalNetworkSpec `json:"externalNetwork,omitempty"`
+	// If not zero, the randomness of the network derives from it, so two
+	// runs of the same config get the same genesis, node IDs and ports:
+	// the staking and signing keys generated for the nodes, the ports
+	// picked for them, and the start time of the default genesis.
+	Seed int64 `json:"seed,omitempty"`
+	// How the staking TLS and BLS keys are given to the nodes. One of the
+	// StakingKeysStorage values, defaults to StakingKeysStorageFile.
+	StakingKeysStorage string `json:"stakingKeysStorage,omitempty"`
+}
+
+// Values of Config.StakingKeysStorage
+const (
+	// The keys are written as plaintext files into the node data dirs
+	StakingKeysStorageFile = "file"
+	// The keys are only held in memory, and given to the nodes as the
+	// content of their staking flags on their environment
+	StakingKeysStorageMemory = "memory"
+	// As StakingKeysStorageMemory, but the keys are also written into the
+	// node data dirs as a keystore encrypted with the passphrase of the env
+	// var constants.StakingKeystorePassphraseEnvVar. A node restarted on
+	// the same data dir without keys in its config gets the ones of the
+	// keystore.
+	StakingKeysStorageKeystore = "keystore"
+)
+
+// ExternalNetworkSpec defines an existing network, e.g. a shared devnet
+// or testnet, the nodes bootstrap from.
+type ExternalNetworkSpec struct {
+	NetworkID uint32 `json:"networkID"`
+	// IP:port of the bootstrap nodes of the network
+	BootstrapIPs []string `json:"bootstrapIPs"`
+	// Node IDs of the bootstrap nodes, in the same order as BootstrapIPs
+	BootstrapIDs []string `json:"bootstrapIDs"`
+}
+
+// Validate returns an error if this spec is invalid
+func (s *ExternalNetworkSpec) Validate() error {
+	if s.NetworkID == 0 {
+		return errors.New("no network ID given")
+	}
+	if len(s.BootstrapIPs) == 0 {
+		return errors.New("no bootstrap nodes given")
+	}
+	if len(s.BootstrapIPs) != len(s.BootstrapIDs) {
+		return fmt.Errorf("got %d bootstrap IPs but %d bootstrap IDs", len(s.BootstrapIPs), len(s.BootstrapIDs))
+	}
+	for _, ip := range s.BootstrapIPs {
+		if _, err := ips.ToIPPort(ip); err != nil {
+			return fmt.Errorf("invalid bootstrap IP %q: %w", ip, err)
+		}
+	}
+	for _, id := range s.BootstrapIDs {
+		if _, err := ids.NodeIDFromString(id); err != nil {
+			return fmt.Errorf("invalid bootstrap ID %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// Placeholders of the DirLayout templates
+const (
+	// Replaced with the network root dir
+	DirLayoutRootPlaceholder = "{root}"
+	// Replaced with the node name
+	DirLayoutNodePlaceholder = "{node}"
+)
+
+// DirLayout defines where the dirs of each node are placed, eg to have the
+// dbs on a fast disk and the logs on another. Each non empty field is a path
+// template that must contain {node}, and may contain {root}.
+type DirLayout struct {
+	// Node data dir, holding its config files and staking keys.
+	// Defaults to {root}/{node}
+	DataDir string `json:"dataDir,omitempty"`
+	// Node db dir. Defaults to the db dir under the data dir
+	DBDir string `json:"dbDir,omitempty"`
+	// Node logs dir. Defaults to the logs dir under the data dir
+	LogsDir string `json:"logsDir,omitempty"`
+}
+
+// Validate returns an error if this layout is invalid
+func (l *DirLayout) Validate() error {
+	for _, template := range []string{l.DataDir, l.DBDir, l.LogsDir} {
+		if template != "" && !strings.Contains(template, DirLayoutNodePlaceholder) {
+			return fmt.Errorf("dir template %q doesn't contain %s, so the nodes would share the dir", template, DirLayoutNodePlaceholder)
+		}
+	}
+	return nil
+}
+
+// ExpandDirTemplate returns [template] with the DirLayout placeholders replaced
+// by [rootDir] and [nodeName]. Returns the empty string if [template] is empty.
+func ExpandDirTemplate(template string, rootDir string, nodeName string) string {
+	if template == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		DirLayoutRootPlaceholder, rootDir,
+		DirLayoutNodePlaceholder, nodeName,
+	).Replace(template)
+}
+
+// PortRange is an inclusive range of ports. Zero bounds mean the
+// network defaults are used.
+type PortRange struct {
+	Min uint16 `json:"min"`
+	Max uint16 `json:"max"`
+}
+
+// Bounds returns the bounds of the range, with the defaults in place of
+// the zero ones.
+func (r PortRange) Bounds() (uint16, uint16) {
+	minPort, maxPort := r.Min, r.Max
+	if minPort == 0 {
+		minPort = DefaultMinPort
+	}
+	if maxPort == 0 {
+		maxPort = DefaultMaxPort
+	}
+	return minPort, maxPort
+}
+
+// Validate returns an error if the range, with the defaults applied, is empty
+func (r PortRange) Validate() error {
+	if minPort, maxPort := r.Bounds(); minPort > maxPort {
+		return fmt.Errorf("invalid port range [%d, %d]: min port %d is above max port %d", r.Min, r.Max, minPort, maxPort)
+	}
+	return nil
+}
+
+// DefaultValidationStartOffset is the offset of the validation start
+// from the time the validator tx is issued, if the StakingSpec doesn't set it
+const DefaultValidationStartOffset = 20 * time.Second
+
+// StakingSpec defines the staking parameters used by the network when it
+// adds validators at runtime. Zero values mean the network defaults are used.
+type StakingSpec struct {
+	// Offset of validation start from the time the validator tx is issued
+	ValidationStartOffset time.Duration `json:"validationStartOffset"`
+	// Duration of primary network validation
+	ValidationDuration time.Duration `json:"validationDuration"`
+	// Weight assigned to subnet validators
+	SubnetValidatorsWeight uint64 `json:"subnetValidatorsWeight"`
+}
+
+// GetValidationStartOffset returns the offset of the validation start from
+// the time the validator tx is issued, the default one if not set
+func (s StakingSpec) GetValidationStartOffset() time.Duration {
+	if s.ValidationStartOffset == 0 {
+		return DefaultValidationStartOffset
+	}
+	return s.ValidationStartOffset
 }
 
 // Validate returns an error if this config is invalid
 func (c *Config) Validate() error {
-	if len(c.Genesis) == 0 {
+	if c.ExternalNetwork != nil {
+		if err := c.ExternalNetwork.Validate(); err != nil {
+			return fmt.Errorf("invalid external network: %w", err)
+		}
+	} else if len(c.Genesis) == 0 {
 		return errors.New("no genesis given")
 	}
 
-	networkID, err := utils.NetworkIDFromGenesis([]byte(c.Genesis))
+	networkID, err := c.GetNetworkID()
 	if err != nil {
-		return fmt.Errorf("couldn't get network ID from genesis: %w", err)
+		return err
+	}
+	if c.ExternalNetwork != nil && len(c.Genesis) != 0 {
+		genesisNetworkID, err := utils.NetworkIDFromGenesis([]byte(c.Genesis))
+		if err != nil {
+			return fmt.Errorf("couldn't get network ID from genesis: %w", err)
+		}
+		if genesisNetworkID != networkID {
+			return fmt.Errorf("genesis network ID %d differs from external network ID %d", genesisNetworkID, networkID)
+		}
 	}
 
 	var someNodeIsBeacon bool
@@ -103,12 +291,40 @@ func (c *Config) Validate() error {
 			someNodeIsBeacon = true
 		}
 	}
-	if len(c.NodeConfigs) > 0 && !someNodeIsBeacon {
+	// the bootstrap nodes of an external network are the beacons
+	if len(c.NodeConfigs) > 0 && !someNodeIsBeacon && c.ExternalNetwork == nil {
 		return errors.New("beacon nodes not given")
 	}
+	if c.StakingSpec.ValidationStartOffset < 0 || c.StakingSpec.ValidationDuration < 0 {
+		return errors.New("staking spec durations must not be negative")
+	}
+	if err := c.PortRange.Validate(); err != nil {
+		return err
+	}
+	if err := c.DirLayout.Validate(); err != nil {
+		return fmt.Errorf("invalid dir layout: %w", err)
+	}
+	switch c.StakingKeysStorage {
+	case "", StakingKeysStorageFile, StakingKeysStorageMemory, StakingKeysStorageKeystore:
+	default:
+		return fmt.Errorf("invalid staking keys storage %q", c.StakingKeysStorage)
+	}
 	return nil
 }
 
+// GetNetworkID returns the ID of the external network if given,
+// or the one of the genesis otherwise
+func (c *Config) GetNetworkID() (uint32, error) {
+	if c.ExternalNetwork != nil {
+		return c.ExternalNetwork.NetworkID, nil
+	}
+	networkID, err := utils.NetworkIDFromGenesis([]byte(c.Genesis))
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get network ID from genesis: %w", err)
+	}
+	return networkID, nil
+}
+
 // Return a genesis JSON where:
 // The nodes in [genesisVdrs] are validators.
 // The C-Chain and X-Chain balances are given by
@@ -120,6 +336,47 @@ func NewLuxGenesis(
 	xChainBalances []AddrAndBalance,
 	cChainBalances []AddrAndBalance,
 	genesisVdrs []ids.NodeID,
+) ([]byte, error) {
+	return newLuxGenesis(networkID, xChainBalances, cChainBalances, genesisVdrs, rand.Reader, time.Now())
+}
+
+// SeededInitialStakeDuration is the duration of the genesis validations of
+// the seeded networks. It is the max stake duration of the nodes, as the
+// genesis validations can't last longer.
+var SeededInitialStakeDuration = genesis.LocalParams.MaxStakeDuration
+
+// The genesis start time of the seeded networks moves forward in windows of
+// half the duration of their genesis validations, so the genesis is the same
+// within a window, and the validations last for at least half their duration
+// after the network starts.
+var seededStartTimeWindow = SeededInitialStakeDuration / 2
+
+// first genesis start time of the seeded networks
+var seededStartTimeEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// GetSeededStartTime returns the genesis start time of the seeded networks
+// started at [now]. It is the start of the window [now] falls in, so their
+// genesis only derives from the seed, and two runs with the same seed get
+// the same genesis on any date of the window.
+func GetSeededStartTime(now time.Time) time.Time {
+	windows := now.Sub(seededStartTimeEpoch) / seededStartTimeWindow
+	return seededStartTimeEpoch.Add(windows * seededStartTimeWindow)
+}
+
+// Returns a random genesis address read from [r].
+func readGenesisAddr(r io.Reader) (ids.ShortID, error) {
+	var addr ids.ShortID
+	_, err := io.ReadFull(r, addr[:])
+	return addr, err
+}
+
+func newLuxGenesis(
+	networkID uint32,
+	xChainBalances []AddrAndBalance,
+	cChainBalances []AddrAndBalance,
+	genesisVdrs []ids.NodeID,
+	r io.Reader,
+	now time.Time,
 ) ([]byte, error) {
 	switch networkID {
 	case constants.TestnetID, constants.MainnetID, constants.LocalID:
@@ -133,10 +390,14 @@ func NewLuxGenesis(
 	}
 
 	// Address that controls stake doesn't matter -- generate it randomly
+	stakeAddr, err := readGenesisAddr(r)
+	if err != nil {
+		return nil, err
+	}
 	genesisVdrStakeAddr, _ := address.Format(
 		"X",
 		constants.GetHRP(networkID),
-		ids.GenerateTestShortID().Bytes(),
+		stakeAddr.Bytes(),
 	)
 	config := genesis.UnparsedConfig{
 		NetworkID: networkID,
@@ -152,7 +413,7 @@ func NewLuxGenesis(
 				},
 			},
 		},
-		StartTime:                  uint64(time.Now().Unix()),
+		StartTime:                  uint64(now.Unix()),
 		InitialStakedFunds:         []string{genesisVdrStakeAddr},
 		InitialStakeDuration:       31_536_000, // 1 year
 		InitialStakeDurationOffset: 5_400,      // 90 minutes
@@ -170,7 +431,7 @@ func NewLuxGenesis(
 				UnlockSchedule: []genesis.LockedAmount{
 					{
 						Amount:   validatorStake * uint64(len(genesisVdrs)), // Stake
-						Locktime: uint64(time.Now().Add(7 * 24 * time.Hour).Unix()),
+						Locktime: uint64(now.Add(7 * 24 * time.Hour).Unix()),
 					},
 				},
 			},
@@ -194,7 +455,11 @@ func NewLuxGenesis(
 
 	// Set initial validators.
 	// Give staking rewards to random address.
-	rewardAddr, _ := address.Format("X", constants.GetHRP(networkID), ids.GenerateTestShortID().Bytes())
+	rewardShortAddr, err := readGenesisAddr(r)
+	if err != nil {
+		return nil, err
+	}
+	rewardAddr, _ := address.Format("X", constants.GetHRP(networkID), rewardShortAddr.Bytes())
 	for _, genesisVdr := range genesisVdrs {
 		config.InitialStakers = append(
 			config.InitialStakers,
@@ -3,6 +3,7 @@ package network_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/luxdefi/netrunner/network"
 	"github.com/luxdefi/netrunner/network/node"
@@ -58,3 +59,60 @@ func TestConfigMarshalJSON(t *testing.T) {
 
 	require.EqualValues(t, control, netcfg)
 }
+
+func TestDirLayout(t *testing.T) {
+	require := require.New(t)
+
+	layout := network.DirLayout{
+		DBDir:   "/mnt/fast/{node}/db",
+		LogsDir: "{root}/logs/{node}",
+	}
+	require.NoError(layout.Validate())
+	require.Equal("/mnt/fast/node1/db", network.ExpandDirTemplate(layout.DBDir, "/tmp/network", "node1"))
+	require.Equal("/tmp/network/logs/node1", network.ExpandDirTemplate(layout.LogsDir, "/tmp/network", "node1"))
+	require.Empty(network.ExpandDirTemplate(layout.DataDir, "/tmp/network", "node1"))
+
+	// nodes can't share a dir
+	layout.DataDir = "{root}/data"
+	require.Error(layout.Validate())
+}
+
+func TestPortRangeValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		portRange network.PortRange
+		expectErr bool
+	}{
+		{name: "defaults", portRange: network.PortRange{}},
+		{name: "both given", portRange: network.PortRange{Min: 20000, Max: 20100}},
+		{name: "single port", portRange: network.PortRange{Min: 20000, Max: 20000}},
+		{name: "min only", portRange: network.PortRange{Min: 20000}},
+		{name: "max only above default min", portRange: network.PortRange{Max: 20000}},
+		{name: "max only below default min", portRange: network.PortRange{Max: 9000}, expectErr: true},
+		{name: "min above max", portRange: network.PortRange{Min: 20100, Max: 20000}, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.portRange.Validate()
+			if tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetSeededStartTime(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	startTime := network.GetSeededStartTime(now)
+	require.False(startTime.After(now))
+	// the genesis validations last for at least half their duration
+	require.GreaterOrEqual(startTime.Add(network.SeededInitialStakeDuration).Sub(now), network.SeededInitialStakeDuration/2)
+	// the start time is the same within a window, and moves forward by a window
+	require.Equal(startTime, network.GetSeededStartTime(startTime))
+	require.Equal(startTime, network.GetSeededStartTime(startTime.Add(network.SeededInitialStakeDuration/2-time.Second)))
+	require.Equal(startTime.Add(network.SeededInitialStakeDuration/2), network.GetSeededStartTime(startTime.Add(network.SeededInitialStakeDuration/2)))
+}
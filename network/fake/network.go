@@ -0,0 +1,949 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package fake implements an in-memory network.Network, so tools
+// embedding netrunner can unit test their orchestration logic without
+// spawning node processes.
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/luxdefi/netrunner/api"
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/constants"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	firstAPIPort = 9650
+	snapshotsDir = "/fake/snapshots"
+	// default length of the validations added without end time nor duration
+	validationDuration = 365 * 24 * time.Hour
+)
+
+var (
+	ErrNodeNameTaken      = errors.New("node name already taken")
+	ErrSubnetNotFound     = errors.New("subnet not found")
+	ErrBlockchainNotFound = errors.New("blockchain not found")
+	ErrNotElastic         = errors.New("subnet is not elastic")
+	ErrSnapshotNotFound   = errors.New("snapshot not found")
+	ErrSnapshotExists     = errors.New("snapshot already exists")
+)
+
+var _ network.Network = (*Network)(nil)
+
+// Network is an in-memory network.Network. Its operations update the
+// network state as the local network would, without any node process
+// or transaction involved.
+type Network struct {
+	lock    sync.RWMutex
+	stopped bool
+	// error returned by Healthy
+	healthErr error
+	// returned by GetGenesis
+	genesis []byte
+	nodes   map[string]*Node
+	// next port given to added nodes
+	nextPort uint16
+	// subnet ID --> participant node names
+	subnets map[ids.ID][]string
+	// subnet ID --> elastic subnet ID
+	elasticSubnets map[ids.ID]ids.ID
+	blockchains    map[ids.ID]network.BlockchainSpec
+	// blockchain ID --> aliases
+	blockchainAliases map[ids.ID][]string
+	// VM ID --> aliases
+	vmAliases map[ids.ID][]string
+	// snapshot name --> node configs
+	snapshots map[string][]node.Config
+	// nodes whose validations are being renewed
+	renewingNodes map[string]struct{}
+	// closed to let the renewals held by HoldRenewals go on
+	renewalsHeld chan struct{}
+	// error returned by the renewals
+	renewalErr error
+}
+
+// Returns a healthy network running a node for each of [nodeConfigs].
+func New(nodeConfigs ...node.Config) (*Network, error) {
+	net := &Network{
+		nodes:             map[string]*Node{},
+		nextPort:          firstAPIPort,
+		subnets:           map[ids.ID][]string{},
+		elasticSubnets:    map[ids.ID]ids.ID{},
+		blockchains:       map[ids.ID]network.BlockchainSpec{},
+		blockchainAliases: map[ids.ID][]string{},
+		vmAliases:         map[ids.ID][]string{},
+		snapshots:         map[string][]node.Config{},
+		renewingNodes:     map[string]struct{}{},
+	}
+	for _, nodeConfig := range nodeConfigs {
+		if _, err := net.AddNode(nodeConfig); err != nil {
+			return nil, err
+		}
+	}
+	return net, nil
+}
+
+// SetHealthy sets the error returned by Healthy. A nil error
+// makes the network healthy.
+func (net *Network) SetHealthy(err error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	net.healthErr = err
+}
+
+// SetGenesis sets the genesis returned by GetGenesis.
+func (net *Network) SetGenesis(genesis []byte) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	net.genesis = genesis
+}
+
+// HoldRenewals makes the renewals of the primary network validations
+// started from now on wait, as if the validations hadn't ended, until the
+// returned function is called.
+func (net *Network) HoldRenewals() func() {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	held := make(chan struct{})
+	net.renewalsHeld = held
+	return func() {
+		net.lock.Lock()
+		defer net.lock.Unlock()
+
+		if net.renewalsHeld == held {
+			net.renewalsHeld = nil
+		}
+		close(held)
+	}
+}
+
+// SetRenewalError sets the error returned by the renewals of the primary
+// network validations once the validations end. A nil error makes them
+// succeed.
+func (net *Network) SetRenewalError(err error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	net.renewalErr = err
+}
+
+// SetAPIClient sets the API client of the node [name].
+func (net *Network) SetAPIClient(name string, apiClient api.Client) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	n, err := net.getNode(name)
+	if err != nil {
+		return err
+	}
+	n.lock.Lock()
+	n.apiClient = apiClient
+	n.lock.Unlock()
+	return nil
+}
+
+// Subnets returns the participants of each subnet.
+func (net *Network) Subnets() map[ids.ID][]string {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	subnets := make(map[ids.ID][]string, len(net.subnets))
+	for subnetID, participants := range net.subnets {
+		subnets[subnetID] = append([]string{}, participants...)
+	}
+	return subnets
+}
+
+// Blockchains returns the specs of the created blockchains.
+func (net *Network) Blockchains() map[ids.ID]network.BlockchainSpec {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	return maps.Clone(net.blockchains)
+}
+
+// BlockchainAliases returns the aliases of [blockchainID].
+func (net *Network) BlockchainAliases(blockchainID ids.ID) []string {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	return append([]string{}, net.blockchainAliases[blockchainID]...)
+}
+
+func (net *Network) Healthy(context.Context) error {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	if net.stopped {
+		return network.ErrStopped
+	}
+	return net.healthErr
+}
+
+func (net *Network) Stop(context.Context) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return network.ErrStopped
+	}
+	net.stop()
+	return nil
+}
+
+func (net *Network) StopWithOptions(_ context.Context, opts network.StopOptions) ([]network.NodeStopResult, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	nodeNames := maps.Keys(net.nodes)
+	sort.Slice(nodeNames, func(i, j int) bool {
+		if opts.NonBeaconsFirst {
+			iBeacon := net.nodes[nodeNames[i]].GetConfig().IsBeacon
+			jBeacon := net.nodes[nodeNames[j]].GetConfig().IsBeacon
+			if iBeacon != jBeacon {
+				return jBeacon
+			}
+		}
+		return nodeNames[i] < nodeNames[j]
+	})
+	results := make([]network.NodeStopResult, len(nodeNames))
+	for i, nodeName := range nodeNames {
+		results[i] = network.NodeStopResult{NodeName: nodeName}
+	}
+	net.stop()
+	return results, nil
+}
+
+// Assumes [net.lock] is held.
+func (net *Network) stop() {
+	for _, n := range net.nodes {
+		n.stop()
+	}
+	net.stopped = true
+}
+
+func (net *Network) AddNode(nodeConfig node.Config) (node.Node, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	if nodeConfig.Name == "" {
+		for i := len(net.nodes) + 1; ; i++ {
+			name := fmt.Sprintf("node%d", i)
+			if _, ok := net.nodes[name]; !ok {
+				nodeConfig.Name = name
+				break
+			}
+		}
+	}
+	if _, ok := net.nodes[nodeConfig.Name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNameTaken, nodeConfig.Name)
+	}
+	n := NewNode(nodeConfig, nil, net.nextPort, net.nextPort+1)
+	net.nextPort += 2
+	net.nodes[n.GetName()] = n
+	return n, nil
+}
+
+func (net *Network) RemoveNode(_ context.Context, name string) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	n, err := net.getNode(name)
+	if err != nil {
+		return err
+	}
+	n.stop()
+	delete(net.nodes, name)
+	return nil
+}
+
+// DrainNode removes the node from the participants of the non elastic
+// subnets, and then removes it from the network.
+func (net *Network) DrainNode(_ context.Context, name string) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	n, err := net.getNode(name)
+	if err != nil {
+		return err
+	}
+	for subnetID, participants := range net.subnets {
+		if _, ok := net.elasticSubnets[subnetID]; ok {
+			continue
+		}
+		remaining := []string{}
+		for _, participant := range participants {
+			if participant != name {
+				remaining = append(remaining, participant)
+			}
+		}
+		net.subnets[subnetID] = remaining
+	}
+	n.stop()
+	delete(net.nodes, name)
+	return nil
+}
+
+func (net *Network) PauseNode(_ context.Context, name string) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	n, err := net.getNode(name)
+	if err != nil {
+		return err
+	}
+	n.setPaused(true)
+	return nil
+}
+
+func (net *Network) ResumeNode(_ context.Context, name string) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	n, err := net.getNode(name)
+	if err != nil {
+		return err
+	}
+	n.setPaused(false)
+	return nil
+}
+
+func (net *Network) GetNode(name string) (node.Node, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	return net.getNode(name)
+}
+
+// Assumes [net.lock] is held.
+func (net *Network) getNode(name string) (*Node, error) {
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	n, ok := net.nodes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", network.ErrNodeNotFound, name)
+	}
+	return n, nil
+}
+
+func (net *Network) GetAllNodes() (map[string]node.Node, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	nodes := make(map[string]node.Node, len(net.nodes))
+	for name, n := range net.nodes {
+		nodes[name] = n
+	}
+	return nodes, nil
+}
+
+func (net *Network) GetNodeNames() ([]string, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	names := maps.Keys(net.nodes)
+	sort.Strings(names)
+	return names, nil
+}
+
+func (net *Network) GetGenesis() ([]byte, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	return net.genesis, nil
+}
+
+// SaveSnapshot keeps the node configs under [snapshotName], and
+// stops the network.
+func (net *Network) SaveSnapshot(_ context.Context, snapshotName string) (string, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return "", network.ErrStopped
+	}
+	if _, ok := net.snapshots[snapshotName]; ok {
+		return "", fmt.Errorf("%w: %s", ErrSnapshotExists, snapshotName)
+	}
+	nodeConfigs := make([]node.Config, 0, len(net.nodes))
+	for _, n := range net.nodes {
+		nodeConfigs = append(nodeConfigs, n.GetConfig())
+	}
+	net.snapshots[snapshotName] = nodeConfigs
+	net.stop()
+	return filepath.Join(snapshotsDir, snapshotName), nil
+}
+
+func (net *Network) RemoveSnapshot(snapshotName string) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if _, ok := net.snapshots[snapshotName]; !ok {
+		return fmt.Errorf("%w: %s", ErrSnapshotNotFound, snapshotName)
+	}
+	delete(net.snapshots, snapshotName)
+	return nil
+}
+
+func (net *Network) GetSnapshotNames() ([]string, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	names := maps.Keys(net.snapshots)
+	sort.Strings(names)
+	return names, nil
+}
+
+// RestartNode resumes the node, updating its binary path and chain
+// configs if given. The other arguments are ignored.
+func (net *Network) RestartNode(
+	_ context.Context,
+	name string,
+	binaryPath string,
+	_ string,
+	_ string,
+	chainConfigs map[string]string,
+	_ map[string]string,
+	_ map[string]string,
+	_ bool,
+) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	n, err := net.getNode(name)
+	if err != nil {
+		return err
+	}
+	n.restart(binaryPath, chainConfigs)
+	return nil
+}
+
+func (net *Network) CreateBlockchains(_ context.Context, chainSpecs []network.BlockchainSpec) ([]ids.ID, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	chainIDs := make([]ids.ID, len(chainSpecs))
+	for i, chainSpec := range chainSpecs {
+		var subnetID ids.ID
+		switch {
+		case chainSpec.SubnetID != nil:
+			id, err := net.getSubnetID(*chainSpec.SubnetID)
+			if err != nil {
+				return nil, err
+			}
+			subnetID = id
+		default:
+			subnetSpec := network.SubnetSpec{}
+			if chainSpec.SubnetSpec != nil {
+				subnetSpec = *chainSpec.SubnetSpec
+			}
+			id, err := net.createSubnet(subnetSpec)
+			if err != nil {
+				return nil, err
+			}
+			subnetID = id
+			chainSpec.SubnetID = new(string)
+			*chainSpec.SubnetID = subnetID.String()
+		}
+		chainID := ids.GenerateTestID()
+		net.blockchains[chainID] = chainSpec
+		if chainSpec.BlockchainAlias != "" {
+			net.blockchainAliases[chainID] = append(net.blockchainAliases[chainID], chainSpec.BlockchainAlias)
+		}
+		chainIDs[i] = chainID
+	}
+	return chainIDs, nil
+}
+
+func (net *Network) CreateSubnets(_ context.Context, subnetSpecs []network.SubnetSpec) ([]ids.ID, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	subnetIDs := make([]ids.ID, len(subnetSpecs))
+	for i, subnetSpec := range subnetSpecs {
+		subnetID, err := net.createSubnet(subnetSpec)
+		if err != nil {
+			return nil, err
+		}
+		subnetIDs[i] = subnetID
+	}
+	return subnetIDs, nil
+}
+
+// Assumes [net.lock] is held.
+func (net *Network) createSubnet(subnetSpec network.SubnetSpec) (ids.ID, error) {
+	participants := subnetSpec.Participants
+	if len(participants) == 0 {
+		participants = maps.Keys(net.nodes)
+		sort.Strings(participants)
+	}
+	for _, name := range participants {
+		if _, ok := net.nodes[name]; !ok {
+			return ids.Empty, fmt.Errorf("%w: %s", network.ErrNodeNotFound, name)
+		}
+	}
+	subnetID := ids.GenerateTestID()
+	net.subnets[subnetID] = append([]string{}, participants...)
+	return subnetID, nil
+}
+
+// Assumes [net.lock] is held.
+func (net *Network) getSubnetID(subnetIDStr string) (ids.ID, error) {
+	subnetID, err := ids.FromString(subnetIDStr)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if _, ok := net.subnets[subnetID]; !ok {
+		return ids.Empty, fmt.Errorf("%w: %s", ErrSubnetNotFound, subnetID)
+	}
+	return subnetID, nil
+}
+
+func (net *Network) TransformSubnet(_ context.Context, elasticSubnetSpecs []network.ElasticSubnetSpec) ([]ids.ID, []ids.ID, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return nil, nil, network.ErrStopped
+	}
+	elasticSubnetIDs := make([]ids.ID, len(elasticSubnetSpecs))
+	assetIDs := make([]ids.ID, len(elasticSubnetSpecs))
+	for i, spec := range elasticSubnetSpecs {
+		if spec.SubnetID == nil {
+			return nil, nil, fmt.Errorf("%w: no subnet ID", ErrSubnetNotFound)
+		}
+		subnetID, err := net.getSubnetID(*spec.SubnetID)
+		if err != nil {
+			return nil, nil, err
+		}
+		elasticSubnetIDs[i] = ids.GenerateTestID()
+		assetIDs[i] = ids.GenerateTestID()
+		net.elasticSubnets[subnetID] = elasticSubnetIDs[i]
+	}
+	return elasticSubnetIDs, assetIDs, nil
+}
+
+func (net *Network) AddPermissionlessValidators(_ context.Context, validatorSpecs []network.PermissionlessValidatorSpec) ([]network.AddedPermissionlessValidator, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	added := make([]network.AddedPermissionlessValidator, 0, len(validatorSpecs))
+	for _, spec := range validatorSpecs {
+		if _, ok := net.nodes[spec.NodeName]; !ok {
+			return nil, fmt.Errorf("%w: %s", network.ErrNodeNotFound, spec.NodeName)
+		}
+		now := time.Now()
+		if err := spec.Validate(now); err != nil {
+			return nil, err
+		}
+		defaultEndTime := now.Add(validationDuration)
+		if !spec.StartTime.IsZero() {
+			defaultEndTime = spec.StartTime.Add(validationDuration)
+		}
+		startTime, endTime, err := spec.GetValidationPeriod(now, defaultEndTime)
+		if err != nil {
+			return nil, err
+		}
+		subnetID := constants.PrimaryNetworkID
+		// an empty subnet ID is a primary network validation
+		if spec.SubnetID != "" {
+			subnetID, err = net.getSubnetID(spec.SubnetID)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := net.elasticSubnets[subnetID]; !ok {
+				return nil, fmt.Errorf("%w: %s", ErrNotElastic, subnetID)
+			}
+			net.subnets[subnetID] = append(net.subnets[subnetID], spec.NodeName)
+		}
+		added = append(added, network.AddedPermissionlessValidator{
+			NodeName:  spec.NodeName,
+			SubnetID:  subnetID,
+			TxID:      ids.GenerateTestID(),
+			StartTime: startTime,
+			EndTime:   endTime,
+			Weight:    spec.StakedAmount,
+		})
+	}
+	return added, nil
+}
+
+func (net *Network) RemoveSubnetValidators(_ context.Context, validatorSpecs []network.RemoveSubnetValidatorSpec, _ bool) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return network.ErrStopped
+	}
+	for _, spec := range validatorSpecs {
+		subnetID, err := net.getSubnetID(spec.SubnetID)
+		if err != nil {
+			return err
+		}
+		removed := map[string]bool{}
+		for _, name := range spec.NodeNames {
+			removed[name] = true
+		}
+		participants := []string{}
+		for _, name := range net.subnets[subnetID] {
+			if !removed[name] {
+				participants = append(participants, name)
+			}
+		}
+		net.subnets[subnetID] = participants
+	}
+	return nil
+}
+
+func (net *Network) AddBlockchainAlias(_ context.Context, blockchainID ids.ID, alias string) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return network.ErrStopped
+	}
+	if _, ok := net.blockchains[blockchainID]; !ok {
+		return fmt.Errorf("%w: %s", ErrBlockchainNotFound, blockchainID)
+	}
+	net.blockchainAliases[blockchainID] = append(net.blockchainAliases[blockchainID], alias)
+	return nil
+}
+
+func (net *Network) RemoveBlockchainAlias(_ context.Context, blockchainID ids.ID, alias string) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return network.ErrStopped
+	}
+	if _, ok := net.blockchains[blockchainID]; !ok {
+		return fmt.Errorf("%w: %s", ErrBlockchainNotFound, blockchainID)
+	}
+	aliases := []string{}
+	for _, a := range net.blockchainAliases[blockchainID] {
+		if a != alias {
+			aliases = append(aliases, a)
+		}
+	}
+	net.blockchainAliases[blockchainID] = aliases
+	return nil
+}
+
+func (net *Network) AddVMAlias(_ context.Context, vmID ids.ID, alias string) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return network.ErrStopped
+	}
+	net.vmAliases[vmID] = append(net.vmAliases[vmID], alias)
+	return nil
+}
+
+func (net *Network) TransferSubnetOwnership(_ context.Context, transferSpecs []network.TransferSubnetOwnershipSpec) ([]ids.ID, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	txIDs := make([]ids.ID, len(transferSpecs))
+	for i, spec := range transferSpecs {
+		if _, err := net.getSubnetID(spec.SubnetID); err != nil {
+			return nil, err
+		}
+		txIDs[i] = ids.GenerateTestID()
+	}
+	return txIDs, nil
+}
+
+func (net *Network) GetElasticSubnetID(_ context.Context, subnetID ids.ID) (ids.ID, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	if net.stopped {
+		return ids.Empty, network.ErrStopped
+	}
+	elasticSubnetID, ok := net.elasticSubnets[subnetID]
+	if !ok {
+		return ids.Empty, fmt.Errorf("%w: %s", ErrNotElastic, subnetID)
+	}
+	return elasticSubnetID, nil
+}
+
+// GetElasticSubnet returns an empty configuration and staking pool
+func (net *Network) GetElasticSubnet(_ context.Context, subnetID ids.ID) (network.ElasticSubnet, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	if net.stopped {
+		return network.ElasticSubnet{}, network.ErrStopped
+	}
+	elasticSubnetID, ok := net.elasticSubnets[subnetID]
+	if !ok {
+		return network.ElasticSubnet{}, fmt.Errorf("%w: %s", ErrNotElastic, subnetID)
+	}
+	subnetIDStr := subnetID.String()
+	return network.ElasticSubnet{
+		ElasticSubnetID: elasticSubnetID,
+		Spec:            network.ElasticSubnetSpec{SubnetID: &subnetIDStr},
+	}, nil
+}
+
+// WaitForValidatorRewards returns at once, as if the validation just
+// ended with no rewards.
+func (net *Network) WaitForValidatorRewards(_ context.Context, nodeName string, subnetID ids.ID) (network.ValidatorRewards, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	if _, err := net.getNode(nodeName); err != nil {
+		return network.ValidatorRewards{}, err
+	}
+	if subnetID != ids.Empty {
+		if _, ok := net.subnets[subnetID]; !ok {
+			return network.ValidatorRewards{}, fmt.Errorf("%w: %s", ErrSubnetNotFound, subnetID)
+		}
+	}
+	return network.ValidatorRewards{
+		NodeName: nodeName,
+		SubnetID: subnetID,
+		TxID:     ids.GenerateTestID(),
+		EndTime:  time.Now(),
+	}, nil
+}
+
+// GetStakingRewards returns the nodes as validators with no delegators
+// nor rewards.
+func (net *Network) GetStakingRewards(_ context.Context, subnetID ids.ID) ([]network.ValidatorStake, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+
+	if net.stopped {
+		return nil, network.ErrStopped
+	}
+	nodeNames := maps.Keys(net.nodes)
+	if subnetID != ids.Empty {
+		participants, ok := net.subnets[subnetID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrSubnetNotFound, subnetID)
+		}
+		nodeNames = append([]string{}, participants...)
+	}
+	sort.Strings(nodeNames)
+	validators := make([]network.ValidatorStake, 0, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		node, ok := net.nodes[nodeName]
+		if !ok {
+			continue
+		}
+		validators = append(validators, network.ValidatorStake{
+			NodeName: nodeName,
+			NodeID:   node.GetNodeID(),
+			TxID:     ids.GenerateTestID(),
+		})
+	}
+	return validators, nil
+}
+
+// ScaleNetwork adds or removes nodes so the network has [numNodes] nodes,
+// removing the last added ones first.
+func (net *Network) ScaleNetwork(_ context.Context, numNodes uint32) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return network.ErrStopped
+	}
+	names := maps.Keys(net.nodes)
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	if int(numNodes) < len(names) {
+		for _, name := range names[numNodes:] {
+			net.nodes[name].stop()
+			delete(net.nodes, name)
+		}
+	}
+	for i := len(names) + 1; len(net.nodes) < int(numNodes); i++ {
+		name := fmt.Sprintf("node%d", i)
+		if _, ok := net.nodes[name]; ok {
+			continue
+		}
+		net.nodes[name] = NewNode(node.Config{Name: name}, nil, net.nextPort, net.nextPort+1)
+		net.nextPort += 2
+	}
+	return nil
+}
+
+// ResetNetwork restarts all the nodes and removes the subnets and
+// blockchains, as they are created after genesis.
+func (net *Network) ResetNetwork(context.Context) error {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return network.ErrStopped
+	}
+	for _, n := range net.nodes {
+		n.restart("", nil)
+	}
+	net.subnets = map[ids.ID][]string{}
+	net.elasticSubnets = map[ids.ID]ids.ID{}
+	net.blockchains = map[ids.ID]network.BlockchainSpec{}
+	net.blockchainAliases = map[ids.ID][]string{}
+	return nil
+}
+
+func (net *Network) CreateAsset(_ context.Context, assetSpec network.AssetSpec) (ids.ID, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return ids.Empty, network.ErrStopped
+	}
+	if len(assetSpec.Holders) == 0 {
+		return ids.Empty, errors.New("no asset holders given")
+	}
+	return ids.GenerateTestID(), nil
+}
+
+func (net *Network) Transfer(_ context.Context, transferSpec network.TransferSpec) (ids.ID, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return ids.Empty, network.ErrStopped
+	}
+	if transferSpec.Amount == 0 {
+		return ids.Empty, errors.New("zero transfer amount")
+	}
+	return ids.GenerateTestID(), nil
+}
+
+func (net *Network) Stake(_ context.Context, stakeSpec network.StakeSpec) (ids.ID, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return ids.Empty, network.ErrStopped
+	}
+	if _, ok := net.nodes[stakeSpec.NodeName]; !ok {
+		return ids.Empty, network.ErrNodeNotFound
+	}
+	return ids.GenerateTestID(), nil
+}
+
+// RenewPrimaryValidations marks the nodes as being renewed, and returns
+// a function renewing the validations at once, as if they just ended,
+// unless HoldRenewals was called.
+func (net *Network) RenewPrimaryValidations(_ context.Context, renewSpec network.RenewValidationSpec) ([]network.AddedPermissionlessValidator, network.RenewFunc, error) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+
+	if net.stopped {
+		return nil, nil, network.ErrStopped
+	}
+	nodeNames := renewSpec.NodeNames
+	if len(nodeNames) == 0 {
+		nodeNames = maps.Keys(net.nodes)
+		sort.Strings(nodeNames)
+	}
+	duration := renewSpec.Duration
+	if duration == 0 {
+		duration = validationDuration
+	}
+	startTime := time.Now()
+	expected := make([]network.AddedPermissionlessValidator, len(nodeNames))
+	for i, nodeName := range nodeNames {
+		if _, err := net.getNode(nodeName); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := net.renewingNodes[nodeName]; ok {
+			return nil, nil, fmt.Errorf("%w for node %s", network.ErrRenewalInProgress, nodeName)
+		}
+		expected[i] = network.AddedPermissionlessValidator{
+			NodeName:  nodeName,
+			SubnetID:  constants.PrimaryNetworkID,
+			StartTime: startTime,
+			EndTime:   startTime.Add(duration),
+			Weight:    renewSpec.Amount,
+		}
+	}
+	for _, nodeName := range nodeNames {
+		net.renewingNodes[nodeName] = struct{}{}
+	}
+	held := net.renewalsHeld
+
+	renew := func(ctx context.Context) ([]network.AddedPermissionlessValidator, error) {
+		defer func() {
+			net.lock.Lock()
+			defer net.lock.Unlock()
+
+			for _, nodeName := range nodeNames {
+				delete(net.renewingNodes, nodeName)
+			}
+		}()
+		if held != nil {
+			select {
+			case <-held:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		net.lock.RLock()
+		err := net.renewalErr
+		net.lock.RUnlock()
+		if err != nil {
+			return nil, err
+		}
+		renewed := make([]network.AddedPermissionlessValidator, len(expected))
+		for i, v := range expected {
+			v.TxID = ids.GenerateTestID()
+			renewed[i] = v
+		}
+		return renewed, nil
+	}
+	return expected, renew, nil
+}
@@ -0,0 +1,142 @@
+package fake_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/network/fake"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/network/node/status"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetwork(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	net, err := fake.New(node.Config{Name: "node1"}, node.Config{Name: "node2"}, node.Config{})
+	require.NoError(err)
+	require.NoError(net.Healthy(ctx))
+
+	names, err := net.GetNodeNames()
+	require.NoError(err)
+	require.Equal([]string{"node1", "node2", "node3"}, names)
+
+	_, err = net.AddNode(node.Config{Name: "node1"})
+	require.ErrorIs(err, fake.ErrNodeNameTaken)
+
+	require.NoError(net.PauseNode(ctx, "node2"))
+	node2, err := net.GetNode("node2")
+	require.NoError(err)
+	require.True(node2.GetPaused())
+	require.Equal(status.Stopped, node2.Status())
+	require.NoError(net.RestartNode(ctx, "node2", "/tmp/luxd", "", "", nil, nil, nil, false))
+	require.False(node2.GetPaused())
+	require.Equal("/tmp/luxd", node2.GetBinaryPath())
+
+	require.NoError(net.RemoveNode(ctx, "node3"))
+	_, err = net.GetNode("node3")
+	require.ErrorIs(err, network.ErrNodeNotFound)
+
+	subnetIDs, err := net.CreateSubnets(ctx, []network.SubnetSpec{{Participants: []string{"node1"}}, {}})
+	require.NoError(err)
+	require.Len(subnetIDs, 2)
+	require.Equal([]string{"node1"}, net.Subnets()[subnetIDs[0]])
+	require.Equal([]string{"node1", "node2"}, net.Subnets()[subnetIDs[1]])
+
+	_, err = net.CreateSubnets(ctx, []network.SubnetSpec{{Participants: []string{"node3"}}})
+	require.ErrorIs(err, network.ErrNodeNotFound)
+
+	subnetID := subnetIDs[0].String()
+	chainIDs, err := net.CreateBlockchains(ctx, []network.BlockchainSpec{{VMName: "vm", SubnetID: &subnetID, BlockchainAlias: "chain"}})
+	require.NoError(err)
+	require.Len(chainIDs, 1)
+	require.Equal([]string{"chain"}, net.BlockchainAliases(chainIDs[0]))
+
+	require.NoError(net.Stop(ctx))
+	require.ErrorIs(net.Stop(ctx), network.ErrStopped)
+	require.ErrorIs(net.Healthy(ctx), network.ErrStopped)
+	_, err = net.GetNodeNames()
+	require.ErrorIs(err, network.ErrStopped)
+}
+
+func TestScaleNetwork(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	net, err := fake.New(node.Config{}, node.Config{})
+	require.NoError(err)
+
+	require.NoError(net.ScaleNetwork(ctx, 11))
+	names, err := net.GetNodeNames()
+	require.NoError(err)
+	require.Len(names, 11)
+	_, err = net.GetNode("node11")
+	require.NoError(err)
+
+	require.NoError(net.ScaleNetwork(ctx, 1))
+	names, err = net.GetNodeNames()
+	require.NoError(err)
+	require.Equal([]string{"node1"}, names)
+
+	require.NoError(net.Stop(ctx))
+	require.ErrorIs(net.ScaleNetwork(ctx, 2), network.ErrStopped)
+}
+
+func TestDrainNode(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	net, err := fake.New(node.Config{}, node.Config{})
+	require.NoError(err)
+
+	subnetIDs, err := net.CreateSubnets(ctx, []network.SubnetSpec{{}})
+	require.NoError(err)
+	require.NoError(net.DrainNode(ctx, "node1"))
+	require.Equal([]string{"node2"}, net.Subnets()[subnetIDs[0]])
+	_, err = net.GetNode("node1")
+	require.ErrorIs(err, network.ErrNodeNotFound)
+	require.ErrorIs(net.DrainNode(ctx, "node1"), network.ErrNodeNotFound)
+}
+
+func TestStopWithOptions(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	net, err := fake.New(node.Config{IsBeacon: true}, node.Config{})
+	require.NoError(err)
+
+	results, err := net.StopWithOptions(ctx, network.StopOptions{NonBeaconsFirst: true})
+	require.NoError(err)
+	require.Len(results, 2)
+	require.Equal("node2", results[0].NodeName)
+	require.Equal("node1", results[1].NodeName)
+	_, err = net.StopWithOptions(ctx, network.StopOptions{})
+	require.ErrorIs(err, network.ErrStopped)
+}
+
+func TestResetNetwork(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	net, err := fake.New(node.Config{}, node.Config{})
+	require.NoError(err)
+
+	subnetIDs, err := net.CreateSubnets(ctx, []network.SubnetSpec{{}})
+	require.NoError(err)
+	subnetID := subnetIDs[0].String()
+	_, err = net.CreateBlockchains(ctx, []network.BlockchainSpec{{VMName: "vm", SubnetID: &subnetID}})
+	require.NoError(err)
+	require.NoError(net.PauseNode(ctx, "node1"))
+
+	require.NoError(net.ResetNetwork(ctx))
+	require.Empty(net.Subnets())
+	require.Empty(net.Blockchains())
+	node1, err := net.GetNode("node1")
+	require.NoError(err)
+	require.False(node1.GetPaused())
+
+	require.NoError(net.Stop(ctx))
+	require.ErrorIs(net.ResetNetwork(ctx), network.ErrStopped)
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/luxdefi/netrunner/api"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/network/node/status"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/network/peer"
+	"github.com/luxdefi/node/snow/networking/router"
+)
+
+var ErrNotSupported = errors.New("not supported by the fake network")
+
+var _ node.Node = (*Node)(nil)
+
+// Node is an in-memory node.Node. It has no process, so the
+// operations that need one return ErrNotSupported.
+type Node struct {
+	lock      sync.RWMutex
+	name      string
+	nodeID    ids.NodeID
+	config    node.Config
+	apiClient api.Client
+	apiPort   uint16
+	p2pPort   uint16
+	dataDir   string
+	paused    bool
+	stopped   bool
+}
+
+// Returns a running node with the given config, a random node ID,
+// and [apiClient] (which may be nil) as API client.
+func NewNode(config node.Config, apiClient api.Client, apiPort uint16, p2pPort uint16) *Node {
+	return &Node{
+		name:      config.Name,
+		nodeID:    ids.GenerateTestNodeID(),
+		config:    config,
+		apiClient: apiClient,
+		apiPort:   apiPort,
+		p2pPort:   p2pPort,
+		dataDir:   filepath.Join("/fake", config.Name),
+	}
+}
+
+func (n *Node) GetName() string {
+	return n.name
+}
+
+func (n *Node) GetNodeID() ids.NodeID {
+	return n.nodeID
+}
+
+func (n *Node) GetAPIClient() api.Client {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.apiClient
+}
+
+func (*Node) GetURL() string {
+	return "127.0.0.1"
+}
+
+func (n *Node) GetP2PPort() uint16 {
+	return n.p2pPort
+}
+
+func (n *Node) GetAPIPort() uint16 {
+	return n.apiPort
+}
+
+func (*Node) AttachPeer(context.Context, router.InboundHandler, *node.PeerBehavior) (peer.Peer, error) {
+	return nil, ErrNotSupported
+}
+
+func (*Node) SendOutboundMessage(context.Context, string, []byte, uint32) (bool, error) {
+	return false, ErrNotSupported
+}
+
+func (*Node) RecordPeerMessages(string, string) error {
+	return ErrNotSupported
+}
+
+func (*Node) ReplayPeerMessages(context.Context, string, string, bool) (uint32, error) {
+	return 0, ErrNotSupported
+}
+
+func (*Node) SubscribePeerMessages(string) (<-chan node.PeerMessage, func(), error) {
+	return nil, nil, ErrNotSupported
+}
+
+func (n *Node) Status() status.Status {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	if n.stopped || n.paused {
+		return status.Stopped
+	}
+	return status.Running
+}
+
+func (n *Node) GetBinaryPath() string {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.config.BinaryPath
+}
+
+func (n *Node) GetDataDir() string {
+	return n.dataDir
+}
+
+func (n *Node) GetDbDir() string {
+	return filepath.Join(n.dataDir, "db")
+}
+
+func (n *Node) GetLogsDir() string {
+	return filepath.Join(n.dataDir, "logs")
+}
+
+func (n *Node) GetPluginDir() string {
+	return filepath.Join(n.dataDir, "plugins")
+}
+
+func (n *Node) GetConfigFile() string {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.config.ConfigFile
+}
+
+func (n *Node) GetConfig() node.Config {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.config
+}
+
+// Returns the value of flag [k] in the node config flags, or the
+// empty string if it is not set.
+func (n *Node) GetFlag(k string) (string, error) {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	v, ok := n.config.Flags[k]
+	if !ok {
+		return "", nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+func (n *Node) GetPaused() bool {
+	n.lock.RLock()
+	defer n.lock.RUnlock()
+
+	return n.paused
+}
+
+func (*Node) GetPID() int {
+	return 0
+}
+
+func (*Node) GetArgs() []string {
+	return nil
+}
+
+func (n *Node) setPaused(paused bool) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.paused = paused
+}
+
+func (n *Node) stop() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.stopped = true
+}
+
+func (n *Node) restart(binaryPath string, chainConfigs map[string]string) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if binaryPath != "" {
+		n.config.BinaryPath = binaryPath
+	}
+	if len(chainConfigs) > 0 {
+		// the configs returned before are not modified
+		chainConfigFiles := make(map[string]string, len(n.config.ChainConfigFiles)+len(chainConfigs))
+		for k, v := range n.config.ChainConfigFiles {
+			chainConfigFiles[k] = v
+		}
+		for k, v := range chainConfigs {
+			chainConfigFiles[k] = v
+		}
+		n.config.ChainConfigFiles = chainConfigFiles
+	}
+	n.paused = false
+	n.stopped = false
+}
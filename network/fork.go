@@ -0,0 +1,122 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/luxdefi/coreth/ethclient"
+	"github.com/luxdefi/coreth/rpc"
+	"github.com/luxdefi/node/utils/set"
+	"golang.org/x/exp/maps"
+)
+
+// DefaultForkNetworkID is the network ID of forked networks, if not given.
+// The local network ID can't be used, as nodes ignore the given genesis
+// for standard networks.
+const DefaultForkNetworkID uint32 = 1338
+
+var ErrStandardForkNetworkID = errors.New("forked network ID can't be mainnet, testnet or local network ID")
+
+// ForkSpec defines the state of a remote C-Chain copied into the genesis
+// of a new network ("forking"). Only the given accounts are copied, as
+// the whole state can't be read over RPC. The P-Chain state (validators,
+// subnets) can't be forked, as it isn't part of the C-Chain genesis.
+type ForkSpec struct {
+	// C-Chain RPC endpoint of the remote network, e.g. an archive node
+	RPCURL string
+	// Height the state is read at, the latest block if zero
+	BlockNumber uint64
+	// Accounts whose balance, nonce and code are copied
+	Addresses []common.Address
+	// Storage slots copied, by account. The accounts are copied too
+	StorageSlots map[common.Address][]common.Hash
+	// Network ID of the forked network, DefaultForkNetworkID if zero
+	NetworkID uint32
+}
+
+// ForkStateReader reads the state of C-Chain accounts, e.g. an ethclient.Client
+type ForkStateReader interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// ForkGenesis returns [genesis] with the C-Chain state of the accounts of
+// [spec] read from [spec.RPCURL], and the network ID of [spec]
+func ForkGenesis(ctx context.Context, genesis string, spec ForkSpec) (string, error) {
+	rpcClient, err := rpc.DialContext(ctx, spec.RPCURL)
+	if err != nil {
+		return "", fmt.Errorf("couldn't connect to %s: %w", spec.RPCURL, err)
+	}
+	defer rpcClient.Close()
+	return ForkGenesisFromReader(ctx, ethclient.NewClient(rpcClient), genesis, spec)
+}
+
+// ForkGenesisFromReader returns [genesis] with the C-Chain state of the
+// accounts of [spec] read from [reader], and the network ID of [spec]
+func ForkGenesisFromReader(ctx context.Context, reader ForkStateReader, genesis string, spec ForkSpec) (string, error) {
+	var blockNumber *big.Int
+	if spec.BlockNumber != 0 {
+		blockNumber = new(big.Int).SetUint64(spec.BlockNumber)
+	}
+	addresses := set.Set[common.Address]{}
+	addresses.Add(spec.Addresses...)
+	addresses.Add(maps.Keys(spec.StorageSlots)...)
+	alloc := CChainAlloc{}
+	for addr := range addresses {
+		account, err := readForkAccount(ctx, reader, addr, spec.StorageSlots[addr], blockNumber)
+		if err != nil {
+			return "", fmt.Errorf("couldn't read account %s: %w", addr, err)
+		}
+		alloc[addr] = account
+	}
+	return ImportCChainAlloc(genesis, alloc, spec.NetworkID)
+}
+
+// Returns the genesis alloc entry of [addr], with its storage at [slots]
+func readForkAccount(
+	ctx context.Context,
+	reader ForkStateReader,
+	addr common.Address,
+	slots []common.Hash,
+	blockNumber *big.Int,
+) (map[string]interface{}, error) {
+	balance, err := reader.BalanceAt(ctx, addr, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	account := map[string]interface{}{
+		"balance": hexutil.EncodeBig(balance),
+	}
+	nonce, err := reader.NonceAt(ctx, addr, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if nonce != 0 {
+		account["nonce"] = hexutil.EncodeUint64(nonce)
+	}
+	code, err := reader.CodeAt(ctx, addr, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) != 0 {
+		account["code"] = hexutil.Encode(code)
+	}
+	if len(slots) != 0 {
+		storage := map[string]string{}
+		for _, slot := range slots {
+			value, err := reader.StorageAt(ctx, addr, slot, blockNumber)
+			if err != nil {
+				return nil, err
+			}
+			storage[slot.Hex()] = common.BytesToHash(value).Hex()
+		}
+		account["storage"] = storage
+	}
+	return account, nil
+}
@@ -0,0 +1,113 @@
+package network_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/stretchr/testify/require"
+)
+
+type forkAccount struct {
+	balance *big.Int
+	nonce   uint64
+	code    []byte
+	storage map[common.Hash][]byte
+}
+
+type fakeForkStateReader struct {
+	accounts map[common.Address]forkAccount
+	err      error
+}
+
+func (r *fakeForkStateReader) BalanceAt(_ context.Context, account common.Address, _ *big.Int) (*big.Int, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if a, ok := r.accounts[account]; ok {
+		return a.balance, nil
+	}
+	return new(big.Int), nil
+}
+
+func (r *fakeForkStateReader) NonceAt(_ context.Context, account common.Address, _ *big.Int) (uint64, error) {
+	return r.accounts[account].nonce, nil
+}
+
+func (r *fakeForkStateReader) CodeAt(_ context.Context, account common.Address, _ *big.Int) ([]byte, error) {
+	return r.accounts[account].code, nil
+}
+
+func (r *fakeForkStateReader) StorageAt(_ context.Context, account common.Address, key common.Hash, _ *big.Int) ([]byte, error) {
+	return r.accounts[account].storage[key], nil
+}
+
+const forkTestGenesis = `{"networkID":1337,"cChainGenesis":"{\"config\":{\"chainId\":43112},\"alloc\":{\"8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC\":{\"balance\":\"0x1\"},\"0x1111111111111111111111111111111111111111\":{\"balance\":\"0x2\"}}}"}`
+
+func TestForkGenesis(t *testing.T) {
+	require := require.New(t)
+
+	contract := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	slot := common.HexToHash("0x1")
+	reader := &fakeForkStateReader{
+		accounts: map[common.Address]forkAccount{
+			contract: {
+				balance: big.NewInt(100),
+				nonce:   3,
+				code:    []byte{0x60, 0x80},
+				storage: map[common.Hash][]byte{slot: {0x2a}},
+			},
+		},
+	}
+	genesis, err := network.ForkGenesisFromReader(context.Background(), reader, forkTestGenesis, network.ForkSpec{
+		StorageSlots: map[common.Address][]common.Hash{contract: {slot}},
+	})
+	require.NoError(err)
+
+	var genesisMap map[string]interface{}
+	require.NoError(json.Unmarshal([]byte(genesis), &genesisMap))
+	require.EqualValues(network.DefaultForkNetworkID, genesisMap["networkID"])
+
+	var cChainGenesis struct {
+		Alloc map[string]map[string]interface{} `json:"alloc"`
+	}
+	require.NoError(json.Unmarshal([]byte(genesisMap["cChainGenesis"].(string)), &cChainGenesis))
+	// the existing entry of the contract is replaced, the others are kept
+	require.Len(cChainGenesis.Alloc, 2)
+	require.Contains(cChainGenesis.Alloc, "0x1111111111111111111111111111111111111111")
+	account := cChainGenesis.Alloc["0x8db97c7cece249c2b98bdc0226cc4c2a57bf52fc"]
+	require.Equal("0x64", account["balance"])
+	require.Equal("0x3", account["nonce"])
+	require.Equal("0x6080", account["code"])
+	require.Equal(map[string]interface{}{
+		slot.Hex(): common.BytesToHash([]byte{0x2a}).Hex(),
+	}, account["storage"])
+}
+
+func TestForkGenesisNetworkID(t *testing.T) {
+	require := require.New(t)
+
+	reader := &fakeForkStateReader{}
+	_, err := network.ForkGenesisFromReader(context.Background(), reader, forkTestGenesis, network.ForkSpec{NetworkID: constants.LocalID})
+	require.ErrorIs(err, network.ErrStandardForkNetworkID)
+
+	genesis, err := network.ForkGenesisFromReader(context.Background(), reader, forkTestGenesis, network.ForkSpec{NetworkID: 5555})
+	require.NoError(err)
+	var genesisMap map[string]interface{}
+	require.NoError(json.Unmarshal([]byte(genesis), &genesisMap))
+	require.EqualValues(5555, genesisMap["networkID"])
+}
+
+func TestForkGenesisReadError(t *testing.T) {
+	errRead := errors.New("read error")
+	reader := &fakeForkStateReader{err: errRead}
+	_, err := network.ForkGenesisFromReader(context.Background(), reader, forkTestGenesis, network.ForkSpec{
+		Addresses: []common.Address{common.HexToAddress("0x1")},
+	})
+	require.ErrorIs(t, err, errRead)
+}
@@ -3,14 +3,21 @@ package network
 import (
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	coreth_params "github.com/luxdefi/coreth/params"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/formatting/address"
+	"github.com/luxdefi/node/utils/set"
 )
 
 //go:embed default/genesis.json
 var genesisBytes []byte
 
+var ErrReservedNetworkID = errors.New("network ID can't be zero, mainnet, testnet or local network ID")
+
 // LoadLocalGenesis loads the local network genesis from disk
 // and returns it as a map[string]interface{}
 func LoadLocalGenesis() (map[string]interface{}, error) {
@@ -45,3 +52,108 @@ func LoadLocalGenesis() (map[string]interface{}, error) {
 	genesisMap["cChainGenesis"] = string(configBytes)
 	return genesisMap, nil
 }
+
+// CheckNetworkID returns an error if [networkID] can't be the ID of a new
+// network, as nodes ignore the given genesis for standard networks.
+func CheckNetworkID(networkID uint32) error {
+	switch networkID {
+	case 0, constants.MainnetID, constants.TestnetID, constants.LocalID:
+		return fmt.Errorf("%w: %d", ErrReservedNetworkID, networkID)
+	}
+	return nil
+}
+
+// SetGenesisNetworkID returns [genesis] with network ID [networkID], and
+// its X-Chain addresses formatted with the HRP of [networkID].
+func SetGenesisNetworkID(genesis string, networkID uint32) (string, error) {
+	var genesisMap map[string]interface{}
+	if err := json.Unmarshal([]byte(genesis), &genesisMap); err != nil {
+		return "", fmt.Errorf("couldn't unmarshal genesis: %w", err)
+	}
+	if err := setGenesisMapNetworkID(genesisMap, networkID); err != nil {
+		return "", err
+	}
+	genesisBytes, err := json.Marshal(genesisMap)
+	if err != nil {
+		return "", err
+	}
+	return string(genesisBytes), nil
+}
+
+// GetGenesisAllocatedAddrs returns the addresses of the allocations of
+// [genesis], the ones with funds when the network starts.
+func GetGenesisAllocatedAddrs(genesis []byte) (set.Set[ids.ShortID], error) {
+	var genesisMap struct {
+		Allocations []struct {
+			LuxAddr string `json:"luxAddr"`
+		} `json:"allocations"`
+	}
+	if err := json.Unmarshal(genesis, &genesisMap); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal genesis: %w", err)
+	}
+	addrs := set.Set[ids.ShortID]{}
+	for _, alloc := range genesisMap.Allocations {
+		_, _, addrBytes, err := address.Parse(alloc.LuxAddr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse genesis address %q: %w", alloc.LuxAddr, err)
+		}
+		addr, err := ids.ToShortID(addrBytes)
+		if err != nil {
+			return nil, err
+		}
+		addrs.Add(addr)
+	}
+	return addrs, nil
+}
+
+// Sets the network ID of [genesisMap] to [networkID], and formats the
+// addresses of its allocations and initial stakers with its HRP.
+func setGenesisMapNetworkID(genesisMap map[string]interface{}, networkID uint32) error {
+	hrp := constants.GetHRP(networkID)
+	formatAddr := func(addrIntf interface{}) (string, error) {
+		addr, ok := addrIntf.(string)
+		if !ok {
+			return "", fmt.Errorf("expected genesis address to be a string, but got %T", addrIntf)
+		}
+		chainAlias, _, addrBytes, err := address.Parse(addr)
+		if err != nil {
+			return "", fmt.Errorf("couldn't parse genesis address %q: %w", addr, err)
+		}
+		return address.Format(chainAlias, hrp, addrBytes)
+	}
+
+	genesisMap["networkID"] = networkID
+	allocations, _ := genesisMap["allocations"].([]interface{})
+	for _, allocIntf := range allocations {
+		alloc, ok := allocIntf.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected type for allocation in genesis. got %T", allocIntf)
+		}
+		addr, err := formatAddr(alloc["luxAddr"])
+		if err != nil {
+			return err
+		}
+		alloc["luxAddr"] = addr
+	}
+	stakedFunds, _ := genesisMap["initialStakedFunds"].([]interface{})
+	for i, addrIntf := range stakedFunds {
+		addr, err := formatAddr(addrIntf)
+		if err != nil {
+			return err
+		}
+		stakedFunds[i] = addr
+	}
+	stakers, _ := genesisMap["initialStakers"].([]interface{})
+	for _, stakerIntf := range stakers {
+		staker, ok := stakerIntf.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected type for initial staker in genesis. got %T", stakerIntf)
+		}
+		addr, err := formatAddr(staker["rewardAddress"])
+		if err != nil {
+			return err
+		}
+		staker["rewardAddress"] = addr
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package network_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/formatting/address"
+	"github.com/luxdefi/node/utils/set"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckNetworkID(t *testing.T) {
+	require := require.New(t)
+
+	for _, networkID := range []uint32{0, constants.MainnetID, constants.TestnetID, constants.LocalID} {
+		require.ErrorIs(network.CheckNetworkID(networkID), network.ErrReservedNetworkID)
+	}
+	require.NoError(network.CheckNetworkID(1337))
+	require.NoError(network.CheckNetworkID(2000))
+}
+
+func TestSetGenesisNetworkID(t *testing.T) {
+	require := require.New(t)
+
+	addr := ids.GenerateTestShortID()
+	localAddr, err := address.Format("X", constants.GetHRP(constants.LocalID), addr[:])
+	require.NoError(err)
+	genesis := `{
+		"networkID": 12345,
+		"allocations": [{"luxAddr": "` + localAddr + `", "initialAmount": 1}],
+		"initialStakedFunds": ["` + localAddr + `"],
+		"initialStakers": [{"nodeID": "NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg", "rewardAddress": "` + localAddr + `"}]
+	}`
+
+	genesis, err = network.SetGenesisNetworkID(genesis, 2000)
+	require.NoError(err)
+
+	expectedAddr, err := address.Format("X", constants.GetHRP(2000), addr[:])
+	require.NoError(err)
+	var genesisMap struct {
+		NetworkID   uint32 `json:"networkID"`
+		Allocations []struct {
+			LuxAddr string `json:"luxAddr"`
+		} `json:"allocations"`
+		InitialStakedFunds []string `json:"initialStakedFunds"`
+		InitialStakers     []struct {
+			RewardAddress string `json:"rewardAddress"`
+		} `json:"initialStakers"`
+	}
+	require.NoError(json.Unmarshal([]byte(genesis), &genesisMap))
+	require.Equal(uint32(2000), genesisMap.NetworkID)
+	require.Equal(expectedAddr, genesisMap.Allocations[0].LuxAddr)
+	require.Equal([]string{expectedAddr}, genesisMap.InitialStakedFunds)
+	require.Equal(expectedAddr, genesisMap.InitialStakers[0].RewardAddress)
+}
+
+func TestGetGenesisAllocatedAddrs(t *testing.T) {
+	require := require.New(t)
+
+	addr := ids.GenerateTestShortID()
+	localAddr, err := address.Format("X", constants.GetHRP(constants.LocalID), addr[:])
+	require.NoError(err)
+
+	addrs, err := network.GetGenesisAllocatedAddrs([]byte(`{"allocations": [{"luxAddr": "` + localAddr + `", "initialAmount": 1}]}`))
+	require.NoError(err)
+	require.Equal(set.Set[ids.ShortID]{addr: struct{}{}}, addrs)
+
+	addrs, err = network.GetGenesisAllocatedAddrs([]byte(`{}`))
+	require.NoError(err)
+	require.Zero(addrs.Len())
+
+	_, err = network.GetGenesisAllocatedAddrs([]byte(`{"allocations": [{"luxAddr": "invalid"}]}`))
+	require.Error(err)
+}
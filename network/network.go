@@ -3,6 +3,7 @@ package network
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/luxdefi/netrunner/network/node"
@@ -10,18 +11,143 @@ import (
 )
 
 var (
-	ErrUndefined    = errors.New("undefined network")
-	ErrStopped      = errors.New("network stopped")
-	ErrNodeNotFound = errors.New("node not found in network")
+	ErrUndefined               = errors.New("undefined network")
+	ErrStopped                 = errors.New("network stopped")
+	ErrNodeNotFound            = errors.New("node not found in network")
+	ErrTxFailed                = errors.New("tx failed")
+	ErrElasticSubnetIDNotFound = errors.New("elastic subnet ID not found")
+	ErrNodePaused              = errors.New("node is paused")
+	ErrRenewalBelowQuorum      = errors.New("renewing the validations would leave less than a quorum of the primary network weight validating")
+	ErrRenewalInProgress       = errors.New("validation renewal already in progress")
+	ErrInvalidValidatorSpec    = errors.New("invalid validator spec")
 )
 
 type PermissionlessValidatorSpec struct {
-	SubnetID      string
-	AssetID       string
-	NodeName      string
-	StakedAmount  uint64
-	StartTime     time.Time
+	// Empty for the primary network, in which case the node BLS key is registered
+	SubnetID string
+	// Empty for the primary network LUX asset
+	AssetID  string
+	NodeName string
+	// Weight of the validator. Must be greater than zero
+	StakedAmount uint64
+	// If zero, the validation starts as soon as possible
+	StartTime time.Time
+	// End of the validation. Only one of EndTime and StakeDuration can be given.
+	// If none is, a subnet validation lasts as the primary network one of the
+	// node, and a primary network validation lasts the network validation duration
+	EndTime       time.Time
 	StakeDuration time.Duration
+	// P-Chain addresses given the validation and delegation rewards. If empty,
+	// the network key is used
+	RewardOwners []string
+	// Number of reward owner signatures needed to spend them. If zero, 1 is used
+	RewardThreshold uint32
+	// P-Chain addresses given the change of the staked funds. If empty,
+	// the network key is used
+	ChangeOwners []string
+	// Number of change owner signatures needed to spend it. If zero, 1 is used
+	ChangeThreshold uint32
+}
+
+// Validate returns an error if the weight or validation period of [spec]
+// are invalid, as far as they can be checked without the network state.
+// A validation without start time is assumed to start at [minStartTime].
+func (spec PermissionlessValidatorSpec) Validate(minStartTime time.Time) error {
+	if spec.StakedAmount == 0 {
+		return fmt.Errorf("%w: zero staked amount for validator spec of %s", ErrInvalidValidatorSpec, spec.NodeName)
+	}
+	if !spec.EndTime.IsZero() && spec.StakeDuration != 0 {
+		return fmt.Errorf("%w: both end time and stake duration given for validator spec of %s", ErrInvalidValidatorSpec, spec.NodeName)
+	}
+	if spec.StakeDuration < 0 {
+		return fmt.Errorf("%w: negative stake duration for validator spec of %s", ErrInvalidValidatorSpec, spec.NodeName)
+	}
+	if spec.EndTime.IsZero() && spec.StakeDuration == 0 {
+		return nil
+	}
+	_, _, err := spec.GetValidationPeriod(minStartTime, time.Time{})
+	return err
+}
+
+// GetValidationPeriod returns the start and end times of the validation of
+// [spec], truncated to seconds as kept by the P-Chain. The validation starts
+// at [defaultStartTime] if the spec has no start time, and ends at
+// [defaultEndTime] if the spec has neither end time nor stake duration.
+func (spec PermissionlessValidatorSpec) GetValidationPeriod(defaultStartTime time.Time, defaultEndTime time.Time) (time.Time, time.Time, error) {
+	startTime := spec.StartTime
+	if startTime.IsZero() {
+		startTime = defaultStartTime
+	}
+	startTime = startTime.Truncate(time.Second)
+	var endTime time.Time
+	switch {
+	case !spec.EndTime.IsZero():
+		endTime = spec.EndTime
+	case spec.StakeDuration != 0:
+		endTime = startTime.Add(spec.StakeDuration)
+	default:
+		endTime = defaultEndTime
+	}
+	endTime = endTime.Truncate(time.Second)
+	if !endTime.After(startTime) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: validation of %s would end at %s, not after its start at %s", ErrInvalidValidatorSpec, spec.NodeName, endTime, startTime)
+	}
+	return startTime, endTime, nil
+}
+
+// RewardUTXO is an UTXO received by a validator as staking reward
+type RewardUTXO struct {
+	UTXOID  ids.ID
+	AssetID ids.ID
+	Amount  uint64
+}
+
+// ValidatorRewards holds the outcome of a finished validation period
+type ValidatorRewards struct {
+	NodeName        string
+	SubnetID        ids.ID
+	TxID            ids.ID
+	EndTime         time.Time
+	PotentialReward uint64
+	RewardUTXOs     []RewardUTXO
+}
+
+// AddedPermissionlessValidator is a validation added from a
+// PermissionlessValidatorSpec, with its computed period
+type AddedPermissionlessValidator struct {
+	NodeName  string
+	SubnetID  ids.ID
+	TxID      ids.ID
+	StartTime time.Time
+	EndTime   time.Time
+	Weight    uint64
+}
+
+// DelegatorStake is a current delegation to a validator
+type DelegatorStake struct {
+	TxID            ids.ID
+	StartTime       time.Time
+	EndTime         time.Time
+	Weight          uint64
+	PotentialReward uint64
+}
+
+// ValidatorStake is a current validation of a subnet, with its delegations
+// and the rewards pending on them
+type ValidatorStake struct {
+	// Empty if the validator is not a node of the network
+	NodeName        string
+	NodeID          ids.NodeID
+	TxID            ids.ID
+	StartTime       time.Time
+	EndTime         time.Time
+	Weight          uint64
+	PotentialReward uint64
+	// Fees of the finished delegations, received at the end of the validation
+	AccruedDelegateeReward uint64
+	// Percentage of the delegation rewards kept by the validator
+	DelegationFee float32
+	Delegators    []DelegatorStake
 }
 
 type ElasticSubnetSpec struct {
@@ -42,9 +168,121 @@ type ElasticSubnetSpec struct {
 	UptimeRequirement        uint32
 }
 
+// ElasticSubnet is the configuration of an elastic subnet, as given by its
+// transform tx, and the current state of its staking pool
+type ElasticSubnet struct {
+	// ID of the transform tx
+	ElasticSubnetID ids.ID
+	AssetID         ids.ID
+	Spec            ElasticSubnetSpec
+	CurrentSupply   uint64
+	NumValidators   uint32
+	// Total stake of the validators, without their delegations
+	ValidatorsStake uint64
+	NumDelegators   uint64
+	DelegatorsStake uint64
+}
+
 type SubnetSpec struct {
 	Participants []string
 	SubnetConfig []byte
+	// Weight of the participants as subnet validators. If zero, the network default is used
+	ValidatorsWeight uint64
+	// Duration of the participants validation. If zero, it lasts until their
+	// primary network validation ends
+	ValidationDuration time.Duration
+	// P-Chain addresses of the subnet owners. If empty, the network key is the only owner.
+	// The subnet is set up using the network key, and its ownership is transferred
+	// to the given owners at the end
+	Owners []string
+	// Number of owner signatures needed to manage the subnet. If zero, 1 is used
+	Threshold uint32
+}
+
+type TransferSubnetOwnershipSpec struct {
+	SubnetID string
+	// P-Chain addresses of the new subnet owners
+	Owners []string
+	// Number of owner signatures needed to manage the subnet. If zero, 1 is used
+	Threshold uint32
+}
+
+// AssetHolder is an initial holder of an X-Chain asset
+type AssetHolder struct {
+	// X-Chain addresses of the holder. If empty, the network key is the holder
+	Owners []string
+	// Number of owner signatures needed to spend the holding. If zero, 1 is used
+	Threshold uint32
+	// Amount given to the holder of a fungible asset
+	Amount uint64
+	// Payload of the NFT minted to the holder of an NFT asset
+	Payload []byte
+}
+
+// AssetSpec is an X-Chain asset to be created
+type AssetSpec struct {
+	Name   string
+	Symbol string
+	// Denomination for UI purposes only. Ignored for NFT assets
+	Denomination byte
+	// Create an NFT asset, minting one NFT for each holder, instead of
+	// a fungible one
+	NFT     bool
+	Holders []AssetHolder
+}
+
+// TransferSpec is a transfer of funds of the network key on the P-Chain
+type TransferSpec struct {
+	// P-Chain address receiving the funds
+	To string
+	// Amount of nLUX transferred
+	Amount uint64
+}
+
+// StakeSpec is a stake of funds of the network key on a primary network node
+type StakeSpec struct {
+	NodeName string
+	// Amount of nLUX staked. If zero, the minimum stake is used
+	Amount uint64
+	// Delegate to the node, which must be a validator, instead of adding it as one
+	Delegate bool
+	// Duration of the stake. If zero, the network validation duration is used
+	Duration time.Duration
+	// P-Chain address given the rewards. If empty, the network key is used
+	RewardAddress string
+}
+
+// RenewValidationSpec sets which primary network validations are renewed,
+// and how long the new ones last
+type RenewValidationSpec struct {
+	// Nodes whose validations are renewed. If empty, all the nodes are
+	NodeNames []string
+	// Amount of nLUX staked on each new validation. If zero, the
+	// weight of the ended validation is used
+	Amount uint64
+	// Duration of the new validations. If zero, the network validation
+	// duration is used
+	Duration time.Duration
+}
+
+// StopOptions sets how the nodes of a network are stopped
+type StopOptions struct {
+	// Stop the non beacon nodes before the beacon ones, so the remaining
+	// nodes don't lose their bootstrap peers while others are still running
+	NonBeaconsFirst bool
+	// Time given to each node to exit after being interrupted, before it is
+	// killed. If zero, the backend default is used
+	NodeTimeout time.Duration
+}
+
+// NodeStopResult is the outcome of stopping a node of the network
+type NodeStopResult struct {
+	NodeName string
+	ExitCode int
+	// True if the node didn't exit within the timeout, and was killed
+	Killed   bool
+	Duration time.Duration
+	Err      error
 }
 
 type RemoveSubnetValidatorSpec struct {
@@ -72,12 +310,20 @@ type Network interface {
 	// Stop all the nodes.
 	// Returns ErrStopped if Stop() was previously called.
 	Stop(context.Context) error
+	// Stop all the nodes as set by the given options, returning the outcome for
+	// each node. A node failing to stop doesn't prevent the others from stopping.
+	// Returns ErrStopped if Stop() was previously called.
+	StopWithOptions(context.Context, StopOptions) ([]NodeStopResult, error)
 	// Start a new node with the given config.
 	// Returns ErrStopped if Stop() was previously called.
 	AddNode(node.Config) (node.Node, error)
 	// Stop the node with this name.
 	// Returns ErrStopped if Stop() was previously called.
 	RemoveNode(ctx context.Context, name string) error
+	// Remove the node with this name as validator of the permissioned subnets,
+	// wait for the removals to take effect, and then stop it.
+	// Returns ErrStopped if Stop() was previously called.
+	DrainNode(ctx context.Context, name string) error
 	// Pause the node with this name.
 	// Returns ErrStopped if Stop() was previously called.
 	PauseNode(ctx context.Context, name string) error
@@ -94,6 +340,9 @@ type Network interface {
 	// Returns the names of all nodes in this network.
 	// Returns ErrStopped if Stop() was previously called.
 	GetNodeNames() ([]string, error)
+	// Returns the genesis the nodes of this network were started with.
+	// Returns ErrStopped if Stop() was previously called.
+	GetGenesis() ([]byte, error)
 	// Save network snapshot
 	// Network is stopped in order to do a safe preservation
 	// Returns the full local path to the snapshot dir
@@ -104,18 +353,63 @@ type Network interface {
 	GetSnapshotNames() ([]string, error)
 	// Restart a given node using the same config, optionally changing binary path, plugin dir,
 	// track subnets, a map of chain configs, a map of upgrade configs, and
-	// a map of subnet configs
-	RestartNode(context.Context, string, string, string, string, map[string]string, map[string]string, map[string]string) error
+	// a map of subnet configs. If wipeDB is true, the node db is removed
+	// before restarting, so it bootstraps again from its peers.
+	RestartNode(context.Context, string, string, string, string, map[string]string, map[string]string, map[string]string, bool) error
 	// Create the specified blockchains
 	CreateBlockchains(context.Context, []BlockchainSpec) ([]ids.ID, error)
 	// Create the given numbers of subnets
 	CreateSubnets(context.Context, []SubnetSpec) ([]ids.ID, error)
 	// Transform subnet into elastic subnet
 	TransformSubnet(context.Context, []ElasticSubnetSpec) ([]ids.ID, []ids.ID, error)
-	// Add a validator into an elastic subnet
-	AddPermissionlessValidators(context.Context, []PermissionlessValidatorSpec) error
-	// Remove a validator from a subnet
-	RemoveSubnetValidators(context.Context, []RemoveSubnetValidatorSpec) error
+	// Add a validator into an elastic subnet or the primary network, returning
+	// the added validations
+	AddPermissionlessValidators(context.Context, []PermissionlessValidatorSpec) ([]AddedPermissionlessValidator, error)
+	// Remove a validator from a subnet. Only the nodes that stop tracking a subnet are
+	// restarted, unless the given bool is true, in which case no node is restarted
+	RemoveSubnetValidators(context.Context, []RemoveSubnetValidatorSpec, bool) error
+	// Register an alias for the given blockchain on all nodes
+	AddBlockchainAlias(context.Context, ids.ID, string) error
+	// Remove an alias of the given blockchain from all nodes
+	RemoveBlockchainAlias(context.Context, ids.ID, string) error
+	// Register an alias for the given VM on all nodes
+	AddVMAlias(context.Context, ids.ID, string) error
+	// Transfer the ownership of subnets, returning the tx ids
+	TransferSubnetOwnership(context.Context, []TransferSubnetOwnershipSpec) ([]ids.ID, error)
 	// Get the elastic subnet tx id for the given subnet id
 	GetElasticSubnetID(context.Context, ids.ID) (ids.ID, error)
+	// Get the configuration and staking pool state of the given elastic subnet
+	GetElasticSubnet(context.Context, ids.ID) (ElasticSubnet, error)
+	// Get the current validators of the given subnet, with their delegators
+	// and pending rewards
+	GetStakingRewards(context.Context, ids.ID) ([]ValidatorStake, error)
+	// Wait for the current validation of the given node on the given subnet to end,
+	// and return the rewards it received
+	WaitForValidatorRewards(context.Context, string, ids.ID) (ValidatorRewards, error)
+	// Add or remove nodes so the network has the given number of nodes.
+	// Added nodes are registered as primary network validators
+	ScaleNetwork(context.Context, uint32) error
+	// Stop all the nodes, remove their dbs and restart them with the same configs,
+	// so the network starts again from genesis
+	ResetNetwork(context.Context) error
+	// Create an X-Chain asset, returning its id
+	CreateAsset(context.Context, AssetSpec) (ids.ID, error)
+	// Transfer funds of the network key to a P-Chain address, returning the tx id
+	Transfer(context.Context, TransferSpec) (ids.ID, error)
+	// Stake funds of the network key on a primary network node, as validator
+	// or delegator, returning the tx id
+	Stake(context.Context, StakeSpec) (ids.ID, error)
+	// Check the primary network validations of the given nodes can be renewed
+	// while a quorum of the primary network weight keeps validating, and mark
+	// the nodes as being renewed, failing with ErrRenewalInProgress if any of
+	// them already is. Returns the validations the renewal is expected to add,
+	// with their expected periods and no tx id, the subnet validations of the
+	// nodes included, and the RenewFunc doing it, which must be called
+	RenewPrimaryValidations(context.Context, RenewValidationSpec) ([]AddedPermissionlessValidator, RenewFunc, error)
 }
+
+// RenewFunc waits for the primary network validations marked as being
+// renewed to end, adding each node again as validator as soon as its
+// validation ends, along with its subnet validations, and returns the
+// new validations. The nodes are no longer marked once it returns
+type RenewFunc func(context.Context) ([]AddedPermissionlessValidator, error)
@@ -0,0 +1,117 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionlessValidatorSpecValidate(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name      string
+		spec      network.PermissionlessValidatorSpec
+		expectErr bool
+	}{
+		{
+			name: "defaults",
+			spec: network.PermissionlessValidatorSpec{StakedAmount: 1},
+		},
+		{
+			name: "duration from now",
+			spec: network.PermissionlessValidatorSpec{StakedAmount: 1, StakeDuration: time.Hour},
+		},
+		{
+			name: "end time",
+			spec: network.PermissionlessValidatorSpec{StakedAmount: 1, StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)},
+		},
+		{
+			name:      "zero amount",
+			spec:      network.PermissionlessValidatorSpec{},
+			expectErr: true,
+		},
+		{
+			name:      "end time and duration",
+			spec:      network.PermissionlessValidatorSpec{StakedAmount: 1, EndTime: now.Add(2 * time.Hour), StakeDuration: time.Hour},
+			expectErr: true,
+		},
+		{
+			name:      "negative duration",
+			spec:      network.PermissionlessValidatorSpec{StakedAmount: 1, StakeDuration: -time.Hour},
+			expectErr: true,
+		},
+		{
+			name:      "end time before start time",
+			spec:      network.PermissionlessValidatorSpec{StakedAmount: 1, StartTime: now.Add(2 * time.Hour), EndTime: now.Add(time.Hour)},
+			expectErr: true,
+		},
+		{
+			name:      "end time before the min start time",
+			spec:      network.PermissionlessValidatorSpec{StakedAmount: 1, EndTime: now.Add(-time.Hour)},
+			expectErr: true,
+		},
+		{
+			name:      "duration under a second",
+			spec:      network.PermissionlessValidatorSpec{StakedAmount: 1, StakeDuration: time.Millisecond},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate(now)
+			if tt.expectErr {
+				require.ErrorIs(t, err, network.ErrInvalidValidatorSpec)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetValidationPeriod(t *testing.T) {
+	now := time.Unix(1_700_000_000, 500)
+	start := time.Unix(1_700_000_000, 0)
+	defaultEndTime := start.Add(24 * time.Hour)
+	tests := []struct {
+		name          string
+		spec          network.PermissionlessValidatorSpec
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "defaults",
+			expectedStart: start,
+			expectedEnd:   defaultEndTime,
+		},
+		{
+			name:          "duration counted from the default start",
+			spec:          network.PermissionlessValidatorSpec{StakeDuration: time.Hour},
+			expectedStart: start,
+			expectedEnd:   start.Add(time.Hour),
+		},
+		{
+			name:          "duration counted from the start time",
+			spec:          network.PermissionlessValidatorSpec{StartTime: start.Add(time.Hour), StakeDuration: time.Hour},
+			expectedStart: start.Add(time.Hour),
+			expectedEnd:   start.Add(2 * time.Hour),
+		},
+		{
+			name:          "end time",
+			spec:          network.PermissionlessValidatorSpec{EndTime: start.Add(3 * time.Hour)},
+			expectedStart: start,
+			expectedEnd:   start.Add(3 * time.Hour),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			startTime, endTime, err := tt.spec.GetValidationPeriod(now, defaultEndTime)
+			require.NoError(err)
+			require.Equal(tt.expectedStart, startTime)
+			require.Equal(tt.expectedEnd, endTime)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/luxdefi/netrunner/api"
 	"github.com/luxdefi/netrunner/network/node/status"
@@ -23,7 +24,7 @@ type Node interface {
 	GetNodeID() ids.NodeID
 	// Return a client that can be used to make API calls.
 	GetAPIClient() api.Client
-	// Return this node's IP (e.g. 127.0.0.1).
+	// Return this node's IP (e.g. 127.0.0.1 or ::1).
 	GetURL() string
 	// Return this node's P2P (staking) port.
 	GetP2PPort() uint16
@@ -34,9 +35,24 @@ type Node interface {
 	// The test peer can be used to send messages to the node it's attached to.
 	// It's left to the caller to maintain a reference to the returned peer.
 	// The caller should call StartClose() on the peer when they're done with it.
-	AttachPeer(ctx context.Context, handler router.InboundHandler) (peer.Peer, error)
+	// If [behavior] is not nil, the test peer misbehaves as described by it
+	// when sending messages with SendOutboundMessage.
+	AttachPeer(ctx context.Context, handler router.InboundHandler, behavior *PeerBehavior) (peer.Peer, error)
 	// Sends a message  from the attached peer to the node
 	SendOutboundMessage(ctx context.Context, peerID string, content []byte, op uint32) (bool, error)
+	// Starts recording the messages sent and received by the attached peer
+	// to a new file at [path], one JSON object per line. Stops recording if
+	// [path] is empty.
+	RecordPeerMessages(peerID string, path string) error
+	// Sends from the attached peer to the node the outbound messages
+	// recorded at [path], in order. If [keepTiming], waits between messages
+	// as much as when recorded. Returns the number of messages sent.
+	ReplayPeerMessages(ctx context.Context, peerID string, path string, keepTiming bool) (uint32, error)
+	// Returns a channel with the messages received by the attached peer from
+	// the node, and a function to call when done with it. Messages are
+	// dropped if the channel is full. The channel is closed when the node
+	// is removed.
+	SubscribePeerMessages(peerID string) (<-chan PeerMessage, func(), error)
 	// Return the state of the node process
 	Status() status.Status
 	// Return this node's node binary path
@@ -57,8 +73,49 @@ type Node interface {
 	GetFlag(string) (string, error)
 	// Return this node's paused status
 	GetPaused() bool
+	// Return this node's process ID, or 0 if it isn't running
+	GetPID() int
+	// Return the command line flags this node's process was started with
+	GetArgs() []string
 }
 
+// Max number of extra copies a test peer can send of each message.
+const MaxPeerDuplicates = 100
+
+// Misbehavior of a test peer attached to a node, used to test the
+// robustness of the node against byzantine peers. The zero value
+// describes a well behaved peer.
+type PeerBehavior struct {
+	// Time to wait before sending each message.
+	SendDelay time.Duration `json:"sendDelay"`
+	// Number of extra copies sent of each message, up to [MaxPeerDuplicates].
+	Duplicates uint32 `json:"duplicates"`
+	// If true, the message bytes are corrupted before sending.
+	MalformedBody bool `json:"malformedBody"`
+	// If true, the chain ID of the message is replaced with a random one.
+	WrongChainID bool `json:"wrongChainID"`
+}
+
+// A message received by an attached test peer from the node.
+type PeerMessage struct {
+	Op uint32
+	// empty if the message is not chain specific
+	ChainID ids.ID
+	// serialized body of the message, if it is a protobuf message
+	Bytes []byte
+}
+
+// Storage modes of a node
+const (
+	// Keeps all the C-Chain state and indexes the accepted containers,
+	// as needed by indexers and historical state queries
+	ModeArchival = "archival"
+	// Prunes the old C-Chain state and doesn't index, the node default
+	ModePruned = "pruned"
+)
+
+var ErrInvalidMode = errors.New("invalid node mode")
+
 // Config encapsulates an node configuration
 type Config struct {
 	// A node's name must be unique from all other nodes
@@ -68,11 +125,13 @@ type Config struct {
 	// True if other nodes should use this node
 	// as a bootstrap beacon.
 	IsBeacon bool `json:"isBeacon"`
-	// Must not be nil.
+	// If empty along with StakingCert, both are generated when the
+	// node is added to a network.
 	StakingKey string `json:"stakingKey"`
-	// Must not be nil.
+	// If empty along with StakingKey, both are generated when the
+	// node is added to a network.
 	StakingCert string `json:"stakingCert"`
-	// Must not be nil.
+	// If empty, it is generated when the node is added to a network.
 	StakingSigningKey string `json:"stakingSigningKey"`
 	// May be nil.
 	ConfigFile string `json:"configFile"`
@@ -95,15 +154,28 @@ type Config struct {
 	RedirectStdout bool `json:"redirectStdout"`
 	// If non-nil, direct this node's Stderr to os.Stderr
 	RedirectStderr bool `json:"redirectStderr"`
+	// Storage mode of the node, ModeArchival or ModePruned. It is expanded to
+	// the node flags and C-Chain config of the mode, except for the ones given
+	// explicitly. May be empty.
+	Mode string `json:"mode,omitempty"`
+	// Dirs of the node, eg to place the db on a fast disk and the logs on
+	// another. Override the network dir layout, and are overridden by the
+	// data-dir, db-dir and log-dir flags. May be empty.
+	DataDir string `json:"dataDir,omitempty"`
+	DBDir   string `json:"dbDir,omitempty"`
+	LogsDir string `json:"logsDir,omitempty"`
 }
 
 // Validate returns an error if this config is invalid
 func (c *Config) Validate(expectedNetworkID uint32) error {
 	switch {
-	case c.StakingKey == "":
+	// if both are empty, they are generated when the node is added
+	case c.StakingKey == "" && c.StakingCert != "":
 		return errors.New("staking key not given")
-	case c.StakingCert == "":
+	case c.StakingCert == "" && c.StakingKey != "":
 		return errors.New("staking cert not given")
+	case c.Mode != "" && c.Mode != ModeArchival && c.Mode != ModePruned:
+		return fmt.Errorf("%w: %q", ErrInvalidMode, c.Mode)
 	default:
 		return validateConfigFile([]byte(c.ConfigFile), expectedNetworkID)
 	}
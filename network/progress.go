@@ -0,0 +1,39 @@
+package network
+
+// Phases of the long network operations, reported to a ProgressHandler
+const (
+	// Waiting for the nodes to report healthy
+	PhaseWaitHealthy = "wait-healthy"
+	// Adding the nodes as primary network validators
+	PhaseAddPrimaryValidators = "add-primary-validators"
+	// Waiting for the nodes to start validating the primary network
+	PhaseWaitPrimaryValidators = "wait-primary-validators"
+	// Creating the subnets of the blockchains
+	PhaseCreateSubnets = "create-subnets"
+	// Adding the nodes as subnet validators
+	PhaseAddSubnetValidators = "add-subnet-validators"
+	// Waiting for the nodes to start validating the subnets
+	PhaseWaitSubnetValidators = "wait-subnet-validators"
+	// Restarting the nodes to track the subnets or load configs
+	PhaseRestartNodes = "restart-nodes"
+	// Issuing the blockchain creation txs
+	PhaseCreateBlockchains = "create-blockchains"
+	// Waiting for the blockchains to run on their validators
+	PhaseWaitBlockchains = "wait-blockchains"
+	// Renewing the primary network validations as they end
+	PhaseRenewPrimaryValidations = "renew-primary-validations"
+)
+
+// ProgressEvent reports the progress of a phase of a long network operation
+type ProgressEvent struct {
+	// One of the Phase constants
+	Phase string
+	// Completion of the phase, from 0 to 100
+	Percent float64
+	// Human readable description of the last step done
+	Message string
+}
+
+// ProgressHandler is called with the progress of the long network
+// operations. It may be called concurrently, and must not block.
+type ProgressHandler func(ProgressEvent)
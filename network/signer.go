@@ -0,0 +1,168 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/luxdefi/node/genesis"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/formatting/address"
+)
+
+const remoteTxSignerTimeout = 30 * time.Second
+
+var ErrRemoteTxSigner = errors.New("remote tx signer failed")
+
+// TxSigner signs the P-Chain and X-Chain txs issued by the network, e.g. to
+// create subnets and add validators, instead of the in-memory key funded by
+// the genesis, so flows using keys kept by an HSM or a KMS can be tested
+type TxSigner interface {
+	// Address of the key signing the txs, holding the funds they spend
+	Address() ids.ShortID
+	// Returns the 65 bytes recoverable secp256k1 signature [r || s || v]
+	// of [hash], the SHA-256 hash of an unsigned tx
+	SignHash(hash []byte) ([]byte, error)
+}
+
+type callbackTxSigner struct {
+	addr ids.ShortID
+	sign func(hash []byte) ([]byte, error)
+}
+
+// NewCallbackTxSigner returns a TxSigner of the key of address [addr],
+// signing with [sign]
+func NewCallbackTxSigner(addr ids.ShortID, sign func(hash []byte) ([]byte, error)) TxSigner {
+	return &callbackTxSigner{
+		addr: addr,
+		sign: sign,
+	}
+}
+
+func (s *callbackTxSigner) Address() ids.ShortID {
+	return s.addr
+}
+
+func (s *callbackTxSigner) SignHash(hash []byte) ([]byte, error) {
+	return s.sign(hash)
+}
+
+// Body posted to a remote tx signer
+type remoteTxSignRequest struct {
+	// short address ID of the key
+	Address string `json:"address"`
+	// hex encoded hash to sign
+	Hash string `json:"hash"`
+}
+
+// Body replied by a remote tx signer
+type remoteTxSignResponse struct {
+	// hex encoded recoverable signature of the hash
+	Signature string `json:"signature"`
+}
+
+type remoteTxSigner struct {
+	url    string
+	addr   ids.ShortID
+	client *http.Client
+}
+
+// NewRemoteTxSigner returns a TxSigner of the key of address [addr],
+// kept by the signer at [url]. The hashes to sign are posted to [url] as
+// JSON {"address": <short ID>, "hash": <hex>}, and the signer is expected
+// to reply with JSON {"signature": <hex>}
+func NewRemoteTxSigner(url string, addr ids.ShortID) TxSigner {
+	return &remoteTxSigner{
+		url:    url,
+		addr:   addr,
+		client: &http.Client{Timeout: remoteTxSignerTimeout},
+	}
+}
+
+func (s *remoteTxSigner) Address() ids.ShortID {
+	return s.addr
+}
+
+func (s *remoteTxSigner) SignHash(hash []byte) ([]byte, error) {
+	body, err := json.Marshal(remoteTxSignRequest{
+		Address: s.addr.String(),
+		Hash:    hexutil.Encode(hash),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrRemoteTxSigner, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%w: status %s", ErrRemoteTxSigner, resp.Status)
+	}
+	var signResp remoteTxSignResponse
+	if err := json.NewDecoder(resp.Body).Decode(&signResp); err != nil {
+		return nil, fmt.Errorf("%w: couldn't decode response: %s", ErrRemoteTxSigner, err)
+	}
+	signature, err := hexutil.Decode(signResp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: couldn't decode signature: %s", ErrRemoteTxSigner, err)
+	}
+	return signature, nil
+}
+
+// FundGenesisAddress returns [genesis] with an allocation for [addr] equal
+// to the one of the key funded by the default genesis, so a TxSigner of
+// [addr] can issue the same txs
+func FundGenesisAddress(genesisStr string, addr ids.ShortID) (string, error) {
+	var genesisMap map[string]interface{}
+	if err := json.Unmarshal([]byte(genesisStr), &genesisMap); err != nil {
+		return "", fmt.Errorf("couldn't unmarshal genesis: %w", err)
+	}
+	networkID, ok := genesisMap["networkID"].(float64)
+	if !ok {
+		return "", fmt.Errorf("expected networkID to be a number, but got %T", genesisMap["networkID"])
+	}
+	hrp := constants.GetHRP(uint32(networkID))
+	fundedAddr, err := address.Format("X", hrp, genesis.EWOQKey.PublicKey().Address().Bytes())
+	if err != nil {
+		return "", err
+	}
+	luxAddr, err := address.Format("X", hrp, addr.Bytes())
+	if err != nil {
+		return "", err
+	}
+
+	allocations, _ := genesisMap["allocations"].([]interface{})
+	for _, allocIntf := range allocations {
+		alloc, ok := allocIntf.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("unexpected type for allocation in genesis. got %T", allocIntf)
+		}
+		if allocAddr, _ := alloc["luxAddr"].(string); !strings.EqualFold(allocAddr, fundedAddr) {
+			continue
+		}
+		// the allocation is copied through JSON, so the unlock schedule isn't shared
+		allocBytes, err := json.Marshal(alloc)
+		if err != nil {
+			return "", err
+		}
+		var newAlloc map[string]interface{}
+		if err := json.Unmarshal(allocBytes, &newAlloc); err != nil {
+			return "", err
+		}
+		newAlloc["luxAddr"] = luxAddr
+		genesisMap["allocations"] = append(allocations, newAlloc)
+		genesisBytes, err := json.Marshal(genesisMap)
+		if err != nil {
+			return "", err
+		}
+		return string(genesisBytes), nil
+	}
+	return "", fmt.Errorf("no allocation of the funded key %s in genesis", fundedAddr)
+}
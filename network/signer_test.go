@@ -0,0 +1,96 @@
+package network_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/node/genesis"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/formatting/address"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteTxSigner(t *testing.T) {
+	require := require.New(t)
+
+	key := genesis.EWOQKey
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Address string `json:"address"`
+			Hash    string `json:"hash"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Address != key.PublicKey().Address().String() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		hash, err := hexutil.Decode(req.Hash)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		signature, err := key.SignHash(hash)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"signature": hexutil.Encode(signature)})
+	}))
+	defer server.Close()
+
+	hash := make([]byte, 32)
+	expected, err := key.SignHash(hash)
+	require.NoError(err)
+
+	signer := network.NewRemoteTxSigner(server.URL, key.PublicKey().Address())
+	require.Equal(key.PublicKey().Address(), signer.Address())
+	signature, err := signer.SignHash(hash)
+	require.NoError(err)
+	require.Equal(expected, signature)
+
+	// the server rejects the requests of other addresses
+	signer = network.NewRemoteTxSigner(server.URL, ids.GenerateTestShortID())
+	_, err = signer.SignHash(hash)
+	require.ErrorIs(err, network.ErrRemoteTxSigner)
+}
+
+func TestFundGenesisAddress(t *testing.T) {
+	require := require.New(t)
+
+	genesisMap, err := network.LoadLocalGenesis()
+	require.NoError(err)
+	genesisBytes, err := json.Marshal(genesisMap)
+	require.NoError(err)
+
+	addr := ids.GenerateTestShortID()
+	genesisStr, err := network.FundGenesisAddress(string(genesisBytes), addr)
+	require.NoError(err)
+
+	var updated struct {
+		NetworkID   uint32 `json:"networkID"`
+		Allocations []struct {
+			LuxAddr        string          `json:"luxAddr"`
+			InitialAmount  uint64          `json:"initialAmount"`
+			UnlockSchedule json.RawMessage `json:"unlockSchedule"`
+		} `json:"allocations"`
+	}
+	require.NoError(json.Unmarshal([]byte(genesisStr), &updated))
+	hrp := constants.GetHRP(updated.NetworkID)
+	fundedAddr, err := address.Format("X", hrp, genesis.EWOQKey.PublicKey().Address().Bytes())
+	require.NoError(err)
+	luxAddr, err := address.Format("X", hrp, addr.Bytes())
+	require.NoError(err)
+
+	newAlloc := updated.Allocations[len(updated.Allocations)-1]
+	require.Equal(luxAddr, newAlloc.LuxAddr)
+	for _, alloc := range updated.Allocations {
+		if alloc.LuxAddr == fundedAddr {
+			require.Equal(alloc.InitialAmount, newAlloc.InitialAmount)
+			require.JSONEq(string(alloc.UnlockSchedule), string(newAlloc.UnlockSchedule))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	networkUpgradeOverridesKey = "networkUpgradeOverrides"
+	precompileUpgradesKey      = "precompileUpgrades"
+	stateUpgradesKey           = "stateUpgrades"
+	blockTimestampKey          = "blockTimestamp"
+)
+
+var ErrEmptyUpgradeSpec = errors.New("upgrade spec has no fork activations, precompile upgrades or state upgrades")
+
+// PrecompileUpgrade activates (or deactivates) a precompile at a given time
+type PrecompileUpgrade struct {
+	// Precompile config key, e.g. "feeManagerConfig"
+	Name string
+	// Time at which the upgrade is applied
+	ActivationTime time.Time
+	// Precompile specific config. The block timestamp is set from [ActivationTime]
+	Config map[string]interface{}
+}
+
+// NetworkUpgradeSpec defines, at a high level, the contents of a chain upgrade file (upgrade.json)
+type NetworkUpgradeSpec struct {
+	// Map from fork name (e.g. "durangoTimestamp") to its activation time
+	ForkActivations map[string]time.Time
+	// Precompile upgrades, in activation order
+	PrecompileUpgrades []PrecompileUpgrade
+	// State upgrades, passed verbatim
+	StateUpgrades []map[string]interface{}
+}
+
+// Bytes returns the upgrade file contents for the spec
+func (s NetworkUpgradeSpec) Bytes() ([]byte, error) {
+	if len(s.ForkActivations) == 0 && len(s.PrecompileUpgrades) == 0 && len(s.StateUpgrades) == 0 {
+		return nil, ErrEmptyUpgradeSpec
+	}
+	upgrade := map[string]interface{}{}
+	if len(s.ForkActivations) > 0 {
+		overrides := map[string]uint64{}
+		for fork, activationTime := range s.ForkActivations {
+			if fork == "" {
+				return nil, errors.New("fork name must not be empty")
+			}
+			overrides[fork] = uint64(activationTime.Unix())
+		}
+		upgrade[networkUpgradeOverridesKey] = overrides
+	}
+	if len(s.PrecompileUpgrades) > 0 {
+		precompileUpgrades := []map[string]interface{}{}
+		for _, precompileUpgrade := range s.PrecompileUpgrades {
+			if precompileUpgrade.Name == "" {
+				return nil, errors.New("precompile upgrade name must not be empty")
+			}
+			precompileConfig := map[string]interface{}{}
+			for k, v := range precompileUpgrade.Config {
+				precompileConfig[k] = v
+			}
+			precompileConfig[blockTimestampKey] = uint64(precompileUpgrade.ActivationTime.Unix())
+			precompileUpgrades = append(precompileUpgrades, map[string]interface{}{
+				precompileUpgrade.Name: precompileConfig,
+			})
+		}
+		upgrade[precompileUpgradesKey] = precompileUpgrades
+	}
+	if len(s.StateUpgrades) > 0 {
+		upgrade[stateUpgradesKey] = s.StateUpgrades
+	}
+	upgradeBytes, err := json.Marshal(upgrade)
+	if err != nil {
+		return nil, fmt.Errorf("failure marshaling upgrade spec: %w", err)
+	}
+	return upgradeBytes, nil
+}
+
+// NewForkActivationsUpgradeSpec returns an upgrade spec that activates the given forks
+// [offset] after [now], e.g. to schedule a fork a couple of minutes into a test run
+func NewForkActivationsUpgradeSpec(now time.Time, offset time.Duration, forks ...string) NetworkUpgradeSpec {
+	forkActivations := map[string]time.Time{}
+	for _, fork := range forks {
+		forkActivations[fork] = now.Add(offset)
+	}
+	return NetworkUpgradeSpec{ForkActivations: forkActivations}
+}
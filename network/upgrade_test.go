@@ -0,0 +1,43 @@
+package network_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkUpgradeSpecBytes(t *testing.T) {
+	require := require.New(t)
+
+	activationTime := time.Unix(1700000000, 0)
+	spec := network.NetworkUpgradeSpec{
+		ForkActivations: map[string]time.Time{
+			"durangoTimestamp": activationTime,
+		},
+		PrecompileUpgrades: []network.PrecompileUpgrade{
+			{
+				Name:           "feeManagerConfig",
+				ActivationTime: activationTime,
+				Config: map[string]interface{}{
+					"adminAddresses": []string{"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"},
+				},
+			},
+		},
+	}
+	upgradeBytes, err := spec.Bytes()
+	require.NoError(err)
+	require.JSONEq(
+		`{"networkUpgradeOverrides":{"durangoTimestamp":1700000000},"precompileUpgrades":[{"feeManagerConfig":{"adminAddresses":["0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"],"blockTimestamp":1700000000}}]}`,
+		string(upgradeBytes),
+	)
+
+	_, err = network.NetworkUpgradeSpec{}.Bytes()
+	require.ErrorIs(err, network.ErrEmptyUpgradeSpec)
+
+	spec = network.NewForkActivationsUpgradeSpec(activationTime, time.Minute, "durangoTimestamp")
+	upgradeBytes, err = spec.Bytes()
+	require.NoError(err)
+	require.JSONEq(`{"networkUpgradeOverrides":{"durangoTimestamp":1700000060}}`, string(upgradeBytes))
+}
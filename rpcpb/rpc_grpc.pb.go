@@ -113,22 +113,67 @@ const (
 	ControlService_Start_FullMethodName                      = "/rpcpb.ControlService/Start"
 	ControlService_CreateBlockchains_FullMethodName          = "/rpcpb.ControlService/CreateBlockchains"
 	ControlService_TransformElasticSubnets_FullMethodName    = "/rpcpb.ControlService/TransformElasticSubnets"
+	ControlService_GetElasticSubnet_FullMethodName           = "/rpcpb.ControlService/GetElasticSubnet"
 	ControlService_AddPermissionlessValidator_FullMethodName = "/rpcpb.ControlService/AddPermissionlessValidator"
 	ControlService_RemoveSubnetValidator_FullMethodName      = "/rpcpb.ControlService/RemoveSubnetValidator"
+	ControlService_ListBlockchains_FullMethodName            = "/rpcpb.ControlService/ListBlockchains"
+	ControlService_GetBlockchainStatus_FullMethodName        = "/rpcpb.ControlService/GetBlockchainStatus"
+	ControlService_AddBlockchainAlias_FullMethodName         = "/rpcpb.ControlService/AddBlockchainAlias"
+	ControlService_RemoveBlockchainAlias_FullMethodName      = "/rpcpb.ControlService/RemoveBlockchainAlias"
+	ControlService_AddVMAlias_FullMethodName                 = "/rpcpb.ControlService/AddVMAlias"
+	ControlService_TransferSubnetOwnership_FullMethodName    = "/rpcpb.ControlService/TransferSubnetOwnership"
+	ControlService_CreateAsset_FullMethodName                = "/rpcpb.ControlService/CreateAsset"
+	ControlService_Transfer_FullMethodName                   = "/rpcpb.ControlService/Transfer"
+	ControlService_Stake_FullMethodName                      = "/rpcpb.ControlService/Stake"
+	ControlService_RenewPrimaryValidations_FullMethodName    = "/rpcpb.ControlService/RenewPrimaryValidations"
+	ControlService_GetStakingRewards_FullMethodName          = "/rpcpb.ControlService/GetStakingRewards"
+	ControlService_WaitForValidatorRewards_FullMethodName    = "/rpcpb.ControlService/WaitForValidatorRewards"
+	ControlService_GetUptimes_FullMethodName                 = "/rpcpb.ControlService/GetUptimes"
+	ControlService_GetVersions_FullMethodName                = "/rpcpb.ControlService/GetVersions"
+	ControlService_GetTopology_FullMethodName                = "/rpcpb.ControlService/GetTopology"
+	ControlService_ExportNetwork_FullMethodName              = "/rpcpb.ControlService/ExportNetwork"
+	ControlService_CheckConnectivity_FullMethodName          = "/rpcpb.ControlService/CheckConnectivity"
+	ControlService_GetOperationHistory_FullMethodName        = "/rpcpb.ControlService/GetOperationHistory"
 	ControlService_CreateSubnets_FullMethodName              = "/rpcpb.ControlService/CreateSubnets"
 	ControlService_Health_FullMethodName                     = "/rpcpb.ControlService/Health"
 	ControlService_URIs_FullMethodName                       = "/rpcpb.ControlService/URIs"
 	ControlService_WaitForHealthy_FullMethodName             = "/rpcpb.ControlService/WaitForHealthy"
+	ControlService_WaitFor_FullMethodName                    = "/rpcpb.ControlService/WaitFor"
+	ControlService_Assert_FullMethodName                     = "/rpcpb.ControlService/Assert"
 	ControlService_Status_FullMethodName                     = "/rpcpb.ControlService/Status"
+	ControlService_GetNodeInfo_FullMethodName                = "/rpcpb.ControlService/GetNodeInfo"
+	ControlService_ListNodes_FullMethodName                  = "/rpcpb.ControlService/ListNodes"
+	ControlService_ListCustomChains_FullMethodName           = "/rpcpb.ControlService/ListCustomChains"
 	ControlService_StreamStatus_FullMethodName               = "/rpcpb.ControlService/StreamStatus"
+	ControlService_StreamLogs_FullMethodName                 = "/rpcpb.ControlService/StreamLogs"
+	ControlService_SearchLogs_FullMethodName                 = "/rpcpb.ControlService/SearchLogs"
 	ControlService_RemoveNode_FullMethodName                 = "/rpcpb.ControlService/RemoveNode"
+	ControlService_DrainNode_FullMethodName                  = "/rpcpb.ControlService/DrainNode"
 	ControlService_AddNode_FullMethodName                    = "/rpcpb.ControlService/AddNode"
+	ControlService_ScaleNetwork_FullMethodName               = "/rpcpb.ControlService/ScaleNetwork"
+	ControlService_ResetNetwork_FullMethodName               = "/rpcpb.ControlService/ResetNetwork"
+	ControlService_StateSyncNode_FullMethodName              = "/rpcpb.ControlService/StateSyncNode"
+	ControlService_ExportCChainState_FullMethodName          = "/rpcpb.ControlService/ExportCChainState"
+	ControlService_GetDBSizes_FullMethodName                 = "/rpcpb.ControlService/GetDBSizes"
+	ControlService_PruneNodeDB_FullMethodName                = "/rpcpb.ControlService/PruneNodeDB"
 	ControlService_RestartNode_FullMethodName                = "/rpcpb.ControlService/RestartNode"
 	ControlService_PauseNode_FullMethodName                  = "/rpcpb.ControlService/PauseNode"
 	ControlService_ResumeNode_FullMethodName                 = "/rpcpb.ControlService/ResumeNode"
+	ControlService_PauseAll_FullMethodName                   = "/rpcpb.ControlService/PauseAll"
+	ControlService_ResumeAll_FullMethodName                  = "/rpcpb.ControlService/ResumeAll"
+	ControlService_SimulateDowntime_FullMethodName           = "/rpcpb.ControlService/SimulateDowntime"
+	ControlService_StartChaos_FullMethodName                 = "/rpcpb.ControlService/StartChaos"
+	ControlService_StopChaos_FullMethodName                  = "/rpcpb.ControlService/StopChaos"
+	ControlService_GetChaosStatus_FullMethodName             = "/rpcpb.ControlService/GetChaosStatus"
 	ControlService_Stop_FullMethodName                       = "/rpcpb.ControlService/Stop"
 	ControlService_AttachPeer_FullMethodName                 = "/rpcpb.ControlService/AttachPeer"
 	ControlService_SendOutboundMessage_FullMethodName        = "/rpcpb.ControlService/SendOutboundMessage"
+	ControlService_SendPeerMessage_FullMethodName            = "/rpcpb.ControlService/SendPeerMessage"
+	ControlService_SendAppRequest_FullMethodName             = "/rpcpb.ControlService/SendAppRequest"
+	ControlService_StreamPeerMessages_FullMethodName         = "/rpcpb.ControlService/StreamPeerMessages"
+	ControlService_StreamProgress_FullMethodName             = "/rpcpb.ControlService/StreamProgress"
+	ControlService_RecordPeerMessages_FullMethodName         = "/rpcpb.ControlService/RecordPeerMessages"
+	ControlService_ReplayPeerMessages_FullMethodName         = "/rpcpb.ControlService/ReplayPeerMessages"
 	ControlService_SaveSnapshot_FullMethodName               = "/rpcpb.ControlService/SaveSnapshot"
 	ControlService_LoadSnapshot_FullMethodName               = "/rpcpb.ControlService/LoadSnapshot"
 	ControlService_RemoveSnapshot_FullMethodName             = "/rpcpb.ControlService/RemoveSnapshot"
@@ -143,22 +188,67 @@ type ControlServiceClient interface {
 	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error)
 	CreateBlockchains(ctx context.Context, in *CreateBlockchainsRequest, opts ...grpc.CallOption) (*CreateBlockchainsResponse, error)
 	TransformElasticSubnets(ctx context.Context, in *TransformElasticSubnetsRequest, opts ...grpc.CallOption) (*TransformElasticSubnetsResponse, error)
+	GetElasticSubnet(ctx context.Context, in *GetElasticSubnetRequest, opts ...grpc.CallOption) (*GetElasticSubnetResponse, error)
 	AddPermissionlessValidator(ctx context.Context, in *AddPermissionlessValidatorRequest, opts ...grpc.CallOption) (*AddPermissionlessValidatorResponse, error)
 	RemoveSubnetValidator(ctx context.Context, in *RemoveSubnetValidatorRequest, opts ...grpc.CallOption) (*RemoveSubnetValidatorResponse, error)
+	ListBlockchains(ctx context.Context, in *ListBlockchainsRequest, opts ...grpc.CallOption) (*ListBlockchainsResponse, error)
+	GetBlockchainStatus(ctx context.Context, in *GetBlockchainStatusRequest, opts ...grpc.CallOption) (*GetBlockchainStatusResponse, error)
+	AddBlockchainAlias(ctx context.Context, in *AddBlockchainAliasRequest, opts ...grpc.CallOption) (*AddBlockchainAliasResponse, error)
+	RemoveBlockchainAlias(ctx context.Context, in *RemoveBlockchainAliasRequest, opts ...grpc.CallOption) (*RemoveBlockchainAliasResponse, error)
+	AddVMAlias(ctx context.Context, in *AddVMAliasRequest, opts ...grpc.CallOption) (*AddVMAliasResponse, error)
+	TransferSubnetOwnership(ctx context.Context, in *TransferSubnetOwnershipRequest, opts ...grpc.CallOption) (*TransferSubnetOwnershipResponse, error)
+	CreateAsset(ctx context.Context, in *CreateAssetRequest, opts ...grpc.CallOption) (*CreateAssetResponse, error)
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
+	Stake(ctx context.Context, in *StakeRequest, opts ...grpc.CallOption) (*StakeResponse, error)
+	RenewPrimaryValidations(ctx context.Context, in *RenewPrimaryValidationsRequest, opts ...grpc.CallOption) (*RenewPrimaryValidationsResponse, error)
+	GetStakingRewards(ctx context.Context, in *GetStakingRewardsRequest, opts ...grpc.CallOption) (*GetStakingRewardsResponse, error)
+	WaitForValidatorRewards(ctx context.Context, in *WaitForValidatorRewardsRequest, opts ...grpc.CallOption) (*WaitForValidatorRewardsResponse, error)
+	GetUptimes(ctx context.Context, in *GetUptimesRequest, opts ...grpc.CallOption) (*GetUptimesResponse, error)
+	GetVersions(ctx context.Context, in *GetVersionsRequest, opts ...grpc.CallOption) (*GetVersionsResponse, error)
+	GetTopology(ctx context.Context, in *GetTopologyRequest, opts ...grpc.CallOption) (*GetTopologyResponse, error)
+	ExportNetwork(ctx context.Context, in *ExportNetworkRequest, opts ...grpc.CallOption) (*ExportNetworkResponse, error)
+	CheckConnectivity(ctx context.Context, in *CheckConnectivityRequest, opts ...grpc.CallOption) (*CheckConnectivityResponse, error)
+	GetOperationHistory(ctx context.Context, in *GetOperationHistoryRequest, opts ...grpc.CallOption) (*GetOperationHistoryResponse, error)
 	CreateSubnets(ctx context.Context, in *CreateSubnetsRequest, opts ...grpc.CallOption) (*CreateSubnetsResponse, error)
 	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
 	URIs(ctx context.Context, in *URIsRequest, opts ...grpc.CallOption) (*URIsResponse, error)
 	WaitForHealthy(ctx context.Context, in *WaitForHealthyRequest, opts ...grpc.CallOption) (*WaitForHealthyResponse, error)
+	WaitFor(ctx context.Context, in *WaitForRequest, opts ...grpc.CallOption) (*WaitForResponse, error)
+	Assert(ctx context.Context, in *AssertRequest, opts ...grpc.CallOption) (*AssertResponse, error)
 	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+	GetNodeInfo(ctx context.Context, in *GetNodeInfoRequest, opts ...grpc.CallOption) (*GetNodeInfoResponse, error)
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error)
+	ListCustomChains(ctx context.Context, in *ListCustomChainsRequest, opts ...grpc.CallOption) (*ListCustomChainsResponse, error)
 	StreamStatus(ctx context.Context, in *StreamStatusRequest, opts ...grpc.CallOption) (ControlService_StreamStatusClient, error)
+	StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (ControlService_StreamLogsClient, error)
+	SearchLogs(ctx context.Context, in *SearchLogsRequest, opts ...grpc.CallOption) (*SearchLogsResponse, error)
 	RemoveNode(ctx context.Context, in *RemoveNodeRequest, opts ...grpc.CallOption) (*RemoveNodeResponse, error)
+	DrainNode(ctx context.Context, in *DrainNodeRequest, opts ...grpc.CallOption) (*DrainNodeResponse, error)
 	AddNode(ctx context.Context, in *AddNodeRequest, opts ...grpc.CallOption) (*AddNodeResponse, error)
+	ScaleNetwork(ctx context.Context, in *ScaleNetworkRequest, opts ...grpc.CallOption) (*ScaleNetworkResponse, error)
+	ResetNetwork(ctx context.Context, in *ResetNetworkRequest, opts ...grpc.CallOption) (*ResetNetworkResponse, error)
+	StateSyncNode(ctx context.Context, in *StateSyncNodeRequest, opts ...grpc.CallOption) (ControlService_StateSyncNodeClient, error)
+	ExportCChainState(ctx context.Context, in *ExportCChainStateRequest, opts ...grpc.CallOption) (*ExportCChainStateResponse, error)
+	GetDBSizes(ctx context.Context, in *GetDBSizesRequest, opts ...grpc.CallOption) (*GetDBSizesResponse, error)
+	PruneNodeDB(ctx context.Context, in *PruneNodeDBRequest, opts ...grpc.CallOption) (*PruneNodeDBResponse, error)
 	RestartNode(ctx context.Context, in *RestartNodeRequest, opts ...grpc.CallOption) (*RestartNodeResponse, error)
 	PauseNode(ctx context.Context, in *PauseNodeRequest, opts ...grpc.CallOption) (*PauseNodeResponse, error)
 	ResumeNode(ctx context.Context, in *ResumeNodeRequest, opts ...grpc.CallOption) (*ResumeNodeResponse, error)
+	PauseAll(ctx context.Context, in *PauseAllRequest, opts ...grpc.CallOption) (*PauseAllResponse, error)
+	ResumeAll(ctx context.Context, in *ResumeAllRequest, opts ...grpc.CallOption) (*ResumeAllResponse, error)
+	SimulateDowntime(ctx context.Context, in *SimulateDowntimeRequest, opts ...grpc.CallOption) (*SimulateDowntimeResponse, error)
+	StartChaos(ctx context.Context, in *StartChaosRequest, opts ...grpc.CallOption) (*StartChaosResponse, error)
+	StopChaos(ctx context.Context, in *StopChaosRequest, opts ...grpc.CallOption) (*StopChaosResponse, error)
+	GetChaosStatus(ctx context.Context, in *GetChaosStatusRequest, opts ...grpc.CallOption) (*GetChaosStatusResponse, error)
 	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
 	AttachPeer(ctx context.Context, in *AttachPeerRequest, opts ...grpc.CallOption) (*AttachPeerResponse, error)
 	SendOutboundMessage(ctx context.Context, in *SendOutboundMessageRequest, opts ...grpc.CallOption) (*SendOutboundMessageResponse, error)
+	SendPeerMessage(ctx context.Context, in *SendPeerMessageRequest, opts ...grpc.CallOption) (*SendPeerMessageResponse, error)
+	SendAppRequest(ctx context.Context, in *SendAppRequestRequest, opts ...grpc.CallOption) (*SendAppRequestResponse, error)
+	StreamPeerMessages(ctx context.Context, in *StreamPeerMessagesRequest, opts ...grpc.CallOption) (ControlService_StreamPeerMessagesClient, error)
+	StreamProgress(ctx context.Context, in *StreamProgressRequest, opts ...grpc.CallOption) (ControlService_StreamProgressClient, error)
+	RecordPeerMessages(ctx context.Context, in *RecordPeerMessagesRequest, opts ...grpc.CallOption) (*RecordPeerMessagesResponse, error)
+	ReplayPeerMessages(ctx context.Context, in *ReplayPeerMessagesRequest, opts ...grpc.CallOption) (*ReplayPeerMessagesResponse, error)
 	SaveSnapshot(ctx context.Context, in *SaveSnapshotRequest, opts ...grpc.CallOption) (*SaveSnapshotResponse, error)
 	LoadSnapshot(ctx context.Context, in *LoadSnapshotRequest, opts ...grpc.CallOption) (*LoadSnapshotResponse, error)
 	RemoveSnapshot(ctx context.Context, in *RemoveSnapshotRequest, opts ...grpc.CallOption) (*RemoveSnapshotResponse, error)
@@ -209,6 +299,15 @@ func (c *controlServiceClient) TransformElasticSubnets(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *controlServiceClient) GetElasticSubnet(ctx context.Context, in *GetElasticSubnetRequest, opts ...grpc.CallOption) (*GetElasticSubnetResponse, error) {
+	out := new(GetElasticSubnetResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetElasticSubnet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) AddPermissionlessValidator(ctx context.Context, in *AddPermissionlessValidatorRequest, opts ...grpc.CallOption) (*AddPermissionlessValidatorResponse, error) {
 	out := new(AddPermissionlessValidatorResponse)
 	err := c.cc.Invoke(ctx, ControlService_AddPermissionlessValidator_FullMethodName, in, out, opts...)
@@ -227,6 +326,168 @@ func (c *controlServiceClient) RemoveSubnetValidator(ctx context.Context, in *Re
 	return out, nil
 }
 
+func (c *controlServiceClient) ListBlockchains(ctx context.Context, in *ListBlockchainsRequest, opts ...grpc.CallOption) (*ListBlockchainsResponse, error) {
+	out := new(ListBlockchainsResponse)
+	err := c.cc.Invoke(ctx, ControlService_ListBlockchains_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetBlockchainStatus(ctx context.Context, in *GetBlockchainStatusRequest, opts ...grpc.CallOption) (*GetBlockchainStatusResponse, error) {
+	out := new(GetBlockchainStatusResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetBlockchainStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) AddBlockchainAlias(ctx context.Context, in *AddBlockchainAliasRequest, opts ...grpc.CallOption) (*AddBlockchainAliasResponse, error) {
+	out := new(AddBlockchainAliasResponse)
+	err := c.cc.Invoke(ctx, ControlService_AddBlockchainAlias_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) RemoveBlockchainAlias(ctx context.Context, in *RemoveBlockchainAliasRequest, opts ...grpc.CallOption) (*RemoveBlockchainAliasResponse, error) {
+	out := new(RemoveBlockchainAliasResponse)
+	err := c.cc.Invoke(ctx, ControlService_RemoveBlockchainAlias_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) AddVMAlias(ctx context.Context, in *AddVMAliasRequest, opts ...grpc.CallOption) (*AddVMAliasResponse, error) {
+	out := new(AddVMAliasResponse)
+	err := c.cc.Invoke(ctx, ControlService_AddVMAlias_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) TransferSubnetOwnership(ctx context.Context, in *TransferSubnetOwnershipRequest, opts ...grpc.CallOption) (*TransferSubnetOwnershipResponse, error) {
+	out := new(TransferSubnetOwnershipResponse)
+	err := c.cc.Invoke(ctx, ControlService_TransferSubnetOwnership_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) CreateAsset(ctx context.Context, in *CreateAssetRequest, opts ...grpc.CallOption) (*CreateAssetResponse, error) {
+	out := new(CreateAssetResponse)
+	err := c.cc.Invoke(ctx, ControlService_CreateAsset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error) {
+	out := new(TransferResponse)
+	err := c.cc.Invoke(ctx, ControlService_Transfer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) Stake(ctx context.Context, in *StakeRequest, opts ...grpc.CallOption) (*StakeResponse, error) {
+	out := new(StakeResponse)
+	err := c.cc.Invoke(ctx, ControlService_Stake_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) RenewPrimaryValidations(ctx context.Context, in *RenewPrimaryValidationsRequest, opts ...grpc.CallOption) (*RenewPrimaryValidationsResponse, error) {
+	out := new(RenewPrimaryValidationsResponse)
+	err := c.cc.Invoke(ctx, ControlService_RenewPrimaryValidations_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetStakingRewards(ctx context.Context, in *GetStakingRewardsRequest, opts ...grpc.CallOption) (*GetStakingRewardsResponse, error) {
+	out := new(GetStakingRewardsResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetStakingRewards_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) WaitForValidatorRewards(ctx context.Context, in *WaitForValidatorRewardsRequest, opts ...grpc.CallOption) (*WaitForValidatorRewardsResponse, error) {
+	out := new(WaitForValidatorRewardsResponse)
+	err := c.cc.Invoke(ctx, ControlService_WaitForValidatorRewards_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetUptimes(ctx context.Context, in *GetUptimesRequest, opts ...grpc.CallOption) (*GetUptimesResponse, error) {
+	out := new(GetUptimesResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetUptimes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetVersions(ctx context.Context, in *GetVersionsRequest, opts ...grpc.CallOption) (*GetVersionsResponse, error) {
+	out := new(GetVersionsResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetVersions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetTopology(ctx context.Context, in *GetTopologyRequest, opts ...grpc.CallOption) (*GetTopologyResponse, error) {
+	out := new(GetTopologyResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetTopology_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ExportNetwork(ctx context.Context, in *ExportNetworkRequest, opts ...grpc.CallOption) (*ExportNetworkResponse, error) {
+	out := new(ExportNetworkResponse)
+	err := c.cc.Invoke(ctx, ControlService_ExportNetwork_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) CheckConnectivity(ctx context.Context, in *CheckConnectivityRequest, opts ...grpc.CallOption) (*CheckConnectivityResponse, error) {
+	out := new(CheckConnectivityResponse)
+	err := c.cc.Invoke(ctx, ControlService_CheckConnectivity_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetOperationHistory(ctx context.Context, in *GetOperationHistoryRequest, opts ...grpc.CallOption) (*GetOperationHistoryResponse, error) {
+	out := new(GetOperationHistoryResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetOperationHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) CreateSubnets(ctx context.Context, in *CreateSubnetsRequest, opts ...grpc.CallOption) (*CreateSubnetsResponse, error) {
 	out := new(CreateSubnetsResponse)
 	err := c.cc.Invoke(ctx, ControlService_CreateSubnets_FullMethodName, in, out, opts...)
@@ -263,6 +524,24 @@ func (c *controlServiceClient) WaitForHealthy(ctx context.Context, in *WaitForHe
 	return out, nil
 }
 
+func (c *controlServiceClient) WaitFor(ctx context.Context, in *WaitForRequest, opts ...grpc.CallOption) (*WaitForResponse, error) {
+	out := new(WaitForResponse)
+	err := c.cc.Invoke(ctx, ControlService_WaitFor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) Assert(ctx context.Context, in *AssertRequest, opts ...grpc.CallOption) (*AssertResponse, error) {
+	out := new(AssertResponse)
+	err := c.cc.Invoke(ctx, ControlService_Assert_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
 	out := new(StatusResponse)
 	err := c.cc.Invoke(ctx, ControlService_Status_FullMethodName, in, out, opts...)
@@ -272,6 +551,33 @@ func (c *controlServiceClient) Status(ctx context.Context, in *StatusRequest, op
 	return out, nil
 }
 
+func (c *controlServiceClient) GetNodeInfo(ctx context.Context, in *GetNodeInfoRequest, opts ...grpc.CallOption) (*GetNodeInfoResponse, error) {
+	out := new(GetNodeInfoResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetNodeInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error) {
+	out := new(ListNodesResponse)
+	err := c.cc.Invoke(ctx, ControlService_ListNodes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ListCustomChains(ctx context.Context, in *ListCustomChainsRequest, opts ...grpc.CallOption) (*ListCustomChainsResponse, error) {
+	out := new(ListCustomChainsResponse)
+	err := c.cc.Invoke(ctx, ControlService_ListCustomChains_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) StreamStatus(ctx context.Context, in *StreamStatusRequest, opts ...grpc.CallOption) (ControlService_StreamStatusClient, error) {
 	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[0], ControlService_StreamStatus_FullMethodName, opts...)
 	if err != nil {
@@ -304,6 +610,47 @@ func (x *controlServiceStreamStatusClient) Recv() (*StreamStatusResponse, error)
 	return m, nil
 }
 
+func (c *controlServiceClient) StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (ControlService_StreamLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[1], ControlService_StreamLogs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlServiceStreamLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ControlService_StreamLogsClient interface {
+	Recv() (*StreamLogsResponse, error)
+	grpc.ClientStream
+}
+
+type controlServiceStreamLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlServiceStreamLogsClient) Recv() (*StreamLogsResponse, error) {
+	m := new(StreamLogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlServiceClient) SearchLogs(ctx context.Context, in *SearchLogsRequest, opts ...grpc.CallOption) (*SearchLogsResponse, error) {
+	out := new(SearchLogsResponse)
+	err := c.cc.Invoke(ctx, ControlService_SearchLogs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) RemoveNode(ctx context.Context, in *RemoveNodeRequest, opts ...grpc.CallOption) (*RemoveNodeResponse, error) {
 	out := new(RemoveNodeResponse)
 	err := c.cc.Invoke(ctx, ControlService_RemoveNode_FullMethodName, in, out, opts...)
@@ -313,6 +660,15 @@ func (c *controlServiceClient) RemoveNode(ctx context.Context, in *RemoveNodeReq
 	return out, nil
 }
 
+func (c *controlServiceClient) DrainNode(ctx context.Context, in *DrainNodeRequest, opts ...grpc.CallOption) (*DrainNodeResponse, error) {
+	out := new(DrainNodeResponse)
+	err := c.cc.Invoke(ctx, ControlService_DrainNode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) AddNode(ctx context.Context, in *AddNodeRequest, opts ...grpc.CallOption) (*AddNodeResponse, error) {
 	out := new(AddNodeResponse)
 	err := c.cc.Invoke(ctx, ControlService_AddNode_FullMethodName, in, out, opts...)
@@ -322,6 +678,83 @@ func (c *controlServiceClient) AddNode(ctx context.Context, in *AddNodeRequest,
 	return out, nil
 }
 
+func (c *controlServiceClient) ScaleNetwork(ctx context.Context, in *ScaleNetworkRequest, opts ...grpc.CallOption) (*ScaleNetworkResponse, error) {
+	out := new(ScaleNetworkResponse)
+	err := c.cc.Invoke(ctx, ControlService_ScaleNetwork_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ResetNetwork(ctx context.Context, in *ResetNetworkRequest, opts ...grpc.CallOption) (*ResetNetworkResponse, error) {
+	out := new(ResetNetworkResponse)
+	err := c.cc.Invoke(ctx, ControlService_ResetNetwork_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) StateSyncNode(ctx context.Context, in *StateSyncNodeRequest, opts ...grpc.CallOption) (ControlService_StateSyncNodeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[2], ControlService_StateSyncNode_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlServiceStateSyncNodeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ControlService_StateSyncNodeClient interface {
+	Recv() (*StateSyncNodeResponse, error)
+	grpc.ClientStream
+}
+
+type controlServiceStateSyncNodeClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlServiceStateSyncNodeClient) Recv() (*StateSyncNodeResponse, error) {
+	m := new(StateSyncNodeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlServiceClient) ExportCChainState(ctx context.Context, in *ExportCChainStateRequest, opts ...grpc.CallOption) (*ExportCChainStateResponse, error) {
+	out := new(ExportCChainStateResponse)
+	err := c.cc.Invoke(ctx, ControlService_ExportCChainState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetDBSizes(ctx context.Context, in *GetDBSizesRequest, opts ...grpc.CallOption) (*GetDBSizesResponse, error) {
+	out := new(GetDBSizesResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetDBSizes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) PruneNodeDB(ctx context.Context, in *PruneNodeDBRequest, opts ...grpc.CallOption) (*PruneNodeDBResponse, error) {
+	out := new(PruneNodeDBResponse)
+	err := c.cc.Invoke(ctx, ControlService_PruneNodeDB_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *controlServiceClient) RestartNode(ctx context.Context, in *RestartNodeRequest, opts ...grpc.CallOption) (*RestartNodeResponse, error) {
 	out := new(RestartNodeResponse)
 	err := c.cc.Invoke(ctx, ControlService_RestartNode_FullMethodName, in, out, opts...)
@@ -349,64 +782,218 @@ func (c *controlServiceClient) ResumeNode(ctx context.Context, in *ResumeNodeReq
 	return out, nil
 }
 
-func (c *controlServiceClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
-	out := new(StopResponse)
-	err := c.cc.Invoke(ctx, ControlService_Stop_FullMethodName, in, out, opts...)
+func (c *controlServiceClient) PauseAll(ctx context.Context, in *PauseAllRequest, opts ...grpc.CallOption) (*PauseAllResponse, error) {
+	out := new(PauseAllResponse)
+	err := c.cc.Invoke(ctx, ControlService_PauseAll_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *controlServiceClient) AttachPeer(ctx context.Context, in *AttachPeerRequest, opts ...grpc.CallOption) (*AttachPeerResponse, error) {
-	out := new(AttachPeerResponse)
-	err := c.cc.Invoke(ctx, ControlService_AttachPeer_FullMethodName, in, out, opts...)
+func (c *controlServiceClient) ResumeAll(ctx context.Context, in *ResumeAllRequest, opts ...grpc.CallOption) (*ResumeAllResponse, error) {
+	out := new(ResumeAllResponse)
+	err := c.cc.Invoke(ctx, ControlService_ResumeAll_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *controlServiceClient) SendOutboundMessage(ctx context.Context, in *SendOutboundMessageRequest, opts ...grpc.CallOption) (*SendOutboundMessageResponse, error) {
-	out := new(SendOutboundMessageResponse)
-	err := c.cc.Invoke(ctx, ControlService_SendOutboundMessage_FullMethodName, in, out, opts...)
+func (c *controlServiceClient) SimulateDowntime(ctx context.Context, in *SimulateDowntimeRequest, opts ...grpc.CallOption) (*SimulateDowntimeResponse, error) {
+	out := new(SimulateDowntimeResponse)
+	err := c.cc.Invoke(ctx, ControlService_SimulateDowntime_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *controlServiceClient) SaveSnapshot(ctx context.Context, in *SaveSnapshotRequest, opts ...grpc.CallOption) (*SaveSnapshotResponse, error) {
-	out := new(SaveSnapshotResponse)
-	err := c.cc.Invoke(ctx, ControlService_SaveSnapshot_FullMethodName, in, out, opts...)
+func (c *controlServiceClient) StartChaos(ctx context.Context, in *StartChaosRequest, opts ...grpc.CallOption) (*StartChaosResponse, error) {
+	out := new(StartChaosResponse)
+	err := c.cc.Invoke(ctx, ControlService_StartChaos_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *controlServiceClient) LoadSnapshot(ctx context.Context, in *LoadSnapshotRequest, opts ...grpc.CallOption) (*LoadSnapshotResponse, error) {
-	out := new(LoadSnapshotResponse)
-	err := c.cc.Invoke(ctx, ControlService_LoadSnapshot_FullMethodName, in, out, opts...)
+func (c *controlServiceClient) StopChaos(ctx context.Context, in *StopChaosRequest, opts ...grpc.CallOption) (*StopChaosResponse, error) {
+	out := new(StopChaosResponse)
+	err := c.cc.Invoke(ctx, ControlService_StopChaos_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *controlServiceClient) RemoveSnapshot(ctx context.Context, in *RemoveSnapshotRequest, opts ...grpc.CallOption) (*RemoveSnapshotResponse, error) {
-	out := new(RemoveSnapshotResponse)
-	err := c.cc.Invoke(ctx, ControlService_RemoveSnapshot_FullMethodName, in, out, opts...)
+func (c *controlServiceClient) GetChaosStatus(ctx context.Context, in *GetChaosStatusRequest, opts ...grpc.CallOption) (*GetChaosStatusResponse, error) {
+	out := new(GetChaosStatusResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetChaosStatus_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *controlServiceClient) GetSnapshotNames(ctx context.Context, in *GetSnapshotNamesRequest, opts ...grpc.CallOption) (*GetSnapshotNamesResponse, error) {
-	out := new(GetSnapshotNamesResponse)
-	err := c.cc.Invoke(ctx, ControlService_GetSnapshotNames_FullMethodName, in, out, opts...)
-	if err != nil {
+func (c *controlServiceClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error) {
+	out := new(StopResponse)
+	err := c.cc.Invoke(ctx, ControlService_Stop_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) AttachPeer(ctx context.Context, in *AttachPeerRequest, opts ...grpc.CallOption) (*AttachPeerResponse, error) {
+	out := new(AttachPeerResponse)
+	err := c.cc.Invoke(ctx, ControlService_AttachPeer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) SendOutboundMessage(ctx context.Context, in *SendOutboundMessageRequest, opts ...grpc.CallOption) (*SendOutboundMessageResponse, error) {
+	out := new(SendOutboundMessageResponse)
+	err := c.cc.Invoke(ctx, ControlService_SendOutboundMessage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) SendPeerMessage(ctx context.Context, in *SendPeerMessageRequest, opts ...grpc.CallOption) (*SendPeerMessageResponse, error) {
+	out := new(SendPeerMessageResponse)
+	err := c.cc.Invoke(ctx, ControlService_SendPeerMessage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) SendAppRequest(ctx context.Context, in *SendAppRequestRequest, opts ...grpc.CallOption) (*SendAppRequestResponse, error) {
+	out := new(SendAppRequestResponse)
+	err := c.cc.Invoke(ctx, ControlService_SendAppRequest_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) StreamPeerMessages(ctx context.Context, in *StreamPeerMessagesRequest, opts ...grpc.CallOption) (ControlService_StreamPeerMessagesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[3], ControlService_StreamPeerMessages_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlServiceStreamPeerMessagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ControlService_StreamPeerMessagesClient interface {
+	Recv() (*StreamPeerMessagesResponse, error)
+	grpc.ClientStream
+}
+
+type controlServiceStreamPeerMessagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlServiceStreamPeerMessagesClient) Recv() (*StreamPeerMessagesResponse, error) {
+	m := new(StreamPeerMessagesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlServiceClient) StreamProgress(ctx context.Context, in *StreamProgressRequest, opts ...grpc.CallOption) (ControlService_StreamProgressClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[4], ControlService_StreamProgress_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlServiceStreamProgressClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ControlService_StreamProgressClient interface {
+	Recv() (*StreamProgressResponse, error)
+	grpc.ClientStream
+}
+
+type controlServiceStreamProgressClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlServiceStreamProgressClient) Recv() (*StreamProgressResponse, error) {
+	m := new(StreamProgressResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlServiceClient) RecordPeerMessages(ctx context.Context, in *RecordPeerMessagesRequest, opts ...grpc.CallOption) (*RecordPeerMessagesResponse, error) {
+	out := new(RecordPeerMessagesResponse)
+	err := c.cc.Invoke(ctx, ControlService_RecordPeerMessages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ReplayPeerMessages(ctx context.Context, in *ReplayPeerMessagesRequest, opts ...grpc.CallOption) (*ReplayPeerMessagesResponse, error) {
+	out := new(ReplayPeerMessagesResponse)
+	err := c.cc.Invoke(ctx, ControlService_ReplayPeerMessages_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) SaveSnapshot(ctx context.Context, in *SaveSnapshotRequest, opts ...grpc.CallOption) (*SaveSnapshotResponse, error) {
+	out := new(SaveSnapshotResponse)
+	err := c.cc.Invoke(ctx, ControlService_SaveSnapshot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) LoadSnapshot(ctx context.Context, in *LoadSnapshotRequest, opts ...grpc.CallOption) (*LoadSnapshotResponse, error) {
+	out := new(LoadSnapshotResponse)
+	err := c.cc.Invoke(ctx, ControlService_LoadSnapshot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) RemoveSnapshot(ctx context.Context, in *RemoveSnapshotRequest, opts ...grpc.CallOption) (*RemoveSnapshotResponse, error) {
+	out := new(RemoveSnapshotResponse)
+	err := c.cc.Invoke(ctx, ControlService_RemoveSnapshot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) GetSnapshotNames(ctx context.Context, in *GetSnapshotNamesRequest, opts ...grpc.CallOption) (*GetSnapshotNamesResponse, error) {
+	out := new(GetSnapshotNamesResponse)
+	err := c.cc.Invoke(ctx, ControlService_GetSnapshotNames_FullMethodName, in, out, opts...)
+	if err != nil {
 		return nil, err
 	}
 	return out, nil
@@ -420,22 +1007,67 @@ type ControlServiceServer interface {
 	Start(context.Context, *StartRequest) (*StartResponse, error)
 	CreateBlockchains(context.Context, *CreateBlockchainsRequest) (*CreateBlockchainsResponse, error)
 	TransformElasticSubnets(context.Context, *TransformElasticSubnetsRequest) (*TransformElasticSubnetsResponse, error)
+	GetElasticSubnet(context.Context, *GetElasticSubnetRequest) (*GetElasticSubnetResponse, error)
 	AddPermissionlessValidator(context.Context, *AddPermissionlessValidatorRequest) (*AddPermissionlessValidatorResponse, error)
 	RemoveSubnetValidator(context.Context, *RemoveSubnetValidatorRequest) (*RemoveSubnetValidatorResponse, error)
+	ListBlockchains(context.Context, *ListBlockchainsRequest) (*ListBlockchainsResponse, error)
+	GetBlockchainStatus(context.Context, *GetBlockchainStatusRequest) (*GetBlockchainStatusResponse, error)
+	AddBlockchainAlias(context.Context, *AddBlockchainAliasRequest) (*AddBlockchainAliasResponse, error)
+	RemoveBlockchainAlias(context.Context, *RemoveBlockchainAliasRequest) (*RemoveBlockchainAliasResponse, error)
+	AddVMAlias(context.Context, *AddVMAliasRequest) (*AddVMAliasResponse, error)
+	TransferSubnetOwnership(context.Context, *TransferSubnetOwnershipRequest) (*TransferSubnetOwnershipResponse, error)
+	CreateAsset(context.Context, *CreateAssetRequest) (*CreateAssetResponse, error)
+	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
+	Stake(context.Context, *StakeRequest) (*StakeResponse, error)
+	RenewPrimaryValidations(context.Context, *RenewPrimaryValidationsRequest) (*RenewPrimaryValidationsResponse, error)
+	GetStakingRewards(context.Context, *GetStakingRewardsRequest) (*GetStakingRewardsResponse, error)
+	WaitForValidatorRewards(context.Context, *WaitForValidatorRewardsRequest) (*WaitForValidatorRewardsResponse, error)
+	GetUptimes(context.Context, *GetUptimesRequest) (*GetUptimesResponse, error)
+	GetVersions(context.Context, *GetVersionsRequest) (*GetVersionsResponse, error)
+	GetTopology(context.Context, *GetTopologyRequest) (*GetTopologyResponse, error)
+	ExportNetwork(context.Context, *ExportNetworkRequest) (*ExportNetworkResponse, error)
+	CheckConnectivity(context.Context, *CheckConnectivityRequest) (*CheckConnectivityResponse, error)
+	GetOperationHistory(context.Context, *GetOperationHistoryRequest) (*GetOperationHistoryResponse, error)
 	CreateSubnets(context.Context, *CreateSubnetsRequest) (*CreateSubnetsResponse, error)
 	Health(context.Context, *HealthRequest) (*HealthResponse, error)
 	URIs(context.Context, *URIsRequest) (*URIsResponse, error)
 	WaitForHealthy(context.Context, *WaitForHealthyRequest) (*WaitForHealthyResponse, error)
+	WaitFor(context.Context, *WaitForRequest) (*WaitForResponse, error)
+	Assert(context.Context, *AssertRequest) (*AssertResponse, error)
 	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	GetNodeInfo(context.Context, *GetNodeInfoRequest) (*GetNodeInfoResponse, error)
+	ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error)
+	ListCustomChains(context.Context, *ListCustomChainsRequest) (*ListCustomChainsResponse, error)
 	StreamStatus(*StreamStatusRequest, ControlService_StreamStatusServer) error
+	StreamLogs(*StreamLogsRequest, ControlService_StreamLogsServer) error
+	SearchLogs(context.Context, *SearchLogsRequest) (*SearchLogsResponse, error)
 	RemoveNode(context.Context, *RemoveNodeRequest) (*RemoveNodeResponse, error)
+	DrainNode(context.Context, *DrainNodeRequest) (*DrainNodeResponse, error)
 	AddNode(context.Context, *AddNodeRequest) (*AddNodeResponse, error)
+	ScaleNetwork(context.Context, *ScaleNetworkRequest) (*ScaleNetworkResponse, error)
+	ResetNetwork(context.Context, *ResetNetworkRequest) (*ResetNetworkResponse, error)
+	StateSyncNode(*StateSyncNodeRequest, ControlService_StateSyncNodeServer) error
+	ExportCChainState(context.Context, *ExportCChainStateRequest) (*ExportCChainStateResponse, error)
+	GetDBSizes(context.Context, *GetDBSizesRequest) (*GetDBSizesResponse, error)
+	PruneNodeDB(context.Context, *PruneNodeDBRequest) (*PruneNodeDBResponse, error)
 	RestartNode(context.Context, *RestartNodeRequest) (*RestartNodeResponse, error)
 	PauseNode(context.Context, *PauseNodeRequest) (*PauseNodeResponse, error)
 	ResumeNode(context.Context, *ResumeNodeRequest) (*ResumeNodeResponse, error)
+	PauseAll(context.Context, *PauseAllRequest) (*PauseAllResponse, error)
+	ResumeAll(context.Context, *ResumeAllRequest) (*ResumeAllResponse, error)
+	SimulateDowntime(context.Context, *SimulateDowntimeRequest) (*SimulateDowntimeResponse, error)
+	StartChaos(context.Context, *StartChaosRequest) (*StartChaosResponse, error)
+	StopChaos(context.Context, *StopChaosRequest) (*StopChaosResponse, error)
+	GetChaosStatus(context.Context, *GetChaosStatusRequest) (*GetChaosStatusResponse, error)
 	Stop(context.Context, *StopRequest) (*StopResponse, error)
 	AttachPeer(context.Context, *AttachPeerRequest) (*AttachPeerResponse, error)
 	SendOutboundMessage(context.Context, *SendOutboundMessageRequest) (*SendOutboundMessageResponse, error)
+	SendPeerMessage(context.Context, *SendPeerMessageRequest) (*SendPeerMessageResponse, error)
+	SendAppRequest(context.Context, *SendAppRequestRequest) (*SendAppRequestResponse, error)
+	StreamPeerMessages(*StreamPeerMessagesRequest, ControlService_StreamPeerMessagesServer) error
+	StreamProgress(*StreamProgressRequest, ControlService_StreamProgressServer) error
+	RecordPeerMessages(context.Context, *RecordPeerMessagesRequest) (*RecordPeerMessagesResponse, error)
+	ReplayPeerMessages(context.Context, *ReplayPeerMessagesRequest) (*ReplayPeerMessagesResponse, error)
 	SaveSnapshot(context.Context, *SaveSnapshotRequest) (*SaveSnapshotResponse, error)
 	LoadSnapshot(context.Context, *LoadSnapshotRequest) (*LoadSnapshotResponse, error)
 	RemoveSnapshot(context.Context, *RemoveSnapshotRequest) (*RemoveSnapshotResponse, error)
@@ -459,12 +1091,69 @@ func (UnimplementedControlServiceServer) CreateBlockchains(context.Context, *Cre
 func (UnimplementedControlServiceServer) TransformElasticSubnets(context.Context, *TransformElasticSubnetsRequest) (*TransformElasticSubnetsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TransformElasticSubnets not implemented")
 }
+func (UnimplementedControlServiceServer) GetElasticSubnet(context.Context, *GetElasticSubnetRequest) (*GetElasticSubnetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetElasticSubnet not implemented")
+}
 func (UnimplementedControlServiceServer) AddPermissionlessValidator(context.Context, *AddPermissionlessValidatorRequest) (*AddPermissionlessValidatorResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddPermissionlessValidator not implemented")
 }
 func (UnimplementedControlServiceServer) RemoveSubnetValidator(context.Context, *RemoveSubnetValidatorRequest) (*RemoveSubnetValidatorResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveSubnetValidator not implemented")
 }
+func (UnimplementedControlServiceServer) ListBlockchains(context.Context, *ListBlockchainsRequest) (*ListBlockchainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBlockchains not implemented")
+}
+func (UnimplementedControlServiceServer) GetBlockchainStatus(context.Context, *GetBlockchainStatusRequest) (*GetBlockchainStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockchainStatus not implemented")
+}
+func (UnimplementedControlServiceServer) AddBlockchainAlias(context.Context, *AddBlockchainAliasRequest) (*AddBlockchainAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddBlockchainAlias not implemented")
+}
+func (UnimplementedControlServiceServer) RemoveBlockchainAlias(context.Context, *RemoveBlockchainAliasRequest) (*RemoveBlockchainAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBlockchainAlias not implemented")
+}
+func (UnimplementedControlServiceServer) AddVMAlias(context.Context, *AddVMAliasRequest) (*AddVMAliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddVMAlias not implemented")
+}
+func (UnimplementedControlServiceServer) TransferSubnetOwnership(context.Context, *TransferSubnetOwnershipRequest) (*TransferSubnetOwnershipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferSubnetOwnership not implemented")
+}
+func (UnimplementedControlServiceServer) CreateAsset(context.Context, *CreateAssetRequest) (*CreateAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAsset not implemented")
+}
+func (UnimplementedControlServiceServer) Transfer(context.Context, *TransferRequest) (*TransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
+}
+func (UnimplementedControlServiceServer) Stake(context.Context, *StakeRequest) (*StakeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stake not implemented")
+}
+func (UnimplementedControlServiceServer) RenewPrimaryValidations(context.Context, *RenewPrimaryValidationsRequest) (*RenewPrimaryValidationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewPrimaryValidations not implemented")
+}
+func (UnimplementedControlServiceServer) GetStakingRewards(context.Context, *GetStakingRewardsRequest) (*GetStakingRewardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStakingRewards not implemented")
+}
+func (UnimplementedControlServiceServer) WaitForValidatorRewards(context.Context, *WaitForValidatorRewardsRequest) (*WaitForValidatorRewardsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WaitForValidatorRewards not implemented")
+}
+func (UnimplementedControlServiceServer) GetUptimes(context.Context, *GetUptimesRequest) (*GetUptimesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUptimes not implemented")
+}
+func (UnimplementedControlServiceServer) GetVersions(context.Context, *GetVersionsRequest) (*GetVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersions not implemented")
+}
+func (UnimplementedControlServiceServer) GetTopology(context.Context, *GetTopologyRequest) (*GetTopologyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopology not implemented")
+}
+func (UnimplementedControlServiceServer) ExportNetwork(context.Context, *ExportNetworkRequest) (*ExportNetworkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportNetwork not implemented")
+}
+func (UnimplementedControlServiceServer) CheckConnectivity(context.Context, *CheckConnectivityRequest) (*CheckConnectivityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckConnectivity not implemented")
+}
+func (UnimplementedControlServiceServer) GetOperationHistory(context.Context, *GetOperationHistoryRequest) (*GetOperationHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOperationHistory not implemented")
+}
 func (UnimplementedControlServiceServer) CreateSubnets(context.Context, *CreateSubnetsRequest) (*CreateSubnetsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateSubnets not implemented")
 }
@@ -477,18 +1166,60 @@ func (UnimplementedControlServiceServer) URIs(context.Context, *URIsRequest) (*U
 func (UnimplementedControlServiceServer) WaitForHealthy(context.Context, *WaitForHealthyRequest) (*WaitForHealthyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WaitForHealthy not implemented")
 }
+func (UnimplementedControlServiceServer) WaitFor(context.Context, *WaitForRequest) (*WaitForResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WaitFor not implemented")
+}
+func (UnimplementedControlServiceServer) Assert(context.Context, *AssertRequest) (*AssertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Assert not implemented")
+}
 func (UnimplementedControlServiceServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (UnimplementedControlServiceServer) GetNodeInfo(context.Context, *GetNodeInfoRequest) (*GetNodeInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeInfo not implemented")
+}
+func (UnimplementedControlServiceServer) ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedControlServiceServer) ListCustomChains(context.Context, *ListCustomChainsRequest) (*ListCustomChainsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCustomChains not implemented")
+}
 func (UnimplementedControlServiceServer) StreamStatus(*StreamStatusRequest, ControlService_StreamStatusServer) error {
 	return status.Errorf(codes.Unimplemented, "method StreamStatus not implemented")
 }
+func (UnimplementedControlServiceServer) StreamLogs(*StreamLogsRequest, ControlService_StreamLogsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLogs not implemented")
+}
+func (UnimplementedControlServiceServer) SearchLogs(context.Context, *SearchLogsRequest) (*SearchLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchLogs not implemented")
+}
 func (UnimplementedControlServiceServer) RemoveNode(context.Context, *RemoveNodeRequest) (*RemoveNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveNode not implemented")
 }
+func (UnimplementedControlServiceServer) DrainNode(context.Context, *DrainNodeRequest) (*DrainNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainNode not implemented")
+}
 func (UnimplementedControlServiceServer) AddNode(context.Context, *AddNodeRequest) (*AddNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddNode not implemented")
 }
+func (UnimplementedControlServiceServer) ScaleNetwork(context.Context, *ScaleNetworkRequest) (*ScaleNetworkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScaleNetwork not implemented")
+}
+func (UnimplementedControlServiceServer) ResetNetwork(context.Context, *ResetNetworkRequest) (*ResetNetworkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetNetwork not implemented")
+}
+func (UnimplementedControlServiceServer) StateSyncNode(*StateSyncNodeRequest, ControlService_StateSyncNodeServer) error {
+	return status.Errorf(codes.Unimplemented, "method StateSyncNode not implemented")
+}
+func (UnimplementedControlServiceServer) ExportCChainState(context.Context, *ExportCChainStateRequest) (*ExportCChainStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportCChainState not implemented")
+}
+func (UnimplementedControlServiceServer) GetDBSizes(context.Context, *GetDBSizesRequest) (*GetDBSizesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDBSizes not implemented")
+}
+func (UnimplementedControlServiceServer) PruneNodeDB(context.Context, *PruneNodeDBRequest) (*PruneNodeDBResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PruneNodeDB not implemented")
+}
 func (UnimplementedControlServiceServer) RestartNode(context.Context, *RestartNodeRequest) (*RestartNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RestartNode not implemented")
 }
@@ -498,6 +1229,24 @@ func (UnimplementedControlServiceServer) PauseNode(context.Context, *PauseNodeRe
 func (UnimplementedControlServiceServer) ResumeNode(context.Context, *ResumeNodeRequest) (*ResumeNodeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ResumeNode not implemented")
 }
+func (UnimplementedControlServiceServer) PauseAll(context.Context, *PauseAllRequest) (*PauseAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseAll not implemented")
+}
+func (UnimplementedControlServiceServer) ResumeAll(context.Context, *ResumeAllRequest) (*ResumeAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeAll not implemented")
+}
+func (UnimplementedControlServiceServer) SimulateDowntime(context.Context, *SimulateDowntimeRequest) (*SimulateDowntimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SimulateDowntime not implemented")
+}
+func (UnimplementedControlServiceServer) StartChaos(context.Context, *StartChaosRequest) (*StartChaosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartChaos not implemented")
+}
+func (UnimplementedControlServiceServer) StopChaos(context.Context, *StopChaosRequest) (*StopChaosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopChaos not implemented")
+}
+func (UnimplementedControlServiceServer) GetChaosStatus(context.Context, *GetChaosStatusRequest) (*GetChaosStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChaosStatus not implemented")
+}
 func (UnimplementedControlServiceServer) Stop(context.Context, *StopRequest) (*StopResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
 }
@@ -507,390 +1256,1230 @@ func (UnimplementedControlServiceServer) AttachPeer(context.Context, *AttachPeer
 func (UnimplementedControlServiceServer) SendOutboundMessage(context.Context, *SendOutboundMessageRequest) (*SendOutboundMessageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendOutboundMessage not implemented")
 }
+func (UnimplementedControlServiceServer) SendPeerMessage(context.Context, *SendPeerMessageRequest) (*SendPeerMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendPeerMessage not implemented")
+}
+func (UnimplementedControlServiceServer) SendAppRequest(context.Context, *SendAppRequestRequest) (*SendAppRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendAppRequest not implemented")
+}
+func (UnimplementedControlServiceServer) StreamPeerMessages(*StreamPeerMessagesRequest, ControlService_StreamPeerMessagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPeerMessages not implemented")
+}
+func (UnimplementedControlServiceServer) StreamProgress(*StreamProgressRequest, ControlService_StreamProgressServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamProgress not implemented")
+}
+func (UnimplementedControlServiceServer) RecordPeerMessages(context.Context, *RecordPeerMessagesRequest) (*RecordPeerMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordPeerMessages not implemented")
+}
+func (UnimplementedControlServiceServer) ReplayPeerMessages(context.Context, *ReplayPeerMessagesRequest) (*ReplayPeerMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayPeerMessages not implemented")
+}
 func (UnimplementedControlServiceServer) SaveSnapshot(context.Context, *SaveSnapshotRequest) (*SaveSnapshotResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SaveSnapshot not implemented")
 }
-func (UnimplementedControlServiceServer) LoadSnapshot(context.Context, *LoadSnapshotRequest) (*LoadSnapshotResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method LoadSnapshot not implemented")
+func (UnimplementedControlServiceServer) LoadSnapshot(context.Context, *LoadSnapshotRequest) (*LoadSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadSnapshot not implemented")
+}
+func (UnimplementedControlServiceServer) RemoveSnapshot(context.Context, *RemoveSnapshotRequest) (*RemoveSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveSnapshot not implemented")
+}
+func (UnimplementedControlServiceServer) GetSnapshotNames(context.Context, *GetSnapshotNamesRequest) (*GetSnapshotNamesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshotNames not implemented")
+}
+func (UnimplementedControlServiceServer) mustEmbedUnimplementedControlServiceServer() {}
+
+// UnsafeControlServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ControlServiceServer will
+// result in compilation errors.
+type UnsafeControlServiceServer interface {
+	mustEmbedUnimplementedControlServiceServer()
+}
+
+func RegisterControlServiceServer(s grpc.ServiceRegistrar, srv ControlServiceServer) {
+	s.RegisterService(&ControlService_ServiceDesc, srv)
+}
+
+func _ControlService_RPCVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RPCVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).RPCVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_RPCVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).RPCVersion(ctx, req.(*RPCVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).Start(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_Start_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).Start(ctx, req.(*StartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateBlockchains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBlockchainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateBlockchains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CreateBlockchains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateBlockchains(ctx, req.(*CreateBlockchainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_TransformElasticSubnets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransformElasticSubnetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).TransformElasticSubnets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_TransformElasticSubnets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).TransformElasticSubnets(ctx, req.(*TransformElasticSubnetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetElasticSubnet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetElasticSubnetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetElasticSubnet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetElasticSubnet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetElasticSubnet(ctx, req.(*GetElasticSubnetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_AddPermissionlessValidator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPermissionlessValidatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).AddPermissionlessValidator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_AddPermissionlessValidator_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).AddPermissionlessValidator(ctx, req.(*AddPermissionlessValidatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_RemoveSubnetValidator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveSubnetValidatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).RemoveSubnetValidator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_RemoveSubnetValidator_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).RemoveSubnetValidator(ctx, req.(*RemoveSubnetValidatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListBlockchains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBlockchainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListBlockchains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ListBlockchains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListBlockchains(ctx, req.(*ListBlockchainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetBlockchainStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockchainStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetBlockchainStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetBlockchainStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetBlockchainStatus(ctx, req.(*GetBlockchainStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_AddBlockchainAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddBlockchainAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).AddBlockchainAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_AddBlockchainAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).AddBlockchainAlias(ctx, req.(*AddBlockchainAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_RemoveBlockchainAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBlockchainAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).RemoveBlockchainAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_RemoveBlockchainAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).RemoveBlockchainAlias(ctx, req.(*RemoveBlockchainAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_AddVMAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddVMAliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).AddVMAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_AddVMAlias_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).AddVMAlias(ctx, req.(*AddVMAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_TransferSubnetOwnership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferSubnetOwnershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).TransferSubnetOwnership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_TransferSubnetOwnership_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).TransferSubnetOwnership(ctx, req.(*TransferSubnetOwnershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CreateAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateAsset(ctx, req.(*CreateAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_Transfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_Stake_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).Stake(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_Stake_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).Stake(ctx, req.(*StakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_RenewPrimaryValidations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewPrimaryValidationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).RenewPrimaryValidations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_RenewPrimaryValidations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).RenewPrimaryValidations(ctx, req.(*RenewPrimaryValidationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetStakingRewards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStakingRewardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetStakingRewards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetStakingRewards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetStakingRewards(ctx, req.(*GetStakingRewardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_WaitForValidatorRewards_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitForValidatorRewardsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).WaitForValidatorRewards(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_WaitForValidatorRewards_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).WaitForValidatorRewards(ctx, req.(*WaitForValidatorRewardsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetUptimes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUptimesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetUptimes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetUptimes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetUptimes(ctx, req.(*GetUptimesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetVersions(ctx, req.(*GetVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetTopology_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTopologyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetTopology(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetTopology_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetTopology(ctx, req.(*GetTopologyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ExportNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ExportNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ExportNetwork_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ExportNetwork(ctx, req.(*ExportNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CheckConnectivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckConnectivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CheckConnectivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CheckConnectivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CheckConnectivity(ctx, req.(*CheckConnectivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetOperationHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOperationHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetOperationHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetOperationHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetOperationHistory(ctx, req.(*GetOperationHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateSubnets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSubnetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateSubnets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_CreateSubnets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateSubnets(ctx, req.(*CreateSubnetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_URIs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(URIsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).URIs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_URIs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).URIs(ctx, req.(*URIsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_WaitForHealthy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitForHealthyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).WaitForHealthy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_WaitForHealthy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).WaitForHealthy(ctx, req.(*WaitForHealthyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_WaitFor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitForRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).WaitFor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_WaitFor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).WaitFor(ctx, req.(*WaitForRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_Assert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).Assert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_Assert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).Assert(ctx, req.(*AssertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_Status_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).GetNodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_GetNodeInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).GetNodeInfo(ctx, req.(*GetNodeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ListNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListCustomChains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCustomChainsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListCustomChains(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ListCustomChains_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListCustomChains(ctx, req.(*ListCustomChainsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_StreamStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServiceServer).StreamStatus(m, &controlServiceStreamStatusServer{stream})
+}
+
+type ControlService_StreamStatusServer interface {
+	Send(*StreamStatusResponse) error
+	grpc.ServerStream
+}
+
+type controlServiceStreamStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlServiceStreamStatusServer) Send(m *StreamStatusResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ControlService_StreamLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServiceServer).StreamLogs(m, &controlServiceStreamLogsServer{stream})
+}
+
+type ControlService_StreamLogsServer interface {
+	Send(*StreamLogsResponse) error
+	grpc.ServerStream
 }
-func (UnimplementedControlServiceServer) RemoveSnapshot(context.Context, *RemoveSnapshotRequest) (*RemoveSnapshotResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RemoveSnapshot not implemented")
+
+type controlServiceStreamLogsServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedControlServiceServer) GetSnapshotNames(context.Context, *GetSnapshotNamesRequest) (*GetSnapshotNamesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshotNames not implemented")
+
+func (x *controlServiceStreamLogsServer) Send(m *StreamLogsResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedControlServiceServer) mustEmbedUnimplementedControlServiceServer() {}
 
-// UnsafeControlServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to ControlServiceServer will
-// result in compilation errors.
-type UnsafeControlServiceServer interface {
-	mustEmbedUnimplementedControlServiceServer()
+func _ControlService_SearchLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).SearchLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_SearchLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).SearchLogs(ctx, req.(*SearchLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterControlServiceServer(s grpc.ServiceRegistrar, srv ControlServiceServer) {
-	s.RegisterService(&ControlService_ServiceDesc, srv)
+func _ControlService_RemoveNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).RemoveNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_RemoveNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).RemoveNode(ctx, req.(*RemoveNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_RPCVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RPCVersionRequest)
+func _ControlService_DrainNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainNodeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).RPCVersion(ctx, in)
+		return srv.(ControlServiceServer).DrainNode(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_RPCVersion_FullMethodName,
+		FullMethod: ControlService_DrainNode_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).RPCVersion(ctx, req.(*RPCVersionRequest))
+		return srv.(ControlServiceServer).DrainNode(ctx, req.(*DrainNodeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_Start_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StartRequest)
+func _ControlService_AddNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddNodeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).Start(ctx, in)
+		return srv.(ControlServiceServer).AddNode(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_Start_FullMethodName,
+		FullMethod: ControlService_AddNode_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).Start(ctx, req.(*StartRequest))
+		return srv.(ControlServiceServer).AddNode(ctx, req.(*AddNodeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_CreateBlockchains_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateBlockchainsRequest)
+func _ControlService_ScaleNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScaleNetworkRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).CreateBlockchains(ctx, in)
+		return srv.(ControlServiceServer).ScaleNetwork(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_CreateBlockchains_FullMethodName,
+		FullMethod: ControlService_ScaleNetwork_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).CreateBlockchains(ctx, req.(*CreateBlockchainsRequest))
+		return srv.(ControlServiceServer).ScaleNetwork(ctx, req.(*ScaleNetworkRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_TransformElasticSubnets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TransformElasticSubnetsRequest)
+func _ControlService_ResetNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetNetworkRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).TransformElasticSubnets(ctx, in)
+		return srv.(ControlServiceServer).ResetNetwork(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_TransformElasticSubnets_FullMethodName,
+		FullMethod: ControlService_ResetNetwork_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).TransformElasticSubnets(ctx, req.(*TransformElasticSubnetsRequest))
+		return srv.(ControlServiceServer).ResetNetwork(ctx, req.(*ResetNetworkRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_AddPermissionlessValidator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddPermissionlessValidatorRequest)
+func _ControlService_StateSyncNode_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StateSyncNodeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServiceServer).StateSyncNode(m, &controlServiceStateSyncNodeServer{stream})
+}
+
+type ControlService_StateSyncNodeServer interface {
+	Send(*StateSyncNodeResponse) error
+	grpc.ServerStream
+}
+
+type controlServiceStateSyncNodeServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlServiceStateSyncNodeServer) Send(m *StateSyncNodeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ControlService_ExportCChainState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportCChainStateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).AddPermissionlessValidator(ctx, in)
+		return srv.(ControlServiceServer).ExportCChainState(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_AddPermissionlessValidator_FullMethodName,
+		FullMethod: ControlService_ExportCChainState_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).AddPermissionlessValidator(ctx, req.(*AddPermissionlessValidatorRequest))
+		return srv.(ControlServiceServer).ExportCChainState(ctx, req.(*ExportCChainStateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_RemoveSubnetValidator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RemoveSubnetValidatorRequest)
+func _ControlService_GetDBSizes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDBSizesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).RemoveSubnetValidator(ctx, in)
+		return srv.(ControlServiceServer).GetDBSizes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_RemoveSubnetValidator_FullMethodName,
+		FullMethod: ControlService_GetDBSizes_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).RemoveSubnetValidator(ctx, req.(*RemoveSubnetValidatorRequest))
+		return srv.(ControlServiceServer).GetDBSizes(ctx, req.(*GetDBSizesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_CreateSubnets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateSubnetsRequest)
+func _ControlService_PruneNodeDB_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneNodeDBRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).CreateSubnets(ctx, in)
+		return srv.(ControlServiceServer).PruneNodeDB(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_CreateSubnets_FullMethodName,
+		FullMethod: ControlService_PruneNodeDB_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).CreateSubnets(ctx, req.(*CreateSubnetsRequest))
+		return srv.(ControlServiceServer).PruneNodeDB(ctx, req.(*PruneNodeDBRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(HealthRequest)
+func _ControlService_RestartNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartNodeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).Health(ctx, in)
+		return srv.(ControlServiceServer).RestartNode(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_Health_FullMethodName,
+		FullMethod: ControlService_RestartNode_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).Health(ctx, req.(*HealthRequest))
+		return srv.(ControlServiceServer).RestartNode(ctx, req.(*RestartNodeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_URIs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(URIsRequest)
+func _ControlService_PauseNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseNodeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).URIs(ctx, in)
+		return srv.(ControlServiceServer).PauseNode(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_URIs_FullMethodName,
+		FullMethod: ControlService_PauseNode_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).URIs(ctx, req.(*URIsRequest))
+		return srv.(ControlServiceServer).PauseNode(ctx, req.(*PauseNodeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_WaitForHealthy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(WaitForHealthyRequest)
+func _ControlService_ResumeNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeNodeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).WaitForHealthy(ctx, in)
+		return srv.(ControlServiceServer).ResumeNode(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_WaitForHealthy_FullMethodName,
+		FullMethod: ControlService_ResumeNode_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).WaitForHealthy(ctx, req.(*WaitForHealthyRequest))
+		return srv.(ControlServiceServer).ResumeNode(ctx, req.(*ResumeNodeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StatusRequest)
+func _ControlService_PauseAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseAllRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).Status(ctx, in)
+		return srv.(ControlServiceServer).PauseAll(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_Status_FullMethodName,
+		FullMethod: ControlService_PauseAll_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).Status(ctx, req.(*StatusRequest))
+		return srv.(ControlServiceServer).PauseAll(ctx, req.(*PauseAllRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_StreamStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(StreamStatusRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _ControlService_ResumeAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(ControlServiceServer).StreamStatus(m, &controlServiceStreamStatusServer{stream})
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ResumeAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_ResumeAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ResumeAll(ctx, req.(*ResumeAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type ControlService_StreamStatusServer interface {
-	Send(*StreamStatusResponse) error
-	grpc.ServerStream
+func _ControlService_SimulateDowntime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SimulateDowntimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).SimulateDowntime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_SimulateDowntime_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).SimulateDowntime(ctx, req.(*SimulateDowntimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type controlServiceStreamStatusServer struct {
-	grpc.ServerStream
+func _ControlService_StartChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartChaosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).StartChaos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_StartChaos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).StartChaos(ctx, req.(*StartChaosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *controlServiceStreamStatusServer) Send(m *StreamStatusResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _ControlService_StopChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopChaosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).StopChaos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ControlService_StopChaos_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).StopChaos(ctx, req.(*StopChaosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_RemoveNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RemoveNodeRequest)
+func _ControlService_GetChaosStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChaosStatusRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).RemoveNode(ctx, in)
+		return srv.(ControlServiceServer).GetChaosStatus(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_RemoveNode_FullMethodName,
+		FullMethod: ControlService_GetChaosStatus_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).RemoveNode(ctx, req.(*RemoveNodeRequest))
+		return srv.(ControlServiceServer).GetChaosStatus(ctx, req.(*GetChaosStatusRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_AddNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddNodeRequest)
+func _ControlService_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).AddNode(ctx, in)
+		return srv.(ControlServiceServer).Stop(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_AddNode_FullMethodName,
+		FullMethod: ControlService_Stop_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).AddNode(ctx, req.(*AddNodeRequest))
+		return srv.(ControlServiceServer).Stop(ctx, req.(*StopRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_RestartNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RestartNodeRequest)
+func _ControlService_AttachPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttachPeerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).RestartNode(ctx, in)
+		return srv.(ControlServiceServer).AttachPeer(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_RestartNode_FullMethodName,
+		FullMethod: ControlService_AttachPeer_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).RestartNode(ctx, req.(*RestartNodeRequest))
+		return srv.(ControlServiceServer).AttachPeer(ctx, req.(*AttachPeerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_PauseNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PauseNodeRequest)
+func _ControlService_SendOutboundMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendOutboundMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).PauseNode(ctx, in)
+		return srv.(ControlServiceServer).SendOutboundMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_PauseNode_FullMethodName,
+		FullMethod: ControlService_SendOutboundMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).PauseNode(ctx, req.(*PauseNodeRequest))
+		return srv.(ControlServiceServer).SendOutboundMessage(ctx, req.(*SendOutboundMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_ResumeNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ResumeNodeRequest)
+func _ControlService_SendPeerMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendPeerMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).ResumeNode(ctx, in)
+		return srv.(ControlServiceServer).SendPeerMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_ResumeNode_FullMethodName,
+		FullMethod: ControlService_SendPeerMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).ResumeNode(ctx, req.(*ResumeNodeRequest))
+		return srv.(ControlServiceServer).SendPeerMessage(ctx, req.(*SendPeerMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopRequest)
+func _ControlService_SendAppRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendAppRequestRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).Stop(ctx, in)
+		return srv.(ControlServiceServer).SendAppRequest(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_Stop_FullMethodName,
+		FullMethod: ControlService_SendAppRequest_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).Stop(ctx, req.(*StopRequest))
+		return srv.(ControlServiceServer).SendAppRequest(ctx, req.(*SendAppRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_StreamPeerMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPeerMessagesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServiceServer).StreamPeerMessages(m, &controlServiceStreamPeerMessagesServer{stream})
+}
+
+type ControlService_StreamPeerMessagesServer interface {
+	Send(*StreamPeerMessagesResponse) error
+	grpc.ServerStream
+}
+
+type controlServiceStreamPeerMessagesServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlServiceStreamPeerMessagesServer) Send(m *StreamPeerMessagesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ControlService_StreamProgress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamProgressRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return interceptor(ctx, in, info, handler)
+	return srv.(ControlServiceServer).StreamProgress(m, &controlServiceStreamProgressServer{stream})
 }
 
-func _ControlService_AttachPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AttachPeerRequest)
+type ControlService_StreamProgressServer interface {
+	Send(*StreamProgressResponse) error
+	grpc.ServerStream
+}
+
+type controlServiceStreamProgressServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlServiceStreamProgressServer) Send(m *StreamProgressResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ControlService_RecordPeerMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordPeerMessagesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).AttachPeer(ctx, in)
+		return srv.(ControlServiceServer).RecordPeerMessages(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_AttachPeer_FullMethodName,
+		FullMethod: ControlService_RecordPeerMessages_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).AttachPeer(ctx, req.(*AttachPeerRequest))
+		return srv.(ControlServiceServer).RecordPeerMessages(ctx, req.(*RecordPeerMessagesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ControlService_SendOutboundMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendOutboundMessageRequest)
+func _ControlService_ReplayPeerMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayPeerMessagesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ControlServiceServer).SendOutboundMessage(ctx, in)
+		return srv.(ControlServiceServer).ReplayPeerMessages(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ControlService_SendOutboundMessage_FullMethodName,
+		FullMethod: ControlService_ReplayPeerMessages_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ControlServiceServer).SendOutboundMessage(ctx, req.(*SendOutboundMessageRequest))
+		return srv.(ControlServiceServer).ReplayPeerMessages(ctx, req.(*ReplayPeerMessagesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -990,6 +2579,10 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "TransformElasticSubnets",
 			Handler:    _ControlService_TransformElasticSubnets_Handler,
 		},
+		{
+			MethodName: "GetElasticSubnet",
+			Handler:    _ControlService_GetElasticSubnet_Handler,
+		},
 		{
 			MethodName: "AddPermissionlessValidator",
 			Handler:    _ControlService_AddPermissionlessValidator_Handler,
@@ -998,6 +2591,78 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveSubnetValidator",
 			Handler:    _ControlService_RemoveSubnetValidator_Handler,
 		},
+		{
+			MethodName: "ListBlockchains",
+			Handler:    _ControlService_ListBlockchains_Handler,
+		},
+		{
+			MethodName: "GetBlockchainStatus",
+			Handler:    _ControlService_GetBlockchainStatus_Handler,
+		},
+		{
+			MethodName: "AddBlockchainAlias",
+			Handler:    _ControlService_AddBlockchainAlias_Handler,
+		},
+		{
+			MethodName: "RemoveBlockchainAlias",
+			Handler:    _ControlService_RemoveBlockchainAlias_Handler,
+		},
+		{
+			MethodName: "AddVMAlias",
+			Handler:    _ControlService_AddVMAlias_Handler,
+		},
+		{
+			MethodName: "TransferSubnetOwnership",
+			Handler:    _ControlService_TransferSubnetOwnership_Handler,
+		},
+		{
+			MethodName: "CreateAsset",
+			Handler:    _ControlService_CreateAsset_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _ControlService_Transfer_Handler,
+		},
+		{
+			MethodName: "Stake",
+			Handler:    _ControlService_Stake_Handler,
+		},
+		{
+			MethodName: "RenewPrimaryValidations",
+			Handler:    _ControlService_RenewPrimaryValidations_Handler,
+		},
+		{
+			MethodName: "GetStakingRewards",
+			Handler:    _ControlService_GetStakingRewards_Handler,
+		},
+		{
+			MethodName: "WaitForValidatorRewards",
+			Handler:    _ControlService_WaitForValidatorRewards_Handler,
+		},
+		{
+			MethodName: "GetUptimes",
+			Handler:    _ControlService_GetUptimes_Handler,
+		},
+		{
+			MethodName: "GetVersions",
+			Handler:    _ControlService_GetVersions_Handler,
+		},
+		{
+			MethodName: "GetTopology",
+			Handler:    _ControlService_GetTopology_Handler,
+		},
+		{
+			MethodName: "ExportNetwork",
+			Handler:    _ControlService_ExportNetwork_Handler,
+		},
+		{
+			MethodName: "CheckConnectivity",
+			Handler:    _ControlService_CheckConnectivity_Handler,
+		},
+		{
+			MethodName: "GetOperationHistory",
+			Handler:    _ControlService_GetOperationHistory_Handler,
+		},
 		{
 			MethodName: "CreateSubnets",
 			Handler:    _ControlService_CreateSubnets_Handler,
@@ -1014,18 +2679,66 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "WaitForHealthy",
 			Handler:    _ControlService_WaitForHealthy_Handler,
 		},
+		{
+			MethodName: "WaitFor",
+			Handler:    _ControlService_WaitFor_Handler,
+		},
+		{
+			MethodName: "Assert",
+			Handler:    _ControlService_Assert_Handler,
+		},
 		{
 			MethodName: "Status",
 			Handler:    _ControlService_Status_Handler,
 		},
+		{
+			MethodName: "GetNodeInfo",
+			Handler:    _ControlService_GetNodeInfo_Handler,
+		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _ControlService_ListNodes_Handler,
+		},
+		{
+			MethodName: "ListCustomChains",
+			Handler:    _ControlService_ListCustomChains_Handler,
+		},
+		{
+			MethodName: "SearchLogs",
+			Handler:    _ControlService_SearchLogs_Handler,
+		},
 		{
 			MethodName: "RemoveNode",
 			Handler:    _ControlService_RemoveNode_Handler,
 		},
+		{
+			MethodName: "DrainNode",
+			Handler:    _ControlService_DrainNode_Handler,
+		},
 		{
 			MethodName: "AddNode",
 			Handler:    _ControlService_AddNode_Handler,
 		},
+		{
+			MethodName: "ScaleNetwork",
+			Handler:    _ControlService_ScaleNetwork_Handler,
+		},
+		{
+			MethodName: "ResetNetwork",
+			Handler:    _ControlService_ResetNetwork_Handler,
+		},
+		{
+			MethodName: "ExportCChainState",
+			Handler:    _ControlService_ExportCChainState_Handler,
+		},
+		{
+			MethodName: "GetDBSizes",
+			Handler:    _ControlService_GetDBSizes_Handler,
+		},
+		{
+			MethodName: "PruneNodeDB",
+			Handler:    _ControlService_PruneNodeDB_Handler,
+		},
 		{
 			MethodName: "RestartNode",
 			Handler:    _ControlService_RestartNode_Handler,
@@ -1038,6 +2751,30 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ResumeNode",
 			Handler:    _ControlService_ResumeNode_Handler,
 		},
+		{
+			MethodName: "PauseAll",
+			Handler:    _ControlService_PauseAll_Handler,
+		},
+		{
+			MethodName: "ResumeAll",
+			Handler:    _ControlService_ResumeAll_Handler,
+		},
+		{
+			MethodName: "SimulateDowntime",
+			Handler:    _ControlService_SimulateDowntime_Handler,
+		},
+		{
+			MethodName: "StartChaos",
+			Handler:    _ControlService_StartChaos_Handler,
+		},
+		{
+			MethodName: "StopChaos",
+			Handler:    _ControlService_StopChaos_Handler,
+		},
+		{
+			MethodName: "GetChaosStatus",
+			Handler:    _ControlService_GetChaosStatus_Handler,
+		},
 		{
 			MethodName: "Stop",
 			Handler:    _ControlService_Stop_Handler,
@@ -1050,6 +2787,22 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendOutboundMessage",
 			Handler:    _ControlService_SendOutboundMessage_Handler,
 		},
+		{
+			MethodName: "SendPeerMessage",
+			Handler:    _ControlService_SendPeerMessage_Handler,
+		},
+		{
+			MethodName: "SendAppRequest",
+			Handler:    _ControlService_SendAppRequest_Handler,
+		},
+		{
+			MethodName: "RecordPeerMessages",
+			Handler:    _ControlService_RecordPeerMessages_Handler,
+		},
+		{
+			MethodName: "ReplayPeerMessages",
+			Handler:    _ControlService_ReplayPeerMessages_Handler,
+		},
 		{
 			MethodName: "SaveSnapshot",
 			Handler:    _ControlService_SaveSnapshot_Handler,
@@ -1073,6 +2826,264 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ControlService_StreamStatus_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamLogs",
+			Handler:       _ControlService_StreamLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StateSyncNode",
+			Handler:       _ControlService_StateSyncNode_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamPeerMessages",
+			Handler:       _ControlService_StreamPeerMessages_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamProgress",
+			Handler:       _ControlService_StreamProgress_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpcpb/rpc.proto",
+}
+
+const (
+	KeyService_ListKeys_FullMethodName    = "/rpcpb.KeyService/ListKeys"
+	KeyService_CreateKey_FullMethodName   = "/rpcpb.KeyService/CreateKey"
+	KeyService_ExportKey_FullMethodName   = "/rpcpb.KeyService/ExportKey"
+	KeyService_SignMessage_FullMethodName = "/rpcpb.KeyService/SignMessage"
+	KeyService_SignTx_FullMethodName      = "/rpcpb.KeyService/SignTx"
+)
+
+// KeyServiceClient is the client API for KeyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type KeyServiceClient interface {
+	ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error)
+	CreateKey(ctx context.Context, in *CreateKeyRequest, opts ...grpc.CallOption) (*CreateKeyResponse, error)
+	ExportKey(ctx context.Context, in *ExportKeyRequest, opts ...grpc.CallOption) (*ExportKeyResponse, error)
+	SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error)
+	SignTx(ctx context.Context, in *SignTxRequest, opts ...grpc.CallOption) (*SignTxResponse, error)
+}
+
+type keyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKeyServiceClient(cc grpc.ClientConnInterface) KeyServiceClient {
+	return &keyServiceClient{cc}
+}
+
+func (c *keyServiceClient) ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error) {
+	out := new(ListKeysResponse)
+	err := c.cc.Invoke(ctx, KeyService_ListKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyServiceClient) CreateKey(ctx context.Context, in *CreateKeyRequest, opts ...grpc.CallOption) (*CreateKeyResponse, error) {
+	out := new(CreateKeyResponse)
+	err := c.cc.Invoke(ctx, KeyService_CreateKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyServiceClient) ExportKey(ctx context.Context, in *ExportKeyRequest, opts ...grpc.CallOption) (*ExportKeyResponse, error) {
+	out := new(ExportKeyResponse)
+	err := c.cc.Invoke(ctx, KeyService_ExportKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyServiceClient) SignMessage(ctx context.Context, in *SignMessageRequest, opts ...grpc.CallOption) (*SignMessageResponse, error) {
+	out := new(SignMessageResponse)
+	err := c.cc.Invoke(ctx, KeyService_SignMessage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyServiceClient) SignTx(ctx context.Context, in *SignTxRequest, opts ...grpc.CallOption) (*SignTxResponse, error) {
+	out := new(SignTxResponse)
+	err := c.cc.Invoke(ctx, KeyService_SignTx_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// KeyServiceServer is the server API for KeyService service.
+// All implementations must embed UnimplementedKeyServiceServer
+// for forward compatibility
+type KeyServiceServer interface {
+	ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error)
+	CreateKey(context.Context, *CreateKeyRequest) (*CreateKeyResponse, error)
+	ExportKey(context.Context, *ExportKeyRequest) (*ExportKeyResponse, error)
+	SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error)
+	SignTx(context.Context, *SignTxRequest) (*SignTxResponse, error)
+	mustEmbedUnimplementedKeyServiceServer()
+}
+
+// UnimplementedKeyServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedKeyServiceServer struct {
+}
+
+func (UnimplementedKeyServiceServer) ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
+func (UnimplementedKeyServiceServer) CreateKey(context.Context, *CreateKeyRequest) (*CreateKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateKey not implemented")
+}
+func (UnimplementedKeyServiceServer) ExportKey(context.Context, *ExportKeyRequest) (*ExportKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportKey not implemented")
+}
+func (UnimplementedKeyServiceServer) SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignMessage not implemented")
+}
+func (UnimplementedKeyServiceServer) SignTx(context.Context, *SignTxRequest) (*SignTxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignTx not implemented")
+}
+func (UnimplementedKeyServiceServer) mustEmbedUnimplementedKeyServiceServer() {}
+
+// UnsafeKeyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to KeyServiceServer will
+// result in compilation errors.
+type UnsafeKeyServiceServer interface {
+	mustEmbedUnimplementedKeyServiceServer()
+}
+
+func RegisterKeyServiceServer(s grpc.ServiceRegistrar, srv KeyServiceServer) {
+	s.RegisterService(&KeyService_ServiceDesc, srv)
+}
+
+func _KeyService_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyService_ListKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).ListKeys(ctx, req.(*ListKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyService_CreateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).CreateKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyService_CreateKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).CreateKey(ctx, req.(*CreateKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyService_ExportKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).ExportKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyService_ExportKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).ExportKey(ctx, req.(*ExportKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyService_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).SignMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyService_SignMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).SignMessage(ctx, req.(*SignMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyService_SignTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyServiceServer).SignTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyService_SignTx_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyServiceServer).SignTx(ctx, req.(*SignTxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// KeyService_ServiceDesc is the grpc.ServiceDesc for KeyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var KeyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rpcpb.KeyService",
+	HandlerType: (*KeyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListKeys",
+			Handler:    _KeyService_ListKeys_Handler,
+		},
+		{
+			MethodName: "CreateKey",
+			Handler:    _KeyService_CreateKey_Handler,
+		},
+		{
+			MethodName: "ExportKey",
+			Handler:    _KeyService_ExportKey_Handler,
+		},
+		{
+			MethodName: "SignMessage",
+			Handler:    _KeyService_SignMessage_Handler,
+		},
+		{
+			MethodName: "SignTx",
+			Handler:    _KeyService_SignTx_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpcpb/rpc.proto",
 }
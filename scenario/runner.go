@@ -0,0 +1,232 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/luxdefi/netrunner/client"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/utils/logging"
+	"go.uber.org/zap"
+)
+
+// StepResult is the outcome of a scenario step.
+type StepResult struct {
+	Name     string
+	Duration time.Duration
+	// nil if the step succeeded
+	Err error
+	// true if the step was not run because a previous one failed
+	Skipped bool
+}
+
+// Report is the outcome of a scenario run.
+type Report struct {
+	Name     string
+	Duration time.Duration
+	Steps    []StepResult
+}
+
+// Failed returns true if any step of the run failed.
+func (r *Report) Failed() bool {
+	for _, step := range r.Steps {
+		if step.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// runner keeps the state shared by the steps of a run.
+type runner struct {
+	cli client.Client
+	log logging.Logger
+	// IDs of the subnets created by the scenario, in creation order
+	subnetIDs []string
+}
+
+// Run executes the steps of [sc] in order using [cli], stopping at the
+// first one that fails. The returned report includes all steps.
+func Run(ctx context.Context, cli client.Client, log logging.Logger, sc *Scenario) *Report {
+	r := &runner{cli: cli, log: log}
+	report := &Report{Name: sc.Name}
+	start := time.Now()
+	failed := false
+	for i := range sc.Steps {
+		step := &sc.Steps[i]
+		result := StepResult{Name: step.displayName()}
+		if failed {
+			result.Skipped = true
+			report.Steps = append(report.Steps, result)
+			continue
+		}
+
+		log.Info("running scenario step", zap.Int("step", i), zap.String("name", result.Name))
+		stepStart := time.Now()
+		stepCtx, cancel := context.WithTimeout(ctx, sc.stepTimeout(step))
+		result.Err = r.runStep(stepCtx, step)
+		cancel()
+		result.Duration = time.Since(stepStart)
+		if result.Err != nil {
+			log.Warn("scenario step failed", zap.Int("step", i), zap.String("name", result.Name), zap.Error(result.Err))
+			failed = true
+		}
+		report.Steps = append(report.Steps, result)
+	}
+	report.Duration = time.Since(start)
+	return report
+}
+
+func (r *runner) runStep(ctx context.Context, step *Step) error {
+	switch {
+	case step.Start != nil:
+		return r.start(ctx, step.Start)
+	case step.CreateSubnets != nil:
+		return r.createSubnets(ctx, step.CreateSubnets)
+	case step.CreateBlockchains != nil:
+		return r.createBlockchains(ctx, step.CreateBlockchains)
+	case step.AddNode != nil:
+		_, err := r.cli.AddNode(ctx, step.AddNode.Name, step.AddNode.ExecPath)
+		return err
+	case step.RemoveNode != nil:
+		_, err := r.cli.RemoveNode(ctx, step.RemoveNode.Name)
+		return err
+	case step.PauseNode != nil:
+		_, err := r.cli.PauseNode(ctx, step.PauseNode.Name)
+		return err
+	case step.ResumeNode != nil:
+		_, err := r.cli.ResumeNode(ctx, step.ResumeNode.Name)
+		return err
+	case step.RestartNode != nil:
+		opts := []client.OpOption{}
+		if step.RestartNode.ExecPath != "" {
+			opts = append(opts, client.WithExecPath(step.RestartNode.ExecPath))
+		}
+		if step.RestartNode.WipeDB {
+			opts = append(opts, client.WithWipeDB(true))
+		}
+		_, err := r.cli.RestartNode(ctx, step.RestartNode.Name, opts...)
+		return err
+	case step.WaitHealthy != nil:
+		_, err := r.cli.WaitForHealthy(ctx)
+		return err
+	case step.Sleep != nil:
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(step.Sleep.Duration):
+			return nil
+		}
+	case step.AssertValidators != nil:
+		return r.assertValidators(ctx, step.AssertValidators)
+	case step.AssertNodes != nil:
+		return r.assertNodes(ctx, step.AssertNodes)
+	case step.Stop != nil:
+		_, err := r.cli.Stop(ctx)
+		return err
+	default:
+		return errNoStepAction
+	}
+}
+
+func (r *runner) start(ctx context.Context, action *StartAction) error {
+	opts := []client.OpOption{
+		client.WithPluginDir(action.PluginDir),
+		client.WithRootDataDir(action.RootDataDir),
+		client.WithTrackSubnets(action.TrackSubnets),
+		client.WithGlobalNodeConfig(action.GlobalNodeConfig),
+	}
+	if action.NumNodes != 0 {
+		opts = append(opts, client.WithNumNodes(action.NumNodes))
+	}
+	_, err := r.cli.Start(ctx, action.ExecPath, opts...)
+	return err
+}
+
+func (r *runner) createSubnets(ctx context.Context, action *CreateSubnetsAction) error {
+	specs := make([]*rpcpb.SubnetSpec, len(action.Subnets))
+	for i := range action.Subnets {
+		specs[i] = action.Subnets[i].toRPC()
+	}
+	resp, err := r.cli.CreateSubnets(ctx, specs)
+	if err != nil {
+		return err
+	}
+	r.subnetIDs = append(r.subnetIDs, resp.SubnetIds...)
+	return nil
+}
+
+func (r *runner) createBlockchains(ctx context.Context, action *CreateBlockchainsAction) error {
+	specs := make([]*rpcpb.BlockchainSpec, len(action.Blockchains))
+	for i, chain := range action.Blockchains {
+		spec := &rpcpb.BlockchainSpec{
+			VmName:          chain.VMName,
+			Genesis:         chain.Genesis,
+			ChainConfig:     chain.ChainConfig,
+			BlockchainAlias: chain.BlockchainAlias,
+		}
+		subnetID, err := r.getSubnetID(chain.SubnetID, chain.SubnetRef)
+		if err != nil {
+			return err
+		}
+		switch {
+		case subnetID != "":
+			spec.SubnetId = &subnetID
+		case chain.Subnet != nil:
+			spec.SubnetSpec = chain.Subnet.toRPC()
+		}
+		specs[i] = spec
+	}
+	_, err := r.cli.CreateBlockchains(ctx, specs)
+	return err
+}
+
+func (r *runner) assertValidators(ctx context.Context, action *AssertValidatorsAction) error {
+	subnetID, err := r.getSubnetID(action.SubnetID, action.SubnetRef)
+	if err != nil {
+		return err
+	}
+	resp, err := r.cli.GetUptimes(ctx, subnetID)
+	if err != nil {
+		return err
+	}
+	if len(resp.Uptimes) != action.Count {
+		return fmt.Errorf("%w: expected %d validators, got %d", ErrAssertion, action.Count, len(resp.Uptimes))
+	}
+	return nil
+}
+
+func (r *runner) assertNodes(ctx context.Context, action *AssertNodesAction) error {
+	resp, err := r.cli.Status(ctx)
+	if err != nil {
+		return err
+	}
+	numNodes := len(resp.GetClusterInfo().GetNodeNames())
+	if numNodes != action.Count {
+		return fmt.Errorf("%w: expected %d nodes, got %d", ErrAssertion, action.Count, numNodes)
+	}
+	return nil
+}
+
+// Returns [subnetID], or the ID of the subnet created by the scenario
+// at [subnetRef] if given.
+func (r *runner) getSubnetID(subnetID string, subnetRef *int) (string, error) {
+	if subnetRef == nil {
+		return subnetID, nil
+	}
+	if *subnetRef < 0 || *subnetRef >= len(r.subnetIDs) {
+		return "", fmt.Errorf("%w: %d", ErrUnknownSubnet, *subnetRef)
+	}
+	return r.subnetIDs[*subnetRef], nil
+}
+
+func (s *SubnetSpec) toRPC() *rpcpb.SubnetSpec {
+	return &rpcpb.SubnetSpec{
+		Participants: s.Participants,
+		SubnetConfig: s.SubnetConfig,
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package scenario runs YAML described sequences of operations against
+// a network runner server.
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Timeout of the steps that don't set one, if the scenario doesn't either
+const DefaultStepTimeout = 5 * time.Minute
+
+var (
+	ErrNoSteps        = errors.New("scenario has no steps")
+	ErrInvalidStep    = errors.New("invalid scenario step")
+	ErrAssertion      = errors.New("assertion failed")
+	ErrUnknownSubnet  = errors.New("unknown subnet reference")
+	errNoStepAction   = errors.New("step has no action")
+	errManyStepAction = errors.New("step has more than one action")
+)
+
+// Scenario is a sequence of steps run in order, stopping at the first
+// one that fails.
+//
+// Example:
+//
+//	name: subnet-deploy
+//	timeout: 5m
+//	steps:
+//	  - start:
+//	      exec-path: /path/to/luxd
+//	      num-nodes: 5
+//	  - wait-healthy: {}
+//	  - create-subnets:
+//	      subnets:
+//	        - participants: [node1, node2, node3]
+//	  - create-blockchains:
+//	      blockchains:
+//	        - vm-name: subnetevm
+//	          genesis: /path/to/genesis.json
+//	          subnet-ref: 0
+//	  - name: kill node3
+//	    remove-node:
+//	      name: node3
+//	  - assert-validators:
+//	      subnet-ref: 0
+//	      count: 3
+type Scenario struct {
+	Name string `yaml:"name"`
+	// timeout of the steps that don't set one
+	Timeout time.Duration `yaml:"timeout"`
+	Steps   []Step        `yaml:"steps"`
+}
+
+// Step is a single operation of a scenario. Exactly one of its
+// actions must be set.
+type Step struct {
+	// name shown on the report, defaults to the action name
+	Name    string        `yaml:"name"`
+	Timeout time.Duration `yaml:"timeout"`
+
+	Start             *StartAction             `yaml:"start"`
+	CreateSubnets     *CreateSubnetsAction     `yaml:"create-subnets"`
+	CreateBlockchains *CreateBlockchainsAction `yaml:"create-blockchains"`
+	AddNode           *NodeAction              `yaml:"add-node"`
+	RemoveNode        *NodeAction              `yaml:"remove-node"`
+	PauseNode         *NodeAction              `yaml:"pause-node"`
+	ResumeNode        *NodeAction              `yaml:"resume-node"`
+	RestartNode       *NodeAction              `yaml:"restart-node"`
+	WaitHealthy       *struct{}                `yaml:"wait-healthy"`
+	Sleep             *SleepAction             `yaml:"sleep"`
+	AssertValidators  *AssertValidatorsAction  `yaml:"assert-validators"`
+	AssertNodes       *AssertNodesAction       `yaml:"assert-nodes"`
+	Stop              *struct{}                `yaml:"stop"`
+}
+
+type StartAction struct {
+	ExecPath         string `yaml:"exec-path"`
+	NumNodes         uint32 `yaml:"num-nodes"`
+	PluginDir        string `yaml:"plugin-dir"`
+	RootDataDir      string `yaml:"root-data-dir"`
+	TrackSubnets     string `yaml:"track-subnets"`
+	GlobalNodeConfig string `yaml:"global-node-config"`
+}
+
+type SubnetSpec struct {
+	// if empty, all nodes are participants
+	Participants []string `yaml:"participants"`
+	SubnetConfig string   `yaml:"subnet-config"`
+}
+
+type CreateSubnetsAction struct {
+	Subnets []SubnetSpec `yaml:"subnets"`
+}
+
+type BlockchainSpec struct {
+	VMName string `yaml:"vm-name"`
+	// either file path or file contents
+	Genesis string `yaml:"genesis"`
+	// existing subnet of the blockchain
+	SubnetID string `yaml:"subnet-id"`
+	// index of a subnet created by a previous step of the scenario,
+	// in creation order
+	SubnetRef *int `yaml:"subnet-ref"`
+	// new subnet for the blockchain, if no subnet is referenced
+	Subnet          *SubnetSpec `yaml:"subnet"`
+	ChainConfig     string      `yaml:"chain-config"`
+	BlockchainAlias string      `yaml:"alias"`
+}
+
+type CreateBlockchainsAction struct {
+	Blockchains []BlockchainSpec `yaml:"blockchains"`
+}
+
+type NodeAction struct {
+	Name string `yaml:"name"`
+	// used by add-node and restart-node, defaults to the network binary
+	ExecPath string `yaml:"exec-path"`
+	// used by restart-node, removes the node db before restarting
+	WipeDB bool `yaml:"wipe-db"`
+}
+
+type SleepAction struct {
+	Duration time.Duration `yaml:"duration"`
+}
+
+type AssertValidatorsAction struct {
+	// primary network if neither the subnet ID nor a subnet reference is given
+	SubnetID  string `yaml:"subnet-id"`
+	SubnetRef *int   `yaml:"subnet-ref"`
+	Count     int    `yaml:"count"`
+}
+
+type AssertNodesAction struct {
+	Count int `yaml:"count"`
+}
+
+// Load reads and validates the scenario at [path].
+func Load(path string) (*Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+// Parse decodes and validates the YAML scenario [b].
+func Parse(b []byte) (*Scenario, error) {
+	sc := &Scenario{}
+	if err := yaml.Unmarshal(b, sc); err != nil {
+		return nil, err
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func (sc *Scenario) Validate() error {
+	if len(sc.Steps) == 0 {
+		return ErrNoSteps
+	}
+	for i := range sc.Steps {
+		if _, err := sc.Steps[i].action(); err != nil {
+			return fmt.Errorf("%w %d: %s", ErrInvalidStep, i, err)
+		}
+	}
+	return nil
+}
+
+// Returns the timeout of [step].
+func (sc *Scenario) stepTimeout(step *Step) time.Duration {
+	switch {
+	case step.Timeout != 0:
+		return step.Timeout
+	case sc.Timeout != 0:
+		return sc.Timeout
+	default:
+		return DefaultStepTimeout
+	}
+}
+
+// Returns the name of the action set on [s].
+func (s *Step) action() (string, error) {
+	actions := map[string]bool{
+		"start":              s.Start != nil,
+		"create-subnets":     s.CreateSubnets != nil,
+		"create-blockchains": s.CreateBlockchains != nil,
+		"add-node":           s.AddNode != nil,
+		"remove-node":        s.RemoveNode != nil,
+		"pause-node":         s.PauseNode != nil,
+		"resume-node":        s.ResumeNode != nil,
+		"restart-node":       s.RestartNode != nil,
+		"wait-healthy":       s.WaitHealthy != nil,
+		"sleep":              s.Sleep != nil,
+		"assert-validators":  s.AssertValidators != nil,
+		"assert-nodes":       s.AssertNodes != nil,
+		"stop":               s.Stop != nil,
+	}
+	action := ""
+	for name, set := range actions {
+		if !set {
+			continue
+		}
+		if action != "" {
+			return "", errManyStepAction
+		}
+		action = name
+	}
+	if action == "" {
+		return "", errNoStepAction
+	}
+	return action, nil
+}
+
+// Returns the name of [s] shown on reports.
+func (s *Step) displayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	action, _ := s.action()
+	return action
+}
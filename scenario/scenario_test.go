@@ -0,0 +1,53 @@
+package scenario
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	require := require.New(t)
+
+	sc, err := Parse([]byte(`
+name: test
+timeout: 2m
+steps:
+  - start:
+      exec-path: /tmp/luxd
+      num-nodes: 3
+  - wait-healthy: {}
+  - name: kill node2
+    timeout: 10s
+    remove-node:
+      name: node2
+  - assert-nodes:
+      count: 2
+`))
+	require.NoError(err)
+	require.Equal("test", sc.Name)
+	require.Len(sc.Steps, 4)
+	require.Equal("/tmp/luxd", sc.Steps[0].Start.ExecPath)
+	require.Equal(uint32(3), sc.Steps[0].Start.NumNodes)
+	require.Equal("start", sc.Steps[0].displayName())
+	require.Equal("wait-healthy", sc.Steps[1].displayName())
+	require.Equal("kill node2", sc.Steps[2].displayName())
+	require.Equal(2*time.Minute, sc.stepTimeout(&sc.Steps[1]))
+	require.Equal(10*time.Second, sc.stepTimeout(&sc.Steps[2]))
+	require.Equal(2, sc.Steps[3].AssertNodes.Count)
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no steps":    `name: test`,
+		"no action":   "steps:\n  - name: empty\n",
+		"two actions": "steps:\n  - wait-healthy: {}\n    stop: {}\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(input))
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/message"
+	"github.com/luxdefi/node/proto/pb/p2p"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrNoAppResponse = errors.New("no app response received before the deadline")
+
+// last request ID generated for the app requests sent with SendAppRequest
+var lastAppRequestID atomic.Uint32
+
+// Sends an app request to a chain of the node from an attached peer, and
+// waits for the node to respond.
+func (s *server) SendAppRequest(ctx context.Context, req *rpcpb.SendAppRequestRequest) (*rpcpb.SendAppRequestResponse, error) {
+	s.log.Debug("SendAppRequest",
+		zap.String("node-name", req.NodeName),
+		zap.String("peer-ID", req.PeerId),
+		zap.String("chain-ID", req.ChainId),
+	)
+
+	chainID, err := ids.FromString(req.ChainId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid chain ID %q: %s", ErrInvalidPeerMessage, req.ChainId, err)
+	}
+	requestID := req.RequestId
+	if requestID == 0 {
+		requestID = lastAppRequestID.Add(1)
+	}
+	deadline := time.Duration(req.Deadline)
+	if deadline == 0 {
+		deadline = defaultPeerMessageDeadline
+	}
+
+	msgs, unsubscribe, err := s.sendAppRequest(ctx, req, requestID, deadline)
+	if err != nil {
+		return nil, err
+	}
+	defer unsubscribe()
+
+	timer := time.NewTimer(deadline)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, ErrNoAppResponse
+		case msg, ok := <-msgs:
+			if !ok {
+				return nil, fmt.Errorf("%w: the node was stopped", ErrNoAppResponse)
+			}
+			appBytes, ok := getAppResponse(msg, chainID, requestID)
+			if !ok {
+				continue
+			}
+			return &rpcpb.SendAppRequestResponse{
+				RequestId: requestID,
+				AppBytes:  appBytes,
+			}, nil
+		}
+	}
+}
+
+// Subscribes to the messages received by the attached peer, and sends
+// the app request of [req] from it, so the response can't be missed.
+func (s *server) sendAppRequest(
+	ctx context.Context,
+	req *rpcpb.SendAppRequestRequest,
+	requestID uint32,
+	deadline time.Duration,
+) (<-chan node.PeerMessage, func(), error) {
+	defer s.opMu.LockNode(req.NodeName)()
+
+	if s.network == nil {
+		return nil, nil, ErrNotBootstrapped
+	}
+
+	nd, err := s.network.nw.GetNode(req.NodeName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msg, err := buildPeerMessage(&rpcpb.SendPeerMessageRequest{
+		ChainId:   req.ChainId,
+		RequestId: requestID,
+		Deadline:  int64(deadline),
+		Message: &rpcpb.SendPeerMessageRequest_AppRequest{
+			AppRequest: &rpcpb.AppRequestMessage{AppBytes: req.AppBytes},
+		},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	msgs, unsubscribe, err := nd.SubscribePeerMessages(req.PeerId)
+	if err != nil {
+		return nil, nil, err
+	}
+	sent, err := nd.SendOutboundMessage(ctx, req.PeerId, msg.Bytes(), uint32(msg.Op()))
+	if err == nil && !sent {
+		err = errors.New("app request not sent")
+	}
+	if err != nil {
+		unsubscribe()
+		return nil, nil, err
+	}
+	return msgs, unsubscribe, nil
+}
+
+// Returns the app bytes of [msg] if it is the response to the app
+// request [requestID] sent to [chainID].
+func getAppResponse(msg node.PeerMessage, chainID ids.ID, requestID uint32) ([]byte, bool) {
+	if message.Op(msg.Op) != message.AppResponseOp || msg.ChainID != chainID {
+		return nil, false
+	}
+	resp := &p2p.AppResponse{}
+	if err := proto.Unmarshal(msg.Bytes, resp); err != nil || resp.RequestId != requestID {
+		return nil, false
+	}
+	return resp.AppBytes, true
+}
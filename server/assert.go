@@ -0,0 +1,150 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/ids"
+	luxd_constants "github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/vms/platformvm/status"
+	"go.uber.org/zap"
+)
+
+// Evaluates the assertions of [req] on the network. Failed assertions are
+// reported in the response, with the expected and actual values, instead
+// of as an error, so test harnesses in any language can report them.
+func (s *server) Assert(
+	ctx context.Context,
+	req *rpcpb.AssertRequest,
+) (*rpcpb.AssertResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+	if len(req.GetAssertions()) == 0 {
+		return nil, ErrNoAssertions
+	}
+	for i, assertion := range req.GetAssertions() {
+		if assertion.GetAssertion() == nil {
+			return nil, fmt.Errorf("%w: assertion %d is empty", ErrInvalidAssertion, i)
+		}
+	}
+
+	s.log.Debug("Assert", zap.Int("assertions", len(req.GetAssertions())))
+
+	ctx, cancel := context.WithTimeout(ctx, waitForHealthyTimeout)
+	defer cancel()
+	return nw.assert(ctx, req.GetAssertions())
+}
+
+// Evaluates [assertions] on a view of the network, so the nodes are
+// queried without holding [lc.lock].
+// Assumes [lc.lock] isn't held.
+func (lc *localNetwork) assert(ctx context.Context, assertions []*rpcpb.Assertion) (*rpcpb.AssertResponse, error) {
+	view, err := lc.GetView()
+	if err != nil {
+		return nil, err
+	}
+	resp := &rpcpb.AssertResponse{Passed: true}
+	for _, assertion := range assertions {
+		var result *rpcpb.AssertionResult
+		switch {
+		case assertion.GetValidatorCount() != nil:
+			result = view.assertValidatorCount(ctx, assertion.GetValidatorCount())
+		case assertion.GetChainHealthy() != nil:
+			result = view.assertChainHealthy(ctx, assertion.GetChainHealthy())
+		case assertion.GetNodeVersion() != nil:
+			result = view.assertNodeVersion(ctx, assertion.GetNodeVersion())
+		}
+		resp.Results = append(resp.Results, result)
+		resp.Passed = resp.Passed && result.Passed
+	}
+	return resp, nil
+}
+
+func (v *networkView) assertValidatorCount(ctx context.Context, assertion *rpcpb.ValidatorCountAssertion) *rpcpb.AssertionResult {
+	result := &rpcpb.AssertionResult{
+		Expected: strconv.Itoa(int(assertion.Count)),
+	}
+	subnetID := luxd_constants.PrimaryNetworkID
+	if assertion.SubnetId != "" {
+		var err error
+		subnetID, err = ids.FromString(assertion.SubnetId)
+		if err != nil {
+			result.Assertion = fmt.Sprintf("subnet %s has %d validators", assertion.SubnetId, assertion.Count)
+			result.Error = err.Error()
+			return result
+		}
+	}
+	result.Assertion = fmt.Sprintf("subnet %s has %d validators", subnetID, assertion.Count)
+	numValidators, err := v.getNumValidators(ctx, subnetID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Actual = strconv.Itoa(int(numValidators))
+	result.Passed = numValidators == assertion.Count
+	return result
+}
+
+func (v *networkView) assertChainHealthy(ctx context.Context, assertion *rpcpb.ChainHealthyAssertion) *rpcpb.AssertionResult {
+	result := &rpcpb.AssertionResult{
+		Assertion: fmt.Sprintf("blockchain %s is healthy", assertion.Chain),
+		Expected:  fmt.Sprintf("%s and bootstrapped on all running participants", status.Validating),
+	}
+	notBootstrapped, err := v.getNotBootstrappedNodes(ctx, assertion.Chain)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	chainID, err := v.getChainID(ctx, assertion.Chain)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	refNode, err := v.getRefNode()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	chainStatus, err := refNode.GetAPIClient().PChainAPI().GetBlockchainStatus(ctx, chainID.String())
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if len(notBootstrapped) > 0 {
+		result.Actual = fmt.Sprintf("%s and not bootstrapped on %s", chainStatus, strings.Join(notBootstrapped, ", "))
+	} else {
+		result.Actual = fmt.Sprintf("%s and bootstrapped on all running participants", chainStatus)
+	}
+	result.Passed = chainStatus == status.Validating && len(notBootstrapped) == 0
+	return result
+}
+
+func (v *networkView) assertNodeVersion(ctx context.Context, assertion *rpcpb.NodeVersionAssertion) *rpcpb.AssertionResult {
+	result := &rpcpb.AssertionResult{
+		Assertion: fmt.Sprintf("%s runs version %s", assertion.NodeName, assertion.Version),
+		Expected:  assertion.Version,
+	}
+	node, ok := v.nodes[assertion.NodeName]
+	if !ok {
+		result.Error = fmt.Sprintf("node %q not found or not running", assertion.NodeName)
+		return result
+	}
+	resp, err := node.GetAPIClient().InfoAPI().GetNodeVersion(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Actual = resp.Version
+	// the node reports its version prefixed by the app name, e.g. luxd/1.10.3
+	_, versionNumber, _ := strings.Cut(resp.Version, "/")
+	result.Passed = resp.Version == assertion.Version || versionNumber == strings.TrimPrefix(assertion.Version, "v")
+	return result
+}
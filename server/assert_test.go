@@ -0,0 +1,115 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	apimocks "github.com/luxdefi/netrunner/api/mocks"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertNodeVersion(t *testing.T) {
+	s, nw := newTestServer(t, 2)
+	for _, nodeName := range []string{"node1", "node2"} {
+		client := &apimocks.Client{}
+		client.On("InfoAPI").Return(&testInfoClient{version: "luxd/1.10.3"})
+		require.NoError(t, nw.SetAPIClient(nodeName, client))
+	}
+	_, err := s.PauseNode(context.Background(), &rpcpb.PauseNodeRequest{Name: "node2"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		assertion *rpcpb.NodeVersionAssertion
+		passed    bool
+		expectErr bool
+	}{
+		{
+			name:      "same version",
+			assertion: &rpcpb.NodeVersionAssertion{NodeName: "node1", Version: "v1.10.3"},
+			passed:    true,
+		},
+		{
+			name:      "other version",
+			assertion: &rpcpb.NodeVersionAssertion{NodeName: "node1", Version: "v1.10.4"},
+		},
+		{
+			name:      "paused node",
+			assertion: &rpcpb.NodeVersionAssertion{NodeName: "node2", Version: "v1.10.3"},
+			expectErr: true,
+		},
+		{
+			name:      "unknown node",
+			assertion: &rpcpb.NodeVersionAssertion{NodeName: "node3", Version: "v1.10.3"},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			resp, err := s.Assert(context.Background(), &rpcpb.AssertRequest{
+				Assertions: []*rpcpb.Assertion{
+					{Assertion: &rpcpb.Assertion_NodeVersion{NodeVersion: tt.assertion}},
+				},
+			})
+			require.NoError(err)
+			require.Len(resp.Results, 1)
+			require.Equal(tt.passed, resp.Passed)
+			require.Equal(tt.passed, resp.Results[0].Passed)
+			require.Equal(tt.expectErr, resp.Results[0].Error != "")
+		})
+	}
+}
+
+func TestAssertReleasesLock(t *testing.T) {
+	require := require.New(t)
+
+	s, nw := newTestServer(t, 1)
+	// the network lock is taken by other operations while the node is queried
+	locked := false
+	client := &apimocks.Client{}
+	client.On("InfoAPI").Return(&testInfoClient{
+		onRequest: func() {
+			locked = !s.network.lock.TryLock()
+			if !locked {
+				s.network.lock.Unlock()
+			}
+		},
+	})
+	require.NoError(nw.SetAPIClient("node1", client))
+
+	_, err := s.Assert(context.Background(), &rpcpb.AssertRequest{
+		Assertions: []*rpcpb.Assertion{
+			{Assertion: &rpcpb.Assertion_NodeVersion{NodeVersion: &rpcpb.NodeVersionAssertion{NodeName: "node1"}}},
+		},
+	})
+	require.NoError(err)
+	require.False(locked)
+}
+
+func TestAssertCanceled(t *testing.T) {
+	require := require.New(t)
+
+	s, nw := newTestServer(t, 1)
+	client := &apimocks.Client{}
+	client.On("InfoAPI").Return(&testInfoClient{version: "luxd/1.10.3"})
+	require.NoError(nw.SetAPIClient("node1", client))
+
+	// the nodes are queried with the context of the call, so a canceled
+	// call doesn't wait on them
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp, err := s.Assert(ctx, &rpcpb.AssertRequest{
+		Assertions: []*rpcpb.Assertion{
+			{Assertion: &rpcpb.Assertion_NodeVersion{NodeVersion: &rpcpb.NodeVersionAssertion{NodeName: "node1", Version: "v1.10.3"}}},
+		},
+	})
+	require.NoError(err)
+	require.False(resp.Passed)
+	require.Contains(resp.Results[0].Error, context.Canceled.Error())
+}
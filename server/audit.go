@@ -0,0 +1,247 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	auditedMethodsPrefix = "/rpcpb.ControlService/"
+	// the KeyService calls are recorded as KeyService/<method>, as they
+	// export and sign with the keys of the network
+	auditedKeyMethodsPrefix = "/rpcpb.KeyService/"
+	// max number of operations returned by GetOperationHistory, if not given
+	defaultOperationHistoryLimit = 100
+)
+
+var ErrAuditLogDisabled = errors.New("audit log is disabled")
+
+// Methods not recorded in the audit log
+var auditUnrecordedMethods = newMethodSet(
+	"/rpcpb.ControlService/GetOperationHistory",
+)
+
+// Entry of the audit log, written as a JSON line for each ControlService
+// and KeyService call.
+type auditEntry struct {
+	Time     time.Time     `json:"time"`
+	Client   string        `json:"client"`
+	Method   string        `json:"method"`
+	Request  string        `json:"request,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Append-only log of the ControlService and KeyService calls.
+type auditLog struct {
+	lock sync.Mutex
+	path string
+	file *os.File
+}
+
+func newAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{path: path, file: file}, nil
+}
+
+func (a *auditLog) write(entry *auditEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	_, err = a.file.Write(append(b, '\n'))
+	return err
+}
+
+// Returns the last [limit] entries recorded since [since], whose
+// method contains [method].
+func (a *auditLog) read(method string, since time.Time, limit int) ([]*auditEntry, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	file, err := os.Open(a.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := []*auditEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		entry := &auditEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return nil, err
+		}
+		if entry.Time.Before(since) || !strings.Contains(entry.Method, method) {
+			continue
+		}
+		entries = append(entries, entry)
+		if len(entries) > limit {
+			entries = entries[1:]
+		}
+	}
+	return entries, scanner.Err()
+}
+
+func (a *auditLog) close() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.file.Close()
+}
+
+// Returns the method recorded in the audit log for [fullMethod], and
+// false if its calls aren't recorded.
+func getAuditedMethod(fullMethod string) (string, bool) {
+	switch {
+	case auditUnrecordedMethods.contains(fullMethod):
+		return "", false
+	case strings.HasPrefix(fullMethod, auditedMethodsPrefix):
+		return strings.TrimPrefix(fullMethod, auditedMethodsPrefix), true
+	case strings.HasPrefix(fullMethod, auditedKeyMethodsPrefix):
+		return strings.TrimPrefix(fullMethod, "/rpcpb."), true
+	}
+	return "", false
+}
+
+// Records the call to [fullMethod] in the audit log, if enabled.
+// Only the request is recorded, so the exported keys and signatures
+// returned aren't.
+func (s *server) audit(ctx context.Context, fullMethod string, req interface{}, start time.Time, err error) {
+	if s.auditLog == nil {
+		return
+	}
+	method, ok := getAuditedMethod(fullMethod)
+	if !ok {
+		return
+	}
+	entry := &auditEntry{
+		Time:     start,
+		Client:   getClientAddress(ctx),
+		Method:   method,
+		Duration: time.Since(start),
+	}
+	if msg, ok := req.(proto.Message); ok {
+		if b, err := protojson.Marshal(redactRequest(msg)); err == nil {
+			entry.Request = string(b)
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if err := s.auditLog.write(entry); err != nil {
+		s.log.Warn("failed to write audit log", zap.Error(err))
+	}
+}
+
+// Returns [msg] without the secrets it may hold, so they aren't recorded.
+func redactRequest(msg proto.Message) proto.Message {
+	switch req := msg.(type) {
+	case *rpcpb.SaveSnapshotRequest:
+		if req.GetPassphrase() != "" {
+			redacted := proto.Clone(req).(*rpcpb.SaveSnapshotRequest)
+			redacted.Passphrase = ""
+			return redacted
+		}
+	case *rpcpb.LoadSnapshotRequest:
+		if req.GetPassphrase() != "" {
+			redacted := proto.Clone(req).(*rpcpb.LoadSnapshotRequest)
+			redacted.Passphrase = ""
+			return redacted
+		}
+	case *rpcpb.SignMessageRequest:
+		// only who signed with which key is recorded
+		if len(req.GetMessage()) > 0 {
+			redacted := proto.Clone(req).(*rpcpb.SignMessageRequest)
+			redacted.Message = nil
+			return redacted
+		}
+	case *rpcpb.SignTxRequest:
+		if len(req.GetUnsignedTx()) > 0 {
+			redacted := proto.Clone(req).(*rpcpb.SignTxRequest)
+			redacted.UnsignedTx = nil
+			return redacted
+		}
+	}
+	return msg
+}
+
+func (s *server) unaryAuditInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	s.audit(ctx, info.FullMethod, req, start, err)
+	return resp, err
+}
+
+func (s *server) streamAuditInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	s.audit(ss.Context(), info.FullMethod, nil, start, err)
+	return err
+}
+
+// Returns the ControlService calls recorded in the audit log.
+func (s *server) GetOperationHistory(
+	_ context.Context,
+	req *rpcpb.GetOperationHistoryRequest,
+) (*rpcpb.GetOperationHistoryResponse, error) {
+	s.log.Debug("GetOperationHistory", zap.String("method", req.Method))
+
+	if s.auditLog == nil {
+		return nil, ErrAuditLogDisabled
+	}
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultOperationHistoryLimit
+	}
+	var since time.Time
+	if req.Since != 0 {
+		since = time.Unix(0, req.Since)
+	}
+	entries, err := s.auditLog.read(req.Method, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	operations := make([]*rpcpb.OperationRecord, 0, len(entries))
+	for _, entry := range entries {
+		operations = append(operations, &rpcpb.OperationRecord{
+			Time:     entry.Time.UnixNano(),
+			Client:   entry.Client,
+			Method:   entry.Method,
+			Request:  entry.Request,
+			Error:    entry.Error,
+			Duration: int64(entry.Duration),
+		})
+	}
+	return &rpcpb.GetOperationHistoryResponse{Operations: operations}, nil
+}
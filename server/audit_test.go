@@ -0,0 +1,81 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditKeyService(t *testing.T) {
+	require := require.New(t)
+
+	auditLog, err := newAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	require.NoError(err)
+	defer auditLog.close()
+	s := &server{log: logging.NoLog{}, auditLog: auditLog}
+
+	tests := []struct {
+		fullMethod      string
+		req             interface{}
+		expectedMethod  string
+		expectedRequest string
+	}{
+		{
+			fullMethod:      "/rpcpb.ControlService/SaveSnapshot",
+			req:             &rpcpb.SaveSnapshotRequest{SnapshotName: "snapshot", Passphrase: "secret"},
+			expectedMethod:  "SaveSnapshot",
+			expectedRequest: `{"snapshotName":"snapshot"}`,
+		},
+		{
+			fullMethod:      "/rpcpb.KeyService/ExportKey",
+			req:             &rpcpb.ExportKeyRequest{Name: "alice"},
+			expectedMethod:  "KeyService/ExportKey",
+			expectedRequest: `{"name":"alice"}`,
+		},
+		{
+			fullMethod:      "/rpcpb.KeyService/SignMessage",
+			req:             &rpcpb.SignMessageRequest{KeyName: "alice", Message: []byte("hello")},
+			expectedMethod:  "KeyService/SignMessage",
+			expectedRequest: `{"keyName":"alice"}`,
+		},
+		{
+			fullMethod:      "/rpcpb.KeyService/SignTx",
+			req:             &rpcpb.SignTxRequest{KeyName: "alice", UnsignedTx: []byte{1, 2, 3}, EvmChainId: 43112},
+			expectedMethod:  "KeyService/SignTx",
+			expectedRequest: `{"keyName":"alice","evmChainId":"43112"}`,
+		},
+		{
+			fullMethod: "/rpcpb.ControlService/GetOperationHistory",
+			req:        &rpcpb.GetOperationHistoryRequest{},
+		},
+		{
+			fullMethod: "/rpcpb.PingService/Ping",
+			req:        &rpcpb.PingRequest{},
+		},
+	}
+	for _, tt := range tests {
+		s.audit(context.Background(), tt.fullMethod, tt.req, time.Now(), nil)
+	}
+
+	entries, err := auditLog.read("", time.Time{}, len(tests))
+	require.NoError(err)
+	i := 0
+	for _, tt := range tests {
+		if tt.expectedMethod == "" {
+			continue
+		}
+		require.Less(i, len(entries))
+		require.Equal(tt.expectedMethod, entries[i].Method)
+		require.JSONEq(tt.expectedRequest, entries[i].Request)
+		i++
+	}
+	require.Len(entries, i)
+}
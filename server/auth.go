@@ -0,0 +1,166 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/luxdefi/netrunner/utils/constants"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	bearerPrefix = "Bearer "
+	// query param with the token of HTTP requests made by browsers, as
+	// they can't set headers on WebSocket requests
+	tokenQueryParam = "token"
+)
+
+var (
+	ErrUnauthenticated  = errors.New("missing or invalid API token")
+	ErrPermissionDenied = errors.New("API token not allowed to call this method")
+)
+
+// Methods that can be called without a token, so liveness checks
+// don't need one.
+var authExemptMethods = newMethodSet(
+	"/rpcpb.PingService/",
+)
+
+// Methods that only read the server state, allowed to read-only tokens.
+// ExportNetwork is left out, as the exported node flags may hold key
+// material.
+var readOnlyMethods = newMethodSet(
+	"/rpcpb.ControlService/RPCVersion",
+	"/rpcpb.ControlService/ListBlockchains",
+	"/rpcpb.ControlService/GetBlockchainStatus",
+	"/rpcpb.ControlService/GetUptimes",
+	"/rpcpb.ControlService/GetElasticSubnet",
+	"/rpcpb.ControlService/GetStakingRewards",
+	"/rpcpb.ControlService/GetVersions",
+	"/rpcpb.ControlService/GetTopology",
+	"/rpcpb.ControlService/CheckConnectivity",
+	"/rpcpb.ControlService/GetOperationHistory",
+	"/rpcpb.ControlService/Health",
+	"/rpcpb.ControlService/URIs",
+	"/rpcpb.ControlService/WaitForHealthy",
+	"/rpcpb.ControlService/Status",
+	"/rpcpb.ControlService/GetNodeInfo",
+	"/rpcpb.ControlService/ListNodes",
+	"/rpcpb.ControlService/ListCustomChains",
+	"/rpcpb.ControlService/StreamStatus",
+	"/rpcpb.ControlService/StreamLogs",
+	"/rpcpb.ControlService/SearchLogs",
+	"/rpcpb.ControlService/StreamPeerMessages",
+	"/rpcpb.ControlService/StreamProgress",
+	"/rpcpb.ControlService/GetSnapshotNames",
+	"/rpcpb.ControlService/GetDBSizes",
+	"/rpcpb.ControlService/WaitFor",
+	"/rpcpb.ControlService/Assert",
+	"/rpcpb.ControlService/GetChaosStatus",
+	"/rpcpb.KeyService/ListKeys",
+)
+
+type tokenRole int
+
+const (
+	roleNone tokenRole = iota
+	roleReadOnly
+	roleAdmin
+)
+
+// Returns true if API tokens are required to call the server.
+func (s *server) authEnabled() bool {
+	return len(s.cfg.AdminTokens) > 0 || len(s.cfg.ReadOnlyTokens) > 0
+}
+
+// Returns the role of [token], or roleNone if it is not a known token.
+func (s *server) getTokenRole(token string) tokenRole {
+	if token == "" {
+		return roleNone
+	}
+	// all tokens are compared, so the time taken doesn't tell which matched
+	role := roleNone
+	for _, t := range s.cfg.AdminTokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			role = roleAdmin
+		}
+	}
+	for _, t := range s.cfg.ReadOnlyTokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 && role == roleNone {
+			role = roleReadOnly
+		}
+	}
+	return role
+}
+
+// Returns the token given in the "authorization" header of [ctx].
+func getRequestToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(constants.AuthorizationMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(values[0], bearerPrefix)
+}
+
+// Returns the token given in the "Authorization" header of [r], or in
+// its [tokenQueryParam] query param.
+func getHTTPRequestToken(r *http.Request) string {
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		return strings.TrimPrefix(authorization, bearerPrefix)
+	}
+	return r.URL.Query().Get(tokenQueryParam)
+}
+
+// Returns an error if auth is enabled and the token of [ctx] is
+// not allowed to call [fullMethod].
+func (s *server) checkAuth(ctx context.Context, fullMethod string) error {
+	if !s.authEnabled() || authExemptMethods.contains(fullMethod) {
+		return nil
+	}
+	switch s.getTokenRole(getRequestToken(ctx)) {
+	case roleAdmin:
+		return nil
+	case roleReadOnly:
+		if readOnlyMethods.contains(fullMethod) {
+			return nil
+		}
+		return ErrPermissionDenied
+	default:
+		return ErrUnauthenticated
+	}
+}
+
+func (s *server) unaryAuthInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := s.checkAuth(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *server) streamAuthInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := s.checkAuth(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
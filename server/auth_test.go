@@ -0,0 +1,47 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxdefi/netrunner/utils/constants"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCheckAuth(t *testing.T) {
+	s := &server{cfg: Config{
+		AdminTokens:    []string{"admin"},
+		ReadOnlyTokens: []string{"reader"},
+	}}
+
+	tests := []struct {
+		name        string
+		token       string
+		method      string
+		expectedErr error
+	}{
+		{name: "exempt", method: "/rpcpb.PingService/Ping"},
+		{name: "no token", method: "/rpcpb.ControlService/Status", expectedErr: ErrUnauthenticated},
+		{name: "unknown token", token: "other", method: "/rpcpb.ControlService/Status", expectedErr: ErrUnauthenticated},
+		{name: "read-only reading", token: "reader", method: "/rpcpb.ControlService/Status"},
+		{name: "read-only writing", token: "reader", method: "/rpcpb.ControlService/Stop", expectedErr: ErrPermissionDenied},
+		{name: "read-only exporting", token: "reader", method: "/rpcpb.ControlService/ExportNetwork", expectedErr: ErrPermissionDenied},
+		{name: "admin exporting", token: "admin", method: "/rpcpb.ControlService/ExportNetwork"},
+		{name: "read-only listing keys", token: "reader", method: "/rpcpb.KeyService/ListKeys"},
+		{name: "read-only signing", token: "reader", method: "/rpcpb.KeyService/SignMessage", expectedErr: ErrPermissionDenied},
+		{name: "admin signing", token: "admin", method: "/rpcpb.KeyService/SignMessage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(constants.AuthorizationMetadataKey, bearerPrefix+tt.token))
+			}
+			require.ErrorIs(t, s.checkAuth(ctx, tt.method), tt.expectedErr)
+		})
+	}
+}
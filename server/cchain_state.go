@@ -0,0 +1,101 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/luxdefi/netrunner/local"
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/netrunner/utils"
+	"go.uber.org/zap"
+)
+
+// Dumps the C-Chain state of a node of the network, and writes it as a C-Chain
+// genesis with alloc, to seed new networks with (see StartRequest.CChainStatePath).
+func (s *server) ExportCChainState(ctx context.Context, req *rpcpb.ExportCChainStateRequest) (*rpcpb.ExportCChainStateResponse, error) {
+	s.log.Debug("ExportCChainState",
+		zap.String("path", req.Path),
+		zap.String("node-name", req.NodeName),
+		zap.Uint64("block-number", req.BlockNumber),
+	)
+
+	if req.Path == "" {
+		return nil, ErrNoCChainStatePath
+	}
+	s.mu.RLock()
+	if s.network == nil || s.clusterInfo == nil {
+		s.mu.RUnlock()
+		return nil, ErrNotBootstrapped
+	}
+	rootDir := s.clusterInfo.RootDataDir
+	s.mu.RUnlock()
+	path, err := getCChainStatePath(req.Path, rootDir, s.cfg.SnapshotsDir)
+	if err != nil {
+		return nil, invalidArgumentError(err)
+	}
+	nd, cChainGenesis, err := s.getCChainStateNode(req.NodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	// the dump may take long for big states, so [s.opMu] isn't held
+	rpcURL := fmt.Sprintf("%s/ext/bc/%s/rpc", utils.HTTPURI(nd.GetURL(), nd.GetAPIPort()), cChainAlias)
+	alloc, err := network.DumpCChainAlloc(ctx, rpcURL, req.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't dump C-Chain state of node %q: %w", nd.GetName(), err)
+	}
+	if err := network.WriteCChainAlloc(path, cChainGenesis, alloc); err != nil {
+		return nil, err
+	}
+	s.log.Info("exported C-Chain state",
+		zap.String("node-name", nd.GetName()),
+		zap.String("path", path),
+		zap.Int("num-accounts", len(alloc)),
+	)
+	return &rpcpb.ExportCChainStateResponse{
+		Path:        path,
+		NumAccounts: uint32(len(alloc)),
+	}, nil
+}
+
+// Returns the path of the C-Chain state file given by a client as [path].
+// Relative paths are taken from [rootDir], if given, or from the snapshots
+// dir, and the path must be under one of them, so clients can't read or
+// write arbitrary files of the server host. States exported under the
+// snapshots dir outlive the network, to seed new ones.
+func getCChainStatePath(path string, rootDir string, snapshotsDir string) (string, error) {
+	return getConfinedPath(path, ErrInvalidCChainStatePath, rootDir, local.GetSnapshotsDir(snapshotsDir))
+}
+
+// Returns the node [nodeName] (the first running node if empty), and the
+// C-Chain genesis of the network.
+func (s *server) getCChainStateNode(nodeName string) (node.Node, string, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return nil, "", ErrNotBootstrapped
+	}
+	cChainGenesis, err := network.CChainGenesis(s.network.cfg.Genesis)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if nodeName != "" {
+		nd, err := s.network.nw.GetNode(nodeName)
+		if err != nil {
+			return nil, "", err
+		}
+		return nd, cChainGenesis, nil
+	}
+	nd, err := s.getFirstRunningNode()
+	if err != nil {
+		return nil, "", err
+	}
+	return nd, cChainGenesis, nil
+}
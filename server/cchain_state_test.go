@@ -0,0 +1,69 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetCChainStatePath(t *testing.T) {
+	rootDir := filepath.Join(t.TempDir(), "network")
+	snapshotsDir := filepath.Join(t.TempDir(), "snapshots")
+
+	tests := []struct {
+		name         string
+		path         string
+		rootDir      string
+		expectedPath string
+		expectErr    bool
+	}{
+		{name: "relative", path: "state.json", rootDir: rootDir, expectedPath: filepath.Join(rootDir, "state.json")},
+		{name: "relative without root dir", path: "state.json", expectedPath: filepath.Join(snapshotsDir, "state.json")},
+		{name: "under root dir", path: filepath.Join(rootDir, "a", "state.json"), rootDir: rootDir, expectedPath: filepath.Join(rootDir, "a", "state.json")},
+		{name: "under snapshots dir", path: filepath.Join(snapshotsDir, "state.json"), rootDir: rootDir, expectedPath: filepath.Join(snapshotsDir, "state.json")},
+		{name: "under root dir without root dir", path: filepath.Join(rootDir, "state.json"), expectErr: true},
+		{name: "outside", path: "/etc/passwd", rootDir: rootDir, expectErr: true},
+		{name: "parent elements", path: "../state.json", rootDir: rootDir, expectErr: true},
+		{name: "dir itself", path: snapshotsDir, rootDir: rootDir, expectErr: true},
+		{name: "sibling with same prefix", path: snapshotsDir + "2/state.json", rootDir: rootDir, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := getCChainStatePath(tt.path, tt.rootDir, snapshotsDir)
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrInvalidCChainStatePath)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedPath, path)
+		})
+	}
+}
+
+func TestExportCChainStateInvalidPath(t *testing.T) {
+	s, _ := newTestServer(t, 1)
+
+	_, err := s.ExportCChainState(context.Background(), &rpcpb.ExportCChainStateRequest{Path: "/etc/cron.d/state"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestExportCChainStateNotStarted(t *testing.T) {
+	s := &server{
+		opMu: newOpLocks(),
+		mu:   new(sync.RWMutex),
+		log:  logging.NoLog{},
+	}
+
+	_, err := s.ExportCChainState(context.Background(), &rpcpb.ExportCChainStateRequest{Path: "state.json"})
+	require.ErrorIs(t, err, ErrNotBootstrapped)
+}
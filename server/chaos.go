@@ -0,0 +1,380 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultChaosInterval = time.Minute
+	defaultChaosDowntime = 30 * time.Second
+	defaultChaosMaxDown  = 1
+	// max interval and downtime, so the random waits don't overflow
+	maxChaosDuration = 7 * 24 * time.Hour
+	// max number of chaos events kept, the oldest being dropped
+	maxChaosEvents = 1000
+)
+
+var allChaosActions = []rpcpb.ChaosAction{
+	rpcpb.ChaosAction_CHAOS_ACTION_PAUSE,
+	rpcpb.ChaosAction_CHAOS_ACTION_KILL,
+	rpcpb.ChaosAction_CHAOS_ACTION_RESTART,
+}
+
+// Randomly takes nodes of the network down and back up, to test its
+// resilience during long running soak tests.
+type chaosMonkey struct {
+	lock sync.Mutex
+	// config of the last run, with the defaults applied
+	cfg *rpcpb.StartChaosRequest
+	// set while running
+	cancel context.CancelFunc
+	// closed once the running loop returns
+	done   chan struct{}
+	events []*rpcpb.ChaosEvent
+}
+
+func newChaosMonkey() *chaosMonkey {
+	return &chaosMonkey{}
+}
+
+func (c *chaosMonkey) addEvent(event *rpcpb.ChaosEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.events = append(c.events, event)
+	if len(c.events) > maxChaosEvents {
+		c.events = c.events[len(c.events)-maxChaosEvents:]
+	}
+}
+
+func (c *chaosMonkey) getEvents() []*rpcpb.ChaosEvent {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return append([]*rpcpb.ChaosEvent{}, c.events...)
+}
+
+// A node taken down by the chaos monkey, to be brought back up.
+type chaosDownNode struct {
+	action rpcpb.ChaosAction
+	upAt   time.Time
+}
+
+// Starts taking random nodes of the network down and back up, until
+// StopChaos is called or the network is stopped.
+func (s *server) StartChaos(_ context.Context, req *rpcpb.StartChaosRequest) (*rpcpb.StartChaosResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	s.log.Debug("StartChaos",
+		zap.Duration("interval", time.Duration(req.GetInterval())),
+		zap.Duration("downtime", time.Duration(req.GetDowntime())),
+		zap.Strings("node-names", req.GetNodeNames()),
+	)
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+	for _, nodeName := range req.GetNodeNames() {
+		if _, err := s.network.nw.GetNode(nodeName); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, nodeName)
+		}
+	}
+	if err := validateChaosRequest(req); err != nil {
+		return nil, invalidArgumentError(err)
+	}
+	for _, action := range req.GetActions() {
+		if _, ok := rpcpb.ChaosAction_name[int32(action)]; !ok || action == rpcpb.ChaosAction_CHAOS_ACTION_UNSPECIFIED {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidChaosAction, action)
+		}
+	}
+
+	cfg := proto.Clone(req).(*rpcpb.StartChaosRequest)
+	if cfg.Interval == 0 {
+		cfg.Interval = int64(defaultChaosInterval)
+	}
+	if cfg.Downtime == 0 {
+		cfg.Downtime = int64(defaultChaosDowntime)
+	}
+	if len(cfg.Actions) == 0 {
+		cfg.Actions = allChaosActions
+	}
+	if cfg.MaxDown == 0 {
+		cfg.MaxDown = defaultChaosMaxDown
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+
+	s.chaos.lock.Lock()
+	defer s.chaos.lock.Unlock()
+
+	if s.chaos.cancel != nil {
+		return nil, ErrChaosRunning
+	}
+	ctx, cancel := context.WithCancel(s.rootCtx)
+	done := make(chan struct{})
+	s.chaos.cfg = cfg
+	s.chaos.cancel = cancel
+	s.chaos.done = done
+	s.chaos.events = nil
+	s.log.Info("starting chaos", zap.Int64("seed", cfg.Seed))
+	go s.runChaos(ctx, s.network, cfg, done)
+
+	return &rpcpb.StartChaosResponse{}, nil
+}
+
+// Checks the durations of [req] are in range. Zero ones take the defaults.
+func validateChaosRequest(req *rpcpb.StartChaosRequest) error {
+	if req.GetInterval() < 0 || time.Duration(req.GetInterval()) > maxChaosDuration {
+		return fmt.Errorf("%w: interval %s is not between 0 and %s", ErrInvalidChaosConfig, time.Duration(req.GetInterval()), maxChaosDuration)
+	}
+	if req.GetDowntime() < 0 || time.Duration(req.GetDowntime()) > maxChaosDuration {
+		return fmt.Errorf("%w: downtime %s is not between 0 and %s", ErrInvalidChaosConfig, time.Duration(req.GetDowntime()), maxChaosDuration)
+	}
+	return nil
+}
+
+// Returns the wait before the next chaos action, picked at random between
+// half and one and a half of [interval], which must be positive.
+func getChaosWait(rng *rand.Rand, interval time.Duration) time.Duration {
+	return interval/2 + time.Duration(rng.Int63n(int64(interval)+1))
+}
+
+// Stops taking nodes down, and brings back up the ones that are down.
+func (s *server) StopChaos(_ context.Context, _ *rpcpb.StopChaosRequest) (*rpcpb.StopChaosResponse, error) {
+	s.log.Debug("StopChaos")
+
+	s.chaos.lock.Lock()
+	cancel, done := s.chaos.cancel, s.chaos.done
+	s.chaos.lock.Unlock()
+
+	if cancel == nil {
+		return nil, ErrChaosNotRunning
+	}
+	// the running loop takes [s.opMu] to bring the nodes back up, so it
+	// isn't held while waiting
+	cancel()
+	<-done
+	return &rpcpb.StopChaosResponse{Events: s.chaos.getEvents()}, nil
+}
+
+func (s *server) GetChaosStatus(_ context.Context, _ *rpcpb.GetChaosStatusRequest) (*rpcpb.GetChaosStatusResponse, error) {
+	s.log.Debug("GetChaosStatus")
+
+	s.chaos.lock.Lock()
+	resp := &rpcpb.GetChaosStatusResponse{
+		Enabled: s.chaos.cancel != nil,
+	}
+	if s.chaos.cfg != nil {
+		resp.Config = proto.Clone(s.chaos.cfg).(*rpcpb.StartChaosRequest)
+	}
+	s.chaos.lock.Unlock()
+
+	resp.Events = s.chaos.getEvents()
+	return resp, nil
+}
+
+// Takes random nodes of [nw] down, and back up after the downtime, until
+// [ctx] is done or [nw] is stopped. Closes [done] on return.
+func (s *server) runChaos(ctx context.Context, nw *localNetwork, cfg *rpcpb.StartChaosRequest, done chan struct{}) {
+	defer func() {
+		s.chaos.lock.Lock()
+		if s.chaos.done == done {
+			s.chaos.cancel()
+			s.chaos.cancel = nil
+		}
+		s.chaos.lock.Unlock()
+		close(done)
+	}()
+
+	rng := rand.New(rand.NewSource(cfg.Seed)) //nolint:gosec
+	interval := time.Duration(cfg.Interval)
+	downtime := time.Duration(cfg.Downtime)
+	// maps from the name of each node taken down to how to bring it up
+	down := map[string]chaosDownNode{}
+
+	nextActionAt := time.Now().Add(getChaosWait(rng, interval))
+	for {
+		wakeAt := nextActionAt
+		for _, downNode := range down {
+			if downNode.upAt.Before(wakeAt) {
+				wakeAt = downNode.upAt
+			}
+		}
+		timer := time.NewTimer(time.Until(wakeAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			// the nodes aren't left down once chaos is stopped
+			nodeNames := maps.Keys(down)
+			sort.Strings(nodeNames)
+			for _, nodeName := range nodeNames {
+				s.chaosRecover(nw, nodeName, down[nodeName].action)
+			}
+			return
+		case <-nw.stopCh:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		nodeNames := maps.Keys(down)
+		sort.Strings(nodeNames)
+		for _, nodeName := range nodeNames {
+			if !down[nodeName].upAt.After(now) {
+				s.chaosRecover(nw, nodeName, down[nodeName].action)
+				delete(down, nodeName)
+			}
+		}
+		if now.Before(nextActionAt) {
+			continue
+		}
+		nextActionAt = now.Add(getChaosWait(rng, interval))
+
+		if uint32(len(down)) >= cfg.MaxDown {
+			continue
+		}
+		candidates := s.getChaosCandidates(nw, cfg.NodeNames, down)
+		if len(candidates) == 0 {
+			continue
+		}
+		nodeName := candidates[rng.Intn(len(candidates))]
+		action := cfg.Actions[rng.Intn(len(cfg.Actions))]
+		if s.chaosTakeDown(nw, nodeName, action) && action != rpcpb.ChaosAction_CHAOS_ACTION_RESTART {
+			down[nodeName] = chaosDownNode{action: action, upAt: time.Now().Add(downtime)}
+		}
+	}
+}
+
+// Returns the sorted names of the running nodes of [nw] in [nodeNames],
+// or in all the network if empty, that aren't [down].
+func (s *server) getChaosCandidates(nw *localNetwork, nodeNames []string, down map[string]chaosDownNode) []string {
+	nodes, err := nw.nw.GetAllNodes()
+	if err != nil {
+		return nil
+	}
+	if len(nodeNames) == 0 {
+		nodeNames = maps.Keys(nodes)
+	}
+	candidates := []string{}
+	for _, nodeName := range nodeNames {
+		node, ok := nodes[nodeName]
+		if !ok || node.GetPaused() {
+			continue
+		}
+		if _, ok := down[nodeName]; ok {
+			continue
+		}
+		candidates = append(candidates, nodeName)
+	}
+	sort.Strings(candidates)
+	return candidates
+}
+
+// Applies [action] to [nodeName], returning true if it succeeded.
+func (s *server) chaosTakeDown(nw *localNetwork, nodeName string, action rpcpb.ChaosAction) bool {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network != nw {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+
+	var err error
+	switch action {
+	case rpcpb.ChaosAction_CHAOS_ACTION_PAUSE:
+		err = nw.nw.PauseNode(ctx, nodeName)
+	case rpcpb.ChaosAction_CHAOS_ACTION_KILL:
+		err = killNode(nw, nodeName)
+	case rpcpb.ChaosAction_CHAOS_ACTION_RESTART:
+		err = nw.nw.RestartNode(ctx, nodeName, "", "", "", nil, nil, nil, false)
+	}
+	s.addChaosEvent(nodeName, action, false, err)
+	s.updateChaosNodeInfos(nw)
+	return err == nil
+}
+
+// Brings [nodeName] back up, after being taken down by [action].
+func (s *server) chaosRecover(nw *localNetwork, nodeName string, action rpcpb.ChaosAction) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network != nw {
+		return
+	}
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+
+	var err error
+	switch action {
+	case rpcpb.ChaosAction_CHAOS_ACTION_PAUSE:
+		err = nw.nw.ResumeNode(ctx, nodeName)
+	case rpcpb.ChaosAction_CHAOS_ACTION_KILL:
+		err = nw.nw.RestartNode(ctx, nodeName, "", "", "", nil, nil, nil, false)
+	}
+	s.addChaosEvent(nodeName, action, true, err)
+	s.updateChaosNodeInfos(nw)
+}
+
+// Sends a SIGKILL to the process group of [nodeName], as if it crashed.
+// The node processes lead their own group, so the VM plugins are killed
+// along with it.
+func killNode(nw *localNetwork, nodeName string) error {
+	node, err := nw.nw.GetNode(nodeName)
+	if err != nil {
+		return err
+	}
+	pid := node.GetPID()
+	if pid == 0 {
+		return fmt.Errorf("%w: node %s is not running", ErrInvalidNodeState, nodeName)
+	}
+	return syscall.Kill(-pid, syscall.SIGKILL)
+}
+
+// Assumes [s.opMu] is held.
+func (s *server) updateChaosNodeInfos(nw *localNetwork) {
+	if err := nw.UpdateNodeInfo(); err != nil {
+		s.log.Warn("couldn't update node infos after chaos action", zap.Error(err))
+		return
+	}
+	s.updateClusterNodeInfos()
+}
+
+func (s *server) addChaosEvent(nodeName string, action rpcpb.ChaosAction, recovery bool, err error) {
+	event := &rpcpb.ChaosEvent{
+		Timestamp: time.Now().UnixNano(),
+		NodeName:  nodeName,
+		Action:    action,
+		Recovery:  recovery,
+	}
+	fields := []zap.Field{
+		zap.String("node-name", nodeName),
+		zap.String("action", action.String()),
+		zap.Bool("recovery", recovery),
+	}
+	if err != nil {
+		event.Error = err.Error()
+		s.log.Warn("chaos action failed", append(fields, zap.Error(err))...)
+	} else {
+		s.log.Info("chaos action", fields...)
+	}
+	s.chaos.addEvent(event)
+}
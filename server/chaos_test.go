@@ -0,0 +1,108 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateChaosRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *rpcpb.StartChaosRequest
+		expectErr bool
+	}{
+		{name: "defaults", req: &rpcpb.StartChaosRequest{}},
+		{name: "in range", req: &rpcpb.StartChaosRequest{Interval: int64(time.Minute), Downtime: int64(time.Second)}},
+		{name: "negative interval", req: &rpcpb.StartChaosRequest{Interval: -1}, expectErr: true},
+		{name: "max interval", req: &rpcpb.StartChaosRequest{Interval: math.MaxInt64}, expectErr: true},
+		{name: "negative downtime", req: &rpcpb.StartChaosRequest{Downtime: -1}, expectErr: true},
+		{name: "max downtime", req: &rpcpb.StartChaosRequest{Downtime: math.MaxInt64}, expectErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateChaosRequest(tt.req)
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrInvalidChaosConfig)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetChaosWait(t *testing.T) {
+	require := require.New(t)
+
+	rng := rand.New(rand.NewSource(1)) //nolint:gosec
+	for _, interval := range []time.Duration{time.Nanosecond, time.Minute, maxChaosDuration} {
+		for i := 0; i < 100; i++ {
+			wait := getChaosWait(rng, interval)
+			require.GreaterOrEqual(wait, interval/2)
+			require.LessOrEqual(wait, interval/2+interval)
+		}
+	}
+}
+
+func TestStartChaosInvalidConfig(t *testing.T) {
+	s, _ := newTestServer(t, 2)
+
+	_, err := s.StartChaos(context.Background(), &rpcpb.StartChaosRequest{Interval: -1})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.GetChaosStatus(context.Background(), &rpcpb.GetChaosStatusRequest{})
+	require.NoError(t, err)
+	require.Nil(t, s.chaos.cancel)
+}
+
+func TestChaosLoop(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, _ := newTestServer(t, 3)
+
+	_, err := s.StartChaos(ctx, &rpcpb.StartChaosRequest{
+		Interval: int64(10 * time.Millisecond),
+		Downtime: int64(20 * time.Millisecond),
+		Actions:  []rpcpb.ChaosAction{rpcpb.ChaosAction_CHAOS_ACTION_PAUSE},
+		MaxDown:  2,
+		Seed:     1,
+	})
+	require.NoError(err)
+	_, err = s.StartChaos(ctx, &rpcpb.StartChaosRequest{})
+	require.ErrorIs(err, ErrChaosRunning)
+
+	require.Eventually(func() bool {
+		for _, event := range s.chaos.getEvents() {
+			if event.Recovery {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	resp, err := s.StopChaos(ctx, &rpcpb.StopChaosRequest{})
+	require.NoError(err)
+	for _, event := range resp.Events {
+		require.Equal(rpcpb.ChaosAction_CHAOS_ACTION_PAUSE, event.Action)
+		require.Empty(event.Error)
+	}
+
+	// the nodes are brought back up once chaos is stopped
+	clusterInfo, err := s.copyClusterInfo()
+	require.NoError(err)
+	for _, nodeInfo := range clusterInfo.NodeInfos {
+		require.False(nodeInfo.Paused)
+	}
+	_, err = s.StopChaos(ctx, &rpcpb.StopChaosRequest{})
+	require.ErrorIs(err, ErrChaosNotRunning)
+}
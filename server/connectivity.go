@@ -0,0 +1,80 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"go.uber.org/zap"
+)
+
+// Reports the connections between running nodes missing from the peers
+// reported by their info API, as a network may be healthy while some
+// nodes are not connected to each other. A node that can't be reached
+// doesn't fail the check, its connections are reported as missing.
+func (s *server) CheckConnectivity(
+	_ context.Context,
+	_ *rpcpb.CheckConnectivityRequest,
+) (*rpcpb.CheckConnectivityResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("CheckConnectivity")
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	topology, err := nw.GetTopology(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := getMissingConnections(topology)
+	if !resp.FullyConnected {
+		s.log.Info("nodes not fully connected", zap.Int("num-missing", len(resp.Missing)))
+	}
+	return resp, nil
+}
+
+// Returns the connections between the running nodes of [topology] that
+// are not reported by any of its edges. Paused nodes are not expected to
+// be connected. The connections of a node whose peers couldn't be got are
+// all missing, with the error of the node.
+func getMissingConnections(topology *rpcpb.GetTopologyResponse) *rpcpb.CheckConnectivityResponse {
+	peers := map[string]map[string]struct{}{}
+	for _, edge := range topology.Edges {
+		if edge.To == "" {
+			continue
+		}
+		if peers[edge.From] == nil {
+			peers[edge.From] = map[string]struct{}{}
+		}
+		peers[edge.From][edge.To] = struct{}{}
+	}
+
+	resp := &rpcpb.CheckConnectivityResponse{}
+	// nodes are sorted by name, so the missing connections are too
+	for _, from := range topology.Nodes {
+		if from.Paused {
+			continue
+		}
+		for _, to := range topology.Nodes {
+			if to.Paused || to.Name == from.Name {
+				continue
+			}
+			resp.NumExpected++
+			if _, ok := peers[from.Name][to.Name]; !ok {
+				resp.Missing = append(resp.Missing, &rpcpb.MissingConnection{
+					From:  from.Name,
+					To:    to.Name,
+					Error: from.Error,
+				})
+			}
+		}
+	}
+	resp.FullyConnected = len(resp.Missing) == 0
+	return resp
+}
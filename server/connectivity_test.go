@@ -0,0 +1,100 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apimocks "github.com/luxdefi/netrunner/api/mocks"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMissingConnections(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology *rpcpb.GetTopologyResponse
+		expected *rpcpb.CheckConnectivityResponse
+	}{
+		{
+			name: "fully connected",
+			topology: &rpcpb.GetTopologyResponse{
+				Nodes: []*rpcpb.TopologyNode{{Name: "node1"}, {Name: "node2"}},
+				Edges: []*rpcpb.TopologyEdge{
+					{From: "node1", To: "node2"},
+					{From: "node2", To: "node1"},
+				},
+			},
+			expected: &rpcpb.CheckConnectivityResponse{FullyConnected: true, NumExpected: 2},
+		},
+		{
+			name: "paused node",
+			topology: &rpcpb.GetTopologyResponse{
+				Nodes: []*rpcpb.TopologyNode{{Name: "node1"}, {Name: "node2", Paused: true}},
+			},
+			expected: &rpcpb.CheckConnectivityResponse{FullyConnected: true},
+		},
+		{
+			name: "missing connection",
+			topology: &rpcpb.GetTopologyResponse{
+				Nodes: []*rpcpb.TopologyNode{{Name: "node1"}, {Name: "node2"}},
+				Edges: []*rpcpb.TopologyEdge{{From: "node1", To: "node2"}},
+			},
+			expected: &rpcpb.CheckConnectivityResponse{
+				NumExpected: 2,
+				Missing:     []*rpcpb.MissingConnection{{From: "node2", To: "node1"}},
+			},
+		},
+		{
+			name: "unreachable node",
+			topology: &rpcpb.GetTopologyResponse{
+				Nodes: []*rpcpb.TopologyNode{
+					{Name: "node1"},
+					{Name: "node2", Error: "unreachable"},
+					{Name: "node3"},
+				},
+				Edges: []*rpcpb.TopologyEdge{
+					{From: "node1", To: "node2"},
+					{From: "node1", To: "node3"},
+					{From: "node3", To: "node1"},
+					{From: "node3", To: "node2"},
+				},
+			},
+			expected: &rpcpb.CheckConnectivityResponse{
+				NumExpected: 6,
+				Missing: []*rpcpb.MissingConnection{
+					{From: "node2", To: "node1", Error: "unreachable"},
+					{From: "node2", To: "node3", Error: "unreachable"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, getMissingConnections(tt.topology))
+		})
+	}
+}
+
+func TestCheckConnectivityUnreachableNode(t *testing.T) {
+	require := require.New(t)
+
+	s, nw := newTestServer(t, 2)
+	for nodeName, peersErr := range map[string]error{"node1": nil, "node2": errors.New("unreachable")} {
+		client := &apimocks.Client{}
+		client.On("InfoAPI").Return(&testInfoClient{peersErr: peersErr})
+		require.NoError(nw.SetAPIClient(nodeName, client))
+	}
+
+	resp, err := s.CheckConnectivity(context.Background(), &rpcpb.CheckConnectivityRequest{})
+	require.NoError(err)
+	require.False(resp.FullyConnected)
+	require.Equal(uint32(2), resp.NumExpected)
+	require.Len(resp.Missing, 2)
+	require.Empty(resp.Missing[0].Error)
+	require.Equal("node2", resp.Missing[1].From)
+	require.Contains(resp.Missing[1].Error, "unreachable")
+}
@@ -0,0 +1,172 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/luxdefi/netrunner/utils/constants"
+	"go.uber.org/zap"
+)
+
+const (
+	dashboardPathPrefix = "/dashboard/"
+	dashboardLogsPath   = dashboardPathPrefix + "logs"
+	// node log file tailed by the dashboard
+	nodeMainLogFname = "main.log"
+	// amount of the log file sent when starting to tail it
+	logsTailBytes        = 16 * 1024
+	logsPollInterval     = 500 * time.Millisecond
+	logsWriteWaitTimeout = 10 * time.Second
+)
+
+//go:embed dashboard
+var dashboardFS embed.FS
+
+var wsUpgrader = websocket.Upgrader{}
+
+// Registers the dashboard handlers on [mux]. The dashboard is a static
+// page using the gRPC gateway endpoints, plus a WebSocket feed with the
+// logs of a node.
+func (s *server) registerDashboard(mux *http.ServeMux) error {
+	staticFS, err := fs.Sub(dashboardFS, "dashboard")
+	if err != nil {
+		return err
+	}
+	mux.Handle(dashboardPathPrefix, http.StripPrefix(dashboardPathPrefix, http.FileServer(http.FS(staticFS))))
+	mux.HandleFunc(dashboardLogsPath, s.serveDashboardLogs)
+	return nil
+}
+
+// Streams the main log file of the node given in the "node" query
+// param, first sending its tail, and then the lines appended to it.
+// As with StreamLogs, read-only tokens can read the logs.
+func (s *server) serveDashboardLogs(w http.ResponseWriter, r *http.Request) {
+	if s.authEnabled() && s.getTokenRole(getHTTPRequestToken(r)) == roleNone {
+		http.Error(w, ErrUnauthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+	networkName := r.URL.Query().Get("network")
+	if networkName == "" {
+		networkName = constants.DefaultNetworkName
+	}
+	nodeName := r.URL.Query().Get("node")
+	logsDir, err := s.getNodeLogsDir(networkName, nodeName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	f, err := os.Open(filepath.Join(logsDir, nodeMainLogFname))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.log.Debug("dashboard logs upgrade failed", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+
+	// the client doesn't send messages; read until it closes the connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if info, err := f.Stat(); err == nil && info.Size() > logsTailBytes {
+		if _, err := f.Seek(-logsTailBytes, io.SeekEnd); err != nil {
+			return
+		}
+	}
+
+	buf := make([]byte, 32*1024)
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			_ = conn.SetWriteDeadline(time.Now().Add(logsWriteWaitTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, buf[:n]); err != nil {
+				s.log.Debug("dashboard logs write failed", zap.String("node-name", nodeName), zap.Error(err))
+				return
+			}
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			s.log.Debug("dashboard logs read failed", zap.String("node-name", nodeName), zap.Error(err))
+			return
+		}
+		if n == len(buf) {
+			// there is more to read
+			continue
+		}
+		select {
+		case <-s.rootCtx.Done():
+			return
+		case <-closed:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *server) getNodeLogsDir(networkName string, nodeName string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.network == nil {
+		return "", ErrNotBootstrapped
+	}
+	if networkName != s.networkName {
+		return "", fmt.Errorf("%w: %q", ErrNetworkNotFound, networkName)
+	}
+	nodeInfo, ok := s.clusterInfo.NodeInfos[nodeName]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNodeNotFound, nodeName)
+	}
+	return nodeInfo.LogDir, nil
+}
+
+// Returns the logs dir of [nodeName], or of every node if empty,
+// by node name.
+func (s *server) getNodesLogsDir(networkName string, nodeName string) (map[string]string, error) {
+	if nodeName != "" {
+		logsDir, err := s.getNodeLogsDir(networkName, nodeName)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{nodeName: logsDir}, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+	if networkName != s.networkName {
+		return nil, fmt.Errorf("%w: %q", ErrNetworkNotFound, networkName)
+	}
+	nodesLogsDir := make(map[string]string, len(s.clusterInfo.NodeInfos))
+	for name, nodeInfo := range s.clusterInfo.NodeInfos {
+		nodesLogsDir[name] = nodeInfo.LogDir
+	}
+	return nodesLogsDir, nil
+}
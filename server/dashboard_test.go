@@ -0,0 +1,44 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeDashboardLogsAuth(t *testing.T) {
+	s, _ := newTestServer(t, 1)
+	s.cfg.AdminTokens = []string{"admin"}
+	s.cfg.ReadOnlyTokens = []string{"reader"}
+
+	tests := []struct {
+		name           string
+		header         string
+		query          string
+		expectedStatus int
+	}{
+		{name: "no token", expectedStatus: http.StatusUnauthorized},
+		{name: "unknown token", header: bearerPrefix + "other", expectedStatus: http.StatusUnauthorized},
+		{name: "unknown query token", query: "&token=other", expectedStatus: http.StatusUnauthorized},
+		// the node has no log file
+		{name: "read-only token", header: bearerPrefix + "reader", expectedStatus: http.StatusNotFound},
+		{name: "admin token", header: bearerPrefix + "admin", expectedStatus: http.StatusNotFound},
+		{name: "query token", query: "&token=reader", expectedStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, dashboardLogsPath+"?node=node1"+tt.query, nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			s.serveDashboardLogs(w, r)
+			require.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
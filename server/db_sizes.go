@@ -0,0 +1,226 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/netrunner/utils"
+	"github.com/luxdefi/node/config"
+	"go.uber.org/zap"
+)
+
+const (
+	// default chain data dir of the nodes, under their data dir
+	defaultChainDataSubdir = "chainData"
+	// dir of the C-Chain offline pruning data, under the node data dir
+	offlinePruningSubdir = "offline-pruning"
+)
+
+// Reports the sizes on disk of the db and chain data dirs of the nodes.
+// The dirs are walked without holding [s.mu], as it can take a while.
+func (s *server) GetDBSizes(_ context.Context, req *rpcpb.GetDBSizesRequest) (*rpcpb.GetDBSizesResponse, error) {
+	s.log.Debug("GetDBSizes", zap.Strings("node-names", req.NodeNames))
+
+	nodes, err := s.getDBSizesNodes(req.NodeNames)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &rpcpb.GetDBSizesResponse{NodeDbSizes: map[string]*rpcpb.NodeDBSizes{}}
+	for nodeName, nd := range nodes {
+		sizes, err := getNodeDBSizes(nd)
+		if err != nil {
+			return nil, err
+		}
+		resp.NodeDbSizes[nodeName] = sizes
+	}
+	return resp, nil
+}
+
+// Returns the nodes named [nodeNames], or all of them if none is given.
+func (s *server) getDBSizesNodes(nodeNames []string) (map[string]node.Node, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	if len(nodeNames) == 0 {
+		return s.network.nw.GetAllNodes()
+	}
+	nodes := map[string]node.Node{}
+	for _, nodeName := range nodeNames {
+		nd, err := s.network.nw.GetNode(nodeName)
+		if err != nil {
+			return nil, err
+		}
+		nodes[nodeName] = nd
+	}
+	return nodes, nil
+}
+
+// Prunes the C-Chain state of a node offline, by restarting it with offline
+// pruning enabled, and then with its original C-Chain config, as pruning
+// runs on every start while enabled.
+func (s *server) PruneNodeDB(ctx context.Context, req *rpcpb.PruneNodeDBRequest) (*rpcpb.PruneNodeDBResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	s.log.Debug("PruneNodeDB", zap.String("name", req.Name))
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	nd, err := s.network.nw.GetNode(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	before, err := getNodeDBSizes(nd)
+	if err != nil {
+		return nil, err
+	}
+
+	cChainConfig := nd.GetConfig().ChainConfigFiles[cChainAlias]
+	if cChainConfig == "" {
+		cChainConfig = "{}"
+	}
+	pruningConfig := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(cChainConfig), &pruningConfig); err != nil {
+		return nil, fmt.Errorf("couldn't parse C-Chain config of node %q: %w", req.Name, err)
+	}
+	pruningConfig["pruning-enabled"] = true
+	pruningConfig["offline-pruning-enabled"] = true
+	pruningConfig["offline-pruning-data-directory"] = filepath.Join(nd.GetDataDir(), offlinePruningSubdir)
+	pruningConfigBytes, err := json.Marshal(pruningConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	restored := false
+	defer func() {
+		if restored {
+			return
+		}
+		// [ctx] may be done, so the node is not left pruning on every start
+		restoreCtx, cancel := context.WithTimeout(context.Background(), waitForHealthyTimeout)
+		defer cancel()
+		if err := s.restartWithCChainConfig(restoreCtx, req.Name, cChainConfig); err != nil {
+			s.log.Error("failed to restore the C-Chain config of node", zap.String("name", req.Name), zap.Error(err))
+		}
+		if err := s.network.UpdateNodeInfo(); err != nil {
+			s.log.Error("failed to update node info", zap.Error(err))
+			return
+		}
+		s.updateClusterNodeInfos()
+	}()
+
+	// the pruning runs when the C-Chain starts, so it is done once the
+	// network is healthy again
+	if err := s.restartWithCChainConfig(ctx, req.Name, string(pruningConfigBytes)); err != nil {
+		return nil, err
+	}
+	if err := s.network.nw.Healthy(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.restartWithCChainConfig(ctx, req.Name, cChainConfig); err != nil {
+		return nil, err
+	}
+	restored = true
+	if err := s.network.nw.Healthy(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := os.RemoveAll(filepath.Join(nd.GetDataDir(), offlinePruningSubdir)); err != nil {
+		return nil, err
+	}
+
+	nd, err = s.network.nw.GetNode(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	after, err := getNodeDBSizes(nd)
+	if err != nil {
+		return nil, err
+	}
+	s.log.Info("pruned node db",
+		zap.String("node-name", req.Name),
+		zap.Uint64("size-before", before.TotalSize),
+		zap.Uint64("size-after", after.TotalSize),
+	)
+
+	if err := s.network.UpdateNodeInfo(); err != nil {
+		return nil, err
+	}
+	s.updateClusterNodeInfos()
+
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.PruneNodeDBResponse{
+		ClusterInfo: clusterInfo,
+		Before:      before,
+		After:       after,
+	}, nil
+}
+
+// Restarts the node [nodeName] with [cChainConfig] as C-Chain config.
+// Assumes [s.opMu] is held.
+func (s *server) restartWithCChainConfig(ctx context.Context, nodeName string, cChainConfig string) error {
+	return s.network.nw.RestartNode(
+		ctx,
+		nodeName,
+		"",
+		"",
+		"",
+		map[string]string{cChainAlias: cChainConfig},
+		nil,
+		nil,
+		false,
+	)
+}
+
+// Returns the sizes on disk of the db of [nd], and of its chain data dirs.
+// The db is shared by all the chains, so it can't be broken down by chain.
+func getNodeDBSizes(nd node.Node) (*rpcpb.NodeDBSizes, error) {
+	dbSize, err := utils.DirSize(nd.GetDbDir())
+	if err != nil {
+		return nil, err
+	}
+	sizes := &rpcpb.NodeDBSizes{
+		DbSize:         dbSize,
+		ChainDataSizes: map[string]uint64{},
+		TotalSize:      dbSize,
+	}
+
+	chainDataDir := filepath.Join(nd.GetDataDir(), defaultChainDataSubdir)
+	if dir, ok := nd.GetConfig().Flags[config.ChainDataDirKey].(string); ok && dir != "" {
+		chainDataDir = dir
+	}
+	entries, err := os.ReadDir(chainDataDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		size, err := utils.DirSize(filepath.Join(chainDataDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		sizes.ChainDataSizes[entry.Name()] = size
+		sizes.TotalSize += size
+	}
+	return sizes, nil
+}
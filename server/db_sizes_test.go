@@ -0,0 +1,60 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDBSizes(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, _ := newTestServer(t, 2)
+
+	resp, err := s.GetDBSizes(ctx, &rpcpb.GetDBSizesRequest{})
+	require.NoError(err)
+	require.Len(resp.NodeDbSizes, 2)
+
+	resp, err = s.GetDBSizes(ctx, &rpcpb.GetDBSizesRequest{NodeNames: []string{"node2"}})
+	require.NoError(err)
+	require.Len(resp.NodeDbSizes, 1)
+	require.Contains(resp.NodeDbSizes, "node2")
+
+	_, err = s.GetDBSizes(ctx, &rpcpb.GetDBSizesRequest{NodeNames: []string{"node3"}})
+	require.ErrorIs(err, network.ErrNodeNotFound)
+}
+
+func TestPruneNodeDBRestoresConfig(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, nw := newTestServer(t, 2)
+	cChainConfig := `{"log-level":"info"}`
+	require.NoError(nw.RestartNode(ctx, "node1", "", "", "", map[string]string{cChainAlias: cChainConfig}, nil, nil, false))
+
+	// the node is restarted with its config, even if pruning fails
+	errUnhealthy := errors.New("unhealthy")
+	nw.SetHealthy(errUnhealthy)
+	_, err := s.PruneNodeDB(ctx, &rpcpb.PruneNodeDBRequest{Name: "node1"})
+	require.ErrorIs(err, errUnhealthy)
+	nd, err := nw.GetNode("node1")
+	require.NoError(err)
+	require.Equal(cChainConfig, nd.GetConfig().ChainConfigFiles[cChainAlias])
+
+	nw.SetHealthy(nil)
+	resp, err := s.PruneNodeDB(ctx, &rpcpb.PruneNodeDBRequest{Name: "node1"})
+	require.NoError(err)
+	require.NotNil(resp.Before)
+	require.NotNil(resp.After)
+	nd, err = nw.GetNode("node1")
+	require.NoError(err)
+	require.Equal(cChainConfig, nd.GetConfig().ChainConfigFiles[cChainAlias])
+}
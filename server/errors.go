@@ -0,0 +1,173 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"syscall"
+
+	"github.com/luxdefi/netrunner/local"
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/netrunner/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var errorCodes = []struct {
+	code rpcpb.ErrorCode
+	errs []error
+}{
+	{rpcpb.ErrorCode_NOT_BOOTSTRAPPED, []error{ErrNotBootstrapped}},
+	{rpcpb.ErrorCode_ALREADY_BOOTSTRAPPED, []error{ErrAlreadyBootstrapped}},
+	{rpcpb.ErrorCode_NOT_FOUND, []error{
+		ErrNodeNotFound,
+		ErrPeerNotFound,
+		ErrNetworkNotFound,
+		ErrSubnetNotFound,
+		network.ErrNodeNotFound,
+		network.ErrElasticSubnetIDNotFound,
+		local.ErrSnapshotNotFound,
+		ErrKeyNotFound,
+	}},
+	{rpcpb.ErrorCode_PORT_IN_USE, []error{syscall.EADDRINUSE}},
+	{rpcpb.ErrorCode_BINARY_NOT_FOUND, []error{
+		utils.ErrNotExists,
+		utils.ErrNotExistsPlugin,
+		exec.ErrNotFound,
+	}},
+	// before UNHEALTHY_TIMEOUT, as txs can fail by timing out
+	{rpcpb.ErrorCode_TX_FAILED, []error{network.ErrTxFailed, network.ErrRemoteTxSigner}},
+	{rpcpb.ErrorCode_UNHEALTHY_TIMEOUT, []error{context.DeadlineExceeded}},
+	{rpcpb.ErrorCode_RATE_LIMITED, []error{ErrRateLimited, ErrTooManyHeavyOps}},
+	{rpcpb.ErrorCode_UNAUTHENTICATED, []error{ErrUnauthenticated}},
+	{rpcpb.ErrorCode_PERMISSION_DENIED, []error{ErrPermissionDenied}},
+	{rpcpb.ErrorCode_NODE_PAUSED, []error{network.ErrNodePaused}},
+	{rpcpb.ErrorCode_INVALID_ARGUMENT, []error{
+		ErrInvalidVMName,
+		ErrInvalidPort,
+		ErrNotEnoughNodesForStart,
+		ErrTooManyNodes,
+		ErrNoBlockchainSpec,
+		ErrNoSubnetID,
+		ErrNoElasticSubnetSpec,
+		ErrNoValidatorSpec,
+		ErrNetworkUpgradeAndSpec,
+		ErrNoBlockchain,
+		ErrNoAlias,
+		ErrNoTransferSpec,
+		ErrInvalidAssetSpec,
+		ErrNoAddress,
+		ErrZeroAmount,
+		ErrInvalidPublicIP,
+		ErrInvalidChain,
+		ErrInvalidGrep,
+		ErrInvalidTTL,
+		ErrInvalidDirLayout,
+		ErrInvalidExportFormat,
+		ErrNoWaitConditions,
+		ErrInvalidWaitConfig,
+		ErrUnreconcilableStart,
+		ErrNoAssertions,
+		ErrInvalidAssertion,
+		ErrInvalidChaosAction,
+		ErrChaosRunning,
+		ErrInvalidChaosConfig,
+		ErrChaosNotRunning,
+		ErrExternalNetworkChains,
+		ErrInvalidForkSpec,
+		ErrExternalNetworkState,
+		ErrExternalNetworkID,
+		ErrKeyExists,
+		ErrInvalidKeyName,
+		ErrInvalidTx,
+		ErrInvalidTxSignerSpec,
+		ErrInvalidKeysStorage,
+		ErrSnapshotPassphrase,
+		ErrInvalidNodeTimeout,
+		local.ErrSnapshotEncrypted,
+		utils.ErrDecryptStream,
+		ErrNoCChainStatePath,
+		ErrInvalidCChainStatePath,
+		network.ErrStandardForkNetworkID,
+		network.ErrReservedNetworkID,
+		ErrInvalidNodeGroup,
+		ErrValidationFailed,
+		ErrInvalidPeerBehavior,
+		ErrInvalidRecordingPath,
+		ErrInvalidRenewal,
+		ErrInvalidDuration,
+		network.ErrRenewalBelowQuorum,
+		network.ErrRenewalInProgress,
+		ErrInvalidPeerMessage,
+		ErrInvalidNodeState,
+		utils.ErrInvalidExecPath,
+		utils.ErrInvalidBlockchainSpec,
+		network.ErrEmptyUpgradeSpec,
+		network.ErrBackendNotFound,
+		network.ErrInvalidValidatorSpec,
+	}},
+}
+
+// Returns the error code describing the cause of [err].
+func getErrorCode(err error) rpcpb.ErrorCode {
+	for _, c := range errorCodes {
+		for _, e := range c.errs {
+			if errors.Is(err, e) {
+				return c.code
+			}
+		}
+	}
+	return rpcpb.ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+// Adds an ErrorInfo detail with the error code of [err] to the returned
+// status. The status message is kept as before, so clients matching on it
+// are not affected. The status code is DeadlineExceeded or Canceled for
+// errors caused by a context, and Unknown otherwise.
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	st, detailsErr := status.FromContextError(err).WithDetails(&rpcpb.ErrorInfo{Code: getErrorCode(err)})
+	if detailsErr != nil {
+		return err
+	}
+	return st.Err()
+}
+
+// Returns [err] as an InvalidArgument status, with an ErrorInfo detail
+// with the error code of [err].
+func invalidArgumentError(err error) error {
+	st, detailsErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(&rpcpb.ErrorInfo{Code: getErrorCode(err)})
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return st.Err()
+}
+
+func unaryErrorCodeInterceptor(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, toStatusError(err)
+}
+
+func streamErrorCodeInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	return toStatusError(handler(srv, ss))
+}
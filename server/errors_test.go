@@ -0,0 +1,97 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Wraps a wallet error while matching network.ErrTxFailed, as the
+// errors of issued txs do.
+type testTxError struct {
+	error
+}
+
+func (e testTxError) Unwrap() error {
+	return e.error
+}
+
+func (testTxError) Is(target error) bool {
+	return target == network.ErrTxFailed
+}
+
+func TestToStatusError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode codes.Code
+		expectedInfo rpcpb.ErrorCode
+	}{
+		{
+			name:         "unknown",
+			err:          errors.New("error"),
+			expectedCode: codes.Unknown,
+			expectedInfo: rpcpb.ErrorCode_ERROR_CODE_UNSPECIFIED,
+		},
+		{
+			name:         "port in use",
+			err:          fmt.Errorf("couldn't start node: %w", syscall.EADDRINUSE),
+			expectedCode: codes.Unknown,
+			expectedInfo: rpcpb.ErrorCode_PORT_IN_USE,
+		},
+		{
+			name:         "health timeout",
+			err:          fmt.Errorf("network never became healthy: %w", context.DeadlineExceeded),
+			expectedCode: codes.DeadlineExceeded,
+			expectedInfo: rpcpb.ErrorCode_UNHEALTHY_TIMEOUT,
+		},
+		{
+			name:         "tx timeout",
+			err:          testTxError{fmt.Errorf("couldn't issue tx: %w", context.DeadlineExceeded)},
+			expectedCode: codes.DeadlineExceeded,
+			expectedInfo: rpcpb.ErrorCode_TX_FAILED,
+		},
+		{
+			name:         "canceled",
+			err:          fmt.Errorf("couldn't create chains: %w", context.Canceled),
+			expectedCode: codes.Canceled,
+			expectedInfo: rpcpb.ErrorCode_ERROR_CODE_UNSPECIFIED,
+		},
+		{
+			name:         "not bootstrapped",
+			err:          ErrNotBootstrapped,
+			expectedCode: codes.Unknown,
+			expectedInfo: rpcpb.ErrorCode_NOT_BOOTSTRAPPED,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			st, ok := status.FromError(toStatusError(tt.err))
+			require.True(ok)
+			require.Equal(tt.expectedCode, st.Code())
+			require.Equal(tt.err.Error(), st.Message())
+			require.Len(st.Details(), 1)
+			info, ok := st.Details()[0].(*rpcpb.ErrorInfo)
+			require.True(ok)
+			require.Equal(tt.expectedInfo, info.Code)
+		})
+	}
+
+	require.NoError(t, toStatusError(nil))
+	// status errors are kept as they are
+	err := status.Error(codes.NotFound, "not found")
+	require.Equal(t, err, toStatusError(err))
+}
@@ -0,0 +1,220 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/config"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultComposeImage = "luxdefi/node:latest"
+	composeNetworkName  = "netrunner"
+	// subnet of the compose network. the nodes are given static IPs on it,
+	// as the bootstrap IPs can't be host names
+	composeSubnet = "172.30.0.0/16"
+	// offset of the node IPs on [composeSubnet], leaving room for the gateway
+	composeFirstNodeIP = 10
+)
+
+type composeFile struct {
+	Services map[string]composeService `yaml:"services"`
+	Networks map[string]composeNetwork `yaml:"networks"`
+}
+
+type composeService struct {
+	Image    string                           `yaml:"image"`
+	Command  []string                         `yaml:"command"`
+	Ports    []string                         `yaml:"ports"`
+	Volumes  []string                         `yaml:"volumes"`
+	Networks map[string]composeServiceNetwork `yaml:"networks"`
+}
+
+type composeServiceNetwork struct {
+	IPv4Address string `yaml:"ipv4_address"`
+}
+
+type composeNetwork struct {
+	IPAM composeIPAM `yaml:"ipam"`
+}
+
+type composeIPAM struct {
+	Config []composeIPAMConfig `yaml:"config"`
+}
+
+type composeIPAMConfig struct {
+	Subnet string `yaml:"subnet"`
+}
+
+// Renders the running network in the requested format, so the same
+// topology can be run outside the runner.
+func (s *server) ExportNetwork(
+	_ context.Context,
+	req *rpcpb.ExportNetworkRequest,
+) (*rpcpb.ExportNetworkResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("ExportNetwork", zap.String("format", req.GetFormat().String()))
+
+	var (
+		export string
+		err    error
+	)
+	switch req.GetFormat() {
+	case rpcpb.NetworkExportFormat_NETWORK_EXPORT_FORMAT_DOCKER_COMPOSE:
+		image := req.GetImage()
+		if image == "" {
+			image = defaultComposeImage
+		}
+		export, err = nw.exportDockerCompose(image)
+	case rpcpb.NetworkExportFormat_NETWORK_EXPORT_FORMAT_MANIFEST:
+		export, err = s.exportManifest()
+	default:
+		err = fmt.Errorf("%w: %s", ErrInvalidExportFormat, req.GetFormat())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.ExportNetworkResponse{Export: export}, nil
+}
+
+// Returns a docker compose file with a service per node, running [image]
+// with the flags the node was started with. The node dirs are mounted at
+// the same paths, so the flags pointing to them, and the node state, are
+// kept. The nodes get static IPs on a compose network, and their ports
+// are published on the host.
+// Assumes [lc.lock] isn't held.
+func (lc *localNetwork) exportDockerCompose(image string) (string, error) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+
+	nodes, err := lc.nw.GetAllNodes()
+	if err != nil {
+		return "", err
+	}
+	nodeNames := maps.Keys(nodes)
+	sort.Strings(nodeNames)
+
+	// maps from the P2P port of each node to its IP on the compose network,
+	// to translate the bootstrap IPs
+	p2pPortToIP := map[string]string{}
+	nodeIPs := map[string]string{}
+	for i, nodeName := range nodeNames {
+		n := composeFirstNodeIP + i
+		if n >= 0xffff {
+			return "", fmt.Errorf("too many nodes to export: %d", len(nodeNames))
+		}
+		ip := fmt.Sprintf("172.30.%d.%d", n>>8, n&0xff)
+		nodeIPs[nodeName] = ip
+		p2pPortToIP[strconv.Itoa(int(nodes[nodeName].GetP2PPort()))] = ip
+	}
+
+	compose := composeFile{
+		Services: map[string]composeService{},
+		Networks: map[string]composeNetwork{
+			composeNetworkName: {
+				IPAM: composeIPAM{Config: []composeIPAMConfig{{Subnet: composeSubnet}}},
+			},
+		},
+	}
+	for _, nodeName := range nodeNames {
+		node := nodes[nodeName]
+		ip := nodeIPs[nodeName]
+
+		flags := map[string]string{}
+		for _, arg := range node.GetArgs() {
+			k, v, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+			flags[k] = v
+		}
+		flags[config.HTTPHostKey] = "0.0.0.0"
+		flags[config.PublicIPKey] = ip
+		if bootstrapIPs := flags[config.BootstrapIPsKey]; bootstrapIPs != "" {
+			ips := strings.Split(bootstrapIPs, ",")
+			for i, bootstrapIP := range ips {
+				_, port, err := net.SplitHostPort(bootstrapIP)
+				if err != nil {
+					return "", fmt.Errorf("couldn't parse bootstrap IP %q of node %s: %w", bootstrapIP, nodeName, err)
+				}
+				// peers that aren't nodes of the network are kept as is
+				if peerIP, ok := p2pPortToIP[port]; ok {
+					ips[i] = net.JoinHostPort(peerIP, port)
+				}
+			}
+			flags[config.BootstrapIPsKey] = strings.Join(ips, ",")
+		}
+		command := make([]string, 0, len(flags))
+		for k, v := range flags {
+			command = append(command, fmt.Sprintf("--%s=%s", k, v))
+		}
+		sort.Strings(command)
+
+		volumes := []string{}
+		for _, dir := range getComposeMountDirs(node.GetDataDir(), node.GetDbDir(), node.GetLogsDir(), node.GetPluginDir()) {
+			volumes = append(volumes, dir+":"+dir)
+		}
+
+		compose.Services[nodeName] = composeService{
+			Image:   image,
+			Command: command,
+			Ports: []string{
+				fmt.Sprintf("%d:%d", node.GetAPIPort(), node.GetAPIPort()),
+				fmt.Sprintf("%d:%d", node.GetP2PPort(), node.GetP2PPort()),
+			},
+			Volumes: volumes,
+			Networks: map[string]composeServiceNetwork{
+				composeNetworkName: {IPv4Address: ip},
+			},
+		}
+	}
+
+	composeBytes, err := yaml.Marshal(compose)
+	if err != nil {
+		return "", err
+	}
+	return string(composeBytes), nil
+}
+
+// Returns the non empty [dirs], sorted and without the ones inside
+// another one of them, which are mounted with it.
+func getComposeMountDirs(dirs ...string) []string {
+	cleaned := []string{}
+	for _, dir := range dirs {
+		if dir != "" {
+			cleaned = append(cleaned, filepath.Clean(dir))
+		}
+	}
+	// shorter first, so the parents are added before their subdirs
+	sort.Slice(cleaned, func(i, j int) bool {
+		return len(cleaned[i]) < len(cleaned[j])
+	})
+	mountDirs := []string{}
+	for _, dir := range cleaned {
+		mounted := false
+		for _, mountDir := range mountDirs {
+			if dir == mountDir || strings.HasPrefix(dir, mountDir+string(filepath.Separator)) {
+				mounted = true
+				break
+			}
+		}
+		if !mounted {
+			mountDirs = append(mountDirs, dir)
+		}
+	}
+	sort.Strings(mountDirs)
+	return mountDirs
+}
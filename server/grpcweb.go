@@ -0,0 +1,69 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/luxdefi/netrunner/utils/constants"
+)
+
+// headers browsers may send on the gateway requests, besides the
+// CORS safelisted ones
+var corsAllowedHeaders = []string{
+	"Content-Type",
+	constants.AuthorizationMetadataKey,
+	"X-Grpc-Web",
+	"X-User-Agent",
+	"Grpc-Timeout",
+	constants.NetworkNameMetadataKey,
+}
+
+// Wraps the gateway [handler] so browsers can call the gRPC services
+// with grpc-web, if enabled, and so requests from the allowed origins
+// pass the CORS checks.
+func (s *server) withGRPCWebAndCORS(handler http.Handler) http.Handler {
+	var grpcWebServer *grpcweb.WrappedGrpcServer
+	if s.cfg.GRPCWebEnabled {
+		grpcWebServer = grpcweb.WrapServer(
+			s.gRPCServer,
+			grpcweb.WithOriginFunc(s.isAllowedOrigin),
+			grpcweb.WithAllowedRequestHeaders(corsAllowedHeaders),
+		)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if grpcWebServer != nil &&
+			(grpcWebServer.IsGrpcWebRequest(r) || grpcWebServer.IsAcceptableGrpcCorsRequest(r)) {
+			grpcWebServer.ServeHTTP(w, r)
+			return
+		}
+		origin := r.Header.Get("Origin")
+		if origin == "" || !s.isAllowedOrigin(origin) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			// preflight request
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(corsAllowedHeaders, ", "))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Returns true if browsers are allowed to call the server from [origin].
+func (s *server) isAllowedOrigin(origin string) bool {
+	for _, allowed := range s.cfg.CORSAllowedOrigins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
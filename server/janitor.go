@@ -0,0 +1,163 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luxdefi/netrunner/utils"
+	"github.com/luxdefi/netrunner/utils/constants"
+	"github.com/luxdefi/node/utils/set"
+	"go.uber.org/zap"
+)
+
+const (
+	// Time between the clean ups of the old network dirs.
+	dataDirJanitorInterval = 10 * time.Minute
+	// file with the PID of the server that created a network dir
+	networkDirOwnerFileName = "server.pid"
+)
+
+// A network dir left by a previous network.
+type networkDir struct {
+	path    string
+	modTime time.Time
+	size    uint64
+}
+
+// Periodically removes the dirs of old networks, so their dbs and logs
+// don't build up. The dirs older than [s.cfg.DataDirMaxAge] are removed,
+// and then the oldest ones until their total size is under
+// [s.cfg.DataDirMaxSize]. The dir of the running network is never removed,
+// nor the dirs of other running servers sharing the temp dir (see
+// isRemovableNetworkDir).
+// Returns when the server is closed.
+func (s *server) runDataDirJanitor() {
+	s.log.Info("cleaning up old network dirs",
+		zap.Uint64("max-size", s.cfg.DataDirMaxSize),
+		zap.Duration("max-age", s.cfg.DataDirMaxAge),
+	)
+	// root data dirs the networks have been created at
+	rootDataDirs := set.Set[string]{}
+	rootDataDirs.Add(filepath.Join(os.TempDir(), constants.RootDirPrefix))
+
+	ticker := time.NewTicker(dataDirJanitorInterval)
+	defer ticker.Stop()
+	for {
+		var runningDir string
+		s.mu.RLock()
+		if s.network != nil && s.clusterInfo != nil {
+			runningDir = s.clusterInfo.RootDataDir
+		}
+		s.mu.RUnlock()
+		if runningDir != "" {
+			rootDataDirs.Add(filepath.Dir(runningDir))
+		}
+
+		for rootDataDir := range rootDataDirs {
+			s.cleanNetworkDirs(rootDataDir, runningDir)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.rootCtx.Done():
+			return
+		}
+	}
+}
+
+// Removes the network dirs at [rootDataDir] exceeding the max age or
+// total size, except for [runningDir].
+func (s *server) cleanNetworkDirs(rootDataDir string, runningDir string) {
+	entries, err := os.ReadDir(rootDataDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.log.Warn("couldn't read root data dir", zap.String("dir", rootDataDir), zap.Error(err))
+		}
+		return
+	}
+
+	dirs := []networkDir{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), networkRootDirPrefix) {
+			continue
+		}
+		path := filepath.Join(rootDataDir, entry.Name())
+		if path == runningDir || !isRemovableNetworkDir(path) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		size, err := utils.DirSize(path)
+		if err != nil {
+			s.log.Warn("couldn't get network dir size", zap.String("dir", path), zap.Error(err))
+			continue
+		}
+		dirs = append(dirs, networkDir{
+			path:    path,
+			modTime: info.ModTime(),
+			size:    size,
+		})
+	}
+	// oldest first
+	sort.Slice(dirs, func(i, j int) bool {
+		return dirs[i].modTime.Before(dirs[j].modTime)
+	})
+
+	var totalSize uint64
+	for _, dir := range dirs {
+		totalSize += dir.size
+	}
+	for _, dir := range dirs {
+		expired := s.cfg.DataDirMaxAge > 0 && time.Since(dir.modTime) > s.cfg.DataDirMaxAge
+		oversized := s.cfg.DataDirMaxSize > 0 && totalSize > s.cfg.DataDirMaxSize
+		if !expired && !oversized {
+			continue
+		}
+		if err := os.RemoveAll(dir.path); err != nil {
+			s.log.Warn("couldn't remove old network dir", zap.String("dir", dir.path), zap.Error(err))
+			continue
+		}
+		s.log.Info("removed old network dir",
+			zap.String("dir", dir.path),
+			zap.Uint64("size", dir.size),
+			zap.Time("modified", dir.modTime),
+		)
+		totalSize -= dir.size
+	}
+}
+
+// Records this server as the owner of the network dir [dir].
+func markNetworkDir(dir string) error {
+	return os.WriteFile(filepath.Join(dir, networkDirOwnerFileName), []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644)
+}
+
+// Returns true if the network dir [dir] was created by this server, or by
+// a server that isn't running anymore. Dirs without owner, as the ones of
+// networks run through the local package, are left alone.
+func isRemovableNetworkDir(dir string) bool {
+	pidBytes, err := os.ReadFile(filepath.Join(dir, networkDirOwnerFileName))
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(pidBytes)))
+	if err != nil || pid <= 0 {
+		return false
+	}
+	if pid == os.Getpid() {
+		return true
+	}
+	// signal 0 only checks the process exists
+	err = syscall.Kill(pid, 0)
+	return err != nil && !errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanNetworkDirs(t *testing.T) {
+	require := require.New(t)
+
+	rootDataDir := t.TempDir()
+	newDir := func(name string, owner string) string {
+		dir := filepath.Join(rootDataDir, name)
+		require.NoError(os.MkdirAll(dir, 0o750))
+		if owner != "" {
+			require.NoError(os.WriteFile(filepath.Join(dir, networkDirOwnerFileName), []byte(owner), 0o644))
+		}
+		old := time.Now().Add(-time.Hour)
+		require.NoError(os.Chtimes(dir, old, old))
+		return dir
+	}
+	ownDir := newDir("network_own", strconv.Itoa(os.Getpid()))
+	// no process has the max PID
+	orphanedDir := newDir("network_orphaned", strconv.Itoa(math.MaxInt32))
+	// PID 1 always runs
+	otherServerDir := newDir("network_other", "1")
+	unownedDir := newDir("network_unowned", "")
+	invalidOwnerDir := newDir("network_invalid", "not a pid")
+	runningDir := newDir("network_running", strconv.Itoa(os.Getpid()))
+	otherDir := newDir("other", strconv.Itoa(os.Getpid()))
+
+	s := &server{
+		log: logging.NoLog{},
+		cfg: Config{DataDirMaxAge: time.Minute},
+	}
+	s.cleanNetworkDirs(rootDataDir, runningDir)
+
+	for _, dir := range []string{ownDir, orphanedDir} {
+		_, err := os.Stat(dir)
+		require.ErrorIs(err, os.ErrNotExist)
+	}
+	for _, dir := range []string{otherServerDir, unownedDir, invalidOwnerDir, runningDir, otherDir} {
+		_, err := os.Stat(dir)
+		require.NoError(err)
+	}
+}
+
+func TestMarkNetworkDir(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	require.False(isRemovableNetworkDir(dir))
+	require.NoError(markNetworkDir(dir))
+	require.True(isRemovableNetworkDir(dir))
+}
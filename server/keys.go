@@ -0,0 +1,290 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/luxdefi/coreth/core/types"
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/genesis"
+	"github.com/luxdefi/node/ids"
+	luxd_constants "github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/crypto/secp256k1"
+	"github.com/luxdefi/node/utils/formatting/address"
+	"github.com/luxdefi/node/utils/set"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// name of the key funded by the default genesis
+	fundedKeyName = "ewoq"
+	// prefix of the signed messages, so a message signature can't be
+	// used as the signature of a tx
+	signedMessagePrefix = "\x1ALux Signed Message:\n"
+)
+
+// Keys of a network, by name. They have their own lock, so they can be
+// used while a network operation holds [lc.lock].
+type keyStore struct {
+	lock sync.Mutex
+	keys map[string]*secp256k1.PrivateKey
+}
+
+func newKeyStore() *keyStore {
+	return &keyStore{
+		keys: map[string]*secp256k1.PrivateKey{
+			fundedKeyName: genesis.EWOQKey,
+		},
+	}
+}
+
+// Returns the names of the keys, sorted.
+func (ks *keyStore) names() []string {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	names := maps.Keys(ks.keys)
+	sort.Strings(names)
+	return names
+}
+
+func (ks *keyStore) get(name string) (*secp256k1.PrivateKey, error) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	key, ok := ks.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, name)
+	}
+	return key, nil
+}
+
+// Creates a new random key named [name].
+func (ks *keyStore) create(name string) (*secp256k1.PrivateKey, error) {
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	if _, ok := ks.keys[name]; ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyExists, name)
+	}
+	factory := secp256k1.Factory{}
+	key, err := factory.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	ks.keys[name] = key
+	return key, nil
+}
+
+// Returns the addresses of [key], with the P-Chain and X-Chain ones
+// formatted with [hrp]. The key is funded if its address is one of
+// [fundedAddrs].
+func newKeyInfo(name string, key *secp256k1.PrivateKey, hrp string, fundedAddrs set.Set[ids.ShortID]) (*rpcpb.KeyInfo, error) {
+	addr := key.PublicKey().Address()
+	pChainAddr, err := address.Format("P", hrp, addr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	xChainAddr, err := address.Format("X", hrp, addr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.KeyInfo{
+		Name:          name,
+		Address:       addr.String(),
+		PChainAddress: pChainAddr,
+		XChainAddress: xChainAddr,
+		EthAddress:    ethcrypto.PubkeyToAddress(key.ToECDSA().PublicKey).Hex(),
+		Funded:        fundedAddrs.Contains(addr),
+	}, nil
+}
+
+// Returns the HRP of the addresses of the running network.
+func (s *server) getNetworkHRP() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.clusterInfo == nil || s.clusterInfo.Hrp == "" {
+		return luxd_constants.FallbackHRP
+	}
+	return s.clusterInfo.Hrp
+}
+
+// Returns the addresses funded by the genesis of the network. The ones
+// of a network without genesis, e.g. an external one, are not known.
+func (lc *localNetwork) getGenesisFundedAddrs() (set.Set[ids.ShortID], error) {
+	genesis, err := lc.nw.GetGenesis()
+	if err != nil {
+		return nil, err
+	}
+	if len(genesis) == 0 {
+		return set.Set[ids.ShortID]{}, nil
+	}
+	return network.GetGenesisAllocatedAddrs(genesis)
+}
+
+// Returns the bytes signed for [msg]: the message prefixed with
+// [signedMessagePrefix] and its length.
+func getSignedMessageBytes(msg []byte) []byte {
+	b := make([]byte, 0, len(signedMessagePrefix)+4+len(msg))
+	b = append(b, signedMessagePrefix...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(msg)))
+	return append(b, msg...)
+}
+
+// Returns the key store of the running network.
+func (s *server) getKeyStore() (*keyStore, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+	return nw.keys, nil
+}
+
+func (s *server) ListKeys(
+	_ context.Context,
+	_ *rpcpb.ListKeysRequest,
+) (*rpcpb.ListKeysResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+	fundedAddrs, err := nw.getGenesisFundedAddrs()
+	if err != nil {
+		return nil, err
+	}
+	hrp := s.getNetworkHRP()
+	ks := nw.keys
+	resp := &rpcpb.ListKeysResponse{}
+	for _, name := range ks.names() {
+		key, err := ks.get(name)
+		if err != nil {
+			return nil, err
+		}
+		keyInfo, err := newKeyInfo(name, key, hrp, fundedAddrs)
+		if err != nil {
+			return nil, err
+		}
+		resp.Keys = append(resp.Keys, keyInfo)
+	}
+	return resp, nil
+}
+
+func (s *server) CreateKey(
+	_ context.Context,
+	req *rpcpb.CreateKeyRequest,
+) (*rpcpb.CreateKeyResponse, error) {
+	if req.GetName() == "" {
+		return nil, ErrInvalidKeyName
+	}
+	ks, err := s.getKeyStore()
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Debug("CreateKey", zap.String("name", req.GetName()))
+
+	key, err := ks.create(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	// a new key has no funds
+	keyInfo, err := newKeyInfo(req.GetName(), key, s.getNetworkHRP(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.CreateKeyResponse{Key: keyInfo}, nil
+}
+
+func (s *server) ExportKey(
+	_ context.Context,
+	req *rpcpb.ExportKeyRequest,
+) (*rpcpb.ExportKeyResponse, error) {
+	ks, err := s.getKeyStore()
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.get(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.ExportKeyResponse{
+		PrivateKey:    key.String(),
+		PrivateKeyHex: hex.EncodeToString(key.Bytes()),
+	}, nil
+}
+
+// Signs the message prefixed with [signedMessagePrefix] and its length,
+// so the signature can't be used for anything but the message.
+func (s *server) SignMessage(
+	_ context.Context,
+	req *rpcpb.SignMessageRequest,
+) (*rpcpb.SignMessageResponse, error) {
+	ks, err := s.getKeyStore()
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.get(req.GetKeyName())
+	if err != nil {
+		return nil, err
+	}
+	signature, err := key.Sign(getSignedMessageBytes(req.GetMessage()))
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.SignMessageResponse{Signature: signature}, nil
+}
+
+// Signs a P-Chain or X-Chain tx by returning the signature to put in its
+// credentials, as the server can't tell which inputs are owned by the key,
+// or signs an EVM tx returning it signed.
+func (s *server) SignTx(
+	_ context.Context,
+	req *rpcpb.SignTxRequest,
+) (*rpcpb.SignTxResponse, error) {
+	if len(req.GetUnsignedTx()) == 0 {
+		return nil, fmt.Errorf("%w: unsigned tx is empty", ErrInvalidTx)
+	}
+	ks, err := s.getKeyStore()
+	if err != nil {
+		return nil, err
+	}
+	key, err := ks.get(req.GetKeyName())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetEvmChainId() == 0 {
+		signature, err := key.Sign(req.GetUnsignedTx())
+		if err != nil {
+			return nil, err
+		}
+		return &rpcpb.SignTxResponse{Signature: signature}, nil
+	}
+
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(req.GetUnsignedTx()); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTx, err)
+	}
+	signer := types.LatestSignerForChainID(new(big.Int).SetUint64(req.GetEvmChainId()))
+	signedTx, err := types.SignTx(tx, signer, key.ToECDSA())
+	if err != nil {
+		return nil, err
+	}
+	signedTxBytes, err := signedTx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.SignTxResponse{SignedTx: signedTxBytes}, nil
+}
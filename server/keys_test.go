@@ -0,0 +1,74 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/genesis"
+	luxd_constants "github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/formatting/address"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListKeysFunded(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, nw := newTestServer(t, 1)
+	s.network.keys = newKeyStore()
+	_, err := s.CreateKey(ctx, &rpcpb.CreateKeyRequest{Name: "alice"})
+	require.NoError(err)
+	alice, err := s.network.keys.get("alice")
+	require.NoError(err)
+
+	getFunded := func() map[string]bool {
+		resp, err := s.ListKeys(ctx, &rpcpb.ListKeysRequest{})
+		require.NoError(err)
+		funded := map[string]bool{}
+		for _, keyInfo := range resp.Keys {
+			funded[keyInfo.Name] = keyInfo.Funded
+		}
+		return funded
+	}
+
+	// the allocations of a network without genesis are not known
+	require.Equal(map[string]bool{"alice": false, fundedKeyName: false}, getFunded())
+
+	// only the allocated addresses are funded, whatever the key name
+	aliceAddr, err := address.Format("X", luxd_constants.FallbackHRP, alice.PublicKey().Address().Bytes())
+	require.NoError(err)
+	nw.SetGenesis([]byte(fmt.Sprintf(`{"allocations":[{"luxAddr":%q}]}`, aliceAddr)))
+	require.Equal(map[string]bool{"alice": true, fundedKeyName: false}, getFunded())
+
+	ewoqAddr, err := address.Format("X", luxd_constants.FallbackHRP, genesis.EWOQKey.PublicKey().Address().Bytes())
+	require.NoError(err)
+	nw.SetGenesis([]byte(fmt.Sprintf(`{"allocations":[{"luxAddr":%q},{"luxAddr":%q}]}`, aliceAddr, ewoqAddr)))
+	require.Equal(map[string]bool{"alice": true, fundedKeyName: true}, getFunded())
+}
+
+func TestSignMessage(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, _ := newTestServer(t, 1)
+	s.network.keys = newKeyStore()
+	key, err := s.network.keys.get(fundedKeyName)
+	require.NoError(err)
+
+	msg := []byte("hello")
+	resp, err := s.SignMessage(ctx, &rpcpb.SignMessageRequest{KeyName: fundedKeyName, Message: msg})
+	require.NoError(err)
+
+	// the prefixed message is signed, not the raw bytes
+	require.True(key.PublicKey().Verify(getSignedMessageBytes(msg), resp.Signature))
+	require.False(key.PublicKey().Verify(msg, resp.Signature))
+	require.Equal(append([]byte("\x1ALux Signed Message:\n\x00\x00\x00\x05"), msg...), getSignedMessageBytes(msg))
+
+	_, err = s.SignMessage(ctx, &rpcpb.SignMessageRequest{KeyName: "alice", Message: msg})
+	require.ErrorIs(err, ErrKeyNotFound)
+}
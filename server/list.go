@@ -0,0 +1,149 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// page size of the list RPCs, if not given
+	defaultPageSize = 100
+	// max page size of the list RPCs
+	maxPageSize = 1000
+)
+
+var (
+	ErrInvalidNodeState = errors.New("invalid node state")
+	ErrSubnetNotFound   = errors.New("subnet not found")
+)
+
+// Returns a page of nodes of the network, sorted by name.
+func (s *server) ListNodes(_ context.Context, req *rpcpb.ListNodesRequest) (*rpcpb.ListNodesResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.log.Debug("ListNodes",
+		zap.String("subnet-ID", req.SubnetId),
+		zap.String("state", req.State.String()),
+		zap.String("page-token", req.PageToken),
+	)
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	var subnetNodes map[string]bool
+	if req.SubnetId != "" {
+		subnetInfo, ok := s.clusterInfo.Subnets[req.SubnetId]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrSubnetNotFound, req.SubnetId)
+		}
+		subnetNodes = map[string]bool{}
+		for _, nodeName := range subnetInfo.GetSubnetParticipants().GetNodeNames() {
+			subnetNodes[nodeName] = true
+		}
+	}
+
+	nodeNames := []string{}
+	for nodeName, nodeInfo := range s.clusterInfo.NodeInfos {
+		if subnetNodes != nil && !subnetNodes[nodeName] {
+			continue
+		}
+		matches, err := nodeStateMatches(nodeInfo, req.State)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			nodeNames = append(nodeNames, nodeName)
+		}
+	}
+
+	page, nextPageToken := paginate(nodeNames, req.PageSize, req.PageToken)
+	nodeInfos := make([]*rpcpb.NodeInfo, 0, len(page))
+	for _, nodeName := range page {
+		nodeInfos = append(nodeInfos, proto.Clone(s.clusterInfo.NodeInfos[nodeName]).(*rpcpb.NodeInfo))
+	}
+	return &rpcpb.ListNodesResponse{
+		NodeInfos:     nodeInfos,
+		NextPageToken: nextPageToken,
+		TotalSize:     uint32(len(nodeNames)),
+	}, nil
+}
+
+// Returns a page of custom chains of the network, sorted by chain ID.
+func (s *server) ListCustomChains(_ context.Context, req *rpcpb.ListCustomChainsRequest) (*rpcpb.ListCustomChainsResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.log.Debug("ListCustomChains",
+		zap.String("subnet-ID", req.SubnetId),
+		zap.String("page-token", req.PageToken),
+	)
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	chainIDs := []string{}
+	for chainID, chainInfo := range s.clusterInfo.CustomChains {
+		if req.SubnetId == "" || chainInfo.SubnetId == req.SubnetId {
+			chainIDs = append(chainIDs, chainID)
+		}
+	}
+
+	page, nextPageToken := paginate(chainIDs, req.PageSize, req.PageToken)
+	customChains := make([]*rpcpb.CustomChainInfo, 0, len(page))
+	for _, chainID := range page {
+		customChains = append(customChains, proto.Clone(s.clusterInfo.CustomChains[chainID]).(*rpcpb.CustomChainInfo))
+	}
+	return &rpcpb.ListCustomChainsResponse{
+		CustomChains:  customChains,
+		NextPageToken: nextPageToken,
+		TotalSize:     uint32(len(chainIDs)),
+	}, nil
+}
+
+// Returns true if [nodeInfo] is in [state].
+func nodeStateMatches(nodeInfo *rpcpb.NodeInfo, state rpcpb.NodeState) (bool, error) {
+	switch state {
+	case rpcpb.NodeState_NODE_STATE_UNSPECIFIED:
+		return true, nil
+	case rpcpb.NodeState_NODE_STATE_RUNNING:
+		return !nodeInfo.Paused, nil
+	case rpcpb.NodeState_NODE_STATE_PAUSED:
+		return nodeInfo.Paused, nil
+	default:
+		return false, fmt.Errorf("%w: %d", ErrInvalidNodeState, state)
+	}
+}
+
+// Sorts [keys] and returns the page of up to [pageSize] keys following
+// [pageToken], along with the token of the next page, empty if it is
+// the last one. The token of a page is the last key of the previous one.
+func paginate(keys []string, pageSize uint32, pageToken string) ([]string, string) {
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	sort.Strings(keys)
+	start := 0
+	if pageToken != "" {
+		start = sort.Search(len(keys), func(i int) bool { return keys[i] > pageToken })
+	}
+	end := start + int(pageSize)
+	if end >= len(keys) {
+		return keys[start:], ""
+	}
+	return keys[start:end], keys[end-1]
+}
@@ -0,0 +1,377 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luxdefi/node/utils/set"
+	"go.uber.org/zap"
+)
+
+const (
+	// time between the scans of the node logs dirs for new log files
+	logForwardScanInterval = 2 * time.Second
+	lokiPushPath           = "/loki/api/v1/push"
+	lokiPushTimeout        = 10 * time.Second
+	syslogTag              = "netrunner"
+	// syslog address used to log to the local syslog daemon
+	localSyslogAddr = "local"
+)
+
+// suffix added to the name of the rotated node log files, e.g.
+// main-2024-01-02T15-04-05.000.log, compressed or not
+const rotatedLogFnameSuffix = `-\d{4}-\d{2}-\d{2}T[\d.-]+\.log(\.gz)?$`
+
+// rotated node log files, which are not forwarded as their lines were
+// forwarded from the current log
+var rotatedLogFnameRe = regexp.MustCompile(rotatedLogFnameSuffix)
+
+// Where a forwarded log line comes from.
+type logLabels struct {
+	Network string `json:"network"`
+	Node    string `json:"node"`
+	// name of the log file without extension, "main" for the node main log
+	Chain string `json:"chain"`
+}
+
+// Destination of the forwarded node log lines.
+type logSink interface {
+	send(labels logLabels, lines []string) error
+	close() error
+}
+
+// Returns the sinks given in [cfg]. Returns no sinks if log forwarding
+// is disabled.
+func newLogSinks(cfg Config) ([]logSink, error) {
+	sinks := []logSink{}
+	if cfg.LogForwardLokiURL != "" {
+		sinks = append(sinks, newLokiSink(cfg.LogForwardLokiURL))
+	}
+	if cfg.LogForwardSyslogAddr != "" {
+		sink, err := newSyslogSink(cfg.LogForwardSyslogAddr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't connect to syslog: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.LogForwardFile != "" {
+		sink, err := newFileSink(cfg.LogForwardFile)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't open log forward file: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// Forwards the log files of the nodes of the running network to [s.logSinks],
+// following the nodes added and the log files created. Returns when the
+// server is closed.
+func (s *server) runLogForwarder() {
+	var (
+		forwarded *localNetwork
+		// cancelled when [forwarded] is no longer the running network
+		ctx    context.Context
+		cancel context.CancelFunc = func() {}
+		// log files being forwarded
+		paths = set.Set[string]{}
+		wg    sync.WaitGroup
+	)
+	defer func() {
+		cancel()
+		wg.Wait()
+		for _, sink := range s.logSinks {
+			if err := sink.close(); err != nil {
+				s.log.Warn("couldn't close log sink", zap.Error(err))
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(logForwardScanInterval)
+	defer ticker.Stop()
+	for {
+		nodesLogsDir := map[string]string{}
+		s.mu.RLock()
+		nw, networkName := s.network, s.networkName
+		if nw != nil && s.clusterInfo != nil {
+			for nodeName, nodeInfo := range s.clusterInfo.NodeInfos {
+				nodesLogsDir[nodeName] = nodeInfo.LogDir
+			}
+		}
+		s.mu.RUnlock()
+
+		if nw != forwarded {
+			cancel()
+			forwarded = nw
+			paths = set.Set[string]{}
+			ctx, cancel = context.WithCancel(s.rootCtx)
+		}
+		for nodeName, logsDir := range nodesLogsDir {
+			labels := logLabels{Network: networkName, Node: nodeName}
+			s.forwardNewLogFiles(ctx, &wg, paths, labels, logsDir)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.rootCtx.Done():
+			return
+		}
+	}
+}
+
+// Starts forwarding the log files at [logsDir] not in [paths] yet.
+func (s *server) forwardNewLogFiles(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	paths set.Set[string],
+	labels logLabels,
+	logsDir string,
+) {
+	entries, err := os.ReadDir(logsDir)
+	if err != nil {
+		// the node may not have created its logs dir yet
+		return
+	}
+	for _, entry := range entries {
+		fname := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fname, ".log") || rotatedLogFnameRe.MatchString(fname) {
+			continue
+		}
+		path := filepath.Join(logsDir, fname)
+		if paths.Contains(path) {
+			continue
+		}
+		paths.Add(path)
+		fileLabels := labels
+		fileLabels.Chain = strings.TrimSuffix(fname, ".log")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.forwardLogFile(ctx, fileLabels, path)
+		}()
+	}
+}
+
+// Sends the lines of the log file at [path] to the log sinks, from its
+// start and then as they are written, until [ctx] is done. When the file
+// is rotated, the new file at [path] is forwarded.
+func (s *server) forwardLogFile(ctx context.Context, labels logLabels, path string) {
+	send := func(lines []string) error {
+		for _, sink := range s.logSinks {
+			if err := sink.send(labels, lines); err != nil {
+				// the lines are dropped, so a sink down doesn't block the others
+				s.log.Debug("couldn't forward node log lines", zap.String("node", labels.Node), zap.Error(err))
+			}
+		}
+		return nil
+	}
+	for ctx.Err() == nil {
+		f, err := os.Open(path)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(logForwardScanInterval):
+			}
+			continue
+		}
+		fileCtx, cancel := context.WithCancel(ctx)
+		go cancelOnRotation(fileCtx, cancel, f, path)
+		err = tailLogLines(fileCtx, f, true, &logFilter{}, send)
+		cancel()
+		if err == nil && ctx.Err() == nil {
+			// the lines written before the rotation
+			err = tailLogLines(ctx, f, false, &logFilter{}, send)
+		}
+		if err != nil {
+			s.log.Debug("couldn't read node log", zap.String("path", path), zap.Error(err))
+		}
+		_ = f.Close()
+	}
+}
+
+// Calls [cancel] once [path] no longer refers to [f], e.g. because
+// the log was rotated, or when [ctx] is done.
+func cancelOnRotation(ctx context.Context, cancel context.CancelFunc, f *os.File, path string) {
+	ticker := time.NewTicker(logForwardScanInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		fInfo, err := f.Stat()
+		if err != nil {
+			cancel()
+			return
+		}
+		pathInfo, err := os.Stat(path)
+		if err == nil && !os.SameFile(fInfo, pathInfo) {
+			cancel()
+			return
+		}
+	}
+}
+
+// Appends the lines to a file, as JSON objects with their labels.
+type fileSink struct {
+	lock sync.Mutex
+	file *os.File
+}
+
+// Entry of the log forward file, written as a JSON line for each log line.
+type forwardedLogLine struct {
+	Time time.Time `json:"time"`
+	logLabels
+	Line string `json:"line"`
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (fs *fileSink) send(labels logLabels, lines []string) error {
+	now := time.Now()
+	buf := bytes.Buffer{}
+	for _, line := range lines {
+		b, err := json.Marshal(forwardedLogLine{
+			Time:      getLogLineTime(line, now),
+			logLabels: labels,
+			Line:      line,
+		})
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		buf.WriteByte('\n')
+	}
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	_, err := fs.file.Write(buf.Bytes())
+	return err
+}
+
+func (fs *fileSink) close() error {
+	return fs.file.Close()
+}
+
+// Pushes the lines to a Loki server, as a stream labeled with the node
+// and chain they come from.
+type lokiSink struct {
+	pushURL string
+	client  *http.Client
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	// pairs of timestamp in nanoseconds and line
+	Values [][2]string `json:"values"`
+}
+
+func newLokiSink(lokiURL string) *lokiSink {
+	return &lokiSink{
+		pushURL: strings.TrimSuffix(lokiURL, "/") + lokiPushPath,
+		client:  &http.Client{Timeout: lokiPushTimeout},
+	}
+}
+
+func (ls *lokiSink) send(labels logLabels, lines []string) error {
+	now := time.Now()
+	stream := lokiStream{
+		Stream: map[string]string{
+			"job":     syslogTag,
+			"network": labels.Network,
+			"node":    labels.Node,
+			"chain":   labels.Chain,
+		},
+		Values: make([][2]string, len(lines)),
+	}
+	for i, line := range lines {
+		ts := getLogLineTime(line, now).UnixNano()
+		stream.Values[i] = [2]string{strconv.FormatInt(ts, 10), line}
+	}
+	body, err := json.Marshal(lokiPushRequest{Streams: []lokiStream{stream}})
+	if err != nil {
+		return err
+	}
+	resp, err := ls.client.Post(ls.pushURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("loki push failed with status %s", resp.Status)
+	}
+	return nil
+}
+
+func (*lokiSink) close() error {
+	return nil
+}
+
+// Sends the lines to syslog, prefixed with the node and chain they come from.
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+// [addr] is either "local", for the local syslog daemon, or a
+// network address such as udp://host:514.
+func newSyslogSink(addr string) (*syslogSink, error) {
+	network, raddr := "", ""
+	if addr != localSyslogAddr {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return nil, err
+		}
+		network, raddr = u.Scheme, u.Host
+	}
+	writer, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_DAEMON, syslogTag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{writer: writer}, nil
+}
+
+func (ss *syslogSink) send(labels logLabels, lines []string) error {
+	for _, line := range lines {
+		if err := ss.writer.Info(fmt.Sprintf("%s/%s/%s: %s", labels.Network, labels.Node, labels.Chain, line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ss *syslogSink) close() error {
+	return ss.writer.Close()
+}
+
+// Returns the time [line] was logged at, or [now] if it has no timestamp.
+func getLogLineTime(line string, now time.Time) time.Time {
+	if t, ok := parseNodeLogTime(line, now); ok {
+		return t
+	}
+	return now
+}
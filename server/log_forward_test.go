@@ -0,0 +1,268 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// time given to the forwarder to pick up the log changes, which it
+// checks every [logForwardScanInterval]
+const logForwardTestTimeout = 5 * logForwardScanInterval
+
+var errTestSinkDown = errors.New("sink down")
+
+// Records the lines sent, failing the first [failures] sends.
+type testLogSink struct {
+	lock     sync.Mutex
+	lines    map[logLabels][]string
+	failures int
+	closed   bool
+}
+
+func newTestLogSink() *testLogSink {
+	return &testLogSink{lines: map[logLabels][]string{}}
+}
+
+func (ts *testLogSink) send(labels logLabels, lines []string) error {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.failures > 0 {
+		ts.failures--
+		return errTestSinkDown
+	}
+	ts.lines[labels] = append(ts.lines[labels], lines...)
+	return nil
+}
+
+func (ts *testLogSink) close() error {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	ts.closed = true
+	return nil
+}
+
+func (ts *testLogSink) getLines(labels logLabels) []string {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	return append([]string{}, ts.lines[labels]...)
+}
+
+func (ts *testLogSink) isClosed() bool {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	return ts.closed
+}
+
+func appendLogLines(t *testing.T, path string, lines ...string) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	for _, line := range lines {
+		_, err = f.WriteString(line + "\n")
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+}
+
+func TestRunLogForwarder(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newTestServer(t, 1)
+	s.rootCtx, s.rootCancel = context.WithCancel(context.Background())
+	sink := newTestLogSink()
+	s.logSinks = []logSink{sink}
+	logsDir := t.TempDir()
+	s.clusterInfo.NodeInfos["node1"].LogDir = logsDir
+	mainLabels := logLabels{Network: s.networkName, Node: "node1", Chain: "main"}
+	cLabels := logLabels{Network: s.networkName, Node: "node1", Chain: "C"}
+
+	appendLogLines(t, filepath.Join(logsDir, "main.log"), "line 1", "line 2")
+	// not forwarded, as its lines were forwarded from the current log
+	appendLogLines(t, filepath.Join(logsDir, "main-2024-01-02T15-04-05.000.log"), "rotated line")
+
+	done := make(chan struct{})
+	go func() {
+		s.runLogForwarder()
+		close(done)
+	}()
+
+	require.Eventually(func() bool {
+		return len(sink.getLines(mainLabels)) == 2
+	}, logForwardTestTimeout, 10*time.Millisecond)
+	require.Equal([]string{"line 1", "line 2"}, sink.getLines(mainLabels))
+
+	// the lines written afterwards, and the log files created, are forwarded
+	appendLogLines(t, filepath.Join(logsDir, "main.log"), "line 3")
+	appendLogLines(t, filepath.Join(logsDir, "C.log"), "chain line")
+	require.Eventually(func() bool {
+		return len(sink.getLines(mainLabels)) == 3 && len(sink.getLines(cLabels)) == 1
+	}, logForwardTestTimeout, 10*time.Millisecond)
+	require.Equal([]string{"chain line"}, sink.getLines(cLabels))
+
+	// the sinks are closed once the server is
+	s.rootCancel()
+	select {
+	case <-done:
+	case <-time.After(logForwardTestTimeout):
+		require.FailNow("log forwarder didn't return")
+	}
+	require.True(sink.isClosed())
+}
+
+func TestForwardLogFileRotation(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newTestServer(t, 1)
+	sink := newTestLogSink()
+	s.logSinks = []logSink{sink}
+	labels := logLabels{Node: "node1", Chain: "main"}
+	path := filepath.Join(t.TempDir(), "main.log")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.forwardLogFile(ctx, labels, path)
+		close(done)
+	}()
+
+	// the file is forwarded once created
+	appendLogLines(t, path, "line 1")
+	require.Eventually(func() bool {
+		return len(sink.getLines(labels)) == 1
+	}, logForwardTestTimeout, 10*time.Millisecond)
+
+	// the new file is forwarded once rotated, after the lines written
+	// to the old one before the rotation
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(err)
+	require.NoError(os.Rename(path, filepath.Join(filepath.Dir(path), "main-2024-01-02T15-04-05.000.log")))
+	_, err = f.WriteString("line 2\n")
+	require.NoError(err)
+	require.NoError(f.Close())
+	appendLogLines(t, path, "line 3")
+	require.Eventually(func() bool {
+		return len(sink.getLines(labels)) == 3
+	}, logForwardTestTimeout, 10*time.Millisecond)
+	require.Equal([]string{"line 1", "line 2", "line 3"}, sink.getLines(labels))
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(logForwardTestTimeout):
+		require.FailNow("log file forwarding didn't return")
+	}
+}
+
+func TestForwardLogFileSinkDown(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newTestServer(t, 1)
+	sink := newTestLogSink()
+	sink.failures = 1
+	s.logSinks = []logSink{sink}
+	labels := logLabels{Node: "node1", Chain: "main"}
+	path := filepath.Join(t.TempDir(), "main.log")
+	appendLogLines(t, path, "dropped line")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.forwardLogFile(ctx, labels, path)
+
+	// the lines sent while the sink is down are dropped, and the
+	// following ones forwarded once it is back
+	require.Eventually(func() bool {
+		sink.lock.Lock()
+		defer sink.lock.Unlock()
+		return sink.failures == 0
+	}, logForwardTestTimeout, 10*time.Millisecond)
+	appendLogLines(t, path, "line")
+	require.Eventually(func() bool {
+		return len(sink.getLines(labels)) == 1
+	}, logForwardTestTimeout, 10*time.Millisecond)
+	require.Equal([]string{"line"}, sink.getLines(labels))
+}
+
+func TestLokiSink(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		lock     sync.Mutex
+		requests []lokiPushRequest
+		status   = http.StatusNoContent
+	)
+	lokiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req lokiPushRequest
+		body, err := io.ReadAll(r.Body)
+		if r.URL.Path != lokiPushPath || err != nil || json.Unmarshal(body, &req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		requests = append(requests, req)
+		w.WriteHeader(status)
+	}))
+	defer lokiServer.Close()
+
+	sink := newLokiSink(lokiServer.URL + "/")
+	labels := logLabels{Network: "default", Node: "node1", Chain: "C"}
+	require.NoError(sink.send(labels, []string{"[01-02|15:04:05.000] INFO line"}))
+	require.Len(requests, 1)
+	stream := requests[0].Streams[0]
+	require.Equal(map[string]string{"job": syslogTag, "network": "default", "node": "node1", "chain": "C"}, stream.Stream)
+	require.Len(stream.Values, 1)
+	require.Equal("[01-02|15:04:05.000] INFO line", stream.Values[0][1])
+
+	lock.Lock()
+	status = http.StatusServiceUnavailable
+	lock.Unlock()
+	require.Error(sink.send(labels, []string{"line"}))
+	require.NoError(sink.close())
+}
+
+func TestFileSink(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "forwarded.log")
+	sink, err := newFileSink(path)
+	require.NoError(err)
+	labels := logLabels{Network: "default", Node: "node1", Chain: "main"}
+	require.NoError(sink.send(labels, []string{"line 1", "line 2"}))
+	require.NoError(sink.close())
+
+	f, err := os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lines := []forwardedLogLine{}
+	for scanner.Scan() {
+		var line forwardedLogLine
+		require.NoError(json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(scanner.Err())
+	require.Len(lines, 2)
+	for i, line := range lines {
+		require.Equal(labels, line.logLabels)
+		require.Equal([]string{"line 1", "line 2"}[i], line.Line)
+	}
+}
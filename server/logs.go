@@ -0,0 +1,333 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// max number of lines sent on each stream message
+	maxLogLinesPerMessage = 256
+	// timestamp prefix of the node log lines, e.g. "[10-16|12:34:56.789]"
+	nodeLogTimeLayout = "[01-02|15:04:05.000]"
+	// max number of lines returned by a log search, if not given
+	defaultMaxLogMatches = 1000
+)
+
+// Streams the log of a node, or of one of its chains, filtered by
+// the given regular expression and start time.
+func (s *server) StreamLogs(req *rpcpb.StreamLogsRequest, stream rpcpb.ControlService_StreamLogsServer) error {
+	s.log.Debug("StreamLogs",
+		zap.String("node-name", req.NodeName),
+		zap.String("chain", req.Chain),
+		zap.Bool("follow", req.Follow),
+	)
+
+	logFname, err := getLogFname(req.Chain)
+	if err != nil {
+		return err
+	}
+	filter, err := newLogFilter(req.Grep, req.Since)
+	if err != nil {
+		return err
+	}
+	logsDir, err := s.getNodeLogsDir(getRequestNetworkName(stream.Context()), req.NodeName)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(filepath.Join(logsDir, logFname))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.rootCtx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return tailLogLines(ctx, f, req.Follow, filter, func(lines []string) error {
+		return stream.Send(&rpcpb.StreamLogsResponse{Lines: lines})
+	})
+}
+
+// Sends the lines of [r] accepted by [filter] to [send]. If [follow] is
+// true, keeps polling [r] for new lines until [ctx] is done.
+func tailLogLines(
+	ctx context.Context,
+	r io.Reader,
+	follow bool,
+	filter *logFilter,
+	send func([]string) error,
+) error {
+	reader := bufio.NewReader(r)
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+
+	// line being written when the end of the log was reached
+	partial := ""
+	for {
+		lines := []string{}
+		var err error
+		for len(lines) < maxLogLinesPerMessage {
+			var line string
+			line, err = reader.ReadString('\n')
+			if err != nil {
+				partial += line
+				break
+			}
+			line = strings.TrimRight(partial+line, "\r\n")
+			partial = ""
+			if filter.accept(line) {
+				lines = append(lines, line)
+			}
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if err != nil && !follow && partial != "" && filter.accept(partial) {
+			lines = append(lines, partial)
+		}
+		if len(lines) > 0 {
+			if err := send(lines); err != nil {
+				return err
+			}
+		}
+		if err == nil {
+			// there is more to read
+			continue
+		}
+		if !follow {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Returns the lines of the log of a node, or of every node, or of one
+// of their chains, matching the given regular expression, with the
+// time they were logged at.
+func (s *server) SearchLogs(ctx context.Context, req *rpcpb.SearchLogsRequest) (*rpcpb.SearchLogsResponse, error) {
+	s.log.Debug("SearchLogs",
+		zap.String("node-name", req.NodeName),
+		zap.String("chain", req.Chain),
+		zap.String("pattern", req.Pattern),
+	)
+
+	logFname, err := getLogFname(req.Chain)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := newLogFilter(req.Pattern, req.Since)
+	if err != nil {
+		return nil, err
+	}
+	maxMatches := int(req.MaxMatches)
+	if maxMatches == 0 {
+		maxMatches = defaultMaxLogMatches
+	}
+	nodesLogsDir, err := s.getNodesLogsDir(getRequestNetworkName(ctx), req.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	nodeNames := maps.Keys(nodesLogsDir)
+	sort.Strings(nodeNames)
+
+	resp := &rpcpb.SearchLogsResponse{}
+	for _, nodeName := range nodeNames {
+		paths, err := getLogFilePaths(nodesLogsDir[nodeName], logFname)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			matches, truncated, err := searchLogFile(ctx, path, filter, maxMatches-len(resp.Matches))
+			if err != nil {
+				// when searching every node, skip the ones without the chain log
+				if req.NodeName == "" && errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return nil, err
+			}
+			for _, match := range matches {
+				match.NodeName = nodeName
+				match.Chain = req.Chain
+			}
+			resp.Matches = append(resp.Matches, matches...)
+			if truncated {
+				resp.Truncated = true
+				return resp, nil
+			}
+		}
+	}
+	return resp, nil
+}
+
+// Returns the paths of the rotated files of the log [logFname] at
+// [logsDir], oldest first, followed by the path of the current one.
+func getLogFilePaths(logsDir string, logFname string) ([]string, error) {
+	entries, err := os.ReadDir(logsDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	rotatedRe := regexp.MustCompile("^" + regexp.QuoteMeta(strings.TrimSuffix(logFname, ".log")) + rotatedLogFnameSuffix)
+	paths := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && rotatedRe.MatchString(entry.Name()) {
+			paths = append(paths, filepath.Join(logsDir, entry.Name()))
+		}
+	}
+	// the rotation time in the names sorts them in the order they were written
+	sort.Strings(paths)
+	return append(paths, filepath.Join(logsDir, logFname)), nil
+}
+
+// Returns the lines of the log file at [path], decompressed if rotated
+// with compression, accepted by [filter], up to [maxMatches] of them, and
+// whether there were more.
+func searchLogFile(
+	ctx context.Context,
+	path string,
+	filter *logFilter,
+	maxMatches int,
+) ([]*rpcpb.LogMatch, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, false, fmt.Errorf("couldn't decompress %s: %w", path, err)
+		}
+		defer gzipReader.Close()
+		r = gzipReader
+	}
+
+	now := time.Now()
+	matches := []*rpcpb.LogMatch{}
+	// time of the last timestamped line, which the lines that follow
+	// it without a timestamp were logged at too
+	var lineTime int64
+	reader := bufio.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, false, err
+		}
+		if line != "" {
+			line = strings.TrimRight(line, "\r\n")
+			if t, ok := parseNodeLogTime(line, now); ok {
+				lineTime = t.UnixNano()
+			}
+			if filter.accept(line) {
+				if len(matches) == maxMatches {
+					return matches, true, nil
+				}
+				matches = append(matches, &rpcpb.LogMatch{
+					Timestamp: lineTime,
+					Line:      line,
+				})
+			}
+		}
+		if err != nil {
+			return matches, false, nil
+		}
+	}
+}
+
+// Returns the name of the log file of [chain], or of the node main
+// log if empty.
+func getLogFname(chain string) (string, error) {
+	if chain == "" {
+		return nodeMainLogFname, nil
+	}
+	if chain != filepath.Base(chain) || chain == "." || chain == ".." {
+		return "", fmt.Errorf("%w: %q", ErrInvalidChain, chain)
+	}
+	return chain + ".log", nil
+}
+
+type logFilter struct {
+	grep  *regexp.Regexp
+	since time.Time
+	// true while the last timestamped line was logged before [since], so
+	// the lines that follow it without a timestamp are skipped too
+	skipping bool
+}
+
+func newLogFilter(grep string, since int64) (*logFilter, error) {
+	filter := &logFilter{}
+	if grep != "" {
+		re, err := regexp.Compile(grep)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidGrep, err)
+		}
+		filter.grep = re
+	}
+	if since != 0 {
+		filter.since = time.Unix(0, since)
+		filter.skipping = true
+	}
+	return filter, nil
+}
+
+func (f *logFilter) accept(line string) bool {
+	if !f.since.IsZero() {
+		if t, ok := parseNodeLogTime(line, time.Now()); ok {
+			f.skipping = t.Before(f.since)
+		}
+		if f.skipping {
+			return false
+		}
+	}
+	return f.grep == nil || f.grep.MatchString(line)
+}
+
+// Returns the time a node log line was logged at. The line timestamp
+// has no year, so the one making it closest before [now] is used.
+func parseNodeLogTime(line string, now time.Time) (time.Time, bool) {
+	if len(line) < len(nodeLogTimeLayout) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(nodeLogTimeLayout, line[:len(nodeLogTimeLayout)], now.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+	t = t.AddDate(now.Year(), 0, 0)
+	// allow for some clock skew before assuming the line is from last year
+	if t.After(now.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t, true
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGzipFile(t *testing.T, path string, content string) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	w := gzip.NewWriter(f)
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, f.Close())
+}
+
+func TestGetLogFilePaths(t *testing.T) {
+	require := require.New(t)
+
+	logsDir := t.TempDir()
+	for _, fname := range []string{
+		"main.log",
+		"main-2024-01-03T10-00-00.000.log.gz",
+		"main-2024-01-02T10-00-00.000.log",
+		"C.log",
+		"C-2024-01-01T10-00-00.000.log.gz",
+		"mainnet-2024-01-01T10-00-00.000.log",
+	} {
+		require.NoError(os.WriteFile(filepath.Join(logsDir, fname), nil, 0o644))
+	}
+
+	paths, err := getLogFilePaths(logsDir, "main.log")
+	require.NoError(err)
+	require.Equal([]string{
+		filepath.Join(logsDir, "main-2024-01-02T10-00-00.000.log"),
+		filepath.Join(logsDir, "main-2024-01-03T10-00-00.000.log.gz"),
+		filepath.Join(logsDir, "main.log"),
+	}, paths)
+
+	// the current log is searched even if it doesn't exist yet
+	paths, err = getLogFilePaths(filepath.Join(logsDir, "missing"), "main.log")
+	require.NoError(err)
+	require.Equal([]string{filepath.Join(logsDir, "missing", "main.log")}, paths)
+}
+
+func TestSearchLogsRotated(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, _ := newTestServer(t, 2)
+	logsDir := t.TempDir()
+	s.clusterInfo.NodeInfos["node1"].LogDir = logsDir
+	s.clusterInfo.NodeInfos["node2"].LogDir = t.TempDir()
+
+	writeGzipFile(t, filepath.Join(logsDir, "main-2024-01-02T10-00-00.000.log.gz"), "[01-02|10:00:00.000] INFO match 1\n[01-02|10:00:01.000] INFO other\n")
+	require.NoError(os.WriteFile(filepath.Join(logsDir, "main-2024-01-03T10-00-00.000.log"), []byte("[01-03|10:00:00.000] INFO match 2\n"), 0o644))
+	require.NoError(os.WriteFile(filepath.Join(logsDir, "main.log"), []byte("[01-04|10:00:00.000] INFO match 3\n"), 0o644))
+
+	resp, err := s.SearchLogs(ctx, &rpcpb.SearchLogsRequest{Pattern: "match"})
+	require.NoError(err)
+	lines := []string{}
+	for _, match := range resp.Matches {
+		require.Equal("node1", match.NodeName)
+		lines = append(lines, match.Line)
+	}
+	require.Equal([]string{
+		"[01-02|10:00:00.000] INFO match 1",
+		"[01-03|10:00:00.000] INFO match 2",
+		"[01-04|10:00:00.000] INFO match 3",
+	}, lines)
+	require.False(resp.Truncated)
+
+	resp, err = s.SearchLogs(ctx, &rpcpb.SearchLogsRequest{NodeName: "node1", Pattern: "match", MaxMatches: 2})
+	require.NoError(err)
+	require.Len(resp.Matches, 2)
+	require.True(resp.Truncated)
+
+	// a node without log is only skipped when searching every node
+	_, err = s.SearchLogs(ctx, &rpcpb.SearchLogsRequest{NodeName: "node2", Pattern: "match"})
+	require.ErrorIs(err, os.ErrNotExist)
+}
@@ -0,0 +1,280 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/genesis"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// version of the manifest format, increased on breaking changes
+	manifestVersion = 1
+	// name of the manifest file written in the root data dir of the network
+	manifestFname = "manifest.json"
+	// extension of the file created next to each manifest while the
+	// network is healthy
+	readyFileExt = ".ready"
+)
+
+// Endpoints of the running network, written as JSON so other frameworks
+// (e.g. Kurtosis packages, devcontainers) can find the nodes and chains
+// without calling the server. Fields are only added on minor changes.
+type networkManifest struct {
+	Version     int                  `json:"version"`
+	NetworkName string               `json:"networkName"`
+	NetworkID   uint32               `json:"networkId"`
+	HRP         string               `json:"hrp"`
+	RootDataDir string               `json:"rootDataDir"`
+	Healthy     bool                 `json:"healthy"`
+	Nodes       []manifestNode       `json:"nodes"`
+	Subnets     []manifestSubnet     `json:"subnets"`
+	Blockchains []manifestBlockchain `json:"blockchains"`
+	// key used by the server to issue the subnet and blockchain
+	// transactions: the tx signer of the network, or the default genesis
+	// key if it is allocated funds in the genesis. Omitted if there is
+	// none, e.g. for an external network without tx signer.
+	FundedKey *manifestKey `json:"fundedKey,omitempty"`
+}
+
+type manifestKey struct {
+	// CB58 encoded, e.g. PrivateKey-ewoq... Omitted for a tx signer, as
+	// its private key is not known
+	PrivateKey string `json:"privateKey,omitempty"`
+	// hex encoded, as used by the EVM tools
+	PrivateKeyHex string `json:"privateKeyHex,omitempty"`
+	// short address ID, to be formatted with the chain and network HRP
+	Address string `json:"address"`
+}
+
+type manifestNode struct {
+	Name    string `json:"name"`
+	NodeID  string `json:"nodeId"`
+	URI     string `json:"uri"`
+	APIPort uint32 `json:"apiPort"`
+	P2PPort uint32 `json:"p2pPort"`
+	Paused  bool   `json:"paused"`
+	DataDir string `json:"dataDir"`
+	LogsDir string `json:"logsDir"`
+}
+
+type manifestSubnet struct {
+	SubnetID  string   `json:"subnetId"`
+	IsElastic bool     `json:"isElastic"`
+	NodeNames []string `json:"nodeNames"`
+}
+
+type manifestBlockchain struct {
+	ChainID   string   `json:"chainId"`
+	ChainName string   `json:"chainName"`
+	VMID      string   `json:"vmId"`
+	SubnetID  string   `json:"subnetId"`
+	Aliases   []string `json:"aliases"`
+	// maps from the name of each node running the chain to the base URL
+	// of the chain API on the node
+	Endpoints map[string]string `json:"endpoints"`
+}
+
+// Returns the key funding the txs issued by [lc]: the address of its tx
+// signer, or the default genesis key if it is allocated funds in the
+// genesis. Returns nil if there is none.
+// Assumes [lc.lock] is held.
+func (lc *localNetwork) getManifestFundedKey() (*manifestKey, error) {
+	if lc.options.txSigner != nil {
+		return &manifestKey{Address: lc.options.txSigner.Address().String()}, nil
+	}
+	fundedAddrs, err := lc.getGenesisFundedAddrs()
+	if err != nil {
+		return nil, err
+	}
+	addr := genesis.EWOQKey.PublicKey().Address()
+	if !fundedAddrs.Contains(addr) {
+		return nil, nil
+	}
+	return &manifestKey{
+		PrivateKey:    genesis.EWOQKey.String(),
+		PrivateKeyHex: hex.EncodeToString(genesis.EWOQKey.Bytes()),
+		Address:       addr.String(),
+	}, nil
+}
+
+// Returns the manifest of the network described by [clusterInfo] and
+// funded by [fundedKey], with every list sorted so it only changes when
+// the network does.
+func newNetworkManifest(clusterInfo *rpcpb.ClusterInfo, fundedKey *manifestKey) *networkManifest {
+	manifest := &networkManifest{
+		Version:     manifestVersion,
+		NetworkName: clusterInfo.NetworkName,
+		NetworkID:   clusterInfo.NetworkId,
+		HRP:         clusterInfo.Hrp,
+		RootDataDir: clusterInfo.RootDataDir,
+		Healthy:     clusterInfo.Healthy && clusterInfo.CustomChainsHealthy,
+		Nodes:       []manifestNode{},
+		Subnets:     []manifestSubnet{},
+		Blockchains: []manifestBlockchain{},
+		FundedKey:   fundedKey,
+	}
+
+	nodeNames := maps.Keys(clusterInfo.NodeInfos)
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		nodeInfo := clusterInfo.NodeInfos[nodeName]
+		manifest.Nodes = append(manifest.Nodes, manifestNode{
+			Name:    nodeName,
+			NodeID:  nodeInfo.Id,
+			URI:     nodeInfo.Uri,
+			APIPort: nodeInfo.ApiPort,
+			P2PPort: nodeInfo.P2PPort,
+			Paused:  nodeInfo.Paused,
+			DataDir: nodeInfo.DataDir,
+			LogsDir: nodeInfo.LogDir,
+		})
+	}
+
+	subnetIDs := maps.Keys(clusterInfo.Subnets)
+	sort.Strings(subnetIDs)
+	for _, subnetID := range subnetIDs {
+		subnetInfo := clusterInfo.Subnets[subnetID]
+		participants := append([]string{}, subnetInfo.GetSubnetParticipants().GetNodeNames()...)
+		sort.Strings(participants)
+		manifest.Subnets = append(manifest.Subnets, manifestSubnet{
+			SubnetID:  subnetID,
+			IsElastic: subnetInfo.IsElastic,
+			NodeNames: participants,
+		})
+	}
+
+	chainIDs := maps.Keys(clusterInfo.CustomChains)
+	sort.Strings(chainIDs)
+	for _, chainID := range chainIDs {
+		chainInfo := clusterInfo.CustomChains[chainID]
+		// only the participants of the subnet run the chain
+		chainNodeNames := nodeNames
+		if subnetInfo, ok := clusterInfo.Subnets[chainInfo.SubnetId]; ok {
+			chainNodeNames = subnetInfo.GetSubnetParticipants().GetNodeNames()
+		}
+		endpoints := map[string]string{}
+		for _, nodeName := range chainNodeNames {
+			if nodeInfo, ok := clusterInfo.NodeInfos[nodeName]; ok {
+				endpoints[nodeName] = nodeInfo.Uri + "/ext/bc/" + chainID
+			}
+		}
+		manifest.Blockchains = append(manifest.Blockchains, manifestBlockchain{
+			ChainID:   chainID,
+			ChainName: chainInfo.ChainName,
+			VMID:      chainInfo.VmId,
+			SubnetID:  chainInfo.SubnetId,
+			Aliases:   append([]string{}, chainInfo.Aliases...),
+			Endpoints: endpoints,
+		})
+	}
+	return manifest
+}
+
+// Returns the manifest of the running network.
+func (s *server) exportManifest() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.clusterInfo == nil {
+		return "", ErrNotBootstrapped
+	}
+	manifestBytes, err := json.MarshalIndent(newNetworkManifest(s.clusterInfo, s.fundedKey), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(manifestBytes), nil
+}
+
+// Writes the manifest of the network to its root data dir, and to
+// [s.cfg.ManifestPath] if set. Next to each one, a ready file is created
+// while the network is healthy, and removed otherwise, so scripts can
+// wait for it.
+// Assumes [s.mu] is held.
+func (s *server) writeManifest() {
+	if s.clusterInfo == nil {
+		return
+	}
+	manifest := newNetworkManifest(s.clusterInfo, s.fundedKey)
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		s.log.Warn("couldn't marshal network manifest", zap.Error(err))
+		return
+	}
+	paths := []string{}
+	if s.clusterInfo.RootDataDir != "" {
+		paths = append(paths, filepath.Join(s.clusterInfo.RootDataDir, manifestFname))
+	}
+	if s.cfg.ManifestPath != "" {
+		paths = append(paths, s.cfg.ManifestPath)
+	}
+	for _, path := range paths {
+		if err := writeFileAtomically(path, manifestBytes); err != nil {
+			s.log.Warn("couldn't write network manifest", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		readyPath := getReadyFilePath(path)
+		if manifest.Healthy {
+			err = os.WriteFile(readyPath, []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0o644)
+		} else {
+			err = removeIfExists(readyPath)
+		}
+		if err != nil {
+			s.log.Warn("couldn't update network ready file", zap.String("path", readyPath), zap.Error(err))
+		}
+	}
+}
+
+// Removes the manifest at [s.cfg.ManifestPath], and its ready file, once
+// there is no network running. The ones in the root data dir are kept
+// with the network, marked as not ready.
+// Assumes [s.mu] is held.
+func (s *server) removeManifest() {
+	s.writeManifest()
+	if s.cfg.ManifestPath == "" {
+		return
+	}
+	for _, path := range []string{s.cfg.ManifestPath, getReadyFilePath(s.cfg.ManifestPath)} {
+		if err := removeIfExists(path); err != nil {
+			s.log.Warn("couldn't remove network manifest", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
+
+// Returns the path of the ready file of the manifest at [manifestPath],
+// e.g. manifest.ready for manifest.json.
+func getReadyFilePath(manifestPath string) string {
+	return strings.TrimSuffix(manifestPath, filepath.Ext(manifestPath)) + readyFileExt
+}
+
+func removeIfExists(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Writes [b] to [path] through a temporary file, so readers never see
+// a partially written file.
+func writeFileAtomically(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/genesis"
+	"github.com/luxdefi/node/ids"
+	luxd_constants "github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/formatting/address"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkManifestEndpoints(t *testing.T) {
+	require := require.New(t)
+
+	clusterInfo := &rpcpb.ClusterInfo{
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {Name: "node1", Uri: "http://127.0.0.1:9650"},
+			"node2": {Name: "node2", Uri: "http://127.0.0.1:9652"},
+			"node3": {Name: "node3", Uri: "http://127.0.0.1:9654"},
+		},
+		Subnets: map[string]*rpcpb.SubnetInfo{
+			"subnet1": {SubnetParticipants: &rpcpb.SubnetParticipants{NodeNames: []string{"node1", "node3"}}},
+		},
+		CustomChains: map[string]*rpcpb.CustomChainInfo{
+			"chain1": {ChainName: "subnetevm", SubnetId: "subnet1"},
+		},
+	}
+	manifest := newNetworkManifest(clusterInfo, nil)
+	require.Len(manifest.Nodes, 3)
+	require.Len(manifest.Blockchains, 1)
+	require.Equal(map[string]string{
+		"node1": "http://127.0.0.1:9650/ext/bc/chain1",
+		"node3": "http://127.0.0.1:9654/ext/bc/chain1",
+	}, manifest.Blockchains[0].Endpoints)
+}
+
+func TestWriteManifest(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newTestServer(t, 1)
+	rootDataDir := t.TempDir()
+	s.clusterInfo.RootDataDir = rootDataDir
+	s.clusterInfo.Healthy = true
+	s.clusterInfo.CustomChainsHealthy = true
+
+	// only written under the network root data dir by default
+	s.writeManifest()
+	require.FileExists(filepath.Join(rootDataDir, manifestFname))
+	require.FileExists(filepath.Join(rootDataDir, "manifest"+readyFileExt))
+
+	s.cfg.ManifestPath = filepath.Join(t.TempDir(), "netrunner.json")
+	s.writeManifest()
+	require.FileExists(s.cfg.ManifestPath)
+	require.FileExists(getReadyFilePath(s.cfg.ManifestPath))
+
+	s.clusterInfo.Healthy = false
+	s.removeManifest()
+	require.NoFileExists(s.cfg.ManifestPath)
+	_, err := os.Stat(filepath.Join(rootDataDir, "manifest"+readyFileExt))
+	require.ErrorIs(err, os.ErrNotExist)
+	require.FileExists(filepath.Join(rootDataDir, manifestFname))
+}
+
+func TestGetManifestFundedKey(t *testing.T) {
+	require := require.New(t)
+
+	s, nw := newTestServer(t, 1)
+
+	// the default genesis key is only reported if it is funded
+	fundedKey, err := s.network.getManifestFundedKey()
+	require.NoError(err)
+	require.Nil(fundedKey)
+
+	ewoqAddr, err := address.Format("X", luxd_constants.FallbackHRP, genesis.EWOQKey.PublicKey().Address().Bytes())
+	require.NoError(err)
+	nw.SetGenesis([]byte(fmt.Sprintf(`{"allocations":[{"luxAddr":%q}]}`, ewoqAddr)))
+	fundedKey, err = s.network.getManifestFundedKey()
+	require.NoError(err)
+	require.Equal(&manifestKey{
+		PrivateKey:    genesis.EWOQKey.String(),
+		PrivateKeyHex: hex.EncodeToString(genesis.EWOQKey.Bytes()),
+		Address:       genesis.EWOQKey.PublicKey().Address().String(),
+	}, fundedKey)
+
+	// only the address of a tx signer is known
+	signerAddr := ids.GenerateTestShortID()
+	s.network.options.txSigner = network.NewRemoteTxSigner("http://127.0.0.1:1", signerAddr)
+	fundedKey, err = s.network.getManifestFundedKey()
+	require.NoError(err)
+	require.Equal(&manifestKey{Address: signerAddr.String()}, fundedKey)
+
+	s.updateClusterInfo()
+	require.Equal(fundedKey, newNetworkManifest(s.clusterInfo, s.fundedKey).FundedKey)
+}
@@ -6,27 +6,37 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/luxdefi/netrunner/local"
 	"github.com/luxdefi/netrunner/network"
 	"github.com/luxdefi/netrunner/network/node"
 	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/netrunner/utils"
 	"github.com/luxdefi/netrunner/utils/constants"
 	"github.com/luxdefi/netrunner/ux"
 	"github.com/luxdefi/node/config"
 	"github.com/luxdefi/node/ids"
 	luxd_constants "github.com/luxdefi/node/utils/constants"
 	"github.com/luxdefi/node/utils/logging"
+	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 )
 
 const (
+	// time given to resume a node paused to simulate its downtime, once
+	// the request is canceled
+	downtimeResumeTimeout = time.Minute
+
 	prometheusConfFname  = "prometheus.yaml"
 	prometheusConfCommon = `global:
   scrape_interval: 15s
@@ -49,6 +59,8 @@ scrape_configs:
 `
 )
 
+var errNoRunningNode = errors.New("no running node found")
+
 type localNetwork struct {
 	lock sync.Mutex
 	log  logging.Logger
@@ -62,6 +74,9 @@ type localNetwork struct {
 
 	nodeInfos map[string]*rpcpb.NodeInfo
 
+	// ID of the network, as given to the nodes
+	networkID uint32
+
 	options localNetworkOptions
 
 	// map from blockchain ID to blockchain info
@@ -73,6 +88,12 @@ type localNetwork struct {
 
 	subnets map[string]*rpcpb.SubnetInfo
 
+	// primary network uptimes, by node name
+	uptimes map[string]*rpcpb.NodeUptime
+
+	// keys used by the clients to sign txs
+	keys *keyStore
+
 	prometheusConfPath string
 }
 
@@ -107,6 +128,63 @@ type localNetworkOptions struct {
 	reassignPortsIfUsed bool
 
 	dynamicPorts bool
+
+	// staking parameters used when adding validators
+	stakingSpec network.StakingSpec
+
+	// interfaces and addresses used by all nodes
+	networkAccess networkAccess
+
+	// range of the ports assigned to nodes
+	portRange network.PortRange
+
+	// number of nodes per logs shard dir. no sharding if zero
+	logsShardSize int
+
+	// templates of the node dirs, if not under the root data dir
+	dirLayout network.DirLayout
+
+	// if not zero, the randomness of the network derives from it
+	seed int64
+
+	// if not zero, the network ID set in the genesis
+	networkID uint32
+
+	// signs the txs issued by the network, instead of the funded key
+	txSigner network.TxSigner
+
+	// how the staking keys are given to the nodes, see network.Config
+	stakingKeysStorage string
+
+	// creates the network
+	backend network.Backend
+
+	// existing network joined by the nodes, and its genesis
+	externalNetwork *network.ExternalNetworkSpec
+	externalGenesis string
+
+	// remote C-Chain state copied into the genesis
+	fork *network.ForkSpec
+	// file of C-Chain accounts added to the genesis
+	cChainStatePath string
+
+	// called with the progress of the long network operations
+	onProgress network.ProgressHandler
+}
+
+type networkAccess struct {
+	// interface the node APIs are bound to
+	httpHost string
+	// IP advertised to peers
+	publicIP string
+}
+
+// Returns the backend registered as [name], or the local one if [name] is empty.
+func getNetworkBackend(name string) (network.Backend, error) {
+	if name == "" {
+		name = local.BackendName
+	}
+	return network.GetBackend(name)
 }
 
 func newLocalNetwork(opts localNetworkOptions) (*localNetwork, error) {
@@ -131,6 +209,7 @@ func newLocalNetwork(opts localNetworkOptions) (*localNetwork, error) {
 		stopCh:              make(chan struct{}),
 		nodeInfos:           make(map[string]*rpcpb.NodeInfo),
 		subnets:             make(map[string]*rpcpb.SubnetInfo),
+		keys:                newKeyStore(),
 	}, nil
 }
 
@@ -154,11 +233,23 @@ func (lc *localNetwork) createConfig() error {
 	for k, v := range globalConfig {
 		cfg.Flags[k] = v
 	}
+	lc.setNetworkAccessFlags(cfg.Flags, globalConfig)
 
 	if lc.pluginDir != "" {
 		cfg.Flags[config.PluginDirKey] = lc.pluginDir
 	}
 
+	cfg.StakingSpec = lc.options.stakingSpec
+	cfg.PortRange = lc.options.portRange
+	cfg.LogsShardSize = lc.options.logsShardSize
+	cfg.DirLayout = lc.options.dirLayout
+	cfg.Seed = lc.options.seed
+	cfg.StakingKeysStorage = lc.options.stakingKeysStorage
+	if lc.options.externalNetwork != nil {
+		cfg.ExternalNetwork = lc.options.externalNetwork
+		cfg.Genesis = lc.options.externalGenesis
+	}
+
 	for i := range cfg.NodeConfigs {
 		// NOTE: Naming convention for node names is currently `node` + number, i.e. `node1,node2,node3,...node101`
 		nodeName := fmt.Sprintf("node%d", i+1)
@@ -189,6 +280,15 @@ func (lc *localNetwork) createConfig() error {
 			cfg.NodeConfigs[i].Flags[config.TrackSubnetsKey] = lc.options.trackSubnets
 		}
 
+		if lc.options.externalNetwork != nil {
+			// the default staking keys are shared by all local networks, so
+			// nodes joining an external network get their own, generated
+			// when the node is added
+			cfg.NodeConfigs[i].StakingCert = ""
+			cfg.NodeConfigs[i].StakingKey = ""
+			cfg.NodeConfigs[i].StakingSigningKey = ""
+		}
+
 		cfg.NodeConfigs[i].BinaryPath = lc.execPath
 		cfg.NodeConfigs[i].RedirectStdout = lc.options.redirectNodesOutput
 		cfg.NodeConfigs[i].RedirectStderr = lc.options.redirectNodesOutput
@@ -203,12 +303,69 @@ func (lc *localNetwork) createConfig() error {
 		for k, v := range customNodeConfig {
 			cfg.NodeConfigs[i].Flags[k] = v
 		}
+		for _, k := range []string{config.HTTPHostKey, config.PublicIPKey} {
+			if _, ok := customNodeConfig[k]; ok {
+				lc.log.Warn("custom node config overrides network level access flag",
+					zap.String("node-name", nodeName),
+					zap.String("flag", k),
+				)
+			}
+		}
 	}
 
 	lc.cfg = cfg
 	return nil
 }
 
+// Sets the http-host and public-ip flags from [lc.options.networkAccess]
+// into the network level [flags], and warns about combinations that leave
+// the network partially unreachable from other hosts.
+// Assumes [lc.lock] is held.
+func (lc *localNetwork) setNetworkAccessFlags(flags map[string]interface{}, globalConfig map[string]interface{}) {
+	access := lc.options.networkAccess
+	for k, v := range map[string]string{
+		config.HTTPHostKey: access.httpHost,
+		config.PublicIPKey: access.publicIP,
+	} {
+		if v == "" {
+			continue
+		}
+		if globalValue, ok := globalConfig[k]; ok && globalValue != v {
+			lc.log.Warn("network access option overrides global node config",
+				zap.String("flag", k),
+				zap.Any("global-node-config-value", globalValue),
+				zap.String("value", v),
+			)
+		}
+		flags[k] = v
+	}
+
+	httpHost, _ := flags[config.HTTPHostKey].(string)
+	publicIP, _ := flags[config.PublicIPKey].(string)
+	apisExposed := httpHost != "" && !isLoopback(httpHost)
+	ipAdvertised := publicIP != "" && !isLoopback(publicIP)
+	switch {
+	case apisExposed && !ipAdvertised:
+		lc.log.Warn("node APIs are reachable from other hosts, but nodes advertise a loopback public IP, so nodes on other hosts can't peer with them",
+			zap.String("http-host", httpHost),
+			zap.String("public-ip", publicIP),
+		)
+	case ipAdvertised && !apisExposed:
+		lc.log.Warn("nodes advertise a non loopback public IP, but their APIs are only reachable from localhost",
+			zap.String("http-host", httpHost),
+			zap.String("public-ip", publicIP),
+		)
+	}
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
 // Creates a network and sets [lc.nw] to it.
 // Assumes [lc.lock] isn't held.
 func (lc *localNetwork) Start(ctx context.Context) error {
@@ -232,8 +389,47 @@ func (lc *localNetwork) Start(ctx context.Context) error {
 		return err
 	}
 
+	if lc.options.networkID != 0 {
+		genesis, err := network.SetGenesisNetworkID(lc.cfg.Genesis, lc.options.networkID)
+		if err != nil {
+			return fmt.Errorf("couldn't set network ID: %w", err)
+		}
+		lc.cfg.Genesis = genesis
+	}
+	if lc.options.txSigner != nil && lc.options.externalNetwork == nil {
+		genesis, err := network.FundGenesisAddress(lc.cfg.Genesis, lc.options.txSigner.Address())
+		if err != nil {
+			return fmt.Errorf("couldn't fund tx signer address: %w", err)
+		}
+		lc.cfg.Genesis = genesis
+	}
+	if lc.options.cChainStatePath != "" {
+		ux.Print(lc.log, logging.Blue.Wrap(logging.Bold.Wrap("importing C-Chain state from %s")), lc.options.cChainStatePath)
+		alloc, err := network.ReadCChainAlloc(lc.options.cChainStatePath)
+		if err != nil {
+			return fmt.Errorf("couldn't import C-Chain state: %w", err)
+		}
+		networkID := lc.options.networkID
+		if lc.options.fork != nil {
+			networkID = lc.options.fork.NetworkID
+		}
+		genesis, err := network.ImportCChainAlloc(lc.cfg.Genesis, alloc, networkID)
+		if err != nil {
+			return fmt.Errorf("couldn't import C-Chain state: %w", err)
+		}
+		lc.cfg.Genesis = genesis
+	}
+	if lc.options.fork != nil {
+		ux.Print(lc.log, logging.Blue.Wrap(logging.Bold.Wrap("forking C-Chain state from %s")), lc.options.fork.RPCURL)
+		genesis, err := network.ForkGenesis(ctx, lc.cfg.Genesis, *lc.options.fork)
+		if err != nil {
+			return fmt.Errorf("couldn't fork C-Chain state: %w", err)
+		}
+		lc.cfg.Genesis = genesis
+	}
+
 	ux.Print(lc.log, logging.Blue.Wrap(logging.Bold.Wrap("create and run local network")))
-	nw, err := local.NewNetwork(lc.log, lc.cfg, lc.options.rootDataDir, lc.options.snapshotsDir, lc.options.reassignPortsIfUsed)
+	nw, err := lc.options.backend.NewNetwork(lc.log, lc.cfg, lc.backendOptions())
 	if err != nil {
 		return err
 	}
@@ -293,13 +489,13 @@ func (lc *localNetwork) CreateChains(
 	return chainIDs, nil
 }
 
-func (lc *localNetwork) AddPermissionlessValidators(ctx context.Context, validatorSpecs []network.PermissionlessValidatorSpec) error {
+func (lc *localNetwork) AddPermissionlessValidators(ctx context.Context, validatorSpecs []network.PermissionlessValidatorSpec) ([]network.AddedPermissionlessValidator, error) {
 	lc.lock.Lock()
 	defer lc.lock.Unlock()
 
 	if len(validatorSpecs) == 0 {
 		ux.Print(lc.log, logging.Orange.Wrap(logging.Bold.Wrap("no validator specs provided...")))
-		return nil
+		return nil, nil
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -316,23 +512,23 @@ func (lc *localNetwork) AddPermissionlessValidators(ctx context.Context, validat
 	}(ctx)
 
 	if err := lc.awaitHealthyAndUpdateNetworkInfo(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
-	err := lc.nw.AddPermissionlessValidators(ctx, validatorSpecs)
+	added, err := lc.nw.AddPermissionlessValidators(ctx, validatorSpecs)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := lc.awaitHealthyAndUpdateNetworkInfo(ctx); err != nil {
-		return err
+		return nil, err
 	}
 
 	ux.Print(lc.log, logging.Green.Wrap(logging.Bold.Wrap("finished adding permissionless validators")))
-	return nil
+	return added, nil
 }
 
-func (lc *localNetwork) RemoveSubnetValidator(ctx context.Context, validatorSpecs []network.RemoveSubnetValidatorSpec) error {
+func (lc *localNetwork) RemoveSubnetValidator(ctx context.Context, validatorSpecs []network.RemoveSubnetValidatorSpec, skipRestart bool) error {
 	lc.lock.Lock()
 	defer lc.lock.Unlock()
 
@@ -358,7 +554,7 @@ func (lc *localNetwork) RemoveSubnetValidator(ctx context.Context, validatorSpec
 		return err
 	}
 
-	err := lc.nw.RemoveSubnetValidators(ctx, validatorSpecs)
+	err := lc.nw.RemoveSubnetValidators(ctx, validatorSpecs, skipRestart)
 	if err != nil {
 		return err
 	}
@@ -452,8 +648,9 @@ func (lc *localNetwork) CreateSubnets(ctx context.Context, subnetSpecs []network
 }
 
 // Loads a snapshot and sets [l.nw] to the network created from the snapshot.
+// [passphrase] is only needed if the snapshot was saved encrypted.
 // Assumes [lc.lock] isn't held.
-func (lc *localNetwork) LoadSnapshot(snapshotName string) error {
+func (lc *localNetwork) LoadSnapshot(snapshotName string, passphrase string) error {
 	lc.lock.Lock()
 	defer lc.lock.Unlock()
 
@@ -466,19 +663,16 @@ func (lc *localNetwork) LoadSnapshot(snapshotName string) error {
 		}
 	}
 
-	nw, err := local.NewNetworkFromSnapshot(
-		lc.log,
-		snapshotName,
-		lc.options.rootDataDir,
-		lc.options.snapshotsDir,
-		lc.execPath,
-		lc.pluginDir,
-		lc.options.chainConfigs,
-		lc.options.upgradeConfigs,
-		lc.options.subnetConfigs,
-		globalNodeConfig,
-		lc.options.reassignPortsIfUsed,
-	)
+	nw, err := lc.options.backend.NewNetworkFromSnapshot(lc.log, snapshotName, network.SnapshotOptions{
+		BackendOptions:   lc.backendOptions(),
+		BinaryPath:       lc.execPath,
+		PluginDir:        lc.pluginDir,
+		ChainConfigs:     lc.options.chainConfigs,
+		UpgradeConfigs:   lc.options.upgradeConfigs,
+		SubnetConfigs:    lc.options.subnetConfigs,
+		GlobalNodeConfig: globalNodeConfig,
+		Passphrase:       passphrase,
+	})
 	if err != nil {
 		return err
 	}
@@ -491,27 +685,26 @@ func (lc *localNetwork) LoadSnapshot(snapshotName string) error {
 	return nil
 }
 
+func (lc *localNetwork) backendOptions() network.BackendOptions {
+	return network.BackendOptions{
+		RootDataDir:         lc.options.rootDataDir,
+		SnapshotsDir:        lc.options.snapshotsDir,
+		ReassignPortsIfUsed: lc.options.reassignPortsIfUsed,
+		OnProgress:          lc.options.onProgress,
+		TxSigner:            lc.options.txSigner,
+	}
+}
+
 // Populates [lc.customChainIDToInfo] for all chains other than those on
 // the Primary Network (P-Chain, X-Chain, C-Chain.)
 // Populates [lc.subnets] with all subnets that exist.
 // Doesn't contain the Primary network.
 // Assumes [lc.lock] is held.
 func (lc *localNetwork) updateSubnetInfo(ctx context.Context) error {
-	nodes, err := lc.nw.GetAllNodes()
+	node, err := lc.getMinAPIPortNode()
 	if err != nil {
 		return err
 	}
-	minAPIPortNumber := uint16(local.MaxPort)
-	var node node.Node
-	for _, n := range nodes {
-		if n.GetPaused() {
-			continue
-		}
-		if n.GetAPIPort() < minAPIPortNumber {
-			minAPIPortNumber = n.GetAPIPort()
-			node = n
-		}
-	}
 
 	blockchains, err := node.GetAPIClient().PChainAPI().GetBlockchains(ctx)
 	if err != nil {
@@ -522,12 +715,19 @@ func (lc *localNetwork) updateSubnetInfo(ctx context.Context) error {
 		if blockchain.Name == "C-Chain" || blockchain.Name == "X-Chain" {
 			continue
 		}
+		// aliases are restored on the nodes from the network state, also
+		// when loading a snapshot
+		aliases, err := node.GetAPIClient().AdminAPI().GetChainAliases(ctx, blockchain.ID.String())
+		if err != nil {
+			lc.log.Warn("failure getting blockchain aliases", zap.String("chain-id", blockchain.ID.String()), zap.Error(err))
+		}
 		lc.customChainIDToInfo[blockchain.ID] = chainInfo{
 			info: &rpcpb.CustomChainInfo{
 				ChainName: blockchain.Name,
 				VmId:      blockchain.VMID.String(),
 				SubnetId:  blockchain.SubnetID.String(),
 				ChainId:   blockchain.ID.String(),
+				Aliases:   aliases,
 			},
 			subnetID:     blockchain.SubnetID,
 			blockchainID: blockchain.ID,
@@ -570,7 +770,12 @@ func (lc *localNetwork) updateSubnetInfo(ctx context.Context) error {
 		if _, err := node.GetAPIClient().PChainAPI().GetCurrentSupply(ctx, subnetID); err == nil {
 			isElastic = true
 			elasticSubnetID, err = lc.nw.GetElasticSubnetID(ctx, subnetID)
-			if err != nil {
+			switch {
+			case errors.Is(err, network.ErrElasticSubnetIDNotFound):
+				// snapshots of older versions don't keep the transform txs
+				lc.log.Warn("unknown elastic subnet ID", zap.String("subnet-id", subnetIDStr))
+				elasticSubnetID = ids.Empty
+			case err != nil:
 				return err
 			}
 		}
@@ -612,12 +817,388 @@ func (lc *localNetwork) updateSubnetInfo(ctx context.Context) error {
 	return nil
 }
 
+// Returns the running node with min API port, used as the reference
+// node for network wide queries.
+// Assumes [lc.lock] is held.
+func (lc *localNetwork) getMinAPIPortNode() (node.Node, error) {
+	nodes, err := lc.nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	minAPIPortNumber := uint16(local.MaxPort)
+	var node node.Node
+	for _, n := range nodes {
+		if n.GetPaused() {
+			continue
+		}
+		if n.GetAPIPort() < minAPIPortNumber {
+			minAPIPortNumber = n.GetAPIPort()
+			node = n
+		}
+	}
+	if node == nil {
+		return nil, errNoRunningNode
+	}
+	return node, nil
+}
+
+// [lc.lock] is not needed, as only the nodes are queried, so uptimes can
+// be got while an operation is in progress.
+func (lc *localNetwork) GetUptimes(ctx context.Context, subnetID ids.ID) (map[string]*rpcpb.NodeUptime, error) {
+	return lc.getUptimes(ctx, subnetID)
+}
+
+// Returns the uptime of all validators of [subnetID], by node name.
+// Connection status and uptime are as observed by the node with min API port,
+// while the weighted percentages are each node's own view, taken from the info API.
+// Assumes [lc.lock] is held.
+func (lc *localNetwork) getUptimes(ctx context.Context, subnetID ids.ID) (map[string]*rpcpb.NodeUptime, error) {
+	nodes, err := lc.nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	refNode, err := lc.getMinAPIPortNode()
+	if err != nil {
+		return nil, err
+	}
+	vdrs, err := refNode.GetAPIClient().PChainAPI().GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return nil, err
+	}
+	nodeNames := map[ids.NodeID]string{}
+	for nodeName, node := range nodes {
+		nodeNames[node.GetNodeID()] = nodeName
+	}
+	uptimes := map[string]*rpcpb.NodeUptime{}
+	for _, vdr := range vdrs {
+		nodeName, ok := nodeNames[vdr.NodeID]
+		if !ok {
+			continue
+		}
+		uptime := &rpcpb.NodeUptime{
+			NodeName: nodeName,
+			NodeId:   vdr.NodeID.String(),
+		}
+		if vdr.Connected != nil {
+			uptime.Connected = *vdr.Connected
+		}
+		if vdr.Uptime != nil {
+			uptime.ObservedUptime = *vdr.Uptime
+		}
+		node := nodes[nodeName]
+		if !node.GetPaused() {
+			// a node may not yet consider itself a validator, so don't fail on it
+			resp, err := node.GetAPIClient().InfoAPI().Uptime(ctx, subnetID)
+			if err != nil {
+				lc.log.Debug("failure getting uptime from node", zap.String("node-name", nodeName), zap.Error(err))
+			} else {
+				uptime.RewardingStakePercentage = float64(resp.RewardingStakePercentage)
+				uptime.WeightedAveragePercentage = float64(resp.WeightedAveragePercentage)
+			}
+		}
+		uptimes[nodeName] = uptime
+	}
+	return uptimes, nil
+}
+
+// [lc.lock] is not needed, as only the nodes are queried.
+func (lc *localNetwork) GetVersions(ctx context.Context) ([]*rpcpb.NodeVersions, error) {
+	return lc.getVersions(ctx)
+}
+
+// Returns the versions reported by the info API of the running nodes,
+// sorted by node name.
+// Assumes [lc.lock] is held.
+func (lc *localNetwork) getVersions(ctx context.Context) ([]*rpcpb.NodeVersions, error) {
+	nodes, err := lc.nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	nodeNames := maps.Keys(nodes)
+	sort.Strings(nodeNames)
+	nodeVersions := []*rpcpb.NodeVersions{}
+	for _, nodeName := range nodeNames {
+		node := nodes[nodeName]
+		if node.GetPaused() {
+			continue
+		}
+		resp, err := node.GetAPIClient().InfoAPI().GetNodeVersion(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failure getting version of node %q: %w", nodeName, err)
+		}
+		nodeVersions = append(nodeVersions, &rpcpb.NodeVersions{
+			NodeName:           nodeName,
+			Version:            resp.Version,
+			DatabaseVersion:    resp.DatabaseVersion,
+			RpcProtocolVersion: uint32(resp.RPCProtocolVersion),
+			GitCommit:          resp.GitCommit,
+			VmVersions:         resp.VMVersions,
+		})
+	}
+	return nodeVersions, nil
+}
+
+// Returns the info of the custom blockchains [customChains], keyed by chain
+// ID, sorted by chain ID.
+// [customChains] and [subnets] are snapshots taken by the caller, so
+// [lc.lock] is not needed, and blockchains can be listed while an operation
+// is in progress.
+func (lc *localNetwork) ListBlockchains(
+	ctx context.Context,
+	customChains map[string]*rpcpb.CustomChainInfo,
+	subnets map[string]*rpcpb.SubnetInfo,
+) ([]*rpcpb.BlockchainInfo, error) {
+	chainIDs := maps.Keys(customChains)
+	sort.Strings(chainIDs)
+	blockchains := []*rpcpb.BlockchainInfo{}
+	for _, chainID := range chainIDs {
+		blockchain, err := lc.getBlockchainInfo(ctx, customChains[chainID], subnets)
+		if err != nil {
+			return nil, err
+		}
+		blockchains = append(blockchains, blockchain)
+	}
+	return blockchains, nil
+}
+
+// Returns the info of the custom blockchain of [customChains] with the given
+// ID or alias.
+// [customChains] and [subnets] are snapshots taken by the caller, so
+// [lc.lock] is not needed.
+func (lc *localNetwork) GetBlockchainStatus(
+	ctx context.Context,
+	customChains map[string]*rpcpb.CustomChainInfo,
+	subnets map[string]*rpcpb.SubnetInfo,
+	chain string,
+) (*rpcpb.BlockchainInfo, error) {
+	chainID, err := ids.FromString(chain)
+	if err != nil {
+		node, err := lc.getMinAPIPortNode()
+		if err != nil {
+			return nil, err
+		}
+		chainID, err = node.GetAPIClient().InfoAPI().GetBlockchainID(ctx, chain)
+		if err != nil {
+			return nil, fmt.Errorf("failure resolving blockchain alias %q: %w", chain, err)
+		}
+	}
+	chainInfo, ok := customChains[chainID.String()]
+	if !ok {
+		return nil, fmt.Errorf("blockchain %q not found", chain)
+	}
+	return lc.getBlockchainInfo(ctx, chainInfo, subnets)
+}
+
+// Returns the info of the given custom blockchain, querying its P-Chain status,
+// aliases and bootstrap status on each running node that runs it. Nodes not
+// participating in the subnet of the blockchain, as given by [subnets], don't
+// run it.
+func (lc *localNetwork) getBlockchainInfo(
+	ctx context.Context,
+	chainInfo *rpcpb.CustomChainInfo,
+	subnets map[string]*rpcpb.SubnetInfo,
+) (*rpcpb.BlockchainInfo, error) {
+	refNode, err := lc.getMinAPIPortNode()
+	if err != nil {
+		return nil, err
+	}
+	chainID := chainInfo.ChainId
+	status, err := refNode.GetAPIClient().PChainAPI().GetBlockchainStatus(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := refNode.GetAPIClient().AdminAPI().GetChainAliases(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := lc.nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	bootstrapped := map[string]bool{}
+	for nodeName, node := range getBlockchainNodes(nodes, chainInfo.SubnetId, subnets) {
+		isBootstrapped, err := node.GetAPIClient().InfoAPI().IsBootstrapped(ctx, chainID)
+		if err != nil {
+			return nil, err
+		}
+		bootstrapped[nodeName] = isBootstrapped
+	}
+	return &rpcpb.BlockchainInfo{
+		ChainName:    chainInfo.ChainName,
+		VmId:         chainInfo.VmId,
+		SubnetId:     chainInfo.SubnetId,
+		ChainId:      chainID,
+		Aliases:      aliases,
+		Status:       status.String(),
+		Bootstrapped: bootstrapped,
+	}, nil
+}
+
+// Returns the running nodes of [nodes] that run the blockchains of
+// [subnetID], that is, the participants of the subnet as given by
+// [subnets]. Blockchains of subnets not in [subnets] are run by all nodes.
+func getBlockchainNodes(
+	nodes map[string]node.Node,
+	subnetID string,
+	subnets map[string]*rpcpb.SubnetInfo,
+) map[string]node.Node {
+	nodeNames := maps.Keys(nodes)
+	if subnetInfo, ok := subnets[subnetID]; ok {
+		nodeNames = subnetInfo.GetSubnetParticipants().GetNodeNames()
+	}
+	blockchainNodes := map[string]node.Node{}
+	for _, nodeName := range nodeNames {
+		if node, ok := nodes[nodeName]; ok && !node.GetPaused() {
+			blockchainNodes[nodeName] = node
+		}
+	}
+	return blockchainNodes
+}
+
+// Pauses [nodeName] for [duration] and then resumes it, returning the
+// primary network uptime of the node as observed by every other running
+// validator, before the pause and after the node is healthy again.
+// Assumes [lc.lock] isn't held.
+func (lc *localNetwork) SimulateDowntime(
+	ctx context.Context,
+	nodeName string,
+	duration time.Duration,
+) ([]*rpcpb.UptimeDelta, error) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func(ctx context.Context) {
+		select {
+		case <-lc.stopCh:
+			// The network is stopped; return from method calls below.
+			cancel()
+		case <-ctx.Done():
+			// This method is done. Don't leak [ctx].
+		}
+	}(ctx)
+
+	node, err := lc.nw.GetNode(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	nodeID := node.GetNodeID()
+
+	uptimesBefore, err := lc.getObservedUptimes(ctx, nodeName, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	ux.Print(lc.log, logging.Blue.Wrap(logging.Bold.Wrap("pausing node %s for %s")), nodeName, duration)
+	if err := lc.nw.PauseNode(ctx, nodeName); err != nil {
+		return nil, err
+	}
+	resumed := false
+	defer func() {
+		if resumed {
+			return
+		}
+		select {
+		case <-lc.stopCh:
+			// the network is stopped along with the node
+			return
+		default:
+		}
+		// [ctx] may be done, so the node is not left paused
+		resumeCtx, resumeCancel := context.WithTimeout(context.Background(), downtimeResumeTimeout)
+		defer resumeCancel()
+		if err := lc.nw.ResumeNode(resumeCtx, nodeName); err != nil {
+			lc.log.Warn("couldn't resume node after simulating its downtime", zap.String("node-name", nodeName), zap.Error(err))
+		}
+		if err := lc.updateNodeInfo(); err != nil {
+			lc.log.Warn("couldn't update node info", zap.Error(err))
+		}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(duration):
+	}
+	if err := lc.nw.ResumeNode(ctx, nodeName); err != nil {
+		return nil, err
+	}
+	resumed = true
+
+	if err := lc.awaitHealthyAndUpdateNetworkInfo(ctx); err != nil {
+		return nil, err
+	}
+
+	uptimesAfter, err := lc.getObservedUptimes(ctx, nodeName, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	observerNames := maps.Keys(uptimesBefore)
+	sort.Strings(observerNames)
+	deltas := []*rpcpb.UptimeDelta{}
+	for _, observerName := range observerNames {
+		uptimeAfter, ok := uptimesAfter[observerName]
+		if !ok {
+			continue
+		}
+		deltas = append(deltas, &rpcpb.UptimeDelta{
+			ObserverNodeName: observerName,
+			UptimeBefore:     uptimesBefore[observerName],
+			UptimeAfter:      uptimeAfter,
+		})
+	}
+
+	ux.Print(lc.log, logging.Green.Wrap(logging.Bold.Wrap("finished simulating downtime of node %s")), nodeName)
+	return deltas, nil
+}
+
+// Returns the primary network uptime of [nodeID] as observed by each
+// other running node, by observer node name.
+// Assumes [lc.lock] is held.
+func (lc *localNetwork) getObservedUptimes(
+	ctx context.Context,
+	nodeName string,
+	nodeID ids.NodeID,
+) (map[string]float32, error) {
+	nodes, err := lc.nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	uptimes := map[string]float32{}
+	for observerName, observer := range nodes {
+		if observerName == nodeName || observer.GetPaused() {
+			continue
+		}
+		vdrs, err := observer.GetAPIClient().PChainAPI().GetCurrentValidators(ctx, luxd_constants.PrimaryNetworkID, []ids.NodeID{nodeID})
+		if err != nil {
+			return nil, err
+		}
+		if len(vdrs) == 0 {
+			return nil, fmt.Errorf("node %s is not a primary network validator", nodeName)
+		}
+		if vdrs[0].Uptime != nil {
+			uptimes[observerName] = *vdrs[0].Uptime
+		}
+	}
+	return uptimes, nil
+}
+
+// [lc.lock] is only held to update the info, so operations on other
+// nodes are not blocked while waiting.
 // Assumes [lc.lock] isn't held.
 func (lc *localNetwork) AwaitHealthyAndUpdateNetworkInfo(ctx context.Context) error {
+	ux.Print(lc.log, logging.Blue.Wrap(logging.Bold.Wrap("waiting for all nodes to report healthy...")))
+
+	if err := lc.nw.Healthy(ctx); err != nil {
+		return err
+	}
+
 	lc.lock.Lock()
 	defer lc.lock.Unlock()
 
-	return lc.awaitHealthyAndUpdateNetworkInfo(ctx)
+	return lc.updateNetworkInfo(ctx)
 }
 
 // Returns nil when [lc.nw] reports healthy.
@@ -630,6 +1211,12 @@ func (lc *localNetwork) awaitHealthyAndUpdateNetworkInfo(ctx context.Context) er
 		return err
 	}
 
+	return lc.updateNetworkInfo(ctx)
+}
+
+// Updates node and subnet info, once [lc.nw] is healthy.
+// Assumes [lc.lock] is held.
+func (lc *localNetwork) updateNetworkInfo(ctx context.Context) error {
 	if err := lc.updateNodeInfo(); err != nil {
 		return err
 	}
@@ -638,6 +1225,15 @@ func (lc *localNetwork) awaitHealthyAndUpdateNetworkInfo(ctx context.Context) er
 		return err
 	}
 
+	// uptimes are informational, so failing to collect them
+	// doesn't fail the health wait
+	uptimes, err := lc.getUptimes(ctx, luxd_constants.PrimaryNetworkID)
+	if err != nil {
+		lc.log.Warn("failure getting primary network uptimes", zap.Error(err))
+	} else {
+		lc.uptimes = uptimes
+	}
+
 	nodeNames := maps.Keys(lc.nodeInfos)
 	sort.Strings(nodeNames)
 	for _, nodeName := range nodeNames {
@@ -651,6 +1247,21 @@ func (lc *localNetwork) awaitHealthyAndUpdateNetworkInfo(ctx context.Context) er
 	return nil
 }
 
+// Resets [lc.nw] to genesis, forgetting the subnets and custom chains,
+// and updates the network info once it is healthy.
+// Assumes [lc.lock] isn't held.
+func (lc *localNetwork) ResetNetwork(ctx context.Context) error {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+
+	if err := lc.nw.ResetNetwork(ctx); err != nil {
+		return err
+	}
+	lc.customChainIDToInfo = map[ids.ID]chainInfo{}
+	lc.subnets = map[string]*rpcpb.SubnetInfo{}
+	return lc.awaitHealthyAndUpdateNetworkInfo(ctx)
+}
+
 // Assumes [lc.lock] isn't held.
 func (lc *localNetwork) UpdateNodeInfo() error {
 	lc.lock.Lock()
@@ -659,6 +1270,16 @@ func (lc *localNetwork) UpdateNodeInfo() error {
 	return lc.updateNodeInfo()
 }
 
+// Returns a copy of [lc.nodeInfos], safe to read while the operations on
+// the nodes update it.
+// Assumes [lc.lock] isn't held.
+func (lc *localNetwork) getNodeInfos() map[string]*rpcpb.NodeInfo {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+
+	return cloneProtoMap(lc.nodeInfos)
+}
+
 // Populates [lc.nodeNames] and [lc.nodeInfos] for
 // all nodes in this network.
 // Assumes [lc.lock] is held.
@@ -670,22 +1291,20 @@ func (lc *localNetwork) updateNodeInfo() error {
 
 	lc.nodeInfos = make(map[string]*rpcpb.NodeInfo)
 	for name, node := range nodes {
-		trackSubnets, err := node.GetFlag(config.TrackSubnetsKey)
+		nodeInfo, err := newNodeInfo(node)
 		if err != nil {
 			return err
 		}
+		lc.nodeInfos[name] = nodeInfo
 
-		lc.nodeInfos[name] = &rpcpb.NodeInfo{
-			Name:               node.GetName(),
-			Uri:                fmt.Sprintf("http://%s:%d", node.GetURL(), node.GetAPIPort()),
-			Id:                 node.GetNodeID().String(),
-			ExecPath:           node.GetBinaryPath(),
-			LogDir:             node.GetLogsDir(),
-			DbDir:              node.GetDbDir(),
-			Config:             []byte(node.GetConfigFile()),
-			PluginDir:          node.GetPluginDir(),
-			WhitelistedSubnets: trackSubnets,
-			Paused:             node.GetPaused(),
+		for _, arg := range node.GetArgs() {
+			k, v, _ := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+			if k != config.NetworkNameKey {
+				continue
+			}
+			if networkID, err := strconv.ParseUint(v, 10, 32); err == nil {
+				lc.networkID = uint32(networkID)
+			}
 		}
 
 		// update default exec and pluginDir if empty (snapshots started without these params)
@@ -699,6 +1318,63 @@ func (lc *localNetwork) updateNodeInfo() error {
 	return lc.generatePrometheusConf()
 }
 
+// JSON encoded value given in the node infos to the flags that may
+// hold secrets, as the node infos are sent to read-only tokens too
+const redactedFlagValue = `"<redacted>"`
+
+// Returns true if the value of the node flag [k] may be secret: the
+// *-content flags, which hold key material or whole configs given inline,
+// and the ones named after passwords, secrets or tokens.
+func isSecretNodeFlag(k string) bool {
+	if strings.HasSuffix(k, "-content") {
+		return true
+	}
+	for _, word := range []string{"password", "passphrase", "secret", "token"} {
+		if strings.Contains(k, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the details of [node], as held by the network backend, with
+// the secret flags redacted.
+func newNodeInfo(node node.Node) (*rpcpb.NodeInfo, error) {
+	trackSubnets, err := node.GetFlag(config.TrackSubnetsKey)
+	if err != nil {
+		return nil, err
+	}
+	flags := map[string]string{}
+	for k, v := range node.GetConfig().Flags {
+		if isSecretNodeFlag(k) {
+			flags[k] = redactedFlagValue
+			continue
+		}
+		vBytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't marshal flag %q: %w", k, err)
+		}
+		flags[k] = string(vBytes)
+	}
+	return &rpcpb.NodeInfo{
+		Name:               node.GetName(),
+		Uri:                utils.HTTPURI(node.GetURL(), node.GetAPIPort()),
+		Id:                 node.GetNodeID().String(),
+		ExecPath:           node.GetBinaryPath(),
+		LogDir:             node.GetLogsDir(),
+		DbDir:              node.GetDbDir(),
+		Config:             []byte(node.GetConfigFile()),
+		PluginDir:          node.GetPluginDir(),
+		WhitelistedSubnets: trackSubnets,
+		Paused:             node.GetPaused(),
+		DataDir:            node.GetDataDir(),
+		Pid:                int64(node.GetPID()),
+		ApiPort:            uint32(node.GetAPIPort()),
+		P2PPort:            uint32(node.GetP2PPort()),
+		Flags:              flags,
+	}, nil
+}
+
 func (lc *localNetwork) generatePrometheusConf() error {
 	if lc.prometheusConfPath == "" {
 		lc.prometheusConfPath = filepath.Join(lc.options.rootDataDir, prometheusConfFname)
@@ -721,6 +1397,13 @@ func (lc *localNetwork) generatePrometheusConf() error {
 
 // Assumes [lc.lock] isn't held.
 func (lc *localNetwork) Stop(ctx context.Context) {
+	lc.StopWithOptions(ctx, network.StopOptions{})
+}
+
+// Stops the network as set by [opts], returning the outcome for each node.
+// Assumes [lc.lock] isn't held.
+func (lc *localNetwork) StopWithOptions(ctx context.Context, opts network.StopOptions) []network.NodeStopResult {
+	var results []network.NodeStopResult
 	lc.stopOnce.Do(func() {
 		close(lc.stopCh) // Stop in-flight method executions
 
@@ -728,7 +1411,8 @@ func (lc *localNetwork) Stop(ctx context.Context) {
 		defer lc.lock.Unlock()
 
 		if lc.nw != nil {
-			err := lc.nw.Stop(ctx)
+			var err error
+			results, err = lc.nw.StopWithOptions(ctx, opts)
 			msg := "terminated network"
 			if err != nil {
 				msg += fmt.Sprintf(" (error %v)", err)
@@ -736,4 +1420,5 @@ func (lc *localNetwork) Stop(ctx context.Context) {
 			ux.Print(lc.log, logging.Red.Wrap(msg))
 		}
 	})
+	return results
 }
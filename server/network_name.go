@@ -0,0 +1,79 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/luxdefi/netrunner/utils/constants"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Methods that don't target a running network, so the
+// network name given by the client is not checked against it.
+var networkNameUncheckedMethods = newMethodSet(
+	"/rpcpb.PingService/",
+	"/rpcpb.ControlService/RPCVersion",
+	"/rpcpb.ControlService/Start",
+	"/rpcpb.ControlService/LoadSnapshot",
+	"/rpcpb.ControlService/RemoveSnapshot",
+	"/rpcpb.ControlService/GetSnapshotNames",
+)
+
+// Returns the network name given by the client in the metadata
+// of [ctx], or the default network name if none is given.
+func getRequestNetworkName(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return constants.DefaultNetworkName
+	}
+	names := md.Get(constants.NetworkNameMetadataKey)
+	if len(names) == 0 || names[0] == "" {
+		return constants.DefaultNetworkName
+	}
+	return names[0]
+}
+
+// Returns an error if the network name requested in [ctx] doesn't
+// match the running network.
+func (s *server) checkNetworkName(ctx context.Context, fullMethod string) error {
+	if networkNameUncheckedMethods.contains(fullMethod) {
+		return nil
+	}
+	networkName := getRequestNetworkName(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.network != nil && networkName != s.networkName {
+		return fmt.Errorf("%w: %q", ErrNetworkNotFound, networkName)
+	}
+	return nil
+}
+
+func (s *server) unaryNetworkNameInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := s.checkNetworkName(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *server) streamNetworkNameInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := s.checkNetworkName(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
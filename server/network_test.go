@@ -0,0 +1,139 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/config"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNetworkAccess(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           *rpcpb.NetworkAccessSpec
+		expectedAccess networkAccess
+		expectedErr    error
+	}{
+		{
+			name: "no spec",
+		},
+		{
+			name:           "loopback host",
+			spec:           &rpcpb.NetworkAccessSpec{HttpHost: "127.0.0.1"},
+			expectedAccess: networkAccess{httpHost: "127.0.0.1"},
+		},
+		{
+			name:           "specific interface",
+			spec:           &rpcpb.NetworkAccessSpec{HttpHost: "192.168.1.10", PublicIp: "192.168.1.10"},
+			expectedAccess: networkAccess{httpHost: "192.168.1.10", publicIP: "192.168.1.10"},
+		},
+		{
+			name:           "external access binds to the wildcard",
+			spec:           &rpcpb.NetworkAccessSpec{HttpHost: "127.0.0.1", ExternalAccess: true, PublicIp: "10.0.0.5"},
+			expectedAccess: networkAccess{httpHost: "0.0.0.0", publicIP: "10.0.0.5"},
+		},
+		{
+			name:        "invalid public IP",
+			spec:        &rpcpb.NetworkAccessSpec{PublicIp: "not-an-ip"},
+			expectedErr: ErrInvalidPublicIP,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			access, err := getNetworkAccess(tt.spec)
+			require.ErrorIs(err, tt.expectedErr)
+			require.Equal(tt.expectedAccess, access)
+		})
+	}
+}
+
+func TestSetNetworkAccessFlags(t *testing.T) {
+	tests := []struct {
+		name          string
+		access        networkAccess
+		flags         map[string]interface{}
+		globalConfig  map[string]interface{}
+		expectedFlags map[string]interface{}
+	}{
+		{
+			name:          "no access",
+			flags:         map[string]interface{}{},
+			expectedFlags: map[string]interface{}{},
+		},
+		{
+			name:          "no access keeps the given flags",
+			flags:         map[string]interface{}{config.HTTPHostKey: "0.0.0.0", config.PublicIPKey: "10.0.0.5"},
+			expectedFlags: map[string]interface{}{config.HTTPHostKey: "0.0.0.0", config.PublicIPKey: "10.0.0.5"},
+		},
+		{
+			name:          "loopback host",
+			access:        networkAccess{httpHost: "127.0.0.1"},
+			flags:         map[string]interface{}{},
+			expectedFlags: map[string]interface{}{config.HTTPHostKey: "127.0.0.1"},
+		},
+		{
+			name:          "wildcard host and public IP",
+			access:        networkAccess{httpHost: "0.0.0.0", publicIP: "10.0.0.5"},
+			flags:         map[string]interface{}{},
+			expectedFlags: map[string]interface{}{config.HTTPHostKey: "0.0.0.0", config.PublicIPKey: "10.0.0.5"},
+		},
+		{
+			name:          "wildcard host without public IP",
+			access:        networkAccess{httpHost: "0.0.0.0"},
+			flags:         map[string]interface{}{config.PublicIPKey: "127.0.0.1"},
+			expectedFlags: map[string]interface{}{config.HTTPHostKey: "0.0.0.0", config.PublicIPKey: "127.0.0.1"},
+		},
+		{
+			name:          "specific interface",
+			access:        networkAccess{httpHost: "192.168.1.10", publicIP: "192.168.1.10"},
+			flags:         map[string]interface{}{},
+			expectedFlags: map[string]interface{}{config.HTTPHostKey: "192.168.1.10", config.PublicIPKey: "192.168.1.10"},
+		},
+		{
+			name:          "access overrides the global config",
+			access:        networkAccess{httpHost: "0.0.0.0", publicIP: "10.0.0.5"},
+			flags:         map[string]interface{}{config.HTTPHostKey: "127.0.0.1", config.PublicIPKey: "127.0.0.1"},
+			globalConfig:  map[string]interface{}{config.HTTPHostKey: "127.0.0.1", config.PublicIPKey: "127.0.0.1"},
+			expectedFlags: map[string]interface{}{config.HTTPHostKey: "0.0.0.0", config.PublicIPKey: "10.0.0.5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lc := &localNetwork{
+				log:     logging.NoLog{},
+				options: localNetworkOptions{networkAccess: tt.access},
+			}
+			lc.setNetworkAccessFlags(tt.flags, tt.globalConfig)
+			require.Equal(t, tt.expectedFlags, tt.flags)
+		})
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		host     string
+		expected bool
+	}{
+		{host: "localhost", expected: true},
+		{host: "127.0.0.1", expected: true},
+		{host: "127.0.0.2", expected: true},
+		{host: "::1", expected: true},
+		{host: "", expected: false},
+		{host: "0.0.0.0", expected: false},
+		{host: "::", expected: false},
+		{host: "192.168.1.10", expected: false},
+		{host: "example.com", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			require.Equal(t, tt.expected, isLoopback(tt.host))
+		})
+	}
+}
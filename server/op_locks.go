@@ -0,0 +1,58 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import "sync"
+
+// opLocks serializes the operations changing the network. Operations on the
+// whole network are exclusive, while operations on a single node only
+// exclude the network wide ones and the other operations on the same node,
+// so operations on different nodes proceed concurrently.
+type opLocks struct {
+	network sync.RWMutex
+
+	nodesLock sync.Mutex
+	// node name -> lock held by the operation on the node
+	nodes map[string]*sync.Mutex
+}
+
+func newOpLocks() *opLocks {
+	return &opLocks{
+		nodes: map[string]*sync.Mutex{},
+	}
+}
+
+// Locks the whole network.
+func (l *opLocks) Lock() {
+	l.network.Lock()
+
+	// no node lock is held now, so the ones of removed nodes are dropped
+	l.nodesLock.Lock()
+	l.nodes = map[string]*sync.Mutex{}
+	l.nodesLock.Unlock()
+}
+
+// Unlocks the whole network.
+func (l *opLocks) Unlock() {
+	l.network.Unlock()
+}
+
+// Locks the node [nodeName]. The returned func unlocks it.
+func (l *opLocks) LockNode(nodeName string) func() {
+	l.network.RLock()
+
+	l.nodesLock.Lock()
+	nodeLock, ok := l.nodes[nodeName]
+	if !ok {
+		nodeLock = new(sync.Mutex)
+		l.nodes[nodeName] = nodeLock
+	}
+	l.nodesLock.Unlock()
+
+	nodeLock.Lock()
+	return func() {
+		nodeLock.Unlock()
+		l.network.RUnlock()
+	}
+}
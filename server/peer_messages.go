@@ -0,0 +1,184 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/luxdefi/netrunner/local"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/message"
+	"github.com/luxdefi/node/proto/pb/p2p"
+	"github.com/luxdefi/node/utils/compression"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	// deadline of the request messages built by SendPeerMessage, if not given
+	defaultPeerMessageDeadline = 10 * time.Second
+	// max time a built message is considered valid by the node
+	peerMessageMaxTimeout = 10 * time.Second
+)
+
+var ErrInvalidPeerMessage = errors.New("invalid peer message")
+
+// Returns the path of the file on the server host to record peer messages
+// to, or replay them from, given by a client as [path]. Relative paths are
+// taken from the network root dir, and the path must be under the network
+// root dir or the snapshots dir.
+func (s *server) getPeerRecordingPath(path string) (string, error) {
+	s.mu.RLock()
+	rootDir := s.clusterInfo.RootDataDir
+	s.mu.RUnlock()
+
+	return getConfinedPath(path, ErrInvalidRecordingPath, rootDir, local.GetSnapshotsDir(s.cfg.SnapshotsDir))
+}
+
+// Returns the path of a file on the server host given by a client as
+// [path]. Relative paths are taken from the first non empty of [dirs].
+// So that clients can't read or write arbitrary files of the server host,
+// the path must be under one of the non empty [dirs], and can't contain
+// "..". The errors returned wrap [errInvalid].
+func getConfinedPath(path string, errInvalid error, dirs ...string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("%w: path is missing", errInvalid)
+	}
+	for _, elem := range strings.Split(filepath.ToSlash(path), "/") {
+		if elem == ".." {
+			return "", fmt.Errorf("%w: %q contains \"..\"", errInvalid, path)
+		}
+	}
+
+	allowedDirs := []string{}
+	for _, dir := range dirs {
+		if dir != "" {
+			allowedDirs = append(allowedDirs, dir)
+		}
+	}
+	if !filepath.IsAbs(path) {
+		if len(allowedDirs) == 0 {
+			return "", fmt.Errorf("%w: %q is relative and there is no dir to take it from", errInvalid, path)
+		}
+		path = filepath.Join(allowedDirs[0], path)
+	}
+	path = filepath.Clean(path)
+	for _, dir := range allowedDirs {
+		rel, err := filepath.Rel(dir, path)
+		if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q is not under %s", errInvalid, path, strings.Join(allowedDirs, " or "))
+}
+
+// Builds the message of [req] and sends it from the attached peer to the node.
+func (s *server) SendPeerMessage(ctx context.Context, req *rpcpb.SendPeerMessageRequest) (*rpcpb.SendPeerMessageResponse, error) {
+	defer s.opMu.LockNode(req.NodeName)()
+
+	s.log.Debug("SendPeerMessage", zap.String("node-name", req.NodeName), zap.String("peer-ID", req.PeerId))
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	node, err := s.network.nw.GetNode(req.NodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := buildPeerMessage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := node.SendOutboundMessage(ctx, req.PeerId, msg.Bytes(), uint32(msg.Op()))
+	return &rpcpb.SendPeerMessageResponse{
+		Sent:  sent,
+		Op:    uint32(msg.Op()),
+		Bytes: msg.Bytes(),
+	}, err
+}
+
+// Builds the p2p message described by [req]. Messages are not compressed,
+// so the attached peer can alter them if it misbehaves.
+func buildPeerMessage(req *rpcpb.SendPeerMessageRequest) (message.OutboundMessage, error) {
+	mc, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		"",
+		compression.TypeNone,
+		peerMessageMaxTimeout,
+	)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := ids.FromString(req.ChainId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid chain ID %q: %s", ErrInvalidPeerMessage, req.ChainId, err)
+	}
+	deadline := time.Duration(req.Deadline)
+	if deadline == 0 {
+		deadline = defaultPeerMessageDeadline
+	}
+	engineType := p2p.EngineType(req.EngineType)
+
+	switch {
+	case req.GetPullQuery() != nil:
+		containerID, err := parsePeerMessageID(req.GetPullQuery().ContainerId)
+		if err != nil {
+			return nil, err
+		}
+		return mc.PullQuery(chainID, req.RequestId, deadline, containerID, engineType)
+	case req.GetPushQuery() != nil:
+		return mc.PushQuery(chainID, req.RequestId, deadline, req.GetPushQuery().Container, engineType)
+	case req.GetGet() != nil:
+		containerID, err := parsePeerMessageID(req.GetGet().ContainerId)
+		if err != nil {
+			return nil, err
+		}
+		return mc.Get(chainID, req.RequestId, deadline, containerID, engineType)
+	case req.GetChits() != nil:
+		preferredIDs, err := parsePeerMessageIDs(req.GetChits().PreferredContainerIds)
+		if err != nil {
+			return nil, err
+		}
+		acceptedIDs, err := parsePeerMessageIDs(req.GetChits().AcceptedContainerIds)
+		if err != nil {
+			return nil, err
+		}
+		return mc.Chits(chainID, req.RequestId, preferredIDs, acceptedIDs)
+	case req.GetAppRequest() != nil:
+		return mc.AppRequest(chainID, req.RequestId, deadline, req.GetAppRequest().AppBytes)
+	default:
+		return nil, fmt.Errorf("%w: no message given", ErrInvalidPeerMessage)
+	}
+}
+
+func parsePeerMessageID(s string) (ids.ID, error) {
+	id, err := ids.FromString(s)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("%w: invalid container ID %q: %s", ErrInvalidPeerMessage, s, err)
+	}
+	return id, nil
+}
+
+func parsePeerMessageIDs(strs []string) ([]ids.ID, error) {
+	containerIDs := make([]ids.ID, 0, len(strs))
+	for _, s := range strs {
+		id, err := parsePeerMessageID(s)
+		if err != nil {
+			return nil, err
+		}
+		containerIDs = append(containerIDs, id)
+	}
+	return containerIDs, nil
+}
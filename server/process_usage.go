@@ -0,0 +1,81 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"sync"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/shirou/gopsutil/process"
+)
+
+// processSampler samples resource usage of node processes.
+// Process handles are kept between samples, so CPU usage
+// is computed over the time elapsed since the previous sample.
+type processSampler struct {
+	lock  sync.Mutex
+	procs map[int32]*process.Process
+}
+
+func newProcessSampler() *processSampler {
+	return &processSampler{
+		procs: map[int32]*process.Process{},
+	}
+}
+
+// Fills in the resource usage of each of [nodeInfos].
+// Nodes that are not running, or that can't be sampled, are
+// left with zero usage.
+func (ps *processSampler) sample(nodeInfos map[string]*rpcpb.NodeInfo) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	for _, nodeInfo := range nodeInfos {
+		if nodeInfo.Pid == 0 {
+			continue
+		}
+		pid := int32(nodeInfo.Pid)
+		proc, ok := ps.procs[pid]
+		if !ok {
+			var err error
+			proc, err = process.NewProcess(pid)
+			if err != nil {
+				continue
+			}
+			ps.procs[pid] = proc
+		}
+		cpuPercent, err := proc.Percent(0)
+		if err != nil {
+			// the process is gone
+			delete(ps.procs, pid)
+			continue
+		}
+		nodeInfo.CpuPercent = cpuPercent
+		if memInfo, err := proc.MemoryInfo(); err == nil {
+			nodeInfo.Rss = memInfo.RSS
+		}
+		if numFDs, err := proc.NumFDs(); err == nil {
+			nodeInfo.NumFds = numFDs
+		}
+	}
+}
+
+// Forgets the processes that are not the one of any of [nodeInfos],
+// so the handles of stopped, removed or restarted nodes are not kept.
+func (ps *processSampler) retain(nodeInfos map[string]*rpcpb.NodeInfo) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	pids := make(map[int32]struct{}, len(nodeInfos))
+	for _, nodeInfo := range nodeInfos {
+		if nodeInfo.Pid != 0 {
+			pids[int32(nodeInfo.Pid)] = struct{}{}
+		}
+	}
+	for pid := range ps.procs {
+		if _, ok := pids[pid]; !ok {
+			delete(ps.procs, pid)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/rpcpb"
+)
+
+// max number of progress events buffered for a subscriber
+const progressSubscriptionBufferSize = 256
+
+// Forwards the progress events of the network operations to the
+// clients streaming them.
+type progressHub struct {
+	lock        sync.Mutex
+	subscribers map[chan *rpcpb.StreamProgressResponse]struct{}
+}
+
+func newProgressHub() *progressHub {
+	return &progressHub{
+		subscribers: map[chan *rpcpb.StreamProgressResponse]struct{}{},
+	}
+}
+
+// Returns a channel with the progress events from now on, and a
+// function to stop receiving them.
+func (h *progressHub) subscribe() (<-chan *rpcpb.StreamProgressResponse, func()) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	ch := make(chan *rpcpb.StreamProgressResponse, progressSubscriptionBufferSize)
+	h.subscribers[ch] = struct{}{}
+	unsubscribe := func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, unsubscribe
+}
+
+// Forwards [event] to the subscribers, dropping it for the ones whose
+// channel is full, so a slow client doesn't block the operation.
+// Used as the progress handler of the networks.
+func (h *progressHub) publish(event network.ProgressEvent) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	resp := &rpcpb.StreamProgressResponse{
+		Phase:     event.Phase,
+		Percent:   event.Percent,
+		Message:   event.Message,
+		Timestamp: time.Now().UnixMilli(),
+	}
+	for ch := range h.subscribers {
+		select {
+		case ch <- resp:
+		default:
+		}
+	}
+}
+
+// Streams the progress of the network operations (start, blockchain
+// creation, ...) from now on, until the client cancels or the server stops.
+func (s *server) StreamProgress(_ *rpcpb.StreamProgressRequest, stream rpcpb.ControlService_StreamProgressServer) error {
+	s.log.Debug("StreamProgress")
+
+	events, unsubscribe := s.progress.subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-s.rootCtx.Done():
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			if err := stream.Send(event); err != nil {
+				if isClientCanceled(stream.Context().Err(), err) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Requests to "/networks/<network>/nodes/<node>/ext/..." on the gateway
+	// port are forwarded to "/ext/..." on the node API port.
+	proxyPathPrefix = "/networks/"
+)
+
+// Returns the gateway handler, which serves the reverse proxy to the
+// node APIs, the dashboard if enabled, and the gRPC gateway for all other paths.
+func (s *server) newGatewayHandler() (http.Handler, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(proxyPathPrefix, s.serveNodeProxy)
+	if s.cfg.DashboardEnabled {
+		if err := s.registerDashboard(mux); err != nil {
+			return nil, err
+		}
+	}
+	mux.Handle("/", s.gwMux)
+	return s.withGRPCWebAndCORS(mux), nil
+}
+
+func (s *server) serveNodeProxy(w http.ResponseWriter, r *http.Request) {
+	// node APIs can change the network, so only admin tokens can reach them
+	if s.authEnabled() {
+		if s.getTokenRole(strings.TrimPrefix(r.Header.Get("Authorization"), bearerPrefix)) != roleAdmin {
+			http.Error(w, ErrUnauthenticated.Error(), http.StatusUnauthorized)
+			return
+		}
+		r.Header.Del("Authorization")
+	}
+	// <network>/nodes/<node>/ext/...
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, proxyPathPrefix), "/", 4)
+	if len(parts) != 4 || parts[1] != "nodes" || (parts[3] != "ext" && !strings.HasPrefix(parts[3], "ext/")) {
+		http.NotFound(w, r)
+		return
+	}
+	networkName, nodeName, nodePath := parts[0], parts[2], "/"+parts[3]
+
+	nodeURI, statusCode, err := s.getNodeProxyURI(networkName, nodeName)
+	if err != nil {
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+	target, err := url.Parse(nodeURI)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.log.Debug("proxying node request",
+		zap.String("node-name", nodeName),
+		zap.String("path", nodePath),
+	)
+	r.URL.Path = nodePath
+	r.URL.RawPath = ""
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(r *http.Request) {
+		director(r)
+		// the node gets its own address as host, not the gateway one
+		r.Host = target.Host
+	}
+	proxy.ErrorHandler = func(w http.ResponseWriter, _ *http.Request, err error) {
+		s.log.Debug("node proxy error", zap.String("node-name", nodeName), zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// Returns the API URI of [nodeName] in [networkName], or the HTTP status
+// code to reply with if the node can't be reached.
+func (s *server) getNodeProxyURI(networkName string, nodeName string) (string, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.network == nil {
+		return "", http.StatusServiceUnavailable, ErrNotBootstrapped
+	}
+	if networkName != s.networkName {
+		return "", http.StatusNotFound, fmt.Errorf("%w: %q", ErrNetworkNotFound, networkName)
+	}
+	nodeInfo, ok := s.clusterInfo.NodeInfos[nodeName]
+	if !ok {
+		return "", http.StatusNotFound, fmt.Errorf("%w: %s", ErrNodeNotFound, nodeName)
+	}
+	if nodeInfo.Paused {
+		return "", http.StatusServiceUnavailable, fmt.Errorf("node %q is paused", nodeName)
+	}
+	return nodeInfo.Uri, 0, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/luxdefi/netrunner/utils/constants"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeNodeProxy(t *testing.T) {
+	require := require.New(t)
+
+	var gotHost, gotPath string
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		gotPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer node.Close()
+	nodeURL, err := url.Parse(node.URL)
+	require.NoError(err)
+
+	s, _ := newTestServer(t, 1)
+	s.mu.Lock()
+	s.clusterInfo.NodeInfos["node1"].Uri = node.URL
+	s.mu.Unlock()
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+	}{
+		{
+			name:         "node API",
+			path:         "/networks/" + constants.DefaultNetworkName + "/nodes/node1/ext/info",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "unknown node",
+			path:         "/networks/" + constants.DefaultNetworkName + "/nodes/node2/ext/info",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "unknown network",
+			path:         "/networks/other/nodes/node1/ext/info",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "not a node API",
+			path:         "/networks/" + constants.DefaultNetworkName + "/nodes/node1/metrics",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotHost, gotPath = "", ""
+			req := httptest.NewRequest(http.MethodPost, "http://gateway.example:8081"+tt.path, nil)
+			w := httptest.NewRecorder()
+			s.serveNodeProxy(w, req)
+			require.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusOK {
+				// the node gets its own address as host
+				require.Equal(t, nodeURL.Host, gotHost)
+				require.Equal(t, "/ext/info", gotPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// clients not seen for this long have their rate limiter removed
+const clientLimiterIdleTimeout = 10 * time.Minute
+
+var (
+	ErrRateLimited         = errors.New("too many requests from this client")
+	ErrTooManyHeavyOps     = errors.New("too many start and snapshot load operations queued")
+	rateLimitExemptMethods = newMethodSet(
+		"/rpcpb.PingService/",
+	)
+	// operations that start node processes, capped by
+	// [Config.MaxConcurrentHeavyOps]
+	heavyOpMethods = newMethodSet(
+		"/rpcpb.ControlService/Start",
+		"/rpcpb.ControlService/LoadSnapshot",
+		"/rpcpb.ControlService/ResetNetwork",
+		"/rpcpb.ControlService/ExportCChainState",
+		"/rpcpb.ControlService/PruneNodeDB",
+		"/rpcpb.ControlService/ResumeAll",
+	)
+)
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limits the rate of the requests of each client, and the number
+// of heavy operations in progress or queued.
+type requestLimiter struct {
+	// requests per second allowed for each client. 0 disables the limit
+	rateLimit rate.Limit
+	burst     int
+
+	lock    sync.Mutex
+	clients map[string]*clientLimiter
+
+	// a slot is taken by each heavy operation in progress
+	heavyOps chan struct{}
+	// a slot is taken by each heavy operation in progress or queued
+	queuedHeavyOps chan struct{}
+}
+
+func newRequestLimiter(cfg Config) *requestLimiter {
+	l := &requestLimiter{
+		rateLimit: rate.Limit(cfg.RateLimit),
+		burst:     cfg.RateLimitBurst,
+		clients:   map[string]*clientLimiter{},
+	}
+	if l.burst < 1 {
+		l.burst = 1
+	}
+	if cfg.MaxConcurrentHeavyOps > 0 {
+		l.heavyOps = make(chan struct{}, cfg.MaxConcurrentHeavyOps)
+		l.queuedHeavyOps = make(chan struct{}, cfg.MaxConcurrentHeavyOps+cfg.MaxQueuedHeavyOps)
+	}
+	return l
+}
+
+// Returns an error if the client of [ctx] exceeded its request rate.
+func (l *requestLimiter) allow(ctx context.Context, fullMethod string) error {
+	if l.rateLimit <= 0 || rateLimitExemptMethods.contains(fullMethod) {
+		return nil
+	}
+	client := getClientAddress(ctx)
+	now := time.Now()
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	c, ok := l.clients[client]
+	if !ok {
+		l.removeIdleClients(now)
+		c = &clientLimiter{limiter: rate.NewLimiter(l.rateLimit, l.burst)}
+		l.clients[client] = c
+	}
+	c.lastSeen = now
+	if !c.limiter.AllowN(now, 1) {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// Assumes [l.lock] is held.
+func (l *requestLimiter) removeIdleClients(now time.Time) {
+	for client, c := range l.clients {
+		if now.Sub(c.lastSeen) > clientLimiterIdleTimeout {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// Waits until a heavy operation can run, if [fullMethod] is one. The
+// returned function must be called once the operation is done.
+// Returns an error if the queue is full or [ctx] is done while waiting.
+func (l *requestLimiter) acquireHeavyOp(ctx context.Context, fullMethod string) (func(), error) {
+	if l.heavyOps == nil || !heavyOpMethods.contains(fullMethod) {
+		return func() {}, nil
+	}
+	select {
+	case l.queuedHeavyOps <- struct{}{}:
+	default:
+		return nil, ErrTooManyHeavyOps
+	}
+	select {
+	case l.heavyOps <- struct{}{}:
+	case <-ctx.Done():
+		<-l.queuedHeavyOps
+		return nil, ctx.Err()
+	}
+	return func() {
+		<-l.heavyOps
+		<-l.queuedHeavyOps
+	}, nil
+}
+
+// Returns the address of the client of [ctx]. Requests forwarded by
+// the grpc-gateway are identified by the address they were sent from.
+// The forwarded address is only trusted from a local peer, as the
+// gateway dials the server over loopback or the unix socket. Only the
+// right-most entry is used: it is the one appended by the gateway, while
+// the ones before are copied from the X-Forwarded-For header of the
+// request, so remote clients could pick them.
+func getClientAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if isLocalPeer(p.Addr) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+				hops := strings.Split(forwarded[len(forwarded)-1], ",")
+				if hop := strings.TrimSpace(hops[len(hops)-1]); hop != "" {
+					return hop
+				}
+			}
+		}
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// Returns true if [addr] is a unix socket or loopback address.
+func isLocalPeer(addr net.Addr) bool {
+	switch a := addr.(type) {
+	case *net.UnixAddr:
+		return true
+	case *net.TCPAddr:
+		return a.IP.IsLoopback()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Set of gRPC methods, given by their full name or, to include all the
+// methods of a service, by the service name as "/<service>/".
+type methodSet map[string]struct{}
+
+func newMethodSet(methods ...string) methodSet {
+	set := make(methodSet, len(methods))
+	for _, method := range methods {
+		set[method] = struct{}{}
+	}
+	return set
+}
+
+// Returns true if [fullMethod] or its service is in the set.
+func (set methodSet) contains(fullMethod string) bool {
+	if _, ok := set[fullMethod]; ok {
+		return true
+	}
+	i := strings.LastIndex(fullMethod, "/")
+	if i <= 0 {
+		return false
+	}
+	_, ok := set[fullMethod[:i+1]]
+	return ok
+}
+
+func (s *server) unaryLimitInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := s.limiter.allow(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	release, err := s.limiter.acquireHeavyOp(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return handler(ctx, req)
+}
+
+func (s *server) streamLimitInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := s.limiter.allow(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
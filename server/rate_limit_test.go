@@ -0,0 +1,137 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/luxdefi/netrunner/utils/constants"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetClientAddress(t *testing.T) {
+	localPeer := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1000}
+	remotePeer := &net.TCPAddr{IP: net.IPv4(198, 51, 100, 1), Port: 1000}
+
+	tests := []struct {
+		name      string
+		addr      net.Addr
+		forwarded []string
+		expected  string
+	}{
+		{name: "remote", addr: remotePeer, expected: "198.51.100.1"},
+		{name: "local", addr: localPeer, expected: "127.0.0.1"},
+		{name: "forwarded by the gateway", addr: localPeer, forwarded: []string{"203.0.113.1"}, expected: "203.0.113.1"},
+		{name: "spoofed hops", addr: localPeer, forwarded: []string{"192.0.2.1, 192.0.2.2, 203.0.113.1"}, expected: "203.0.113.1"},
+		{name: "empty hop", addr: localPeer, forwarded: []string{"192.0.2.1, "}, expected: "127.0.0.1"},
+		{name: "forwarded to a remote peer", addr: remotePeer, forwarded: []string{"203.0.113.1"}, expected: "198.51.100.1"},
+		{name: "unix socket", addr: &net.UnixAddr{Name: "/tmp/netrunner.sock", Net: "unix"}, forwarded: []string{"203.0.113.1"}, expected: "203.0.113.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tt.addr})
+			if len(tt.forwarded) > 0 {
+				ctx = metadata.NewIncomingContext(ctx, metadata.MD{"x-forwarded-for": tt.forwarded})
+			}
+			require.Equal(t, tt.expected, getClientAddress(ctx))
+		})
+	}
+}
+
+// Clients sending their own X-Forwarded-For header through the gateway
+// are still limited by their address.
+func TestGetClientAddressSpoofed(t *testing.T) {
+	require := require.New(t)
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/control/status", nil)
+	r.RemoteAddr = "203.0.113.1:1234"
+	r.Header.Set("X-Forwarded-For", "192.0.2.1")
+	ctx, err := runtime.AnnotateContext(context.Background(), runtime.NewServeMux(), r, "/rpcpb.ControlService/Status")
+	require.NoError(err)
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(ok)
+
+	ctx = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1000}})
+	ctx = metadata.NewIncomingContext(ctx, md)
+	require.Equal("203.0.113.1", getClientAddress(ctx))
+}
+
+// Calls [fullMethod] through the unary interceptors of [s].
+func callUnaryInterceptors(ctx context.Context, s *server, fullMethod string, handler grpc.UnaryHandler) error {
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+	interceptors := s.unaryInterceptors()
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+	_, err := handler(ctx, nil)
+	return err
+}
+
+// Unauthenticated heavy operations are rejected before being queued, so
+// they can't starve the ones of admins.
+func TestUnauthenticatedHeavyOps(t *testing.T) {
+	require := require.New(t)
+
+	cfg := Config{
+		AdminTokens:           []string{"admin"},
+		MaxConcurrentHeavyOps: 1,
+		MaxQueuedHeavyOps:     1,
+	}
+	s := &server{
+		cfg:     cfg,
+		mu:      new(sync.RWMutex),
+		log:     logging.NoLog{},
+		limiter: newRequestLimiter(cfg),
+	}
+	adminCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(constants.AuthorizationMetadataKey, bearerPrefix+"admin"))
+	const method = "/rpcpb.ControlService/Start"
+
+	// an admin operation in progress
+	running := make(chan struct{})
+	done := make(chan struct{})
+	errCh := make(chan error, 2)
+	go func() {
+		errCh <- callUnaryInterceptors(adminCtx, s, method, func(context.Context, interface{}) (interface{}, error) {
+			close(running)
+			<-done
+			return nil, nil
+		})
+	}()
+	<-running
+
+	// the unauthenticated one is rejected at once instead of waiting for
+	// a slot, and its handler is never called
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := callUnaryInterceptors(ctx, s, method, func(context.Context, interface{}) (interface{}, error) {
+		return nil, errors.New("unauthenticated call handled")
+	})
+	require.Equal(ErrUnauthenticated.Error(), status.Convert(err).Message())
+
+	// the queue slot is still free for another admin operation
+	go func() {
+		errCh <- callUnaryInterceptors(adminCtx, s, method, func(context.Context, interface{}) (interface{}, error) {
+			return nil, nil
+		})
+	}()
+	close(done)
+	require.NoError(<-errCh)
+	require.NoError(<-errCh)
+}
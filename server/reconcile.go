@@ -0,0 +1,283 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/config"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Answers a Start request received while the network is running. If the
+// network was started with the same request, it is returned as is. In
+// reconcile mode, the nodes missing from the network, or in excess, are
+// added or removed, and the missing chains are created, to match the
+// request.
+// Assumes [s.opMu] is held.
+func (s *server) startRunningNetwork(ctx context.Context, req *rpcpb.StartRequest) (*rpcpb.StartResponse, error) {
+	if s.startRequest == nil || getRequestNetworkName(ctx) != s.networkName {
+		return nil, ErrAlreadyBootstrapped
+	}
+	if !isSameStartRequest(req, s.startRequest) {
+		if !req.GetReconcile() {
+			return nil, fmt.Errorf("%w with a different config", ErrAlreadyBootstrapped)
+		}
+		if err := s.reconcile(req); err != nil {
+			return nil, err
+		}
+	}
+
+	chainIDs := maps.Keys(s.clusterInfo.CustomChains)
+	sort.Strings(chainIDs)
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.StartResponse{ClusterInfo: clusterInfo, ChainIds: chainIDs}, nil
+}
+
+// Returns true if [a] and [b] request the same network.
+func isSameStartRequest(a *rpcpb.StartRequest, b *rpcpb.StartRequest) bool {
+	a = proto.Clone(a).(*rpcpb.StartRequest)
+	b = proto.Clone(b).(*rpcpb.StartRequest)
+	a.Reconcile, b.Reconcile = false, false
+	return proto.Equal(a, b)
+}
+
+// Start request fields applied to a running network by reconcile.
+var reconciledStartFields = map[protoreflect.Name]bool{
+	"num_nodes":           true,
+	"custom_node_configs": true,
+	"node_groups":         true,
+	"blockchain_specs":    true,
+	"reconcile":           true,
+	"validate_only":       true,
+}
+
+// Adds and removes nodes and creates chains so the network matches [req].
+// Nothing is changed if [req] differs from the request the network was
+// started with in a way that can't be reconciled.
+// Assumes [s.opMu] is held.
+func (s *server) reconcile(req *rpcpb.StartRequest) error {
+	s.log.Info("reconciling network with start request")
+
+	if fields := getUnreconciledFields(req, s.startRequest); len(fields) > 0 {
+		return invalidArgumentError(fmt.Errorf("%w: %s differ from the running network", ErrUnreconcilableStart, strings.Join(fields, ", ")))
+	}
+	// the node groups of [req] are expanded into its custom node configs by
+	// Start, so a change of the groups is a change of the node configs
+	numNodes := req.GetNumNodes()
+	if len(req.GetCustomNodeConfigs()) > 0 {
+		numNodes = uint32(len(req.GetCustomNodeConfigs()))
+	}
+	if numNodes < MinNodes {
+		return ErrNotEnoughNodesForStart
+	}
+	if numNodes > MaxNodes {
+		return invalidArgumentError(fmt.Errorf("%w: %d nodes, the maximum is %d", ErrTooManyNodes, numNodes, MaxNodes))
+	}
+	specs, err := getReconciledChainSpecs(req.GetBlockchainSpecs(), s.startRequest.GetBlockchainSpecs())
+	if err != nil {
+		return invalidArgumentError(err)
+	}
+	chainSpecs := []network.BlockchainSpec{}
+	for _, spec := range specs {
+		chainSpec, err := getNetworkBlockchainSpec(s.log, spec, false, s.network.pluginDir, s.network.options.stakingSpec.GetValidationStartOffset())
+		if err != nil {
+			if errors.Is(err, ErrInvalidDuration) {
+				return invalidArgumentError(err)
+			}
+			return err
+		}
+		chainSpecs = append(chainSpecs, chainSpec)
+	}
+
+	if err := s.reconcileNodes(req); err != nil {
+		return err
+	}
+	if err := s.reconcileChains(chainSpecs); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.startRequest = proto.Clone(req).(*rpcpb.StartRequest)
+	s.mu.Unlock()
+	return nil
+}
+
+// Returns the names of the fields of [req] that differ from [running], the
+// request of the running network, and that reconcile can't apply.
+func getUnreconciledFields(req *rpcpb.StartRequest, running *rpcpb.StartRequest) []string {
+	fields := []string{}
+	reqMsg, runningMsg := req.ProtoReflect(), running.ProtoReflect()
+	fieldDescs := reqMsg.Descriptor().Fields()
+	for i := 0; i < fieldDescs.Len(); i++ {
+		fd := fieldDescs.Get(i)
+		if reconciledStartFields[fd.Name()] {
+			continue
+		}
+		// compare messages only holding the field
+		a, b := &rpcpb.StartRequest{}, &rpcpb.StartRequest{}
+		if reqMsg.Has(fd) {
+			a.ProtoReflect().Set(fd, reqMsg.Get(fd))
+		}
+		if runningMsg.Has(fd) {
+			b.ProtoReflect().Set(fd, runningMsg.Get(fd))
+		}
+		if !proto.Equal(a, b) {
+			fields = append(fields, string(fd.Name()))
+		}
+	}
+	return fields
+}
+
+// Returns the blockchain specs of [specs] the network doesn't have yet,
+// given the [runningSpecs] it was started with. Specs are matched as a
+// whole, subnet included. An error is returned if a running spec is not in
+// [specs], as chains can't be removed or changed.
+func getReconciledChainSpecs(specs []*rpcpb.BlockchainSpec, runningSpecs []*rpcpb.BlockchainSpec) ([]*rpcpb.BlockchainSpec, error) {
+	matched := make([]bool, len(runningSpecs))
+	toCreate := []*rpcpb.BlockchainSpec{}
+	for _, spec := range specs {
+		found := false
+		for i, runningSpec := range runningSpecs {
+			if !matched[i] && proto.Equal(spec, runningSpec) {
+				matched[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			toCreate = append(toCreate, spec)
+		}
+	}
+	for i, runningSpec := range runningSpecs {
+		if !matched[i] {
+			return nil, fmt.Errorf("%w: blockchain of VM %q is not in the request, and chains can't be removed or changed", ErrUnreconcilableStart, runningSpec.VmName)
+		}
+	}
+	return toCreate, nil
+}
+
+// Assumes [s.opMu] is held.
+func (s *server) reconcileNodes(req *rpcpb.StartRequest) error {
+	nodeNames := append([]string{}, s.clusterInfo.NodeNames...)
+	sortNodeNames(nodeNames)
+	nodeInfos := s.network.getNodeInfos()
+
+	// node name --> JSON config of the nodes to add
+	toAdd := map[string]string{}
+	toRemove := []string{}
+	if customNodeConfigs := req.GetCustomNodeConfigs(); len(customNodeConfigs) > 0 {
+		for name, nodeConfig := range customNodeConfigs {
+			if _, ok := nodeInfos[name]; !ok {
+				toAdd[name] = nodeConfig
+			}
+		}
+		for _, name := range nodeNames {
+			if _, ok := customNodeConfigs[name]; !ok {
+				toRemove = append(toRemove, name)
+			}
+		}
+	} else {
+		numNodes := int(req.GetNumNodes())
+		for i := len(nodeNames) + 1; len(nodeNames)+len(toAdd) < numNodes; i++ {
+			name := fmt.Sprintf("node%d", i)
+			if _, ok := nodeInfos[name]; !ok {
+				toAdd[name] = ""
+			}
+		}
+		if len(nodeNames) > numNodes {
+			// remove the last added nodes
+			toRemove = nodeNames[numNodes:]
+		}
+	}
+	if len(toAdd) == 0 && len(toRemove) == 0 {
+		return nil
+	}
+
+	for _, name := range toRemove {
+		s.log.Info("reconcile: removing node", zap.String("name", name))
+		ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+		err := s.network.nw.RemoveNode(ctx, name)
+		cancel()
+		if err != nil {
+			return err
+		}
+	}
+	addNames := maps.Keys(toAdd)
+	sortNodeNames(addNames)
+	for _, name := range addNames {
+		s.log.Info("reconcile: adding node", zap.String("name", name))
+		nodeFlags := map[string]interface{}{}
+		if toAdd[name] != "" {
+			if err := json.Unmarshal([]byte(toAdd[name]), &nodeFlags); err != nil {
+				return err
+			}
+		}
+		if req.GetPluginDir() != "" {
+			nodeFlags[config.PluginDirKey] = req.GetPluginDir()
+		}
+		if _, err := s.network.nw.AddNode(node.Config{
+			Name:               name,
+			Flags:              nodeFlags,
+			BinaryPath:         req.GetExecPath(),
+			RedirectStdout:     s.cfg.RedirectNodesOutput,
+			RedirectStderr:     s.cfg.RedirectNodesOutput,
+			ChainConfigFiles:   req.ChainConfigs,
+			UpgradeConfigFiles: req.UpgradeConfigs,
+			SubnetConfigFiles:  req.SubnetConfigs,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := s.network.UpdateNodeInfo(); err != nil {
+		return err
+	}
+	s.updateClusterNodeInfos()
+	return nil
+}
+
+// Creates the chains of [chainSpecs], the requested ones the network
+// doesn't have yet.
+// Assumes [s.opMu] is held.
+func (s *server) reconcileChains(chainSpecs []network.BlockchainSpec) error {
+	if len(chainSpecs) == 0 {
+		return nil
+	}
+
+	s.log.Info("reconcile: creating chains", zap.Int("chains", len(chainSpecs)))
+	s.setClusterUnhealthy()
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	if _, err := s.network.CreateChains(ctx, chainSpecs); err != nil {
+		s.log.Error("failed to create blockchains", zap.Error(err))
+		s.stopAndRemoveNetwork(err)
+		return err
+	}
+	s.updateClusterInfo()
+	return nil
+}
+
+// Sorts node names as numbered, so "node2" comes before "node10".
+func sortNodeNames(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) < len(names[j])
+		}
+		return names[i] < names[j]
+	})
+}
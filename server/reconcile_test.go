@@ -0,0 +1,171 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetUnreconciledFields(t *testing.T) {
+	numNodes := uint32(3)
+	globalNodeConfig := `{"log-level":"debug"}`
+	running := &rpcpb.StartRequest{ExecPath: "luxd", NumNodes: &numNodes, Ttl: 10}
+
+	otherNumNodes := uint32(5)
+	tests := []struct {
+		name     string
+		req      *rpcpb.StartRequest
+		expected []string
+	}{
+		{
+			name:     "same",
+			req:      &rpcpb.StartRequest{ExecPath: "luxd", NumNodes: &numNodes, Ttl: 10},
+			expected: []string{},
+		},
+		{
+			name: "reconciled fields",
+			req: &rpcpb.StartRequest{
+				ExecPath:          "luxd",
+				NumNodes:          &otherNumNodes,
+				Ttl:               10,
+				CustomNodeConfigs: map[string]string{"node1": "{}"},
+				BlockchainSpecs:   []*rpcpb.BlockchainSpec{{VmName: "subnetevm"}},
+				Reconcile:         true,
+			},
+			expected: []string{},
+		},
+		{
+			name:     "other fields",
+			req:      &rpcpb.StartRequest{ExecPath: "luxd2", NumNodes: &numNodes, GlobalNodeConfig: &globalNodeConfig},
+			expected: []string{"exec_path", "global_node_config", "ttl"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, getUnreconciledFields(tt.req, running))
+		})
+	}
+}
+
+func TestGetReconciledChainSpecs(t *testing.T) {
+	subnetEVM := &rpcpb.BlockchainSpec{VmName: "subnetevm", Genesis: "genesis"}
+	otherSubnet := &rpcpb.BlockchainSpec{
+		VmName:     "subnetevm",
+		Genesis:    "genesis",
+		SubnetSpec: &rpcpb.SubnetSpec{Participants: []string{"node1"}},
+	}
+	timestampVM := &rpcpb.BlockchainSpec{VmName: "timestampvm", Genesis: "genesis"}
+
+	tests := []struct {
+		name         string
+		specs        []*rpcpb.BlockchainSpec
+		runningSpecs []*rpcpb.BlockchainSpec
+		expected     []*rpcpb.BlockchainSpec
+		expectErr    bool
+	}{
+		{
+			name:         "same",
+			specs:        []*rpcpb.BlockchainSpec{subnetEVM, timestampVM},
+			runningSpecs: []*rpcpb.BlockchainSpec{timestampVM, subnetEVM},
+			expected:     []*rpcpb.BlockchainSpec{},
+		},
+		{
+			name:         "added chains",
+			specs:        []*rpcpb.BlockchainSpec{subnetEVM, subnetEVM, timestampVM},
+			runningSpecs: []*rpcpb.BlockchainSpec{subnetEVM},
+			expected:     []*rpcpb.BlockchainSpec{subnetEVM, timestampVM},
+		},
+		{
+			name:         "removed chain",
+			specs:        []*rpcpb.BlockchainSpec{subnetEVM},
+			runningSpecs: []*rpcpb.BlockchainSpec{subnetEVM, timestampVM},
+			expectErr:    true,
+		},
+		{
+			name:         "changed subnet",
+			specs:        []*rpcpb.BlockchainSpec{otherSubnet},
+			runningSpecs: []*rpcpb.BlockchainSpec{subnetEVM},
+			expectErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := getReconciledChainSpecs(tt.specs, tt.runningSpecs)
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrUnreconcilableStart)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, specs)
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, _ := newTestServer(t, 2)
+	runningNumNodes := uint32(2)
+	s.startRequest = &rpcpb.StartRequest{ExecPath: "luxd", NumNodes: &runningNumNodes}
+
+	// nothing is changed when a field can't be reconciled
+	numNodes := uint32(3)
+	_, err := s.Start(ctx, &rpcpb.StartRequest{ExecPath: "luxd2", NumNodes: &numNodes, Reconcile: true})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	require.ErrorContains(err, "exec_path")
+	clusterInfo, err := s.copyClusterInfo()
+	require.NoError(err)
+	require.Equal([]string{"node1", "node2"}, clusterInfo.NodeNames)
+
+	noNodes := uint32(0)
+	_, err = s.Start(ctx, &rpcpb.StartRequest{ExecPath: "luxd", NumNodes: &noNodes, Reconcile: true})
+	require.ErrorIs(err, ErrNotEnoughNodesForStart)
+
+	resp, err := s.Start(ctx, &rpcpb.StartRequest{ExecPath: "luxd", NumNodes: &numNodes, Reconcile: true})
+	require.NoError(err)
+	require.Equal([]string{"node1", "node2", "node3"}, resp.ClusterInfo.NodeNames)
+	require.Equal(numNodes, s.startRequest.GetNumNodes())
+}
+
+func TestReconcileNodeGroups(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	getNodeGroups := func(count uint32) []*rpcpb.NodeGroup {
+		return []*rpcpb.NodeGroup{{Name: "validators", Count: count, NodeConfig: `{"log-level":"debug"}`}}
+	}
+	s, _ := newTestServer(t, 2)
+	numNodes := DefaultNodes
+	// as recorded by Start, with the groups expanded
+	s.startRequest = &rpcpb.StartRequest{
+		ExecPath:          "luxd",
+		NumNodes:          &numNodes,
+		NodeGroups:        getNodeGroups(2),
+		CustomNodeConfigs: map[string]string{"node1": `{"log-level":"debug"}`, "node2": `{"log-level":"debug"}`},
+	}
+
+	// the same groups leave the network as is
+	resp, err := s.Start(ctx, &rpcpb.StartRequest{ExecPath: "luxd", NodeGroups: getNodeGroups(2)})
+	require.NoError(err)
+	require.Equal([]string{"node1", "node2"}, resp.ClusterInfo.NodeNames)
+
+	// a group growing adds its nodes
+	resp, err = s.Start(ctx, &rpcpb.StartRequest{ExecPath: "luxd", NodeGroups: getNodeGroups(4), Reconcile: true})
+	require.NoError(err)
+	require.Equal([]string{"node1", "node2", "node3", "node4"}, resp.ClusterInfo.NodeNames)
+	require.Len(s.startRequest.GetCustomNodeConfigs(), 4)
+
+	// and shrinking removes them
+	resp, err = s.Start(ctx, &rpcpb.StartRequest{ExecPath: "luxd", NodeGroups: getNodeGroups(1), Reconcile: true})
+	require.NoError(err)
+	require.Equal([]string{"node1"}, resp.ClusterInfo.NodeNames)
+	require.Equal(getNodeGroups(1)[0].Count, s.startRequest.GetNodeGroups()[0].Count)
+}
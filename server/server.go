@@ -6,10 +6,12 @@ package server
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"net/http"
 	"os"
@@ -21,16 +23,22 @@ import (
 
 	"go.uber.org/multierr"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/luxdefi/netrunner/local"
 	"github.com/luxdefi/netrunner/network"
 	"github.com/luxdefi/netrunner/network/node"
 	"github.com/luxdefi/netrunner/rpcpb"
 	"github.com/luxdefi/netrunner/utils"
 	"github.com/luxdefi/netrunner/utils/constants"
 	"github.com/luxdefi/node/config"
+	"github.com/luxdefi/node/ids"
 	"github.com/luxdefi/node/message"
 	"github.com/luxdefi/node/snow/networking/router"
+	luxd_constants "github.com/luxdefi/node/utils/constants"
+	"github.com/luxdefi/node/utils/formatting/address"
 	"github.com/luxdefi/node/utils/logging"
 	"github.com/luxdefi/node/utils/set"
+	"github.com/luxdefi/node/vms/nftfx"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
@@ -38,28 +46,80 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
 	// RPCVersion should be bumped anytime changes are made which require
 	// the RPC client to upgrade to latest RPC server to be compatible
-	RPCVersion   uint32 = 1
-	MinNodes     uint32 = 1
-	DefaultNodes uint32 = 5
+	RPCVersion uint32 = 2
+	// MinClientRPCVersion is the oldest RPC client version compatible with
+	// this server. Should be bumped when a change breaks older clients
+	MinClientRPCVersion uint32 = 1
+	MinNodes            uint32 = 1
+	DefaultNodes        uint32 = 5
+	// bounds the nodes a single request can create, as each one is a
+	// process with its own ports and file descriptors
+	MaxNodes uint32 = 1000
 
 	stopTimeout           = 5 * time.Second
 	defaultStartTimeout   = 5 * time.Minute
 	waitForHealthyTimeout = 3 * time.Minute
+	// time given to each node to exit on Stop if the request sets none
+	defaultNodeStopTimeout = 30 * time.Second
 
 	networkRootDirPrefix   = "network"
 	TimeParseLayout        = "2006-01-02 15:04:05"
 	StakingMinimumLeadTime = 25 * time.Second
+	// stake durations are given in hours, and longer ones overflow a time.Duration
+	maxStakeDurationHours = uint64(math.MaxInt64 / int64(time.Hour))
 )
 
+// optional features supported by this server
+var rpcCapabilities = []string{
+	constants.CapabilityTTL,
+	constants.CapabilityReconcile,
+	constants.CapabilityScaleNetwork,
+	constants.CapabilityNodeGroups,
+	constants.CapabilityLogsShardSize,
+	constants.CapabilityStreamLogs,
+	constants.CapabilityPeerBehavior,
+	constants.CapabilityPeerMessageRecording,
+	constants.CapabilityStreamPeerMessages,
+	constants.CapabilitySendPeerMessage,
+	constants.CapabilitySendAppRequest,
+	constants.CapabilityGetVersions,
+	constants.CapabilityOperationHistory,
+	constants.CapabilityPagination,
+	constants.CapabilityResetNetwork,
+	constants.CapabilityStateSyncNode,
+	constants.CapabilityCChainState,
+	constants.CapabilityDBSizes,
+	constants.CapabilityStreamProgress,
+	constants.CapabilityTopology,
+	constants.CapabilityConnectivity,
+	constants.CapabilityPauseAll,
+	constants.CapabilityDirLayout,
+	constants.CapabilitySearchLogs,
+	constants.CapabilityExportNetwork,
+	constants.CapabilityWaitFor,
+	constants.CapabilityAssert,
+	constants.CapabilityChaos,
+	constants.CapabilityKeyService,
+	constants.CapabilityCreateAsset,
+	constants.CapabilityTransfer,
+	constants.CapabilityStake,
+	constants.CapabilityGetElasticSubnet,
+	constants.CapabilityStakingRewards,
+	constants.CapabilityRenewValidations,
+	constants.CapabilityDrainNode,
+}
+
 var (
 	ErrInvalidVMName          = errors.New("invalid VM name")
 	ErrInvalidPort            = errors.New("invalid port")
 	ErrNotEnoughNodesForStart = errors.New("not enough nodes specified for start")
+	ErrTooManyNodes           = errors.New("too many nodes specified")
 	ErrAlreadyBootstrapped    = errors.New("already bootstrapped")
 	ErrNotBootstrapped        = errors.New("not bootstrapped")
 	ErrNodeNotFound           = errors.New("node not found")
@@ -69,17 +129,104 @@ var (
 	ErrNoSubnetID             = errors.New("subnetID is missing")
 	ErrNoElasticSubnetSpec    = errors.New("no elastic subnet spec was provided")
 	ErrNoValidatorSpec        = errors.New("no validator spec was provided")
+	ErrNetworkUpgradeAndSpec  = errors.New("network upgrade and network upgrade spec can't be given at the same time")
+	ErrNoBlockchain           = errors.New("blockchain id or alias is missing")
+	ErrNoAlias                = errors.New("alias is missing")
+	ErrNoTransferSpec         = errors.New("no transfer subnet ownership spec was provided")
+	ErrInvalidAssetSpec       = errors.New("invalid asset spec")
+	ErrNoAddress              = errors.New("address is missing")
+	ErrZeroAmount             = errors.New("amount must be greater than zero")
+	ErrInvalidPublicIP        = errors.New("invalid public IP")
+	ErrNetworkNotFound        = errors.New("network not found")
+	ErrInvalidChain           = errors.New("invalid chain")
+	ErrInvalidGrep            = errors.New("invalid grep expression")
+	ErrInvalidTTL             = errors.New("invalid TTL")
+	ErrInvalidDirLayout       = errors.New("invalid dir layout")
+	ErrInvalidExportFormat    = errors.New("invalid export format")
+	ErrNoWaitConditions       = errors.New("no wait conditions were provided")
+	ErrNoAssertions           = errors.New("no assertions were provided")
+	ErrInvalidAssertion       = errors.New("invalid assertion")
+	ErrInvalidChaosAction     = errors.New("invalid chaos action")
+	ErrChaosRunning           = errors.New("chaos already running")
+	ErrInvalidChaosConfig     = errors.New("invalid chaos config")
+	ErrChaosNotRunning        = errors.New("chaos not running")
+	ErrExternalNetworkChains  = errors.New("blockchains can't be created on start when joining an external network")
+	ErrInvalidForkSpec        = errors.New("invalid fork spec")
+	ErrExternalNetworkState   = errors.New("C-Chain state can't be imported into an external network")
+	ErrExternalNetworkID      = errors.New("network ID can't be given when joining an external network")
+	ErrKeyNotFound            = errors.New("key not found")
+	ErrKeyExists              = errors.New("key already exists")
+	ErrInvalidKeyName         = errors.New("invalid key name")
+	ErrInvalidTx              = errors.New("invalid tx")
+	ErrInvalidTxSignerSpec    = errors.New("invalid tx signer spec")
+	ErrInvalidKeysStorage     = errors.New("invalid staking keys storage")
+	ErrNoCChainStatePath      = errors.New("C-Chain state path is missing")
+	ErrInvalidCChainStatePath = errors.New("invalid C-Chain state path")
+	ErrNetworkExpired         = errors.New("network stopped after its TTL expired")
+	ErrInvalidNodeGroup       = errors.New("invalid node group")
+	ErrValidationFailed       = errors.New("validation failed")
+	ErrInvalidPeerBehavior    = errors.New("invalid peer behavior")
+	ErrInvalidRecordingPath   = errors.New("invalid peer messages recording path")
+	ErrInvalidRenewal         = errors.New("invalid validation renewal")
+	ErrInvalidDuration        = errors.New("invalid duration")
+	ErrSnapshotPassphrase     = errors.New("snapshots of networks keeping the staking keys in memory must be encrypted")
+	ErrInvalidNodeTimeout     = errors.New("invalid node timeout")
+	ErrInvalidWaitConfig      = errors.New("invalid wait config")
+	ErrUnreconcilableStart    = errors.New("start request can't be reconciled")
 )
 
 type Config struct {
 	Port   string
 	GwPort string
+	// unix domain socket path for the gRPC server (overrides Port)
+	GRPCSocket string
 	// true to disable grpc-gateway server
 	GwDisabled          bool
 	DialTimeout         time.Duration
 	RedirectNodesOutput bool
 	SnapshotsDir        string
 	LogLevel            logging.Level
+	// true to serve the web dashboard on the grpc-gateway port
+	DashboardEnabled bool
+	// true to serve the gRPC services with grpc-web on the grpc-gateway port
+	GRPCWebEnabled bool
+	// origins browsers may call the grpc-gateway port from. "*" allows all
+	CORSAllowedOrigins []string
+	// requests per second allowed for each client. 0 disables the limit
+	RateLimit float64
+	// requests a client can burst over [RateLimit]
+	RateLimitBurst int
+	// max number of start and snapshot load operations in progress.
+	// 0 disables the limit
+	MaxConcurrentHeavyOps int
+	// max number of start and snapshot load operations waiting for the
+	// ones in progress, before new ones are rejected
+	MaxQueuedHeavyOps int
+	// file the ControlService calls are appended to. Empty disables it
+	AuditLogPath string
+	// API tokens allowed to call every method. If any admin or
+	// read-only token is given, requests must carry one
+	AdminTokens []string
+	// API tokens only allowed to call the methods reading the server state
+	ReadOnlyTokens []string
+	// max total size in bytes of the dirs of old networks, the oldest being
+	// removed first. 0 disables the limit
+	DataDirMaxSize uint64
+	// max age of the dirs of old networks. 0 disables the limit
+	DataDirMaxAge time.Duration
+	// base URL of a Loki server the node logs are pushed to. Empty disables it
+	LogForwardLokiURL string
+	// syslog the node logs are sent to, either "local" or an address
+	// such as udp://host:514. Empty disables it
+	LogForwardSyslogAddr string
+	// file the node logs are appended to as JSON lines. Empty disables it
+	LogForwardFile string
+	// file the manifest of the running network is written to, besides the
+	// one in the network root data dir. Empty disables it
+	ManifestPath string
+	// dirs the node dirs of a start request dir layout may be placed under,
+	// besides the network root data dir
+	NodeDirsAllowed []string
 }
 
 type Server interface {
@@ -87,6 +234,16 @@ type Server interface {
 }
 
 type server struct {
+	// serializes the operations changing the network, and is held
+	// for their whole duration. Operations on a single node only lock
+	// that node, so they don't wait for operations on other nodes.
+	opMu *opLocks
+	// guards [network], [clusterInfo], [fundedKey], [networkName] and [startRequest],
+	// so the RPCs reading them don't wait for the operation in progress.
+	// [network], [networkName] and [startRequest] are written holding both
+	// the whole [opMu] and [mu], so holding either of them is enough to
+	// read them. [clusterInfo] is also updated by node operations, so it
+	// is only read holding [mu].
 	mu *sync.RWMutex
 
 	cfg Config
@@ -103,14 +260,38 @@ type server struct {
 	gwServer *http.Server
 
 	clusterInfo *rpcpb.ClusterInfo
+	// key funding the txs of [network], written to its manifest. nil if
+	// the network has none.
+	fundedKey *manifestKey
 	// Controls running nodes.
 	// Invariant: If [network] is non-nil, then [clusterInfo] is non-nil.
 
 	network    *localNetwork
 	asyncErrCh chan error
+	// name of [network], used by clients to target it
+	networkName string
+	// request [network] was started with, if not loaded from a snapshot
+	startRequest *rpcpb.StartRequest
+
+	// Samples resource usage of node processes for status reports.
+	procSampler *processSampler
+
+	// Limits the request rate of clients and the heavy operations.
+	limiter *requestLimiter
+	// Records the ControlService calls, if enabled.
+	auditLog *auditLog
+	// Forwards the progress of the network operations to clients.
+	progress *progressHub
+	// Destinations of the node logs, if log forwarding is enabled.
+	logSinks []logSink
+	// Takes random nodes down and back up, once started.
+	chaos *chaosMonkey
+	// Renewals of primary network validations running in the background.
+	renewals sync.WaitGroup
 
 	rpcpb.UnimplementedPingServiceServer
 	rpcpb.UnimplementedControlServiceServer
+	rpcpb.UnimplementedKeyServiceServer
 }
 
 // grpc encapsulates the non protocol-related, ANR server domain errors,
@@ -124,11 +305,11 @@ func IsServerError(err error, serverError error) bool {
 }
 
 func New(cfg Config, log logging.Logger) (Server, error) {
-	if cfg.Port == "" || cfg.GwPort == "" {
+	if (cfg.Port == "" && cfg.GRPCSocket == "") || (cfg.GwPort == "" && !cfg.GwDisabled) {
 		return nil, ErrInvalidPort
 	}
 
-	listener, err := net.Listen("tcp", cfg.Port)
+	listener, err := newGRPCListener(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -138,31 +319,107 @@ func New(cfg Config, log logging.Logger) (Server, error) {
 		log:        log,
 		closed:     make(chan struct{}),
 		ln:         listener,
-		gRPCServer: grpc.NewServer(),
+		opMu:       newOpLocks(),
 		mu:         new(sync.RWMutex),
 		asyncErrCh: make(chan error, 1),
+
+		procSampler: newProcessSampler(),
+		limiter:     newRequestLimiter(cfg),
+		progress:    newProgressHub(),
+		chaos:       newChaosMonkey(),
+	}
+	if cfg.AuditLogPath != "" {
+		s.auditLog, err = newAuditLog(cfg.AuditLogPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.logSinks, err = newLogSinks(cfg)
+	if err != nil {
+		return nil, err
 	}
+	s.gRPCServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryInterceptors()...),
+		grpc.ChainStreamInterceptor(s.streamInterceptors()...),
+	)
 	if !cfg.GwDisabled {
 		s.gwMux = runtime.NewServeMux()
+		gwHandler, err := s.newGatewayHandler()
+		if err != nil {
+			return nil, err
+		}
 		s.gwServer = &http.Server{ //nolint // TODO add ReadHeaderTimeout
 			Addr:    cfg.GwPort,
-			Handler: s.gwMux,
+			Handler: gwHandler,
 		}
 	}
 
 	return s, nil
 }
 
+// Returns the interceptors of unary calls, in the order they run. Auth is
+// checked first, so rejected callers neither take rate limit or heavy
+// operation slots, nor are recorded in the audit log.
+func (s *server) unaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		unaryErrorCodeInterceptor,
+		s.unaryAuthInterceptor,
+		s.unaryLimitInterceptor,
+		s.unaryAuditInterceptor,
+		s.unaryNetworkNameInterceptor,
+	}
+}
+
+// Returns the interceptors of streaming calls, in the order they run.
+func (s *server) streamInterceptors() []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{
+		streamErrorCodeInterceptor,
+		s.streamAuthInterceptor,
+		s.streamLimitInterceptor,
+		s.streamAuditInterceptor,
+		s.streamNetworkNameInterceptor,
+	}
+}
+
+// Returns the listener for the gRPC server, which is a unix domain
+// socket if [cfg.GRPCSocket] is set, or a TCP port otherwise.
+func newGRPCListener(cfg Config) (net.Listener, error) {
+	if cfg.GRPCSocket == "" {
+		return net.Listen("tcp", cfg.Port)
+	}
+	// remove a socket file left behind by a previous server
+	if err := os.Remove(cfg.GRPCSocket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return net.Listen("unix", cfg.GRPCSocket)
+}
+
+// Returns the target the gRPC gateway uses to dial the gRPC server.
+func (s *server) grpcTarget() string {
+	if s.cfg.GRPCSocket != "" {
+		return "unix:" + s.cfg.GRPCSocket
+	}
+	return "0.0.0.0" + s.cfg.Port
+}
+
 // Blocking call until server listeners return.
 func (s *server) Run(rootCtx context.Context) (err error) {
 	s.rootCtx, s.rootCancel = context.WithCancel(rootCtx)
 
 	rpcpb.RegisterPingServiceServer(s.gRPCServer, s)
 	rpcpb.RegisterControlServiceServer(s.gRPCServer, s)
+	rpcpb.RegisterKeyServiceServer(s.gRPCServer, s)
+
+	if s.cfg.DataDirMaxSize > 0 || s.cfg.DataDirMaxAge > 0 {
+		go s.runDataDirJanitor()
+	}
+	if len(s.logSinks) > 0 {
+		go s.runLogForwarder()
+	}
 
 	gRPCErrChan := make(chan error)
 	go func() {
-		s.log.Info("serving gRPC server", zap.String("port", s.cfg.Port))
+		s.log.Info("serving gRPC server", zap.String("address", s.ln.Addr().String()))
 		gRPCErrChan <- s.gRPCServer.Serve(s.ln)
 	}()
 
@@ -172,11 +429,11 @@ func (s *server) Run(rootCtx context.Context) (err error) {
 	} else {
 		// Set up gRPC gateway to allow for HTTP requests to [s.gRPCServer].
 		go func() {
-			s.log.Info("dialing gRPC server for gRPC gateway", zap.String("port", s.cfg.Port))
+			s.log.Info("dialing gRPC server for gRPC gateway", zap.String("target", s.grpcTarget()))
 			ctx, cancel := context.WithTimeout(rootCtx, s.cfg.DialTimeout)
 			gwConn, err := grpc.DialContext(
 				ctx,
-				"0.0.0.0"+s.cfg.Port,
+				s.grpcTarget(),
 				grpc.WithBlock(),
 				grpc.WithTransportCredentials(insecure.NewCredentials()),
 			)
@@ -195,6 +452,10 @@ func (s *server) Run(rootCtx context.Context) (err error) {
 				gwErrChan <- err
 				return
 			}
+			if err := rpcpb.RegisterKeyServiceHandler(rootCtx, s.gwMux, gwConn); err != nil {
+				gwErrChan <- err
+				return
+			}
 
 			s.log.Info("serving gRPC gateway", zap.String("port", s.cfg.GwPort))
 			gwErrChan <- s.gwServer.ListenAndServe()
@@ -232,9 +493,14 @@ func (s *server) Run(rootCtx context.Context) (err error) {
 		<-gRPCErrChan // Wait for [s.gRPCServer.Serve] to return.
 	}
 
-	// Grab lock to ensure [s.network] isn't being used.
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	// Abort the in-flight operations, as their contexts derive from [s.rootCtx],
+	// so they release [s.opMu] instead of running until their timeouts.
+	s.rootCancel()
+	s.renewals.Wait()
+
+	// Grab lock to ensure [s.network] isn't being changed.
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	if s.network != nil {
 		// Close the network.
@@ -242,7 +508,12 @@ func (s *server) Run(rootCtx context.Context) (err error) {
 		s.log.Warn("network stopped")
 	}
 
-	s.rootCancel()
+	if s.auditLog != nil {
+		if err := s.auditLog.close(); err != nil {
+			s.log.Warn("failed to close audit log", zap.Error(err))
+		}
+	}
+
 	return err
 }
 
@@ -251,41 +522,172 @@ func (s *server) Ping(context.Context, *rpcpb.PingRequest) (*rpcpb.PingResponse,
 	return &rpcpb.PingResponse{Pid: int32(os.Getpid())}, nil
 }
 
-func (s *server) RPCVersion(context.Context, *rpcpb.RPCVersionRequest) (*rpcpb.RPCVersionResponse, error) {
-	s.log.Debug("RPCVersion")
+func (s *server) RPCVersion(_ context.Context, req *rpcpb.RPCVersionRequest) (*rpcpb.RPCVersionResponse, error) {
+	s.log.Debug("RPCVersion", zap.Uint32("client-version", req.ClientVersion))
 
-	return &rpcpb.RPCVersionResponse{Version: RPCVersion}, nil
+	if req.ClientVersion != 0 && req.ClientVersion < MinClientRPCVersion {
+		s.log.Warn("RPC client version is older than the min supported one",
+			zap.Uint32("client-version", req.ClientVersion),
+			zap.Uint32("min-client-version", MinClientRPCVersion),
+		)
+	}
+	return &rpcpb.RPCVersionResponse{
+		Version:          RPCVersion,
+		MinClientVersion: MinClientRPCVersion,
+		Capabilities:     rpcCapabilities,
+	}, nil
 }
 
-func (s *server) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.StartResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	// If [network] is already populated, the network has already been started.
-	if s.network != nil {
-		return nil, ErrAlreadyBootstrapped
-	}
+func (s *server) Start(ctx context.Context, req *rpcpb.StartRequest) (*rpcpb.StartResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	// Set default values for [req.NumNodes] if not given.
 	if req.NumNodes == nil {
 		n := DefaultNodes
 		req.NumNodes = &n
 	}
+
+	// Expand node groups before comparing with a running network's request.
+	customNodeConfigs, err := expandNodeGroups(s.log, req.GetCustomNodeConfigs(), req.GetNodeGroups())
+	if err != nil {
+		return nil, invalidArgumentError(err)
+	}
+	req.CustomNodeConfigs = customNodeConfigs
+
+	if req.GetValidateOnly() {
+		if err := s.validateStart(req); err != nil {
+			return nil, err
+		}
+		return &rpcpb.StartResponse{}, nil
+	}
+
+	// If [network] is already populated, the network has already been started.
+	if s.network != nil {
+		return s.startRunningNetwork(ctx, req)
+	}
 	if *req.NumNodes < MinNodes {
 		return nil, ErrNotEnoughNodesForStart
 	}
+	if *req.NumNodes > MaxNodes {
+		return nil, invalidArgumentError(fmt.Errorf("%w: %d nodes, the maximum is %d", ErrTooManyNodes, *req.NumNodes, MaxNodes))
+	}
 
 	if err := utils.CheckExecPath(req.GetExecPath()); err != nil {
 		return nil, err
 	}
 
+	upgradeConfigs, err := mergeUpgradeSpecs(req.GetUpgradeConfigs(), req.GetUpgradeSpecs())
+	if err != nil {
+		return nil, err
+	}
+
+	networkAccess, err := getNetworkAccess(req.GetNetworkAccess())
+	if err != nil {
+		return nil, err
+	}
+
+	portRange, err := getNetworkPortRange(req.GetPortRange())
+	if err != nil {
+		return nil, err
+	}
+
+	dirLayout, err := getNetworkDirLayout(req.GetDirLayout(), s.cfg.NodeDirsAllowed)
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := getNetworkBackend(req.GetBackend())
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(req.GetTtl())
+	if ttl < 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTTL, ttl)
+	}
+
+	// nodes joining an external network take longer to become healthy,
+	// and have no funded key to create blockchains with
+	healthyTimeout := waitForHealthyTimeout
+	externalNetwork := getNetworkExternalNetwork(req.GetExternalNetwork())
+	if externalNetwork != nil {
+		if len(req.GetBlockchainSpecs()) > 0 {
+			return nil, ErrExternalNetworkChains
+		}
+		if timeout := time.Duration(req.GetExternalNetwork().GetBootstrapTimeout()); timeout > 0 {
+			healthyTimeout = timeout
+		}
+	}
+
+	forkSpec, err := getNetworkForkSpec(req.GetFork())
+	if err != nil {
+		return nil, err
+	}
+	if forkSpec != nil && externalNetwork != nil {
+		return nil, fmt.Errorf("%w: an external network can't be forked", ErrInvalidForkSpec)
+	}
+	cChainStatePath := req.GetCChainStatePath()
+	if cChainStatePath != "" {
+		if externalNetwork != nil {
+			return nil, ErrExternalNetworkState
+		}
+		// the network has no root dir yet
+		cChainStatePath, err = getCChainStatePath(cChainStatePath, "", s.cfg.SnapshotsDir)
+		if err != nil {
+			return nil, invalidArgumentError(err)
+		}
+	}
+	networkID := req.GetNetworkId()
+	if networkID != 0 {
+		if externalNetwork != nil {
+			return nil, ErrExternalNetworkID
+		}
+		if err := network.CheckNetworkID(networkID); err != nil {
+			return nil, err
+		}
+		if forkSpec != nil {
+			if forkSpec.NetworkID != 0 && forkSpec.NetworkID != networkID {
+				return nil, fmt.Errorf("%w: fork network ID %d differs from network ID %d", ErrInvalidForkSpec, forkSpec.NetworkID, networkID)
+			}
+			forkSpec.NetworkID = networkID
+		}
+	}
+
+	txSigner, err := getNetworkTxSigner(req.GetTxSigner())
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GetStakingKeysStorage() {
+	case "", network.StakingKeysStorageFile, network.StakingKeysStorageMemory:
+	case network.StakingKeysStorageKeystore:
+		// the nodes are run by the server, so the passphrase is read from its env
+		if os.Getenv(constants.StakingKeystorePassphraseEnvVar) == "" {
+			return nil, fmt.Errorf("%w: no passphrase given on server env var %s", ErrInvalidKeysStorage, constants.StakingKeystorePassphraseEnvVar)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidKeysStorage, req.GetStakingKeysStorage())
+	}
+
+	stakingSpec, err := getNetworkStakingSpec(req.GetStakingSpec())
+	if err != nil {
+		return nil, invalidArgumentError(err)
+	}
+
 	pluginDir := req.GetPluginDir()
 	chainSpecs := []network.BlockchainSpec{}
 	if len(req.GetBlockchainSpecs()) > 0 {
+		if err := validationError(utils.LintBlockchainSpecs(req.GetBlockchainSpecs(), nil)); err != nil {
+			return nil, err
+		}
 		s.log.Info("plugin-dir:", zap.String("plugin-dir", pluginDir))
 		for _, spec := range req.GetBlockchainSpecs() {
-			chainSpec, err := getNetworkBlockchainSpec(s.log, spec, true, pluginDir)
+			chainSpec, err := getNetworkBlockchainSpec(s.log, spec, true, pluginDir, stakingSpec.GetValidationStartOffset())
 			if err != nil {
+				if errors.Is(err, ErrInvalidDuration) {
+					return nil, invalidArgumentError(err)
+				}
 				return nil, err
 			}
 			chainSpecs = append(chainSpecs, chainSpec)
@@ -293,14 +695,12 @@ func (s *server) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.Start
 	}
 
 	var (
-		execPath          = req.GetExecPath()
-		numNodes          = req.GetNumNodes()
-		trackSubnets      = req.GetWhitelistedSubnets()
-		rootDataDir       = req.GetRootDataDir()
-		pid               = int32(os.Getpid())
-		globalNodeConfig  = req.GetGlobalNodeConfig()
-		customNodeConfigs = req.GetCustomNodeConfigs()
-		err               error
+		execPath         = req.GetExecPath()
+		numNodes         = req.GetNumNodes()
+		trackSubnets     = req.GetWhitelistedSubnets()
+		rootDataDir      = req.GetRootDataDir()
+		pid              = int32(os.Getpid())
+		globalNodeConfig = req.GetGlobalNodeConfig()
 	)
 
 	if len(rootDataDir) == 0 {
@@ -310,9 +710,18 @@ func (s *server) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.Start
 			return nil, err
 		}
 	}
-	rootDataDir = filepath.Join(rootDataDir, networkRootDirPrefix)
-	rootDataDir, err = utils.MkDirWithTimestamp(rootDataDir)
-	if err != nil {
+	if _, err := local.ReadNetworkState(rootDataDir); err == nil {
+		// the root data dir of a previous network, e.g. one left by a
+		// server restart, is reused with its dbs and network state
+		s.log.Info("reusing network root data dir", zap.String("root-data-dir", rootDataDir))
+	} else {
+		rootDataDir = filepath.Join(rootDataDir, networkRootDirPrefix)
+		rootDataDir, err = utils.MkDirWithTimestamp(rootDataDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := markNetworkDir(rootDataDir); err != nil {
 		return nil, err
 	}
 
@@ -321,12 +730,7 @@ func (s *server) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.Start
 		numNodes = uint32(len(customNodeConfigs))
 	}
 
-	s.clusterInfo = &rpcpb.ClusterInfo{
-		Pid:         pid,
-		RootDataDir: rootDataDir,
-	}
-
-	s.network, err = newLocalNetwork(localNetworkOptions{
+	nw, err := newLocalNetwork(localNetworkOptions{
 		execPath:            execPath,
 		rootDataDir:         rootDataDir,
 		numNodes:            numNodes,
@@ -336,16 +740,35 @@ func (s *server) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.Start
 		globalNodeConfig:    globalNodeConfig,
 		customNodeConfigs:   customNodeConfigs,
 		chainConfigs:        req.ChainConfigs,
-		upgradeConfigs:      req.UpgradeConfigs,
+		upgradeConfigs:      upgradeConfigs,
 		subnetConfigs:       req.SubnetConfigs,
 		logLevel:            s.cfg.LogLevel,
 		reassignPortsIfUsed: req.GetReassignPortsIfUsed(),
 		dynamicPorts:        req.GetDynamicPorts(),
+		stakingSpec:         stakingSpec,
+		networkAccess:       networkAccess,
+		portRange:           portRange,
+		logsShardSize:       int(req.GetLogsShardSize()),
+		dirLayout:           dirLayout,
+		seed:                req.GetSeed(),
+		networkID:           networkID,
+		txSigner:            txSigner,
+		stakingKeysStorage:  req.GetStakingKeysStorage(),
+		backend:             backend,
 		snapshotsDir:        s.cfg.SnapshotsDir,
+		externalNetwork:     externalNetwork,
+		externalGenesis:     req.GetExternalNetwork().GetGenesis(),
+		fork:                forkSpec,
+		cChainStatePath:     cChainStatePath,
+		onProgress:          s.progress.publish,
 	})
 	if err != nil {
 		return nil, err
 	}
+	s.setNetwork(ctx, nw, &rpcpb.ClusterInfo{
+		Pid:         pid,
+		RootDataDir: rootDataDir,
+	})
 
 	s.log.Info("starting",
 		zap.String("exec-path", execPath),
@@ -356,9 +779,11 @@ func (s *server) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.Start
 		zap.String("plugin-dir", pluginDir),
 		zap.Any("chain-configs", req.ChainConfigs),
 		zap.String("global-node-config", globalNodeConfig),
+		zap.Int64("seed", req.GetSeed()),
+		zap.Uint32("network-id", networkID),
 	)
 
-	ctx, cancel := context.WithTimeout(context.Background(), waitForHealthyTimeout)
+	ctx, cancel := context.WithTimeout(s.rootCtx, healthyTimeout)
 	defer cancel()
 	if err := s.network.Start(ctx); err != nil {
 		s.log.Warn("start failed to complete", zap.Error(err))
@@ -366,7 +791,7 @@ func (s *server) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.Start
 		return nil, err
 	}
 
-	ctx, cancel = context.WithTimeout(context.Background(), waitForHealthyTimeout)
+	ctx, cancel = context.WithTimeout(s.rootCtx, healthyTimeout)
 	defer cancel()
 	chainIDs, err := s.network.CreateChains(ctx, chainSpecs)
 	if err != nil {
@@ -377,6 +802,13 @@ func (s *server) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.Start
 	s.updateClusterInfo()
 	s.log.Info("network healthy")
 
+	if ttl > 0 {
+		s.scheduleNetworkStop(s.network, ttl)
+	}
+	s.mu.Lock()
+	s.startRequest = proto.Clone(req).(*rpcpb.StartRequest)
+	s.mu.Unlock()
+
 	strChainIDs := []string{}
 	for _, chainID := range chainIDs {
 		strChainIDs = append(strChainIDs, chainID.String())
@@ -389,22 +821,72 @@ func (s *server) Start(_ context.Context, req *rpcpb.StartRequest) (*rpcpb.Start
 	return &rpcpb.StartResponse{ClusterInfo: clusterInfo, ChainIds: strChainIDs}, nil
 }
 
-// Asssumes [s.mu] is held.
+// Updates [s.clusterInfo] with the state of [s.network]. The state is
+// copied, so the RPCs reading [s.clusterInfo] don't race with the
+// operations changing the network afterwards.
+// Assumes [s.opMu] is held.
 func (s *server) updateClusterInfo() {
 	if s.network == nil {
 		// stop may have been called
 		return
 	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setClusterNodeInfos()
+
+	s.network.lock.Lock()
 	s.clusterInfo.Healthy = true
-	s.clusterInfo.NodeNames = maps.Keys(s.network.nodeInfos)
-	sort.Strings(s.clusterInfo.NodeNames)
-	s.clusterInfo.NodeInfos = s.network.nodeInfos
+	s.clusterInfo.NetworkId = s.network.networkID
+	s.clusterInfo.Hrp = luxd_constants.GetHRP(s.network.networkID)
 	s.clusterInfo.CustomChainsHealthy = true
 	s.clusterInfo.CustomChains = make(map[string]*rpcpb.CustomChainInfo)
 	for chainID, chainInfo := range s.network.customChainIDToInfo {
-		s.clusterInfo.CustomChains[chainID.String()] = chainInfo.info
+		s.clusterInfo.CustomChains[chainID.String()] = proto.Clone(chainInfo.info).(*rpcpb.CustomChainInfo)
 	}
-	s.clusterInfo.Subnets = s.network.subnets
+	s.clusterInfo.Subnets = cloneProtoMap(s.network.subnets)
+	s.clusterInfo.Uptimes = cloneProtoMap(s.network.uptimes)
+	fundedKey, err := s.network.getManifestFundedKey()
+	s.network.lock.Unlock()
+	if err != nil {
+		s.log.Warn("couldn't get network funded key", zap.Error(err))
+	}
+	s.fundedKey = fundedKey
+
+	s.writeManifest()
+}
+
+// Updates the nodes of [s.clusterInfo] with the ones of [s.network].
+// Assumes [s.opMu] is held.
+func (s *server) updateClusterNodeInfos() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setClusterNodeInfos()
+	s.writeManifest()
+}
+
+// The node infos are copied holding [s.network.lock], as the operations on
+// other nodes may be updating them.
+// Assumes [s.opMu] and [s.mu] are held.
+func (s *server) setClusterNodeInfos() {
+	nodeInfos := s.network.getNodeInfos()
+	s.clusterInfo.NodeNames = maps.Keys(nodeInfos)
+	sort.Strings(s.clusterInfo.NodeNames)
+	s.clusterInfo.NodeInfos = nodeInfos
+	s.procSampler.retain(s.clusterInfo.NodeInfos)
+}
+
+// Marks [s.clusterInfo] unhealthy, before an operation that can make the
+// network unhealthy.
+// Assumes [s.opMu] is held.
+func (s *server) setClusterUnhealthy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clusterInfo.Healthy = false
+	s.clusterInfo.CustomChainsHealthy = false
+	s.writeManifest()
 }
 
 // wait until some of this conditions is met:
@@ -462,8 +944,8 @@ func (s *server) CreateBlockchains(
 	_ context.Context,
 	req *rpcpb.CreateBlockchainsRequest,
 ) (*rpcpb.CreateBlockchainsResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	if s.network == nil {
 		return nil, ErrNotBootstrapped
@@ -471,14 +953,27 @@ func (s *server) CreateBlockchains(
 
 	s.log.Debug("CreateBlockchains")
 
+	if req.GetValidateOnly() {
+		if err := s.validateCreateBlockchains(req); err != nil {
+			return nil, err
+		}
+		return &rpcpb.CreateBlockchainsResponse{}, nil
+	}
+
 	if len(req.GetBlockchainSpecs()) == 0 {
 		return nil, ErrNoBlockchainSpec
 	}
+	if err := validationError(utils.LintBlockchainSpecs(req.GetBlockchainSpecs(), nil)); err != nil {
+		return nil, err
+	}
 
 	chainSpecs := []network.BlockchainSpec{}
 	for _, spec := range req.GetBlockchainSpecs() {
-		chainSpec, err := getNetworkBlockchainSpec(s.log, spec, false, s.network.pluginDir)
+		chainSpec, err := getNetworkBlockchainSpec(s.log, spec, false, s.network.pluginDir, s.network.options.stakingSpec.GetValidationStartOffset())
 		if err != nil {
+			if errors.Is(err, ErrInvalidDuration) {
+				return nil, invalidArgumentError(err)
+			}
 			return nil, err
 		}
 		chainSpecs = append(chainSpecs, chainSpec)
@@ -495,10 +990,9 @@ func (s *server) CreateBlockchains(
 		}
 	}
 
-	s.clusterInfo.Healthy = false
-	s.clusterInfo.CustomChainsHealthy = false
+	s.setClusterUnhealthy()
 
-	ctx, cancel := context.WithTimeout(context.Background(), waitForHealthyTimeout)
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
 	defer cancel()
 	chainIDs, err := s.network.CreateChains(ctx, chainSpecs)
 	if err != nil {
@@ -526,8 +1020,8 @@ func (s *server) AddPermissionlessValidator(
 	_ context.Context,
 	req *rpcpb.AddPermissionlessValidatorRequest,
 ) (*rpcpb.AddPermissionlessValidatorResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	if s.network == nil {
 		return nil, ErrNotBootstrapped
@@ -553,19 +1047,23 @@ func (s *server) AddPermissionlessValidator(
 	subnetsSet.Add(maps.Keys(s.clusterInfo.Subnets)...)
 
 	for _, validatorSpec := range validatorSpecList {
+		// an empty subnet id adds a primary network validator
 		if validatorSpec.SubnetID == "" {
-			return nil, ErrNoSubnetID
-		} else if !subnetsSet.Contains(validatorSpec.SubnetID) {
+			continue
+		}
+		if !subnetsSet.Contains(validatorSpec.SubnetID) {
 			return nil, fmt.Errorf("subnet id %q does not exist", validatorSpec.SubnetID)
 		}
+		if validatorSpec.AssetID == "" {
+			return nil, fmt.Errorf("no asset id given for the validator spec of %s on subnet %s", validatorSpec.NodeName, validatorSpec.SubnetID)
+		}
 	}
 
-	s.clusterInfo.Healthy = false
-	s.clusterInfo.CustomChainsHealthy = false
+	s.setClusterUnhealthy()
 
-	ctx, cancel := context.WithTimeout(context.Background(), waitForHealthyTimeout)
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
 	defer cancel()
-	err := s.network.AddPermissionlessValidators(ctx, validatorSpecList)
+	added, err := s.network.AddPermissionlessValidators(ctx, validatorSpecList)
 
 	s.updateClusterInfo()
 
@@ -576,110 +1074,756 @@ func (s *server) AddPermissionlessValidator(
 
 	s.log.Info("successfully added permissionless validator")
 
+	validators := make([]*rpcpb.AddedPermissionlessValidator, len(added))
+	for i, v := range added {
+		validators[i] = &rpcpb.AddedPermissionlessValidator{
+			NodeName:  v.NodeName,
+			SubnetId:  v.SubnetID.String(),
+			TxId:      v.TxID.String(),
+			StartTime: v.StartTime.Unix(),
+			EndTime:   v.EndTime.Unix(),
+			Weight:    v.Weight,
+		}
+	}
+
 	clusterInfo, err := deepCopy(s.clusterInfo)
 	if err != nil {
 		return nil, err
 	}
-	return &rpcpb.AddPermissionlessValidatorResponse{ClusterInfo: clusterInfo}, nil
+	return &rpcpb.AddPermissionlessValidatorResponse{ClusterInfo: clusterInfo, Validators: validators}, nil
 }
 
-func (s *server) RemoveSubnetValidator(
-	_ context.Context,
-	req *rpcpb.RemoveSubnetValidatorRequest,
-) (*rpcpb.RemoveSubnetValidatorResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if s.network == nil {
+func (s *server) ListBlockchains(context.Context, *rpcpb.ListBlockchainsRequest) (*rpcpb.ListBlockchainsResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
 		return nil, ErrNotBootstrapped
 	}
 
-	s.log.Debug("RemoveSubnetValidator")
+	s.log.Debug("ListBlockchains")
 
-	if len(req.GetValidatorSpec()) == 0 {
-		return nil, ErrNoValidatorSpec
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	blockchains, err := nw.ListBlockchains(ctx, s.getCustomChains(), s.getSubnets())
+	if err != nil {
+		return nil, err
 	}
+	return &rpcpb.ListBlockchainsResponse{Blockchains: blockchains}, nil
+}
 
-	validatorSpecList := []network.RemoveSubnetValidatorSpec{}
-	for _, spec := range req.GetValidatorSpec() {
-		validatorSpec := getRemoveSubnetValidatorSpec(spec)
-		validatorSpecList = append(validatorSpecList, validatorSpec)
+func (s *server) GetBlockchainStatus(_ context.Context, req *rpcpb.GetBlockchainStatusRequest) (*rpcpb.GetBlockchainStatusResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
 	}
 
-	// check that the given subnets exist
-	subnetsSet := set.Set[string]{}
-	subnetsSet.Add(maps.Keys(s.clusterInfo.Subnets)...)
+	s.log.Debug("GetBlockchainStatus", zap.String("chain", req.GetChain()))
 
-	for _, validatorSpec := range validatorSpecList {
-		if validatorSpec.SubnetID == "" {
-			return nil, ErrNoSubnetID
-		} else if !subnetsSet.Contains(validatorSpec.SubnetID) {
-			return nil, fmt.Errorf("subnet id %q does not exist", validatorSpec.SubnetID)
-		}
+	if req.GetChain() == "" {
+		return nil, ErrNoBlockchain
 	}
 
-	s.clusterInfo.Healthy = false
-	s.clusterInfo.CustomChainsHealthy = false
-
-	ctx, cancel := context.WithTimeout(context.Background(), waitForHealthyTimeout)
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
 	defer cancel()
-	err := s.network.RemoveSubnetValidator(ctx, validatorSpecList)
+	blockchain, err := nw.GetBlockchainStatus(ctx, s.getCustomChains(), s.getSubnets(), req.GetChain())
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.GetBlockchainStatusResponse{Blockchain: blockchain}, nil
+}
 
-	s.updateClusterInfo()
+func (s *server) AddBlockchainAlias(_ context.Context, req *rpcpb.AddBlockchainAliasRequest) (*rpcpb.AddBlockchainAliasResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("AddBlockchainAlias", zap.String("chain-id", req.ChainId), zap.String("alias", req.Alias))
+
+	chainID, err := ids.FromString(req.ChainId)
 	if err != nil {
-		s.log.Error("failed to remove subnet validator", zap.Error(err))
 		return nil, err
 	}
+	if req.Alias == "" {
+		return nil, ErrNoAlias
+	}
 
-	s.log.Info("successfully removed subnet validator")
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	if err := s.network.nw.AddBlockchainAlias(ctx, chainID, req.Alias); err != nil {
+		return nil, err
+	}
 
 	clusterInfo, err := deepCopy(s.clusterInfo)
 	if err != nil {
 		return nil, err
 	}
-	return &rpcpb.RemoveSubnetValidatorResponse{ClusterInfo: clusterInfo}, nil
+	return &rpcpb.AddBlockchainAliasResponse{ClusterInfo: clusterInfo}, nil
 }
 
-func (s *server) TransformElasticSubnets(
-	_ context.Context,
-	req *rpcpb.TransformElasticSubnetsRequest,
-) (*rpcpb.TransformElasticSubnetsResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *server) RemoveBlockchainAlias(_ context.Context, req *rpcpb.RemoveBlockchainAliasRequest) (*rpcpb.RemoveBlockchainAliasResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	if s.network == nil {
 		return nil, ErrNotBootstrapped
 	}
 
-	s.log.Debug("TransformElasticSubnet")
+	s.log.Debug("RemoveBlockchainAlias", zap.String("chain-id", req.ChainId), zap.String("alias", req.Alias))
 
-	if len(req.GetElasticSubnetSpec()) == 0 {
-		return nil, ErrNoElasticSubnetSpec
+	chainID, err := ids.FromString(req.ChainId)
+	if err != nil {
+		return nil, err
 	}
-
-	elasticSubnetSpecList := []network.ElasticSubnetSpec{}
-	for _, spec := range req.GetElasticSubnetSpec() {
-		elasticSubnetSpec := getNetworkElasticSubnetSpec(spec)
-		elasticSubnetSpecList = append(elasticSubnetSpecList, elasticSubnetSpec)
+	if req.Alias == "" {
+		return nil, ErrNoAlias
 	}
 
-	// check that the given subnets exist
-	subnetsSet := set.Set[string]{}
-	subnetsSet.Add(maps.Keys(s.clusterInfo.Subnets)...)
+	s.setClusterUnhealthy()
 
-	for _, elasticSubnetSpec := range elasticSubnetSpecList {
-		if elasticSubnetSpec.SubnetID == nil {
-			return nil, ErrNoSubnetID
-		} else if !subnetsSet.Contains(*elasticSubnetSpec.SubnetID) {
-			return nil, fmt.Errorf("subnet id %q does not exist", *elasticSubnetSpec.SubnetID)
-		}
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	err = s.network.nw.RemoveBlockchainAlias(ctx, chainID, req.Alias)
+	if err == nil {
+		err = s.network.AwaitHealthyAndUpdateNetworkInfo(ctx)
 	}
 
-	s.clusterInfo.Healthy = false
-	s.clusterInfo.CustomChainsHealthy = false
+	s.updateClusterInfo()
+
+	if err != nil {
+		s.log.Error("failed to remove blockchain alias", zap.Error(err))
+		return nil, err
+	}
+
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.RemoveBlockchainAliasResponse{ClusterInfo: clusterInfo}, nil
+}
+
+func (s *server) AddVMAlias(_ context.Context, req *rpcpb.AddVMAliasRequest) (*rpcpb.AddVMAliasResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("AddVMAlias", zap.String("vm-id", req.VmId), zap.String("alias", req.Alias))
+
+	vmID, err := ids.FromString(req.VmId)
+	if err != nil {
+		return nil, err
+	}
+	if req.Alias == "" {
+		return nil, ErrNoAlias
+	}
+
+	s.setClusterUnhealthy()
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	err = s.network.nw.AddVMAlias(ctx, vmID, req.Alias)
+	if err == nil {
+		err = s.network.AwaitHealthyAndUpdateNetworkInfo(ctx)
+	}
+
+	s.updateClusterInfo()
+
+	if err != nil {
+		s.log.Error("failed to add vm alias", zap.Error(err))
+		return nil, err
+	}
+
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.AddVMAliasResponse{ClusterInfo: clusterInfo}, nil
+}
+
+func (s *server) TransferSubnetOwnership(
+	_ context.Context,
+	req *rpcpb.TransferSubnetOwnershipRequest,
+) (*rpcpb.TransferSubnetOwnershipResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("TransferSubnetOwnership")
+
+	if len(req.GetTransferSpecs()) == 0 {
+		return nil, ErrNoTransferSpec
+	}
+
+	// check that the given subnets exist
+	subnetsSet := set.Set[string]{}
+	subnetsSet.Add(maps.Keys(s.clusterInfo.Subnets)...)
+
+	transferSpecs := []network.TransferSubnetOwnershipSpec{}
+	for _, spec := range req.GetTransferSpecs() {
+		if spec.SubnetId == "" {
+			return nil, ErrNoSubnetID
+		} else if !subnetsSet.Contains(spec.SubnetId) {
+			return nil, fmt.Errorf("subnet id %q does not exist", spec.SubnetId)
+		}
+		transferSpecs = append(transferSpecs, network.TransferSubnetOwnershipSpec{
+			SubnetID:  spec.SubnetId,
+			Owners:    spec.Owners,
+			Threshold: spec.Threshold,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	txIDs, err := s.network.nw.TransferSubnetOwnership(ctx, transferSpecs)
+	if err != nil {
+		s.log.Error("failed to transfer subnet ownership", zap.Error(err))
+		return nil, err
+	}
+
+	s.log.Info("successfully transferred subnet ownership")
+
+	txIDsStr := make([]string, len(txIDs))
+	for i, txID := range txIDs {
+		txIDsStr[i] = txID.String()
+	}
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.TransferSubnetOwnershipResponse{ClusterInfo: clusterInfo, TxIds: txIDsStr}, nil
+}
+
+func (s *server) CreateAsset(
+	_ context.Context,
+	req *rpcpb.CreateAssetRequest,
+) (*rpcpb.CreateAssetResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("CreateAsset", zap.String("name", req.GetName()), zap.Bool("nft", req.GetNft()))
+
+	assetSpec, err := getAssetSpec(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	assetID, err := s.network.nw.CreateAsset(ctx, assetSpec)
+	if err != nil {
+		s.log.Error("failed to create asset", zap.Error(err))
+		return nil, err
+	}
+
+	s.log.Info("successfully created asset", zap.String("asset-ID", assetID.String()))
+
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.CreateAssetResponse{ClusterInfo: clusterInfo, AssetId: assetID.String()}, nil
+}
+
+func (s *server) Transfer(
+	_ context.Context,
+	req *rpcpb.TransferRequest,
+) (*rpcpb.TransferResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("Transfer", zap.String("to", req.GetTo()), zap.Uint64("amount", req.GetAmount()))
+
+	if req.GetTo() == "" {
+		return nil, ErrNoAddress
+	}
+	if req.GetAmount() == 0 {
+		return nil, ErrZeroAmount
+	}
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	txID, err := s.network.nw.Transfer(ctx, network.TransferSpec{
+		To:     req.GetTo(),
+		Amount: req.GetAmount(),
+	})
+	if err != nil {
+		s.log.Error("failed to transfer funds", zap.Error(err))
+		return nil, err
+	}
+
+	s.log.Info("successfully transferred funds", zap.String("tx-ID", txID.String()))
+
+	clusterInfo, err := s.copyClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.TransferResponse{ClusterInfo: clusterInfo, TxId: txID.String()}, nil
+}
+
+func (s *server) Stake(
+	_ context.Context,
+	req *rpcpb.StakeRequest,
+) (*rpcpb.StakeResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("Stake", zap.String("node-name", req.GetNodeName()), zap.Bool("delegate", req.GetDelegate()))
+
+	// node operations update [s.clusterInfo] holding only [s.mu]
+	s.mu.RLock()
+	_, ok := s.clusterInfo.NodeInfos[req.GetNodeName()]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, req.GetNodeName())
+	}
+
+	duration, err := getValidationDuration("stake duration", req.GetDuration(), s.network.options.stakingSpec.GetValidationStartOffset())
+	if err != nil {
+		return nil, invalidArgumentError(err)
+	}
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	txID, err := s.network.nw.Stake(ctx, network.StakeSpec{
+		NodeName:      req.GetNodeName(),
+		Amount:        req.GetAmount(),
+		Delegate:      req.GetDelegate(),
+		Duration:      duration,
+		RewardAddress: req.GetRewardAddress(),
+	})
+	if err != nil {
+		s.log.Error("failed to stake", zap.Error(err))
+		return nil, err
+	}
+
+	s.log.Info("successfully staked", zap.String("node-name", req.GetNodeName()), zap.String("tx-ID", txID.String()))
+
+	clusterInfo, err := s.copyClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.StakeResponse{ClusterInfo: clusterInfo, TxId: txID.String()}, nil
+}
+
+// builds the asset spec of [req], checking it can be issued
+func getAssetSpec(req *rpcpb.CreateAssetRequest) (network.AssetSpec, error) {
+	if req.GetName() == "" || req.GetSymbol() == "" {
+		return network.AssetSpec{}, fmt.Errorf("%w: name and symbol are required", ErrInvalidAssetSpec)
+	}
+	if req.GetDenomination() > math.MaxUint8 {
+		return network.AssetSpec{}, fmt.Errorf("%w: denomination %d is too large", ErrInvalidAssetSpec, req.GetDenomination())
+	}
+	if len(req.GetHolders()) == 0 {
+		return network.AssetSpec{}, fmt.Errorf("%w: no holders given", ErrInvalidAssetSpec)
+	}
+	holders := make([]network.AssetHolder, len(req.GetHolders()))
+	for i, holder := range req.GetHolders() {
+		switch {
+		case req.GetNft() && len(holder.GetPayload()) > nftfx.MaxPayloadSize:
+			return network.AssetSpec{}, fmt.Errorf("%w: payload of holder %d is larger than %d bytes", ErrInvalidAssetSpec, i, nftfx.MaxPayloadSize)
+		case !req.GetNft() && holder.GetAmount() == 0:
+			return network.AssetSpec{}, fmt.Errorf("%w: zero amount for holder %d", ErrInvalidAssetSpec, i)
+		case int(holder.GetThreshold()) > len(holder.GetOwners()):
+			return network.AssetSpec{}, fmt.Errorf("%w: threshold of holder %d is greater than its number of owners", ErrInvalidAssetSpec, i)
+		}
+		holders[i] = network.AssetHolder{
+			Owners:    holder.GetOwners(),
+			Threshold: holder.GetThreshold(),
+			Amount:    holder.GetAmount(),
+			Payload:   holder.GetPayload(),
+		}
+	}
+	return network.AssetSpec{
+		Name:         req.GetName(),
+		Symbol:       req.GetSymbol(),
+		Denomination: byte(req.GetDenomination()),
+		NFT:          req.GetNft(),
+		Holders:      holders,
+	}, nil
+}
+
+func (s *server) GetUptimes(
+	_ context.Context,
+	req *rpcpb.GetUptimesRequest,
+) (*rpcpb.GetUptimesResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("GetUptimes")
+
+	subnetID := luxd_constants.PrimaryNetworkID
+	if req.GetSubnetId() != "" {
+		var err error
+		subnetID, err = ids.FromString(req.GetSubnetId())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	uptimes, err := nw.GetUptimes(ctx, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.GetUptimesResponse{Uptimes: uptimes}, nil
+}
+
+func (s *server) GetStakingRewards(
+	_ context.Context,
+	req *rpcpb.GetStakingRewardsRequest,
+) (*rpcpb.GetStakingRewardsResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("GetStakingRewards", zap.String("subnet-id", req.GetSubnetId()))
+
+	subnetID := luxd_constants.PrimaryNetworkID
+	if req.GetSubnetId() != "" {
+		var err error
+		subnetID, err = ids.FromString(req.GetSubnetId())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	validators, err := nw.nw.GetStakingRewards(ctx, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &rpcpb.GetStakingRewardsResponse{}
+	for _, validator := range validators {
+		validatorStake := &rpcpb.ValidatorStake{
+			NodeName:               validator.NodeName,
+			NodeId:                 validator.NodeID.String(),
+			TxId:                   validator.TxID.String(),
+			StartTime:              validator.StartTime.Unix(),
+			EndTime:                validator.EndTime.Unix(),
+			Weight:                 validator.Weight,
+			PotentialReward:        validator.PotentialReward,
+			AccruedDelegateeReward: validator.AccruedDelegateeReward,
+			DelegationFee:          validator.DelegationFee,
+		}
+		resp.TotalPotentialReward += validator.PotentialReward
+		for _, delegator := range validator.Delegators {
+			validatorStake.Delegators = append(validatorStake.Delegators, &rpcpb.DelegatorStake{
+				TxId:            delegator.TxID.String(),
+				StartTime:       delegator.StartTime.Unix(),
+				EndTime:         delegator.EndTime.Unix(),
+				Weight:          delegator.Weight,
+				PotentialReward: delegator.PotentialReward,
+			})
+			resp.TotalPotentialReward += delegator.PotentialReward
+		}
+		resp.Validators = append(resp.Validators, validatorStake)
+	}
+	return resp, nil
+}
+
+func (s *server) GetElasticSubnet(
+	_ context.Context,
+	req *rpcpb.GetElasticSubnetRequest,
+) (*rpcpb.GetElasticSubnetResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("GetElasticSubnet", zap.String("subnet-id", req.GetSubnetId()))
+
+	if req.GetSubnetId() == "" {
+		return nil, ErrNoSubnetID
+	}
+	subnetID, err := ids.FromString(req.GetSubnetId())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	elasticSubnet, err := nw.nw.GetElasticSubnet(ctx, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.GetElasticSubnetResponse{
+		ElasticSubnetId: elasticSubnet.ElasticSubnetID.String(),
+		AssetId:         elasticSubnet.AssetID.String(),
+		Spec:            getRPCElasticSubnetSpec(elasticSubnet.Spec),
+		StakingPool: &rpcpb.ElasticSubnetStakingPool{
+			CurrentSupply:   elasticSubnet.CurrentSupply,
+			NumValidators:   elasticSubnet.NumValidators,
+			ValidatorsStake: elasticSubnet.ValidatorsStake,
+			NumDelegators:   elasticSubnet.NumDelegators,
+			DelegatorsStake: elasticSubnet.DelegatorsStake,
+		},
+	}, nil
+}
+
+func (s *server) GetVersions(
+	_ context.Context,
+	_ *rpcpb.GetVersionsRequest,
+) (*rpcpb.GetVersionsResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("GetVersions")
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	nodeVersions, err := nw.GetVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.GetVersionsResponse{
+		NodeVersions: nodeVersions,
+		Consistent:   areVersionsConsistent(nodeVersions),
+	}, nil
+}
+
+func (s *server) WaitForValidatorRewards(
+	ctx context.Context,
+	req *rpcpb.WaitForValidatorRewardsRequest,
+) (*rpcpb.WaitForValidatorRewardsResponse, error) {
+	// the server lock is not held while waiting, as validation periods can be long
+	network := s.getNetwork()
+	if network == nil {
+		return nil, ErrNotBootstrapped
+	}
+	nw := network.nw
+
+	s.log.Debug("WaitForValidatorRewards", zap.String("node-name", req.GetNodeName()))
+
+	subnetID := luxd_constants.PrimaryNetworkID
+	if req.GetSubnetId() != "" {
+		var err error
+		subnetID, err = ids.FromString(req.GetSubnetId())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rewards, err := nw.WaitForValidatorRewards(ctx, req.GetNodeName(), subnetID)
+	if err != nil {
+		s.log.Error("failed to wait for validator rewards", zap.Error(err))
+		return nil, err
+	}
+
+	resp := &rpcpb.WaitForValidatorRewardsResponse{
+		TxId:            rewards.TxID.String(),
+		EndTime:         rewards.EndTime.Unix(),
+		PotentialReward: rewards.PotentialReward,
+	}
+	for _, utxo := range rewards.RewardUTXOs {
+		resp.RewardUtxos = append(resp.RewardUtxos, &rpcpb.RewardUTXO{
+			UtxoId:  utxo.UTXOID.String(),
+			AssetId: utxo.AssetID.String(),
+			Amount:  utxo.Amount,
+		})
+	}
+	return resp, nil
+}
+
+// Checks the validations can be renewed and marks their nodes as being
+// renewed, and renews them in the background as they end, long after the
+// request returns. Returns the expected renewals. The outcome of the
+// background renewals is reported as progress events.
+func (s *server) RenewPrimaryValidations(
+	ctx context.Context,
+	req *rpcpb.RenewPrimaryValidationsRequest,
+) (*rpcpb.RenewPrimaryValidationsResponse, error) {
+	// the server lock is not held while waiting, as validation periods can be long
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("RenewPrimaryValidations", zap.Strings("node-names", req.GetNodeNames()))
+
+	duration, err := getValidationDuration("renewal duration", req.GetDuration(), nw.options.stakingSpec.GetValidationStartOffset())
+	if err != nil {
+		return nil, invalidArgumentError(err)
+	}
+	renewSpec := network.RenewValidationSpec{
+		NodeNames: req.GetNodeNames(),
+		Amount:    req.GetAmount(),
+		Duration:  duration,
+	}
+	expected, renew, err := nw.nw.RenewPrimaryValidations(ctx, renewSpec)
+	if err != nil {
+		if errors.Is(err, network.ErrRenewalBelowQuorum) {
+			return nil, invalidArgumentError(err)
+		}
+		return nil, err
+	}
+
+	s.renewals.Add(1)
+	go s.renewPrimaryValidations(renew, renewSpec)
+
+	resp := &rpcpb.RenewPrimaryValidationsResponse{}
+	for _, v := range expected {
+		resp.Validators = append(resp.Validators, &rpcpb.AddedPermissionlessValidator{
+			NodeName:  v.NodeName,
+			SubnetId:  v.SubnetID.String(),
+			StartTime: v.StartTime.Unix(),
+			EndTime:   v.EndTime.Unix(),
+			Weight:    v.Weight,
+		})
+	}
+	return resp, nil
+}
+
+// Renews the validations of [renewSpec] with [renew] as they end, until
+// the server or the network is stopped, reporting the outcome as a
+// progress event, as the request returned long ago.
+func (s *server) renewPrimaryValidations(renew network.RenewFunc, renewSpec network.RenewValidationSpec) {
+	defer s.renewals.Done()
+
+	renewed, err := renew(s.rootCtx)
+	if err != nil {
+		s.log.Error("failed to renew primary validations", zap.Strings("node-names", renewSpec.NodeNames), zap.Error(err))
+		s.progress.publish(network.ProgressEvent{
+			Phase:   network.PhaseRenewPrimaryValidations,
+			Message: fmt.Sprintf("failed to renew primary validations: %s", err),
+		})
+		return
+	}
+	for _, v := range renewed {
+		s.log.Info("renewed validation",
+			zap.String("node-name", v.NodeName),
+			zap.String("subnet-id", v.SubnetID.String()),
+			zap.String("tx-id", v.TxID.String()),
+			zap.Time("end-time", v.EndTime),
+		)
+	}
+	s.progress.publish(network.ProgressEvent{
+		Phase:   network.PhaseRenewPrimaryValidations,
+		Percent: 100,
+		Message: fmt.Sprintf("renewed %d validations", len(renewed)),
+	})
+}
+
+func (s *server) RemoveSubnetValidator(
+	_ context.Context,
+	req *rpcpb.RemoveSubnetValidatorRequest,
+) (*rpcpb.RemoveSubnetValidatorResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("RemoveSubnetValidator", zap.Bool("skip-restart", req.GetSkipRestart()))
+
+	if len(req.GetValidatorSpec()) == 0 {
+		return nil, ErrNoValidatorSpec
+	}
+
+	validatorSpecList := []network.RemoveSubnetValidatorSpec{}
+	for _, spec := range req.GetValidatorSpec() {
+		validatorSpec := getRemoveSubnetValidatorSpec(spec)
+		validatorSpecList = append(validatorSpecList, validatorSpec)
+	}
+
+	// check that the given subnets exist
+	subnetsSet := set.Set[string]{}
+	subnetsSet.Add(maps.Keys(s.clusterInfo.Subnets)...)
+
+	for _, validatorSpec := range validatorSpecList {
+		if validatorSpec.SubnetID == "" {
+			return nil, ErrNoSubnetID
+		} else if !subnetsSet.Contains(validatorSpec.SubnetID) {
+			return nil, fmt.Errorf("subnet id %q does not exist", validatorSpec.SubnetID)
+		}
+	}
+
+	s.setClusterUnhealthy()
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	err := s.network.RemoveSubnetValidator(ctx, validatorSpecList, req.GetSkipRestart())
+
+	s.updateClusterInfo()
+
+	if err != nil {
+		s.log.Error("failed to remove subnet validator", zap.Error(err))
+		return nil, err
+	}
+
+	s.log.Info("successfully removed subnet validator")
+
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.RemoveSubnetValidatorResponse{ClusterInfo: clusterInfo}, nil
+}
+
+func (s *server) TransformElasticSubnets(
+	_ context.Context,
+	req *rpcpb.TransformElasticSubnetsRequest,
+) (*rpcpb.TransformElasticSubnetsResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("TransformElasticSubnet")
+
+	if len(req.GetElasticSubnetSpec()) == 0 {
+		return nil, ErrNoElasticSubnetSpec
+	}
+
+	elasticSubnetSpecList := []network.ElasticSubnetSpec{}
+	for _, spec := range req.GetElasticSubnetSpec() {
+		elasticSubnetSpec := getNetworkElasticSubnetSpec(spec)
+		elasticSubnetSpecList = append(elasticSubnetSpecList, elasticSubnetSpec)
+	}
+
+	// check that the given subnets exist
+	subnetsSet := set.Set[string]{}
+	subnetsSet.Add(maps.Keys(s.clusterInfo.Subnets)...)
+
+	for _, elasticSubnetSpec := range elasticSubnetSpecList {
+		if elasticSubnetSpec.SubnetID == nil {
+			return nil, ErrNoSubnetID
+		} else if !subnetsSet.Contains(*elasticSubnetSpec.SubnetID) {
+			return nil, fmt.Errorf("subnet id %q does not exist", *elasticSubnetSpec.SubnetID)
+		}
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), waitForHealthyTimeout)
+	s.setClusterUnhealthy()
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
 	defer cancel()
 	txIDs, assetIDs, err := s.network.TransformSubnets(ctx, elasticSubnetSpecList)
 
@@ -710,8 +1854,8 @@ func (s *server) TransformElasticSubnets(
 }
 
 func (s *server) CreateSubnets(_ context.Context, req *rpcpb.CreateSubnetsRequest) (*rpcpb.CreateSubnetsResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	if s.network == nil {
 		return nil, ErrNotBootstrapped
@@ -721,16 +1865,18 @@ func (s *server) CreateSubnets(_ context.Context, req *rpcpb.CreateSubnetsReques
 
 	subnetSpecs := []network.SubnetSpec{}
 	for _, spec := range req.GetSubnetSpecs() {
-		subnetSpec := getNetworkSubnetSpec(spec)
+		subnetSpec, err := getNetworkSubnetSpec(spec, s.network.options.stakingSpec.GetValidationStartOffset())
+		if err != nil {
+			return nil, invalidArgumentError(err)
+		}
 		subnetSpecs = append(subnetSpecs, subnetSpec)
 	}
 
 	s.log.Info("waiting for local cluster readiness")
 
-	s.clusterInfo.Healthy = false
-	s.clusterInfo.CustomChainsHealthy = false
+	s.setClusterUnhealthy()
 
-	ctx, cancel := context.WithTimeout(context.Background(), waitForHealthyTimeout)
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
 	defer cancel()
 	subnetIDs, err := s.network.CreateSubnets(ctx, subnetSpecs)
 	if err != nil {
@@ -755,8 +1901,8 @@ func (s *server) CreateSubnets(_ context.Context, req *rpcpb.CreateSubnetsReques
 }
 
 func (s *server) Health(ctx context.Context, _ *rpcpb.HealthRequest) (*rpcpb.HealthResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	s.log.Debug("Health")
 
@@ -769,10 +1915,10 @@ func (s *server) Health(ctx context.Context, _ *rpcpb.HealthRequest) (*rpcpb.Hea
 		return nil, err
 	}
 
-	s.clusterInfo.NodeNames = maps.Keys(s.network.nodeInfos)
-	sort.Strings(s.clusterInfo.NodeNames)
-	s.clusterInfo.NodeInfos = s.network.nodeInfos
+	s.mu.Lock()
+	s.setClusterNodeInfos()
 	s.clusterInfo.Healthy = true
+	s.mu.Unlock()
 
 	clusterInfo, err := deepCopy(s.clusterInfo)
 	if err != nil {
@@ -810,11 +1956,108 @@ func (s *server) Status(context.Context, *rpcpb.StatusRequest) (*rpcpb.StatusRes
 		return &rpcpb.StatusResponse{}, ErrNotBootstrapped
 	}
 
-	return &rpcpb.StatusResponse{ClusterInfo: s.clusterInfo}, nil
+	clusterInfo, err := s.sampleClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.StatusResponse{ClusterInfo: clusterInfo}, nil
 }
 
+// Returns a copy of [s.clusterInfo] with fresh resource usage
+// of the node processes.
 // Assumes [s.mu] is held.
+func (s *server) sampleClusterInfo() (*rpcpb.ClusterInfo, error) {
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	s.procSampler.sample(clusterInfo.NodeInfos)
+	return clusterInfo, nil
+}
+
+func (s *server) GetNodeInfo(_ context.Context, req *rpcpb.GetNodeInfoRequest) (*rpcpb.GetNodeInfoResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	s.log.Debug("GetNodeInfo", zap.String("name", req.GetName()))
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	nodeInfo, ok := s.clusterInfo.NodeInfos[req.GetName()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNodeNotFound, req.GetName())
+	}
+	nodeInfo = proto.Clone(nodeInfo).(*rpcpb.NodeInfo)
+	s.procSampler.sample(map[string]*rpcpb.NodeInfo{nodeInfo.Name: nodeInfo})
+	return &rpcpb.GetNodeInfoResponse{NodeInfo: nodeInfo}, nil
+}
+
+// Returns a copy of the custom chains of [s.clusterInfo], so they are read
+// without waiting for the operation in progress.
+func (s *server) getCustomChains() map[string]*rpcpb.CustomChainInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.clusterInfo == nil {
+		return nil
+	}
+	return cloneProtoMap(s.clusterInfo.CustomChains)
+}
+
+// Returns a copy of the subnets of [s.clusterInfo].
+func (s *server) getSubnets() map[string]*rpcpb.SubnetInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.clusterInfo == nil {
+		return nil
+	}
+	return cloneProtoMap(s.clusterInfo.Subnets)
+}
+
+// Returns a copy of [s.clusterInfo].
+func (s *server) copyClusterInfo() (*rpcpb.ClusterInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return deepCopy(s.clusterInfo)
+}
+
+// Returns the network of the server, or nil if there isn't one. The
+// network may be stopped by an operation while it is used.
+func (s *server) getNetwork() *localNetwork {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.network
+}
+
+// Sets [nw] as the network of the server, named after the network
+// name requested in [ctx].
+// Assumes [s.opMu] is held.
+func (s *server) setNetwork(ctx context.Context, nw *localNetwork, clusterInfo *rpcpb.ClusterInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.network = nw
+	s.networkName = getRequestNetworkName(ctx)
+	s.clusterInfo = clusterInfo
+	s.clusterInfo.NetworkName = s.networkName
+	s.fundedKey = nil
+	s.writeManifest()
+}
+
+// Assumes [s.opMu] is held.
 func (s *server) stopAndRemoveNetwork(err error) {
+	s.stopAndRemoveNetworkWithOptions(err, network.StopOptions{})
+}
+
+// Stops the network as set by [opts] and removes it from the server.
+// Returns the outcome of stopping each node.
+// Assumes [s.opMu] is held.
+func (s *server) stopAndRemoveNetworkWithOptions(err error, opts network.StopOptions) []network.NodeStopResult {
 	s.log.Info("removing network")
 	select {
 	// cleanup of possible previous unchecked async err
@@ -825,16 +2068,33 @@ func (s *server) stopAndRemoveNetwork(err error) {
 	if err != nil {
 		s.asyncErrCh <- err
 	}
+	var results []network.NodeStopResult
 	if s.network != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+		if opts.NodeTimeout == 0 {
+			opts.NodeTimeout = defaultNodeStopTimeout
+		}
+		// nodes are stopped one at a time, each one possibly using its full timeout
+		timeout := stopTimeout
+		if s.clusterInfo != nil {
+			timeout += opts.NodeTimeout * time.Duration(len(s.clusterInfo.NodeNames))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
-		s.network.Stop(ctx)
+		results = s.network.StopWithOptions(ctx, opts)
 	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.clusterInfo != nil {
 		s.clusterInfo.Healthy = false
 		s.clusterInfo.CustomChainsHealthy = false
 	}
 	s.network = nil
+	s.startRequest = nil
+	s.procSampler.retain(nil)
+	s.removeManifest()
+	return results
 }
 
 // TODO document this
@@ -848,7 +2108,7 @@ func (s *server) StreamStatus(req *rpcpb.StreamStatusRequest, stream rpcpb.Contr
 	wg := sync.WaitGroup{}
 	wg.Add(1)
 	go func() {
-		s.sendLoop(stream, interval)
+		s.sendLoop(stream, interval, req.Fields, req.Delta)
 		wg.Done()
 	}()
 
@@ -881,13 +2141,23 @@ func (s *server) StreamStatus(req *rpcpb.StreamStatusRequest, stream rpcpb.Contr
 	return err
 }
 
-// TODO document this
-func (s *server) sendLoop(stream rpcpb.ControlService_StreamStatusServer, interval time.Duration) {
+// Sends the [fields] of the cluster info to [stream] every [interval],
+// until the server is stopped or the stream fails. If [delta] is set,
+// the cluster info is only sent when it changed, ignoring small changes
+// in the resource usage of the nodes.
+func (s *server) sendLoop(
+	stream rpcpb.ControlService_StreamStatusServer,
+	interval time.Duration,
+	fields []rpcpb.ClusterInfoField,
+	delta bool,
+) {
 	s.log.Info("start status send loop")
 
 	tc := time.NewTicker(1)
 	defer tc.Stop()
 
+	var lastSent *rpcpb.ClusterInfo
+
 	for {
 		select {
 		case <-s.rootCtx.Done():
@@ -899,8 +2169,16 @@ func (s *server) sendLoop(stream rpcpb.ControlService_StreamStatusServer, interv
 		s.log.Debug("sending cluster info")
 
 		s.mu.RLock()
-		err := stream.Send(&rpcpb.StreamStatusResponse{ClusterInfo: s.clusterInfo})
+		clusterInfo, err := s.sampleClusterInfo()
 		s.mu.RUnlock()
+		if err == nil {
+			filterClusterInfo(clusterInfo, fields)
+			if delta && lastSent != nil && isClusterInfoUnchanged(lastSent, clusterInfo) {
+				continue
+			}
+			err = stream.Send(&rpcpb.StreamStatusResponse{ClusterInfo: clusterInfo})
+			lastSent = clusterInfo
+		}
 		if err != nil {
 			if isClientCanceled(stream.Context().Err(), err) {
 				s.log.Debug("client stream canceled", zap.Error(err))
@@ -937,8 +2215,8 @@ func (s *server) recvLoop(stream rpcpb.ControlService_StreamStatusServer) error
 }
 
 func (s *server) AddNode(_ context.Context, req *rpcpb.AddNodeRequest) (*rpcpb.AddNodeResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	s.log.Debug("AddNode", zap.String("name", req.Name))
 
@@ -976,9 +2254,7 @@ func (s *server) AddNode(_ context.Context, req *rpcpb.AddNodeRequest) (*rpcpb.A
 		return nil, err
 	}
 
-	s.clusterInfo.NodeNames = maps.Keys(s.network.nodeInfos)
-	sort.Strings(s.clusterInfo.NodeNames)
-	s.clusterInfo.NodeInfos = s.network.nodeInfos
+	s.updateClusterNodeInfos()
 
 	clusterInfo, err := deepCopy(s.clusterInfo)
 	if err != nil {
@@ -988,8 +2264,7 @@ func (s *server) AddNode(_ context.Context, req *rpcpb.AddNodeRequest) (*rpcpb.A
 }
 
 func (s *server) RemoveNode(ctx context.Context, req *rpcpb.RemoveNodeRequest) (*rpcpb.RemoveNodeResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	defer s.opMu.LockNode(req.Name)()
 
 	s.log.Debug("RemoveNode", zap.String("name", req.Name))
 
@@ -1005,22 +2280,117 @@ func (s *server) RemoveNode(ctx context.Context, req *rpcpb.RemoveNodeRequest) (
 		return nil, err
 	}
 
-	s.clusterInfo.NodeNames = maps.Keys(s.network.nodeInfos)
-	sort.Strings(s.clusterInfo.NodeNames)
-	s.clusterInfo.NodeInfos = s.network.nodeInfos
+	s.updateClusterNodeInfos()
 
-	clusterInfo, err := deepCopy(s.clusterInfo)
+	clusterInfo, err := s.copyClusterInfo()
 	if err != nil {
 		return nil, err
 	}
 	return &rpcpb.RemoveNodeResponse{ClusterInfo: clusterInfo}, nil
 }
 
+// Removes the node as validator of the permissioned subnets, waits for the
+// removals to take effect, and only then stops it.
+func (s *server) DrainNode(_ context.Context, req *rpcpb.DrainNodeRequest) (*rpcpb.DrainNodeResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	s.log.Debug("DrainNode", zap.String("name", req.Name))
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.setClusterUnhealthy()
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	err := s.network.nw.DrainNode(ctx, req.Name)
+	if err == nil {
+		err = s.network.AwaitHealthyAndUpdateNetworkInfo(ctx)
+	}
+
+	s.updateClusterInfo()
+
+	if err != nil {
+		s.log.Error("failed to drain node", zap.Error(err))
+		return nil, err
+	}
+
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.DrainNodeResponse{ClusterInfo: clusterInfo}, nil
+}
+
+func (s *server) ScaleNetwork(_ context.Context, req *rpcpb.ScaleNetworkRequest) (*rpcpb.ScaleNetworkResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	s.log.Debug("ScaleNetwork", zap.Uint32("num-nodes", req.NumNodes))
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+	if req.NumNodes < MinNodes {
+		return nil, ErrNotEnoughNodesForStart
+	}
+	if req.NumNodes > MaxNodes {
+		return nil, invalidArgumentError(fmt.Errorf("%w: %d nodes, the maximum is %d", ErrTooManyNodes, req.NumNodes, MaxNodes))
+	}
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	// some nodes may have been added or removed before an error, so the
+	// cluster info is updated anyway
+	err := s.network.nw.ScaleNetwork(ctx, req.NumNodes)
+	if updateErr := s.network.UpdateNodeInfo(); updateErr != nil && err == nil {
+		err = updateErr
+	}
+	s.updateClusterNodeInfos()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterInfo, err := s.copyClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.ScaleNetworkResponse{ClusterInfo: clusterInfo}, nil
+}
+
+func (s *server) ResetNetwork(_ context.Context, _ *rpcpb.ResetNetworkRequest) (*rpcpb.ResetNetworkResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	s.log.Debug("ResetNetwork")
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.setClusterUnhealthy()
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	if err := s.network.ResetNetwork(ctx); err != nil {
+		return nil, err
+	}
+
+	s.updateClusterInfo()
+
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.ResetNetworkResponse{ClusterInfo: clusterInfo}, nil
+}
+
 func (s *server) RestartNode(ctx context.Context, req *rpcpb.RestartNodeRequest) (*rpcpb.RestartNodeResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	defer s.opMu.LockNode(req.Name)()
 
-	s.log.Debug("RestartNode", zap.String("name", req.Name))
+	s.log.Debug("RestartNode", zap.String("name", req.Name), zap.Bool("wipe-db", req.GetWipeDb()))
 
 	if s.network == nil {
 		return nil, ErrNotBootstrapped
@@ -1035,6 +2405,7 @@ func (s *server) RestartNode(ctx context.Context, req *rpcpb.RestartNodeRequest)
 		req.GetChainConfigs(),
 		req.GetUpgradeConfigs(),
 		req.GetSubnetConfigs(),
+		req.GetWipeDb(),
 	); err != nil {
 		return nil, err
 	}
@@ -1043,11 +2414,9 @@ func (s *server) RestartNode(ctx context.Context, req *rpcpb.RestartNodeRequest)
 		return nil, err
 	}
 
-	s.clusterInfo.NodeNames = maps.Keys(s.network.nodeInfos)
-	sort.Strings(s.clusterInfo.NodeNames)
-	s.clusterInfo.NodeInfos = s.network.nodeInfos
+	s.updateClusterNodeInfos()
 
-	clusterInfo, err := deepCopy(s.clusterInfo)
+	clusterInfo, err := s.copyClusterInfo()
 	if err != nil {
 		return nil, err
 	}
@@ -1055,8 +2424,7 @@ func (s *server) RestartNode(ctx context.Context, req *rpcpb.RestartNodeRequest)
 }
 
 func (s *server) PauseNode(ctx context.Context, req *rpcpb.PauseNodeRequest) (*rpcpb.PauseNodeResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	defer s.opMu.LockNode(req.Name)()
 
 	s.log.Debug("PauseNode", zap.String("name", req.Name))
 
@@ -1074,51 +2442,195 @@ func (s *server) PauseNode(ctx context.Context, req *rpcpb.PauseNodeRequest) (*r
 	if err := s.network.UpdateNodeInfo(); err != nil {
 		return nil, err
 	}
-
-	s.clusterInfo.NodeNames = maps.Keys(s.network.nodeInfos)
-	sort.Strings(s.clusterInfo.NodeNames)
-	s.clusterInfo.NodeInfos = s.network.nodeInfos
-
-	return &rpcpb.PauseNodeResponse{ClusterInfo: s.clusterInfo}, nil
+
+	s.updateClusterNodeInfos()
+
+	clusterInfo, err := s.copyClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.PauseNodeResponse{ClusterInfo: clusterInfo}, nil
+}
+
+func (s *server) ResumeNode(ctx context.Context, req *rpcpb.ResumeNodeRequest) (*rpcpb.ResumeNodeResponse, error) {
+	defer s.opMu.LockNode(req.Name)()
+
+	s.log.Debug("ResumeNode", zap.String("name", req.Name))
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	if err := s.network.nw.ResumeNode(
+		ctx,
+		req.Name,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := s.network.UpdateNodeInfo(); err != nil {
+		return nil, err
+	}
+
+	s.updateClusterNodeInfos()
+
+	clusterInfo, err := s.copyClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.ResumeNodeResponse{ClusterInfo: clusterInfo}, nil
+}
+
+// Pauses all the running nodes. The work done on the network while they are
+// paused (tracking subnets, aliases, ...) is applied when they are resumed.
+func (s *server) PauseAll(ctx context.Context, _ *rpcpb.PauseAllRequest) (*rpcpb.PauseAllResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	s.log.Debug("PauseAll")
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	nodeNames, err := s.setNodesPaused(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterInfo, err := s.copyClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.PauseAllResponse{ClusterInfo: clusterInfo, NodeNames: nodeNames}, nil
+}
+
+// Resumes all the paused nodes, applying the work queued for them.
+func (s *server) ResumeAll(ctx context.Context, _ *rpcpb.ResumeAllRequest) (*rpcpb.ResumeAllResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	s.log.Debug("ResumeAll")
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	nodeNames, err := s.setNodesPaused(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterInfo, err := s.copyClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.ResumeAllResponse{ClusterInfo: clusterInfo, NodeNames: nodeNames}, nil
+}
+
+// Pauses all the running nodes if [paused] is true, or resumes all the
+// paused ones otherwise, in name order. Returns the names of the nodes
+// changed. The node infos are updated even if an operation fails.
+// Assumes [s.opMu] is held.
+func (s *server) setNodesPaused(ctx context.Context, paused bool) ([]string, error) {
+	nodes, err := s.network.nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	nodeNames := maps.Keys(nodes)
+	sort.Strings(nodeNames)
+	changed := []string{}
+	for _, nodeName := range nodeNames {
+		if nodes[nodeName].GetPaused() == paused {
+			continue
+		}
+		if paused {
+			err = s.network.nw.PauseNode(ctx, nodeName)
+		} else {
+			err = s.network.nw.ResumeNode(ctx, nodeName)
+		}
+		if err != nil {
+			err = fmt.Errorf("failure changing paused state of node %q: %w", nodeName, err)
+			break
+		}
+		changed = append(changed, nodeName)
+	}
+
+	if updateErr := s.network.UpdateNodeInfo(); updateErr != nil && err == nil {
+		err = updateErr
+	}
+	s.updateClusterNodeInfos()
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
 }
 
-func (s *server) ResumeNode(ctx context.Context, req *rpcpb.ResumeNodeRequest) (*rpcpb.ResumeNodeResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *server) SimulateDowntime(ctx context.Context, req *rpcpb.SimulateDowntimeRequest) (*rpcpb.SimulateDowntimeResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
-	s.log.Debug("ResumeNode", zap.String("name", req.Name))
+	s.log.Debug("SimulateDowntime", zap.String("name", req.Name), zap.Uint64("duration", req.Duration))
 
 	if s.network == nil {
 		return nil, ErrNotBootstrapped
 	}
 
-	if err := s.network.nw.ResumeNode(
-		ctx,
-		req.Name,
-	); err != nil {
-		return nil, err
+	duration, err := getDuration("downtime duration", req.Duration)
+	if err != nil {
+		return nil, invalidArgumentError(err)
 	}
+	deltas, err := s.network.SimulateDowntime(ctx, req.Name, duration)
 
-	if err := s.network.UpdateNodeInfo(); err != nil {
+	s.updateClusterInfo()
+
+	if err != nil {
+		s.log.Error("failed to simulate downtime", zap.Error(err))
 		return nil, err
 	}
 
-	s.clusterInfo.NodeNames = maps.Keys(s.network.nodeInfos)
-	sort.Strings(s.clusterInfo.NodeNames)
-	s.clusterInfo.NodeInfos = s.network.nodeInfos
-
-	return &rpcpb.ResumeNodeResponse{ClusterInfo: s.clusterInfo}, nil
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.SimulateDowntimeResponse{ClusterInfo: clusterInfo, UptimeDeltas: deltas}, nil
 }
 
-func (s *server) Stop(context.Context, *rpcpb.StopRequest) (*rpcpb.StopResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+func (s *server) Stop(_ context.Context, req *rpcpb.StopRequest) (*rpcpb.StopResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	s.log.Debug("Stop",
+		zap.Bool("non-beacons-first", req.GetNonBeaconsFirst()),
+		zap.Duration("node-timeout", time.Duration(req.GetNodeTimeout())),
+	)
 
-	s.log.Debug("Stop")
+	if req.GetNodeTimeout() < 0 {
+		return nil, invalidArgumentError(fmt.Errorf("%w: %d", ErrInvalidNodeTimeout, req.GetNodeTimeout()))
+	}
 
-	s.stopAndRemoveNetwork(nil)
+	results := s.stopAndRemoveNetworkWithOptions(nil, network.StopOptions{
+		NonBeaconsFirst: req.GetNonBeaconsFirst(),
+		NodeTimeout:     time.Duration(req.GetNodeTimeout()),
+	})
 
-	return &rpcpb.StopResponse{ClusterInfo: s.clusterInfo}, nil
+	clusterInfo, err := deepCopy(s.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	nodeResults := make([]*rpcpb.NodeStopResult, len(results))
+	for i, result := range results {
+		nodeResults[i] = &rpcpb.NodeStopResult{
+			Name:     result.NodeName,
+			ExitCode: int32(result.ExitCode),
+			Killed:   result.Killed,
+			Duration: int64(result.Duration),
+		}
+		if result.Err != nil {
+			nodeResults[i].Error = result.Err.Error()
+		}
+	}
+	return &rpcpb.StopResponse{ClusterInfo: clusterInfo, NodeResults: nodeResults}, nil
 }
 
 var _ router.InboundHandler = &loggingInboundHandler{}
@@ -1137,8 +2649,7 @@ func (lh *loggingInboundHandler) HandleInbound(_ context.Context, m message.Inbo
 }
 
 func (s *server) AttachPeer(ctx context.Context, req *rpcpb.AttachPeerRequest) (*rpcpb.AttachPeerResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	defer s.opMu.LockNode(req.NodeName)()
 
 	s.log.Debug("AttachPeer")
 
@@ -1146,24 +2657,40 @@ func (s *server) AttachPeer(ctx context.Context, req *rpcpb.AttachPeerRequest) (
 		return nil, ErrNotBootstrapped
 	}
 
-	node, err := s.network.nw.GetNode(req.NodeName)
+	nd, err := s.network.nw.GetNode(req.NodeName)
 	if err != nil {
 		return nil, err
 	}
 
+	var behavior *node.PeerBehavior
+	if req.Behavior != nil {
+		if req.Behavior.Duplicates > node.MaxPeerDuplicates {
+			return nil, invalidArgumentError(fmt.Errorf(
+				"%w: duplicates %d is above the max of %d",
+				ErrInvalidPeerBehavior, req.Behavior.Duplicates, node.MaxPeerDuplicates,
+			))
+		}
+		behavior = &node.PeerBehavior{
+			SendDelay:     time.Duration(req.Behavior.SendDelay),
+			Duplicates:    req.Behavior.Duplicates,
+			MalformedBody: req.Behavior.MalformedBody,
+			WrongChainID:  req.Behavior.WrongChainId,
+		}
+	}
 	loggingHandler := &loggingInboundHandler{nodeName: req.NodeName, log: s.log}
-	newPeer, err := node.AttachPeer(ctx, loggingHandler)
+	newPeer, err := nd.AttachPeer(ctx, loggingHandler, behavior)
 	if err != nil {
 		return nil, err
 	}
 
 	newPeerID := newPeer.ID().String()
-	s.log.Debug("new peer is attached to", zap.String("peer-ID", newPeerID), zap.String("node-name", node.GetName()))
+	s.log.Debug("new peer is attached to", zap.String("peer-ID", newPeerID), zap.String("node-name", nd.GetName()))
 
+	s.mu.Lock()
 	if s.clusterInfo.AttachedPeerInfos == nil {
 		s.clusterInfo.AttachedPeerInfos = make(map[string]*rpcpb.ListOfAttachedPeerInfo)
 	}
-	peerInfo := &rpcpb.AttachedPeerInfo{Id: newPeerID}
+	peerInfo := &rpcpb.AttachedPeerInfo{Id: newPeerID, Behavior: req.Behavior}
 	if v, ok := s.clusterInfo.AttachedPeerInfos[req.NodeName]; ok {
 		v.Peers = append(v.Peers, peerInfo)
 	} else {
@@ -1171,8 +2698,9 @@ func (s *server) AttachPeer(ctx context.Context, req *rpcpb.AttachPeerRequest) (
 			Peers: []*rpcpb.AttachedPeerInfo{peerInfo},
 		}
 	}
+	s.mu.Unlock()
 
-	clusterInfo, err := deepCopy(s.clusterInfo)
+	clusterInfo, err := s.copyClusterInfo()
 	if err != nil {
 		return nil, err
 	}
@@ -1180,8 +2708,7 @@ func (s *server) AttachPeer(ctx context.Context, req *rpcpb.AttachPeerRequest) (
 }
 
 func (s *server) SendOutboundMessage(ctx context.Context, req *rpcpb.SendOutboundMessageRequest) (*rpcpb.SendOutboundMessageResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	defer s.opMu.LockNode(req.NodeName)()
 
 	s.log.Debug("SendOutboundMessage")
 
@@ -1198,9 +2725,114 @@ func (s *server) SendOutboundMessage(ctx context.Context, req *rpcpb.SendOutboun
 	return &rpcpb.SendOutboundMessageResponse{Sent: sent}, err
 }
 
-func (s *server) LoadSnapshot(_ context.Context, req *rpcpb.LoadSnapshotRequest) (*rpcpb.LoadSnapshotResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// Streams the messages received by an attached peer, until the client
+// cancels, or the node is removed or paused.
+func (s *server) StreamPeerMessages(req *rpcpb.StreamPeerMessagesRequest, stream rpcpb.ControlService_StreamPeerMessagesServer) error {
+	s.log.Debug("StreamPeerMessages", zap.String("node-name", req.NodeName), zap.String("peer-ID", req.PeerId))
+
+	msgs, unsubscribe, err := s.subscribePeerMessages(req.NodeName, req.PeerId)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-s.rootCtx.Done():
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		case msg, ok := <-msgs:
+			if !ok {
+				return nil
+			}
+			resp := &rpcpb.StreamPeerMessagesResponse{
+				Op:     msg.Op,
+				OpName: message.Op(msg.Op).String(),
+				Bytes:  msg.Bytes,
+			}
+			if msg.ChainID != ids.Empty {
+				resp.ChainId = msg.ChainID.String()
+			}
+			if err := stream.Send(resp); err != nil {
+				if isClientCanceled(stream.Context().Err(), err) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+}
+
+func (s *server) subscribePeerMessages(nodeName string, peerID string) (<-chan node.PeerMessage, func(), error) {
+	defer s.opMu.LockNode(nodeName)()
+
+	if s.network == nil {
+		return nil, nil, ErrNotBootstrapped
+	}
+
+	nd, err := s.network.nw.GetNode(nodeName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nd.SubscribePeerMessages(peerID)
+}
+
+func (s *server) RecordPeerMessages(_ context.Context, req *rpcpb.RecordPeerMessagesRequest) (*rpcpb.RecordPeerMessagesResponse, error) {
+	defer s.opMu.LockNode(req.NodeName)()
+
+	s.log.Debug("RecordPeerMessages", zap.String("peer-ID", req.PeerId), zap.String("path", req.Path))
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	node, err := s.network.nw.GetNode(req.NodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	// an empty path stops the recording
+	path := req.Path
+	if path != "" {
+		path, err = s.getPeerRecordingPath(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := node.RecordPeerMessages(req.PeerId, path); err != nil {
+		return nil, err
+	}
+	return &rpcpb.RecordPeerMessagesResponse{}, nil
+}
+
+func (s *server) ReplayPeerMessages(ctx context.Context, req *rpcpb.ReplayPeerMessagesRequest) (*rpcpb.ReplayPeerMessagesResponse, error) {
+	defer s.opMu.LockNode(req.NodeName)()
+
+	s.log.Debug("ReplayPeerMessages", zap.String("peer-ID", req.PeerId), zap.String("path", req.Path))
+
+	if s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	node, err := s.network.nw.GetNode(req.NodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := s.getPeerRecordingPath(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	numSent, err := node.ReplayPeerMessages(ctx, req.PeerId, path, req.KeepTiming)
+	return &rpcpb.ReplayPeerMessagesResponse{NumSent: numSent}, err
+}
+
+func (s *server) LoadSnapshot(ctx context.Context, req *rpcpb.LoadSnapshotRequest) (*rpcpb.LoadSnapshotResponse, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	s.log.Debug("LoadSnapshot")
 
@@ -1222,11 +2854,19 @@ func (s *server) LoadSnapshot(_ context.Context, req *rpcpb.LoadSnapshotRequest)
 	if err != nil {
 		return nil, err
 	}
+	if err := markNetworkDir(rootDataDir); err != nil {
+		return nil, err
+	}
+
+	backend, err := getNetworkBackend(req.GetBackend())
+	if err != nil {
+		return nil, err
+	}
 
 	pid := int32(os.Getpid())
 	s.log.Info("starting", zap.Int32("pid", pid), zap.String("root-data-dir", rootDataDir))
 
-	s.network, err = newLocalNetwork(localNetworkOptions{
+	nw, err := newLocalNetwork(localNetworkOptions{
 		execPath:            req.GetExecPath(),
 		pluginDir:           req.GetPluginDir(),
 		rootDataDir:         rootDataDir,
@@ -1236,24 +2876,26 @@ func (s *server) LoadSnapshot(_ context.Context, req *rpcpb.LoadSnapshotRequest)
 		globalNodeConfig:    req.GetGlobalNodeConfig(),
 		logLevel:            s.cfg.LogLevel,
 		reassignPortsIfUsed: req.GetReassignPortsIfUsed(),
+		backend:             backend,
 		snapshotsDir:        s.cfg.SnapshotsDir,
+		onProgress:          s.progress.publish,
 	})
 	if err != nil {
 		return nil, err
 	}
-	s.clusterInfo = &rpcpb.ClusterInfo{
+	s.setNetwork(ctx, nw, &rpcpb.ClusterInfo{
 		Pid:         pid,
 		RootDataDir: rootDataDir,
-	}
+	})
 
 	// blocking load snapshot to soon get not found snapshot errors
-	if err := s.network.LoadSnapshot(req.SnapshotName); err != nil {
+	if err := s.network.LoadSnapshot(req.SnapshotName, req.GetPassphrase()); err != nil {
 		s.log.Warn("snapshot load failed to complete", zap.Error(err))
 		s.stopAndRemoveNetwork(nil)
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), waitForHealthyTimeout)
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
 	defer cancel()
 	err = s.network.AwaitHealthyAndUpdateNetworkInfo(ctx)
 	if err != nil {
@@ -1272,14 +2914,18 @@ func (s *server) LoadSnapshot(_ context.Context, req *rpcpb.LoadSnapshotRequest)
 }
 
 func (s *server) SaveSnapshot(ctx context.Context, req *rpcpb.SaveSnapshotRequest) (*rpcpb.SaveSnapshotResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	s.log.Info("SaveSnapshot", zap.String("snapshot-name", req.SnapshotName))
 
 	if s.network == nil {
 		return nil, ErrNotBootstrapped
 	}
+	// the snapshot holds the staking keys
+	if s.network.options.stakingKeysStorage == network.StakingKeysStorageMemory && req.GetPassphrase() == "" {
+		return nil, invalidArgumentError(ErrSnapshotPassphrase)
+	}
 
 	snapshotPath, err := s.network.nw.SaveSnapshot(ctx, req.SnapshotName)
 	if err != nil {
@@ -1289,12 +2935,25 @@ func (s *server) SaveSnapshot(ctx context.Context, req *rpcpb.SaveSnapshotReques
 
 	s.stopAndRemoveNetwork(nil)
 
+	if req.GetPassphrase() != "" {
+		encryptedPath, err := local.EncryptSnapshot(snapshotPath, req.GetPassphrase())
+		if err != nil {
+			// the snapshot was asked for encrypted, so it is not kept in plaintext
+			s.log.Warn("snapshot encryption failed, removing it", zap.String("snapshot-path", snapshotPath), zap.Error(err))
+			if removeErr := os.RemoveAll(snapshotPath); removeErr != nil {
+				s.log.Warn("failed to remove unencrypted snapshot", zap.String("snapshot-path", snapshotPath), zap.Error(removeErr))
+			}
+			return nil, err
+		}
+		snapshotPath = encryptedPath
+	}
+
 	return &rpcpb.SaveSnapshotResponse{SnapshotPath: snapshotPath}, nil
 }
 
 func (s *server) RemoveSnapshot(_ context.Context, req *rpcpb.RemoveSnapshotRequest) (*rpcpb.RemoveSnapshotResponse, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
 
 	s.log.Info("RemoveSnapshot", zap.String("snapshot-name", req.SnapshotName))
 
@@ -1310,16 +2969,14 @@ func (s *server) RemoveSnapshot(_ context.Context, req *rpcpb.RemoveSnapshotRequ
 }
 
 func (s *server) GetSnapshotNames(context.Context, *rpcpb.GetSnapshotNamesRequest) (*rpcpb.GetSnapshotNamesResponse, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	s.log.Info("GetSnapshotNames")
 
-	if s.network == nil {
+	nw := s.getNetwork()
+	if nw == nil {
 		return nil, ErrNotBootstrapped
 	}
 
-	snapshotNames, err := s.network.nw.GetSnapshotNames()
+	snapshotNames, err := nw.nw.GetSnapshotNames()
 	if err != nil {
 		return nil, err
 	}
@@ -1384,6 +3041,32 @@ func getNetworkElasticSubnetSpec(
 	return elasticSubnetSpec
 }
 
+func getRPCElasticSubnetSpec(
+	spec network.ElasticSubnetSpec,
+) *rpcpb.ElasticSubnetSpec {
+	subnetID := ""
+	if spec.SubnetID != nil {
+		subnetID = *spec.SubnetID
+	}
+	return &rpcpb.ElasticSubnetSpec{
+		SubnetId:                 subnetID,
+		AssetName:                spec.AssetName,
+		AssetSymbol:              spec.AssetSymbol,
+		InitialSupply:            spec.InitialSupply,
+		MaxSupply:                spec.MaxSupply,
+		MinConsumptionRate:       spec.MinConsumptionRate,
+		MaxConsumptionRate:       spec.MaxConsumptionRate,
+		MinValidatorStake:        spec.MinValidatorStake,
+		MaxValidatorStake:        spec.MaxValidatorStake,
+		MinStakeDuration:         uint64(spec.MinStakeDuration / time.Hour),
+		MaxStakeDuration:         uint64(spec.MaxStakeDuration / time.Hour),
+		MinDelegationFee:         spec.MinDelegationFee,
+		MinDelegatorStake:        spec.MinDelegatorStake,
+		MaxValidatorWeightFactor: uint32(spec.MaxValidatorWeightFactor),
+		UptimeRequirement:        spec.UptimeRequirement,
+	}
+}
+
 func getPermissionlessValidatorSpec(
 	spec *rpcpb.PermissionlessValidatorSpec,
 ) (network.PermissionlessValidatorSpec, error) {
@@ -1399,15 +3082,45 @@ func getPermissionlessValidatorSpec(
 		}
 	}
 
+	var endTime time.Time
+	if spec.EndTime != "" {
+		endTime, err = time.Parse(TimeParseLayout, spec.EndTime)
+		if err != nil {
+			return network.PermissionlessValidatorSpec{}, err
+		}
+	}
+
+	if spec.StakedTokenAmount == 0 {
+		return network.PermissionlessValidatorSpec{}, fmt.Errorf("%w for validator spec of %s", ErrZeroAmount, spec.NodeName)
+	}
+
+	if spec.StakeDuration > maxStakeDurationHours {
+		return network.PermissionlessValidatorSpec{}, fmt.Errorf("%w: stake duration of %d hours is above the max of %d for validator spec of %s", network.ErrInvalidValidatorSpec, spec.StakeDuration, maxStakeDurationHours, spec.NodeName)
+	}
 	stakeDuration := time.Duration(spec.StakeDuration) * time.Hour
 
+	if int(spec.RewardThreshold) > len(spec.RewardOwners) {
+		return network.PermissionlessValidatorSpec{}, fmt.Errorf("reward threshold %d is greater than the number of reward owners %d for validator spec of %s", spec.RewardThreshold, len(spec.RewardOwners), spec.NodeName)
+	}
+	if int(spec.ChangeThreshold) > len(spec.ChangeOwners) {
+		return network.PermissionlessValidatorSpec{}, fmt.Errorf("change threshold %d is greater than the number of change owners %d for validator spec of %s", spec.ChangeThreshold, len(spec.ChangeOwners), spec.NodeName)
+	}
+
 	validatorSpec := network.PermissionlessValidatorSpec{
-		SubnetID:      spec.SubnetId,
-		AssetID:       spec.AssetId,
-		NodeName:      spec.NodeName,
-		StakedAmount:  spec.StakedTokenAmount,
-		StartTime:     startTime,
-		StakeDuration: stakeDuration,
+		SubnetID:        spec.SubnetId,
+		AssetID:         spec.AssetId,
+		NodeName:        spec.NodeName,
+		StakedAmount:    spec.StakedTokenAmount,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		StakeDuration:   stakeDuration,
+		RewardOwners:    spec.RewardOwners,
+		RewardThreshold: spec.RewardThreshold,
+		ChangeOwners:    spec.ChangeOwners,
+		ChangeThreshold: spec.ChangeThreshold,
+	}
+	if err := validatorSpec.Validate(time.Now().Add(StakingMinimumLeadTime)); err != nil {
+		return network.PermissionlessValidatorSpec{}, err
 	}
 	return validatorSpec, nil
 }
@@ -1427,6 +3140,7 @@ func getNetworkBlockchainSpec(
 	spec *rpcpb.BlockchainSpec,
 	isNewEmptyNetwork bool,
 	pluginDir string,
+	startOffset time.Duration,
 ) (network.BlockchainSpec, error) {
 	if isNewEmptyNetwork && spec.SubnetId != nil {
 		return network.BlockchainSpec{}, errors.New("blockchain subnet id must be nil if starting a new empty network")
@@ -1457,9 +3171,22 @@ func getNetworkBlockchainSpec(
 	}
 
 	var networkUpgradeBytes []byte
+	if spec.NetworkUpgrade != "" && spec.NetworkUpgradeSpec != nil {
+		return network.BlockchainSpec{}, ErrNetworkUpgradeAndSpec
+	}
 	if spec.NetworkUpgrade != "" {
 		networkUpgradeBytes = readFileOrString(spec.NetworkUpgrade)
 	}
+	if spec.NetworkUpgradeSpec != nil {
+		upgradeSpec, err := getNetworkUpgradeSpec(spec.NetworkUpgradeSpec)
+		if err != nil {
+			return network.BlockchainSpec{}, err
+		}
+		networkUpgradeBytes, err = upgradeSpec.Bytes()
+		if err != nil {
+			return network.BlockchainSpec{}, err
+		}
+	}
 
 	var subnetConfigBytes []byte
 	if spec.SubnetSpec != nil && spec.SubnetSpec.SubnetConfig != "" {
@@ -1495,9 +3222,17 @@ func getNetworkBlockchainSpec(
 	}
 
 	if spec.SubnetSpec != nil {
+		validationDuration, err := getValidationDuration("subnet validation duration", spec.SubnetSpec.ValidationDuration, startOffset)
+		if err != nil {
+			return network.BlockchainSpec{}, err
+		}
 		subnetSpec := network.SubnetSpec{
-			Participants: spec.SubnetSpec.Participants,
-			SubnetConfig: subnetConfigBytes,
+			Participants:       spec.SubnetSpec.Participants,
+			SubnetConfig:       subnetConfigBytes,
+			ValidatorsWeight:   spec.SubnetSpec.ValidatorsWeight,
+			ValidationDuration: validationDuration,
+			Owners:             spec.SubnetSpec.Owners,
+			Threshold:          spec.SubnetSpec.Threshold,
 		}
 		blockchainSpec.SubnetSpec = &subnetSpec
 	}
@@ -1505,17 +3240,309 @@ func getNetworkBlockchainSpec(
 	return blockchainSpec, nil
 }
 
+func getNetworkUpgradeSpec(
+	spec *rpcpb.NetworkUpgradeSpec,
+) (network.NetworkUpgradeSpec, error) {
+	upgradeSpec := network.NetworkUpgradeSpec{
+		ForkActivations: map[string]time.Time{},
+	}
+	for fork, activationTime := range spec.GetForkActivations() {
+		upgradeSpec.ForkActivations[fork] = time.Unix(activationTime, 0)
+	}
+	for _, precompileUpgrade := range spec.GetPrecompileUpgrades() {
+		precompileConfig := map[string]interface{}{}
+		if precompileUpgrade.Config != "" {
+			if err := json.Unmarshal([]byte(precompileUpgrade.Config), &precompileConfig); err != nil {
+				return network.NetworkUpgradeSpec{}, fmt.Errorf("failure unmarshaling config of precompile upgrade %q: %w", precompileUpgrade.Name, err)
+			}
+		}
+		upgradeSpec.PrecompileUpgrades = append(upgradeSpec.PrecompileUpgrades, network.PrecompileUpgrade{
+			Name:           precompileUpgrade.Name,
+			ActivationTime: time.Unix(precompileUpgrade.ActivationTime, 0),
+			Config:         precompileConfig,
+		})
+	}
+	return upgradeSpec, nil
+}
+
+// returns a copy of [upgradeConfigs] where the upgrade files built from
+// [upgradeSpecs] are added
+func mergeUpgradeSpecs(
+	upgradeConfigs map[string]string,
+	upgradeSpecs map[string]*rpcpb.NetworkUpgradeSpec,
+) (map[string]string, error) {
+	if len(upgradeSpecs) == 0 {
+		return upgradeConfigs, nil
+	}
+	merged := maps.Clone(upgradeConfigs)
+	if merged == nil {
+		merged = map[string]string{}
+	}
+	for chainName, spec := range upgradeSpecs {
+		if _, ok := merged[chainName]; ok {
+			return nil, fmt.Errorf("both upgrade config and upgrade spec given for chain %q", chainName)
+		}
+		upgradeSpec, err := getNetworkUpgradeSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		upgradeBytes, err := upgradeSpec.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		merged[chainName] = string(upgradeBytes)
+	}
+	return merged, nil
+}
+
+// returns a copy of [customNodeConfigs] where a config is added for each
+// node of [nodeGroups]. nodes take the lowest free names of the node
+// naming convention, in group order
+func expandNodeGroups(
+	log logging.Logger,
+	customNodeConfigs map[string]string,
+	nodeGroups []*rpcpb.NodeGroup,
+) (map[string]string, error) {
+	if len(nodeGroups) == 0 {
+		return customNodeConfigs, nil
+	}
+	expanded := maps.Clone(customNodeConfigs)
+	if expanded == nil {
+		expanded = map[string]string{}
+	}
+	for _, group := range nodeGroups {
+		if group.Count == 0 {
+			return nil, fmt.Errorf("%w: group %q has no nodes", ErrInvalidNodeGroup, group.Name)
+		}
+		if numNodes := uint64(len(expanded)) + uint64(group.Count); numNodes > uint64(MaxNodes) {
+			return nil, fmt.Errorf("%w: group %q takes the network to %d nodes, the maximum is %d", ErrTooManyNodes, group.Name, numNodes, MaxNodes)
+		}
+		if group.NodeConfig != "" {
+			var js map[string]interface{}
+			if err := json.Unmarshal([]byte(group.NodeConfig), &js); err != nil {
+				return nil, fmt.Errorf("%w: node config of group %q is not a JSON object: %s", ErrInvalidNodeGroup, group.Name, err)
+			}
+		}
+		nodeNames := make([]string, 0, group.Count)
+		for i := 1; len(nodeNames) < int(group.Count); i++ {
+			nodeName := fmt.Sprintf("node%d", i)
+			if _, ok := expanded[nodeName]; ok {
+				continue
+			}
+			expanded[nodeName] = group.NodeConfig
+			nodeNames = append(nodeNames, nodeName)
+		}
+		log.Info("node group", zap.String("name", group.Name), zap.Strings("node-names", nodeNames))
+	}
+	return expanded, nil
+}
+
+// Returns [seconds] as a duration, failing with ErrInvalidDuration if it
+// doesn't fit in a time.Duration. [name] describes it in the error.
+func getDuration(name string, seconds uint64) (time.Duration, error) {
+	if seconds > uint64(math.MaxInt64/int64(time.Second)) {
+		return 0, fmt.Errorf("%w: %s %ds is too long", ErrInvalidDuration, name, seconds)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// Returns the validation duration of [seconds], failing with
+// ErrInvalidDuration if it doesn't fit in a time.Duration or, unless zero,
+// isn't longer than the validation [startOffset], as the validations would
+// be refused.
+func getValidationDuration(name string, seconds uint64, startOffset time.Duration) (time.Duration, error) {
+	duration, err := getDuration(name, seconds)
+	if err != nil {
+		return 0, err
+	}
+	if duration != 0 && duration <= startOffset {
+		return 0, fmt.Errorf("%w: %s %s is not longer than the validation start offset %s", ErrInvalidDuration, name, duration, startOffset)
+	}
+	return duration, nil
+}
+
+// Returns the subnet spec of [spec], whose validations start [startOffset]
+// after their txs are issued.
 func getNetworkSubnetSpec(
 	spec *rpcpb.SubnetSpec,
-) network.SubnetSpec {
+	startOffset time.Duration,
+) (network.SubnetSpec, error) {
+	validationDuration, err := getValidationDuration("subnet validation duration", spec.ValidationDuration, startOffset)
+	if err != nil {
+		return network.SubnetSpec{}, err
+	}
 	var subnetConfigBytes []byte
 	if spec.SubnetConfig != "" {
 		subnetConfigBytes = readFileOrString(spec.SubnetConfig)
 	}
 	return network.SubnetSpec{
-		Participants: spec.Participants,
-		SubnetConfig: subnetConfigBytes,
+		Participants:       spec.Participants,
+		SubnetConfig:       subnetConfigBytes,
+		ValidatorsWeight:   spec.ValidatorsWeight,
+		ValidationDuration: validationDuration,
+		Owners:             spec.Owners,
+		Threshold:          spec.Threshold,
+	}, nil
+}
+
+func getNetworkStakingSpec(
+	spec *rpcpb.StakingSpec,
+) (network.StakingSpec, error) {
+	validationStartOffset, err := getDuration("validation start offset", spec.GetValidationStartOffset())
+	if err != nil {
+		return network.StakingSpec{}, err
+	}
+	stakingSpec := network.StakingSpec{
+		ValidationStartOffset:  validationStartOffset,
+		SubnetValidatorsWeight: spec.GetSubnetValidatorsWeight(),
+	}
+	stakingSpec.ValidationDuration, err = getValidationDuration(
+		"validation duration",
+		spec.GetValidationDuration(),
+		stakingSpec.GetValidationStartOffset(),
+	)
+	if err != nil {
+		return network.StakingSpec{}, err
+	}
+	return stakingSpec, nil
+}
+
+func getNetworkAccess(
+	spec *rpcpb.NetworkAccessSpec,
+) (networkAccess, error) {
+	access := networkAccess{
+		httpHost: spec.GetHttpHost(),
+		publicIP: spec.GetPublicIp(),
+	}
+	if spec.GetExternalAccess() {
+		access.httpHost = "0.0.0.0"
+	}
+	if access.publicIP != "" && net.ParseIP(access.publicIP) == nil {
+		return networkAccess{}, fmt.Errorf("%w: %q", ErrInvalidPublicIP, access.publicIP)
+	}
+	return access, nil
+}
+
+func getNetworkExternalNetwork(
+	spec *rpcpb.ExternalNetworkSpec,
+) *network.ExternalNetworkSpec {
+	if spec == nil {
+		return nil
+	}
+	return &network.ExternalNetworkSpec{
+		NetworkID:    spec.GetNetworkId(),
+		BootstrapIPs: spec.GetBootstrapIps(),
+		BootstrapIDs: spec.GetBootstrapIds(),
+	}
+}
+
+func getNetworkForkSpec(
+	spec *rpcpb.ForkSpec,
+) (*network.ForkSpec, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	if spec.GetRpcUrl() == "" {
+		return nil, fmt.Errorf("%w: rpc url is missing", ErrInvalidForkSpec)
+	}
+	forkSpec := &network.ForkSpec{
+		RPCURL:       spec.GetRpcUrl(),
+		BlockNumber:  spec.GetBlockNumber(),
+		StorageSlots: map[common.Address][]common.Hash{},
+		NetworkID:    spec.GetNetworkId(),
+	}
+	for _, addr := range spec.GetAddresses() {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("%w: invalid address %q", ErrInvalidForkSpec, addr)
+		}
+		forkSpec.Addresses = append(forkSpec.Addresses, common.HexToAddress(addr))
+	}
+	for addr, slots := range spec.GetStorageSlots() {
+		if !common.IsHexAddress(addr) {
+			return nil, fmt.Errorf("%w: invalid address %q", ErrInvalidForkSpec, addr)
+		}
+		account := common.HexToAddress(addr)
+		for _, slot := range slots.GetSlots() {
+			slotHex := strings.TrimPrefix(slot, "0x")
+			if len(slotHex)%2 == 1 {
+				slotHex = "0" + slotHex
+			}
+			slotBytes, err := hex.DecodeString(slotHex)
+			if err != nil || len(slotBytes) > common.HashLength {
+				return nil, fmt.Errorf("%w: invalid storage slot %q", ErrInvalidForkSpec, slot)
+			}
+			forkSpec.StorageSlots[account] = append(forkSpec.StorageSlots[account], common.BytesToHash(slotBytes))
+		}
+	}
+	if len(forkSpec.Addresses) == 0 && len(forkSpec.StorageSlots) == 0 {
+		return nil, fmt.Errorf("%w: no account to fork", ErrInvalidForkSpec)
+	}
+	return forkSpec, nil
+}
+
+func getNetworkTxSigner(
+	spec *rpcpb.TxSignerSpec,
+) (network.TxSigner, error) {
+	if spec == nil {
+		return nil, nil
+	}
+	if spec.GetUrl() == "" {
+		return nil, fmt.Errorf("%w: url is missing", ErrInvalidTxSignerSpec)
+	}
+	addr, err := ids.ShortFromString(spec.GetAddress())
+	if err != nil {
+		addr, err = address.ParseToID(spec.GetAddress())
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid address %q", ErrInvalidTxSignerSpec, spec.GetAddress())
+		}
+	}
+	return network.NewRemoteTxSigner(spec.GetUrl(), addr), nil
+}
+
+func getNetworkPortRange(
+	portRange *rpcpb.PortRange,
+) (network.PortRange, error) {
+	if portRange.GetMin() > math.MaxUint16 || portRange.GetMax() > math.MaxUint16 {
+		return network.PortRange{}, fmt.Errorf("%w: port range [%d, %d]", ErrInvalidPort, portRange.GetMin(), portRange.GetMax())
+	}
+	return network.PortRange{
+		Min: uint16(portRange.GetMin()),
+		Max: uint16(portRange.GetMax()),
+	}, nil
+}
+
+// So that clients can't place the node dirs at arbitrary paths of the
+// server host, the templates of [dirLayout] must be under the network root
+// dir or one of [allowedDirs]. Relative templates are taken from the network
+// root dir.
+func getNetworkDirLayout(
+	dirLayout *rpcpb.DirLayout,
+	allowedDirs []string,
+) (network.DirLayout, error) {
+	networkDirLayout := network.DirLayout{
+		DataDir: dirLayout.GetDataDir(),
+		DBDir:   dirLayout.GetDbDir(),
+		LogsDir: dirLayout.GetLogsDir(),
+	}
+	if err := networkDirLayout.Validate(); err != nil {
+		return network.DirLayout{}, fmt.Errorf("%w: %s", ErrInvalidDirLayout, err)
+	}
+	// the root dir is only known once the network is created, so the
+	// templates are confined to its placeholder
+	confiningDirs := append([]string{network.DirLayoutRootPlaceholder}, allowedDirs...)
+	for _, template := range []*string{&networkDirLayout.DataDir, &networkDirLayout.DBDir, &networkDirLayout.LogsDir} {
+		if *template == "" {
+			continue
+		}
+		// relative to the root dir, so it isn't taken from it twice
+		relTemplate := strings.TrimPrefix(*template, network.DirLayoutRootPlaceholder+"/")
+		confinedTemplate, err := getConfinedPath(relTemplate, ErrInvalidDirLayout, confiningDirs...)
+		if err != nil {
+			return network.DirLayout{}, err
+		}
+		*template = confinedTemplate
 	}
+	return networkDirLayout, nil
 }
 
 // if [conf] is a readable file path, returns the file contents
@@ -0,0 +1,537 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/network/fake"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/netrunner/utils/constants"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Returns a server running an in-memory network of [numNodes] nodes.
+func newTestServer(t *testing.T, numNodes int) (*server, *fake.Network) {
+	require := require.New(t)
+
+	nodeConfigs := make([]node.Config, numNodes)
+	for i := range nodeConfigs {
+		nodeConfigs[i] = node.Config{Name: fmt.Sprintf("node%d", i+1)}
+	}
+	nw, err := fake.New(nodeConfigs...)
+	require.NoError(err)
+
+	s := &server{
+		opMu:        newOpLocks(),
+		mu:          new(sync.RWMutex),
+		log:         logging.NoLog{},
+		rootCtx:     context.Background(),
+		closed:      make(chan struct{}),
+		asyncErrCh:  make(chan error, 1),
+		procSampler: newProcessSampler(),
+		progress:    newProgressHub(),
+		chaos:       newChaosMonkey(),
+		clusterInfo: &rpcpb.ClusterInfo{},
+		networkName: constants.DefaultNetworkName,
+		network: &localNetwork{
+			log:                 logging.NoLog{},
+			nw:                  nw,
+			nodeInfos:           map[string]*rpcpb.NodeInfo{},
+			customChainIDToInfo: map[ids.ID]chainInfo{},
+			subnets:             map[string]*rpcpb.SubnetInfo{},
+			stopCh:              make(chan struct{}),
+			options: localNetworkOptions{
+				rootDataDir: t.TempDir(),
+			},
+		},
+	}
+	require.NoError(s.network.UpdateNodeInfo())
+	s.updateClusterInfo()
+	return s, nw
+}
+
+func TestConcurrentNodeOperations(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, _ := newTestServer(t, 4)
+
+	// run with -race: the node infos are updated by both operations
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for _, name := range []string{"node1", "node2", "node3", "node4"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if _, err := s.PauseNode(ctx, &rpcpb.PauseNodeRequest{Name: name}); err != nil {
+				errs <- err
+				return
+			}
+			_, err := s.ResumeNode(ctx, &rpcpb.ResumeNodeRequest{Name: name})
+			errs <- err
+		}(name)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(err)
+	}
+
+	clusterInfo, err := s.copyClusterInfo()
+	require.NoError(err)
+	require.Equal([]string{"node1", "node2", "node3", "node4"}, clusterInfo.NodeNames)
+	for _, nodeInfo := range clusterInfo.NodeInfos {
+		require.False(nodeInfo.Paused)
+	}
+}
+
+func TestRenewPrimaryValidations(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, nw := newTestServer(t, 3)
+	events, unsubscribe := s.progress.subscribe()
+	defer unsubscribe()
+	release := nw.HoldRenewals()
+
+	// the renewals are issued in the background, so the expected ones are returned
+	resp, err := s.RenewPrimaryValidations(ctx, &rpcpb.RenewPrimaryValidationsRequest{
+		NodeNames: []string{"node1", "node3"},
+		Amount:    2000,
+		Duration:  3600,
+	})
+	require.NoError(err)
+	require.Len(resp.Validators, 2)
+	for i, nodeName := range []string{"node1", "node3"} {
+		v := resp.Validators[i]
+		require.Equal(nodeName, v.NodeName)
+		require.Empty(v.TxId)
+		require.Equal(uint64(2000), v.Weight)
+		require.Equal(int64(3600), v.EndTime-v.StartTime)
+	}
+
+	// the nodes are marked before returning, so a renewal of them conflicts
+	// with the one in the background
+	_, err = s.RenewPrimaryValidations(ctx, &rpcpb.RenewPrimaryValidationsRequest{NodeNames: []string{"node3"}})
+	require.ErrorIs(err, network.ErrRenewalInProgress)
+	release()
+	s.renewals.Wait()
+	event := <-events
+	require.Equal(network.PhaseRenewPrimaryValidations, event.Phase)
+	require.Equal(float64(100), event.Percent)
+	require.Equal("renewed 2 validations", event.Message)
+
+	// the nodes are no longer marked once renewed, and the failures in the
+	// background are reported
+	nw.SetRenewalError(errors.New("tx failed"))
+	_, err = s.RenewPrimaryValidations(ctx, &rpcpb.RenewPrimaryValidationsRequest{NodeNames: []string{"node3"}})
+	require.NoError(err)
+	s.renewals.Wait()
+	event = <-events
+	require.Equal(network.PhaseRenewPrimaryValidations, event.Phase)
+	require.Equal("failed to renew primary validations: tx failed", event.Message)
+
+	_, err = s.RenewPrimaryValidations(ctx, &rpcpb.RenewPrimaryValidationsRequest{NodeNames: []string{"node4"}})
+	require.ErrorIs(err, network.ErrNodeNotFound)
+
+	_, err = s.RenewPrimaryValidations(ctx, &rpcpb.RenewPrimaryValidationsRequest{Duration: math.MaxUint64})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestSaveSnapshotKeysInMemory(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newTestServer(t, 2)
+	s.network.options.stakingKeysStorage = network.StakingKeysStorageMemory
+
+	// the keys would be saved unencrypted
+	_, err := s.SaveSnapshot(context.Background(), &rpcpb.SaveSnapshotRequest{SnapshotName: "snapshot"})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	require.NotNil(s.getNetwork())
+}
+
+func TestExpandNodeGroups(t *testing.T) {
+	tests := []struct {
+		name              string
+		customNodeConfigs map[string]string
+		nodeGroups        []*rpcpb.NodeGroup
+		expected          map[string]string
+		expectedErr       error
+	}{
+		{
+			name:              "no groups",
+			customNodeConfigs: map[string]string{"node1": "{}"},
+			expected:          map[string]string{"node1": "{}"},
+		},
+		{
+			name:              "groups take the free names",
+			customNodeConfigs: map[string]string{"node2": "{}"},
+			nodeGroups: []*rpcpb.NodeGroup{
+				{Name: "a", Count: 2, NodeConfig: `{"a":1}`},
+				{Name: "b", Count: 1},
+			},
+			expected: map[string]string{"node1": `{"a":1}`, "node2": "{}", "node3": `{"a":1}`, "node4": ""},
+		},
+		{
+			name:        "empty group",
+			nodeGroups:  []*rpcpb.NodeGroup{{Name: "a"}},
+			expectedErr: ErrInvalidNodeGroup,
+		},
+		{
+			name:        "invalid node config",
+			nodeGroups:  []*rpcpb.NodeGroup{{Name: "a", Count: 1, NodeConfig: "[]"}},
+			expectedErr: ErrInvalidNodeGroup,
+		},
+		{
+			name:        "group above the maximum",
+			nodeGroups:  []*rpcpb.NodeGroup{{Name: "a", Count: math.MaxUint32}},
+			expectedErr: ErrTooManyNodes,
+		},
+		{
+			name:              "groups adding up above the maximum",
+			customNodeConfigs: map[string]string{"node1": "{}"},
+			nodeGroups: []*rpcpb.NodeGroup{
+				{Name: "a", Count: MaxNodes / 2},
+				{Name: "b", Count: MaxNodes / 2},
+			},
+			expectedErr: ErrTooManyNodes,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, err := expandNodeGroups(logging.NoLog{}, tt.customNodeConfigs, tt.nodeGroups)
+			require.ErrorIs(t, err, tt.expectedErr)
+			require.Equal(t, tt.expected, expanded)
+		})
+	}
+}
+
+func TestStartTooManyNodes(t *testing.T) {
+	s, _ := newTestServer(t, 1)
+
+	_, err := s.Start(context.Background(), &rpcpb.StartRequest{
+		NodeGroups: []*rpcpb.NodeGroup{{Name: "a", Count: math.MaxUint32}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestSimulateDowntimeCanceled(t *testing.T) {
+	require := require.New(t)
+
+	s, nw := newTestServer(t, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := s.SimulateDowntime(ctx, &rpcpb.SimulateDowntimeRequest{Name: "node1", Duration: 3600})
+	require.ErrorIs(err, context.DeadlineExceeded)
+
+	// the node is not left paused
+	node, err := nw.GetNode("node1")
+	require.NoError(err)
+	require.False(node.GetPaused())
+	clusterInfo, err := s.copyClusterInfo()
+	require.NoError(err)
+	require.False(clusterInfo.NodeInfos["node1"].Paused)
+}
+
+func TestStopNodeTimeout(t *testing.T) {
+	require := require.New(t)
+
+	s, _ := newTestServer(t, 2)
+
+	_, err := s.Stop(context.Background(), &rpcpb.StopRequest{NodeTimeout: -1})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	require.NotNil(s.getNetwork())
+
+	resp, err := s.Stop(context.Background(), &rpcpb.StopRequest{NodeTimeout: int64(time.Second)})
+	require.NoError(err)
+	require.Len(resp.NodeResults, 2)
+	require.Nil(s.getNetwork())
+}
+
+func TestTransferAndStake(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, _ := newTestServer(t, 2)
+
+	_, err := s.Transfer(ctx, &rpcpb.TransferRequest{To: "P-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p"})
+	require.ErrorIs(err, ErrZeroAmount)
+	transferResp, err := s.Transfer(ctx, &rpcpb.TransferRequest{To: "P-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p", Amount: 1})
+	require.NoError(err)
+	require.NotEmpty(transferResp.TxId)
+	require.Len(transferResp.ClusterInfo.NodeNames, 2)
+
+	_, err = s.Stake(ctx, &rpcpb.StakeRequest{NodeName: "node3"})
+	require.ErrorIs(err, ErrNodeNotFound)
+
+	// run with -race: the node infos are read while a node operation updates them
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = s.PauseNode(ctx, &rpcpb.PauseNodeRequest{Name: "node2"})
+	}()
+	stakeResp, err := s.Stake(ctx, &rpcpb.StakeRequest{NodeName: "node1", Delegate: true})
+	wg.Wait()
+	require.NoError(err)
+	require.NotEmpty(stakeResp.TxId)
+	require.Len(stakeResp.ClusterInfo.NodeNames, 2)
+
+	// the durations that overflow or end before the validations start are refused
+	for _, duration := range []uint64{math.MaxUint64, 20} {
+		_, err = s.Stake(ctx, &rpcpb.StakeRequest{NodeName: "node1", Duration: duration})
+		require.Equal(codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestGetNetworkStakingSpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      *rpcpb.StakingSpec
+		expected  network.StakingSpec
+		expectErr bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:     "durations",
+			spec:     &rpcpb.StakingSpec{ValidationStartOffset: 60, ValidationDuration: 3600, SubnetValidatorsWeight: 10},
+			expected: network.StakingSpec{ValidationStartOffset: time.Minute, ValidationDuration: time.Hour, SubnetValidatorsWeight: 10},
+		},
+		{
+			name:      "start offset overflow",
+			spec:      &rpcpb.StakingSpec{ValidationStartOffset: math.MaxUint64},
+			expectErr: true,
+		},
+		{
+			name:      "duration overflow",
+			spec:      &rpcpb.StakingSpec{ValidationDuration: uint64(math.MaxInt64/int64(time.Second)) + 1},
+			expectErr: true,
+		},
+		{
+			name:      "duration at the start offset",
+			spec:      &rpcpb.StakingSpec{ValidationStartOffset: 60, ValidationDuration: 60},
+			expectErr: true,
+		},
+		{
+			name:      "duration below the default start offset",
+			spec:      &rpcpb.StakingSpec{ValidationDuration: 10},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stakingSpec, err := getNetworkStakingSpec(tt.spec)
+			if tt.expectErr {
+				require.ErrorIs(t, err, ErrInvalidDuration)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, stakingSpec)
+		})
+	}
+}
+
+func TestGetNetworkSubnetSpec(t *testing.T) {
+	subnetSpec, err := getNetworkSubnetSpec(&rpcpb.SubnetSpec{ValidationDuration: 3600}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, subnetSpec.ValidationDuration)
+
+	for _, duration := range []uint64{math.MaxUint64, 60, 30} {
+		_, err := getNetworkSubnetSpec(&rpcpb.SubnetSpec{ValidationDuration: duration}, time.Minute)
+		require.ErrorIs(t, err, ErrInvalidDuration)
+	}
+}
+
+func TestPauseAllResumeAll(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, nw := newTestServer(t, 3)
+	require.NoError(nw.PauseNode(ctx, "node2"))
+	require.NoError(s.network.UpdateNodeInfo())
+	s.updateClusterInfo()
+
+	// the nodes already paused are not returned
+	pauseResp, err := s.PauseAll(ctx, &rpcpb.PauseAllRequest{})
+	require.NoError(err)
+	require.Equal([]string{"node1", "node3"}, pauseResp.NodeNames)
+	for _, nodeInfo := range pauseResp.ClusterInfo.NodeInfos {
+		require.True(nodeInfo.Paused)
+	}
+	pauseResp, err = s.PauseAll(ctx, &rpcpb.PauseAllRequest{})
+	require.NoError(err)
+	require.Empty(pauseResp.NodeNames)
+
+	resumeResp, err := s.ResumeAll(ctx, &rpcpb.ResumeAllRequest{})
+	require.NoError(err)
+	require.Equal([]string{"node1", "node2", "node3"}, resumeResp.NodeNames)
+	for _, nodeInfo := range resumeResp.ClusterInfo.NodeInfos {
+		require.False(nodeInfo.Paused)
+	}
+	resumeResp, err = s.ResumeAll(ctx, &rpcpb.ResumeAllRequest{})
+	require.NoError(err)
+	require.Empty(resumeResp.NodeNames)
+}
+
+func TestGetNetworkDirLayout(t *testing.T) {
+	tests := []struct {
+		name        string
+		dirLayout   *rpcpb.DirLayout
+		allowedDirs []string
+		expected    network.DirLayout
+		expectedErr error
+	}{
+		{
+			name: "no layout",
+		},
+		{
+			name:      "under the root dir",
+			dirLayout: &rpcpb.DirLayout{DataDir: "{root}/data/{node}", LogsDir: "logs/{node}"},
+			expected:  network.DirLayout{DataDir: "{root}/data/{node}", LogsDir: "{root}/logs/{node}"},
+		},
+		{
+			name:        "under an allowed dir",
+			dirLayout:   &rpcpb.DirLayout{DbDir: "/mnt/fast/{node}/db"},
+			allowedDirs: []string{"/mnt/logs", "/mnt/fast"},
+			expected:    network.DirLayout{DBDir: "/mnt/fast/{node}/db"},
+		},
+		{
+			name:        "outside the allowed dirs",
+			dirLayout:   &rpcpb.DirLayout{DbDir: "/etc/{node}"},
+			allowedDirs: []string{"/mnt/fast"},
+			expectedErr: ErrInvalidDirLayout,
+		},
+		{
+			name:        "leaving the root dir",
+			dirLayout:   &rpcpb.DirLayout{LogsDir: "{root}/../{node}"},
+			expectedErr: ErrInvalidDirLayout,
+		},
+		{
+			name:        "shared by the nodes",
+			dirLayout:   &rpcpb.DirLayout{DataDir: "{root}/data"},
+			expectedErr: ErrInvalidDirLayout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dirLayout, err := getNetworkDirLayout(tt.dirLayout, tt.allowedDirs)
+			require.ErrorIs(t, err, tt.expectedErr)
+			require.Equal(t, tt.expected, dirLayout)
+		})
+	}
+}
+
+// Fails after scaling, as a scale partially done.
+type failingScaleNetwork struct {
+	*fake.Network
+}
+
+var errScale = errors.New("scale failed")
+
+func (n *failingScaleNetwork) ScaleNetwork(ctx context.Context, numNodes uint32) error {
+	if err := n.Network.ScaleNetwork(ctx, numNodes); err != nil {
+		return err
+	}
+	return errScale
+}
+
+func TestScaleNetworkFailure(t *testing.T) {
+	require := require.New(t)
+
+	s, nw := newTestServer(t, 3)
+	s.network.nw = &failingScaleNetwork{Network: nw}
+
+	_, err := s.ScaleNetwork(context.Background(), &rpcpb.ScaleNetworkRequest{NumNodes: 5})
+	require.ErrorIs(err, errScale)
+
+	// the added nodes are in the cluster info
+	clusterInfo, err := s.copyClusterInfo()
+	require.NoError(err)
+	require.Equal([]string{"node1", "node2", "node3", "node4", "node5"}, clusterInfo.NodeNames)
+	require.Len(clusterInfo.NodeInfos, 5)
+}
+
+func TestGetBlockchainNodes(t *testing.T) {
+	_, nw := newTestServer(t, 4)
+	require.NoError(t, nw.PauseNode(context.Background(), "node3"))
+	nodes, err := nw.GetAllNodes()
+	require.NoError(t, err)
+
+	subnetID := ids.GenerateTestID().String()
+	subnets := map[string]*rpcpb.SubnetInfo{
+		subnetID: {SubnetParticipants: &rpcpb.SubnetParticipants{NodeNames: []string{"node2", "node3", "node5"}}},
+	}
+	tests := []struct {
+		name     string
+		subnetID string
+		expected []string
+	}{
+		{
+			name:     "subnet participants",
+			subnetID: subnetID,
+			expected: []string{"node2"},
+		},
+		{
+			name:     "unknown subnet",
+			subnetID: ids.GenerateTestID().String(),
+			expected: []string{"node1", "node2", "node4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockchainNodes := getBlockchainNodes(nodes, tt.subnetID, subnets)
+			nodeNames := maps.Keys(blockchainNodes)
+			sort.Strings(nodeNames)
+			require.Equal(t, tt.expected, nodeNames)
+		})
+	}
+}
+
+func TestNewNodeInfoRedactsSecretFlags(t *testing.T) {
+	require := require.New(t)
+
+	nw, err := fake.New(node.Config{
+		Name: "node1",
+		Flags: map[string]interface{}{
+			"staking-tls-key-file-content":    "key",
+			"staking-signer-key-file-content": "signer key",
+			"chain-aliases-content":           "aliases",
+			"api-auth-password":               "password",
+			"log-level":                       "debug",
+			"http-port":                       9650,
+		},
+	})
+	require.NoError(err)
+	nd, err := nw.GetNode("node1")
+	require.NoError(err)
+
+	nodeInfo, err := newNodeInfo(nd)
+	require.NoError(err)
+	require.Equal(map[string]string{
+		"staking-tls-key-file-content":    redactedFlagValue,
+		"staking-signer-key-file-content": redactedFlagValue,
+		"chain-aliases-content":           redactedFlagValue,
+		"api-auth-password":               redactedFlagValue,
+		"log-level":                       `"debug"`,
+		"http-port":                       "9650",
+	}, nodeInfo.Flags)
+}
@@ -0,0 +1,209 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	defaultStateSyncPollInterval = 2 * time.Second
+	cChainAlias                  = "C"
+)
+
+// chains of a state syncing node, in the order they finish syncing
+var stateSyncChains = []string{"P", "X", cChainAlias}
+
+var ErrNoRunningNode = errors.New("no running node in the network")
+
+// Adds a node configured to state sync the C-Chain from the network, and
+// streams its sync milestones until it is healthy.
+func (s *server) StateSyncNode(req *rpcpb.StateSyncNodeRequest, stream rpcpb.ControlService_StateSyncNodeServer) error {
+	s.log.Debug("StateSyncNode", zap.String("name", req.Name))
+
+	syncNode, refNode, err := s.addStateSyncNode(req)
+	if err != nil {
+		return err
+	}
+	nodeName := syncNode.GetName()
+	started := time.Now()
+
+	send := func(resp *rpcpb.StateSyncNodeResponse) error {
+		resp.NodeName = nodeName
+		resp.Elapsed = int64(time.Since(started))
+		if err := stream.Send(resp); err != nil {
+			if isClientCanceled(stream.Context().Err(), err) {
+				return nil
+			}
+			return err
+		}
+		return nil
+	}
+	if err := send(&rpcpb.StateSyncNodeResponse{Milestone: rpcpb.StateSyncMilestone_STATE_SYNC_MILESTONE_NODE_STARTED}); err != nil {
+		return err
+	}
+
+	interval := time.Duration(req.PollInterval)
+	if interval == 0 {
+		interval = defaultStateSyncPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	ctx := stream.Context()
+	bootstrapped := map[string]bool{}
+	var lastHeight uint64
+	for {
+		select {
+		case <-s.rootCtx.Done():
+			return nil
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// the APIs of the node fail until it is up, so the errors are ignored
+		for _, chain := range stateSyncChains {
+			if bootstrapped[chain] {
+				continue
+			}
+			isBootstrapped, err := syncNode.GetAPIClient().InfoAPI().IsBootstrapped(ctx, chain)
+			if err != nil || !isBootstrapped {
+				continue
+			}
+			bootstrapped[chain] = true
+			s.log.Info("state sync node chain bootstrapped", zap.String("node-name", nodeName), zap.String("chain", chain))
+			if err := send(&rpcpb.StateSyncNodeResponse{
+				Milestone: rpcpb.StateSyncMilestone_STATE_SYNC_MILESTONE_CHAIN_BOOTSTRAPPED,
+				Chain:     chain,
+			}); err != nil {
+				return err
+			}
+		}
+
+		height, err := syncNode.GetAPIClient().CChainEthAPI().BlockNumber(ctx)
+		if err == nil && height != lastHeight {
+			lastHeight = height
+			targetHeight, err := refNode.GetAPIClient().CChainEthAPI().BlockNumber(ctx)
+			if err != nil {
+				return fmt.Errorf("couldn't get C-Chain height of node %q: %w", refNode.GetName(), err)
+			}
+			if err := send(&rpcpb.StateSyncNodeResponse{
+				Milestone:    rpcpb.StateSyncMilestone_STATE_SYNC_MILESTONE_PROGRESS,
+				Height:       height,
+				TargetHeight: targetHeight,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(bootstrapped) < len(stateSyncChains) {
+			continue
+		}
+		health, err := syncNode.GetAPIClient().HealthAPI().Health(ctx, nil)
+		if err != nil || !health.Healthy {
+			continue
+		}
+		if err := s.refreshNodeInfos(); err != nil {
+			return err
+		}
+		s.log.Info("state sync node healthy", zap.String("node-name", nodeName), zap.Duration("elapsed", time.Since(started)))
+		return send(&rpcpb.StateSyncNodeResponse{
+			Milestone: rpcpb.StateSyncMilestone_STATE_SYNC_MILESTONE_NODE_HEALTHY,
+			Height:    lastHeight,
+		})
+	}
+}
+
+// Updates the node infos of [s.clusterInfo], once the state syncing node
+// is healthy.
+func (s *server) refreshNodeInfos() error {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return ErrNotBootstrapped
+	}
+	if err := s.network.UpdateNodeInfo(); err != nil {
+		return err
+	}
+	s.updateClusterNodeInfos()
+	return nil
+}
+
+// Returns the first node of the network not paused, by name.
+// Assumes [s.opMu] is held.
+func (s *server) getFirstRunningNode() (node.Node, error) {
+	nodes, err := s.network.nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	nodeNames := maps.Keys(nodes)
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		if !nodes[nodeName].GetPaused() {
+			return nodes[nodeName], nil
+		}
+	}
+	return nil, ErrNoRunningNode
+}
+
+// Adds the state syncing node of [req] to the network. Returns it, along with
+// the running node its C-Chain config is taken from, used as sync reference.
+func (s *server) addStateSyncNode(req *rpcpb.StateSyncNodeRequest) (node.Node, node.Node, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.network == nil {
+		return nil, nil, ErrNotBootstrapped
+	}
+
+	refNode, err := s.getFirstRunningNode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cChainConfig := map[string]interface{}{}
+	if refConfig := refNode.GetConfig().ChainConfigFiles[cChainAlias]; refConfig != "" {
+		if err := json.Unmarshal([]byte(refConfig), &cChainConfig); err != nil {
+			return nil, nil, fmt.Errorf("couldn't parse C-Chain config of node %q: %w", refNode.GetName(), err)
+		}
+	}
+	cChainConfig["state-sync-enabled"] = true
+	if req.StateSyncMinBlocks != 0 {
+		cChainConfig["state-sync-min-blocks"] = req.StateSyncMinBlocks
+	}
+	cChainConfigBytes, err := json.Marshal(cChainConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	syncNode, err := s.network.nw.AddNode(node.Config{
+		Name:             req.Name,
+		BinaryPath:       req.ExecPath,
+		Flags:            map[string]interface{}{},
+		RedirectStdout:   s.cfg.RedirectNodesOutput,
+		RedirectStderr:   s.cfg.RedirectNodesOutput,
+		ChainConfigFiles: map[string]string{cChainAlias: string(cChainConfigBytes)},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	s.log.Info("added state sync node", zap.String("node-name", syncNode.GetName()), zap.String("reference-node", refNode.GetName()))
+
+	if err := s.network.UpdateNodeInfo(); err != nil {
+		return nil, nil, err
+	}
+	s.updateClusterNodeInfos()
+	return syncNode, refNode, nil
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Returns the nodes of the network with their ports and subnets, and the
+// peer connections of the running nodes, optionally exported as JSON or DOT.
+func (s *server) GetTopology(
+	_ context.Context,
+	req *rpcpb.GetTopologyRequest,
+) (*rpcpb.GetTopologyResponse, error) {
+	nw := s.getNetwork()
+	if nw == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.log.Debug("GetTopology", zap.String("format", req.GetFormat().String()))
+
+	ctx, cancel := context.WithTimeout(s.rootCtx, waitForHealthyTimeout)
+	defer cancel()
+	topology, err := nw.GetTopology(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GetFormat() {
+	case rpcpb.TopologyFormat_TOPOLOGY_FORMAT_JSON:
+		exportBytes, err := protojson.MarshalOptions{Multiline: true}.Marshal(topology)
+		if err != nil {
+			return nil, err
+		}
+		topology.Export = string(exportBytes)
+	case rpcpb.TopologyFormat_TOPOLOGY_FORMAT_DOT:
+		topology.Export = topologyToDOT(topology)
+	}
+	return topology, nil
+}
+
+// Returns the nodes of the network with their ports and subnets, and the
+// peer connections reported by the info API of the running nodes. The
+// nodes are queried without holding [lc.lock], and a node failing to
+// report its peers has the error set instead.
+// Assumes [lc.lock] isn't held.
+func (lc *localNetwork) GetTopology(ctx context.Context) (*rpcpb.GetTopologyResponse, error) {
+	topology, runningNodes, err := lc.getTopologyNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeIDToName := map[string]string{}
+	for _, topologyNode := range topology.Nodes {
+		nodeIDToName[topologyNode.NodeId] = topologyNode.Name
+	}
+	for _, topologyNode := range topology.Nodes {
+		nodeName := topologyNode.Name
+		node, ok := runningNodes[nodeName]
+		if !ok {
+			continue
+		}
+		peers, err := node.GetAPIClient().InfoAPI().Peers(ctx)
+		if err != nil {
+			// the other nodes are still reported
+			topologyNode.Error = fmt.Sprintf("failure getting peers of node %q: %s", nodeName, err)
+			continue
+		}
+		edges := make([]*rpcpb.TopologyEdge, 0, len(peers))
+		for _, peer := range peers {
+			peerID := peer.ID.String()
+			edges = append(edges, &rpcpb.TopologyEdge{
+				From:     nodeName,
+				To:       nodeIDToName[peerID],
+				ToNodeId: peerID,
+				Ip:       peer.IP,
+			})
+		}
+		sort.Slice(edges, func(i, j int) bool {
+			return edges[i].ToNodeId < edges[j].ToNodeId
+		})
+		topology.Edges = append(topology.Edges, edges...)
+	}
+	return topology, nil
+}
+
+// Returns the topology of the network without its edges, and the running
+// nodes whose peers give them.
+// Assumes [lc.lock] isn't held.
+func (lc *localNetwork) getTopologyNodes() (*rpcpb.GetTopologyResponse, map[string]node.Node, error) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+
+	topology := &rpcpb.GetTopologyResponse{
+		Subnets: map[string]*rpcpb.SubnetParticipants{},
+	}
+
+	nodeSubnets := map[string][]string{}
+	for subnetID, subnetInfo := range lc.subnets {
+		nodeNames := subnetInfo.GetSubnetParticipants().GetNodeNames()
+		topology.Subnets[subnetID] = proto.Clone(subnetInfo.GetSubnetParticipants()).(*rpcpb.SubnetParticipants)
+		for _, nodeName := range nodeNames {
+			nodeSubnets[nodeName] = append(nodeSubnets[nodeName], subnetID)
+		}
+	}
+
+	nodeNames := maps.Keys(lc.nodeInfos)
+	sort.Strings(nodeNames)
+	runningNodes := map[string]node.Node{}
+	for _, nodeName := range nodeNames {
+		nodeInfo := lc.nodeInfos[nodeName]
+		subnetIDs := append([]string{}, nodeSubnets[nodeName]...)
+		sort.Strings(subnetIDs)
+		topology.Nodes = append(topology.Nodes, &rpcpb.TopologyNode{
+			Name:      nodeName,
+			NodeId:    nodeInfo.Id,
+			Uri:       nodeInfo.Uri,
+			ApiPort:   nodeInfo.ApiPort,
+			P2PPort:   nodeInfo.P2PPort,
+			Paused:    nodeInfo.Paused,
+			SubnetIds: subnetIDs,
+		})
+		if nodeInfo.Paused {
+			continue
+		}
+		node, err := lc.nw.GetNode(nodeName)
+		if err != nil {
+			return nil, nil, err
+		}
+		runningNodes[nodeName] = node
+	}
+	return topology, runningNodes, nil
+}
+
+// Returns [topology] as an undirected graphviz DOT graph. Nodes are labeled
+// with their ID and ports, subnets are drawn as clusters of their validators,
+// and peers that are not nodes of the network are drawn dashed.
+func topologyToDOT(topology *rpcpb.GetTopologyResponse) string {
+	var b strings.Builder
+	b.WriteString("graph network {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range topology.Nodes {
+		style := ""
+		if node.Paused {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %q [label=%q%s];\n",
+			node.Name,
+			fmt.Sprintf("%s\n%s\napi %d, p2p %d", node.Name, node.NodeId, node.ApiPort, node.P2PPort),
+			style,
+		)
+	}
+
+	subnetIDs := maps.Keys(topology.Subnets)
+	sort.Strings(subnetIDs)
+	for i, subnetID := range subnetIDs {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%q;\n", "subnet "+subnetID)
+		for _, nodeName := range topology.Subnets[subnetID].GetNodeNames() {
+			fmt.Fprintf(&b, "    %q;\n", nodeName)
+		}
+		b.WriteString("  }\n")
+	}
+
+	// both ends of a connection report it, but it is drawn once
+	drawn := map[[2]string]struct{}{}
+	externalPeers := map[string]struct{}{}
+	for _, edge := range topology.Edges {
+		to := edge.To
+		if to == "" {
+			to = edge.ToNodeId
+			if _, ok := externalPeers[to]; !ok {
+				externalPeers[to] = struct{}{}
+				fmt.Fprintf(&b, "  %q [style=dashed];\n", to)
+			}
+		}
+		key := [2]string{edge.From, to}
+		if to < edge.From {
+			key = [2]string{to, edge.From}
+		}
+		if _, ok := drawn[key]; ok {
+			continue
+		}
+		drawn[key] = struct{}{}
+		fmt.Fprintf(&b, "  %q -- %q;\n", edge.From, to)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
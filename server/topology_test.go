@@ -0,0 +1,47 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	apimocks "github.com/luxdefi/netrunner/api/mocks"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTopologyReleasesLock(t *testing.T) {
+	require := require.New(t)
+
+	s, nw := newTestServer(t, 2)
+	// the network lock is taken by other operations while the nodes are queried
+	locked := false
+	queried := []string{}
+	for _, nodeName := range []string{"node1", "node2"} {
+		nodeName := nodeName
+		client := &apimocks.Client{}
+		client.On("InfoAPI").Return(&testInfoClient{
+			onRequest: func() {
+				queried = append(queried, nodeName)
+				if !s.network.lock.TryLock() {
+					locked = true
+					return
+				}
+				s.network.lock.Unlock()
+			},
+		})
+		require.NoError(nw.SetAPIClient(nodeName, client))
+	}
+	_, err := s.PauseNode(context.Background(), &rpcpb.PauseNodeRequest{Name: "node2"})
+	require.NoError(err)
+
+	topology, err := s.GetTopology(context.Background(), &rpcpb.GetTopologyRequest{})
+	require.NoError(err)
+	require.False(locked)
+	// paused nodes are listed, but not queried
+	require.Len(topology.Nodes, 2)
+	require.True(topology.Nodes[1].Paused)
+	require.Equal([]string{"node1"}, queried)
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Time left before the network TTL expires at which a warning is logged.
+var ttlWarnings = []time.Duration{
+	10 * time.Minute,
+	time.Minute,
+	10 * time.Second,
+}
+
+// Stops [nw] once [ttl] elapses, logging warnings before that. Returns
+// early if the server is closed, or [nw] is stopped by other means.
+// Assumes [s.opMu] is held.
+func (s *server) scheduleNetworkStop(nw *localNetwork, ttl time.Duration) {
+	expiresAt := time.Now().Add(ttl)
+	s.mu.Lock()
+	s.clusterInfo.ExpiresAt = expiresAt.Unix()
+	s.mu.Unlock()
+	s.log.Info("network will be stopped when its TTL expires", zap.Time("expires-at", expiresAt))
+
+	go func() {
+		for _, left := range ttlWarnings {
+			if left >= ttl {
+				continue
+			}
+			if !s.waitTTL(nw, time.Until(expiresAt.Add(-left))) {
+				return
+			}
+			s.log.Warn("network TTL about to expire", zap.Duration("time-left", left))
+		}
+		if !s.waitTTL(nw, time.Until(expiresAt)) {
+			return
+		}
+
+		s.opMu.Lock()
+		defer s.opMu.Unlock()
+
+		if s.network != nw {
+			return
+		}
+		s.log.Warn("network TTL expired, stopping it")
+		s.stopAndRemoveNetwork(ErrNetworkExpired)
+	}()
+}
+
+// Waits for [d], returning false if the server is closed or [nw] is
+// stopped before.
+func (s *server) waitTTL(nw *localNetwork, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-nw.stopCh:
+		return false
+	case <-s.rootCtx.Done():
+		return false
+	}
+}
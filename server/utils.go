@@ -2,8 +2,11 @@ package server
 
 import (
 	"encoding/json"
+	"math"
 
 	"github.com/luxdefi/netrunner/rpcpb"
+	"golang.org/x/exp/maps"
+	"google.golang.org/protobuf/proto"
 )
 
 func deepCopy(i *rpcpb.ClusterInfo) (*rpcpb.ClusterInfo, error) {
@@ -17,3 +20,90 @@ func deepCopy(i *rpcpb.ClusterInfo) (*rpcpb.ClusterInfo, error) {
 	}
 	return &clusterInfo, nil
 }
+
+// Returns a copy of [m] where the values are cloned.
+func cloneProtoMap[T proto.Message](m map[string]T) map[string]T {
+	if m == nil {
+		return nil
+	}
+	cloned := make(map[string]T, len(m))
+	for k, v := range m {
+		cloned[k] = proto.Clone(v).(T)
+	}
+	return cloned
+}
+
+// Clears the parts of [clusterInfo] not in [fields], if given.
+func filterClusterInfo(clusterInfo *rpcpb.ClusterInfo, fields []rpcpb.ClusterInfoField) {
+	if clusterInfo == nil || len(fields) == 0 {
+		return
+	}
+	included := map[rpcpb.ClusterInfoField]bool{}
+	for _, field := range fields {
+		included[field] = true
+	}
+	if !included[rpcpb.ClusterInfoField_CLUSTER_INFO_FIELD_NODES] {
+		clusterInfo.NodeNames = nil
+		clusterInfo.NodeInfos = nil
+		clusterInfo.AttachedPeerInfos = nil
+		clusterInfo.Uptimes = nil
+	}
+	if !included[rpcpb.ClusterInfoField_CLUSTER_INFO_FIELD_SUBNETS] {
+		clusterInfo.Subnets = nil
+		clusterInfo.CustomChains = nil
+		clusterInfo.CustomChainsHealthy = false
+	}
+}
+
+// Resource usage changes below these are not reported by delta status
+// streams, as the usage is sampled again on every push.
+const (
+	cpuPercentDeltaThreshold = 5
+	rssDeltaThresholdPercent = 10
+	numFdsDeltaThreshold     = 16
+)
+
+// Returns true if [clusterInfo] has no change to report since [lastSent].
+// The resource usage of a node only counts as changed once it moved past
+// the thresholds above, so it is compared with the one last sent.
+func isClusterInfoUnchanged(lastSent *rpcpb.ClusterInfo, clusterInfo *rpcpb.ClusterInfo) bool {
+	if lastSent == nil || clusterInfo == nil {
+		return lastSent == clusterInfo
+	}
+	clusterInfo = proto.Clone(clusterInfo).(*rpcpb.ClusterInfo)
+	for name, nodeInfo := range clusterInfo.NodeInfos {
+		lastNodeInfo, ok := lastSent.NodeInfos[name]
+		if !ok || nodeInfo == nil || lastNodeInfo == nil {
+			continue
+		}
+		if math.Abs(nodeInfo.CpuPercent-lastNodeInfo.CpuPercent) < cpuPercentDeltaThreshold {
+			nodeInfo.CpuPercent = lastNodeInfo.CpuPercent
+		}
+		rssDelta := math.Abs(float64(nodeInfo.Rss) - float64(lastNodeInfo.Rss))
+		if rssDelta*100 < float64(lastNodeInfo.Rss)*rssDeltaThresholdPercent {
+			nodeInfo.Rss = lastNodeInfo.Rss
+		}
+		numFdsDelta := nodeInfo.NumFds - lastNodeInfo.NumFds
+		if numFdsDelta > -numFdsDeltaThreshold && numFdsDelta < numFdsDeltaThreshold {
+			nodeInfo.NumFds = lastNodeInfo.NumFds
+		}
+	}
+	return proto.Equal(clusterInfo, lastSent)
+}
+
+// Returns true if all [nodeVersions] have the same node, database and
+// VM versions.
+func areVersionsConsistent(nodeVersions []*rpcpb.NodeVersions) bool {
+	if len(nodeVersions) == 0 {
+		return true
+	}
+	ref := nodeVersions[0]
+	for _, v := range nodeVersions[1:] {
+		if v.Version != ref.Version ||
+			v.DatabaseVersion != ref.DatabaseVersion ||
+			!maps.Equal(v.VmVersions, ref.VmVersions) {
+			return false
+		}
+	}
+	return true
+}
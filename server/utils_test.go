@@ -0,0 +1,76 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsClusterInfoUnchanged(t *testing.T) {
+	lastSent := &rpcpb.ClusterInfo{
+		Healthy: true,
+		NodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {Name: "node1", CpuPercent: 10, Rss: 1000, NumFds: 100},
+		},
+	}
+	tests := []struct {
+		name      string
+		nodeInfo  *rpcpb.NodeInfo
+		healthy   bool
+		unchanged bool
+	}{
+		{
+			name:      "same",
+			nodeInfo:  &rpcpb.NodeInfo{Name: "node1", CpuPercent: 10, Rss: 1000, NumFds: 100},
+			healthy:   true,
+			unchanged: true,
+		},
+		{
+			name:      "usage below the thresholds",
+			nodeInfo:  &rpcpb.NodeInfo{Name: "node1", CpuPercent: 12.345, Rss: 1050, NumFds: 90},
+			healthy:   true,
+			unchanged: true,
+		},
+		{
+			name:     "cpu above the threshold",
+			nodeInfo: &rpcpb.NodeInfo{Name: "node1", CpuPercent: 15, Rss: 1000, NumFds: 100},
+			healthy:  true,
+		},
+		{
+			name:     "rss above the threshold",
+			nodeInfo: &rpcpb.NodeInfo{Name: "node1", CpuPercent: 10, Rss: 800, NumFds: 100},
+			healthy:  true,
+		},
+		{
+			name:     "fds above the threshold",
+			nodeInfo: &rpcpb.NodeInfo{Name: "node1", CpuPercent: 10, Rss: 1000, NumFds: 116},
+			healthy:  true,
+		},
+		{
+			name:     "other field",
+			nodeInfo: &rpcpb.NodeInfo{Name: "node1", CpuPercent: 10, Rss: 1000, NumFds: 100, Paused: true},
+			healthy:  true,
+		},
+		{
+			name:     "network field",
+			nodeInfo: &rpcpb.NodeInfo{Name: "node1", CpuPercent: 10, Rss: 1000, NumFds: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			clusterInfo := &rpcpb.ClusterInfo{
+				Healthy:   tt.healthy,
+				NodeInfos: map[string]*rpcpb.NodeInfo{"node1": tt.nodeInfo},
+			}
+			require.Equal(tt.unchanged, isClusterInfoUnchanged(lastSent, clusterInfo))
+			// the sampled usage is sent as is
+			require.Equal(tt.nodeInfo.CpuPercent, clusterInfo.NodeInfos["node1"].CpuPercent)
+		})
+	}
+}
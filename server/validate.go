@@ -0,0 +1,273 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/luxdefi/netrunner/network"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/netrunner/utils"
+	"github.com/luxdefi/node/config"
+	"github.com/luxdefi/node/utils/logging"
+	"github.com/luxdefi/node/utils/set"
+)
+
+// Checks [req] the way Start would before and while launching the nodes
+// (exec path, VM plugins, genesis and node configs, ports, spec consistency),
+// without launching anything. All the problems found are returned at once,
+// wrapped in ErrValidationFailed.
+func (s *server) validateStart(req *rpcpb.StartRequest) error {
+	s.log.Debug("validating start request")
+
+	problems := []error{}
+	addProblem := func(err error) {
+		problems = append(problems, err)
+	}
+
+	numNodes := req.GetNumNodes()
+	if len(req.GetCustomNodeConfigs()) > 0 {
+		numNodes = uint32(len(req.GetCustomNodeConfigs()))
+	}
+	if numNodes < MinNodes {
+		addProblem(ErrNotEnoughNodesForStart)
+	}
+	if numNodes > MaxNodes {
+		addProblem(fmt.Errorf("%w: %d nodes, the maximum is %d", ErrTooManyNodes, numNodes, MaxNodes))
+	}
+	if err := utils.CheckExecPath(req.GetExecPath()); err != nil {
+		addProblem(err)
+	}
+
+	upgradeConfigs, err := mergeUpgradeSpecs(req.GetUpgradeConfigs(), req.GetUpgradeSpecs())
+	if err != nil {
+		addProblem(err)
+	}
+	networkAccess, err := getNetworkAccess(req.GetNetworkAccess())
+	if err != nil {
+		addProblem(err)
+	}
+	portRange, err := getNetworkPortRange(req.GetPortRange())
+	if err != nil {
+		addProblem(err)
+	}
+	dirLayout, err := getNetworkDirLayout(req.GetDirLayout(), s.cfg.NodeDirsAllowed)
+	if err != nil {
+		addProblem(err)
+	}
+	if _, err := getNetworkBackend(req.GetBackend()); err != nil {
+		addProblem(err)
+	}
+	if ttl := time.Duration(req.GetTtl()); ttl < 0 {
+		addProblem(fmt.Errorf("%w: %s", ErrInvalidTTL, ttl))
+	}
+
+	externalNetwork := getNetworkExternalNetwork(req.GetExternalNetwork())
+	if externalNetwork != nil && len(req.GetBlockchainSpecs()) > 0 {
+		addProblem(ErrExternalNetworkChains)
+	}
+	forkSpec, err := getNetworkForkSpec(req.GetFork())
+	if err != nil {
+		addProblem(err)
+	}
+	if forkSpec != nil && externalNetwork != nil {
+		addProblem(fmt.Errorf("%w: an external network can't be forked", ErrInvalidForkSpec))
+	}
+	if req.GetCChainStatePath() != "" {
+		if externalNetwork != nil {
+			addProblem(ErrExternalNetworkState)
+		}
+		if path, err := getCChainStatePath(req.GetCChainStatePath(), "", s.cfg.SnapshotsDir); err != nil {
+			addProblem(err)
+		} else if _, err := network.ReadCChainAlloc(path); err != nil {
+			addProblem(fmt.Errorf("invalid C-Chain state: %w", err))
+		}
+	}
+
+	stakingSpec, err := getNetworkStakingSpec(req.GetStakingSpec())
+	if err != nil {
+		addProblem(err)
+	}
+
+	// build the network config the same way Start does, to check the
+	// genesis, the node configs and their ports
+	lc := &localNetwork{
+		log:       s.log,
+		execPath:  req.GetExecPath(),
+		pluginDir: req.GetPluginDir(),
+		options: localNetworkOptions{
+			execPath:            req.GetExecPath(),
+			numNodes:            numNodes,
+			trackSubnets:        req.GetWhitelistedSubnets(),
+			globalNodeConfig:    req.GetGlobalNodeConfig(),
+			customNodeConfigs:   req.GetCustomNodeConfigs(),
+			chainConfigs:        req.ChainConfigs,
+			upgradeConfigs:      upgradeConfigs,
+			subnetConfigs:       req.SubnetConfigs,
+			reassignPortsIfUsed: req.GetReassignPortsIfUsed(),
+			dynamicPorts:        req.GetDynamicPorts(),
+			stakingSpec:         stakingSpec,
+			networkAccess:       networkAccess,
+			portRange:           portRange,
+			dirLayout:           dirLayout,
+			externalNetwork:     externalNetwork,
+			externalGenesis:     req.GetExternalNetwork().GetGenesis(),
+		},
+	}
+	nodeNames := set.Set[string]{}
+	if err := lc.createConfig(); err != nil {
+		addProblem(fmt.Errorf("invalid node configs: %w", err))
+	} else {
+		if err := lc.cfg.Validate(); err != nil {
+			addProblem(fmt.Errorf("invalid network config: %w", err))
+		}
+		for _, nodeConfig := range lc.cfg.NodeConfigs {
+			nodeNames.Add(nodeConfig.Name)
+		}
+		if !req.GetReassignPortsIfUsed() {
+			for _, err := range checkNodePorts(lc.cfg) {
+				addProblem(err)
+			}
+		}
+	}
+
+	for _, err := range validateBlockchainSpecs(s.log, req.GetBlockchainSpecs(), true, req.GetPluginDir(), stakingSpec.GetValidationStartOffset(), nodeNames, nil) {
+		addProblem(err)
+	}
+
+	return validationError(problems)
+}
+
+// Checks [req] the way CreateBlockchains would, without issuing any tx.
+// All the problems found are returned at once, wrapped in ErrValidationFailed.
+// Assumes [s.network] is not nil.
+func (s *server) validateCreateBlockchains(req *rpcpb.CreateBlockchainsRequest) error {
+	s.log.Debug("validating create blockchains request")
+
+	problems := []error{}
+	if len(req.GetBlockchainSpecs()) == 0 {
+		problems = append(problems, ErrNoBlockchainSpec)
+	}
+	nodeNamesList, err := s.network.nw.GetNodeNames()
+	if err != nil {
+		return err
+	}
+	nodeNames := set.Set[string]{}
+	nodeNames.Add(nodeNamesList...)
+	subnetIDs := set.Set[string]{}
+	for subnetID := range s.clusterInfo.Subnets {
+		subnetIDs.Add(subnetID)
+	}
+	problems = append(problems, validateBlockchainSpecs(s.log, req.GetBlockchainSpecs(), false, s.network.pluginDir, s.network.options.stakingSpec.GetValidationStartOffset(), nodeNames, subnetIDs)...)
+	return validationError(problems)
+}
+
+// Returns the problems of [specs]: the common mistakes found by
+// utils.LintBlockchainSpecs, the ones found when parsing them (VM name,
+// plugin binary, upgrades), and the inconsistencies between them and with
+// the network nodes [nodeNames] and subnets [subnetIDs].
+// Subnet participants missing from [nodeNames] are not problems, as
+// they are added to the network.
+func validateBlockchainSpecs(
+	log logging.Logger,
+	specs []*rpcpb.BlockchainSpec,
+	isNewEmptyNetwork bool,
+	pluginDir string,
+	startOffset time.Duration,
+	nodeNames set.Set[string],
+	subnetIDs set.Set[string],
+) []error {
+	problems := utils.LintBlockchainSpecs(specs, nil)
+	aliases := set.Set[string]{}
+	for i, spec := range specs {
+		addProblem := func(err error) {
+			problems = append(problems, fmt.Errorf("blockchain spec %d (vm %q): %w", i, spec.GetVmName(), err))
+		}
+		chainSpec, err := getNetworkBlockchainSpec(log, spec, isNewEmptyNetwork, pluginDir, startOffset)
+		if err != nil {
+			addProblem(err)
+			continue
+		}
+		if chainSpec.SubnetID != nil && subnetIDs != nil && !subnetIDs.Contains(*chainSpec.SubnetID) {
+			addProblem(fmt.Errorf("%w: %s", ErrSubnetNotFound, *chainSpec.SubnetID))
+		}
+		if alias := chainSpec.BlockchainAlias; alias != "" {
+			if aliases.Contains(alias) {
+				addProblem(fmt.Errorf("%w: alias %q is used by another blockchain spec", ErrInvalidChain, alias))
+			}
+			aliases.Add(alias)
+		}
+		// per node chain configs of non participants are reported by the linter
+		hasParticipants := false
+		if chainSpec.SubnetSpec != nil {
+			hasParticipants = len(chainSpec.SubnetSpec.Participants) > 0
+			if threshold := chainSpec.SubnetSpec.Threshold; len(chainSpec.SubnetSpec.Owners) > 0 && int(threshold) > len(chainSpec.SubnetSpec.Owners) {
+				addProblem(fmt.Errorf("subnet owners threshold %d is greater than the number of owners %d", threshold, len(chainSpec.SubnetSpec.Owners)))
+			}
+		}
+		for nodeName := range chainSpec.PerNodeChainConfig {
+			if !hasParticipants && !nodeNames.Contains(nodeName) {
+				addProblem(fmt.Errorf("%w: per node chain config given for %q", ErrNodeNotFound, nodeName))
+			}
+		}
+	}
+	return problems
+}
+
+// Returns the problems of the API and P2P ports set on the nodes of [cfg]:
+// ports used by more than one node, and ports not free on this host.
+// Nodes without ports set get free ones on start.
+func checkNodePorts(cfg network.Config) []error {
+	problems := []error{}
+	usedBy := map[uint16]string{}
+	for _, nodeConfig := range cfg.NodeConfigs {
+		for _, portKey := range []string{config.HTTPPortKey, config.StakingPortKey} {
+			portIntf, ok := nodeConfig.Flags[portKey]
+			if !ok {
+				portIntf, ok = cfg.Flags[portKey]
+			}
+			if !ok {
+				continue
+			}
+			var port uint16
+			switch gotPort := portIntf.(type) {
+			case int:
+				port = uint16(gotPort)
+			case float64:
+				port = uint16(gotPort)
+			default:
+				problems = append(problems, fmt.Errorf("node %q: expected flag %q to be int/float64 but got %T", nodeConfig.Name, portKey, portIntf))
+				continue
+			}
+			if otherNode, ok := usedBy[port]; ok {
+				problems = append(problems, fmt.Errorf("%w: port %d is set on nodes %q and %q", ErrInvalidPort, port, otherNode, nodeConfig.Name))
+				continue
+			}
+			usedBy[port] = nodeConfig.Name
+			l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+			if err != nil {
+				problems = append(problems, fmt.Errorf("node %q: port %d is not free: %w", nodeConfig.Name, port, err))
+				continue
+			}
+			_ = l.Close()
+		}
+	}
+	return problems
+}
+
+// Joins [problems] into a single ErrValidationFailed error, one problem
+// per line. Returns nil if there are no problems.
+func validationError(problems []error) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	lines := make([]string, len(problems))
+	for i, problem := range problems {
+		lines[i] = "- " + problem.Error()
+	}
+	return fmt.Errorf("%w: %d problems found\n%s", ErrValidationFailed, len(problems), strings.Join(lines, "\n"))
+}
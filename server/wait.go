@@ -0,0 +1,258 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/ids"
+	luxd_constants "github.com/luxdefi/node/utils/constants"
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	defaultWaitForTimeout      = 5 * time.Minute
+	defaultWaitForPollInterval = time.Second
+)
+
+// Waits until all the conditions of [req] hold at the same time, so
+// scripts don't need their own polling loops. On timeout, the returned
+// error lists the conditions that didn't hold on the last check.
+func (s *server) WaitFor(
+	ctx context.Context,
+	req *rpcpb.WaitForRequest,
+) (*rpcpb.WaitForResponse, error) {
+	if req.GetHealthyNodes() == 0 && len(req.GetBootstrappedBlockchains()) == 0 && len(req.GetSubnetValidators()) == 0 {
+		return nil, ErrNoWaitConditions
+	}
+	if req.GetTimeout() < 0 || req.GetPollInterval() < 0 {
+		return nil, invalidArgumentError(fmt.Errorf("%w: negative timeout or poll interval", ErrInvalidWaitConfig))
+	}
+	subnetValidators := map[ids.ID]uint32{}
+	for subnetIDStr, numValidators := range req.GetSubnetValidators() {
+		subnetID := luxd_constants.PrimaryNetworkID
+		if subnetIDStr != "" {
+			var err error
+			subnetID, err = ids.FromString(subnetIDStr)
+			if err != nil {
+				return nil, err
+			}
+		}
+		subnetValidators[subnetID] = numValidators
+	}
+
+	s.log.Debug("WaitFor",
+		zap.Uint32("healthy-nodes", req.GetHealthyNodes()),
+		zap.Strings("bootstrapped-blockchains", req.GetBootstrappedBlockchains()),
+		zap.Int("subnets", len(subnetValidators)),
+	)
+
+	timeout := time.Duration(req.GetTimeout())
+	if timeout == 0 {
+		timeout = defaultWaitForTimeout
+	}
+	pollInterval := time.Duration(req.GetPollInterval())
+	if pollInterval == 0 {
+		pollInterval = defaultWaitForPollInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	for {
+		// the network can be replaced while waiting, so it is got on each check
+		nw := s.getNetwork()
+		if nw == nil {
+			return nil, ErrNotBootstrapped
+		}
+		view, err := nw.GetView()
+		if err != nil {
+			return nil, err
+		}
+		unmet := view.getUnmetWaitConditions(ctx, req.GetHealthyNodes(), req.GetBootstrappedBlockchains(), subnetValidators)
+		if len(unmet) == 0 {
+			return &rpcpb.WaitForResponse{Elapsed: int64(time.Since(started))}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: unmet conditions: %s", ctx.Err(), strings.Join(unmet, "; "))
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// State of the network read under [lc.lock], so the nodes can be queried
+// without holding it, and other operations don't wait for slow node APIs.
+type networkView struct {
+	// running nodes, by name
+	nodes map[string]node.Node
+	// running node with min API port, nil if there is none
+	refNode node.Node
+	// names of the participants of the subnet of each custom chain.
+	// Chains not in the map are run by all the nodes
+	chainParticipants map[ids.ID][]string
+}
+
+// Assumes [lc.lock] isn't held.
+func (lc *localNetwork) GetView() (*networkView, error) {
+	lc.lock.Lock()
+	defer lc.lock.Unlock()
+
+	return lc.getView()
+}
+
+// Assumes [lc.lock] is held.
+func (lc *localNetwork) getView() (*networkView, error) {
+	nodes, err := lc.nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	view := &networkView{
+		nodes:             map[string]node.Node{},
+		chainParticipants: map[ids.ID][]string{},
+	}
+	for nodeName, node := range nodes {
+		if !node.GetPaused() {
+			view.nodes[nodeName] = node
+		}
+	}
+	view.refNode, _ = lc.getMinAPIPortNode()
+	for chainID, chainInfo := range lc.customChainIDToInfo {
+		if subnetInfo, ok := lc.subnets[chainInfo.subnetID.String()]; ok {
+			view.chainParticipants[chainID] = append([]string{}, subnetInfo.GetSubnetParticipants().GetNodeNames()...)
+		}
+	}
+	return view, nil
+}
+
+// Returns the running node used for network wide queries.
+func (v *networkView) getRefNode() (node.Node, error) {
+	if v.refNode == nil {
+		return nil, errNoRunningNode
+	}
+	return v.refNode, nil
+}
+
+// Returns a description of each given condition that doesn't hold on the
+// network. Errors getting the state of the network are reported as unmet
+// conditions, as the nodes may still be starting.
+func (v *networkView) getUnmetWaitConditions(
+	ctx context.Context,
+	healthyNodes uint32,
+	bootstrappedBlockchains []string,
+	subnetValidators map[ids.ID]uint32,
+) []string {
+	unmet := []string{}
+	if healthyNodes > 0 {
+		numHealthy := v.getNumHealthyNodes(ctx)
+		if numHealthy < healthyNodes {
+			unmet = append(unmet, fmt.Sprintf("%d nodes healthy: %d healthy", healthyNodes, numHealthy))
+		}
+	}
+	for _, chain := range bootstrappedBlockchains {
+		notBootstrapped, err := v.getNotBootstrappedNodes(ctx, chain)
+		switch {
+		case err != nil:
+			unmet = append(unmet, fmt.Sprintf("blockchain %s bootstrapped: %s", chain, err))
+		case len(notBootstrapped) > 0:
+			unmet = append(unmet, fmt.Sprintf("blockchain %s bootstrapped: not bootstrapped on %s", chain, strings.Join(notBootstrapped, ", ")))
+		}
+	}
+	subnetIDs := maps.Keys(subnetValidators)
+	sort.Slice(subnetIDs, func(i, j int) bool {
+		return subnetIDs[i].String() < subnetIDs[j].String()
+	})
+	for _, subnetID := range subnetIDs {
+		numValidators, err := v.getNumValidators(ctx, subnetID)
+		switch {
+		case err != nil:
+			unmet = append(unmet, fmt.Sprintf("subnet %s has %d validators: %s", subnetID, subnetValidators[subnetID], err))
+		case numValidators < subnetValidators[subnetID]:
+			unmet = append(unmet, fmt.Sprintf("subnet %s has %d validators: %d validators", subnetID, subnetValidators[subnetID], numValidators))
+		}
+	}
+	return unmet
+}
+
+// Returns the number of running nodes whose health API reports healthy.
+func (v *networkView) getNumHealthyNodes(ctx context.Context) uint32 {
+	numHealthy := uint32(0)
+	for _, node := range v.nodes {
+		health, err := node.GetAPIClient().HealthAPI().Health(ctx, nil)
+		if err == nil && health.Healthy {
+			numHealthy++
+		}
+	}
+	return numHealthy
+}
+
+// Returns the sorted names of the running nodes that run [chain], given
+// by ID or alias, and didn't finish bootstrapping it.
+func (v *networkView) getNotBootstrappedNodes(ctx context.Context, chain string) ([]string, error) {
+	chainID, err := v.getChainID(ctx, chain)
+	if err != nil {
+		return nil, err
+	}
+	notBootstrapped := []string{}
+	for _, nodeName := range v.getChainNodeNames(chainID) {
+		isBootstrapped, err := v.nodes[nodeName].GetAPIClient().InfoAPI().IsBootstrapped(ctx, chainID.String())
+		if err != nil || !isBootstrapped {
+			notBootstrapped = append(notBootstrapped, nodeName)
+		}
+	}
+	return notBootstrapped, nil
+}
+
+// Returns the sorted names of the running nodes that run [chainID]. Nodes
+// not participating in the subnet of a custom chain don't run it.
+func (v *networkView) getChainNodeNames(chainID ids.ID) []string {
+	nodeNames := maps.Keys(v.nodes)
+	if participants, ok := v.chainParticipants[chainID]; ok {
+		nodeNames = []string{}
+		for _, nodeName := range participants {
+			if _, ok := v.nodes[nodeName]; ok {
+				nodeNames = append(nodeNames, nodeName)
+			}
+		}
+	}
+	sort.Strings(nodeNames)
+	return nodeNames
+}
+
+// Returns the ID of [chain], given by ID or alias.
+func (v *networkView) getChainID(ctx context.Context, chain string) (ids.ID, error) {
+	if chainID, err := ids.FromString(chain); err == nil {
+		return chainID, nil
+	}
+	node, err := v.getRefNode()
+	if err != nil {
+		return ids.Empty, err
+	}
+	chainID, err := node.GetAPIClient().InfoAPI().GetBlockchainID(ctx, chain)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("failure resolving blockchain alias %q: %w", chain, err)
+	}
+	return chainID, nil
+}
+
+// Returns the number of current validators of [subnetID], as seen by the
+// reference node.
+func (v *networkView) getNumValidators(ctx context.Context, subnetID ids.ID) (uint32, error) {
+	refNode, err := v.getRefNode()
+	if err != nil {
+		return 0, err
+	}
+	vdrs, err := refNode.GetAPIClient().PChainAPI().GetCurrentValidators(ctx, subnetID, nil)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(len(vdrs)), nil
+}
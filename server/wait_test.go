@@ -0,0 +1,130 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apimocks "github.com/luxdefi/netrunner/api/mocks"
+	"github.com/luxdefi/netrunner/network/fake"
+	"github.com/luxdefi/netrunner/network/node"
+	"github.com/luxdefi/netrunner/rpcpb"
+	"github.com/luxdefi/node/api/info"
+	"github.com/luxdefi/node/ids"
+	"github.com/luxdefi/node/utils/rpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Info API client reporting whether the node bootstrapped any chain,
+// and its version.
+type testInfoClient struct {
+	info.Client
+	bootstrapped bool
+	version      string
+	peersErr     error
+	// called on each request, if set
+	onRequest func()
+}
+
+func (c *testInfoClient) IsBootstrapped(context.Context, string, ...rpc.Option) (bool, error) {
+	if c.onRequest != nil {
+		c.onRequest()
+	}
+	return c.bootstrapped, nil
+}
+
+func (c *testInfoClient) GetNodeVersion(ctx context.Context, _ ...rpc.Option) (*info.GetNodeVersionReply, error) {
+	if c.onRequest != nil {
+		c.onRequest()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return &info.GetNodeVersionReply{Version: c.version}, nil
+}
+
+func (c *testInfoClient) Peers(context.Context, ...rpc.Option) ([]info.Peer, error) {
+	if c.onRequest != nil {
+		c.onRequest()
+	}
+	return nil, c.peersErr
+}
+
+// Returns a running node whose info API reports [bootstrapped].
+func newTestBootstrappedNode(name string, port uint16, bootstrapped bool) node.Node {
+	client := &apimocks.Client{}
+	client.On("InfoAPI").Return(&testInfoClient{bootstrapped: bootstrapped})
+	return fake.NewNode(node.Config{Name: name}, client, port, port+1)
+}
+
+func TestWaitForInvalidConfig(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	s, _ := newTestServer(t, 1)
+
+	_, err := s.WaitFor(ctx, &rpcpb.WaitForRequest{})
+	require.ErrorIs(err, ErrNoWaitConditions)
+	_, err = s.WaitFor(ctx, &rpcpb.WaitForRequest{HealthyNodes: 1, Timeout: -1})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+	_, err = s.WaitFor(ctx, &rpcpb.WaitForRequest{HealthyNodes: 1, PollInterval: -1})
+	require.Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func TestWaitForCanceled(t *testing.T) {
+	require := require.New(t)
+
+	// without running nodes, the validators can't be counted
+	s, _ := newTestServer(t, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := s.WaitFor(ctx, &rpcpb.WaitForRequest{
+		SubnetValidators: map[string]uint32{"": 1},
+		PollInterval:     int64(10 * time.Millisecond),
+	})
+	require.ErrorIs(err, context.DeadlineExceeded)
+	require.ErrorContains(err, errNoRunningNode.Error())
+}
+
+func TestGetNotBootstrappedNodes(t *testing.T) {
+	customChainID := ids.GenerateTestID()
+	view := &networkView{
+		nodes: map[string]node.Node{
+			"node1": newTestBootstrappedNode("node1", 9650, false),
+			"node2": newTestBootstrappedNode("node2", 9652, true),
+			"node3": newTestBootstrappedNode("node3", 9654, false),
+		},
+		// node4 is paused
+		chainParticipants: map[ids.ID][]string{customChainID: {"node2", "node3", "node4"}},
+	}
+
+	tests := []struct {
+		name     string
+		chainID  ids.ID
+		expected []string
+	}{
+		{
+			name:     "primary network chain",
+			chainID:  ids.GenerateTestID(),
+			expected: []string{"node1", "node3"},
+		},
+		{
+			name:     "custom chain",
+			chainID:  customChainID,
+			expected: []string{"node3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notBootstrapped, err := view.getNotBootstrappedNodes(context.Background(), tt.chainID.String())
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, notBootstrapped)
+		})
+	}
+}
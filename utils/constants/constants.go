@@ -9,6 +9,63 @@ const (
 	LogNameControl = "control"
 	LogNameTest    = "test"
 	RootDirPrefix  = "network-runner-root-data"
+
+	// gRPC metadata key used by clients to target a network by name
+	NetworkNameMetadataKey = "network-name"
+	// gRPC metadata key of the API token, as "Bearer <token>"
+	AuthorizationMetadataKey = "authorization"
+	// environment variable with the API token used by netrunner control
+	APITokenEnvVar = "NETRUNNER_API_TOKEN"
+	// environment variable with the passphrase of the staking keystores
+	StakingKeystorePassphraseEnvVar = "NETRUNNER_KEYSTORE_PASSPHRASE"
+	// environment variable with the passphrase of the encrypted snapshots
+	// used by netrunner control
+	SnapshotPassphraseEnvVar = "NETRUNNER_SNAPSHOT_PASSPHRASE"
+	// name of the network when none is given on start
+	DefaultNetworkName = "default"
+	// file in the server log directory the control calls are recorded to
+	AuditLogFileName = "audit.jsonl"
+)
+
+// Optional features of the RPC server, reported by RPCVersion so
+// clients can detect the ones missing in older servers.
+const (
+	CapabilityTTL                  = "ttl"
+	CapabilityReconcile            = "reconcile"
+	CapabilityScaleNetwork         = "scale-network"
+	CapabilityNodeGroups           = "node-groups"
+	CapabilityLogsShardSize        = "logs-shard-size"
+	CapabilityStreamLogs           = "stream-logs"
+	CapabilityPeerBehavior         = "peer-behavior"
+	CapabilityPeerMessageRecording = "peer-message-recording"
+	CapabilityStreamPeerMessages   = "stream-peer-messages"
+	CapabilitySendPeerMessage      = "send-peer-message"
+	CapabilitySendAppRequest       = "send-app-request"
+	CapabilityGetVersions          = "get-versions"
+	CapabilityOperationHistory     = "operation-history"
+	CapabilityPagination           = "pagination"
+	CapabilityResetNetwork         = "reset-network"
+	CapabilityStateSyncNode        = "state-sync-node"
+	CapabilityCChainState          = "c-chain-state"
+	CapabilityDBSizes              = "db-sizes"
+	CapabilityStreamProgress       = "stream-progress"
+	CapabilityTopology             = "topology"
+	CapabilityConnectivity         = "connectivity"
+	CapabilityPauseAll             = "pause-all"
+	CapabilityDirLayout            = "dir-layout"
+	CapabilitySearchLogs           = "search-logs"
+	CapabilityExportNetwork        = "export-network"
+	CapabilityWaitFor              = "wait-for"
+	CapabilityAssert               = "assert"
+	CapabilityChaos                = "chaos"
+	CapabilityKeyService           = "key-service"
+	CapabilityCreateAsset          = "create-asset"
+	CapabilityTransfer             = "transfer"
+	CapabilityStake                = "stake"
+	CapabilityGetElasticSubnet     = "get-elastic-subnet"
+	CapabilityStakingRewards       = "staking-rewards"
+	CapabilityRenewValidations     = "renew-validations"
+	CapabilityDrainNode            = "drain-node"
 )
 
 var (
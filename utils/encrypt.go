@@ -0,0 +1,208 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Streams are encrypted in chunks, each sealed with AES-256-GCM under a
+// nonce made of a random prefix, the chunk index and a flag set on the
+// last chunk, so chunks can't be reordered and a truncated stream is
+// detected.
+const (
+	encryptedStreamChunkSize   = 64 * 1024
+	encryptedStreamPrefixLen   = 7
+	encryptedStreamMaxChunks   = 1<<32 - 1
+	encryptedStreamLastChunk   = 1
+	encryptedStreamHeaderMagic = "NRENC1\n"
+)
+
+var (
+	ErrNotEncryptedStream = errors.New("not an encrypted stream")
+	ErrDecryptStream      = errors.New("wrong passphrase or corrupted encrypted stream")
+)
+
+type encryptWriter struct {
+	w           io.Writer
+	aead        cipher.AEAD
+	noncePrefix []byte
+	chunkIndex  uint32
+	buf         []byte
+	closed      bool
+}
+
+// NewEncryptWriter returns a writer encrypting the data written to it into
+// [w], with a key derived from [passphrase]. Close must be called to write
+// the last chunk; it doesn't close [w].
+func NewEncryptWriter(w io.Writer, passphrase string) (io.WriteCloser, error) {
+	salt := make([]byte, keystoreSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	noncePrefix := make([]byte, encryptedStreamPrefixLen)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return nil, err
+	}
+	aead, err := newKeystoreAEAD(passphrase, salt, keystoreIterations)
+	if err != nil {
+		return nil, err
+	}
+	header := bytes.NewBufferString(encryptedStreamHeaderMagic)
+	if err := binary.Write(header, binary.BigEndian, uint32(keystoreIterations)); err != nil {
+		return nil, err
+	}
+	header.Write(salt)
+	header.Write(noncePrefix)
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{
+		w:           w,
+		aead:        aead,
+		noncePrefix: noncePrefix,
+		buf:         make([]byte, 0, encryptedStreamChunkSize),
+	}, nil
+}
+
+func (ew *encryptWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, errors.New("write to closed encrypt writer")
+	}
+	n := 0
+	for len(p) > 0 {
+		// a full chunk is only sealed when more data comes, as the last
+		// chunk must be sealed as such
+		if len(ew.buf) == encryptedStreamChunkSize {
+			if err := ew.sealChunk(false); err != nil {
+				return n, err
+			}
+		}
+		copied := copy(ew.buf[len(ew.buf):encryptedStreamChunkSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+copied]
+		p = p[copied:]
+		n += copied
+	}
+	return n, nil
+}
+
+func (ew *encryptWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+	// a full chunk is never the last one, so the reader can tell it apart
+	if len(ew.buf) == encryptedStreamChunkSize {
+		if err := ew.sealChunk(false); err != nil {
+			return err
+		}
+	}
+	return ew.sealChunk(true)
+}
+
+func (ew *encryptWriter) sealChunk(last bool) error {
+	if ew.chunkIndex == encryptedStreamMaxChunks {
+		return errors.New("encrypted stream too long")
+	}
+	nonce := encryptedStreamNonce(ew.noncePrefix, ew.chunkIndex, last)
+	if _, err := ew.w.Write(ew.aead.Seal(nil, nonce, ew.buf, nil)); err != nil {
+		return err
+	}
+	ew.chunkIndex++
+	ew.buf = ew.buf[:0]
+	return nil
+}
+
+type decryptReader struct {
+	r           io.Reader
+	aead        cipher.AEAD
+	noncePrefix []byte
+	chunkIndex  uint32
+	sealed      []byte
+	plain       []byte
+	done        bool
+}
+
+// NewDecryptReader returns a reader of the data of [r], written by a
+// NewEncryptWriter with [passphrase]
+func NewDecryptReader(r io.Reader, passphrase string) (io.Reader, error) {
+	header := make([]byte, len(encryptedStreamHeaderMagic)+4+keystoreSaltLen+encryptedStreamPrefixLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotEncryptedStream, err)
+	}
+	if string(header[:len(encryptedStreamHeaderMagic)]) != encryptedStreamHeaderMagic {
+		return nil, ErrNotEncryptedStream
+	}
+	header = header[len(encryptedStreamHeaderMagic):]
+	iterations := binary.BigEndian.Uint32(header)
+	if iterations == 0 || iterations > keystoreMaxIterations {
+		return nil, fmt.Errorf("%w: invalid iterations", ErrNotEncryptedStream)
+	}
+	salt := header[4 : 4+keystoreSaltLen]
+	noncePrefix := header[4+keystoreSaltLen:]
+	aead, err := newKeystoreAEAD(passphrase, salt, int(iterations))
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{
+		r:           r,
+		aead:        aead,
+		noncePrefix: noncePrefix,
+		sealed:      make([]byte, encryptedStreamChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	for len(dr.plain) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.openChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.plain)
+	dr.plain = dr.plain[n:]
+	return n, nil
+}
+
+func (dr *decryptReader) openChunk() error {
+	n, err := io.ReadFull(dr.r, dr.sealed)
+	// only the last chunk is shorter than the full size
+	last := false
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF):
+		last = true
+	case err != nil:
+		return err
+	}
+	if n < dr.aead.Overhead() {
+		return fmt.Errorf("%w: truncated", ErrDecryptStream)
+	}
+	nonce := encryptedStreamNonce(dr.noncePrefix, dr.chunkIndex, last)
+	plain, err := dr.aead.Open(nil, nonce, dr.sealed[:n], nil)
+	if err != nil {
+		return ErrDecryptStream
+	}
+	dr.chunkIndex++
+	dr.plain = plain
+	dr.done = last
+	return nil
+}
+
+func encryptedStreamNonce(prefix []byte, chunkIndex uint32, last bool) []byte {
+	nonce := make([]byte, 0, encryptedStreamPrefixLen+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, chunkIndex)
+	if last {
+		return append(nonce, encryptedStreamLastChunk)
+	}
+	return append(nonce, 0)
+}
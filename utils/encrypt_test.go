@@ -0,0 +1,67 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptStream(t *testing.T) {
+	for _, size := range []int{0, 1, encryptedStreamChunkSize, 2*encryptedStreamChunkSize + 1} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		require.NoError(t, err)
+
+		encrypted := &bytes.Buffer{}
+		w, err := NewEncryptWriter(encrypted, "passphrase")
+		require.NoError(t, err)
+		_, err = w.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		r, err := NewDecryptReader(bytes.NewReader(encrypted.Bytes()), "passphrase")
+		require.NoError(t, err)
+		decrypted, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, data, decrypted)
+
+		r, err = NewDecryptReader(bytes.NewReader(encrypted.Bytes()), "other passphrase")
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		require.ErrorIs(t, err, ErrDecryptStream)
+
+		// a stream truncated at a chunk boundary is detected
+		if size > encryptedStreamChunkSize {
+			truncated := encrypted.Bytes()[:encrypted.Len()-encryptedStreamChunkSize]
+			r, err = NewDecryptReader(bytes.NewReader(truncated), "passphrase")
+			require.NoError(t, err)
+			_, err = io.ReadAll(r)
+			require.ErrorIs(t, err, ErrDecryptStream)
+		}
+	}
+
+	_, err := NewDecryptReader(bytes.NewReader([]byte("plaintext, not encrypted")), "passphrase")
+	require.ErrorIs(t, err, ErrNotEncryptedStream)
+}
+
+func TestDecryptStreamIterations(t *testing.T) {
+	encrypted := &bytes.Buffer{}
+	w, err := NewEncryptWriter(encrypted, "passphrase")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	for _, iterations := range []uint32{0, keystoreMaxIterations + 1, math.MaxUint32} {
+		stream := append([]byte{}, encrypted.Bytes()...)
+		binary.BigEndian.PutUint32(stream[len(encryptedStreamHeaderMagic):], iterations)
+		_, err := NewDecryptReader(bytes.NewReader(stream), "passphrase")
+		require.ErrorIs(t, err, ErrNotEncryptedStream)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	keystoreVersion    = 1
+	keystoreSaltLen    = 16
+	keystoreKeyLen     = 32
+	keystoreIterations = 600_000
+	// bounds the key derivation time of a crafted keystore or stream
+	keystoreMaxIterations = 10 * keystoreIterations
+)
+
+var ErrKeystorePassphrase = errors.New("wrong keystore passphrase or corrupted keystore")
+
+// Encrypted contents, as written to disk
+type keystore struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptKeystore returns a JSON keystore holding [plaintext] encrypted with
+// AES-256-GCM, using a key derived from [passphrase] with PBKDF2-SHA256
+func EncryptKeystore(plaintext []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, keystoreSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newKeystoreAEAD(passphrase, salt, keystoreIterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.Marshal(keystore{
+		Version:    keystoreVersion,
+		Iterations: keystoreIterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, nil),
+	})
+}
+
+// DecryptKeystore returns the plaintext of a keystore created by
+// EncryptKeystore with [passphrase]
+func DecryptKeystore(keystoreBytes []byte, passphrase string) ([]byte, error) {
+	var ks keystore
+	if err := json.Unmarshal(keystoreBytes, &ks); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal keystore: %w", err)
+	}
+	if ks.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", ks.Version)
+	}
+	if ks.Iterations <= 0 || ks.Iterations > keystoreMaxIterations {
+		return nil, fmt.Errorf("invalid keystore iterations %d", ks.Iterations)
+	}
+	aead, err := newKeystoreAEAD(passphrase, ks.Salt, ks.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(ks.Nonce) != aead.NonceSize() {
+		return nil, ErrKeystorePassphrase
+	}
+	plaintext, err := aead.Open(nil, ks.Nonce, ks.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrKeystorePassphrase
+	}
+	return plaintext, nil
+}
+
+func newKeystoreAEAD(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2SHA256([]byte(passphrase), salt, iterations, keystoreKeyLen))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// PBKDF2 (RFC 8018) with HMAC-SHA256 as PRF
+func pbkdf2SHA256(password []byte, salt []byte, iterations int, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	key := make([]byte, 0, keyLen)
+	blockIndex := make([]byte, 4)
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(blockIndex, block)
+		prf.Write(blockIndex)
+		u := prf.Sum(nil)
+		t := make([]byte, len(u))
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
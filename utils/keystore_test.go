@@ -0,0 +1,32 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// test vector of RFC 7914, section 11
+	expected, err := hex.DecodeString("55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783")
+	require.NoError(t, err)
+	require.Equal(t, expected, pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, len(expected)))
+}
+
+func TestKeystore(t *testing.T) {
+	plaintext := []byte("staking keys")
+	keystoreBytes, err := EncryptKeystore(plaintext, "passphrase")
+	require.NoError(t, err)
+	require.NotContains(t, string(keystoreBytes), string(plaintext))
+
+	decrypted, err := DecryptKeystore(keystoreBytes, "passphrase")
+	require.NoError(t, err)
+	require.Equal(t, plaintext, decrypted)
+
+	_, err = DecryptKeystore(keystoreBytes, "other passphrase")
+	require.ErrorIs(t, err, ErrKeystorePassphrase)
+}
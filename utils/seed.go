@@ -0,0 +1,180 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/luxdefi/node/utils/crypto/bls"
+)
+
+const (
+	// same key size as the staking keys generated by the node
+	seededStakingKeyBits = 4096
+	seededRSAExponent    = 65537
+)
+
+var (
+	// fixed validity of the seeded staking certs, as the cert bytes, and so
+	// the node ID, must not depend on the time they are generated at
+	seededCertNotBefore = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	seededCertNotAfter  = time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	errSeededKeyGeneration = errors.New("couldn't generate seeded key")
+)
+
+// Deterministic stream of bytes derived from a seed, hashing the seed
+// with an increasing counter.
+type seededReader struct {
+	key     [sha256.Size]byte
+	counter uint64
+	buf     []byte
+}
+
+// NewSeededReader returns a reader of the bytes derived from [seed] and
+// [label]. The same seed and label always give the same bytes, and each
+// label gives an independent stream, e.g. one per node name.
+func NewSeededReader(seed int64, label string) io.Reader {
+	seedBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(seedBytes, uint64(seed))
+	return &seededReader{
+		key: sha256.Sum256(append(seedBytes, label...)),
+	}
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			block := make([]byte, len(r.key)+8)
+			copy(block, r.key[:])
+			binary.BigEndian.PutUint64(block[len(r.key):], r.counter)
+			r.counter++
+			sum := sha256.Sum256(block)
+			r.buf = sum[:]
+		}
+		copied := copy(p[n:], r.buf)
+		r.buf = r.buf[copied:]
+		n += copied
+	}
+	return n, nil
+}
+
+// NewSeededCertAndKeyBytes returns a PEM encoded staking cert and key
+// derived from [seed] and [label], so the node using them gets the same
+// node ID on every run. The key is an RSA key of the size generated by
+// the node, with its primes found by hand, as the standard library
+// doesn't generate keys deterministically.
+func NewSeededCertAndKeyBytes(seed int64, label string) ([]byte, []byte, error) {
+	r := NewSeededReader(seed, "staking-key/"+label)
+	key, err := newSeededRSAKey(r, seededStakingKeyBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	certTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(0),
+		NotBefore:             seededCertNotBefore,
+		NotAfter:              seededCertNotAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageDataEncipherment,
+		BasicConstraintsValid: true,
+	}
+	// PKCS #1 v1.5 signatures are deterministic, so the cert bytes are too
+	certBytes, err := x509.CreateCertificate(r, certTemplate, certTemplate, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	var certBuff bytes.Buffer
+	if err := pem.Encode(&certBuff, &pem.Block{Type: "CERTIFICATE", Bytes: certBytes}); err != nil {
+		return nil, nil, err
+	}
+	privBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	var keyBuff bytes.Buffer
+	if err := pem.Encode(&keyBuff, &pem.Block{Type: "PRIVATE KEY", Bytes: privBytes}); err != nil {
+		return nil, nil, err
+	}
+	return certBuff.Bytes(), keyBuff.Bytes(), nil
+}
+
+// NewSeededSigningKey returns a BLS signing key derived from [seed] and
+// [label], encoded as expected by the node.
+func NewSeededSigningKey(seed int64, label string) ([]byte, error) {
+	r := NewSeededReader(seed, "signing-key/"+label)
+	skBytes := make([]byte, bls.SecretKeyLen)
+	// bytes out of the range of the curve scalars are skipped
+	for i := 0; i < 256; i++ {
+		if _, err := io.ReadFull(r, skBytes); err != nil {
+			return nil, err
+		}
+		if sk, err := bls.SecretKeyFromBytes(skBytes); err == nil {
+			return bls.SecretKeyToBytes(sk), nil
+		}
+	}
+	return nil, errSeededKeyGeneration
+}
+
+// Returns an RSA key of [bits] size, with primes read from [r].
+func newSeededRSAKey(r io.Reader, bits int) (*rsa.PrivateKey, error) {
+	e := big.NewInt(seededRSAExponent)
+	one := big.NewInt(1)
+	for {
+		p, err := newSeededPrime(r, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := newSeededPrime(r, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+		totient := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+		d := new(big.Int).ModInverse(e, totient)
+		if d == nil {
+			continue
+		}
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{
+				N: new(big.Int).Mul(p, q),
+				E: seededRSAExponent,
+			},
+			D:      d,
+			Primes: []*big.Int{p, q},
+		}
+		key.Precompute()
+		if err := key.Validate(); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+}
+
+// Returns a prime of [bits] size read from [r]. The two top bits are set,
+// so the product of two of them has twice the size.
+func newSeededPrime(r io.Reader, bits int) (*big.Int, error) {
+	b := make([]byte, bits/8)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		b[0] |= 0xc0
+		b[len(b)-1] |= 1
+		p := new(big.Int).SetBytes(b)
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2021-2024, Lux Partners Limited. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeededReader(t *testing.T) {
+	read := func(seed int64, label string) []byte {
+		b := make([]byte, 100)
+		_, err := io.ReadFull(NewSeededReader(seed, label), b)
+		require.NoError(t, err)
+		return b
+	}
+	require.Equal(t, read(1, "node1"), read(1, "node1"))
+	require.NotEqual(t, read(1, "node1"), read(2, "node1"))
+	require.NotEqual(t, read(1, "node1"), read(1, "node2"))
+}
+
+func TestSeededSigningKey(t *testing.T) {
+	key1, err := NewSeededSigningKey(1, "node1")
+	require.NoError(t, err)
+	key2, err := NewSeededSigningKey(1, "node1")
+	require.NoError(t, err)
+	require.Equal(t, key1, key2)
+	key3, err := NewSeededSigningKey(1, "node2")
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key3)
+}
+
+func TestSeededCertAndKeyBytes(t *testing.T) {
+	cert1, key1, err := NewSeededCertAndKeyBytes(1, "node1")
+	require.NoError(t, err)
+	cert2, key2, err := NewSeededCertAndKeyBytes(1, "node1")
+	require.NoError(t, err)
+	require.Equal(t, cert1, cert2)
+	require.Equal(t, key1, key2)
+}
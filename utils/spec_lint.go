@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	rpcb "github.com/luxdefi/netrunner/rpcpb"
+)
+
+var ErrInvalidBlockchainSpec = errors.New("invalid blockchain spec")
+
+// VMs whose genesis is JSON, so it is checked by LintBlockchainSpecs
+var jsonGenesisVMs = map[string]struct{}{
+	"subnetevm": {},
+	"evm":       {},
+}
+
+// LintBlockchainSpecs returns the common mistakes found in [specs], that
+// would otherwise fail after some wallet txs are issued: VM names longer than
+// 32 bytes, genesis that is not valid JSON for the known EVM based VMs,
+// repeated participants, and per node chain configs for nodes that are not
+// participants.
+// If [nodeNames] is not nil, participants and per node chain configs
+// referencing nodes not in it are also reported. Leave it nil when the
+// participants missing from the network are meant to be added to it.
+func LintBlockchainSpecs(specs []*rpcb.BlockchainSpec, nodeNames []string) []error {
+	var knownNodes map[string]struct{}
+	if nodeNames != nil {
+		knownNodes = map[string]struct{}{}
+		for _, nodeName := range nodeNames {
+			knownNodes[nodeName] = struct{}{}
+		}
+	}
+
+	problems := []error{}
+	for i, spec := range specs {
+		addProblem := func(format string, args ...interface{}) {
+			problems = append(problems, fmt.Errorf("%w %d (vm %q): %s", ErrInvalidBlockchainSpec, i, spec.GetVmName(), fmt.Sprintf(format, args...)))
+		}
+
+		vmName := spec.GetVmName()
+		switch {
+		case vmName == "":
+			addProblem("VM name is missing")
+		case len(vmName) > 32:
+			addProblem("VM name must be <= 32 bytes, found %d", len(vmName))
+		}
+
+		if spec.GetGenesis() == "" {
+			addProblem("genesis is missing")
+		} else if _, ok := jsonGenesisVMs[vmName]; ok {
+			genesis, err := os.ReadFile(spec.GetGenesis())
+			if err != nil {
+				// not a file path, but the genesis contents
+				genesis = []byte(spec.GetGenesis())
+			}
+			if !json.Valid(genesis) {
+				addProblem("genesis is not valid JSON")
+			}
+		}
+
+		participants := map[string]struct{}{}
+		for _, participant := range spec.GetSubnetSpec().GetParticipants() {
+			if _, ok := participants[participant]; ok {
+				addProblem("participant %q is repeated", participant)
+			}
+			participants[participant] = struct{}{}
+			if _, ok := knownNodes[participant]; knownNodes != nil && !ok {
+				addProblem("participant %q is not a node of the network", participant)
+			}
+		}
+
+		if spec.GetPerNodeChainConfig() == "" {
+			continue
+		}
+		perNodeChainConfig, err := os.ReadFile(spec.GetPerNodeChainConfig())
+		if err != nil {
+			perNodeChainConfig = []byte(spec.GetPerNodeChainConfig())
+		}
+		perNodeChainConfigMap := map[string]interface{}{}
+		if err := json.Unmarshal(perNodeChainConfig, &perNodeChainConfigMap); err != nil {
+			addProblem("per node chain config is not a JSON map of node names to configs: %s", err)
+			continue
+		}
+		for nodeName := range perNodeChainConfigMap {
+			// no participants means all the nodes participate
+			if _, ok := participants[nodeName]; len(participants) > 0 && !ok {
+				addProblem("per node chain config given for %q, which is not a participant", nodeName)
+				continue
+			}
+			if _, ok := knownNodes[nodeName]; len(participants) == 0 && knownNodes != nil && !ok {
+				addProblem("per node chain config given for %q, which is not a node of the network", nodeName)
+			}
+		}
+	}
+	return problems
+}
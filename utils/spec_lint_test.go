@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	rpcb "github.com/luxdefi/netrunner/rpcpb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintBlockchainSpecs(t *testing.T) {
+	genesisPath := filepath.Join(t.TempDir(), "genesis.json")
+	require.NoError(t, os.WriteFile(genesisPath, []byte(`{"config":{}}`), 0o600))
+
+	tests := []struct {
+		name             string
+		specs            []*rpcb.BlockchainSpec
+		nodeNames        []string
+		expectedProblems []string
+	}{
+		{
+			name: "valid specs",
+			specs: []*rpcb.BlockchainSpec{
+				{VmName: "subnetevm", Genesis: genesisPath},
+				{VmName: "timestampvm", Genesis: "not json"},
+				{
+					VmName:             "subnetevm",
+					Genesis:            `{"config":{}}`,
+					SubnetSpec:         &rpcb.SubnetSpec{Participants: []string{"node1", "node2"}},
+					PerNodeChainConfig: `{"node1":{}}`,
+				},
+			},
+			nodeNames: []string{"node1", "node2"},
+		},
+		{
+			name: "long VM name and invalid genesis",
+			specs: []*rpcb.BlockchainSpec{
+				{VmName: strings.Repeat("a", 33), Genesis: "{}"},
+				{VmName: "subnetevm", Genesis: "{"},
+				{VmName: "evm"},
+			},
+			expectedProblems: []string{
+				"VM name must be <= 32 bytes, found 33",
+				"genesis is not valid JSON",
+				"genesis is missing",
+			},
+		},
+		{
+			name: "participants",
+			specs: []*rpcb.BlockchainSpec{
+				{
+					VmName:     "subnetevm",
+					Genesis:    "{}",
+					SubnetSpec: &rpcb.SubnetSpec{Participants: []string{"node1", "node1", "node7"}},
+				},
+			},
+			nodeNames: []string{"node1", "node2"},
+			expectedProblems: []string{
+				`participant "node1" is repeated`,
+				`participant "node7" is not a node of the network`,
+			},
+		},
+		{
+			name: "unknown participants allowed without node names",
+			specs: []*rpcb.BlockchainSpec{
+				{
+					VmName:     "subnetevm",
+					Genesis:    "{}",
+					SubnetSpec: &rpcb.SubnetSpec{Participants: []string{"new_node1"}},
+				},
+			},
+		},
+		{
+			name: "per node chain configs",
+			specs: []*rpcb.BlockchainSpec{
+				{
+					VmName:             "subnetevm",
+					Genesis:            "{}",
+					SubnetSpec:         &rpcb.SubnetSpec{Participants: []string{"node1"}},
+					PerNodeChainConfig: `{"node1":{},"node2":{}}`,
+				},
+				{
+					VmName:             "subnetevm",
+					Genesis:            "{}",
+					PerNodeChainConfig: `{"node9":{}}`,
+				},
+				{
+					VmName:             "subnetevm",
+					Genesis:            "{}",
+					PerNodeChainConfig: `["node1"]`,
+				},
+			},
+			nodeNames: []string{"node1", "node2"},
+			expectedProblems: []string{
+				`per node chain config given for "node2", which is not a participant`,
+				`per node chain config given for "node9", which is not a node of the network`,
+				"per node chain config is not a JSON map of node names to configs",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+			problems := LintBlockchainSpecs(tt.specs, tt.nodeNames)
+			require.Len(problems, len(tt.expectedProblems))
+			for i, problem := range problems {
+				require.ErrorIs(problem, ErrInvalidBlockchainSpec)
+				require.Contains(problem.Error(), tt.expectedProblems[i])
+			}
+		})
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net"
 	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 
 	rpcb "github.com/luxdefi/netrunner/rpcpb"
@@ -87,6 +90,40 @@ func VMID(vmName string) (ids.ID, error) {
 	return ids.ToID(b)
 }
 
+// Returns the HTTP URI of a node API at [host]:[port]. IPv6 hosts are
+// enclosed in brackets.
+func HTTPURI(host string, port uint16) string {
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
+// DirSize returns the size in bytes of the files under [path], zero if it
+// doesn't exist
+func DirSize(path string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			// removed while walking, e.g. a compacted db file
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	return size, err
+}
+
 func MkDirWithTimestamp(dirPrefix string) (string, error) {
 	currentTime := time.Now().Format(dirTimestampFormat)
 	dirName := dirPrefix + "_" + currentTime
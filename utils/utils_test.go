@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -123,3 +124,25 @@ func TestCheckPluginPaths(t *testing.T) {
 		require.Equal(t, tv.expectedErr, err, fmt.Sprintf("[%d] unexpected error", i))
 	}
 }
+
+func TestDirSize(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	require.NoError(os.WriteFile(filepath.Join(dir, "a"), make([]byte, 10), 0o600))
+	require.NoError(os.MkdirAll(filepath.Join(dir, "sub"), 0o700))
+	require.NoError(os.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 5), 0o600))
+	size, err := DirSize(dir)
+	require.NoError(err)
+	require.Equal(uint64(15), size)
+
+	size, err = DirSize(filepath.Join(dir, "missing"))
+	require.NoError(err)
+	require.Zero(size)
+}
+
+func TestHTTPURI(t *testing.T) {
+	require.Equal(t, "http://127.0.0.1:9650", HTTPURI("127.0.0.1", 9650))
+	require.Equal(t, "http://[::1]:9650", HTTPURI("::1", 9650))
+	require.Equal(t, "http://localhost:9650", HTTPURI("localhost", 9650))
+}